@@ -25,16 +25,22 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"syscall"
 	"time"
 
 	configclient "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
 
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
+	"github.com/F5Networks/k8s-bigip-ctlr/v2/config/client/clientset/versioned"
 	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/teem"
 
 	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/controller"
+	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/diagnostics"
 	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/health"
+	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/httpclient"
 	bigIPPrometheus "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/prometheus"
 	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/writer"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -131,6 +137,8 @@ var (
 	syncInterval     *int
 	printVersion     *bool
 	httpAddress      *string
+	enableProfiling  *bool
+	profilingPort    *int
 	dgPath           string
 	disableTeems     *bool
 	enableIPV6       *bool
@@ -146,6 +154,8 @@ var (
 	manageIngress          *bool
 	hubMode                *bool
 	nodeLabelSelector      *string
+	poolMemberNodeOS       *[]string
+	vxlanExcludedNodeOS    *[]string
 	resolveIngNames        *string
 	defaultIngIP           *string
 	vsSnatPoolName         *string
@@ -153,13 +163,43 @@ var (
 	schemaLocal            *string
 	manageIngressClassOnly *bool
 	ingressClass           *string
+	enableCRDIngress       *bool
+	enableACMESolver       *bool
+	enforceSvcRefGrants    *bool
+	enableFinalizers       *bool
+	enableQuarantine       *bool
+	secondaryNetworks      *bool
+	dualStackMembers       *bool
+	poolMemberRatio        *bool
+	shardCount             *int
+	shardIndex             *int
+	resourceClass          *string
+	defaultPolicy          *string
+	vsNameTemplate         *string
+	poolNameTemplate       *string
+	tlsFileDirectory       *string
+	bigipReferences        *[]string
 
 	bigIPURL                  *string
 	bigIPUsername             *string
 	bigIPPassword             *string
+	bigIQURL                  *string
+	bigIQUsername             *string
+	bigIQPassword             *string
+	bigIQLoginProvider        *string
+	xcAPIURL                  *string
+	xcAPIToken                *string
+	xcNamespace               *string
+	xcSite                    *string
+	nginxPlusAPIURL           *string
+	nginxPlusAPIVersion       *int
 	bigIPPartitions           *[]string
 	credsDir                  *string
 	as3Validation             *bool
+	as3DeclValidation         *bool
+	as3SchemaVersion          *string
+	as3OptimisticLock         *bool
+	shareIdenticalPools       *bool
 	sslInsecure               *bool
 	ipam                      *bool
 	enableTLS                 *string
@@ -167,6 +207,26 @@ var (
 	ciphers                   *string
 	trustedCerts              *string
 	as3PostDelay              *int
+	as3MaxRetries             *int
+	as3RetryInterval          *int
+	as3RetryMaxInterval       *int
+	as3RetryJitter            *float64
+	circuitBreakerThreshold   *int
+	circuitBreakerCooldown    *int
+	as3MaxDeclarationSize     *int
+	as3SplitTenants           *bool
+	restjavadExtraMB          *int
+	bigipResponseCacheTTL     *int
+	capacityInterval          *int
+	virtualServerLimit        *int
+	capacityThreshold         *int
+	poolMemberStatsInterval   *int
+	nodeEventBatchInterval    *int
+	unschedulableNodeMembers  *string
+	auditLogFile              *string
+	auditLogMaxSize           *int
+	auditEventsNamespace      *string
+	bigipObjectCheckInterval  *int
 
 	trustedCertsCfgmap     *string
 	agent                  *string
@@ -191,15 +251,24 @@ var (
 
 	extendedSpecConfigmap *string
 	routeSpecConfigmap    *string
+	deployConfigCR        *string
 
 	gtmBigIPURL      *string
 	gtmBigIPUsername *string
 	gtmBigIPPassword *string
 	gtmCredsDir      *string
 
+	autoGenerateWideIP *bool
+
+	bigIPRequestTimeout *int
+	bigIPConnectTimeout *int
+	bigIPMaxIdleConns   *int
+	bigIPProxyFromEnv   *bool
+
 	httpClientMetrics  *bool
 	staticRoutingMode  *bool
 	orchestrationCNI   *string
+	ciliumMode         *string
 	sharedStaticRoutes *bool
 	// package variables
 	isNodePort         bool
@@ -257,10 +326,18 @@ func _init() {
 		"Optional, print version and exit.")
 	httpAddress = globalFlags.String("http-listen-address", "0.0.0.0:8080",
 		"Optional, address to serve http based informations (/metrics and /health).")
+	enableProfiling = globalFlags.Bool("enable-profiling", false,
+		"Optional, when set to true, CIS serves the pprof profiles (/debug/pprof/), the runtime statistics "+
+			"(/debug/vars) and the log level (/loglevel) on --profiling-port of localhost.")
+	profilingPort = globalFlags.Int("profiling-port", diagnostics.DefaultPort,
+		"Optional, port of localhost to serve the pprof profiles, the runtime statistics and the log level with "+
+			"--enable-profiling.")
 	disableTeems = globalFlags.Bool("disable-teems", false,
 		"Optional, flag to disable sending telemetry data to TEEM")
 	staticRoutingMode = globalFlags.Bool("static-routing-mode", false, "Optional, flag to enable configuration of static routes on bigip for pod network subnets")
 	orchestrationCNI = globalFlags.String("orchestration-cni", "", "Optional, flag to specify orchestration CNI configured")
+	ciliumMode = globalFlags.String("cilium-mode", "", "Optional, routing mode of Cilium with orchestration-cni cilium-k8s, "+
+		"native mode disables the static routes and ARP, the routes of BIG-IP to the pods are configured outside CIS")
 	sharedStaticRoutes = globalFlags.Bool("shared-static-routes", false, "Optional, flag to enable configuration of static routes on bigip in common partition")
 	// Custom Resource
 	enableIPV6 = globalFlags.Bool("enable-ipv6", false,
@@ -276,6 +353,10 @@ func _init() {
 			" if controller-mode is 'openshift'")
 	extendedSpecConfigmap = globalFlags.String("extended-spec-configmap", "",
 		"Required, specify a configmap that holds additional spec for controller. It's a required parameter if controller-mode is 'openshift'")
+	deployConfigCR = globalFlags.String("deploy-config-cr", "",
+		"Optional, name of the cluster scoped DeployConfig custom resource holding the controller configuration. "+
+			"Values set in the DeployConfig override the arguments, changes to log level and AS3 configuration "+
+			"are applied without restarting the controller in CRD mode.")
 
 	globalFlags.Usage = func() {
 		fmt.Fprintf(os.Stderr, "  Global:\n%s\n", globalFlags.FlagUsagesWrapped(width))
@@ -288,6 +369,29 @@ func _init() {
 		"Required, user name for the Big-IP user account.")
 	bigIPPassword = bigIPFlags.String("bigip-password", "",
 		"Required, password for the Big-IP user account.")
+	bigIQURL = bigIPFlags.String("bigiq-url", "",
+		"Optional, URL for the BIG-IQ to post the AS3 declarations through BIG-IQ to the Big-IP provided with "+
+			"bigip-url. Supported only in CRD mode.")
+	bigIQUsername = bigIPFlags.String("bigiq-username", "",
+		"Optional, user name for the BIG-IQ user account, required with bigiq-url.")
+	bigIQPassword = bigIPFlags.String("bigiq-password", "",
+		"Optional, password for the BIG-IQ user account, required with bigiq-url.")
+	bigIQLoginProvider = bigIPFlags.String("bigiq-login-provider", "local",
+		"Optional, login provider of the BIG-IQ user account.")
+	xcAPIURL = bigIPFlags.String("xc-api-url", "",
+		"Optional, experimental, URL of the F5 Distributed Cloud tenant to configure the VirtualServer and "+
+			"TransportServer as HTTP and TCP load balancers instead of Big-IP. Supported only in CRD mode.")
+	xcAPIToken = bigIPFlags.String("xc-api-token", "",
+		"Optional, API token of the F5 Distributed Cloud tenant, required with xc-api-url.")
+	xcNamespace = bigIPFlags.String("xc-namespace", "",
+		"Optional, F5 Distributed Cloud namespace of the load balancers, required with xc-api-url.")
+	xcSite = bigIPFlags.String("xc-site", "",
+		"Optional, F5 Distributed Cloud site to reach the pool members, pool members are public IPs when not set.")
+	nginxPlusAPIURL = bigIPFlags.String("nginx-plus-api-url", "",
+		"Optional, URL of the NGINX Plus API to update the servers of the NGINX Plus upstreams named after the "+
+			"VirtualServer and TransportServer pools instead of Big-IP. Supported only in CRD mode.")
+	nginxPlusAPIVersion = bigIPFlags.Int("nginx-plus-api-version", controller.DefaultNginxPlusAPIVersion,
+		"Optional, version of the NGINX Plus API.")
 	bigIPPartitions = bigIPFlags.StringArray("bigip-partition", []string{},
 		"Required, partition(s) for the Big-IP kubernetes objects.")
 	credsDir = bigIPFlags.String("credentials-directory", "",
@@ -295,12 +399,89 @@ func _init() {
 			"url files. To be used instead of username, password, and/or url arguments.")
 	as3Validation = bigIPFlags.Bool("as3-validation", true,
 		"Optional, when set to false, disables as3 template validation on the controller.")
+	as3DeclValidation = bigIPFlags.Bool("as3-declaration-validation", false,
+		"Optional, when set to true, validates the objects of the AS3 declarations with the AS3 schema of CIS "+
+			"before posting to BIG-IP, the tenants with the invalid objects are not posted. Supported only in CRD mode.")
+	as3SchemaVersion = bigIPFlags.String("as3-schema-version", "",
+		"Optional, pins the AS3 schemaVersion of the declarations posted to BIG-IP, "+
+			"defaults to the schema version of the AS3 on BIG-IP.")
+	as3OptimisticLock = bigIPFlags.Bool("as3-optimistic-lock", false,
+		"Optional, when set to true, posts the AS3 tenants with the optimistic lock keys to detect "+
+			"the tenants modified on BIG-IP by other clients. Supported only in CRD mode.")
+	shareIdenticalPools = bigIPFlags.Bool("share-identical-pools", false,
+		"Optional, when set to true, the pools of the same service with the same members, settings and monitors "+
+			"framed for the different virtuals and hosts of a partition are declared as a single pool shared by "+
+			"the virtuals. Supported only in CRD mode.")
 	sslInsecure = bigIPFlags.Bool("insecure", false,
 		"Optional, when set to true, enable insecure SSL communication to BIGIP.")
 	ipam = bigIPFlags.Bool("ipam", false,
 		"Optional, when set to true, enable ipam feature for CRD.")
 	as3PostDelay = bigIPFlags.Int("as3-post-delay", 0,
 		"Optional, time (in seconds) that CIS waits to post the available AS3 declaration.")
+	as3MaxRetries = bigIPFlags.Int("as3-max-retries", 0,
+		"Optional, maximum retries of the failed AS3 tenants until the next configuration update, "+
+			"failed tenants are retried without a limit when set to 0. Supported only in CRD mode.")
+	as3RetryInterval = bigIPFlags.Int("as3-retry-interval", 30,
+		"Optional, time (in seconds) that CIS waits to retry the failed AS3 tenants. Supported only in CRD mode.")
+	as3RetryMaxInterval = bigIPFlags.Int("as3-retry-max-interval", 30,
+		"Optional, maximum time (in seconds) that CIS waits to retry the failed AS3 tenants, retry interval is "+
+			"doubled with every retry up to the maximum interval. Supported only in CRD mode.")
+	as3RetryJitter = bigIPFlags.Float64("as3-retry-jitter", 0,
+		"Optional, fraction of the retry interval, between 0 and 1, randomly added to the retry interval of "+
+			"the failed AS3 tenants. Supported only in CRD mode.")
+	circuitBreakerThreshold = bigIPFlags.Int("circuit-breaker-threshold", 0,
+		"Optional, consecutive REST call errors after which CIS stops posting to the unreachable BIG-IP "+
+			"and reports degraded on /ready, circuit breaker is disabled when set to 0. Supported only in CRD mode.")
+	circuitBreakerCooldown = bigIPFlags.Int("circuit-breaker-cooldown", 30,
+		"Optional, time (in seconds) that CIS waits to probe the unreachable BIG-IP, posts are resumed with "+
+			"the full-state sync of the tenants once BIG-IP is reachable. Supported only in CRD mode.")
+	as3MaxDeclarationSize = bigIPFlags.Int("as3-max-declaration-size", 0,
+		"Optional, size limit (in MB) of the AS3 declarations posted to BIG-IP, should not exceed the request "+
+			"size limit of BIG-IP. Larger declarations are not posted and their tenants are reported as failed, "+
+			"size is not limited when set to 0. Supported only in CRD mode.")
+	as3SplitTenants = bigIPFlags.Bool("as3-split-tenants", false,
+		"Optional, when set to true, tenants of the AS3 declaration exceeding --as3-max-declaration-size are "+
+			"posted in separate declarations one after the other. Supported only in CRD mode.")
+	restjavadExtraMB = bigIPFlags.Int("restjavad-extramb", 0,
+		"Optional, memory (in MB) of restjavad set on BIG-IP with restjavad.useextramb and provision.extramb "+
+			"sys db variables when the REST framework of BIG-IP is not serving the REST calls, sys db variables "+
+			"are not changed when set to 0. Supported only in CRD mode.")
+	bigipResponseCacheTTL = bigIPFlags.Int("bigip-response-cache-ttl", 0,
+		"Optional, time (in seconds) that CIS caches the responses of BIG-IP for the existence of the referred "+
+			"LTM policies and iRules, AS3 info and license, so that the resources referring the same objects "+
+			"don't query BIG-IP on every reconcile, responses are not cached when set to 0. Supported only in CRD mode.")
+	capacityInterval = bigIPFlags.Int("bigip-capacity-interval", 0,
+		"Optional, interval (in seconds) at which CIS queries BIG-IP for the provisioned modules, virtual servers "+
+			"and the throughput license limit and exposes them as metrics, disabled when set to 0. Supported only in CRD mode.")
+	virtualServerLimit = bigIPFlags.Int("bigip-virtual-server-limit", 0,
+		"Optional, virtual servers supported by BIG-IP, CIS raises a warning event when the virtual servers on "+
+			"BIG-IP reach the --bigip-capacity-threshold percentage of the limit.")
+	capacityThreshold = bigIPFlags.Int("bigip-capacity-threshold", controller.DefaultCapacityThreshold,
+		"Optional, percentage of --bigip-virtual-server-limit at which CIS raises the warning event.")
+	poolMemberStatsInterval = bigIPFlags.Int("pool-member-stats-interval", 0,
+		"Optional, interval (in seconds) at which CIS queries BIG-IP for the statistics of the pool members "+
+			"managed by CIS and exposes them as metrics, disabled when set to 0. Supported only in CRD mode.")
+	nodeEventBatchInterval = bigIPFlags.Int("node-event-batch-interval", 5,
+		"Optional, interval (in seconds) in which the node events are batched to update the pool members once, "+
+			"node events are processed individually when set to 0. Supported only in CRD mode.")
+	unschedulableNodeMembers = bigIPFlags.String("unschedulable-node-members", controller.UnschedulableNodeDrain,
+		"Optional, handling of the pool members of the cordoned and not ready nodes in nodeport mode, "+
+			"'drain' disables the members to serve only the existing connections, 'remove' removes the members "+
+			"and 'retain' keeps the members enabled. Supported only in CRD mode.")
+	auditLogFile = bigIPFlags.String("audit-log-file", "",
+		"Optional, file in which CIS records every AS3 declaration posted to BIG-IP as a JSON line with the "+
+			"resources which triggered it, the objects changed and the response of BIG-IP. Supported only in CRD mode.")
+	auditLogMaxSize = bigIPFlags.Int("audit-log-max-size", 100,
+		"Optional, size (in MB) of --audit-log-file after which it is rotated, the last 5 rotated files are "+
+			"retained and the file is not rotated when set to 0.")
+	auditEventsNamespace = bigIPFlags.String("audit-events-namespace", "",
+		"Optional, namespace in which CIS records every AS3 declaration posted to BIG-IP as a kubernetes event "+
+			"with the audit record in its annotation. Supported only in CRD mode.")
+	bigipObjectCheckInterval = bigIPFlags.Int("bigip-object-check-interval", 0,
+		"Optional, interval (in seconds) at which CIS verifies again that the LTM policies and iRules referred by "+
+			"the VirtualServers exist on BIG-IP, objects are verified when the VirtualServers are processed and the "+
+			"VirtualServers referring the objects not found are not processed. Disabled when set to 0. "+
+			"Supported only in CRD mode.")
 	logAS3Response = bigIPFlags.Bool("log-as3-response", false,
 		"Optional, when set to true, add the body of AS3 API response in Controller logs.")
 	shareNodes = bigIPFlags.Bool("share-nodes", false,
@@ -318,12 +499,24 @@ func _init() {
 	ccclGtmAgent = bigIPFlags.Bool("cccl-gtm-agent", true,
 		"Optional, Option to configure GTM objects using CCCL or AS3 Agent. Default Agent is CCCL.")
 	overrideAS3UsageStr := "Optional, provide Namespace and Name of that ConfigMap as <namespace>/<configmap-name>." +
-		"The JSON key/values from this ConfigMap will override key/values from internally generated AS3 declaration."
+		"The JSON key/values from this ConfigMap will override key/values from internally generated AS3 declaration. " +
+		"Multiple ConfigMaps can be provided as comma separated list, applied in the order of " +
+		"virtual-server.f5.com/override-priority annotation."
 	overriderAS3CfgmapName = bigIPFlags.String("override-as3-declaration", "", overrideAS3UsageStr)
 	filterTenants = kubeFlags.Bool("filter-tenants", false,
 		"Optional, specify whether or not to use tenant filtering API for AS3 declaration")
 	httpClientMetrics = bigIPFlags.Bool("http-client-metrics", false,
 		"Optional, adds HTTP client metric instrumentation for the k8s-bigip-ctlr")
+	bigIPRequestTimeout = bigIPFlags.Int("bigip-request-timeout", 180,
+		"Optional, timeout (in seconds) of the REST calls to BIG-IP including the AS3 declarations, REST calls "+
+			"exceeding the timeout are cancelled and retried.")
+	bigIPConnectTimeout = bigIPFlags.Int("bigip-connect-timeout", 30,
+		"Optional, timeout (in seconds) of establishing the connection and the TLS handshake with BIG-IP.")
+	bigIPMaxIdleConns = bigIPFlags.Int("bigip-max-idle-connections", httpclient.DefaultMaxIdleConnsPerHost,
+		"Optional, idle connections to BIG-IP kept alive for the REST calls, keep-alive is disabled when set to 0.")
+	bigIPProxyFromEnv = bigIPFlags.Bool("bigip-proxy-from-env", false,
+		"Optional, when set to true, the REST calls to BIG-IP use the proxy of HTTPS_PROXY and HTTP_PROXY "+
+			"environment variables, except for the hosts in NO_PROXY environment variable.")
 
 	bigIPFlags.Usage = func() {
 		fmt.Fprintf(os.Stderr, "  BigIP:\n%s\n", bigIPFlags.FlagUsagesWrapped(width))
@@ -358,7 +551,12 @@ func _init() {
 	hubMode = kubeFlags.Bool("hubmode", false,
 		"Optional, specify whether or not to manage ConfigMap resources in hub-mode")
 	nodeLabelSelector = kubeFlags.String("node-label-selector", "",
-		"Optional, used to watch only for nodes with this label")
+		"Optional, used to watch only for nodes with this label, the nodes are the pool members in nodeport mode "+
+			"and the targets of the static routes")
+	poolMemberNodeOS = kubeFlags.StringArray("pool-member-node-os", []string{},
+		"Optional, operating system of the nodes, such as linux, used as the pool members in nodeport mode, "+
+			"can be specified multiple times. Nodes of all the operating systems are used when not set. "+
+			"Supported only in CRD mode.")
 	resolveIngNames = kubeFlags.String("resolve-ingress-names", "",
 		"Optional, direct the controller to resolve host names in Ingresses into IP addresses. "+
 			"The 'LOOKUP' option will use the controller's built-in DNS. "+
@@ -383,6 +581,86 @@ func _init() {
 			"Additionally, the Ingress controller processes Ingress resources that do not have that annotation,"+
 			"which can be disabled by setting the `-manage-ingress-class-only` flag")
 
+	enableCRDIngress = kubeFlags.Bool("enable-crd-ingress", false,
+		"Optional, default `false`. When set to true in custom resource mode, the controller processes "+
+			"Ingress (networking.k8s.io/v1) resources along with VirtualServer resources.")
+
+	enableACMESolver = kubeFlags.Bool("enable-acme-solver", false,
+		"Optional, default `false`. When set to true in custom resource mode, the controller forwards the ACME HTTP-01 "+
+			"challenges of the VirtualServer hosts on the HTTP virtual server to the cert-manager solver services.")
+
+	enforceSvcRefGrants = kubeFlags.Bool("enforce-service-reference-grants", false,
+		"Optional, default `false`. When set to true in custom resource mode, the VirtualServer pools refer the "+
+			"services of other namespaces only when a ServiceReferenceGrant in the service namespace allows it.")
+
+	enableFinalizers = kubeFlags.Bool("enable-resource-finalizers", false,
+		"Optional, default `false`. When set to true in custom resource mode, the controller adds the finalizer "+
+			"`cis.f5.com/finalizer` to the VirtualServer, TransportServer and ExternalDNS resources, their deletion "+
+			"completes once the BIG-IP configuration is removed and the IP address is released to IPAM.")
+
+	enableQuarantine = kubeFlags.Bool("enable-resource-quarantine", false,
+		"Optional, default `false`. When set to true in custom resource mode, the VirtualServer, TransportServer "+
+			"and Ingress resources failing the AS3 declaration are excluded from the declarations until they are "+
+			"updated, so that the other resources of the partition are still posted.")
+
+	secondaryNetworks = kubeFlags.Bool("enable-secondary-networks", false,
+		"Optional, default `false`. When set to true in custom resource mode with cluster pool member type, the "+
+			"pools with networkAttachment have the addresses of the pods on the secondary network of the network "+
+			"attachment in the Multus network status annotation of the pods as the pool members.")
+
+	dualStackMembers = kubeFlags.Bool("enable-dual-stack-pool-members", false,
+		"Optional, default `false`. When set to true in custom resource mode with cluster pool member type, the "+
+			"pools with addressFamily have the IPv4 or IPv6 addresses of the dual-stack pods as the pool members, "+
+			"instead of the addresses of the primary address family of the service in the endpoints.")
+
+	poolMemberRatio = kubeFlags.Bool("enable-pool-member-ratio", false,
+		"Optional, default `false`. When set to true in custom resource mode with cluster pool member type, the "+
+			"pools with memberRatio have the ratio of the pool members from the cis.f5.com/pool-member-ratio "+
+			"annotation of the pods or proportional to the CPU requests of the pods, and the pools with "+
+			"warmUpPeriod raise the ratio of the members of the newly ready pods gradually.")
+
+	shardCount = kubeFlags.Int("shard-count", 1,
+		"Optional, default `1`. Number of the CIS replicas sharing the namespaces by the hash of the namespace "+
+			"names, each replica manages the resources of its shard of the namespaces. Replicas must manage the "+
+			"disjoint BIG-IP partitions. Supported only in CRD mode.")
+	shardIndex = kubeFlags.Int("shard-index", -1,
+		"Optional, index of the shard of the namespaces managed by the CIS replica from 0 to shard-count minus 1. "+
+			"When not provided, the ordinal of the StatefulSet pod name of the replica is the index. "+
+			"Supported only in CRD mode.")
+
+	resourceClass = kubeFlags.String("resource-class", "",
+		"Optional, a class of the controller in custom resource and nextgen route mode. The controller only processes "+
+			"VirtualServer, TransportServer and Route resources with the annotation `cis.f5.com/resource-class` equal "+
+			"to the class and Ingress resources of the same ingress class. Resources without class are processed only "+
+			"when the class is not set, which allows multiple controllers to own disjoint sets of resources.")
+
+	defaultPolicy = kubeFlags.String("default-policy", "",
+		"Optional, Policy custom resource in the format <namespace>/<policy-name> merged into the Policy of all the "+
+			"virtual servers created for VirtualServer, TransportServer, Service of type LoadBalancer and Route "+
+			"resources, settings specified in the Policy of the resource take precedence over the default Policy.")
+
+	vsNameTemplate = kubeFlags.String("virtual-server-name-template", "",
+		"Optional, Go template of the names of the virtual servers generated in custom resource and nextgen route "+
+			"mode with the fields {{.Name}}, {{.Address}} and {{.Port}}, where {{.Name}} is the default name "+
+			"crd_<address>_<port>. Profiles and policies of the virtual server are named after it.")
+
+	poolNameTemplate = kubeFlags.String("pool-name-template", "",
+		"Optional, Go template of the names of the pools generated in custom resource and nextgen route mode "+
+			"with the fields {{.Name}}, {{.Namespace}}, {{.Service}}, {{.Port}}, {{.Host}} and {{.Cluster}}, where "+
+			"{{.Name}} is the default name. Names longer than the AS3 limit are truncated with a hash of the name.")
+
+	tlsFileDirectory = kubeFlags.String("tls-file-directory", "",
+		"Optional, absolute path of the directory in which the certificates and keys are mounted by the Secrets "+
+			"Store CSI driver. TLSProfiles with reference file refer the directories in it holding tls.crt and "+
+			"tls.key, which are processed again when the files are rotated. Supported only in CRD mode.")
+
+	bigipReferences = kubeFlags.StringArray("bigip-reference", []string{},
+		"Optional, BIG-IP objects which the resources of a namespace are allowed to refer, in the format "+
+			"<namespace>:<path prefix> such as team-a:/Common/team-a-, namespace * allows the path prefix for all "+
+			"the namespaces. When specified, VirtualServers, TransportServers and IngressLinks referring other "+
+			"BIG-IP profiles, iRules, policies, monitors or VLANs are not processed. Can be specified multiple times. "+
+			"Supported only in CRD mode.")
+
 	// If the flag is specified with no argument, default to LOOKUP
 	kubeFlags.Lookup("resolve-ingress-names").NoOptDefVal = "LOOKUP"
 
@@ -406,6 +684,10 @@ func _init() {
 	flannelName = vxlanFlags.String("flannel-name", "",
 		"Must be provided for BigIP Flannel integration, "+
 			"full path of BigIP Flannel VxLAN Tunnel")
+	vxlanExcludedNodeOS = vxlanFlags.StringArray("vxlan-excluded-node-os", []string{},
+		"Optional, operating system of the nodes, such as windows, which don't participate in the VxLAN overlay "+
+			"and are not added to the FDB of the VxLAN Tunnel, can be specified multiple times. "+
+			"Supported only in CRD mode.")
 	vxlanFlags.Usage = func() {
 		fmt.Fprintf(os.Stderr, "  Openshift SDN:\n%s\n", vxlanFlags.FlagUsagesWrapped(width))
 	}
@@ -439,6 +721,9 @@ func _init() {
 	gtmCredsDir = gtmBigIPFlags.String("gtm-credentials-directory", "",
 		"Optional, directory that contains the GTM BIG-IP username, password, and/or "+
 			"url files. To be used instead of username, password, and/or url arguments.")
+	autoGenerateWideIP = gtmBigIPFlags.Bool("auto-generate-wideip", false,
+		"Optional, when set to true, CIS creates a WideIP for the host of each VirtualServer annotated with "+
+			"cis.f5.com/gslb-zone and cis.f5.com/gslb-data-server, unless an ExternalDNS exists for the host.")
 	gtmBigIPFlags.Usage = func() {
 		fmt.Fprintf(os.Stderr, "  GTM:\n%s\n", gtmBigIPFlags.FlagUsagesWrapped(width))
 	}
@@ -532,6 +817,10 @@ func verifyArgs() error {
 		return fmt.Errorf("missing pool member type")
 	}
 
+	if *enableProfiling && (*profilingPort <= 0 || *profilingPort > 65535) {
+		return fmt.Errorf("invalid value provided for --profiling-port")
+	}
+
 	if len(*bigIPPartitions) == 0 {
 		return fmt.Errorf("missing a BIG-IP partition")
 	} else if len(*bigIPPartitions) > 0 {
@@ -541,11 +830,107 @@ func verifyArgs() error {
 		}
 	}
 
-	if (len(*bigIPURL) == 0 || len(*bigIPUsername) == 0 ||
+	if len(*xcAPIURL) > 0 {
+		// BIG-IP is not configured with F5 Distributed Cloud, BIG-IP credentials are not required
+		if len(*xcAPIToken) == 0 || len(*xcNamespace) == 0 {
+			return fmt.Errorf("Missing F5 Distributed Cloud credentials info")
+		}
+		if !*customResourceMode && *controllerMode == "" {
+			return fmt.Errorf("--xc-api-url is supported only in CRD mode")
+		}
+		if len(*nginxPlusAPIURL) > 0 {
+			return fmt.Errorf("Can not specify both xc-api-url and nginx-plus-api-url")
+		}
+	} else if len(*nginxPlusAPIURL) > 0 {
+		// BIG-IP is not configured with NGINX Plus, BIG-IP credentials are not required
+		if !*customResourceMode && *controllerMode == "" {
+			return fmt.Errorf("--nginx-plus-api-url is supported only in CRD mode")
+		}
+		if *nginxPlusAPIVersion <= 0 {
+			return fmt.Errorf("invalid value provided for --nginx-plus-api-version")
+		}
+	} else if len(*bigIQURL) > 0 {
+		// BIG-IP is reachable only through BIG-IQ, BIG-IP credentials are not required
+		if len(*bigIPURL) == 0 || len(*bigIQUsername) == 0 || len(*bigIQPassword) == 0 {
+			return fmt.Errorf("Missing BIG-IQ credentials info")
+		}
+		if !*customResourceMode && *controllerMode == "" {
+			return fmt.Errorf("--bigiq-url is supported only in CRD mode")
+		}
+		// REST calls other than the AS3 declarations are sent to the target BIG-IP with the BIG-IP credentials
+		if (len(*bigIPUsername) == 0 || len(*bigIPPassword) == 0) && len(*credsDir) == 0 &&
+			(*as3OptimisticLock || *restjavadExtraMB > 0 || *capacityInterval > 0 || *poolMemberStatsInterval > 0 ||
+				*bigipObjectCheckInterval > 0) {
+			return fmt.Errorf("BIG-IP credentials are required with --bigiq-url for --as3-optimistic-lock, " +
+				"--restjavad-extramb, --bigip-capacity-interval, --pool-member-stats-interval and " +
+				"--bigip-object-check-interval")
+		}
+	} else if (len(*bigIPURL) == 0 || len(*bigIPUsername) == 0 ||
 		len(*bigIPPassword) == 0) && len(*credsDir) == 0 {
 		return fmt.Errorf("Missing BIG-IP credentials info")
 	}
 
+	if *as3MaxRetries < 0 || *as3RetryInterval < 0 || *as3RetryMaxInterval < 0 {
+		return fmt.Errorf("invalid value provided for the AS3 retry parameters")
+	}
+	if *as3RetryJitter < 0 || *as3RetryJitter > 1 {
+		return fmt.Errorf("invalid value provided for --as3-retry-jitter, it should be between 0 and 1")
+	}
+	if *auditLogMaxSize < 0 {
+		return fmt.Errorf("invalid value provided for --audit-log-max-size")
+	}
+	if *circuitBreakerThreshold < 0 || *circuitBreakerCooldown < 0 {
+		return fmt.Errorf("invalid value provided for the circuit breaker parameters")
+	}
+	if *as3MaxDeclarationSize < 0 {
+		return fmt.Errorf("invalid value provided for --as3-max-declaration-size")
+	}
+	if *restjavadExtraMB < 0 {
+		return fmt.Errorf("invalid value provided for --restjavad-extramb")
+	}
+	if *bigipResponseCacheTTL < 0 {
+		return fmt.Errorf("invalid value provided for --bigip-response-cache-ttl")
+	}
+	if *bigIPRequestTimeout <= 0 || *bigIPConnectTimeout <= 0 || *bigIPMaxIdleConns < 0 {
+		return fmt.Errorf("invalid value provided for the BIG-IP http client parameters")
+	}
+	if *capacityInterval < 0 || *virtualServerLimit < 0 || *capacityThreshold <= 0 || *capacityThreshold > 100 {
+		return fmt.Errorf("invalid value provided for the BIG-IP capacity parameters")
+	}
+	if *poolMemberStatsInterval < 0 {
+		return fmt.Errorf("invalid value provided for --pool-member-stats-interval")
+	}
+	if *bigipObjectCheckInterval < 0 {
+		return fmt.Errorf("invalid value provided for --bigip-object-check-interval")
+	}
+	if *nodeEventBatchInterval < 0 {
+		return fmt.Errorf("invalid value provided for --node-event-batch-interval")
+	}
+	if *shardCount < 1 {
+		return fmt.Errorf("invalid value provided for --shard-count")
+	}
+	if *shardCount > 1 {
+		if !*customResourceMode && *controllerMode != string(controller.CustomResourceMode) {
+			return fmt.Errorf("--shard-count is supported only in CRD mode")
+		}
+		if *shardIndex < 0 {
+			hostname, _ := os.Hostname()
+			index, err := controller.GetShardIndex(hostname)
+			if err != nil {
+				return err
+			}
+			*shardIndex = index
+		}
+		if *shardIndex >= *shardCount {
+			return fmt.Errorf("invalid value provided for --shard-index")
+		}
+	}
+	switch *unschedulableNodeMembers {
+	case controller.UnschedulableNodeDrain, controller.UnschedulableNodeRemove, controller.UnschedulableNodeRetain:
+	default:
+		return fmt.Errorf("invalid value provided for --unschedulable-node-members")
+	}
+
 	if len(*namespaces) != 0 && len(*namespaceLabel) != 0 {
 		return fmt.Errorf("Can not specify both namespace and namespace-label")
 	}
@@ -575,6 +960,27 @@ func verifyArgs() error {
 				"Usage: --route-spec-configmap=<namespace>/<configmap-name>")
 		}
 	}
+	if len(*defaultPolicy) > 0 {
+		if len(strings.Split(*defaultPolicy, "/")) != 2 {
+			return fmt.Errorf("invalid value provided for --default-policy" +
+				"Usage: --default-policy=<namespace>/<policy-name>")
+		}
+	}
+	if _, err := controller.ParseBigIPReferences(*bigipReferences); err != nil {
+		return fmt.Errorf("invalid value provided for --bigip-reference: %v", err)
+	}
+	if len(*tlsFileDirectory) > 0 && !filepath.IsAbs(*tlsFileDirectory) {
+		return fmt.Errorf("invalid value provided for --tls-file-directory, absolute path of the directory is required")
+	}
+	namingTemplates := controller.NamingTemplates{VirtualServer: *vsNameTemplate, Pool: *poolNameTemplate}
+	if err := namingTemplates.Validate(); err != nil {
+		return fmt.Errorf("invalid value provided for --virtual-server-name-template or --pool-name-template: %v", err)
+	}
+
+	if len(*as3SchemaVersion) > 0 && !regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+$`).MatchString(*as3SchemaVersion) {
+		return fmt.Errorf("invalid value provided for --as3-schema-version. " +
+			"Usage: --as3-schema-version=<major>.<minor>.<patch>")
+	}
 
 	if *multiClusterMode != "standalone" && *multiClusterMode != "primary" && *multiClusterMode != "secondary" && *multiClusterMode != "" {
 		return fmt.Errorf("'%v' is not a valid multi cluster mode, allowed values are: standalone/primary/secondary", *multiClusterMode)
@@ -586,6 +992,18 @@ func verifyArgs() error {
 		return fmt.Errorf("missing --extended-spec-configmap parameter in the multiCluster mode. It's a required parameter in multiCluster mode")
 	}
 
+	if *ciliumMode != "" {
+		if *ciliumMode != controller.CiliumTunnelMode && *ciliumMode != controller.CiliumNativeMode {
+			return fmt.Errorf("'%v' is not a valid cilium mode, allowed values are: tunnel/native", *ciliumMode)
+		}
+		if *orchestrationCNI != controller.CILIUM_K8S {
+			return fmt.Errorf("cilium-mode is supported only with orchestration-cni %v", controller.CILIUM_K8S)
+		}
+		if *ciliumMode == controller.CiliumNativeMode && (*staticRoutingMode || len(*ciliumTunnelName) > 0) {
+			return fmt.Errorf("Cannot have static-routing-mode or cilium-name in cilium native mode as the pod " +
+				"routes of BIG-IP are configured outside CIS.")
+		}
+	}
 	if *staticRoutingMode == true {
 		if isNodePort || *poolMemberType == "nodeportlocal" {
 			return fmt.Errorf("Cannot run NodePort mode or nodeportlocal mode while supplying static-routing-mode true " +
@@ -639,9 +1057,11 @@ func verifyArgs() error {
 		}
 	}
 	if *overriderAS3CfgmapName != "" {
-		if len(strings.Split(*overriderAS3CfgmapName, "/")) != 2 {
-			return fmt.Errorf("Invalid value provided for --override-as3-declaration" +
-				"Usage: --override-as3-declaration=<namespace>/<configmap-name>")
+		for _, cfgmap := range strings.Split(*overriderAS3CfgmapName, ",") {
+			if len(strings.Split(strings.TrimSpace(cfgmap), "/")) != 2 {
+				return fmt.Errorf("Invalid value provided for --override-as3-declaration" +
+					"Usage: --override-as3-declaration=<namespace>/<configmap-name>[,<namespace>/<configmap-name>]")
+			}
 		}
 	}
 	switch *controllerMode {
@@ -716,6 +1136,12 @@ func getCredentials() error {
 		return fmt.Errorf("BIGIP-URL path must be empty or '/'; check URL formatting and/or remove %s from path",
 			u.Path)
 	}
+	if len(*bigIQURL) > 0 && !strings.HasPrefix(*bigIQURL, "https://") {
+		*bigIQURL = "https://" + *bigIQURL
+	}
+	if len(*xcAPIURL) > 0 && !strings.HasPrefix(*xcAPIURL, "https://") {
+		*xcAPIURL = "https://" + *xcAPIURL
+	}
 	return nil
 }
 
@@ -845,15 +1271,31 @@ func initController(
 	config *rest.Config,
 ) *controller.Controller {
 	postMgrParams := controller.PostParams{
-		BIGIPUsername:     *bigIPUsername,
-		BIGIPPassword:     *bigIPPassword,
-		BIGIPURL:          *bigIPURL,
-		TrustedCerts:      "",
-		SSLInsecure:       true,
-		AS3PostDelay:      *as3PostDelay,
-		LogAS3Response:    *logAS3Response,
-		LogAS3Request:     *logAS3Request,
-		HTTPClientMetrics: *httpClientMetrics,
+		BIGIPUsername:           *bigIPUsername,
+		BIGIPPassword:           *bigIPPassword,
+		BIGIPURL:                *bigIPURL,
+		TrustedCerts:            "",
+		SSLInsecure:             true,
+		AS3PostDelay:            *as3PostDelay,
+		LogAS3Response:          *logAS3Response,
+		LogAS3Request:           *logAS3Request,
+		HTTPClientMetrics:       *httpClientMetrics,
+		AS3OptimisticLock:       *as3OptimisticLock,
+		AS3MaxRetries:           *as3MaxRetries,
+		AS3RetryInterval:        *as3RetryInterval,
+		AS3RetryMaxInterval:     *as3RetryMaxInterval,
+		AS3RetryJitter:          *as3RetryJitter,
+		CircuitBreakerThreshold: *circuitBreakerThreshold,
+		CircuitBreakerCooldown:  *circuitBreakerCooldown,
+		AS3MaxDeclarationSize:   *as3MaxDeclarationSize,
+		AS3SplitTenants:         *as3SplitTenants,
+		RESTJavadExtraMB:        *restjavadExtraMB,
+		BigIPResponseCacheTTL:   *bigipResponseCacheTTL,
+		ClientParams:            getHTTPClientParams(),
+		BIGIQURL:                *bigIQURL,
+		BIGIQUsername:           *bigIQUsername,
+		BIGIQPassword:           *bigIQPassword,
+		BIGIQLoginProvider:      *bigIQLoginProvider,
 	}
 
 	GtmParams := controller.GTMParams{
@@ -877,11 +1319,26 @@ func initController(
 		StaticRoutingMode:  *staticRoutingMode,
 		SharedStaticRoutes: *sharedStaticRoutes,
 		MultiClusterMode:   *multiClusterMode,
+		AS3SchemaVersion:   *as3SchemaVersion,
+		AS3DeclValidation:  *as3DeclValidation,
+		SchemaLocal:        *schemaLocal,
+		SharePools:         *shareIdenticalPools,
+		XCParams: controller.XCParams{
+			XCAPIURL:    *xcAPIURL,
+			XCAPIToken:  *xcAPIToken,
+			XCNamespace: *xcNamespace,
+			XCSite:      *xcSite,
+		},
+		NginxParams: controller.NginxParams{
+			NginxPlusAPIURL:     *nginxPlusAPIURL,
+			NginxPlusAPIVersion: *nginxPlusAPIVersion,
+		},
 	}
 
 	// When CIS is configured in OCP cluster mode disable ARP in globalSection
 	// ARP not required for nodeport mode
-	if *openshiftSDNName != "" || *staticRoutingMode == true || *ciliumTunnelName != "" || *poolMemberType == "nodeport" || *poolMemberType == "nodeportlocal" {
+	if *openshiftSDNName != "" || *staticRoutingMode == true || *ciliumTunnelName != "" || *poolMemberType == "nodeport" || *poolMemberType == "nodeportlocal" ||
+		*ciliumMode == controller.CiliumNativeMode {
 		agentParams.DisableARP = true
 	}
 
@@ -894,6 +1351,9 @@ func initController(
 		globalSpecConfigMap = routeSpecConfigmap
 	}
 
+	// BIG-IP references are validated with the arguments
+	bigipRefs, _ := controller.ParseBigIPReferences(*bigipReferences)
+
 	ctlr := controller.NewController(
 		controller.Params{
 			Config:                      config,
@@ -917,6 +1377,42 @@ func initController(
 			StaticRoutingMode:           *staticRoutingMode,
 			OrchestrationCNI:            *orchestrationCNI,
 			MultiClusterMode:            *multiClusterMode,
+			AutoGenerateWideIP:          *autoGenerateWideIP,
+			EnableCRDIngress:            *enableCRDIngress,
+			EnableACMESolver:            *enableACMESolver,
+			EnforceSvcRefGrants:         *enforceSvcRefGrants,
+			EnableFinalizers:            *enableFinalizers,
+			EnableQuarantine:            *enableQuarantine,
+			EnableSecondaryNetworks:     *secondaryNetworks,
+			EnableDualStackMembers:      *dualStackMembers,
+			EnablePoolMemberRatio:       *poolMemberRatio,
+			ResourceClass:               *resourceClass,
+			DefaultPolicy:               *defaultPolicy,
+			IngressClass:                *ingressClass,
+			DeployConfigCR:              *deployConfigCR,
+			PoolMemberStatsInterval:     *poolMemberStatsInterval,
+			NodeEventBatchInterval:      *nodeEventBatchInterval,
+			UnschedulableNodeMembers:    *unschedulableNodeMembers,
+			PoolMemberNodeOS:            *poolMemberNodeOS,
+			VXLANExcludedNodeOS:         *vxlanExcludedNodeOS,
+			CapacityParams: controller.CapacityParams{
+				Interval:           *capacityInterval,
+				VirtualServerLimit: *virtualServerLimit,
+				Threshold:          *capacityThreshold,
+			},
+			NamingTemplates: controller.NamingTemplates{
+				VirtualServer: *vsNameTemplate,
+				Pool:          *poolNameTemplate,
+			},
+			TLSFileDirectory:         *tlsFileDirectory,
+			BigIPReferences:          bigipRefs,
+			BigIPObjectCheckInterval: *bigipObjectCheckInterval,
+			NamespaceShard:           controller.NamespaceShard{Count: *shardCount, Index: *shardIndex},
+			AuditParams: controller.AuditParams{
+				File:            *auditLogFile,
+				MaxSize:         *auditLogMaxSize,
+				EventsNamespace: *auditEventsNamespace,
+			},
 		},
 	)
 
@@ -939,6 +1435,14 @@ func main() {
 		os.Exit(0)
 	}
 
+	if *deployConfigCR != "" {
+		err = loadDeployConfig()
+		if nil != err {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	err = verifyArgs()
 	if nil != err {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
@@ -953,6 +1457,12 @@ func main() {
 	}
 
 	log.Infof("[INIT] Starting: Container Ingress Services - Version: %s, BuildInfo: %s", version, buildInfo)
+	// Switch the log level at runtime with SIGUSR1 or the loglevel endpoint of the localhost diagnostics listener,
+	// the endpoint is not served on the http-listen-address as it is not authenticated
+	log.ToggleDebugOnSignal(syscall.SIGUSR1)
+	if *enableProfiling {
+		diagnostics.Start(*profilingPort)
+	}
 	// add the warning if both extended-config-map & route-config-map are present
 	if len(*routeSpecConfigmap) > 0 && len(*extendedSpecConfigmap) > 0 {
 		log.Warningf("extended-spec-configmap and route-spec-configmap both are present. extended-spec-configmap will be given priority over route-spec-configmap")
@@ -1041,7 +1551,9 @@ func main() {
 		getGTMCredentials()
 		ctlr := initController(config)
 		ctlr.TeemData = td
-		if !(*disableTeems) {
+		if !(*disableTeems) && len(*xcAPIURL) == 0 && len(*nginxPlusAPIURL) == 0 &&
+			(len(*bigIQURL) == 0 || (len(*bigIPUsername) > 0 && len(*bigIPPassword) > 0)) {
+			// registration key is read from the target BIG-IP, not through BIG-IQ
 			key, err := ctlr.Agent.GetBigipRegKey()
 			if err != nil {
 				log.Errorf("%v", err)
@@ -1274,6 +1786,17 @@ func getAS3Params() *as3.Params {
 		DefaultRouteDomain:        *defaultRouteDomain,
 		PoolMemberType:            *poolMemberType,
 		HTTPClientMetrics:         *httpClientMetrics,
+		ClientParams:              getHTTPClientParams(),
+	}
+}
+
+// getHTTPClientParams returns the timeouts, keep-alive and proxy of the http client of the REST calls to BIG-IP
+func getHTTPClientParams() httpclient.Params {
+	return httpclient.Params{
+		RequestTimeout:       time.Duration(*bigIPRequestTimeout) * time.Second,
+		ConnectTimeout:       time.Duration(*bigIPConnectTimeout) * time.Second,
+		MaxIdleConnsPerHost:  *bigIPMaxIdleConns,
+		ProxyFromEnvironment: *bigIPProxyFromEnv,
 	}
 }
 
@@ -1307,6 +1830,70 @@ func getKubeConfig() (*rest.Config, error) {
 	return config, nil
 }
 
+// loadDeployConfig fetches the DeployConfig and sets the arguments from it
+func loadDeployConfig() error {
+	config, err := getKubeConfig()
+	if err != nil {
+		return err
+	}
+	kubeCRClient, err := versioned.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("error connecting to the client: %v", err)
+	}
+	deployConfig, err := kubeCRClient.CisV1().DeployConfigs().Get(context.TODO(), *deployConfigCR, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to fetch DeployConfig %v: %v", *deployConfigCR, err)
+	}
+	setArgsFromDeployConfig(deployConfig.Spec)
+	return nil
+}
+
+// setArgsFromDeployConfig overrides the arguments with the values set in the DeployConfig,
+// arguments are retained for the values not set
+func setArgsFromDeployConfig(spec cisapiv1.DeployConfigSpec) {
+	setString := func(arg *string, value string) {
+		if value != "" {
+			*arg = value
+		}
+	}
+	setInt := func(arg *int, value int) {
+		if value > 0 {
+			*arg = value
+		}
+	}
+	setBool := func(arg *bool, value *bool) {
+		if value != nil {
+			*arg = *value
+		}
+	}
+
+	setString(logLevel, spec.BaseConfig.LogLevel)
+	setString(controllerMode, spec.BaseConfig.ControllerMode)
+	if len(spec.BaseConfig.Namespaces) > 0 {
+		*namespaces = spec.BaseConfig.Namespaces
+	}
+	setString(namespaceLabel, spec.BaseConfig.NamespaceLabel)
+	setInt(nodePollInterval, spec.BaseConfig.NodePollInterval)
+	setInt(verifyInterval, spec.BaseConfig.VerifyInterval)
+	setInt(syncInterval, spec.BaseConfig.PeriodicSyncInterval)
+
+	setString(bigIPURL, spec.BigIPConfig.BigIPURL)
+	if len(spec.BigIPConfig.BigIPPartitions) > 0 {
+		*bigIPPartitions = spec.BigIPConfig.BigIPPartitions
+	}
+	setString(agent, spec.BigIPConfig.Agent)
+
+	setString(poolMemberType, spec.NetworkConfig.PoolMemberType)
+	setString(orchestrationCNI, spec.NetworkConfig.OrchestrationCNI)
+	setString(nodeLabelSelector, spec.NetworkConfig.NodeLabelSelector)
+	setBool(staticRoutingMode, spec.NetworkConfig.StaticRoutingMode)
+	setString(ciliumMode, spec.NetworkConfig.CiliumMode)
+
+	setInt(as3PostDelay, spec.AS3Config.PostDelay)
+	setBool(logAS3Request, spec.AS3Config.LogRequest)
+	setBool(logAS3Response, spec.AS3Config.LogResponse)
+}
+
 // Read certificate from configmap
 func getBIGIPTrustedCerts() string {
 	namespaceCfgmapSlice := strings.Split(*trustedCertsCfgmap, "/")
@@ -1346,14 +1933,16 @@ func getProcessAgentLabelFunc() func(map[string]string, string, string) bool {
 				if cfg == "" {
 					return true
 				}
-				c := strings.Split(cfg, "/")
-				if len(c) == 2 {
+				for _, cfgmap := range strings.Split(cfg, ",") {
+					c := strings.Split(strings.TrimSpace(cfgmap), "/")
+					if len(c) != 2 {
+						return true
+					}
 					if n == c[1] && ns == c[0] {
 						return true
 					}
-					return false
 				}
-				return true
+				return false
 			}
 			if m["overrideAS3"] == "true" || m["overrideAS3"] == "false" {
 				return funCMapOptions(*overriderAS3CfgmapName)
@@ -1415,6 +2004,9 @@ func getSDNType(config *rest.Config) string {
 			switch *orchestrationCNI {
 			case "cilium-k8s":
 				sdnType = "cilium"
+				if *ciliumMode == controller.CiliumNativeMode {
+					sdnType = "cilium-native"
+				}
 			default:
 				sdnType = *orchestrationCNI
 			}
@@ -18,6 +18,7 @@ package main
 
 import (
 	"fmt"
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
 	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/agent/as3"
 	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/agent/cccl"
 	"io/ioutil"
@@ -353,6 +354,237 @@ var _ = Describe("Main Tests", func() {
 			Expect(argError).To(BeNil())
 		})
 
+		It("verifies Cilium mode arguments", func() {
+			defer _init()
+			os.Args = []string{
+				"./bin/k8s-bigip-ctlr",
+				"--namespace=testing",
+				"--bigip-partition=velcro1",
+				"--bigip-password=admin",
+				"--bigip-url=bigip.example.com",
+				"--bigip-username=admin",
+				"--pool-member-type=cluster",
+				"--orchestration-cni=cilium-k8s",
+				"--cilium-mode=native",
+			}
+			flags.Parse(os.Args)
+			argError := verifyArgs()
+			Expect(argError).To(BeNil())
+
+			// Static routes are not configured in native mode
+			*staticRoutingMode = true
+			argError = verifyArgs()
+			Expect(argError).ToNot(BeNil())
+			*staticRoutingMode = false
+
+			*ciliumMode = "invalid"
+			argError = verifyArgs()
+			Expect(argError).ToNot(BeNil())
+
+			// Cilium mode is not supported with the other CNIs
+			*ciliumMode = "native"
+			*orchestrationCNI = "ovn-k8s"
+			argError = verifyArgs()
+			Expect(argError).ToNot(BeNil())
+		})
+
+		It("verifies BIG-IQ arguments", func() {
+			defer _init()
+			os.Args = []string{
+				"./bin/k8s-bigip-ctlr",
+				"--namespace=testing",
+				"--bigip-partition=velcro1",
+				"--bigip-url=bigip.example.com",
+				"--bigiq-url=bigiq.example.com",
+				"--bigiq-username=admin",
+				"--bigiq-password=admin",
+				"--custom-resource-mode=true",
+			}
+			flags.Parse(os.Args)
+			argError := verifyArgs()
+			Expect(argError).To(BeNil())
+			Expect(*bigIQLoginProvider).To(Equal("local"))
+			Expect(getCredentials()).To(BeNil())
+			Expect(*bigIQURL).To(Equal("https://bigiq.example.com"))
+
+			// Missing BIG-IQ credentials
+			*bigIQPassword = ""
+			argError = verifyArgs()
+			Expect(argError).ToNot(BeNil())
+			*bigIQPassword = "admin"
+
+			// REST calls to the target BIG-IP need the BIG-IP credentials
+			*bigipObjectCheckInterval = 30
+			argError = verifyArgs()
+			Expect(argError).ToNot(BeNil())
+			*bigIPUsername = "admin"
+			*bigIPPassword = "admin"
+			argError = verifyArgs()
+			Expect(argError).To(BeNil())
+			*bigipObjectCheckInterval = 0
+
+			// BIG-IQ not supported without CRD mode
+			*customResourceMode = false
+			argError = verifyArgs()
+			Expect(argError).ToNot(BeNil())
+		})
+
+		It("verifies F5 Distributed Cloud arguments", func() {
+			defer _init()
+			os.Args = []string{
+				"./bin/k8s-bigip-ctlr",
+				"--namespace=testing",
+				"--bigip-partition=velcro1",
+				"--xc-api-url=acme.console.ves.volterra.io",
+				"--xc-api-token=token",
+				"--xc-namespace=cis",
+				"--custom-resource-mode=true",
+			}
+			flags.Parse(os.Args)
+			argError := verifyArgs()
+			Expect(argError).To(BeNil())
+			Expect(getCredentials()).To(BeNil())
+			Expect(*xcAPIURL).To(Equal("https://acme.console.ves.volterra.io"))
+
+			// Missing API token
+			*xcAPIToken = ""
+			argError = verifyArgs()
+			Expect(argError).ToNot(BeNil())
+			*xcAPIToken = "token"
+
+			// F5 Distributed Cloud not supported without CRD mode
+			*customResourceMode = false
+			argError = verifyArgs()
+			Expect(argError).ToNot(BeNil())
+		})
+
+		It("verifies NGINX Plus arguments", func() {
+			defer _init()
+			os.Args = []string{
+				"./bin/k8s-bigip-ctlr",
+				"--namespace=testing",
+				"--bigip-partition=velcro1",
+				"--nginx-plus-api-url=http://nginx.example.com:8080",
+				"--custom-resource-mode=true",
+			}
+			flags.Parse(os.Args)
+			argError := verifyArgs()
+			Expect(argError).To(BeNil())
+			Expect(*nginxPlusAPIVersion).To(Equal(8))
+
+			// Invalid API version
+			*nginxPlusAPIVersion = 0
+			argError = verifyArgs()
+			Expect(argError).ToNot(BeNil())
+			*nginxPlusAPIVersion = 8
+
+			// NGINX Plus not supported without CRD mode
+			*customResourceMode = false
+			argError = verifyArgs()
+			Expect(argError).ToNot(BeNil())
+		})
+
+		It("verifies namespace shard arguments", func() {
+			defer _init()
+			os.Args = []string{
+				"./bin/k8s-bigip-ctlr",
+				"--bigip-partition=velcro1",
+				"--bigip-password=admin",
+				"--bigip-url=bigip.example.com",
+				"--bigip-username=admin",
+				"--shard-count=3",
+				"--shard-index=2",
+				"--custom-resource-mode=true",
+			}
+			flags.Parse(os.Args)
+			argError := verifyArgs()
+			Expect(argError).To(BeNil())
+
+			// Shard index out of the shards
+			*shardIndex = 3
+			argError = verifyArgs()
+			Expect(argError).ToNot(BeNil())
+			*shardIndex = 2
+
+			// Invalid shard count
+			*shardCount = 0
+			argError = verifyArgs()
+			Expect(argError).ToNot(BeNil())
+			*shardCount = 3
+
+			// Sharding not supported without CRD mode
+			*customResourceMode = false
+			argError = verifyArgs()
+			Expect(argError).ToNot(BeNil())
+		})
+
+		It("verifies profiling arguments", func() {
+			defer _init()
+			os.Args = []string{
+				"./bin/k8s-bigip-ctlr",
+				"--bigip-partition=velcro1",
+				"--bigip-password=admin",
+				"--bigip-url=bigip.example.com",
+				"--bigip-username=admin",
+				"--enable-profiling=true",
+				"--profiling-port=6061",
+			}
+			flags.Parse(os.Args)
+			argError := verifyArgs()
+			Expect(argError).To(BeNil())
+			Expect(*profilingPort).To(Equal(6061))
+
+			// Invalid profiling port
+			*profilingPort = 70000
+			argError = verifyArgs()
+			Expect(argError).ToNot(BeNil())
+		})
+
+		It("verifies DeployConfig overrides the arguments", func() {
+			defer _init()
+			os.Args = []string{
+				"./bin/k8s-bigip-ctlr",
+				"--namespace=testing",
+				"--bigip-partition=velcro1",
+				"--bigip-password=admin",
+				"--bigip-url=bigip.example.com",
+				"--bigip-username=admin",
+				"--log-as3-response=true",
+				"--deploy-config-cr=cisconfig",
+				"--custom-resource-mode=true",
+			}
+			flags.Parse(os.Args)
+			logRequestArg := true
+			logAS3Request = &logRequestArg
+			logRequest := false
+			setArgsFromDeployConfig(cisapiv1.DeployConfigSpec{
+				BaseConfig: cisapiv1.BaseConfig{
+					LogLevel:   "DEBUG",
+					Namespaces: []string{"default", "test"},
+				},
+				BigIPConfig: cisapiv1.BigIPConfig{
+					BigIPURL:        "https://10.1.1.1",
+					BigIPPartitions: []string{"cis"},
+				},
+				NetworkConfig: cisapiv1.NetworkConfig{PoolMemberType: "cluster"},
+				AS3Config:     cisapiv1.AS3Config{PostDelay: 10, LogRequest: &logRequest},
+			})
+			argError := verifyArgs()
+			Expect(argError).To(BeNil())
+			Expect(*deployConfigCR).To(Equal("cisconfig"))
+			Expect(*logLevel).To(Equal("DEBUG"))
+			Expect(*namespaces).To(Equal([]string{"default", "test"}))
+			Expect(*bigIPURL).To(Equal("https://10.1.1.1"))
+			Expect(*bigIPPartitions).To(Equal([]string{"cis"}))
+			Expect(*poolMemberType).To(Equal("cluster"))
+			Expect(*as3PostDelay).To(Equal(10))
+			Expect(*logAS3Request).To(BeFalse(), "Argument not disabled by the DeployConfig")
+			// Arguments are retained for the values not set in the DeployConfig
+			Expect(*bigIPUsername).To(Equal("admin"))
+			Expect(*logAS3Response).To(BeTrue())
+			Expect(*customResourceMode).To(BeTrue())
+		})
+
 		It("verifies Common not in list of partitions", func() {
 			defer _init()
 			os.Args = []string{
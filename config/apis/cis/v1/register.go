@@ -48,6 +48,16 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&ExternalDNSList{},
 		&Policy{},
 		&PolicyList{},
+		&DataGroup{},
+		&DataGroupList{},
+		&TLSCertificate{},
+		&TLSCertificateList{},
+		&ServiceReferenceGrant{},
+		&ServiceReferenceGrantList{},
+		&HealthMonitor{},
+		&HealthMonitorList{},
+		&DeployConfig{},
+		&DeployConfigList{},
 	)
 
 	scheme.AddKnownTypes(
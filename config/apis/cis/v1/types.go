@@ -21,8 +21,23 @@ type VirtualServer struct {
 
 // VirtualServerStatus is the status of the VirtualServer resource.
 type VirtualServerStatus struct {
-	VSAddress string `json:"vsAddress,omitempty"`
-	StatusOk  string `json:"status,omitempty"`
+	VSAddress       string             `json:"vsAddress,omitempty"`
+	StatusOk        string             `json:"status,omitempty"`
+	Conditions      []metav1.Condition `json:"conditions,omitempty"`
+	EffectivePolicy *PolicySpec        `json:"effectivePolicy,omitempty"`
+	// BIG-IP virtual servers of the VirtualServer with the attached policies and profiles
+	BigIPVirtualServers []BigIPVirtualServer `json:"bigipVirtualServers,omitempty"`
+	// generation of the VirtualServer of the last declaration posted successfully
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// BigIPVirtualServer is the virtual server created on BIG-IP for the VirtualServer resource.
+type BigIPVirtualServer struct {
+	Partition   string   `json:"partition,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Destination string   `json:"destination,omitempty"`
+	Policies    []string `json:"policies,omitempty"`
+	Profiles    []string `json:"profiles,omitempty"`
 }
 
 // VirtualServerSpec is the spec of the VirtualServer resource.
@@ -36,14 +51,22 @@ type VirtualServerSpec struct {
 	VirtualServerHTTPPort            int32            `json:"virtualServerHTTPPort,omitempty"`
 	VirtualServerHTTPSPort           int32            `json:"virtualServerHTTPSPort,omitempty"`
 	DefaultPool                      DefaultPool      `json:"defaultPool,omitempty"`
+	ErrorPage                        *ErrorPage       `json:"errorPage,omitempty"`
+	MaintenanceMode                  *MaintenanceMode `json:"maintenanceMode,omitempty"`
 	Pools                            []Pool           `json:"pools,omitempty"`
+	NodeMemberLabel                  string           `json:"nodeMemberLabel,omitempty"`
 	TLSProfileName                   string           `json:"tlsProfileName,omitempty"`
 	HTTPTraffic                      string           `json:"httpTraffic,omitempty"`
+	HTTPRedirect                     HTTPRedirect     `json:"httpRedirect,omitempty"`
 	SNAT                             string           `json:"snat,omitempty"`
+	ProxyProtocol                    string           `json:"proxyProtocol,omitempty"`
 	WAF                              string           `json:"waf,omitempty"`
 	RewriteAppRoot                   string           `json:"rewriteAppRoot,omitempty"`
 	AllowVLANs                       []string         `json:"allowVlans,omitempty"`
+	RejectVLANs                      []string         `json:"rejectVlans,omitempty"`
 	IRules                           []string         `json:"iRules,omitempty"`
+	IRulesPriority                   string           `json:"iRulesPriority,omitempty"`
+	BigIPPolicies                    []string         `json:"bigipPolicies,omitempty"`
 	ServiceIPAddress                 []ServiceAddress `json:"serviceAddress,omitempty"`
 	PolicyName                       string           `json:"policyName,omitempty"`
 	PersistenceProfile               string           `json:"persistenceProfile,omitempty"`
@@ -54,10 +77,13 @@ type VirtualServerSpec struct {
 	AllowSourceRange                 []string         `json:"allowSourceRange,omitempty"`
 	HttpMrfRoutingEnabled            *bool            `json:"httpMrfRoutingEnabled,omitempty"`
 	Partition                        string           `json:"partition,omitempty"`
+	RouteDomain                      int32            `json:"routeDomain,omitempty"`
+	TrafficGroup                     string           `json:"trafficGroup,omitempty"`
 }
 
 // ServiceAddress Service IP address definition (BIG-IP virtual-address).
 type ServiceAddress struct {
+	VirtualAddress     string `json:"virtualAddress,omitempty"`
 	ArpEnabled         bool   `json:"arpEnabled,omitempty"`
 	ICMPEcho           string `json:"icmpEcho,omitempty"`
 	RouteAdvertisement string `json:"routeAdvertisement,omitempty"`
@@ -77,17 +103,47 @@ type DefaultPool struct {
 	ReselectTries     int32              `json:"reselectTries,omitempty"`
 	ServiceDownAction string             `json:"serviceDownAction,omitempty"`
 	Reference         string             `json:"reference,omitempty"`
+	MinimumMonitors   intstr.IntOrString `json:"minimumMonitors,omitempty"`
+}
+
+// HTTPRedirect defines the redirect of HTTP requests to HTTPS with httpTraffic redirect.
+type HTTPRedirect struct {
+	StatusCode   int32    `json:"statusCode,omitempty"`
+	ExcludePaths []string `json:"excludePaths,omitempty"`
+}
+
+// ErrorPage defines the HTTP response sent for requests not served by any pool.
+type ErrorPage struct {
+	StatusCode  int32  `json:"statusCode"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        string `json:"body,omitempty"`
+}
+
+// MaintenanceMode defines the static response sent for all the requests during maintenance.
+// The optional secret holds the response in body key and its content type in contentType key.
+type MaintenanceMode struct {
+	Enabled bool   `json:"enabled"`
+	Secret  string `json:"secret,omitempty"`
 }
 
 // Pool defines a pool object in BIG-IP.
 type Pool struct {
 	Name                 string                         `json:"name,omitempty"`
 	Path                 string                         `json:"path,omitempty"`
+	PathType             string                         `json:"pathType,omitempty"`
+	CaseSensitive        bool                           `json:"caseSensitive,omitempty"`
+	HostRegex            string                         `json:"hostRegex,omitempty"`
+	PathRegex            string                         `json:"pathRegex,omitempty"`
 	Service              string                         `json:"service"`
 	ServicePort          intstr.IntOrString             `json:"servicePort"`
 	NodeMemberLabel      string                         `json:"nodeMemberLabel,omitempty"`
+	NetworkAttachment    string                         `json:"networkAttachment,omitempty"`
+	AddressFamily        string                         `json:"addressFamily,omitempty"`
+	MemberRatio          string                         `json:"memberRatio,omitempty"`
+	WarmUpPeriod         int32                          `json:"warmUpPeriod,omitempty"`
 	Monitor              Monitor                        `json:"monitor"`
 	Monitors             []Monitor                      `json:"monitors"`
+	MinimumMonitors      intstr.IntOrString             `json:"minimumMonitors,omitempty"`
 	Rewrite              string                         `json:"rewrite,omitempty"`
 	Balance              string                         `json:"loadBalancingMethod,omitempty"`
 	WAF                  string                         `json:"waf,omitempty"`
@@ -97,6 +153,7 @@ type Pool struct {
 	HostRewrite          string                         `json:"hostRewrite,omitempty"`
 	Weight               *int32                         `json:"weight,omitempty"`
 	AlternateBackends    []AlternateBackend             `json:"alternateBackends"`
+	Mirror               *Mirror                        `json:"mirror,omitempty"`
 	MultiClusterServices []MultiClusterServiceReference `json:"extendedServiceReferences,omitempty"`
 }
 
@@ -107,6 +164,14 @@ type AlternateBackend struct {
 	Weight           *int32 `json:"weight,omitempty"`
 }
 
+// Mirror defines the service receiving a copy of the percentage of requests served by the pool.
+type Mirror struct {
+	Service          string             `json:"service"`
+	ServicePort      intstr.IntOrString `json:"servicePort"`
+	ServiceNamespace string             `json:"serviceNamespace,omitempty"`
+	Percentage       int32              `json:"percentage,omitempty"`
+}
+
 type MultiClusterServiceReference struct {
 	ClusterName string             `json:"clusterName"`
 	SvcName     string             `json:"serviceName"`
@@ -125,6 +190,11 @@ type Monitor struct {
 	TargetPort int32  `json:"targetPort"`
 	Name       string `json:"name,omitempty"`
 	Reference  string `json:"reference,omitempty"`
+	// ClientTLS is the secret with the client certificate presented by the https monitor
+	ClientTLS     string `json:"clientTLS,omitempty"`
+	SNIServerName string `json:"sniServerName,omitempty"`
+	// ProbeTimeout is the seconds the GTM monitor of the ExternalDNS pool waits for the probe response
+	ProbeTimeout int `json:"probeTimeout,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -162,6 +232,19 @@ type TLS struct {
 	ServerSSL   string   `json:"serverSSL"`
 	ServerSSLs  []string `json:"serverSSLs"`
 	Reference   string   `json:"reference"`
+	// Private keys of the clientSSL certificates stored on BIG-IP instead of the secrets
+	PrivateKeys []TLSPrivateKey `json:"privateKeys,omitempty"`
+}
+
+// TLSPrivateKey refers the private key of the certificate of a clientSSL secret, which is stored on BIG-IP
+// in the FIPS partition or the external HSM, so that the key does not exist in kubernetes
+type TLSPrivateKey struct {
+	// ClientSSL secret holding only the certificate in tls.crt
+	Secret string `json:"secret"`
+	// Key on BIG-IP, such as /Common/foo.key
+	BigIPKey string `json:"bigipKey"`
+	// Secret holding the passphrase of the key in passphrase
+	PassphraseSecret string `json:"passphraseSecret,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -176,6 +259,7 @@ type TLSProfileList struct {
 
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
 
 // IngressLink is a Custom Resource for KIC Ingress
 type IngressLink struct {
@@ -193,12 +277,14 @@ type IngressLinkStatus struct {
 
 // IngressLinkSpec is Spec for IngressLink
 type IngressLinkSpec struct {
-	VirtualServerAddress string                `json:"virtualServerAddress,omitempty"`
-	Host                 string                `json:"host,omitempty"`
-	Selector             *metav1.LabelSelector `json:"selector"`
-	IRules               []string              `json:"iRules,omitempty"`
-	IPAMLabel            string                `json:"ipamLabel"`
-	Partition            string                `json:"partition,omitempty"`
+	VirtualServerAddress             string                `json:"virtualServerAddress,omitempty"`
+	AdditionalVirtualServerAddresses []string              `json:"additionalVirtualServerAddresses,omitempty"`
+	Host                             string                `json:"host,omitempty"`
+	Selector                         *metav1.LabelSelector `json:"selector"`
+	IRules                           []string              `json:"iRules,omitempty"`
+	IPAMLabel                        string                `json:"ipamLabel"`
+	Partition                        string                `json:"partition,omitempty"`
+	RouteDomain                      int32                 `json:"routeDomain,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -227,32 +313,60 @@ type TransportServer struct {
 
 // TransportServerStatus is the status of the VirtualServer resource.
 type TransportServerStatus struct {
-	VSAddress string `json:"vsAddress,omitempty"`
-	StatusOk  string `json:"status,omitempty"`
+	VSAddress  string             `json:"vsAddress,omitempty"`
+	StatusOk   string             `json:"status,omitempty"`
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// generation of the TransportServer of the last declaration posted successfully
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// SNIRoute routes the TLS passthrough traffic of the server name to the pool
+type SNIRoute struct {
+	Host string `json:"host"`
+	Pool Pool   `json:"pool"`
 }
 
 // TransportServerSpec is the spec of the VirtualServer resource.
 type TransportServerSpec struct {
-	VirtualServerAddress string           `json:"virtualServerAddress"`
-	VirtualServerPort    int32            `json:"virtualServerPort"`
-	VirtualServerName    string           `json:"virtualServerName"`
-	Host                 string           `json:"host,omitempty"`
-	HostGroup            string           `json:"hostGroup,omitempty"`
-	Mode                 string           `json:"mode"`
-	SNAT                 string           `json:"snat"`
-	Pool                 Pool             `json:"pool"`
-	AllowVLANs           []string         `json:"allowVlans,omitempty"`
-	Type                 string           `json:"type,omitempty"`
-	ServiceIPAddress     []ServiceAddress `json:"serviceAddress"`
-	IPAMLabel            string           `json:"ipamLabel"`
-	IRules               []string         `json:"iRules,omitempty"`
-	PolicyName           string           `json:"policyName,omitempty"`
-	PersistenceProfile   string           `json:"persistenceProfile,omitempty"`
-	ProfileL4            string           `json:"profileL4,omitempty"`
-	DOS                  string           `json:"dos,omitempty"`
-	BotDefense           string           `json:"botDefense,omitempty"`
-	Profiles             ProfileSpec      `json:"profiles,omitempty"`
-	Partition            string           `json:"partition,omitempty"`
+	VirtualServerAddress             string           `json:"virtualServerAddress"`
+	AdditionalVirtualServerAddresses []string         `json:"additionalVirtualServerAddresses,omitempty"`
+	VirtualServerPort                int32            `json:"virtualServerPort"`
+	VirtualServerName                string           `json:"virtualServerName"`
+	Host                             string           `json:"host,omitempty"`
+	HostGroup                        string           `json:"hostGroup,omitempty"`
+	Mode                             string           `json:"mode"`
+	SNAT                             string           `json:"snat"`
+	ProxyProtocol                    string           `json:"proxyProtocol,omitempty"`
+	Pool                             Pool             `json:"pool"`
+	SNIRoutes                        []SNIRoute       `json:"sniRoutes,omitempty"`
+	NodeMemberLabel                  string           `json:"nodeMemberLabel,omitempty"`
+	AllowVLANs                       []string         `json:"allowVlans,omitempty"`
+	RejectVLANs                      []string         `json:"rejectVlans,omitempty"`
+	Type                             string           `json:"type,omitempty"`
+	ServiceIPAddress                 []ServiceAddress `json:"serviceAddress"`
+	IPAMLabel                        string           `json:"ipamLabel"`
+	IRules                           []string         `json:"iRules,omitempty"`
+	IRulesPriority                   string           `json:"iRulesPriority,omitempty"`
+	PolicyName                       string           `json:"policyName,omitempty"`
+	PersistenceProfile               string           `json:"persistenceProfile,omitempty"`
+	ProfileL4                        string           `json:"profileL4,omitempty"`
+	FastL4                           FastL4           `json:"fastL4,omitempty"`
+	DOS                              string           `json:"dos,omitempty"`
+	BotDefense                       string           `json:"botDefense,omitempty"`
+	Profiles                         ProfileSpec      `json:"profiles,omitempty"`
+	Partition                        string           `json:"partition,omitempty"`
+	Mirroring                        string           `json:"mirroring,omitempty"`
+	PersistenceMirroring             bool             `json:"persistenceMirroring,omitempty"`
+	RouteDomain                      int32            `json:"routeDomain,omitempty"`
+	TrafficGroup                     string           `json:"trafficGroup,omitempty"`
+}
+
+// FastL4 defines the settings of the FastL4 profile created for the performance mode TransportServer,
+// PVA acceleration is set by the BIG-IP FastL4 profile referred with profileL4
+type FastL4 struct {
+	IdleTimeout         int32 `json:"idleTimeout,omitempty"`
+	LooseInitialization bool  `json:"looseInitialization,omitempty"`
+	LooseClose          bool  `json:"looseClose,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -287,6 +401,8 @@ type ExternalDNSSpec struct {
 	TTLPersistence        uint32    `json:"ttlPersistence"`
 	ClientSubnetPreferred *bool     `json:"clientSubnetPreferred,omitempty"`
 	Pools                 []DNSPool `json:"pools"`
+	// TTL is the seconds the DNS answers of the pools of the WideIP are cached by the resolvers
+	TTL uint32 `json:"ttl,omitempty"`
 }
 
 type DNSPool struct {
@@ -298,6 +414,10 @@ type DNSPool struct {
 	Ratio             int       `json:"ratio"`
 	Monitor           Monitor   `json:"monitor"`
 	Monitors          []Monitor `json:"monitors"`
+	// MinimumMonitors is the number of monitors, or all, that must pass for the pool member to be available
+	MinimumMonitors intstr.IntOrString `json:"minimumMonitors,omitempty"`
+	// Members are the virtual servers of the data server, e.g. registered by CIS of other clusters
+	Members []string `json:"members,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -311,14 +431,16 @@ type ExternalDNSList struct {
 }
 
 type PolicySpec struct {
-	L7Policies  L7PolicySpec  `json:"l7Policies,omitempty"`
-	L3Policies  L3PolicySpec  `json:"l3Policies,omitempty"`
-	LtmPolicies LtmIRulesSpec `json:"ltmPolicies,omitempty"`
-	IRules      LtmIRulesSpec `json:"iRules,omitempty"`
-	IRuleList   []string      `json:"iRuleList,omitempty"`
-	Profiles    ProfileSpec   `json:"profiles,omitempty"`
-	SNAT        string        `json:"snat,omitempty"`
-	AutoLastHop string        `json:"autoLastHop,omitempty"`
+	L7Policies           L7PolicySpec  `json:"l7Policies,omitempty"`
+	L3Policies           L3PolicySpec  `json:"l3Policies,omitempty"`
+	LtmPolicies          LtmIRulesSpec `json:"ltmPolicies,omitempty"`
+	IRules               LtmIRulesSpec `json:"iRules,omitempty"`
+	IRuleList            []string      `json:"iRuleList,omitempty"`
+	Profiles             ProfileSpec   `json:"profiles,omitempty"`
+	SNAT                 string        `json:"snat,omitempty"`
+	AutoLastHop          string        `json:"autoLastHop,omitempty"`
+	Mirroring            string        `json:"mirroring,omitempty"`
+	PersistenceMirroring bool          `json:"persistenceMirroring,omitempty"`
 }
 
 type SSLProfiles struct {
@@ -328,6 +450,7 @@ type SSLProfiles struct {
 
 type AnalyticsProfiles struct {
 	HTTPAnalyticsProfile string `json:"http,omitempty"`
+	TCPAnalyticsProfile  string `json:"tcp,omitempty"`
 }
 
 type L7PolicySpec struct {
@@ -350,23 +473,49 @@ type LtmIRulesSpec struct {
 }
 
 type ProfileSpec struct {
-	TCP                   ProfileTCP        `json:"tcp,omitempty"`
-	UDP                   string            `json:"udp,omitempty"`
-	HTTP                  string            `json:"http,omitempty"`
-	HTTP2                 ProfileHTTP2      `json:"http2,omitempty"`
-	RewriteProfile        string            `json:"rewriteProfile,omitempty"`
-	PersistenceProfile    string            `json:"persistenceProfile,omitempty"`
-	LogProfiles           []string          `json:"logProfiles,omitempty"`
-	ProfileL4             string            `json:"profileL4,omitempty"`
-	ProfileMultiplex      string            `json:"profileMultiplex,omitempty"`
-	HttpMrfRoutingEnabled *bool             `json:"httpMrfRoutingEnabled,omitempty"`
-	SSLProfiles           SSLProfiles       `json:"sslProfiles,omitempty"`
-	AnalyticsProfiles     AnalyticsProfiles `json:"analyticsProfiles,omitempty"`
-	ProfileWebSocket      string            `json:"profileWebSocket,omitempty"`
+	TCP                    ProfileTCP        `json:"tcp,omitempty"`
+	UDP                    string            `json:"udp,omitempty"`
+	HTTP                   string            `json:"http,omitempty"`
+	HTTPProfile            HTTPProfile       `json:"httpProfile,omitempty"`
+	HTTP2                  ProfileHTTP2      `json:"http2,omitempty"`
+	RewriteProfile         string            `json:"rewriteProfile,omitempty"`
+	PersistenceProfile     string            `json:"persistenceProfile,omitempty"`
+	LogProfiles            []string          `json:"logProfiles,omitempty"`
+	RequestLogging         *RequestLogging   `json:"requestLogging,omitempty"`
+	ProfileL4              string            `json:"profileL4,omitempty"`
+	ProfileMultiplex       string            `json:"profileMultiplex,omitempty"`
+	HttpMrfRoutingEnabled  *bool             `json:"httpMrfRoutingEnabled,omitempty"`
+	SSLProfiles            SSLProfiles       `json:"sslProfiles,omitempty"`
+	AnalyticsProfiles      AnalyticsProfiles `json:"analyticsProfiles,omitempty"`
+	ProfileWebSocket       string            `json:"profileWebSocket,omitempty"`
+	ProfileHTTPCompression string            `json:"profileHTTPCompression,omitempty"`
+	ProfileWebAcceleration string            `json:"profileWebAcceleration,omitempty"`
+	ProfileAccess          string            `json:"profileAccess,omitempty"`
+	PolicyPerRequestAccess string            `json:"policyPerRequestAccess,omitempty"`
 }
 type ProfileTCP struct {
-	Client string `json:"client,omitempty"`
-	Server string `json:"server,omitempty"`
+	Client            string `json:"client,omitempty"`
+	Server            string `json:"server,omitempty"`
+	IdleTimeout       int32  `json:"idleTimeout,omitempty"`
+	Nagle             string `json:"nagle,omitempty"`
+	CongestionControl string `json:"congestionControl,omitempty"`
+}
+
+// RequestLogging defines the request logging of the virtual to the remote syslog servers
+type RequestLogging struct {
+	Servers  []string `json:"servers"`
+	Port     int32    `json:"port"`
+	Protocol string   `json:"protocol,omitempty"`
+	Template string   `json:"template,omitempty"`
+}
+
+// HTTPProfile defines the settings of the HTTP profile created for the virtual
+type HTTPProfile struct {
+	InsertXForwardedFor *bool  `json:"insertXForwardedFor,omitempty"`
+	MaxHeaderSize       int32  `json:"maxHeaderSize,omitempty"`
+	MaxHeaderCount      int32  `json:"maxHeaderCount,omitempty"`
+	RewriteRedirects    string `json:"rewriteRedirects,omitempty"`
+	ProxyType           string `json:"proxyType,omitempty"`
 }
 
 type ProfileHTTP2 struct {
@@ -394,3 +543,191 @@ type PolicyList struct {
 
 	Items []Policy `json:"items"`
 }
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DataGroup describes a DataGroup custom resource.
+type DataGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DataGroupSpec `json:"spec"`
+}
+
+// DataGroupSpec is the spec of the DataGroup
+type DataGroupSpec struct {
+	Type      string            `json:"type"`
+	Records   map[string]string `json:"records,omitempty"`
+	Partition string            `json:"partition,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DataGroupList is list of DataGroup resources
+type DataGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []DataGroup `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TLSCertificate describes a TLSCertificate custom resource.
+type TLSCertificate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TLSCertificateSpec `json:"spec"`
+}
+
+// TLSCertificateSpec is the spec of the TLSCertificate
+type TLSCertificateSpec struct {
+	Type        string `json:"type,omitempty"`
+	Secret      string `json:"secret,omitempty"`
+	Certificate string `json:"certificate,omitempty"`
+	PrivateKey  string `json:"privateKey,omitempty"`
+	Partition   string `json:"partition,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TLSCertificateList is list of TLSCertificate resources
+type TLSCertificateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []TLSCertificate `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceReferenceGrant describes a ServiceReferenceGrant custom resource.
+type ServiceReferenceGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ServiceReferenceGrantSpec `json:"spec"`
+}
+
+// ServiceReferenceGrantSpec is the spec of the ServiceReferenceGrant
+type ServiceReferenceGrantSpec struct {
+	From     []ServiceReferenceGrantFrom `json:"from"`
+	Services []string                    `json:"services,omitempty"`
+}
+
+// ServiceReferenceGrantFrom defines the namespace of the VirtualServers allowed to refer the services
+type ServiceReferenceGrantFrom struct {
+	Namespace string `json:"namespace"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceReferenceGrantList is list of ServiceReferenceGrant resources
+type ServiceReferenceGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ServiceReferenceGrant `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// HealthMonitor describes a HealthMonitor custom resource.
+type HealthMonitor struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HealthMonitorSpec `json:"spec"`
+}
+
+// HealthMonitorSpec is the spec of the HealthMonitor
+type HealthMonitorSpec struct {
+	Type              string `json:"type"`
+	Send              string `json:"send,omitempty"`
+	Recv              string `json:"recv,omitempty"`
+	Interval          int    `json:"interval,omitempty"`
+	Timeout           int    `json:"timeout,omitempty"`
+	TargetPort        int32  `json:"targetPort,omitempty"`
+	Ciphers           string `json:"ciphers,omitempty"`
+	ClientCertificate string `json:"clientCertificate,omitempty"`
+	Partition         string `json:"partition,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// HealthMonitorList is list of HealthMonitor resources
+type HealthMonitorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []HealthMonitor `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DeployConfig describes a cluster scoped DeployConfig custom resource holding the controller configuration.
+type DeployConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DeployConfigSpec `json:"spec"`
+}
+
+// DeployConfigSpec is the spec of the DeployConfig
+type DeployConfigSpec struct {
+	BaseConfig    BaseConfig    `json:"baseConfig,omitempty"`
+	BigIPConfig   BigIPConfig   `json:"bigIpConfig,omitempty"`
+	NetworkConfig NetworkConfig `json:"networkConfig,omitempty"`
+	AS3Config     AS3Config     `json:"as3Config,omitempty"`
+}
+
+// BaseConfig defines the logging, mode and intervals of the controller
+type BaseConfig struct {
+	LogLevel             string   `json:"logLevel,omitempty"`
+	ControllerMode       string   `json:"controllerMode,omitempty"`
+	Namespaces           []string `json:"namespaces,omitempty"`
+	NamespaceLabel       string   `json:"namespaceLabel,omitempty"`
+	NodePollInterval     int      `json:"nodePollInterval,omitempty"`
+	VerifyInterval       int      `json:"verifyInterval,omitempty"`
+	PeriodicSyncInterval int      `json:"periodicSyncInterval,omitempty"`
+}
+
+// BigIPConfig defines the BIG-IP managed by the controller, credentials are provided with flags or the credentials directory
+type BigIPConfig struct {
+	BigIPURL        string   `json:"bigIpUrl,omitempty"`
+	BigIPPartitions []string `json:"bigIpPartitions,omitempty"`
+	Agent           string   `json:"agent,omitempty"`
+}
+
+// NetworkConfig defines how the pool members are reached from BIG-IP
+type NetworkConfig struct {
+	PoolMemberType    string `json:"poolMemberType,omitempty"`
+	OrchestrationCNI  string `json:"orchestrationCNI,omitempty"`
+	NodeLabelSelector string `json:"nodeLabelSelector,omitempty"`
+	StaticRoutingMode *bool  `json:"staticRoutingMode,omitempty"`
+	CiliumMode        string `json:"ciliumMode,omitempty"`
+}
+
+// AS3Config defines the posting and logging of the AS3 declarations
+type AS3Config struct {
+	PostDelay   int   `json:"postDelay,omitempty"`
+	LogRequest  *bool `json:"logRequest,omitempty"`
+	LogResponse *bool `json:"logResponse,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DeployConfigList is list of DeployConfig resources
+type DeployConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []DeployConfig `json:"items"`
+}
@@ -26,6 +26,100 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AS3Config) DeepCopyInto(out *AS3Config) {
+	*out = *in
+	if in.LogRequest != nil {
+		in, out := &in.LogRequest, &out.LogRequest
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LogResponse != nil {
+		in, out := &in.LogResponse, &out.LogResponse
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AS3Config.
+func (in *AS3Config) DeepCopy() *AS3Config {
+	if in == nil {
+		return nil
+	}
+	out := new(AS3Config)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BaseConfig) DeepCopyInto(out *BaseConfig) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BaseConfig.
+func (in *BaseConfig) DeepCopy() *BaseConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BaseConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BigIPConfig) DeepCopyInto(out *BigIPConfig) {
+	*out = *in
+	if in.BigIPPartitions != nil {
+		in, out := &in.BigIPPartitions, &out.BigIPPartitions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BigIPConfig.
+func (in *BigIPConfig) DeepCopy() *BigIPConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BigIPConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BigIPVirtualServer) DeepCopyInto(out *BigIPVirtualServer) {
+	*out = *in
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BigIPVirtualServer.
+func (in *BigIPVirtualServer) DeepCopy() *BigIPVirtualServer {
+	if in == nil {
+		return nil
+	}
+	out := new(BigIPVirtualServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DNSPool) DeepCopyInto(out *DNSPool) {
 	*out = *in
@@ -35,6 +129,12 @@ func (in *DNSPool) DeepCopyInto(out *DNSPool) {
 		*out = make([]Monitor, len(*in))
 		copy(*out, *in)
 	}
+	out.MinimumMonitors = in.MinimumMonitors
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -48,6 +148,185 @@ func (in *DNSPool) DeepCopy() *DNSPool {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataGroup) DeepCopyInto(out *DataGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataGroup.
+func (in *DataGroup) DeepCopy() *DataGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(DataGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DataGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataGroupList) DeepCopyInto(out *DataGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DataGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataGroupList.
+func (in *DataGroupList) DeepCopy() *DataGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(DataGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DataGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataGroupSpec) DeepCopyInto(out *DataGroupSpec) {
+	*out = *in
+	if in.Records != nil {
+		in, out := &in.Records, &out.Records
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataGroupSpec.
+func (in *DataGroupSpec) DeepCopy() *DataGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DataGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeployConfig) DeepCopyInto(out *DeployConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployConfig.
+func (in *DeployConfig) DeepCopy() *DeployConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DeployConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeployConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeployConfigList) DeepCopyInto(out *DeployConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DeployConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployConfigList.
+func (in *DeployConfigList) DeepCopy() *DeployConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(DeployConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DeployConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeployConfigSpec) DeepCopyInto(out *DeployConfigSpec) {
+	*out = *in
+	in.BaseConfig.DeepCopyInto(&out.BaseConfig)
+	in.BigIPConfig.DeepCopyInto(&out.BigIPConfig)
+	in.NetworkConfig.DeepCopyInto(&out.NetworkConfig)
+	in.AS3Config.DeepCopyInto(&out.AS3Config)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeployConfigSpec.
+func (in *DeployConfigSpec) DeepCopy() *DeployConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeployConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErrorPage) DeepCopyInto(out *ErrorPage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErrorPage.
+func (in *ErrorPage) DeepCopy() *ErrorPage {
+	if in == nil {
+		return nil
+	}
+	out := new(ErrorPage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalDNS) DeepCopyInto(out *ExternalDNS) {
 	*out = *in
@@ -131,6 +410,103 @@ func (in *ExternalDNSSpec) DeepCopy() *ExternalDNSSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRedirect) DeepCopyInto(out *HTTPRedirect) {
+	*out = *in
+	if in.ExcludePaths != nil {
+		in, out := &in.ExcludePaths, &out.ExcludePaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRedirect.
+func (in *HTTPRedirect) DeepCopy() *HTTPRedirect {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRedirect)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthMonitor) DeepCopyInto(out *HealthMonitor) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthMonitor.
+func (in *HealthMonitor) DeepCopy() *HealthMonitor {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthMonitor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HealthMonitor) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthMonitorList) DeepCopyInto(out *HealthMonitorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HealthMonitor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthMonitorList.
+func (in *HealthMonitorList) DeepCopy() *HealthMonitorList {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthMonitorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HealthMonitorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthMonitorSpec) DeepCopyInto(out *HealthMonitorSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthMonitorSpec.
+func (in *HealthMonitorSpec) DeepCopy() *HealthMonitorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthMonitorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IngressLink) DeepCopyInto(out *IngressLink) {
 	*out = *in
@@ -195,6 +571,11 @@ func (in *IngressLinkList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IngressLinkSpec) DeepCopyInto(out *IngressLinkSpec) {
 	*out = *in
+	if in.AdditionalVirtualServerAddresses != nil {
+		in, out := &in.AdditionalVirtualServerAddresses, &out.AdditionalVirtualServerAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Selector != nil {
 		in, out := &in.Selector, &out.Selector
 		*out = new(metav1.LabelSelector)
@@ -255,39 +636,72 @@ func (in *L3PolicySpec) DeepCopy() *L3PolicySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(L3PolicySpec)
+	out := new(L3PolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *L7PolicySpec) DeepCopyInto(out *L7PolicySpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new L7PolicySpec.
+func (in *L7PolicySpec) DeepCopy() *L7PolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(L7PolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LtmIRulesSpec) DeepCopyInto(out *LtmIRulesSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LtmIRulesSpec.
+func (in *LtmIRulesSpec) DeepCopy() *LtmIRulesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LtmIRulesSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *L7PolicySpec) DeepCopyInto(out *L7PolicySpec) {
+func (in *MaintenanceMode) DeepCopyInto(out *MaintenanceMode) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new L7PolicySpec.
-func (in *L7PolicySpec) DeepCopy() *L7PolicySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceMode.
+func (in *MaintenanceMode) DeepCopy() *MaintenanceMode {
 	if in == nil {
 		return nil
 	}
-	out := new(L7PolicySpec)
+	out := new(MaintenanceMode)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LtmIRulesSpec) DeepCopyInto(out *LtmIRulesSpec) {
+func (in *Mirror) DeepCopyInto(out *Mirror) {
 	*out = *in
+	out.ServicePort = in.ServicePort
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LtmIRulesSpec.
-func (in *LtmIRulesSpec) DeepCopy() *LtmIRulesSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Mirror.
+func (in *Mirror) DeepCopy() *Mirror {
 	if in == nil {
 		return nil
 	}
-	out := new(LtmIRulesSpec)
+	out := new(Mirror)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -308,6 +722,27 @@ func (in *Monitor) DeepCopy() *Monitor {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkConfig) DeepCopyInto(out *NetworkConfig) {
+	*out = *in
+	if in.StaticRoutingMode != nil {
+		in, out := &in.StaticRoutingMode, &out.StaticRoutingMode
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfig.
+func (in *NetworkConfig) DeepCopy() *NetworkConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Policy) DeepCopyInto(out *Policy) {
 	*out = *in
@@ -398,6 +833,11 @@ func (in *Pool) DeepCopyInto(out *Pool) {
 		*out = make([]Monitor, len(*in))
 		copy(*out, *in)
 	}
+	if in.Mirror != nil {
+		in, out := &in.Mirror, &out.Mirror
+		*out = new(Mirror)
+		**out = **in
+	}
 	return
 }
 
@@ -415,11 +855,17 @@ func (in *Pool) DeepCopy() *Pool {
 func (in *ProfileSpec) DeepCopyInto(out *ProfileSpec) {
 	*out = *in
 	out.TCP = in.TCP
+	in.HTTPProfile.DeepCopyInto(&out.HTTPProfile)
 	if in.LogProfiles != nil {
 		in, out := &in.LogProfiles, &out.LogProfiles
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.RequestLogging != nil {
+		in, out := &in.RequestLogging, &out.RequestLogging
+		*out = new(RequestLogging)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -433,6 +879,48 @@ func (in *ProfileSpec) DeepCopy() *ProfileSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPProfile) DeepCopyInto(out *HTTPProfile) {
+	*out = *in
+	if in.InsertXForwardedFor != nil {
+		in, out := &in.InsertXForwardedFor, &out.InsertXForwardedFor
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPProfile.
+func (in *HTTPProfile) DeepCopy() *HTTPProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequestLogging) DeepCopyInto(out *RequestLogging) {
+	*out = *in
+	if in.Servers != nil {
+		in, out := &in.Servers, &out.Servers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequestLogging.
+func (in *RequestLogging) DeepCopy() *RequestLogging {
+	if in == nil {
+		return nil
+	}
+	out := new(RequestLogging)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProfileTCP) DeepCopyInto(out *ProfileTCP) {
 	*out = *in
@@ -449,6 +937,23 @@ func (in *ProfileTCP) DeepCopy() *ProfileTCP {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SNIRoute) DeepCopyInto(out *SNIRoute) {
+	*out = *in
+	in.Pool.DeepCopyInto(&out.Pool)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SNIRoute.
+func (in *SNIRoute) DeepCopy() *SNIRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(SNIRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceAddress) DeepCopyInto(out *ServiceAddress) {
 	*out = *in
@@ -465,6 +970,108 @@ func (in *ServiceAddress) DeepCopy() *ServiceAddress {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceReferenceGrant) DeepCopyInto(out *ServiceReferenceGrant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceReferenceGrant.
+func (in *ServiceReferenceGrant) DeepCopy() *ServiceReferenceGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceReferenceGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceReferenceGrant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceReferenceGrantFrom) DeepCopyInto(out *ServiceReferenceGrantFrom) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceReferenceGrantFrom.
+func (in *ServiceReferenceGrantFrom) DeepCopy() *ServiceReferenceGrantFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceReferenceGrantFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceReferenceGrantList) DeepCopyInto(out *ServiceReferenceGrantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ServiceReferenceGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceReferenceGrantList.
+func (in *ServiceReferenceGrantList) DeepCopy() *ServiceReferenceGrantList {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceReferenceGrantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceReferenceGrantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceReferenceGrantSpec) DeepCopyInto(out *ServiceReferenceGrantSpec) {
+	*out = *in
+	if in.From != nil {
+		in, out := &in.From, &out.From
+		*out = make([]ServiceReferenceGrantFrom, len(*in))
+		copy(*out, *in)
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceReferenceGrantSpec.
+func (in *ServiceReferenceGrantSpec) DeepCopy() *ServiceReferenceGrantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceReferenceGrantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLS) DeepCopyInto(out *TLS) {
 	*out = *in
@@ -478,6 +1085,11 @@ func (in *TLS) DeepCopyInto(out *TLS) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PrivateKeys != nil {
+		in, out := &in.PrivateKeys, &out.PrivateKeys
+		*out = make([]TLSPrivateKey, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -491,6 +1103,98 @@ func (in *TLS) DeepCopy() *TLS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSCertificate) DeepCopyInto(out *TLSCertificate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSCertificate.
+func (in *TLSCertificate) DeepCopy() *TLSCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TLSCertificate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSCertificateList) DeepCopyInto(out *TLSCertificateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TLSCertificate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSCertificateList.
+func (in *TLSCertificateList) DeepCopy() *TLSCertificateList {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSCertificateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TLSCertificateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSCertificateSpec) DeepCopyInto(out *TLSCertificateSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSCertificateSpec.
+func (in *TLSCertificateSpec) DeepCopy() *TLSCertificateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSCertificateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSPrivateKey) DeepCopyInto(out *TLSPrivateKey) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSPrivateKey.
+func (in *TLSPrivateKey) DeepCopy() *TLSPrivateKey {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSPrivateKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLSProfile) DeepCopyInto(out *TLSProfile) {
 	*out = *in
@@ -579,7 +1283,7 @@ func (in *TransportServer) DeepCopyInto(out *TransportServer) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -637,12 +1341,29 @@ func (in *TransportServerList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TransportServerSpec) DeepCopyInto(out *TransportServerSpec) {
 	*out = *in
+	if in.AdditionalVirtualServerAddresses != nil {
+		in, out := &in.AdditionalVirtualServerAddresses, &out.AdditionalVirtualServerAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.Pool.DeepCopyInto(&out.Pool)
+	if in.SNIRoutes != nil {
+		in, out := &in.SNIRoutes, &out.SNIRoutes
+		*out = make([]SNIRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.AllowVLANs != nil {
 		in, out := &in.AllowVLANs, &out.AllowVLANs
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.RejectVLANs != nil {
+		in, out := &in.RejectVLANs, &out.RejectVLANs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.ServiceIPAddress != nil {
 		in, out := &in.ServiceIPAddress, &out.ServiceIPAddress
 		*out = make([]ServiceAddress, len(*in))
@@ -670,6 +1391,13 @@ func (in *TransportServerSpec) DeepCopy() *TransportServerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TransportServerStatus) DeepCopyInto(out *TransportServerStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -689,7 +1417,7 @@ func (in *VirtualServer) DeepCopyInto(out *VirtualServer) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -747,6 +1475,21 @@ func (in *VirtualServerList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualServerSpec) DeepCopyInto(out *VirtualServerSpec) {
 	*out = *in
+	if in.AdditionalVirtualServerAddresses != nil {
+		in, out := &in.AdditionalVirtualServerAddresses, &out.AdditionalVirtualServerAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ErrorPage != nil {
+		in, out := &in.ErrorPage, &out.ErrorPage
+		*out = new(ErrorPage)
+		**out = **in
+	}
+	if in.MaintenanceMode != nil {
+		in, out := &in.MaintenanceMode, &out.MaintenanceMode
+		*out = new(MaintenanceMode)
+		**out = **in
+	}
 	if in.Pools != nil {
 		in, out := &in.Pools, &out.Pools
 		*out = make([]Pool, len(*in))
@@ -754,16 +1497,27 @@ func (in *VirtualServerSpec) DeepCopyInto(out *VirtualServerSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.HTTPRedirect.DeepCopyInto(&out.HTTPRedirect)
 	if in.AllowVLANs != nil {
 		in, out := &in.AllowVLANs, &out.AllowVLANs
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.RejectVLANs != nil {
+		in, out := &in.RejectVLANs, &out.RejectVLANs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.IRules != nil {
 		in, out := &in.IRules, &out.IRules
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.BigIPPolicies != nil {
+		in, out := &in.BigIPPolicies, &out.BigIPPolicies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.ServiceIPAddress != nil {
 		in, out := &in.ServiceIPAddress, &out.ServiceIPAddress
 		*out = make([]ServiceAddress, len(*in))
@@ -791,6 +1545,25 @@ func (in *VirtualServerSpec) DeepCopy() *VirtualServerSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualServerStatus) DeepCopyInto(out *VirtualServerStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EffectivePolicy != nil {
+		in, out := &in.EffectivePolicy, &out.EffectivePolicy
+		*out = new(PolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BigIPVirtualServers != nil {
+		in, out := &in.BigIPVirtualServers, &out.BigIPVirtualServers
+		*out = make([]BigIPVirtualServer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
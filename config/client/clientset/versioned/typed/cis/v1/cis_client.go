@@ -26,9 +26,14 @@ import (
 
 type CisV1Interface interface {
 	RESTClient() rest.Interface
+	DataGroupsGetter
+	DeployConfigsGetter
 	ExternalDNSesGetter
+	HealthMonitorsGetter
 	IngressLinksGetter
 	PoliciesGetter
+	ServiceReferenceGrantsGetter
+	TLSCertificatesGetter
 	TLSProfilesGetter
 	TransportServersGetter
 	VirtualServersGetter
@@ -39,10 +44,22 @@ type CisV1Client struct {
 	restClient rest.Interface
 }
 
+func (c *CisV1Client) DataGroups(namespace string) DataGroupInterface {
+	return newDataGroups(c, namespace)
+}
+
+func (c *CisV1Client) DeployConfigs() DeployConfigInterface {
+	return newDeployConfigs(c)
+}
+
 func (c *CisV1Client) ExternalDNSes(namespace string) ExternalDNSInterface {
 	return newExternalDNSes(c, namespace)
 }
 
+func (c *CisV1Client) HealthMonitors(namespace string) HealthMonitorInterface {
+	return newHealthMonitors(c, namespace)
+}
+
 func (c *CisV1Client) IngressLinks(namespace string) IngressLinkInterface {
 	return newIngressLinks(c, namespace)
 }
@@ -51,6 +68,14 @@ func (c *CisV1Client) Policies(namespace string) PolicyInterface {
 	return newPolicies(c, namespace)
 }
 
+func (c *CisV1Client) ServiceReferenceGrants(namespace string) ServiceReferenceGrantInterface {
+	return newServiceReferenceGrants(c, namespace)
+}
+
+func (c *CisV1Client) TLSCertificates(namespace string) TLSCertificateInterface {
+	return newTLSCertificates(c, namespace)
+}
+
 func (c *CisV1Client) TLSProfiles(namespace string) TLSProfileInterface {
 	return newTLSProfiles(c, namespace)
 }
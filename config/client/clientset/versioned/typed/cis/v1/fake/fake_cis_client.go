@@ -28,10 +28,22 @@ type FakeCisV1 struct {
 	*testing.Fake
 }
 
+func (c *FakeCisV1) DataGroups(namespace string) v1.DataGroupInterface {
+	return &FakeDataGroups{c, namespace}
+}
+
+func (c *FakeCisV1) DeployConfigs() v1.DeployConfigInterface {
+	return &FakeDeployConfigs{c}
+}
+
 func (c *FakeCisV1) ExternalDNSes(namespace string) v1.ExternalDNSInterface {
 	return &FakeExternalDNSes{c, namespace}
 }
 
+func (c *FakeCisV1) HealthMonitors(namespace string) v1.HealthMonitorInterface {
+	return &FakeHealthMonitors{c, namespace}
+}
+
 func (c *FakeCisV1) IngressLinks(namespace string) v1.IngressLinkInterface {
 	return &FakeIngressLinks{c, namespace}
 }
@@ -40,6 +52,14 @@ func (c *FakeCisV1) Policies(namespace string) v1.PolicyInterface {
 	return &FakePolicies{c, namespace}
 }
 
+func (c *FakeCisV1) ServiceReferenceGrants(namespace string) v1.ServiceReferenceGrantInterface {
+	return &FakeServiceReferenceGrants{c, namespace}
+}
+
+func (c *FakeCisV1) TLSCertificates(namespace string) v1.TLSCertificateInterface {
+	return &FakeTLSCertificates{c, namespace}
+}
+
 func (c *FakeCisV1) TLSProfiles(namespace string) v1.TLSProfileInterface {
 	return &FakeTLSProfiles{c, namespace}
 }
@@ -18,12 +18,22 @@ limitations under the License.
 
 package v1
 
+type DataGroupExpansion interface{}
+
+type DeployConfigExpansion interface{}
+
 type ExternalDNSExpansion interface{}
 
+type HealthMonitorExpansion interface{}
+
 type IngressLinkExpansion interface{}
 
 type PolicyExpansion interface{}
 
+type ServiceReferenceGrantExpansion interface{}
+
+type TLSCertificateExpansion interface{}
+
 type TLSProfileExpansion interface{}
 
 type TransportServerExpansion interface{}
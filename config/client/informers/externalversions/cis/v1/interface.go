@@ -24,12 +24,22 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// DataGroups returns a DataGroupInformer.
+	DataGroups() DataGroupInformer
+	// DeployConfigs returns a DeployConfigInformer.
+	DeployConfigs() DeployConfigInformer
 	// ExternalDNSes returns a ExternalDNSInformer.
 	ExternalDNSes() ExternalDNSInformer
+	// HealthMonitors returns a HealthMonitorInformer.
+	HealthMonitors() HealthMonitorInformer
 	// IngressLinks returns a IngressLinkInformer.
 	IngressLinks() IngressLinkInformer
 	// Policies returns a PolicyInformer.
 	Policies() PolicyInformer
+	// ServiceReferenceGrants returns a ServiceReferenceGrantInformer.
+	ServiceReferenceGrants() ServiceReferenceGrantInformer
+	// TLSCertificates returns a TLSCertificateInformer.
+	TLSCertificates() TLSCertificateInformer
 	// TLSProfiles returns a TLSProfileInformer.
 	TLSProfiles() TLSProfileInformer
 	// TransportServers returns a TransportServerInformer.
@@ -49,11 +59,26 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// DataGroups returns a DataGroupInformer.
+func (v *version) DataGroups() DataGroupInformer {
+	return &dataGroupInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// DeployConfigs returns a DeployConfigInformer.
+func (v *version) DeployConfigs() DeployConfigInformer {
+	return &deployConfigInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // ExternalDNSes returns a ExternalDNSInformer.
 func (v *version) ExternalDNSes() ExternalDNSInformer {
 	return &externalDNSInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// HealthMonitors returns a HealthMonitorInformer.
+func (v *version) HealthMonitors() HealthMonitorInformer {
+	return &healthMonitorInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // IngressLinks returns a IngressLinkInformer.
 func (v *version) IngressLinks() IngressLinkInformer {
 	return &ingressLinkInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -64,6 +89,16 @@ func (v *version) Policies() PolicyInformer {
 	return &policyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// ServiceReferenceGrants returns a ServiceReferenceGrantInformer.
+func (v *version) ServiceReferenceGrants() ServiceReferenceGrantInformer {
+	return &serviceReferenceGrantInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// TLSCertificates returns a TLSCertificateInformer.
+func (v *version) TLSCertificates() TLSCertificateInformer {
+	return &tLSCertificateInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // TLSProfiles returns a TLSProfileInformer.
 func (v *version) TLSProfiles() TLSProfileInformer {
 	return &tLSProfileInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
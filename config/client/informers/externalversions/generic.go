@@ -53,12 +53,22 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=cis.f5.com, Version=v1
+	case v1.SchemeGroupVersion.WithResource("datagroups"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cis().V1().DataGroups().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("deployconfigs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cis().V1().DeployConfigs().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("externaldnses"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cis().V1().ExternalDNSes().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("healthmonitors"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cis().V1().HealthMonitors().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("ingresslinks"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cis().V1().IngressLinks().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("policies"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cis().V1().Policies().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("servicereferencegrants"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cis().V1().ServiceReferenceGrants().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("tlscertificates"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cis().V1().TLSCertificates().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("tlsprofiles"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cis().V1().TLSProfiles().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("transportservers"):
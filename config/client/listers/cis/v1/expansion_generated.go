@@ -18,6 +18,18 @@ limitations under the License.
 
 package v1
 
+// DataGroupListerExpansion allows custom methods to be added to
+// DataGroupLister.
+type DataGroupListerExpansion interface{}
+
+// DataGroupNamespaceListerExpansion allows custom methods to be added to
+// DataGroupNamespaceLister.
+type DataGroupNamespaceListerExpansion interface{}
+
+// DeployConfigListerExpansion allows custom methods to be added to
+// DeployConfigLister.
+type DeployConfigListerExpansion interface{}
+
 // ExternalDNSListerExpansion allows custom methods to be added to
 // ExternalDNSLister.
 type ExternalDNSListerExpansion interface{}
@@ -26,6 +38,14 @@ type ExternalDNSListerExpansion interface{}
 // ExternalDNSNamespaceLister.
 type ExternalDNSNamespaceListerExpansion interface{}
 
+// HealthMonitorListerExpansion allows custom methods to be added to
+// HealthMonitorLister.
+type HealthMonitorListerExpansion interface{}
+
+// HealthMonitorNamespaceListerExpansion allows custom methods to be added to
+// HealthMonitorNamespaceLister.
+type HealthMonitorNamespaceListerExpansion interface{}
+
 // IngressLinkListerExpansion allows custom methods to be added to
 // IngressLinkLister.
 type IngressLinkListerExpansion interface{}
@@ -42,6 +62,22 @@ type PolicyListerExpansion interface{}
 // PolicyNamespaceLister.
 type PolicyNamespaceListerExpansion interface{}
 
+// ServiceReferenceGrantListerExpansion allows custom methods to be added to
+// ServiceReferenceGrantLister.
+type ServiceReferenceGrantListerExpansion interface{}
+
+// ServiceReferenceGrantNamespaceListerExpansion allows custom methods to be added to
+// ServiceReferenceGrantNamespaceLister.
+type ServiceReferenceGrantNamespaceListerExpansion interface{}
+
+// TLSCertificateListerExpansion allows custom methods to be added to
+// TLSCertificateLister.
+type TLSCertificateListerExpansion interface{}
+
+// TLSCertificateNamespaceListerExpansion allows custom methods to be added to
+// TLSCertificateNamespaceLister.
+type TLSCertificateNamespaceListerExpansion interface{}
+
 // TLSProfileListerExpansion allows custom methods to be added to
 // TLSProfileLister.
 type TLSProfileListerExpansion interface{}
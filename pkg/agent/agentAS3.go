@@ -52,6 +52,7 @@ func (ag *agentAS3) Clean(partition string) error {
 }
 
 func (ag *agentAS3) DeInit() error {
+	ag.PostManager.Stop()
 	close(ag.RspChan)
 	close(ag.ReqChan)
 	return nil
@@ -346,6 +346,12 @@ func createServiceDecl(cfg *ResourceConfig, sharedApp as3Application, partition
 		}
 		svc.PolicyEndpoint = peps
 	}
+	if cfg.MetaData.ResourceType == "configmap" {
+		// Pool-only ConfigMap without the virtual address creates only the pool
+		if cfg.Virtual.VirtualAddress == nil || cfg.Virtual.VirtualAddress.BindAddr == "" {
+			return
+		}
+	}
 	// Add the default pool if present
 	if cfg.Virtual.PoolName != "" {
 		ps := strings.Split(cfg.Virtual.PoolName, "/")
@@ -374,6 +380,9 @@ func createServiceDecl(cfg *ResourceConfig, sharedApp as3Application, partition
 	}
 
 	svc.SNAT = "auto"
+	if cfg.MetaData.ResourceType == "configmap" {
+		updateServiceForConfigMap(cfg, svc)
+	}
 	for _, v := range cfg.Virtual.IRules {
 		splits := strings.Split(v, "/")
 		iRuleName := splits[len(splits)-1]
@@ -389,6 +398,28 @@ func createServiceDecl(cfg *ResourceConfig, sharedApp as3Application, partition
 	sharedApp[as3FormattedString(cfg.Virtual.Name, cfg.MetaData.ResourceType)] = svc
 }
 
+// updateServiceForConfigMap updates the AS3 Service with the mode and SNAT pool of the virtual of the
+// legacy f5-schema ConfigMap
+func updateServiceForConfigMap(cfg *ResourceConfig, svc *as3Service) {
+	httpMode := false
+	for _, prof := range cfg.Virtual.Profiles {
+		if prof.Name == "http" && prof.Context == CustomProfileAll {
+			httpMode = true
+		}
+	}
+	switch {
+	case cfg.Virtual.IpProtocol == "udp":
+		svc.Class = "Service_UDP"
+	case !httpMode:
+		svc.Class = "Service_TCP"
+	}
+	if cfg.Virtual.SourceAddrTranslation.Type == SnatSourceAddrTranslation {
+		svc.SNAT = as3ResourcePointer{
+			BigIP: cfg.Virtual.SourceAddrTranslation.Pool,
+		}
+	}
+}
+
 // Create AS3 Rule Condition for Route
 func createAS3RuleCondition(rl *Rule, rulesData *as3Rule, port int) {
 	for _, c := range rl.Conditions {
@@ -76,4 +76,64 @@ var _ = Describe("AS3Common Tests", func() {
 		}
 		Expect(hm).To(Equal(expectedHM), "Incorrect Health monitor created for AS3 declaration.")
 	})
+
+	It("Creates AS3 service declaration for legacy ConfigMap", func() {
+		cfg := &ResourceConfig{
+			MetaData: MetaData{
+				ResourceType: "configmap",
+			},
+		}
+		cfg.Virtual.Name = "default_foomap"
+		cfg.Virtual.PoolName = "/test/default_foomap_foo"
+		cfg.Virtual.SourceAddrTranslation = SetSourceAddrTranslation("")
+		cfg.Virtual.SetVirtualAddress("10.1.1.1", 80, true)
+		SetProfilesForMode("tcp", cfg)
+		sharedApp := as3Application{}
+		createServiceDecl(cfg, sharedApp, "test")
+		svc, ok := sharedApp["default_foomap"].(*as3Service)
+		Expect(ok).To(BeTrue(), "Service not created for AS3 declaration.")
+		Expect(svc.Class).To(Equal("Service_TCP"))
+		Expect(svc.Layer4).To(Equal("tcp"))
+		Expect(svc.VirtualAddresses).To(Equal([]string{"10.1.1.1"}))
+		Expect(svc.VirtualPort).To(Equal(80))
+		Expect(svc.Pool).To(Equal("/test/Shared/default_foomap_foo"))
+		Expect(svc.SNAT).To(Equal("auto"))
+
+		// BIG-IP clientssl profile of the tcp mode
+		cfg.Virtual.AddOrUpdateProfile(ProfileRef{Name: "clientssl", Partition: "Common", Context: CustomProfileClient})
+		processConfigMapTLSProfilesForAS3(&cfg.Virtual, svc)
+		Expect(svc.Class).To(Equal("Service_TCP"))
+		Expect(svc.Redirect80).To(BeNil())
+		Expect(svc.ServerTLS).To(Equal([]as3ResourcePointer{{BigIP: "/Common/clientssl"}}))
+
+		// http mode with the SNAT pool
+		cfg = &ResourceConfig{
+			MetaData: MetaData{
+				ResourceType: "configmap",
+			},
+		}
+		cfg.Virtual.Name = "default_foomap"
+		cfg.Virtual.SourceAddrTranslation = SetSourceAddrTranslation("/Common/snatpool")
+		cfg.Virtual.SetVirtualAddress("10.1.1.1", 80, true)
+		SetProfilesForMode("http", cfg)
+		sharedApp = as3Application{}
+		createServiceDecl(cfg, sharedApp, "test")
+		svc = sharedApp["default_foomap"].(*as3Service)
+		Expect(svc.Class).To(Equal("Service_HTTP"))
+		Expect(svc.SNAT).To(Equal(as3ResourcePointer{BigIP: "/Common/snatpool"}))
+
+		// udp mode
+		cfg.Virtual.Profiles = nil
+		SetProfilesForMode("udp", cfg)
+		createServiceDecl(cfg, sharedApp, "test")
+		Expect(sharedApp["default_foomap"].(*as3Service).Class).To(Equal("Service_UDP"))
+		Expect(sharedApp["default_foomap"].(*as3Service).Layer4).To(Equal("udp"))
+
+		// Pool-only ConfigMap
+		cfg.Virtual.Name = "default_poolonly"
+		cfg.Virtual.SetVirtualAddress("", 0, true)
+		createServiceDecl(cfg, sharedApp, "test")
+		_, ok = sharedApp["default_poolonly"]
+		Expect(ok).To(BeFalse(), "Service should not be created for the pool-only ConfigMap.")
+	})
 })
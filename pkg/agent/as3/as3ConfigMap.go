@@ -2,6 +2,9 @@ package as3
 
 import (
 	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
 
 	. "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/resource"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
@@ -20,11 +23,11 @@ const (
 
 func (am *AS3Manager) prepareResourceAS3ConfigMaps() (
 	[]*AS3ConfigMap,
-	string,
+	[]*OverrideAS3ConfigMap,
 	error,
 ) {
 	var as3Cfgmaps []*AS3ConfigMap
-	var overriderAS3CfgmapData string
+	var overrideCfgmaps []*OverrideAS3ConfigMap
 
 	// Reset AS3 persist and logLevel values
 	am.as3DeclarationPersistence = nil
@@ -68,7 +71,7 @@ func (am *AS3Manager) prepareResourceAS3ConfigMaps() (
 			tenantMap, endPoints, err := am.processCfgMap(rscCfgMap)
 			// Skip processing further if error encountered while processing configMap
 			if err != nil {
-				return nil, "", err
+				return nil, nil, err
 			}
 			if tenantMap == nil {
 				continue
@@ -80,13 +83,12 @@ func (am *AS3Manager) prepareResourceAS3ConfigMaps() (
 
 		case OverrideAS3Label:
 			if rscCfgMap.Operation == OprTypeDelete {
-				// In the event of deletion config of overriderAS3Cfgmap stays empty
-				// So that nothing gets overridden
-				log.Debugf("Setting overriderAS3CfgmapData to Empty")
-				overriderAS3CfgmapData = ""
-			} else {
-				overriderAS3CfgmapData = rscCfgMap.Data
+				// In the event of deletion config of overriderAS3Cfgmap is skipped
+				// So that nothing gets overridden by this configmap
+				log.Debugf("Skipping deleted override configmap %v/%v", rscCfgMap.Namespace, rscCfgMap.Name)
+				continue
 			}
+			overrideCfgmaps = append(overrideCfgmaps, newOverrideAS3ConfigMap(rscCfgMap))
 		case StagingAS3Label:
 			tenants := getTenants(as3Declaration(rscCfgMap.Data), true)
 			cfgmap := &AS3ConfigMap{
@@ -98,14 +100,48 @@ func (am *AS3Manager) prepareResourceAS3ConfigMaps() (
 			tenantMap, endPoints, err := am.processCfgMap(rscCfgMap)
 			// Skip processing further if error encountered while processing configMap
 			if err != nil {
-				return nil, "", err
+				return nil, nil, err
 			}
 			cfgmap.config = tenantMap
 			cfgmap.endPoints = endPoints
 			as3Cfgmaps = append(as3Cfgmaps, cfgmap)
 		}
 	}
-	return as3Cfgmaps, overriderAS3CfgmapData, nil
+	// Apply the override configmaps in ascending order of priority,
+	// configmaps with the same priority are ordered by namespace and name
+	sort.SliceStable(overrideCfgmaps, func(i, j int) bool {
+		if overrideCfgmaps[i].Priority != overrideCfgmaps[j].Priority {
+			return overrideCfgmaps[i].Priority < overrideCfgmaps[j].Priority
+		}
+		return overrideCfgmaps[i].Namespace+"/"+overrideCfgmaps[i].Name <
+			overrideCfgmaps[j].Namespace+"/"+overrideCfgmaps[j].Name
+	})
+	return as3Cfgmaps, overrideCfgmaps, nil
+}
+
+// newOverrideAS3ConfigMap prepares the override configmap with the priority and tenants from its annotations
+func newOverrideAS3ConfigMap(cfgmap *AgentCfgMap) *OverrideAS3ConfigMap {
+	overrideCfgmap := &OverrideAS3ConfigMap{
+		Name:      cfgmap.Name,
+		Namespace: cfgmap.Namespace,
+		Data:      cfgmap.Data,
+	}
+	if priority, ok := cfgmap.Annotation[F5OverrideAS3PriorityAnnotation]; ok {
+		if val, err := strconv.Atoi(priority); err == nil {
+			overrideCfgmap.Priority = val
+		} else {
+			log.Errorf("[AS3] Invalid %v annotation: %v in override configmap %v/%v, using default priority",
+				F5OverrideAS3PriorityAnnotation, priority, cfgmap.Namespace, cfgmap.Name)
+		}
+	}
+	if tenants, ok := cfgmap.Annotation[F5OverrideAS3TenantsAnnotation]; ok {
+		for _, tenant := range strings.Split(tenants, ",") {
+			if tenant = strings.TrimSpace(tenant); tenant != "" {
+				overrideCfgmap.Tenants = append(overrideCfgmap.Tenants, tenant)
+			}
+		}
+	}
+	return overrideCfgmap
 }
 
 func (am *AS3Manager) isValidConfigmap(cfgmap *AgentCfgMap) (string, bool) {
@@ -117,8 +153,8 @@ func (am *AS3Manager) isValidConfigmap(cfgmap *AgentCfgMap) (string, bool) {
 		}
 		if val, ok := cfgmap.Label[OverrideAS3Label]; ok && val == TrueLabel {
 			overriderName := cfgmap.Namespace + "/" + cfgmap.Name
-			if len(am.OverriderCfgMapName) > 0 && (am.OverriderCfgMapName != overriderName) {
-				log.Errorf("[AS3] Invalid overrider cfgMap: %v", am.OverriderCfgMapName)
+			if len(am.OverriderCfgMapName) > 0 && !am.isOverriderCfgMap(overriderName) {
+				log.Errorf("[AS3] Invalid overrider cfgMap: %v", overriderName)
 				return "", false
 			}
 			return OverrideAS3Label, true
@@ -131,6 +167,17 @@ func (am *AS3Manager) isValidConfigmap(cfgmap *AgentCfgMap) (string, bool) {
 	return "", false
 }
 
+// isOverriderCfgMap checks whether the configmap is one of the override configmaps
+// provided with --override-as3-declaration
+func (am *AS3Manager) isOverriderCfgMap(name string) bool {
+	for _, overrider := range strings.Split(am.OverriderCfgMapName, ",") {
+		if strings.TrimSpace(overrider) == name {
+			return true
+		}
+	}
+	return false
+}
+
 // processCfgMap processes a configmap and feeds pool Members
 // and return a map of tenants and all endpoints
 func (am *AS3Manager) processCfgMap(rscCfgMap *AgentCfgMap) (
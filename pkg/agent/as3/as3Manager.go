@@ -24,6 +24,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/httpclient"
 	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/writer"
 
 	. "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/resource"
@@ -70,11 +71,11 @@ var baseAS3Config = `{
 
 // AS3Config consists of all the AS3 related configurations
 type AS3Config struct {
-	resourceConfig        as3ADC
-	configmaps            []*AS3ConfigMap
-	overrideConfigmapData string
-	tenantMap             map[string]interface{}
-	unifiedDeclaration    as3Declaration
+	resourceConfig     as3ADC
+	configmaps         []*AS3ConfigMap
+	overrideConfigmaps []*OverrideAS3ConfigMap
+	tenantMap          map[string]interface{}
+	unifiedDeclaration as3Declaration
 }
 
 // ActiveAS3ConfigMap user defined ConfigMap for global availability.
@@ -86,6 +87,15 @@ type AS3ConfigMap struct {
 	Validated bool     // Json Schema validated ok
 }
 
+// OverrideAS3ConfigMap user defined ConfigMap to override the AS3 declaration.
+type OverrideAS3ConfigMap struct {
+	Name      string   // Override ConfigMap name
+	Namespace string   // Override ConfigMap namespace
+	Data      string   // Override AS3 template data
+	Priority  int      // ConfigMaps are applied in ascending order of priority, highest priority takes precedence
+	Tenants   []string // Tenants allowed to be overridden, all the tenants if empty
+}
+
 // AS3Manager holds all the AS3 orchestration specific config
 type AS3Manager struct {
 	as3Validation             bool
@@ -158,6 +168,7 @@ type Params struct {
 	DefaultRouteDomain        int
 	PoolMemberType            string
 	HTTPClientMetrics         bool
+	ClientParams              httpclient.Params
 }
 
 type failureContext struct {
@@ -200,6 +211,7 @@ func NewAS3Manager(params *Params) *AS3Manager {
 			LogAS3Response:    params.LogAS3Response,
 			LogAS3Request:     params.LogAS3Request,
 			HTTPClientMetrics: params.HTTPClientMetrics,
+			ClientParams:      params.ClientParams,
 		}),
 	}
 
@@ -235,7 +247,7 @@ func (am *AS3Manager) postAS3Declaration(rsReq ResourceRequest) (bool, string, e
 
 	var err error
 	// Process all Configmaps (including overrideAS3)
-	as3Config.configmaps, as3Config.overrideConfigmapData, err = am.prepareResourceAS3ConfigMaps()
+	as3Config.configmaps, as3Config.overrideConfigmaps, err = am.prepareResourceAS3ConfigMaps()
 	// Skip posting AS3 declaration if error encountered while processing configMap to avoid possible wrong declaration
 	// getting posted as the pool members may be empty if error is encountered while connecting with api server
 	if err != nil {
@@ -395,7 +407,7 @@ func (cfg *AS3Config) updateConfig(newAS3Cfg AS3Config) {
 	cfg.resourceConfig = newAS3Cfg.resourceConfig
 	cfg.unifiedDeclaration = newAS3Cfg.unifiedDeclaration
 	cfg.configmaps = newAS3Cfg.configmaps
-	cfg.overrideConfigmapData = newAS3Cfg.overrideConfigmapData
+	cfg.overrideConfigmaps = newAS3Cfg.overrideConfigmaps
 }
 
 func (cfg *AS3Config) tenantIsValid(tenant string) bool {
@@ -443,19 +455,19 @@ func (am *AS3Manager) getUnifiedDeclaration(cfg *AS3Config) as3Declaration {
 		log.Debugf("[AS3] Unified declaration: %v\n", err)
 	}
 
-	if cfg.overrideConfigmapData == "" {
-		cfg.unifiedDeclaration = as3Declaration(unifiedDecl)
-		return as3Declaration(unifiedDecl)
-	}
-
-	overriddenUnifiedDecl := ValidateAndOverrideAS3JsonData(
-		cfg.overrideConfigmapData,
-		string(unifiedDecl),
-	)
-	if overriddenUnifiedDecl == "" {
-		log.Debug("[AS3] Failed to override AS3 Declaration")
-		cfg.unifiedDeclaration = as3Declaration(unifiedDecl)
-		return as3Declaration(unifiedDecl)
+	overriddenUnifiedDecl := string(unifiedDecl)
+	// Override configmaps are sorted by priority, later configmaps take precedence
+	for _, cm := range cfg.overrideConfigmaps {
+		overrideData := scopeOverrideAS3Tenants(cm.Data, cm.Tenants)
+		if overrideData == "" {
+			continue
+		}
+		decl := ValidateAndOverrideAS3JsonData(overrideData, overriddenUnifiedDecl)
+		if decl == "" {
+			log.Debugf("[AS3] Failed to override AS3 Declaration with ConfigMap %v/%v", cm.Namespace, cm.Name)
+			continue
+		}
+		overriddenUnifiedDecl = decl
 	}
 	cfg.unifiedDeclaration = as3Declaration(overriddenUnifiedDecl)
 	return as3Declaration(overriddenUnifiedDecl)
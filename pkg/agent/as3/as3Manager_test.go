@@ -250,7 +250,7 @@ var _ = Describe("AS3Manager Tests", func() {
 				},
 			)
 			as3config := &AS3Config{}
-			_, as3config.overrideConfigmapData, _ = mockMgr.prepareResourceAS3ConfigMaps()
+			_, as3config.overrideConfigmaps, _ = mockMgr.prepareResourceAS3ConfigMaps()
 
 			routeCfg := readConfigFile(configPath + "as3_route.json")
 			err := json.Unmarshal([]byte(routeCfg), &routeAdc)
@@ -260,6 +260,71 @@ var _ = Describe("AS3Manager Tests", func() {
 			Expect(string(result)).To(MatchJSON(unifiedConfig), "Failed to Create JSON with correct configuration")
 		})
 
+		It("Override CIS generated config with multiple Override Configmaps", func() {
+			var routeAdc map[string]interface{}
+			newOverrideCfgMap := func(name, data string, annotation map[string]string) *AgentCfgMap {
+				return &AgentCfgMap{
+					GetEndpoints: mockGetEndPoints,
+					Name:         name,
+					Namespace:    "default",
+					Data:         data,
+					Label: map[string]string{
+						OverrideAS3Label: TrueLabel,
+						F5TypeLabel:      VSLabel,
+					},
+					Annotation: annotation,
+				}
+			}
+			virtualAddress := `{"declaration": {"openshift": {"Shared": {"https_ose_vserver": {"virtualAddresses": ["%s"]}}}}}`
+			mockMgr.ResourceRequest.AgentCfgmaps = []*AgentCfgMap{
+				newOverrideCfgMap("platform", fmt.Sprintf(virtualAddress, "10.1.1.1"),
+					map[string]string{F5OverrideAS3PriorityAnnotation: "20"}),
+				newOverrideCfgMap("app", fmt.Sprintf(virtualAddress, "10.2.2.2"),
+					map[string]string{F5OverrideAS3PriorityAnnotation: "10"}),
+				newOverrideCfgMap("other", fmt.Sprintf(virtualAddress, "10.3.3.3"),
+					map[string]string{F5OverrideAS3PriorityAnnotation: "30", F5OverrideAS3TenantsAnnotation: "other"}),
+				newOverrideCfgMap("patch", `[{"op": "add", "path": "/declaration/openshift/Shared/https_ose_vserver/connectionLimit", "value": 100}]`,
+					map[string]string{F5OverrideAS3TenantsAnnotation: "openshift, other"}),
+			}
+			as3config := &AS3Config{}
+			_, as3config.overrideConfigmaps, _ = mockMgr.prepareResourceAS3ConfigMaps()
+			Expect(len(as3config.overrideConfigmaps)).To(Equal(4))
+			Expect(as3config.overrideConfigmaps[0].Name).To(Equal("patch"), "Override configmaps not sorted by priority")
+			Expect(as3config.overrideConfigmaps[0].Tenants).To(Equal([]string{"openshift", "other"}))
+			Expect(as3config.overrideConfigmaps[3].Name).To(Equal("other"), "Override configmaps not sorted by priority")
+
+			routeCfg := readConfigFile(configPath + "as3_route.json")
+			err := json.Unmarshal([]byte(routeCfg), &routeAdc)
+			Expect(err).To(BeNil(), "Route Config should be json")
+			as3config.resourceConfig = routeAdc
+			result := mockMgr.getUnifiedDeclaration(as3config)
+
+			var as3Obj map[string]interface{}
+			Expect(json.Unmarshal([]byte(result), &as3Obj)).To(BeNil())
+			vs := as3Obj["declaration"].(map[string]interface{})["openshift"].(map[string]interface{})["Shared"].(map[string]interface{})["https_ose_vserver"].(map[string]interface{})
+			// Highest priority configmap takes precedence, configmap not allowed for the tenant is discarded
+			Expect(vs["virtualAddresses"]).To(Equal([]interface{}{"10.1.1.1"}))
+			Expect(vs["connectionLimit"]).To(BeEquivalentTo(100))
+		})
+
+		It("Validate multiple Override configmaps", func() {
+			mockMgr.OverriderCfgMapName = "default/ovCfgmap, kube-system/ovCfgmap"
+			cfgmap := &AgentCfgMap{
+				Label: map[string]string{
+					OverrideAS3Label: TrueLabel,
+					F5TypeLabel:      VSLabel,
+				},
+				Namespace: "kube-system",
+				Name:      "ovCfgmap",
+			}
+			label, valid := mockMgr.isValidConfigmap(cfgmap)
+			Expect(label).To(Equal(OverrideAS3Label), "Wrong Label")
+			Expect(valid).To(BeTrue())
+			cfgmap.Namespace = "test"
+			_, valid = mockMgr.isValidConfigmap(cfgmap)
+			Expect(valid).To(BeFalse())
+		})
+
 		It("Validate Override configmap with wrong name", func() {
 			mockMgr.OverriderCfgMapName = "default/ovCfgmap"
 			cfgmap := &AgentCfgMap{
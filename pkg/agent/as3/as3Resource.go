@@ -80,6 +80,8 @@ func (am *AS3Manager) processProfilesForAS3(sharedApp as3Application, partition
 					processRouteTLSProfilesForAS3(&cfg.MetaData, svc)
 				case ResourceTypeIngress:
 					processIngressTLSProfilesForAS3(&cfg.Virtual, svc)
+				case "configmap":
+					processConfigMapTLSProfilesForAS3(&cfg.Virtual, svc)
 				default:
 					log.Warningf("Unsupported resource type: %v", cfg.MetaData.ResourceType)
 				}
@@ -118,6 +120,17 @@ func processIngressTLSProfilesForAS3(virtual *Virtual, svc *as3Service) {
 	}
 }
 
+// processConfigMapTLSProfilesForAS3 sets the BIG-IP SSL profiles of the legacy ConfigMap virtual, virtual of
+// the tcp mode remains Service_TCP to terminate the TLS without the HTTP profile
+func processConfigMapTLSProfilesForAS3(virtual *Virtual, svc *as3Service) {
+	class := svc.Class
+	processIngressTLSProfilesForAS3(virtual, svc)
+	if class != "Service_HTTP" {
+		svc.Class = class
+		svc.Redirect80 = nil
+	}
+}
+
 func processRouteTLSProfilesForAS3(metadata *MetaData, svc *as3Service) {
 	var serverTLS []as3ResourcePointer
 	existingProfile := map[string]struct{}{}
@@ -148,7 +148,7 @@ type (
 		Class                  string            `json:"class,omitempty"`
 		VirtualAddresses       []string          `json:"virtualAddresses,omitempty"`
 		VirtualPort            int               `json:"virtualPort,omitempty"`
-		SNAT                   string            `json:"snat,omitempty"`
+		SNAT                   as3MultiTypeParam `json:"snat,omitempty"`
 		PolicyEndpoint         as3MultiTypeParam `json:"policyEndpoint,omitempty"`
 		ClientTLS              as3MultiTypeParam `json:"clientTLS,omitempty"`
 		ServerTLS              as3MultiTypeParam `json:"serverTLS,omitempty"`
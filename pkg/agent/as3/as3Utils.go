@@ -25,6 +25,7 @@ import (
 
 	. "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/resource"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+	jsonpatch "github.com/evanphx/json-patch"
 )
 
 func ValidateJSONStringAndFetchObject(jsonData string, jsonObj *map[string]interface{}) error {
@@ -49,6 +50,11 @@ func ValidateJSONStringAndFetchObject(jsonData string, jsonObj *map[string]inter
 
 func ValidateAndOverrideAS3JsonData(srcJsonData string, dstJsonData string) string {
 
+	// Override configmap with a list of JSON Patch operations
+	if strings.HasPrefix(strings.TrimSpace(srcJsonData), "[") {
+		return PatchAS3JsonData(srcJsonData, dstJsonData)
+	}
+
 	var srcJsonObj map[string]interface{}
 	if err := ValidateJSONStringAndFetchObject(srcJsonData, &srcJsonObj); err != nil {
 		log.Errorf("[AS3] JSON Validation error on source JSON string !!!")
@@ -89,6 +95,148 @@ func ValidateAndOverrideAS3JsonData(srcJsonData string, dstJsonData string) stri
 	return string(mergedJsonData)
 }
 
+// PatchAS3JsonData applies the JSON Patch (RFC 6902) operations of the override configmap
+// to the declaration. Operations are allowed only on the applications of tenants available in CIS.
+func PatchAS3JsonData(srcJsonData string, dstJsonData string) string {
+
+	var operations []map[string]interface{}
+	if err := json.Unmarshal([]byte(srcJsonData), &operations); err != nil {
+		log.Errorf("[AS3] JSON Validation error on JSON Patch operations !!!: %v", err)
+		return ""
+	}
+
+	var dstJsonObj map[string]interface{}
+	if err := ValidateJSONStringAndFetchObject(dstJsonData, &dstJsonObj); err != nil {
+		log.Errorf("[AS3] JSON Validation error on destination JSON string !!!")
+		return ""
+	}
+	dstDeclr, ok := dstJsonObj["declaration"].(map[string]interface{})
+	if !ok {
+		log.Errorf("[AS3] Destination JSON is not a valid AS3 declaration !!!")
+		return ""
+	}
+
+	// Discard the operations on paths outside the applications of tenants in CIS
+	appOperations := []map[string]interface{}{}
+	for _, op := range operations {
+		if !isAS3ApplicationPath(op["path"], dstDeclr) ||
+			(op["from"] != nil && !isAS3ApplicationPath(op["from"], dstDeclr)) {
+			log.Errorf("[AS3] Discarding JSON Patch operation %v, path is not of an application in CIS tenants", op)
+			continue
+		}
+		appOperations = append(appOperations, op)
+	}
+
+	patchData, _ := json.Marshal(appOperations)
+	patch, err := jsonpatch.DecodePatch(patchData)
+	if err != nil {
+		log.Errorf("[AS3] Invalid JSON Patch operations !!!: %v", err)
+		return ""
+	}
+	patchedJsonData, err := patch.Apply([]byte(dstJsonData))
+	if err != nil {
+		log.Errorf("[AS3] CIS failed to apply JSON Patch operations !!!: %v", err)
+		return ""
+	}
+	return string(patchedJsonData)
+}
+
+// isAS3ApplicationPath checks whether the JSON pointer refers an application of a tenant in the declaration
+// or a property within the application, for example /declaration/<tenant>/<application>/<virtual>/idleTimeout
+func isAS3ApplicationPath(path interface{}, declaration map[string]interface{}) bool {
+	tokens := getAS3PathTokens(path)
+	if len(tokens) < 2 {
+		return false
+	}
+	tnt, ok := declaration[tokens[0]].(map[string]interface{})
+	if !ok || tnt["class"] != "Tenant" {
+		return false
+	}
+	_, ok = tnt[tokens[1]].(map[string]interface{})
+	return ok
+}
+
+// getAS3PathTokens returns the unescaped tokens of the JSON pointer within the declaration,
+// for example [<tenant>, <application>, <virtual>] for /declaration/<tenant>/<application>/<virtual>
+func getAS3PathTokens(path interface{}) []string {
+	pointer, ok := path.(string)
+	if !ok {
+		return nil
+	}
+	tokens := strings.Split(pointer, "/")
+	if len(tokens) < 3 || tokens[0] != "" || tokens[1] != "declaration" {
+		return nil
+	}
+	unescape := strings.NewReplacer("~1", "/", "~0", "~")
+	for i := range tokens {
+		tokens[i] = unescape.Replace(tokens[i])
+	}
+	return tokens[2:]
+}
+
+// scopeOverrideAS3Tenants discards the JSON Patch operations and the tenants of the override
+// declaration for the tenants not allowed to be overridden by the configmap
+func scopeOverrideAS3Tenants(data string, tenants []string) string {
+	if len(tenants) == 0 {
+		return data
+	}
+	allowed := func(tenant string) bool {
+		for _, tnt := range tenants {
+			if tnt == tenant {
+				return true
+			}
+		}
+		return false
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(data), "[") {
+		var operations []map[string]interface{}
+		if err := json.Unmarshal([]byte(data), &operations); err != nil {
+			log.Errorf("[AS3] JSON Validation error on JSON Patch operations !!!: %v", err)
+			return ""
+		}
+		tenantOperations := []map[string]interface{}{}
+		for _, op := range operations {
+			tokens := getAS3PathTokens(op["path"])
+			if len(tokens) == 0 || !allowed(tokens[0]) {
+				log.Errorf("[AS3] Discarding JSON Patch operation %v, tenant is not allowed to be overridden", op)
+				continue
+			}
+			if op["from"] != nil {
+				if tokens = getAS3PathTokens(op["from"]); len(tokens) == 0 || !allowed(tokens[0]) {
+					log.Errorf("[AS3] Discarding JSON Patch operation %v, tenant is not allowed to be overridden", op)
+					continue
+				}
+			}
+			tenantOperations = append(tenantOperations, op)
+		}
+		scopedData, _ := json.Marshal(tenantOperations)
+		return string(scopedData)
+	}
+
+	var dataObj map[string]interface{}
+	if err := ValidateJSONStringAndFetchObject(data, &dataObj); err != nil {
+		log.Errorf("[AS3] JSON Validation error on override JSON string !!!")
+		return ""
+	}
+	if declr, ok := dataObj["declaration"].(map[string]interface{}); ok {
+		for key, val := range declr {
+			tnt, ok := val.(map[string]interface{})
+			if !ok || allowed(key) {
+				continue
+			}
+			// Skip the non tenant objects of ADC like controls
+			if class, found := tnt["class"]; found && class != "Tenant" {
+				continue
+			}
+			log.Errorf("[AS3] Discarding tenant %v, tenant is not allowed to be overridden", key)
+			delete(declr, key)
+		}
+	}
+	scopedData, _ := json.Marshal(dataObj)
+	return string(scopedData)
+}
+
 func mergeRecursive(srcJsonObj, dstJsonObj interface{}) interface{} {
 	//In this algorithm, preferring srcJsonObj overriding on dstJsonObj
 	switch srcJsonObj := srcJsonObj.(type) {
@@ -104,10 +252,16 @@ func mergeRecursive(srcJsonObj, dstJsonObj interface{}) interface{} {
 		// in this case the keys from both objects are included and
 		// only their values are merged recursively
 		for dstKey, dstVal := range dstJsonObj {
-			if srcKey, ok := srcJsonObj[dstKey]; ok {
-				srcJsonObj[dstKey] = mergeRecursive(srcKey, dstVal)
-			} else {
+			if srcKey, ok := srcJsonObj[dstKey]; !ok {
 				srcJsonObj[dstKey] = dstVal
+			} else if srcKey != nil {
+				srcJsonObj[dstKey] = mergeRecursive(srcKey, dstVal)
+			}
+		}
+		// As in JSON merge patch (RFC 7386), null removes the member
+		for srcKey, srcVal := range srcJsonObj {
+			if srcVal == nil {
+				delete(srcJsonObj, srcKey)
 			}
 		}
 	case nil:
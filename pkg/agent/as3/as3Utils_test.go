@@ -1,6 +1,7 @@
 package as3
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"sort"
 
@@ -43,6 +44,41 @@ var _ = Describe("Override Simple Config Map", func() {
 		overrideData := ValidateAndOverrideAS3JsonData(srcCfgMapData, dstCfgMapData)
 		Expect(overrideData == "").To(Equal(true))
 	})
+
+	It("Override with JSON merge patch", func() {
+		srcCfgMapData := `{"declaration": {"example_simple_http_application": {"example_simple_http_application_01": {"serviceMain": {"pool": null, "connectionLimit": 1000}}}}}`
+		dstCfgMapData := readConfigFile(configPath + "as3config_simple_cfgmap_resource.json")
+
+		overrideData := ValidateAndOverrideAS3JsonData(srcCfgMapData, dstCfgMapData)
+		var as3Obj map[string]interface{}
+		Expect(json.Unmarshal([]byte(overrideData), &as3Obj)).To(BeNil())
+		app := as3Obj["declaration"].(map[string]interface{})["example_simple_http_application"].(map[string]interface{})["example_simple_http_application_01"].(map[string]interface{})
+		svc := app["serviceMain"].(map[string]interface{})
+		Expect(svc).NotTo(HaveKey("pool"), "null did not remove the member")
+		Expect(svc["connectionLimit"]).To(BeEquivalentTo(1000))
+		Expect(svc["class"]).To(Equal("Service_HTTP"))
+	})
+
+	It("Override with JSON Patch operations", func() {
+		srcCfgMapData := readConfigFile(configPath + "as3config_override_json_patch.json")
+		dstCfgMapData := readConfigFile(configPath + "as3config_simple_cfgmap_resource.json")
+
+		overrideData := ValidateAndOverrideAS3JsonData(srcCfgMapData, dstCfgMapData)
+		var as3Obj map[string]interface{}
+		Expect(json.Unmarshal([]byte(overrideData), &as3Obj)).To(BeNil())
+		decl := as3Obj["declaration"].(map[string]interface{})
+		// Operations outside the applications of the tenants are discarded
+		Expect(decl).To(HaveKey("id"))
+		Expect(decl).NotTo(HaveKey("openshift"))
+		app := decl["example_simple_http_application"].(map[string]interface{})["example_simple_http_application_01"].(map[string]interface{})
+		svc := app["serviceMain"].(map[string]interface{})
+		Expect(svc["virtualAddresses"]).To(Equal([]interface{}{"172.16.3.111"}))
+		Expect(svc["connectionLimit"]).To(BeEquivalentTo(1000))
+
+		// Failed operation
+		overrideData = ValidateAndOverrideAS3JsonData(`[{"op": "remove", "path": "/declaration/example_simple_http_application/example_simple_http_application_01/serviceMain/connectionLimit"}]`, dstCfgMapData)
+		Expect(overrideData).To(BeEmpty())
+	})
 })
 
 var _ = Describe("JSON comparision of AS3 declaration", func() {
@@ -18,8 +18,7 @@ package as3
 
 import (
 	"bytes"
-	"crypto/tls"
-	"crypto/x509"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -27,10 +26,9 @@ import (
 	"strings"
 	"time"
 
-	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/prometheus"
+	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/httpclient"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
 	routeclient "github.com/openshift/client-go/route/clientset/versioned/typed/route/v1"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 const (
@@ -53,6 +51,9 @@ type PostManager struct {
 	postChan   chan config
 	HttpClient *http.Client
 	activeCfg  config
+	// context of the REST calls, cancelled when the agent is stopped
+	ctx    context.Context
+	cancel context.CancelFunc
 	PostParams
 }
 
@@ -68,6 +69,8 @@ type PostParams struct {
 	LogAS3Request     bool
 	RouteClientV1     routeclient.RouteV1Interface
 	HTTPClientMetrics bool
+	// Timeouts, keep-alive and proxy of the http client of the REST calls
+	ClientParams httpclient.Params
 }
 
 type config struct {
@@ -80,50 +83,24 @@ func NewPostManager(params PostParams) *PostManager {
 		postChan:   make(chan config, 1),
 		PostParams: params,
 	}
+	pm.ctx, pm.cancel = context.WithCancel(context.Background())
 	pm.setupBIGIPRESTClient()
 
 	return pm
 }
 
 func (postMgr *PostManager) setupBIGIPRESTClient() {
-	// Get the SystemCertPool, continue with an empty pool on error
-	rootCAs, _ := x509.SystemCertPool()
-	if rootCAs == nil {
-		rootCAs = x509.NewCertPool()
-	}
-	// TODO: Make sure appMgr sets certificates in bigipInfo
-	certs := []byte(postMgr.TrustedCerts)
-
-	// Append our certs to the system pool
-	if ok := rootCAs.AppendCertsFromPEM(certs); !ok {
-		log.Debug("[AS3] No certs appended, using only system certs")
-	}
-
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: postMgr.SSLInsecure,
-			RootCAs:            rootCAs,
-		},
-	}
+	params := postMgr.ClientParams
+	params.TrustedCerts = postMgr.TrustedCerts
+	params.SSLInsecure = postMgr.SSLInsecure
+	params.Metrics = postMgr.HTTPClientMetrics
+	postMgr.HttpClient = httpclient.NewClient(params)
+}
 
-	if postMgr.HTTPClientMetrics {
-		log.Debug("[BIGIP] Http client instrumented with metrics!")
-		instrumentedRoundTripper := promhttp.InstrumentRoundTripperInFlight(prometheus.ClientInFlightGauge,
-			promhttp.InstrumentRoundTripperCounter(prometheus.ClientAPIRequestsCounter,
-				promhttp.InstrumentRoundTripperTrace(prometheus.ClientTrace,
-					promhttp.InstrumentRoundTripperDuration(prometheus.ClientHistVec, tr),
-				),
-			),
-		)
-		postMgr.HttpClient = &http.Client{
-			Transport: instrumentedRoundTripper,
-			Timeout:   timeoutLarge,
-		}
-	} else {
-		postMgr.HttpClient = &http.Client{
-			Transport: tr,
-			Timeout:   timeoutLarge,
-		}
+// Stop cancels the REST calls in flight
+func (postMgr *PostManager) Stop() {
+	if postMgr.cancel != nil {
+		postMgr.cancel()
 	}
 }
 
@@ -257,6 +234,8 @@ func (postMgr *PostManager) GetBigipRegKey() (string, error) {
 }
 
 func (postMgr *PostManager) httpReq(request *http.Request) (*http.Response, map[string]interface{}) {
+	request, cancel := httpclient.WithRequestContext(postMgr.ctx, request, postMgr.ClientParams.GetRequestTimeout())
+	defer cancel()
 	httpResp, err := postMgr.HttpClient.Do(request)
 	if err != nil {
 		log.Errorf("[AS3] REST call error: %v ", err)
@@ -1584,6 +1584,40 @@ func (appMgr *Manager) syncVirtualServer(sKey serviceQueueKey) error {
 	return nil
 }
 
+// isLegacyConfigMap returns true for the f5-schema virtual-server ConfigMap of the CCCL agent, which is
+// translated into the AS3 declaration along with the Ingresses and Routes when CIS runs with the AS3 agent
+func isLegacyConfigMap(cm *v1.ConfigMap) bool {
+	for _, label := range []string{"as3", "overrideAS3", "stagingAS3"} {
+		if _, ok := cm.Labels[label]; ok {
+			return false
+		}
+	}
+	_, ok := cm.Data["data"]
+	return ok
+}
+
+// isAgentConfigMap returns true for the ConfigMap processed by the agent as is, like the AS3 ConfigMap
+func (appMgr *Manager) isAgentConfigMap(cm *v1.ConfigMap) bool {
+	return appMgr.AgentCIS.IsImplInAgent(ResourceTypeCfgMap) && !isLegacyConfigMap(cm)
+}
+
+// isParsedConfigMap returns true for the f5-schema ConfigMap parsed into the resource config by CIS
+func (appMgr *Manager) isParsedConfigMap(cm *v1.ConfigMap) bool {
+	if appMgr.AgentCIS.IsImplInAgent(ResourceTypeCfgMap) {
+		return isLegacyConfigMap(cm)
+	}
+	return appMgr.processAgentLabels(cm.Labels, cm.Name, cm.Namespace)
+}
+
+// isUnsupportedConfigMap returns true for the iApp ConfigMap under the AS3 agent, as AS3 does not deploy iApps
+func (appMgr *Manager) isUnsupportedConfigMap(cm *v1.ConfigMap, rsCfg *ResourceConfig) bool {
+	if rsCfg.MetaData.ResourceType != "iapp" || !appMgr.AgentCIS.IsImplInAgent(ResourceTypeCfgMap) {
+		return false
+	}
+	log.Errorf("[CORE] iApp ConfigMap %v/%v is not supported with the AS3 agent", cm.Namespace, cm.Name)
+	return true
+}
+
 func (appMgr *Manager) syncConfigMaps(
 	stats *vsSyncStats,
 	sKey serviceQueueKey,
@@ -1670,7 +1704,7 @@ func (appMgr *Manager) syncConfigMaps(
 			continue
 		}
 
-		if appMgr.AgentCIS.IsImplInAgent(ResourceTypeCfgMap) {
+		if appMgr.isAgentConfigMap(cm) {
 			//ignore invalid as3 configmaps if found.
 			if sKey.Operation != OprTypeDelete {
 				err := validateConfigJson(cm.Data["template"])
@@ -1680,10 +1714,12 @@ func (appMgr *Manager) syncConfigMaps(
 			}
 			if ok := appMgr.processAgentLabels(cm.Labels, cm.Name, cm.Namespace); ok {
 				agntCfgMap := new(AgentCfgMap)
-				agntCfgMap.Init(cm.Name, cm.Namespace, cm.Data["template"], cm.Labels, appMgr.getEndpoints)
+				agntCfgMap.Init(cm.Name, cm.Namespace, cm.Data["template"], cm.Labels, cm.Annotations, appMgr.getEndpoints)
 				key := cm.Namespace + "/" + cm.Name
 				if cfgMap, ok := appMgr.agentCfgMap[key]; ok {
-					if appMgr.hubMode || cfgMap.Data != cm.Data["template"] || cm.Labels["as3"] != cfgMap.Label["as3"] || cm.Labels["overrideAS3"] != cfgMap.Label["overrideAS3"] {
+					if appMgr.hubMode || cfgMap.Data != cm.Data["template"] || cm.Labels["as3"] != cfgMap.Label["as3"] || cm.Labels["overrideAS3"] != cfgMap.Label["overrideAS3"] ||
+						cm.Annotations[F5OverrideAS3PriorityAnnotation] != cfgMap.Annotation[F5OverrideAS3PriorityAnnotation] ||
+						cm.Annotations[F5OverrideAS3TenantsAnnotation] != cfgMap.Annotation[F5OverrideAS3TenantsAnnotation] {
 						appMgr.agentCfgMap[key] = agntCfgMap
 						stats.vsUpdated += 1
 					}
@@ -1710,6 +1746,9 @@ func (appMgr *Manager) syncConfigMaps(
 				cm.ObjectMeta.Namespace, cm.ObjectMeta.Name)
 			continue
 		}
+		if appMgr.isUnsupportedConfigMap(cm, rsCfg) {
+			continue
+		}
 
 		bigIPPrometheus.MonitoredServices.WithLabelValues(cm.ObjectMeta.Namespace, cm.ObjectMeta.Name, "parse-error").Set(0)
 
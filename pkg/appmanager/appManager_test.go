@@ -3690,6 +3690,53 @@ var _ = Describe("AppManager Tests", func() {
 				Expect(r).To(BeTrue(), "Config map should be processed.")
 			})
 
+			It("Verify legacy Configmap processing with AS3 agent", func() {
+				ns1 := "ns1"
+				mockMgr.appMgr.AgentCIS, _ = agent.CreateAgent(agent.AS3Agent)
+				mockMgr.appMgr.AgentCIS.Init(&as3.Params{})
+
+				err := mockMgr.startNonLabelMode([]string{ns1})
+				Expect(err).To(BeNil())
+				svc := test.NewService("foo", "1", ns1, "NodePort",
+					[]v1.ServicePort{{Port: 80, NodePort: 37001}})
+				r := mockMgr.addService(svc)
+				Expect(r).To(BeTrue(), "Service should be processed.")
+
+				// f5-schema ConfigMap is parsed into the resource config translated by the AS3 agent
+				cfgFoo := test.NewConfigMap("foomap", "1", ns1,
+					map[string]string{
+						"schema": schemaUrl,
+						"data":   configmapFoo,
+					})
+				r = mockMgr.addConfigMap(cfgFoo)
+				Expect(r).To(BeTrue(), "Config map should be processed.")
+				Expect(mockMgr.appMgr.agentCfgMap).To(BeEmpty(), "Legacy Config map should not be an AS3 Config map.")
+				rs, ok := mockMgr.resources().Get(
+					ServiceKey{ServiceName: "foo", ServicePort: 80, Namespace: ns1},
+					NameRef{Name: FormatConfigMapVSName(cfgFoo), Partition: "velcro"})
+				Expect(ok).To(BeTrue(), "Config map should be accessible.")
+				Expect(rs.MetaData.ResourceType).To(Equal("configmap"))
+				Expect(rs.Virtual.VirtualAddress.BindAddr).To(Equal("10.128.10.240"))
+
+				// AS3 ConfigMap with the f5-schema data is not a legacy ConfigMap
+				as3Cfg := test.NewConfigMap("as3map", "1", ns1,
+					map[string]string{
+						"schema": schemaUrl,
+						"data":   configmapFoo,
+					})
+				as3Cfg.Labels = map[string]string{"as3": "true"}
+				Expect(isLegacyConfigMap(as3Cfg)).To(BeFalse())
+
+				// iApps are not supported with the AS3 agent
+				cfgIapp := test.NewConfigMap("iappmap", "1", ns1,
+					map[string]string{
+						"schema": schemaUrl,
+						"data":   configmapIApp1,
+					})
+				r = mockMgr.addConfigMap(cfgIapp)
+				Expect(r).To(BeFalse(), "iApp Config map should not be processed.")
+			})
+
 			It("handles added and removed namespaces", func() {
 				cfgMapSelector, err := labels.Parse(DefaultConfigMapLabel)
 				Expect(err).To(BeNil())
@@ -44,7 +44,8 @@ func (appMgr *Manager) checkValidConfigMap(
 	}
 	//check if config map is agent specific implementation.
 	//if ok, add cfgMap name and data to serviceQueueKey.
-	if ok := appMgr.AgentCIS.IsImplInAgent(ResourceTypeCfgMap); ok {
+	//legacy configmaps are translated into the AS3 declaration like under the CCCL Agent.
+	if ok := appMgr.isAgentConfigMap(cm); ok {
 		//check if configmap has valid json and ignore the cfmap if invalid.
 		if oprType != OprTypeDelete {
 			err := validateConfigJson(cm.Data["template"])
@@ -64,11 +65,9 @@ func (appMgr *Manager) checkValidConfigMap(
 			return true, keyList
 		}
 		return false, nil
-	} else {
-		// In case of CCCL Agent
-		if !appMgr.processAgentLabels(cm.Labels, cm.Name, namespace) {
-			return false, nil
-		}
+	} else if !appMgr.isParsedConfigMap(cm) {
+		// Ignore the configmaps not meant for CIS
+		return false, nil
 	}
 
 	cfg, err := ParseConfigMap(cm, appMgr.schemaLocal, appMgr.vsSnatPoolName)
@@ -80,6 +79,9 @@ func (appMgr *Manager) checkValidConfigMap(
 		}
 		return false, nil
 	}
+	if appMgr.isUnsupportedConfigMap(cm, cfg) {
+		return false, nil
+	}
 	// This ensures that pool-only mode only logs the message below the first
 	// time we see a config.
 	rsName := NameRef{Name: FormatConfigMapVSName(cm), Partition: cfg.GetPartition()}
@@ -209,27 +211,25 @@ func (appMgr *Manager) checkValidPod(
 func (appMgr *Manager) getSecretServiceQueueKeyForConfigMap(secret *v1.Secret) []*serviceQueueKey {
 	var keyList []*serviceQueueKey
 	// We will be adding ResourceKind as Configmaps so that particular Configmaps can be re-synced
-	if !appMgr.AgentCIS.IsImplInAgent(ResourceTypeCfgMap) {
-		appInf, ok := appMgr.getNamespaceInformer(secret.ObjectMeta.Namespace)
-		if !ok || appInf.cfgMapInformer == nil {
-			return keyList
-		}
-		configmaps := appInf.cfgMapInformer.GetIndexer().List()
-		for _, obj := range configmaps {
-			cm := obj.(*v1.ConfigMap)
-			if appMgr.processAgentLabels(cm.Labels, cm.Name, cm.Namespace) {
-				cfg, err := ParseConfigMap(cm, appMgr.schemaLocal, appMgr.vsSnatPoolName)
-				if nil == err {
-					for _, profile := range cfg.Virtual.Profiles {
-						if profile.Name == secret.Name {
-							key := &serviceQueueKey{
-								ServiceName:  cfg.Pools[0].ServiceName,
-								Namespace:    cm.Namespace,
-								ResourceKind: Configmaps,
-								ResourceName: cm.Name,
-							}
-							keyList = append(keyList, key)
+	appInf, ok := appMgr.getNamespaceInformer(secret.ObjectMeta.Namespace)
+	if !ok || appInf.cfgMapInformer == nil {
+		return keyList
+	}
+	configmaps := appInf.cfgMapInformer.GetIndexer().List()
+	for _, obj := range configmaps {
+		cm := obj.(*v1.ConfigMap)
+		if appMgr.isParsedConfigMap(cm) {
+			cfg, err := ParseConfigMap(cm, appMgr.schemaLocal, appMgr.vsSnatPoolName)
+			if nil == err {
+				for _, profile := range cfg.Virtual.Profiles {
+					if profile.Name == secret.Name {
+						key := &serviceQueueKey{
+							ServiceName:  cfg.Pools[0].ServiceName,
+							Namespace:    cm.Namespace,
+							ResourceKind: Configmaps,
+							ResourceName: cm.Name,
 						}
+						keyList = append(keyList, key)
 					}
 				}
 			}
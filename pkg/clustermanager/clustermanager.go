@@ -1,6 +1,7 @@
 package clustermanager
 
 import (
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 )
@@ -17,6 +18,9 @@ func CreateKubeClientFromKubeConfig(kubeConfig *[]byte) (kubernetes.Interface, e
 	if err != nil {
 		return nil, err
 	}
+	// core types of the clusters are served in protobuf, JSON is accepted as the fallback
+	config.AcceptContentTypes = runtime.ContentTypeProtobuf + "," + runtime.ContentTypeJSON
+	config.ContentType = runtime.ContentTypeProtobuf
 	kubeClient, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,154 @@
+/*-
+* Copyright (c) 2016-2021, F5 Networks, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package controller
+
+import (
+	"fmt"
+	"hash/adler32"
+	"sort"
+	"strconv"
+	"strings"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// ACMESolverLabel identifies the cert-manager ACME HTTP-01 solver services
+	ACMESolverLabel = "acme.cert-manager.io/http01-solver"
+	// ACMEDomainLabel is the adler32 checksum of the domain served by the solver
+	ACMEDomainLabel = "acme.cert-manager.io/http-domain"
+	// ACMEChallengePath is the path of the ACME HTTP-01 challenges
+	ACMEChallengePath = "/.well-known/acme-challenge"
+
+	ACMEChallengeDgName    = "acme_challenge_dg"
+	ACMEChallengeIRuleName = "acme_challenge_irule"
+)
+
+// isACMESolverService checks whether the service is a cert-manager ACME HTTP-01 solver
+func isACMESolverService(svc *v1.Service) bool {
+	return svc.Labels[ACMESolverLabel] == "true"
+}
+
+// getACMEDomainHash returns the domain hash as labelled on the solver services by cert-manager
+func getACMEDomainHash(host string) string {
+	return strconv.FormatUint(uint64(adler32.Checksum([]byte(host))), 10)
+}
+
+// getACMESolverService returns the latest solver service in the namespace
+// serving the ACME HTTP-01 challenges of the host
+func (ctlr *Controller) getACMESolverService(namespace, host string) *v1.Service {
+	comInf, ok := ctlr.getNamespacedCommonInformer(namespace)
+	if !ok || comInf.svcInformer == nil {
+		return nil
+	}
+	services, err := comInf.svcInformer.GetIndexer().ByIndex("namespace", namespace)
+	if err != nil {
+		log.Errorf("Unable to get list of Services for namespace '%v': %v", namespace, err)
+		return nil
+	}
+
+	domainHash := getACMEDomainHash(host)
+	var solvers []*v1.Service
+	for _, obj := range services {
+		svc := obj.(*v1.Service)
+		if isACMESolverService(svc) && svc.Labels[ACMEDomainLabel] == domainHash && len(svc.Spec.Ports) > 0 {
+			solvers = append(solvers, svc)
+		}
+	}
+	if len(solvers) == 0 {
+		return nil
+	}
+	// Only one challenge is served per host, the older solvers belong to the stale challenges
+	sort.Slice(solvers, func(i, j int) bool {
+		if solvers[i].CreationTimestamp.Equal(&solvers[j].CreationTimestamp) {
+			return solvers[i].Name > solvers[j].Name
+		}
+		return solvers[j].CreationTimestamp.Before(&solvers[i].CreationTimestamp)
+	})
+	if len(solvers) > 1 {
+		log.Debugf("Multiple ACME solver services found for host %v, using %v/%v",
+			host, solvers[0].Namespace, solvers[0].Name)
+	}
+	return solvers[0]
+}
+
+// getVirtualServersForACMESolver returns the VirtualServers of the domain served by the solver service
+func (ctlr *Controller) getVirtualServersForACMESolver(svc *v1.Service) []*cisapiv1.VirtualServer {
+	var virtuals []*cisapiv1.VirtualServer
+	for _, vs := range ctlr.getAllVirtualServers(svc.Namespace) {
+		if vs.Spec.Host != "" && getACMEDomainHash(vs.Spec.Host) == svc.Labels[ACMEDomainLabel] {
+			virtuals = append(virtuals, vs)
+		}
+	}
+	return virtuals
+}
+
+// handleACMESolver forwards the ACME HTTP-01 challenges of the VirtualServer host
+// on the HTTP virtual to the cert-manager solver service
+func (ctlr *Controller) handleACMESolver(rsCfg *ResourceConfig, vs *cisapiv1.VirtualServer, rsRef resourceRef) {
+	if vs.Spec.Host == "" || strings.HasPrefix(vs.Spec.Host, "*") {
+		return
+	}
+	solver := ctlr.getACMESolverService(vs.Namespace, vs.Spec.Host)
+	if solver == nil {
+		return
+	}
+
+	servicePort := intstr.FromInt(int(solver.Spec.Ports[0].Port))
+	pool := Pool{
+		Name:             formatPoolName(solver.Namespace, solver.Name, servicePort, "", vs.Spec.Host, ""),
+		Partition:        rsCfg.Virtual.Partition,
+		ServiceName:      solver.Name,
+		ServiceNamespace: solver.Namespace,
+		ServicePort:      ctlr.fetchTargetPort(solver.Namespace, solver.Name, servicePort),
+	}
+	ctlr.updateMultiClusterResourceServiceMap(rsCfg, rsRef, solver.Name, ACMEChallengePath, pool, servicePort, "")
+	ctlr.updatePoolMembersForResources(&pool)
+	rsCfg.Pools = append(rsCfg.Pools, pool)
+
+	updateDataGroup(rsCfg.IntDgMap, getRSCfgResName(rsCfg.Virtual.Name, ACMEChallengeDgName),
+		rsCfg.Virtual.Partition, vs.Namespace, strings.ToLower(vs.Spec.Host), pool.Name, DataGroupType)
+	// Challenges are served on HTTP when the requests are redirected to HTTPS
+	addRedirectExclusions(rsCfg, vs.Namespace, vs.Spec.Host, []string{ACMEChallengePath})
+
+	iRuleName := getRSCfgResName(rsCfg.Virtual.Name, ACMEChallengeIRuleName)
+	rsCfg.addIRule(iRuleName, rsCfg.Virtual.Partition, ctlr.getACMEChallengeIRule(rsCfg.Virtual.Name, rsCfg.Virtual.Partition))
+	rsCfg.Virtual.AddIRule(JoinBigipPath(rsCfg.Virtual.Partition, iRuleName))
+}
+
+// getACMEChallengeIRule returns the iRule selecting the solver pool of the
+// host in ACME challenge data group for the ACME HTTP-01 challenges
+func (ctlr *Controller) getACMEChallengeIRule(rsVSName string, partition string) string {
+	dgPath := strings.Join([]string{partition, Shared}, "/")
+
+	iRule := fmt.Sprintf(`when HTTP_REQUEST priority 100 {
+			if { [HTTP::path] starts_with "%[3]s/" } {
+				set acme_class "/%[1]s/%[2]s_%[4]s"
+				if { [class exists $acme_class] } {
+					set acme_pool [class match -value [string tolower [getfield [HTTP::host] ":" 1]] equals $acme_class]
+					if { $acme_pool != "" } {
+						pool $acme_pool
+					}
+				}
+			}
+		}`, dgPath, rsVSName, ACMEChallengePath, ACMEChallengeDgName)
+
+	return iRule
+}
@@ -0,0 +1,280 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConditionAddressConflict is the VirtualServer and TransportServer status condition set when the
+// virtual server address and port are already claimed by an older resource
+const ConditionAddressConflict = "AddressConflict"
+
+// addressClaim is a resource config claiming a virtual server address and port
+type addressClaim struct {
+	partition string
+	rsName    string
+	rsCfg     *ResourceConfig
+	claimant  resourceRef
+}
+
+// getAddressKey returns the virtual server address and port claimed by the resource config
+func getAddressKey(rsCfg *ResourceConfig) string {
+	if rsCfg.Virtual.VirtualAddress == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v:%v", rsCfg.Virtual.VirtualAddress.BindAddr, rsCfg.Virtual.VirtualAddress.Port)
+}
+
+// isCustomResourceClaim checks whether the resource config is published for the VirtualServers, Ingresses or
+// TransportServers, address claims of the other resources are not validated
+func isCustomResourceClaim(rsCfg *ResourceConfig) bool {
+	if getAddressKey(rsCfg) == "" || len(rsCfg.MetaData.baseResources) == 0 {
+		return false
+	}
+	for _, kind := range rsCfg.MetaData.baseResources {
+		if kind != VirtualServer && kind != TransportServer && kind != Ingress {
+			return false
+		}
+	}
+	return true
+}
+
+// shareBaseResources checks whether the resource configs are published for any of the same resources,
+// resource configs of the VirtualServers sharing the virtual server address are grouped together
+func shareBaseResources(rsCfg, other *ResourceConfig) bool {
+	for key, kind := range rsCfg.MetaData.baseResources {
+		if other.MetaData.baseResources[key] == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// isClaimedByOtherResources checks whether the resource config is published for resources other than the
+// given base resources, resource configs without base resources are not claimed by the custom resources
+func isClaimedByOtherResources(rsCfg *ResourceConfig, baseResources map[string]string) bool {
+	if len(rsCfg.MetaData.baseResources) == 0 {
+		return false
+	}
+	return !shareBaseResources(rsCfg, &ResourceConfig{MetaData: metaData{baseResources: baseResources}})
+}
+
+// getVirtualServerBaseResources returns the base resources of the resource configs of the VirtualServers
+func getVirtualServerBaseResources(virtuals ...*cisapiv1.VirtualServer) map[string]string {
+	baseResources := make(map[string]string)
+	for _, vrt := range virtuals {
+		if ingName, ok := getIngressNameForVirtualServer(vrt); ok {
+			baseResources[vrt.Namespace+"/"+ingName] = Ingress
+			continue
+		}
+		baseResources[vrt.Namespace+"/"+vrt.Name] = VirtualServer
+	}
+	return baseResources
+}
+
+// getBaseResource returns the base resource of the kind with the namespace/name key from the informer
+func (ctlr *Controller) getBaseResource(kind, rscKey string) metav1.Object {
+	crInf, ok := ctlr.getNamespacedCRInformer(strings.Split(rscKey, "/")[0])
+	if !ok {
+		return nil
+	}
+	var obj interface{}
+	var exist bool
+	switch kind {
+	case VirtualServer:
+		obj, exist, _ = crInf.vsInformer.GetIndexer().GetByKey(rscKey)
+	case TransportServer:
+		obj, exist, _ = crInf.tsInformer.GetIndexer().GetByKey(rscKey)
+	case Ingress:
+		if crInf.ingInformer != nil {
+			obj, exist, _ = crInf.ingInformer.GetIndexer().GetByKey(rscKey)
+		}
+	}
+	if !exist {
+		return nil
+	}
+	rsc, _ := obj.(metav1.Object)
+	return rsc
+}
+
+// getAddressClaimant returns the oldest base resource of the resource config, the creation time of
+// the claim is the creation time of its oldest resource. Claims of unknown resources are the newest
+func (ctlr *Controller) getAddressClaimant(rsCfg *ResourceConfig) (metav1.Time, resourceRef) {
+	var keys []string
+	for key := range rsCfg.MetaData.baseResources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	created := metav1.Now()
+	var claimant resourceRef
+	for _, key := range keys {
+		kind := rsCfg.MetaData.baseResources[key]
+		rsc := ctlr.getBaseResource(kind, key)
+		if rsc == nil {
+			continue
+		}
+		if rscCreated := rsc.GetCreationTimestamp(); claimant.kind == "" || rscCreated.Before(&created) {
+			created = rscCreated
+			claimant = resourceRef{kind: kind, namespace: rsc.GetNamespace(), name: rsc.GetName()}
+		}
+	}
+	return created, claimant
+}
+
+// isOlderClaim checks whether the claim created by the claimant is older than the other claim,
+// claims created at the same time are ordered by the kind, namespace and name of the claimant
+func isOlderClaim(created metav1.Time, claimant resourceRef, otherCreated metav1.Time, other resourceRef) bool {
+	if !created.Equal(&otherCreated) {
+		return created.Before(&otherCreated)
+	}
+	return claimant.kind+"/"+claimant.namespace+"/"+claimant.name < other.kind+"/"+other.namespace+"/"+other.name
+}
+
+// claimVirtualAddress checks the virtual server address and port of the resource config against the resource
+// configs of the other resources across the partitions. The oldest resource keeps the address, the newer ones
+// are marked with the AddressConflict condition and not published so that their declarations are not merged
+// into the same virtual on BIG-IP. Returns whether the resource config is to be published
+func (ctlr *Controller) claimVirtualAddress(partition, rsName string, rsCfg *ResourceConfig) bool {
+	if !isCustomResourceClaim(rsCfg) {
+		return true
+	}
+	addrKey := getAddressKey(rsCfg)
+	created, claimant := ctlr.getAddressClaimant(rsCfg)
+	var owned, newer []addressClaim
+	var owner *addressClaim
+	var ownerCreated metav1.Time
+	for ptn, ptnCfg := range ctlr.resources.ltmConfig {
+		for name, cfg := range ptnCfg.ResourceMap {
+			if !isCustomResourceClaim(cfg) || getAddressKey(cfg) != addrKey {
+				continue
+			}
+			if shareBaseResources(rsCfg, cfg) {
+				owned = append(owned, addressClaim{partition: ptn, rsName: name, rsCfg: cfg})
+				continue
+			}
+			cfgCreated, cfgClaimant := ctlr.getAddressClaimant(cfg)
+			claim := addressClaim{partition: ptn, rsName: name, rsCfg: cfg, claimant: cfgClaimant}
+			if !isOlderClaim(cfgCreated, cfgClaimant, created, claimant) {
+				newer = append(newer, claim)
+			} else if owner == nil || isOlderClaim(cfgCreated, cfgClaimant, ownerCreated, owner.claimant) {
+				owner, ownerCreated = &claim, cfgCreated
+			}
+		}
+	}
+
+	if owner != nil {
+		log.Errorf("Address %v of %v %v/%v is already claimed by %v %v/%v", addrKey, claimant.kind,
+			claimant.namespace, claimant.name, owner.claimant.kind, owner.claimant.namespace, owner.claimant.name)
+		ctlr.setAddressConflict(rsCfg, addrKey, owner.claimant)
+		// remove the resource configs published before the conflict
+		for _, claim := range owned {
+			ctlr.deleteVirtualServer(claim.partition, claim.rsName)
+		}
+		return false
+	}
+	for _, claim := range newer {
+		log.Warningf("Address %v of %v %v/%v is claimed by the older %v %v/%v", addrKey, claim.claimant.kind,
+			claim.claimant.namespace, claim.claimant.name, claimant.kind, claimant.namespace, claimant.name)
+		ctlr.setAddressConflict(claim.rsCfg, addrKey, claimant)
+		if claim.partition != partition || claim.rsName != rsName {
+			ctlr.deleteVirtualServer(claim.partition, claim.rsName)
+		}
+	}
+	ctlr.clearAddressConflict(rsCfg)
+	return true
+}
+
+// setAddressConflict sets the AddressConflict condition of the base resources of the resource config and
+// records them so that they are processed again once the address is released by the claimant
+func (ctlr *Controller) setAddressConflict(rsCfg *ResourceConfig, addrKey string, claimant resourceRef) {
+	msg := fmt.Sprintf("Address %v is already claimed by %v %v/%v", addrKey, claimant.kind, claimant.namespace,
+		claimant.name)
+	for key, kind := range rsCfg.MetaData.baseResources {
+		rsc := ctlr.getBaseResource(kind, key)
+		if rsc == nil {
+			continue
+		}
+		rscRef := resourceRef{kind: kind, namespace: rsc.GetNamespace(), name: rsc.GetName()}
+		cond := metav1.Condition{
+			Type:               ConditionAddressConflict,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: rsc.GetGeneration(),
+			Reason:             "DuplicateAddress",
+			Message:            msg,
+		}
+		if kind == VirtualServer || kind == TransportServer {
+			ctlr.resources.addressConflicts[rscRef] = addrKey
+		}
+		ctlr.setResourceCondition(rsc, cond)
+	}
+}
+
+// clearAddressConflict removes the AddressConflict condition of the base resources of the resource config
+func (ctlr *Controller) clearAddressConflict(rsCfg *ResourceConfig) {
+	for key, kind := range rsCfg.MetaData.baseResources {
+		rsc := ctlr.getBaseResource(kind, key)
+		if rsc == nil {
+			continue
+		}
+		delete(ctlr.resources.addressConflicts, resourceRef{kind: kind, namespace: rsc.GetNamespace(), name: rsc.GetName()})
+		ctlr.removeResourceCondition(rsc, ConditionAddressConflict)
+	}
+}
+
+// enqueueReleasedAddressConflicts enqueues the resources discarded for the address conflicts once the
+// address is no longer claimed by another resource
+func (ctlr *Controller) enqueueReleasedAddressConflicts() {
+	for rscRef, addrKey := range ctlr.resources.addressConflicts {
+		rscKey := rscRef.namespace + "/" + rscRef.name
+		rsc := ctlr.getBaseResource(rscRef.kind, rscKey)
+		if rsc == nil {
+			delete(ctlr.resources.addressConflicts, rscRef)
+			continue
+		}
+		if ctlr.isAddressClaimedByOthers(addrKey, map[string]string{rscKey: rscRef.kind}) {
+			continue
+		}
+		log.Debugf("Address %v is released, processing %v %v again", addrKey, rscRef.kind, rscKey)
+		delete(ctlr.resources.addressConflicts, rscRef)
+		switch rscRef.kind {
+		case VirtualServer:
+			ctlr.enqueueVirtualServer(rsc)
+		case TransportServer:
+			ctlr.enqueueTransportServer(rsc)
+		}
+	}
+}
+
+// isAddressClaimedByOthers checks whether the address is claimed by a resource config of other resources
+func (ctlr *Controller) isAddressClaimedByOthers(addrKey string, baseResources map[string]string) bool {
+	for _, ptnCfg := range ctlr.resources.ltmConfig {
+		for _, cfg := range ptnCfg.ResourceMap {
+			if isCustomResourceClaim(cfg) && getAddressKey(cfg) == addrKey &&
+				isClaimedByOtherResources(cfg, baseResources) {
+				return true
+			}
+		}
+	}
+	return false
+}
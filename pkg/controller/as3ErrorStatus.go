@@ -0,0 +1,125 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"regexp"
+	"strings"
+
+	cisscheme "github.com/F5Networks/k8s-bigip-ctlr/v2/config/client/clientset/versioned/scheme"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// ConditionDeclarationError is the VirtualServer and TransportServer status condition set when
+// AS3 fails the declaration of the objects of the resource
+const ConditionDeclarationError = "DeclarationError"
+
+// as3ObjectPath matches the tenant and object name in the paths of the AS3 error messages,
+// such as /test/Shared/crd_10_1_1_1_80/pool
+var as3ObjectPath = regexp.MustCompile(`/([A-Za-z0-9_.-]+)/Shared/([A-Za-z0-9_.-]+)`)
+
+// newEventRecorder returns the recorder of the Kubernetes events of the resources processed by CIS
+func newEventRecorder(kubeClient kubernetes.Interface) record.EventRecorder {
+	eventScheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(eventScheme)
+	_ = cisscheme.AddToScheme(eventScheme)
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	return broadcaster.NewRecorder(eventScheme, v1.EventSource{Component: "k8s-bigip-ctlr"})
+}
+
+// getAS3ObjectNames returns the names of the AS3 objects rendered for the resource config
+func getAS3ObjectNames(rsCfg *ResourceConfig) []string {
+	names := []string{rsCfg.Virtual.Name}
+	for _, pool := range rsCfg.Pools {
+		names = append(names, pool.Name)
+	}
+	for _, monitor := range rsCfg.Monitors {
+		names = append(names, monitor.Name)
+	}
+	for _, policy := range rsCfg.Policies {
+		names = append(names, policy.Name)
+	}
+	for ref := range rsCfg.IRulesMap {
+		names = append(names, ref.Name)
+	}
+	for ref := range rsCfg.IntDgMap {
+		names = append(names, ref.Name)
+	}
+	return names
+}
+
+// getAS3ErrorResources returns the base resources of the AS3 objects of the tenant referred in the error
+// message. Objects derived from the name of another object, such as the TLS profiles of the virtual,
+// are mapped to the resources of that object
+func getAS3ErrorResources(objects map[string]map[string]string, tenant, message string) map[string]string {
+	rscs := make(map[string]string)
+	for _, match := range as3ObjectPath.FindAllStringSubmatch(message, -1) {
+		if match[1] != tenant {
+			continue
+		}
+		baseResources, ok := objects[match[2]]
+		if !ok {
+			for name, objRscs := range objects {
+				if name != "" && strings.HasPrefix(match[2], name+"_") {
+					baseResources = objRscs
+					break
+				}
+			}
+		}
+		for key, kind := range baseResources {
+			rscs[key] = kind
+		}
+	}
+	return rscs
+}
+
+// updateAS3ErrorStatus records the AS3 error of the failed tenant in the status of the resources of the
+// objects referred in the error message and as their events. Returns the resources of the error
+func (ctlr *Controller) updateAS3ErrorStatus(rm requestMeta, tenant, message string) map[string]string {
+	rscs := getAS3ErrorResources(rm.as3Objects[tenant], tenant, message)
+	if len(rscs) == 0 {
+		log.Debugf("[AS3] Unable to find the resources of the error of tenant %v: %v", tenant, message)
+		return nil
+	}
+	cond := metav1.Condition{
+		Type:    ConditionDeclarationError,
+		Status:  metav1.ConditionTrue,
+		Reason:  "AS3Error",
+		Message: message,
+	}
+	for key, kind := range rscs {
+		rsc := ctlr.getBaseResource(kind, key)
+		if rsc == nil {
+			continue
+		}
+		log.Errorf("[AS3] Declaration of %v %v failed: %v", kind, key, message)
+		cond.ObservedGeneration = rsc.GetGeneration()
+		ctlr.setResourceCondition(rsc, cond)
+		if obj, ok := rsc.(runtime.Object); ok && ctlr.eventRecorder != nil {
+			ctlr.eventRecorder.Event(obj, v1.EventTypeWarning, "AS3Error", message)
+		}
+	}
+	return rscs
+}
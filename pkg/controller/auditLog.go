@@ -0,0 +1,307 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// operations of the audit records
+	auditPost  = "post"
+	auditRetry = "retry"
+	// rotated audit log files retained, as <file>.1 to <file>.5
+	auditLogBackups = 5
+	// reason and annotation of the audit events, annotation holds the audit record
+	auditEventReason      = "AS3Declaration"
+	auditRecordAnnotation = "cis.f5.com/audit-record"
+	// maximum length of the message of the kubernetes events
+	auditEventMessageLimit = 1024
+)
+
+type (
+	// AuditParams of the audit log of the declarations posted to BIG-IP
+	AuditParams struct {
+		// File of the audit records in JSON lines, rotated once its size exceeds MaxSize MB
+		File    string
+		MaxSize int
+		// EventsNamespace of the kubernetes events of the audit records
+		EventsNamespace string
+	}
+
+	// auditLogger records the declarations posted to BIG-IP in the audit log file and as kubernetes events
+	auditLogger struct {
+		sync.Mutex
+		AuditParams
+		kubeClient kubernetes.Interface
+		file       *os.File
+		size       int64
+	}
+
+	// auditRecord is the audit record of a declaration posted to BIG-IP
+	auditRecord struct {
+		Time      string        `json:"time"`
+		Operation string        `json:"operation"`
+		RequestID int           `json:"requestId,omitempty"`
+		BigIPURL  string        `json:"bigipURL"`
+		BigIPUser string        `json:"bigipUser,omitempty"`
+		Tenants   []auditTenant `json:"tenants"`
+	}
+
+	// auditTenant is the change of the tenant in the declaration and the response of BIG-IP
+	auditTenant struct {
+		Tenant string `json:"tenant"`
+		// resources of the tenant which triggered the declaration, as <kind>/<namespace>/<name>
+		Resources []string `json:"resources,omitempty"`
+		// objects of the tenant changed from the last successful declaration, as <application>/<object>
+		Added    []string `json:"added,omitempty"`
+		Modified []string `json:"modified,omitempty"`
+		Deleted  []string `json:"deleted,omitempty"`
+		Code     int      `json:"code"`
+		Message  string   `json:"message,omitempty"`
+	}
+)
+
+// newAuditLogger returns the audit logger, nil when the audit log is not enabled
+func newAuditLogger(params AuditParams, kubeClient kubernetes.Interface) *auditLogger {
+	if params.File == "" && params.EventsNamespace == "" {
+		return nil
+	}
+	return &auditLogger{AuditParams: params, kubeClient: kubeClient}
+}
+
+// auditDeclaration records the posted declaration of the tenants with the resources which triggered it, the
+// objects changed from the last successful declaration and the responses of BIG-IP
+func (agent *Agent) auditDeclaration(operation string, rsConfig *ResourceConfigRequest, tenants []string,
+	tenantDeclMap map[string]as3Tenant) {
+	if agent.auditLog == nil {
+		return
+	}
+	rec := auditRecord{
+		Time:      time.Now().UTC().Format(time.RFC3339),
+		Operation: operation,
+		BigIPURL:  agent.BIGIPURL,
+		BigIPUser: agent.BIGIPUsername,
+	}
+	if rsConfig != nil {
+		rec.RequestID = rsConfig.reqId
+	}
+	sortedTenants := append([]string{}, tenants...)
+	sort.Strings(sortedTenants)
+	for _, tenant := range sortedTenants {
+		at := auditTenant{Tenant: tenant}
+		if rsConfig != nil {
+			at.Resources = getAuditResources(rsConfig.ltmConfig[tenant])
+		}
+		at.Added, at.Modified, at.Deleted = diffTenantDeclaration(agent.cachedTenantDeclMap[tenant],
+			tenantDeclMap[tenant])
+		if resp, ok := agent.tenantResponseMap[tenant]; ok {
+			at.Code = resp.agentResponseCode
+			at.Message = resp.message
+		}
+		rec.Tenants = append(rec.Tenants, at)
+	}
+	agent.auditLog.record(rec)
+}
+
+// getAuditResources returns the resources of the partition config
+func getAuditResources(partitionConfig *PartitionConfig) []string {
+	if partitionConfig == nil {
+		return nil
+	}
+	rscs := make(map[string]struct{})
+	for _, rsCfg := range partitionConfig.ResourceMap {
+		for key, kind := range rsCfg.MetaData.baseResources {
+			rscs[kind+"/"+key] = struct{}{}
+		}
+	}
+	var resources []string
+	for rsc := range rscs {
+		resources = append(resources, rsc)
+	}
+	sort.Strings(resources)
+	return resources
+}
+
+// diffTenantDeclaration returns the objects of the applications added, modified and deleted in the declaration
+// of the tenant
+func diffTenantDeclaration(cached, incoming as3Tenant) ([]string, []string, []string) {
+	cachedObjs := getTenantObjects(cached)
+	incomingObjs := getTenantObjects(incoming)
+	var added, modified, deleted []string
+	for name, obj := range incomingObjs {
+		cachedObj, ok := cachedObjs[name]
+		if !ok {
+			added = append(added, name)
+		} else if !reflect.DeepEqual(cachedObj, obj) {
+			modified = append(modified, name)
+		}
+	}
+	for name := range cachedObjs {
+		if _, ok := incomingObjs[name]; !ok {
+			deleted = append(deleted, name)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(modified)
+	sort.Strings(deleted)
+	return added, modified, deleted
+}
+
+// getTenantObjects returns the objects of the applications of the tenant by <application>/<object>, objects are
+// compared in their JSON form as the cached declarations fetched from BIG-IP are not typed
+func getTenantObjects(tenant as3Tenant) map[string]interface{} {
+	objs := make(map[string]interface{})
+	if tenant == nil {
+		return objs
+	}
+	data, err := json.Marshal(tenant)
+	if err != nil {
+		return objs
+	}
+	var apps map[string]interface{}
+	if err := json.Unmarshal(data, &apps); err != nil {
+		return objs
+	}
+	for appName, app := range apps {
+		appObjs, ok := app.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for name, obj := range appObjs {
+			if _, ok := obj.(map[string]interface{}); ok {
+				objs[appName+"/"+name] = obj
+			}
+		}
+	}
+	return objs
+}
+
+// record writes the audit record to the audit log file and creates the kubernetes event of the record
+func (al *auditLogger) record(rec auditRecord) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		log.Errorf("[AUDIT] Unable to marshal the audit record: %v", err)
+		return
+	}
+	if al.File != "" {
+		if err := al.writeRecord(data); err != nil {
+			log.Errorf("[AUDIT] Unable to write the audit record to %v: %v", al.File, err)
+		}
+	}
+	if al.EventsNamespace != "" && al.kubeClient != nil {
+		go al.createEvent(rec, data)
+	}
+}
+
+// writeRecord appends the record to the audit log file, file is rotated once its size exceeds the maximum size
+func (al *auditLogger) writeRecord(data []byte) error {
+	al.Lock()
+	defer al.Unlock()
+	maxSize := int64(al.MaxSize) * 1024 * 1024
+	if al.file != nil && maxSize > 0 && al.size+int64(len(data))+1 > maxSize {
+		al.file.Close()
+		al.file = nil
+		for i := auditLogBackups - 1; i > 0; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", al.File, i), fmt.Sprintf("%s.%d", al.File, i+1))
+		}
+		if err := os.Rename(al.File, al.File+".1"); err != nil {
+			return err
+		}
+	}
+	if al.file == nil {
+		file, err := os.OpenFile(al.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		info, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return err
+		}
+		al.file = file
+		al.size = info.Size()
+	}
+	n, err := al.file.Write(append(data, '\n'))
+	al.size += int64(n)
+	return err
+}
+
+// createEvent creates the kubernetes event of the audit record in the namespace of the audit events
+func (al *auditLogger) createEvent(rec auditRecord, data []byte) {
+	eventType := v1.EventTypeNormal
+	var summary []string
+	for _, tenant := range rec.Tenants {
+		if tenant.Code != http.StatusOK {
+			eventType = v1.EventTypeWarning
+		}
+		summary = append(summary, fmt.Sprintf("%v: code %v, added %v, modified %v, deleted %v", tenant.Tenant,
+			tenant.Code, len(tenant.Added), len(tenant.Modified), len(tenant.Deleted)))
+	}
+	message := fmt.Sprintf("AS3 declaration %v to %v, %v", rec.Operation, rec.BigIPURL, strings.Join(summary, "; "))
+	if len(message) > auditEventMessageLimit {
+		message = message[:auditEventMessageLimit]
+	}
+	now := metav1.Now()
+	event := &v1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "k8s-bigip-ctlr-audit-",
+			Namespace:    al.EventsNamespace,
+			Annotations:  map[string]string{auditRecordAnnotation: string(data)},
+		},
+		InvolvedObject: v1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "Namespace",
+			Name:       al.EventsNamespace,
+		},
+		Reason:         auditEventReason,
+		Message:        message,
+		Type:           eventType,
+		Source:         v1.EventSource{Component: "k8s-bigip-ctlr"},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	_, err := al.kubeClient.CoreV1().Events(al.EventsNamespace).Create(context.TODO(), event, metav1.CreateOptions{})
+	if err != nil {
+		log.Errorf("[AUDIT] Unable to create the audit event in namespace %v: %v", al.EventsNamespace, err)
+	}
+}
+
+// close closes the audit log file
+func (al *auditLogger) close() {
+	al.Lock()
+	defer al.Unlock()
+	if al.file != nil {
+		al.file.Close()
+		al.file = nil
+	}
+}
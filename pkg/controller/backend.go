@@ -30,11 +30,17 @@ import (
 	rsc "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/resource"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
 	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/writer"
+	"github.com/xeipuuv/gojsonschema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 const (
 	as3SharedApplication = "Shared"
 	gtmPartition         = "Common"
+	// AS3 schema used to validate the declarations, present in the schema-db-base-dir
+	as3SchemaFileName = "as3-schema-3.45.0-5-cis.json"
+	// as3SchemaFileVersion is the AS3 schema version of the as3SchemaFileName
+	as3SchemaFileVersion = "3.45.0"
 )
 
 var baseAS3Config = `{
@@ -81,6 +87,25 @@ func NewAgent(params AgentParams) *Agent {
 		HttpAddress:           params.HttpAddress,
 		ccclGTMAgent:          params.CCCLGTMAgent,
 		disableARP:            params.DisableARP,
+		as3SchemaVersion:      params.AS3SchemaVersion,
+		as3Validation:         params.AS3DeclValidation,
+		as3SchemaURL:          params.SchemaLocal + as3SchemaFileName,
+		sharePools:            params.SharePools,
+	}
+	if params.XCParams.XCAPIURL != "" {
+		// The configuration is posted to F5 Distributed Cloud, BIG-IP is not configured
+		agent.xcManager = NewXCManager(params)
+		go agent.exportWorker(agent.xcManager.postXCConfig)
+		go agent.enableMetrics()
+		log.Warning("[XC] F5 Distributed Cloud agent is experimental")
+		return agent
+	}
+	if params.NginxParams.NginxPlusAPIURL != "" {
+		// Pools are exported as the NGINX Plus upstreams, BIG-IP is not configured
+		agent.nginxManager = NewNginxManager(params, postMgr.httpClient)
+		go agent.exportWorker(agent.nginxManager.postNginxConfig)
+		go agent.enableMetrics()
+		return agent
 	}
 	// agentWorker runs as a separate go routine
 	// blocks on postChan to get new/updated configuration to be posted to BIG-IP
@@ -90,6 +115,11 @@ func NewAgent(params AgentParams) *Agent {
 	// blocks on retryChan ; retries failed declarations and polls for accepted tenant statuses
 	go agent.retryWorker()
 
+	if params.PostParams.CircuitBreakerThreshold > 0 {
+		// circuitBreakerWorker probes the unreachable BIG-IP and resumes the posts once it is back
+		go agent.circuitBreakerWorker()
+	}
+
 	// If running in VXLAN mode, extract the partition name from the tunnel
 	// to be used in configuring a net instance of CCCL for that partition
 	var vxlanPartition string
@@ -153,7 +183,7 @@ func NewAgent(params AgentParams) *Agent {
 			params.PythonBaseDir,
 		)
 	} else {
-		// we only enable metrics as pythondriver is not initialized for ipv6
+		// we only enable metrics and readiness as pythondriver is not initialized for ipv6
 		go agent.enableMetrics()
 	}
 	// Set the AS3 version for the agent
@@ -166,8 +196,161 @@ func NewAgent(params AgentParams) *Agent {
 	return agent
 }
 
+// pinAS3SchemaVersion sets the schemaVersion of the declarations to the as3-schema-version,
+// it is ignored when the AS3 on BIG-IP doesn't support the schema version
+func (agent *Agent) pinAS3SchemaVersion(bigIPSchemaVersion string) {
+	if agent.as3SchemaVersion == "" {
+		return
+	}
+	if compareAS3SchemaVersions(agent.as3SchemaVersion, bigIPSchemaVersion) > 0 {
+		log.Warningf("[AS3] AS3 schema version %v is not supported by BIG-IP, using the schema version %v",
+			agent.as3SchemaVersion, agent.AS3VersionInfo.as3SchemaVersion)
+		return
+	}
+	log.Debugf("[AS3] Using the AS3 schema version %v", agent.as3SchemaVersion)
+	agent.AS3VersionInfo.as3SchemaVersion = agent.as3SchemaVersion
+}
+
+// compareAS3SchemaVersions compares the major, minor and patch of the AS3 schema versions
+func compareAS3SchemaVersions(v1, v2 string) int {
+	s1, s2 := strings.Split(v1, "."), strings.Split(v2, ".")
+	for i := 0; i < len(s1) || i < len(s2); i++ {
+		var n1, n2 int
+		if i < len(s1) {
+			n1, _ = strconv.Atoi(s1[i])
+		}
+		if i < len(s2) {
+			n2, _ = strconv.Atoi(s2[i])
+		}
+		if n1 != n2 {
+			if n1 > n2 {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
+
+// validateAS3Declaration validates the objects of the declaration with the AS3 schema and returns the
+// errors of the invalid objects by tenant. Schema errors don't have the names of the tenants and the objects,
+// so the objects are validated one at a time along with the properties of their tenant and application
+func (agent *Agent) validateAS3Declaration(decl as3Declaration) map[string][]string {
+	if !agent.as3Validation {
+		return nil
+	}
+	// objects supported only by the newer schema versions are rejected by the schema of CIS
+	if compareAS3SchemaVersions(agent.AS3VersionInfo.as3SchemaVersion, as3SchemaFileVersion) > 0 {
+		log.Debugf("[AS3] AS3 schema version %v is newer than the AS3 schema %v, skipping the validation",
+			agent.AS3VersionInfo.as3SchemaVersion, as3SchemaFileName)
+		return nil
+	}
+	if agent.as3Schema == nil {
+		schema, err := gojsonschema.NewSchema(gojsonschema.NewReferenceLoader(agent.as3SchemaURL))
+		if err != nil {
+			log.Errorf("[AS3] Unable to load AS3 schema %v, skipping the validation: %v", agent.as3SchemaURL, err)
+			return nil
+		}
+		agent.as3Schema = schema
+	}
+	var as3Config map[string]interface{}
+	if err := json.Unmarshal([]byte(decl), &as3Config); err != nil {
+		log.Errorf("[AS3] Unable to validate AS3 declaration: %v", err)
+		return nil
+	}
+	adc, _ := as3Config["declaration"].(map[string]interface{})
+	tenantErrs := make(map[string][]string)
+	for tenant, tenantDecl := range adc {
+		apps, ok := tenantDecl.(map[string]interface{})
+		if !ok || apps["class"] != "Tenant" {
+			continue
+		}
+		for app, appDecl := range apps {
+			objs, ok := appDecl.(map[string]interface{})
+			if !ok || objs["class"] != "Application" {
+				continue
+			}
+			for obj, objDecl := range objs {
+				if _, ok := objDecl.(map[string]interface{}); !ok {
+					continue
+				}
+				singleObjDecl, _ := json.Marshal(map[string]interface{}{
+					"class": as3Config["class"],
+					"declaration": withAS3Properties(adc, map[string]interface{}{
+						tenant: withAS3Properties(apps, map[string]interface{}{
+							app: withAS3Properties(objs, map[string]interface{}{obj: objDecl}),
+						}),
+					}),
+				})
+				singleObjErrs, err := agent.getAS3SchemaErrors(string(singleObjDecl))
+				if err != nil {
+					log.Errorf("[AS3] Unable to validate AS3 object /%v/%v/%v: %v", tenant, app, obj, err)
+					continue
+				}
+				for _, desc := range singleObjErrs {
+					tenantErrs[tenant] = append(tenantErrs[tenant], fmt.Sprintf("/%v/%v/%v: %v", tenant, app, obj, desc))
+				}
+			}
+		}
+	}
+	for _, errs := range tenantErrs {
+		sort.Strings(errs)
+	}
+	return tenantErrs
+}
+
+// removeInvalidTenants removes the tenants with the invalid objects from the incoming tenants, so that only
+// the valid tenants are posted to BIG-IP, and returns the declaration of the valid tenants
+func (agent *Agent) removeInvalidTenants(tenantErrs map[string][]string) as3Declaration {
+	for tenant, errs := range tenantErrs {
+		log.Errorf("[AS3] Invalid AS3 declaration of tenant %v, skipping the post to BIG-IP. See errors", tenant)
+		for _, desc := range errs {
+			log.Errorf("- %s", desc)
+		}
+		delete(agent.incomingTenantDeclMap, tenant)
+		agent.invalidTenantErrors[tenant] = strings.Join(errs, "; ")
+	}
+	return agent.createAS3Declaration(agent.incomingTenantDeclMap)
+}
+
+// withAS3Properties adds the properties of the AS3 class, which are not other AS3 classes, to the objects
+func withAS3Properties(class, objs map[string]interface{}) map[string]interface{} {
+	for key, val := range class {
+		if obj, ok := val.(map[string]interface{}); ok && obj["class"] != nil {
+			continue
+		}
+		objs[key] = val
+	}
+	return objs
+}
+
+// getAS3SchemaErrors returns the descriptions of the AS3 schema errors of the declaration
+func (agent *Agent) getAS3SchemaErrors(decl string) ([]string, error) {
+	result, err := agent.as3Schema.Validate(gojsonschema.NewStringLoader(decl))
+	if err != nil {
+		return nil, err
+	}
+	var errs []string
+	for _, desc := range result.Errors() {
+		// Errors of the schema combinations and conditions are reported along with the errors of the fields
+		switch desc.Type() {
+		case "number_one_of", "number_any_of", "number_all_of", "condition_then", "condition_else":
+			continue
+		}
+		errs = append(errs, desc.Description())
+	}
+	return errs, nil
+}
+
 func (agent *Agent) Stop() {
+	if agent.PostManager != nil && agent.cancel != nil {
+		// cancel the REST calls in flight
+		agent.cancel()
+	}
 	agent.ConfigWriter.Stop()
+	if agent.auditLog != nil {
+		agent.auditLog.close()
+	}
 	if !(agent.EnableIPV6) {
 		agent.stopPythonDriver()
 	}
@@ -197,6 +380,7 @@ func (agent *Agent) IsBigIPAppServicesAvailable() error {
 	agent.bigIPAS3Version = bigIPAS3Version
 	if bigIPAS3Version >= as3SupportedVersion && bigIPAS3Version <= as3Version {
 		log.Debugf("[AS3] BIGIP is serving with AS3 version: %v", version)
+		agent.pinAS3SchemaVersion(schemaVersion)
 		return nil
 	}
 
@@ -207,6 +391,7 @@ func (agent *Agent) IsBigIPAppServicesAvailable() error {
 		am.as3Release = am.as3Version + "-" + as3Build
 		log.Debugf("[AS3] BIGIP is serving with AS3 version: %v", bigIPAS3Version)
 		agent.AS3VersionInfo = am
+		agent.pinAS3SchemaVersion(schemaVersion)
 		return nil
 	}
 
@@ -232,6 +417,10 @@ func (agent *Agent) PostConfig(rsConfig ResourceConfigRequest) {
 // removeDeletedTenantsForBigIP will check the tenant exists on bigip or not
 // if tenant exists and rsConfig does not have tenant, update the tenant with empty PartitionConfig
 func (agent *Agent) removeDeletedTenantsForBigIP(rsConfig *ResourceConfigRequest, cisLabel string) {
+	if !agent.isDeviceReachable() {
+		log.Debugf("[AS3] BIG-IP credentials are not provided, skipping the removal of the deleted tenants")
+		return
+	}
 	//Fetching the latest BIGIP Configuration and identify if any tenant needs to be deleted
 	as3Config, err := agent.PostManager.GetAS3DeclarationFromBigIP()
 	if err != nil {
@@ -255,21 +444,32 @@ func (agent *Agent) removeDeletedTenantsForBigIP(rsConfig *ResourceConfigRequest
 func (agent *Agent) agentWorker() {
 	for rsConfig := range agent.postChan {
 		// For the very first post after starting controller, need not wait to post
-		if !agent.firstPost && agent.AS3PostDelay != 0 {
+		if postDelay := agent.getAS3PostDelay(); !agent.firstPost && postDelay != 0 {
 			// Time (in seconds) that CIS waits to post the AS3 declaration to BIG-IP.
-			log.Debugf("[AS3] Delaying post to BIG-IP for %v seconds ", agent.AS3PostDelay)
-			_ = <-time.After(time.Duration(agent.AS3PostDelay) * time.Second)
+			log.Debugf("[AS3] Delaying post to BIG-IP for %v seconds ", postDelay)
+			_ = <-time.After(time.Duration(postDelay) * time.Second)
 		}
 
 		// If there are no retries going on in parallel, acquiring lock will be straight forward.
 		// Otherwise, we will wait for retryWorker to complete its current iteration
 		agent.declUpdate.Lock()
 
+		// REST framework of BIG-IP recovers without the load of the declarations
+		agent.backOffRESTFramework()
+
 		// Fetch the latest config from channel
 		select {
 		case rsConfig = <-agent.postChan:
 		case <-time.After(1 * time.Microsecond):
 		}
+		agent.lastConfig = rsConfig
+
+		if agent.isCircuitOpen() {
+			// configuration is posted with the full-state sync once BIG-IP is reachable
+			log.Warningf("[AS3] BIG-IP is unreachable, deferring the post of the configuration")
+			agent.declUpdate.Unlock()
+			continue
+		}
 
 		if !(agent.EnableIPV6) && agent.ccclGTMAgent {
 			agent.PostGTMConfig(rsConfig)
@@ -282,6 +482,15 @@ func (agent *Agent) agentWorker() {
 			continue
 		}
 
+		if tenantErrs := agent.validateAS3Declaration(decl); len(tenantErrs) > 0 {
+			decl = agent.removeInvalidTenants(tenantErrs)
+			if len(agent.incomingTenantDeclMap) == 0 {
+				agent.notifyRscStatusHandler(rsConfig.reqId, true)
+				agent.declUpdate.Unlock()
+				continue
+			}
+		}
+
 		if agent.HAMode {
 			// if endPoint is not empty means, cis is running in secondary mode
 			// check if the primary cis is up and running
@@ -313,8 +522,8 @@ func (agent *Agent) agentWorker() {
 
 		for tenant := range agent.incomingTenantDeclMap {
 			// CIS with AS3 doesnt allow write to Common partition.So objects in common partition
-			// should not be updated or deleted by CIS. So removing from tenant map
-			if tenant != "Common" {
+			// should not be updated or deleted by CIS except the shared bundle. So removing from tenant map
+			if tenant != "Common" || agent.isSharedBundleTenant(rsConfig, tenant) {
 				if _, ok := agent.tenantPriorityMap[tenant]; ok {
 					priorityTenants = append(priorityTenants, tenant)
 				} else {
@@ -326,15 +535,52 @@ func (agent *Agent) agentWorker() {
 
 		// Update the priority tenants first
 		if len(priorityTenants) > 0 {
-			agent.postTenantsDeclaration(decl, rsConfig, priorityTenants)
+			agent.postTenants(decl, rsConfig, priorityTenants)
 		}
 		// Updating the remaining tenants
-		agent.postTenantsDeclaration(decl, rsConfig, updatedTenants)
+		agent.postTenants(decl, rsConfig, updatedTenants)
 
 		agent.declUpdate.Unlock()
 	}
 }
 
+// exportWorker blocks on postChan and exports the configuration to a target other than BIG-IP.
+// Failed configuration is retried until it is exported or a new configuration is received
+func (agent *Agent) exportWorker(export func(rsConfig ResourceConfigRequest) (map[string]struct{}, bool)) {
+	var rsConfig ResourceConfigRequest
+	var retry <-chan time.Time
+	for {
+		reqId := 0
+		select {
+		case cfg, ok := <-agent.postChan:
+			if !ok {
+				return
+			}
+			rsConfig = cfg
+			reqId = cfg.reqId
+		case <-retry:
+			log.Debugf("Exporting the failed configuration")
+		}
+
+		failedPartitions, ok := export(rsConfig)
+		retry = nil
+		if !ok {
+			retry = time.After(timeoutMedium)
+		}
+		if reqId == 0 {
+			// request initiated from a retry, the last request is processed completely
+			agent.respChan <- resourceStatusMeta{reqId, failedPartitions, nil}
+			continue
+		}
+		// Always push latest id to channel
+		select {
+		case agent.respChan <- resourceStatusMeta{reqId, failedPartitions, nil}:
+		case <-agent.respChan:
+			agent.respChan <- resourceStatusMeta{reqId, failedPartitions, nil}
+		}
+	}
+}
+
 // Post the tenants declaration
 func (agent *Agent) postTenantsDeclaration(decl as3Declaration, rsConfig ResourceConfigRequest, tenants []string) {
 	cfg := agentConfig{
@@ -344,6 +590,7 @@ func (agent *Agent) postTenantsDeclaration(decl as3Declaration, rsConfig Resourc
 	}
 
 	agent.publishConfig(cfg)
+	agent.auditDeclaration(auditPost, &rsConfig, tenants, agent.incomingTenantDeclMap)
 
 	// Don't update ARPs if disableARP is set to true
 	if !agent.disableARP {
@@ -376,9 +623,18 @@ func (agent *Agent) notifyRscStatusHandler(id int, overwriteCfg bool) {
 	rscUpdateMeta := resourceStatusMeta{
 		id,
 		make(map[string]struct{}),
+		make(map[string]string),
+	}
+	for tenant, cfg := range agent.retryTenantDeclMap {
+		rscUpdateMeta.failedTenants[tenant] = struct{}{}
+		if cfg.message != "" {
+			rscUpdateMeta.tenantErrors[tenant] = cfg.message
+		}
 	}
-	for tenant := range agent.retryTenantDeclMap {
+	// tenants with the invalid objects are not posted to BIG-IP
+	for tenant, message := range agent.invalidTenantErrors {
 		rscUpdateMeta.failedTenants[tenant] = struct{}{}
+		rscUpdateMeta.tenantErrors[tenant] = message
 	}
 	// If triggerred from retry block, process the previous successful request completely
 	if !overwriteCfg {
@@ -407,7 +663,8 @@ func (agent *Agent) updateRetryMap(tenant string, resp tenantResponse, tenDecl i
 	} else {
 		agent.retryTenantDeclMap[tenant] = &tenantParams{
 			tenDecl,
-			tenantResponse{resp.agentResponseCode, resp.taskId, false},
+			tenantResponse{resp.agentResponseCode, resp.taskId, false, resp.message},
+			0,
 		}
 	}
 }
@@ -421,7 +678,12 @@ func (agent *Agent) updateARPsForPoolMembers(rsConfig ResourceConfigRequest) {
 	for _, poolMem := range allPoolMembers {
 		allPoolMems = append(
 			allPoolMems,
-			rsc.Member(poolMem),
+			rsc.Member{
+				Address: poolMem.Address,
+				Port:    poolMem.Port,
+				SvcPort: poolMem.SvcPort,
+				Session: poolMem.Session,
+			},
 		)
 	}
 	if agent.EventChan != nil {
@@ -483,7 +745,12 @@ func (agent *Agent) retryWorker() {
 
 	for range agent.retryChan {
 
-		for len(agent.retryTenantDeclMap) != 0 {
+		for agent.hasPendingRetries() {
+
+			if agent.isCircuitOpen() {
+				// failed tenants are posted with the full-state sync once BIG-IP is reachable
+				break
+			}
 
 			if agent.HAMode {
 				// if endPoint is not empty -> cis is running in secondary mode
@@ -500,13 +767,11 @@ func (agent *Agent) retryWorker() {
 			agent.declUpdate.Lock()
 
 			// If we had a delay in acquiring lock, re-check if we have any tenants to be retried
-			if len(agent.retryTenantDeclMap) == 0 {
+			if !agent.hasPendingRetries() {
 				agent.declUpdate.Unlock()
 				break
 			}
 
-			log.Debugf("[AS3] Posting failed tenants configuration in %v seconds", timeoutMedium)
-
 			//If there are any 201 tenants, poll for its status
 			agent.pollTenantStatus()
 
@@ -527,29 +792,55 @@ func (agent *Agent) retryFailedTenant() {
 	retryDecl := make(map[string]as3Tenant)
 
 	agent.tenantResponseMap = make(map[string]tenantResponse)
+	// retries of the posted tenants, the interval of the retry depends on the retries of the tenants
+	retries := make(map[string]int)
+	minRetries := -1
 
 	for tenant, cfg := range agent.retryTenantDeclMap {
 		// So, when we call updateTenantResponse, we have to retain failed agentResponseCodes and taskId's correctly
-		agent.tenantResponseMap[tenant] = tenantResponse{agentResponseCode: cfg.agentResponseCode, taskId: cfg.taskId}
-		if cfg.taskId == "" {
+		agent.tenantResponseMap[tenant] = tenantResponse{agentResponseCode: cfg.agentResponseCode, taskId: cfg.taskId,
+			message: cfg.message}
+		if cfg.taskId == "" && !agent.isRetryExhausted(cfg) {
 			retryTenants = append(retryTenants, tenant)
 			retryDecl[tenant] = cfg.as3Decl.(as3Tenant)
+			retries[tenant] = cfg.retries + 1
+			if minRetries == -1 || cfg.retries < minRetries {
+				minRetries = cfg.retries
+			}
 		}
 	}
 
 	if len(retryTenants) > 0 {
 		// Until all accepted tenants are not processed, we do not want to re-post failed tenants since we will anyways get a 503
-		cfg := agentConfig{
-			data:      string(agent.createAS3Declaration(retryDecl)),
-			as3APIURL: agent.getAS3APIURL(retryTenants),
-			id:        0,
-		}
+		decls, oversized := agent.splitDeclaration(agent.createAS3Declaration(retryDecl), retryDecl, retryTenants)
+		interval := agent.getRetryInterval(minRetries)
+		log.Debugf("[AS3] Posting failed tenants configuration in %v", interval)
 		// Ignoring timeouts for custom errors
-		<-time.After(timeoutMedium)
+		<-time.After(interval)
+		agent.backOffRESTFramework()
 
-		agent.postConfig(&cfg)
+		for _, tenantsDecl := range decls {
+			cfg := agentConfig{
+				data:      string(tenantsDecl.decl),
+				as3APIURL: agent.getAS3APIURL(tenantsDecl.tenants),
+				id:        0,
+			}
+			agent.postConfig(&cfg)
+			agent.auditDeclaration(auditRetry, nil, tenantsDecl.tenants, retryDecl)
+		}
+		agent.setOversizedTenantResponse(oversized)
 
 		agent.updateTenantResponse(false)
+
+		for tenant, count := range retries {
+			if cfg, ok := agent.retryTenantDeclMap[tenant]; ok {
+				cfg.retries = count
+				if agent.isRetryExhausted(cfg) && cfg.agentResponseCode != http.StatusRequestEntityTooLarge {
+					log.Errorf("[AS3] Failed to post tenant %v after %v retries, tenant is posted again with "+
+						"the next configuration update", tenant, count)
+				}
+			}
+		}
 	}
 
 }
@@ -564,7 +855,8 @@ func (agent *Agent) pollTenantStatus() {
 
 	for tenant, cfg := range agent.retryTenantDeclMap {
 		// So, when we call updateTenantResponse, we have to retain failed agentResponseCodes and taskId's correctly
-		agent.tenantResponseMap[tenant] = tenantResponse{agentResponseCode: cfg.agentResponseCode, taskId: cfg.taskId}
+		agent.tenantResponseMap[tenant] = tenantResponse{agentResponseCode: cfg.agentResponseCode, taskId: cfg.taskId,
+			message: cfg.message}
 		if cfg.taskId != "" {
 			if _, found := acceptedTenantIds[cfg.taskId]; !found {
 				acceptedTenantIds[cfg.taskId] = struct{}{}
@@ -624,9 +916,10 @@ func (agent *Agent) PostGTMConfig(config ResourceConfigRequest) {
 
 // Creates AS3 adc only for tenants with updated configuration
 func (agent *Agent) createTenantAS3Declaration(config ResourceConfigRequest) as3Declaration {
-	// Re-initialise incomingTenantDeclMap map and tenantPriorityMap for each new config request
+	// Re-initialise incomingTenantDeclMap map, tenantPriorityMap and invalidTenantErrors for each new config request
 	agent.incomingTenantDeclMap = make(map[string]as3Tenant)
 	agent.tenantPriorityMap = make(map[string]int)
+	agent.invalidTenantErrors = make(map[string]string)
 	for tenant, cfg := range agent.createAS3LTMAndGTMConfigADC(config) {
 		if !reflect.DeepEqual(cfg, agent.cachedTenantDeclMap[tenant]) ||
 			(agent.PrimaryClusterHealthProbeParams.EndPoint != "" && agent.PrimaryClusterHealthProbeParams.statusChanged) {
@@ -669,8 +962,13 @@ func (agent *Agent) createAS3Declaration(tenantDeclMap map[string]as3Tenant) as3
 	controlObj["userAgent"] = agent.userAgent
 	adc["controls"] = controlObj
 
+	if agent.PostManager != nil && agent.BIGIQURL != "" {
+		// BIG-IQ deploys the declaration to the target BIG-IP
+		adc["target"] = agent.getBigIQTarget()
+	}
+
 	for tenant, decl := range tenantDeclMap {
-		adc[tenant] = decl
+		adc[tenant] = agent.withTenantLockKey(tenant, decl)
 	}
 	decl, err := json.Marshal(as3Config)
 	if err != nil {
@@ -680,6 +978,24 @@ func (agent *Agent) createAS3Declaration(tenantDeclMap map[string]as3Tenant) as3
 	return as3Declaration(decl)
 }
 
+// withTenantLockKey returns the declaration of the tenant with its optimistic lock key,
+// declaration is copied to keep the lock key out of the cached tenant declarations
+func (agent *Agent) withTenantLockKey(tenant string, decl as3Tenant) as3Tenant {
+	if agent.PostManager == nil || !agent.AS3OptimisticLock {
+		return decl
+	}
+	key, ok := agent.tenantLockKeys[tenant]
+	if !ok {
+		return decl
+	}
+	lockedDecl := make(as3Tenant, len(decl)+1)
+	for k, v := range decl {
+		lockedDecl[k] = v
+	}
+	lockedDecl["optimisticLockKey"] = key
+	return lockedDecl
+}
+
 func (agent *Agent) createAS3LTMAndGTMConfigADC(config ResourceConfigRequest) as3ADC {
 	adc := agent.createAS3LTMConfigADC(config)
 	if !agent.ccclGTMAgent {
@@ -747,6 +1063,14 @@ func (agent *Agent) createAS3GTMConfigADC(config ResourceConfigRequest, adc as3A
 					LBModeFallback: pool.LBModeFallBack,
 					Members:        make([]as3GSLBPoolMemberA, 0, len(pool.Members)),
 					Monitors:       make([]as3ResourcePointer, 0, len(pool.Monitors)),
+					TTL:            pool.TTL,
+				}
+				if pool.MinimumMonitors != nil {
+					if pool.MinimumMonitors.Type == intstr.String {
+						gslbPool.MinimumMonitors = pool.MinimumMonitors.StrVal
+					} else {
+						gslbPool.MinimumMonitors = pool.MinimumMonitors.IntVal
+					}
 				}
 
 				for _, mem := range pool.Members {
@@ -761,12 +1085,14 @@ func (agent *Agent) createAS3GTMConfigADC(config ResourceConfigRequest, adc as3A
 
 				for _, mon := range pool.Monitors {
 					gslbMon := as3GSLBMonitor{
-						Class:    "GSLB_Monitor",
-						Interval: mon.Interval,
-						Type:     mon.Type,
-						Send:     mon.Send,
-						Receive:  mon.Recv,
-						Timeout:  mon.Timeout,
+						Class:         "GSLB_Monitor",
+						Interval:      mon.Interval,
+						Type:          mon.Type,
+						Send:          mon.Send,
+						Receive:       mon.Recv,
+						Timeout:       mon.Timeout,
+						ProbeTimeout:  mon.ProbeTimeout,
+						SNIServerName: mon.SNIServerName,
 					}
 
 					gslbPool.Monitors = append(gslbPool.Monitors, as3ResourcePointer{
@@ -814,7 +1140,7 @@ func (agent *Agent) createAS3LTMConfigADC(config ResourceConfigRequest) as3ADC {
 			agent.tenantPriorityMap[tenantName] = *(partitionConfig.Priority)
 		}
 		partitionConfig.PriorityMutex.RUnlock()
-		if len(partitionConfig.ResourceMap) == 0 {
+		if !partitionConfig.hasConfig() {
 			// Remove partition
 			adc[tenantName] = getDeletedTenantDeclaration(agent.Partition, tenantName, cisLabel)
 			continue
@@ -837,6 +1163,17 @@ func (agent *Agent) createAS3LTMConfigADC(config ResourceConfigRequest) as3ADC {
 
 		processDataGroupForAS3(partitionConfig.ResourceMap, sharedApp)
 
+		// Process the shared objects of the DataGroup, TLSCertificate and HealthMonitor resources
+		processSharedDataGroupsForAS3(partitionConfig.DataGroups, sharedApp)
+
+		processSharedCertificatesForAS3(partitionConfig.Certificates, sharedApp)
+
+		processSharedMonitorsForAS3(partitionConfig.Monitors, sharedApp)
+
+		if agent.sharePools {
+			agent.setSharedPools(tenantName, shareIdenticalPools(partitionConfig.ResourceMap, sharedApp))
+		}
+
 		// Create AS3 Tenant
 		tenantDecl := as3Tenant{
 			"class":              "Tenant",
@@ -846,6 +1183,10 @@ func (agent *Agent) createAS3LTMConfigADC(config ResourceConfigRequest) as3ADC {
 		}
 		adc[tenantName] = tenantDecl
 	}
+	// Common tenant holds only the shared bundle, it is flushed once the installed bundle is removed
+	if _, ok := agent.cachedTenantDeclMap[sharedBundlePartition]; ok || config.sharedBundle != nil {
+		adc[sharedBundlePartition] = createSharedBundleTenantDecl(config.sharedBundle)
+	}
 	return adc
 }
 
@@ -894,29 +1235,66 @@ func processDataGroupForAS3(rsMap ResourceMap, sharedApp as3Application) {
 		}
 		for _, idg := range rsCfg.IntDgMap {
 			for _, dg := range idg {
-				dataGroupRecord, found := sharedApp[dg.Name]
-				if !found {
-					dgMap := &as3DataGroup{}
-					dgMap.Class = "Data_Group"
-					dgMap.KeyDataType = dg.Type
-					for _, record := range dg.Records {
-						dgMap.Records = append(dgMap.Records, as3Record{Key: record.Name, Value: record.Data})
-					}
-					// sort above create dgMap records.
-					sort.Slice(dgMap.Records, func(i, j int) bool { return (dgMap.Records[i].Key < dgMap.Records[j].Key) })
-					sharedApp[dg.Name] = dgMap
-				} else {
-					for _, record := range dg.Records {
-						sharedApp[dg.Name].(*as3DataGroup).Records = append(dataGroupRecord.(*as3DataGroup).Records, as3Record{Key: record.Name, Value: record.Data})
-					}
-					// sort above created
-					sort.Slice(sharedApp[dg.Name].(*as3DataGroup).Records,
-						func(i, j int) bool {
-							return (sharedApp[dg.Name].(*as3DataGroup).Records[i].Key <
-								sharedApp[dg.Name].(*as3DataGroup).Records[j].Key)
-						})
-				}
+				addDataGroupForAS3(dg, sharedApp)
+			}
+		}
+	}
+}
+
+// addDataGroupForAS3 creates the data group, records are merged with the data group of the same name
+func addDataGroupForAS3(dg *InternalDataGroup, sharedApp as3Application) {
+	dataGroupRecord, found := sharedApp[dg.Name]
+	if !found {
+		dgMap := &as3DataGroup{}
+		dgMap.Class = "Data_Group"
+		dgMap.KeyDataType = dg.Type
+		for _, record := range dg.Records {
+			dgMap.Records = append(dgMap.Records, as3Record{Key: record.Name, Value: record.Data})
+		}
+		// sort above create dgMap records.
+		sort.Slice(dgMap.Records, func(i, j int) bool { return (dgMap.Records[i].Key < dgMap.Records[j].Key) })
+		sharedApp[dg.Name] = dgMap
+	} else {
+		for _, record := range dg.Records {
+			sharedApp[dg.Name].(*as3DataGroup).Records = append(dataGroupRecord.(*as3DataGroup).Records, as3Record{Key: record.Name, Value: record.Data})
+		}
+		// sort above created
+		sort.Slice(sharedApp[dg.Name].(*as3DataGroup).Records,
+			func(i, j int) bool {
+				return (sharedApp[dg.Name].(*as3DataGroup).Records[i].Key <
+					sharedApp[dg.Name].(*as3DataGroup).Records[j].Key)
+			})
+	}
+}
+
+// processSharedDataGroupsForAS3 creates the data groups of the DataGroups
+func processSharedDataGroupsForAS3(dataGroups map[string]*InternalDataGroup, sharedApp as3Application) {
+	for _, dg := range dataGroups {
+		addDataGroupForAS3(dg, sharedApp)
+	}
+}
+
+// processSharedMonitorsForAS3 creates the monitors of the HealthMonitors shared by the pools
+func processSharedMonitorsForAS3(monitors map[string]Monitor, sharedApp as3Application) {
+	for _, monitor := range monitors {
+		createMonitorsDecl([]Monitor{monitor}, sharedApp)
+	}
+}
+
+// processSharedCertificatesForAS3 creates the certificates and CA bundles of the TLSCertificates
+func processSharedCertificatesForAS3(certs map[string]*SharedCertificate, sharedApp as3Application) {
+	for _, sc := range certs {
+		if sc.Type == CABundleType {
+			sharedApp[sc.Name] = &as3CABundle{
+				Class:  "CA_Bundle",
+				Bundle: sc.Certificate,
 			}
+			continue
+		}
+		sharedApp[sc.Name] = &as3Certificate{
+			Class:       "Certificate",
+			Certificate: sc.Certificate,
+			PrivateKey:  sc.PrivateKey,
 		}
 	}
 }
@@ -980,6 +1358,13 @@ func createPoolDecl(cfg *ResourceConfig, sharedApp as3Application, shareNodes bo
 		pool.Class = "Pool"
 		pool.ReselectTries = v.ReselectTries
 		pool.ServiceDownAction = v.ServiceDownAction
+		if v.MinimumMonitors != nil {
+			if v.MinimumMonitors.Type == intstr.String {
+				pool.MinimumMonitors = v.MinimumMonitors.StrVal
+			} else {
+				pool.MinimumMonitors = v.MinimumMonitors.IntVal
+			}
+		}
 		poolMemberSet := make(map[PoolMember]struct{})
 		for _, val := range v.Members {
 			// Skip duplicate pool members
@@ -994,6 +1379,12 @@ func createPoolDecl(cfg *ResourceConfig, sharedApp as3Application, shareNodes bo
 			if shareNodes {
 				member.ShareNodes = shareNodes
 			}
+			member.PriorityGroup = val.PriorityGroup
+			member.Ratio = val.Ratio
+			// disabled members serve only the existing connections until they are drained
+			if val.Session == "user-disabled" {
+				member.AdminState = "disable"
+			}
 			pool.Members = append(pool.Members, member)
 		}
 		for _, val := range v.MonitorNames {
@@ -1024,11 +1415,17 @@ func updateVirtualToHTTPS(v *as3Service) {
 // Process Irules for CRD
 func processIrulesForCRD(cfg *ResourceConfig, svc *as3Service) {
 	var IRules []interface{}
+	processed := make(map[string]struct{})
 	// Skip processing IRules for "None" value
 	for _, v := range cfg.Virtual.IRules {
 		if v == "none" {
 			continue
 		}
+		// iRules are attached in the order of appearance, skip the repeated ones
+		if _, ok := processed[v]; ok {
+			continue
+		}
+		processed[v] = struct{}{}
 		splits := strings.Split(v, "/")
 		iRuleName := splits[len(splits)-1]
 
@@ -1042,7 +1439,15 @@ func processIrulesForCRD(cfg *ResourceConfig, svc *as3Service) {
 		if strings.HasSuffix(iRuleNoPort, HttpRedirectIRuleName) ||
 			strings.HasSuffix(iRuleNoPort, HttpRedirectNoHostIRuleName) ||
 			strings.HasSuffix(iRuleName, TLSIRuleName) ||
-			strings.HasSuffix(iRuleName, ABPathIRuleName) {
+			strings.HasSuffix(iRuleName, ABPathIRuleName) ||
+			strings.HasSuffix(iRuleName, RegexPathIRuleName) ||
+			strings.HasSuffix(iRuleName, ErrorPageIRuleName) ||
+			strings.HasSuffix(iRuleName, ACMEChallengeIRuleName) ||
+			strings.HasSuffix(iRuleName, MaintenanceIRuleName) ||
+			strings.HasSuffix(iRuleName, PoolMirrorIRuleName) ||
+			strings.HasSuffix(iRuleName, RouteTrafficIRuleName) ||
+			strings.HasSuffix(iRuleName, SNIRoutesIRuleName) ||
+			strings.HasSuffix(iRuleName, ProxyProtocolIRuleName) {
 
 			IRules = append(IRules, iRuleName)
 		} else {
@@ -1060,13 +1465,13 @@ func createServiceDecl(cfg *ResourceConfig, sharedApp as3Application, tenant str
 	svc := &as3Service{}
 	numPolicies := len(cfg.Virtual.Policies)
 	switch {
-	case numPolicies == 1:
+	case numPolicies == 1 && len(cfg.Virtual.BigIPPolicies) == 0:
 		policyName := cfg.Virtual.Policies[0].Name
 		svc.PolicyEndpoint = fmt.Sprintf("/%s/%s/%s",
 			tenant,
 			as3SharedApplication,
 			policyName)
-	case numPolicies > 1:
+	case numPolicies+len(cfg.Virtual.BigIPPolicies) > 0:
 		var peps []as3ResourcePointer
 		for _, pep := range cfg.Virtual.Policies {
 			peps = append(
@@ -1080,6 +1485,10 @@ func createServiceDecl(cfg *ResourceConfig, sharedApp as3Application, tenant str
 				},
 			)
 		}
+		// LTM policies of BIG-IP follow the policies of CIS
+		for _, policy := range cfg.Virtual.BigIPPolicies {
+			peps = append(peps, as3ResourcePointer{BigIP: policy})
+		}
 		svc.PolicyEndpoint = peps
 	}
 	// Attach the default pool if pool name is present for virtual.
@@ -1105,13 +1514,11 @@ func createServiceDecl(cfg *ResourceConfig, sharedApp as3Application, tenant str
 		svc.TranslateServerPort = true
 		svc.Class = "Service_HTTP"
 	} else {
-		if len(cfg.Virtual.PersistenceProfile) == 0 {
-			cfg.Virtual.PersistenceProfile = "tls-session-id"
-		}
 		svc.Class = "Service_TCP"
 	}
 
-	svc.addPersistenceMethod(cfg.Virtual.PersistenceProfile)
+	svc.addPersistenceMethod(cfg.getPersistenceProfile())
+	svc.addMirroring(cfg, sharedApp, tenant)
 
 	if len(cfg.Virtual.ProfileDOS) > 0 {
 		svc.ProfileDOS = &as3ResourcePointer{
@@ -1154,26 +1561,7 @@ func createServiceDecl(cfg *ResourceConfig, sharedApp as3Application, tenant str
 		}
 	}
 
-	if len(cfg.Virtual.TCP.Client) > 0 || len(cfg.Virtual.TCP.Server) > 0 {
-		if cfg.Virtual.TCP.Client == "" {
-			log.Errorf("[AS3] resetting ProfileTCP as client profile doesnt co-exist with TCP Server Profile, Please include client TCP Profile ")
-		}
-		if cfg.Virtual.TCP.Server == "" {
-			svc.ProfileTCP = &as3ResourcePointer{
-				BigIP: fmt.Sprintf("%v", cfg.Virtual.TCP.Client),
-			}
-		}
-		if cfg.Virtual.TCP.Client != "" && cfg.Virtual.TCP.Server != "" {
-			svc.ProfileTCP = as3ProfileTCP{
-				Ingress: &as3ResourcePointer{
-					BigIP: fmt.Sprintf("%v", cfg.Virtual.TCP.Client),
-				},
-				Egress: &as3ResourcePointer{
-					BigIP: fmt.Sprintf("%v", cfg.Virtual.TCP.Server),
-				},
-			}
-		}
-	}
+	svc.addTCPProfile(cfg, sharedApp)
 
 	if len(cfg.Virtual.ProfileMultiplex) > 0 {
 		svc.ProfileMultiplex = &as3ResourcePointer{
@@ -1207,6 +1595,24 @@ func createServiceDecl(cfg *ResourceConfig, sharedApp as3Application, tenant str
 			}
 		}
 	}
+	// HTTP profile is not created for the passthrough virtuals as they don't process HTTP
+	if cfg.Virtual.HTTPProfile != (HTTPProfile{}) && cfg.Virtual.TLSTermination == TLSPassthrough {
+		log.Warningf("[AS3] HTTP profile settings are not supported with passthrough virtual %v, skipping the HTTP profile",
+			cfg.Virtual.Name)
+	} else if cfg.Virtual.HTTPProfile != (HTTPProfile{}) {
+		name := cfg.Virtual.Name + "_http_profile"
+		sharedApp[name] = &as3HTTPProfile{
+			Class:            "HTTP_Profile",
+			XForwardedFor:    cfg.Virtual.HTTPProfile.InsertXForwardedFor,
+			MaxHeaderSize:    cfg.Virtual.HTTPProfile.MaxHeaderSize,
+			MaxHeaderCount:   cfg.Virtual.HTTPProfile.MaxHeaderCount,
+			RewriteRedirects: cfg.Virtual.HTTPProfile.RewriteRedirects,
+			ProxyType:        cfg.Virtual.HTTPProfile.ProxyType,
+		}
+		svc.ProfileHTTP = &as3ResourcePointer{
+			Use: name,
+		}
+	}
 
 	//Attaching WAF policy
 	if cfg.Virtual.WAF != "" {
@@ -1218,35 +1624,13 @@ func createServiceDecl(cfg *ResourceConfig, sharedApp as3Application, tenant str
 	virtualAddress, port := extractVirtualAddressAndPort(cfg.Virtual.Destination)
 	// verify that ip address and port exists.
 	if virtualAddress != "" && port != 0 {
-		if len(cfg.ServiceAddress) == 0 {
-			va := append(svc.VirtualAddresses, virtualAddress)
-			if len(cfg.Virtual.AdditionalVirtualAddresses) > 0 {
-				for _, val := range cfg.Virtual.AdditionalVirtualAddresses {
-					va = append(va, val)
-				}
-			}
-			svc.VirtualAddresses = va
-			svc.VirtualPort = port
-		} else {
-			//Attach Service Address
-			serviceAddressName := createServiceAddressDecl(cfg, virtualAddress, sharedApp)
-			sa := &as3ResourcePointer{
-				Use: serviceAddressName,
-			}
-			svc.VirtualAddresses = append(svc.VirtualAddresses, sa)
-			if len(cfg.Virtual.AdditionalVirtualAddresses) > 0 {
-				for _, val := range cfg.Virtual.AdditionalVirtualAddresses {
-					//Attach Service Address
-					serviceAddressName := createServiceAddressDecl(cfg, val, sharedApp)
-					//handle additional service addresses
-					asa := &as3ResourcePointer{
-						Use: serviceAddressName,
-					}
-					svc.VirtualAddresses = append(svc.VirtualAddresses, asa)
-				}
-			}
-			svc.VirtualPort = port
+		virtualAddress = cfg.Virtual.getVirtualAddressWithMask(virtualAddress)
+		svc.VirtualAddresses = append(svc.VirtualAddresses, createVirtualAddressDecl(cfg, virtualAddress, sharedApp))
+		//handle additional service addresses
+		for _, val := range cfg.Virtual.AdditionalVirtualAddresses {
+			svc.VirtualAddresses = append(svc.VirtualAddresses, createVirtualAddressDecl(cfg, val, sharedApp))
 		}
+		svc.VirtualPort = port
 	}
 	if cfg.Virtual.HttpMrfRoutingEnabled != nil {
 		//set HttpMrfRoutingEnabled
@@ -1259,31 +1643,204 @@ func createServiceDecl(cfg *ResourceConfig, sharedApp as3Application, tenant str
 			BigIP: cfg.Virtual.AnalyticsProfiles.HTTPAnalyticsProfile,
 		}
 	}
+	if cfg.Virtual.AnalyticsProfiles.TCPAnalyticsProfile != "" {
+		svc.TCPAnalyticsProfile = &as3ResourcePointer{
+			BigIP: cfg.Virtual.AnalyticsProfiles.TCPAnalyticsProfile,
+		}
+	}
 	//set websocket profile
 	if cfg.Virtual.ProfileWebSocket != "" {
 		svc.ProfileWebSocket = &as3ResourcePointer{
 			BigIP: cfg.Virtual.ProfileWebSocket,
 		}
 	}
+	//set APM access profile and per-request policy
+	if cfg.Virtual.ProfileAccess != "" {
+		svc.ProfileAccess = &as3ResourcePointer{
+			BigIP: cfg.Virtual.ProfileAccess,
+		}
+		if cfg.Virtual.PolicyPerRequestAccess != "" {
+			svc.PolicyPerRequestAccess = &as3ResourcePointer{
+				BigIP: cfg.Virtual.PolicyPerRequestAccess,
+			}
+		}
+	}
+	svc.addRequestLogging(cfg, sharedApp)
+	//set compression and web acceleration profiles
+	if cfg.Virtual.ProfileHTTPCompression != "" {
+		svc.ProfileHTTPCompression = getProfileParam(cfg.Virtual.ProfileHTTPCompression)
+	}
+	if cfg.Virtual.ProfileWebAcceleration != "" {
+		svc.ProfileHTTPAcceleration = getProfileParam(cfg.Virtual.ProfileWebAcceleration)
+	}
 	processCommonDecl(cfg, svc)
 	sharedApp[cfg.Virtual.Name] = svc
 }
 
+// addRequestLogging attaches the Traffic_Log_Profile logging the requests
+// to the pool of remote syslog servers
+func (svc *as3Service) addRequestLogging(cfg *ResourceConfig, sharedApp as3Application) {
+	logging := cfg.Virtual.RequestLogging
+	if len(logging.Servers) == 0 {
+		return
+	}
+	poolName := cfg.Virtual.Name + "_request_log_pool"
+	sharedApp[poolName] = &as3Pool{
+		Class: "Pool",
+		Members: []as3PoolMember{{
+			AddressDiscovery: "static",
+			ServerAddresses:  logging.Servers,
+			ServicePort:      logging.Port,
+		}},
+	}
+
+	settings := as3TrafficLogSettings{
+		RequestEnabled:  true,
+		RequestProtocol: "mds-udp",
+		RequestPool:     &as3ResourcePointer{Use: poolName},
+		RequestTemplate: logging.Template,
+	}
+	if logging.Protocol == "tcp" {
+		settings.RequestProtocol = "mds-tcp"
+	}
+	if settings.RequestTemplate == "" {
+		settings.RequestTemplate = DefaultRequestLogTemplate
+	}
+	name := cfg.Virtual.Name + "_request_log"
+	sharedApp[name] = &as3TrafficLogProfile{
+		Class:           "Traffic_Log_Profile",
+		RequestSettings: settings,
+	}
+	svc.ProfileTrafficLog = &as3ResourcePointer{
+		Use: name,
+	}
+}
+
+// getProfileParam returns the reference of an existing BIG-IP profile, or the AS3
+// built-in profile like basic as is
+func getProfileParam(profile string) as3MultiTypeParam {
+	if strings.HasPrefix(profile, "/") {
+		return &as3ResourcePointer{
+			BigIP: profile,
+		}
+	}
+	return profile
+}
+
+// addTCPProfile attaches the client and server TCP profiles of the virtual. TCP_Profile
+// with the inline settings is attached on the client side, or on the server side when
+// a client TCP profile is referred
+func (svc *as3Service) addTCPProfile(cfg *ResourceConfig, sharedApp as3Application) {
+	tcp := cfg.Virtual.TCP
+	var ingress, egress *as3ResourcePointer
+	if tcp.Client != "" {
+		ingress = &as3ResourcePointer{
+			BigIP: fmt.Sprintf("%v", tcp.Client),
+		}
+	}
+	if tcp.Server != "" {
+		egress = &as3ResourcePointer{
+			BigIP: fmt.Sprintf("%v", tcp.Server),
+		}
+	}
+	if tcp.IdleTimeout != 0 || tcp.Nagle != "" || tcp.CongestionControl != "" {
+		if ingress != nil && egress != nil {
+			log.Warningf("[AS3] Ignoring the TCP profile settings of %v as client and server TCP profiles are referred", cfg.Virtual.Name)
+		} else {
+			name := cfg.Virtual.Name + "_tcp_profile"
+			sharedApp[name] = &as3TCPProfile{
+				Class:             "TCP_Profile",
+				IdleTimeout:       tcp.IdleTimeout,
+				Nagle:             tcp.Nagle,
+				CongestionControl: tcp.CongestionControl,
+			}
+			if ingress == nil {
+				ingress = &as3ResourcePointer{Use: name}
+			} else {
+				egress = &as3ResourcePointer{Use: name}
+			}
+		}
+	}
+
+	switch {
+	case ingress == nil && egress != nil:
+		log.Errorf("[AS3] resetting ProfileTCP as client profile doesnt co-exist with TCP Server Profile, Please include client TCP Profile ")
+	case egress == nil && ingress != nil:
+		svc.ProfileTCP = ingress
+	case ingress != nil && egress != nil:
+		svc.ProfileTCP = as3ProfileTCP{
+			Ingress: ingress,
+			Egress:  egress,
+		}
+	}
+}
+
+// Create AS3 Virtual Address for Virtual Server Address, the Service Address is
+// referred when one is defined for the address
+func createVirtualAddressDecl(cfg *ResourceConfig, virtualAddress string, sharedApp as3Application) as3MultiTypeParam {
+	serviceAddressName := createServiceAddressDecl(cfg, virtualAddress, sharedApp)
+	if serviceAddressName == "" {
+		return virtualAddress
+	}
+	//Attach Service Address
+	return &as3ResourcePointer{
+		Use: serviceAddressName,
+	}
+}
+
+// getServiceAddress returns the Service Address defined for the virtual address.
+// Service Address without virtualAddress applies to the rest of the addresses
+func getServiceAddress(serviceAddresses []ServiceAddress, virtualAddress string) (ServiceAddress, bool) {
+	var defaultSA ServiceAddress
+	var found bool
+	for _, sa := range serviceAddresses {
+		if sa.VirtualAddress == "" {
+			if !found {
+				defaultSA, found = sa, true
+			}
+			continue
+		}
+		// Service Address may omit the route domain of the virtual address
+		if sa.VirtualAddress == virtualAddress || sa.VirtualAddress == strings.Split(virtualAddress, "%")[0] {
+			return sa, true
+		}
+	}
+	return defaultSA, found
+}
+
 // Create AS3 Service Address for Virtual Server Address
 func createServiceAddressDecl(cfg *ResourceConfig, virtualAddress string, sharedApp as3Application) string {
-	var name string
-	for _, sa := range cfg.ServiceAddress {
-		serviceAddress := &as3ServiceAddress{}
-		serviceAddress.Class = "Service_Address"
-		serviceAddress.ArpEnabled = sa.ArpEnabled
-		serviceAddress.ICMPEcho = sa.ICMPEcho
-		serviceAddress.RouteAdvertisement = sa.RouteAdvertisement
-		serviceAddress.SpanningEnabled = sa.SpanningEnabled
-		serviceAddress.TrafficGroup = sa.TrafficGroup
-		serviceAddress.VirtualAddress = virtualAddress
-		name = "crd_service_address_" + AS3NameFormatter(virtualAddress)
-		sharedApp[name] = serviceAddress
-	}
+	sa, ok := getServiceAddress(cfg.ServiceAddress, virtualAddress)
+	if !ok {
+		if cfg.Virtual.TrafficGroup == "" {
+			return ""
+		}
+		// Service Address with the AS3 defaults to assign the virtual address to the traffic group
+		sa = ServiceAddress{ArpEnabled: true}
+	}
+	if sa.TrafficGroup == "" {
+		sa.TrafficGroup = cfg.Virtual.TrafficGroup
+	}
+	serviceAddress := &as3ServiceAddress{}
+	serviceAddress.Class = "Service_Address"
+	serviceAddress.ArpEnabled = sa.ArpEnabled
+	serviceAddress.ICMPEcho = sa.ICMPEcho
+	serviceAddress.RouteAdvertisement = sa.RouteAdvertisement
+	serviceAddress.SpanningEnabled = sa.SpanningEnabled
+	serviceAddress.TrafficGroup = sa.TrafficGroup
+	serviceAddress.VirtualAddress = virtualAddress
+	name := "crd_service_address_" + AS3NameFormatter(virtualAddress)
+	// virtual address is assigned to one traffic group, the traffic group sorting first is kept on conflict
+	// so that the declaration doesn't depend on the order of the virtuals
+	if existing, ok := sharedApp[name].(*as3ServiceAddress); ok && existing.TrafficGroup != serviceAddress.TrafficGroup {
+		log.Errorf("[AS3] Virtual address %v of virtual %v is assigned to traffic groups %v and %v, "+
+			"virtuals sharing the address are to be in the same traffic group", virtualAddress, cfg.Virtual.Name,
+			existing.TrafficGroup, serviceAddress.TrafficGroup)
+		if existing.TrafficGroup < serviceAddress.TrafficGroup {
+			return name
+		}
+	}
+	sharedApp[name] = serviceAddress
 	return name
 }
 
@@ -1324,7 +1881,8 @@ func createRuleCondition(rl *Rule, rulesData *as3Rule, port int) {
 			}
 		} else if c.PathSegment {
 			condition.PathSegment = &as3PolicyCompareString{
-				Values: c.Values,
+				Values:        c.Values,
+				CaseSensitive: c.CaseSensitive,
 			}
 			if c.Name != "" {
 				condition.Name = c.Name
@@ -1338,7 +1896,8 @@ func createRuleCondition(rl *Rule, rulesData *as3Rule, port int) {
 			}
 		} else if c.Path {
 			condition.Path = &as3PolicyCompareString{
-				Values: c.Values,
+				Values:        c.Values,
+				CaseSensitive: c.CaseSensitive,
 			}
 			if c.Name != "" {
 				condition.Name = c.Name
@@ -1443,15 +2002,13 @@ func createRuleAction(rl *Rule, rulesData *as3Rule) {
 func extractVirtualAddressAndPort(str string) (string, int) {
 
 	destination := strings.Split(str, "/")
-	// split separator is in accordance with SetVirtualAddress function - ipv4/6 format
-	ipPort := strings.Split(destination[len(destination)-1], ":")
-	if len(ipPort) != 2 {
-		ipPort = strings.Split(destination[len(destination)-1], ".")
-	}
+	ipPort := destination[len(destination)-1]
+	// split separator is in accordance with SetVirtualAddress function - ipv4/6 format,
+	// port follows the last separator of the IPv6 addresses with the embedded IPv4 address
 	// verify that ip address and port exists else log error.
-	if len(ipPort) == 2 {
-		port, _ := strconv.Atoi(ipPort[1])
-		return ipPort[0], port
+	if i := strings.LastIndexAny(ipPort, ":."); i > 0 {
+		port, _ := strconv.Atoi(ipPort[i+1:])
+		return ipPort[:i], port
 	} else {
 		log.Error("Invalid Virtual Server Destination IP address/Port.")
 		return "", 0
@@ -1556,6 +2113,9 @@ func processCustomProfilesForAS3(rsMap ResourceMap, sharedApp as3Application, as
 			} else {
 				createUpdateCABundle(prof, caBundleName, sharedApp)
 				tlsClient = createTLSClient(prof, svcName, caBundleName, sharedApp)
+				if prof.ValidateCertificate {
+					createRouteTLSClient(prof, svcName, sharedApp)
+				}
 
 				skey := SecretKey{
 					Name: prof.Name + "-ca",
@@ -1588,7 +2148,7 @@ func processCustomProfilesForAS3(rsMap ResourceMap, sharedApp as3Application, as
 
 // createUpdateTLSServer creates a new TLSServer instance or updates if one exists already
 func createUpdateTLSServer(prof CustomProfile, svcName string, sharedApp as3Application) bool {
-	if len(prof.Certificates) > 0 {
+	if len(prof.Certificates) > 0 || len(prof.SharedCertificates) > 0 {
 		if sharedApp[svcName] == nil {
 			return false
 		}
@@ -1614,7 +2174,7 @@ func createUpdateTLSServer(prof CustomProfile, svcName string, sharedApp as3Appl
 		for index, certificate := range prof.Certificates {
 			certName := fmt.Sprintf("%s_%d", prof.Name, index)
 			// A TLSServer profile needs to carry both Certificate and Key
-			if len(certificate.Cert) > 0 && len(certificate.Key) > 0 {
+			if len(certificate.Cert) > 0 && certificate.hasPrivateKey() {
 				tlsServer.Certificates = append(
 					tlsServer.Certificates,
 					as3TLSServerCertificates{
@@ -1625,6 +2185,15 @@ func createUpdateTLSServer(prof CustomProfile, svcName string, sharedApp as3Appl
 				return false
 			}
 		}
+		// Certificates of the TLSCertificates are shared across the TLSServers
+		for _, certName := range prof.SharedCertificates {
+			tlsServer.Certificates = append(
+				tlsServer.Certificates,
+				as3TLSServerCertificates{
+					Certificate: certName,
+				},
+			)
+		}
 		return true
 	}
 	return false
@@ -1632,12 +2201,17 @@ func createUpdateTLSServer(prof CustomProfile, svcName string, sharedApp as3Appl
 
 func createCertificateDecl(prof CustomProfile, sharedApp as3Application) {
 	for index, certificate := range prof.Certificates {
-		if len(certificate.Cert) > 0 && len(certificate.Key) > 0 {
+		if len(certificate.Cert) > 0 && certificate.hasPrivateKey() {
 			cert := &as3Certificate{
 				Class:       "Certificate",
 				Certificate: certificate.Cert,
 				PrivateKey:  certificate.Key,
 				ChainCA:     prof.CAFile,
+				Passphrase:  newAS3Passphrase(certificate.Passphrase),
+			}
+			if certificate.BigIPKey != "" {
+				// Key is stored on BIG-IP, in the FIPS partition or the external HSM
+				cert.PrivateKey = &as3ResourcePointer{BigIP: certificate.BigIPKey}
 			}
 			sharedApp[fmt.Sprintf("%s_%d", prof.Name, index)] = cert
 		}
@@ -1647,7 +2221,7 @@ func createCertificateDecl(prof CustomProfile, sharedApp as3Application) {
 func createUpdateCABundle(prof CustomProfile, caBundleName string, sharedApp as3Application) {
 	for _, cert := range prof.Certificates {
 		// For TLSClient only Cert (DestinationCACertificate) is given and key is empty string
-		if len(cert.Cert) > 0 && len(cert.Key) == 0 {
+		if len(cert.Cert) > 0 && !cert.hasPrivateKey() {
 			caBundle, ok := sharedApp[caBundleName].(*as3CABundle)
 
 			if !ok {
@@ -1670,13 +2244,17 @@ func createTLSClient(
 
 	// For TLSClient only Cert (DestinationCACertificate) is given and key is empty string
 	for _, certificate := range prof.Certificates {
-		if certificate.Key != "" {
+		if certificate.hasPrivateKey() {
 			return nil
 		}
 	}
-	if _, ok := sharedApp[svcName]; len(prof.Certificates) > 0 && ok {
+	if _, ok := sharedApp[svcName]; (len(prof.Certificates) > 0 || prof.SharedCABundle != "") && ok {
 		svc := sharedApp[svcName].(*as3Service)
 		tlsClientName := fmt.Sprintf("%s_tls_client", svcName)
+		if prof.SharedCABundle != "" {
+			// Trust the CA bundle of the TLSCertificate
+			caBundleName = prof.SharedCABundle
+		}
 
 		tlsClient := &as3TLSClient{
 			Class: "TLS_Client",
@@ -1699,10 +2277,60 @@ func createTLSClient(
 	return nil
 }
 
+// createRouteTLSClient creates the TLS client selected for the route by the TLS iRule, the TLS client trusts
+// only the CA certificates of the route and verifies the certificate of the backends
+func createRouteTLSClient(prof CustomProfile, svcName string, sharedApp as3Application) {
+	if _, ok := sharedApp[svcName]; !ok {
+		return
+	}
+	var bundle string
+	for _, cert := range prof.Certificates {
+		if len(cert.Cert) > 0 && !cert.hasPrivateKey() {
+			bundle += "\n" + cert.Cert
+		}
+	}
+	if bundle == "" {
+		return
+	}
+	tlsClientName := getRouteTLSClientName(svcName, prof.Name)
+	caBundleName := AS3NameFormatter(fmt.Sprintf("%s_%s_ca_bundle", svcName, prof.Name))
+	sharedApp[caBundleName] = &as3CABundle{
+		Class:  "CA_Bundle",
+		Bundle: bundle,
+	}
+	tlsClient := &as3TLSClient{
+		Class: "TLS_Client",
+		TrustCA: &as3ResourcePointer{
+			Use: caBundleName,
+		},
+		ValidateCertificate: true,
+	}
+	if prof.CipherGroup != "" {
+		tlsClient.CipherGroup = &as3ResourcePointer{BigIP: prof.CipherGroup}
+		tlsClient.TLS1_3Enabled = true
+	} else {
+		tlsClient.Ciphers = prof.Ciphers
+	}
+	sharedApp[tlsClientName] = tlsClient
+}
+
 // Create health monitor declaration
 func createMonitorDecl(cfg *ResourceConfig, sharedApp as3Application) {
+	createMonitorsDecl(cfg.Monitors, sharedApp)
+}
 
-	for _, v := range cfg.Monitors {
+// createMonitorsDecl creates the declarations of the monitors
+func createMonitorsDecl(monitors []Monitor, sharedApp as3Application) {
+	for _, v := range monitors {
+		if v.Type == InbandMonitor {
+			sharedApp[v.Name] = &as3InbandMonitor{
+				Class:           "Monitor",
+				MonitorType:     InbandMonitor,
+				FailureInterval: v.Interval,
+				ResponseTime:    v.Timeout,
+			}
+			continue
+		}
 		monitor := &as3Monitor{}
 		monitor.Class = "Monitor"
 		monitor.Interval = v.Interval
@@ -1724,7 +2352,7 @@ func createMonitorDecl(cfg *ResourceConfig, sharedApp as3Application) {
 			}
 			monitor.TimeUnitilUp = &val
 			monitor.Send = v.Send
-		case "https":
+		case "https", HTTP2Monitor:
 			//Todo: For https monitor type
 			adaptiveFalse := false
 			monitor.Adaptive = &adaptiveFalse
@@ -1732,6 +2360,28 @@ func createMonitorDecl(cfg *ResourceConfig, sharedApp as3Application) {
 				monitor.Receive = v.Recv
 			}
 			monitor.Send = v.Send
+			monitor.Ciphers = v.Ciphers
+			clientCertificate := v.ClientCertificate
+			if v.ClientTLSCertificate != "" {
+				clientCertificate = v.Name + "_client_certificate"
+				sharedApp[clientCertificate] = &as3Certificate{
+					Class:       "Certificate",
+					Certificate: v.ClientTLSCertificate,
+					PrivateKey:  v.ClientTLSKey,
+				}
+			}
+			if v.SNIServerName != "" {
+				// SNI is sent by the TLS client profile of the monitor, which presents the client certificate as well
+				tlsClientName := v.Name + "_client_tls"
+				sharedApp[tlsClientName] = &as3TLSClient{
+					Class:             "TLS_Client",
+					ServerName:        v.SNIServerName,
+					ClientCertificate: clientCertificate,
+				}
+				monitor.ClientTLS = &as3ResourcePointer{Use: tlsClientName}
+			} else {
+				monitor.ClientCertificate = clientCertificate
+			}
 		case "tcp", "udp":
 			adaptiveFalse := false
 			monitor.Adaptive = &adaptiveFalse
@@ -1771,8 +2421,29 @@ func createTransportServiceDecl(cfg *ResourceConfig, sharedApp as3Application, t
 			BigIP: cfg.Virtual.ProfileL4,
 		}
 	}
+	if cfg.Virtual.FastL4 != (FastL4{}) {
+		name := cfg.Virtual.Name + "_l4_profile"
+		sharedApp[name] = &as3L4Profile{
+			Class:               "L4_Profile",
+			IdleTimeout:         cfg.Virtual.FastL4.IdleTimeout,
+			LooseInitialization: cfg.Virtual.FastL4.LooseInitialization,
+			LooseClose:          cfg.Virtual.FastL4.LooseClose,
+		}
+		svc.ProfileL4 = &as3ResourcePointer{
+			Use: name,
+		}
+	}
 
 	svc.addPersistenceMethod(cfg.Virtual.PersistenceProfile)
+	svc.addMirroring(cfg, sharedApp, tenant)
+
+	// TCP analytics are collected only for the TCP virtuals
+	if cfg.Virtual.AnalyticsProfiles.TCPAnalyticsProfile != "" && svc.Class != "Service_UDP" &&
+		svc.Class != "Service_SCTP" && svc.Layer4 != "udp" && svc.Layer4 != "sctp" {
+		svc.TCPAnalyticsProfile = &as3ResourcePointer{
+			BigIP: cfg.Virtual.AnalyticsProfiles.TCPAnalyticsProfile,
+		}
+	}
 
 	if len(cfg.Virtual.ProfileDOS) > 0 {
 		svc.ProfileDOS = &as3ResourcePointer{
@@ -1786,26 +2457,7 @@ func createTransportServiceDecl(cfg *ResourceConfig, sharedApp as3Application, t
 		}
 	}
 
-	if len(cfg.Virtual.TCP.Client) > 0 || len(cfg.Virtual.TCP.Server) > 0 {
-		if cfg.Virtual.TCP.Client == "" {
-			log.Errorf("[AS3] resetting ProfileTCP as client profile doesnt co-exist with TCP Server Profile, Please include client TCP Profile ")
-		}
-		if cfg.Virtual.TCP.Server == "" {
-			svc.ProfileTCP = &as3ResourcePointer{
-				BigIP: fmt.Sprintf("%v", cfg.Virtual.TCP.Client),
-			}
-		}
-		if cfg.Virtual.TCP.Client != "" && cfg.Virtual.TCP.Server != "" {
-			svc.ProfileTCP = as3ProfileTCP{
-				Ingress: &as3ResourcePointer{
-					BigIP: fmt.Sprintf("%v", cfg.Virtual.TCP.Client),
-				},
-				Egress: &as3ResourcePointer{
-					BigIP: fmt.Sprintf("%v", cfg.Virtual.TCP.Server),
-				},
-			}
-		}
-	}
+	svc.addTCPProfile(cfg, sharedApp)
 
 	// Attaching Profiles from Policy CRD
 	for _, profile := range cfg.Virtual.Profiles {
@@ -1834,19 +2486,13 @@ func createTransportServiceDecl(cfg *ResourceConfig, sharedApp as3Application, t
 	virtualAddress, port := extractVirtualAddressAndPort(cfg.Virtual.Destination)
 	// verify that ip address and port exists.
 	if virtualAddress != "" && port != 0 {
-		if len(cfg.ServiceAddress) == 0 {
-			va := append(svc.VirtualAddresses, virtualAddress)
-			svc.VirtualAddresses = va
-			svc.VirtualPort = port
-		} else {
-			//Attach Service Address
-			serviceAddressName := createServiceAddressDecl(cfg, virtualAddress, sharedApp)
-			sa := &as3ResourcePointer{
-				Use: serviceAddressName,
-			}
-			svc.VirtualAddresses = append(svc.VirtualAddresses, sa)
-			svc.VirtualPort = port
+		virtualAddress = cfg.Virtual.getVirtualAddressWithMask(virtualAddress)
+		svc.VirtualAddresses = append(svc.VirtualAddresses, createVirtualAddressDecl(cfg, virtualAddress, sharedApp))
+		//handle additional service addresses
+		for _, val := range cfg.Virtual.AdditionalVirtualAddresses {
+			svc.VirtualAddresses = append(svc.VirtualAddresses, createVirtualAddressDecl(cfg, val, sharedApp))
 		}
+		svc.VirtualPort = port
 	}
 	var poolPointer as3ResourcePointer
 	ps := strings.Split(cfg.Virtual.PoolName, "/")
@@ -1879,6 +2525,14 @@ func processCommonDecl(cfg *ResourceConfig, svc *as3Service) {
 		}
 	}
 
+	//Attach RejectVLANs
+	if cfg.Virtual.RejectVLANs != nil {
+		for _, vlan := range cfg.Virtual.RejectVLANs {
+			vlans := as3ResourcePointer{BigIP: vlan}
+			svc.RejectVLANs = append(svc.RejectVLANs, vlans)
+		}
+	}
+
 	//Attach Firewall policy
 	if cfg.Virtual.Firewall != "" {
 		svc.Firewall = &as3ResourcePointer{
@@ -1940,6 +2594,51 @@ func (svc *as3Service) addPersistenceMethod(persistenceProfile string) {
 	}
 }
 
+// getPersistenceProfile returns the persistence profile of the virtual, TLS passthrough virtuals persist with
+// tls-session-id by default. ResourceConfig is shared with the store and is not updated with the default
+func (cfg *ResourceConfig) getPersistenceProfile() string {
+	if len(cfg.Virtual.PersistenceProfile) == 0 && cfg.Virtual.TLSTermination == TLSPassthrough {
+		return "tls-session-id"
+	}
+	return cfg.Virtual.PersistenceProfile
+}
+
+// addMirroring adds the connection mirroring in the service declaration, persistence
+// records are mirrored with a Persist of the persistence method in the shared application
+func (svc *as3Service) addMirroring(cfg *ResourceConfig, sharedApp as3Application, tenant string) {
+	svc.Mirroring = cfg.Virtual.Mirroring
+	if !cfg.Virtual.PersistenceMirroring {
+		return
+	}
+	persistenceMethod := cfg.getPersistenceProfile()
+	if len(persistenceMethod) == 0 {
+		// default persistence method of the service
+		persistenceMethod = "source-address"
+		if svc.Class == "Service_HTTP" {
+			persistenceMethod = "cookie"
+		}
+	}
+	switch persistenceMethod {
+	case "cookie", "destination-address", "hash", "msrdp", "sip-info", "source-address", "tls-session-id", "universal":
+		persistName := cfg.Virtual.Name + "_persist"
+		sharedApp[persistName] = &as3Persist{
+			Class:             "Persist",
+			PersistenceMethod: persistenceMethod,
+			Mirror:            true,
+		}
+		svc.PersistenceMethods = &[]as3MultiTypeParam{
+			as3MultiTypeParam(
+				as3ResourcePointer{
+					Use: fmt.Sprintf("/%s/%s/%s", tenant, as3SharedApplication, persistName),
+				},
+			),
+		}
+	default:
+		log.Warningf("[AS3] persistenceMirroring is not supported with persistence profile %v of virtual %v, "+
+			"enable mirroring in the persistence profile", persistenceMethod, cfg.Virtual.Name)
+	}
+}
+
 func (agent *Agent) isGTMTenant(partition string) bool {
 	return partition == DEFAULT_GTM_PARTITION
 }
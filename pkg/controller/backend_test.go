@@ -1,11 +1,18 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/test"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/ghttp"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -246,6 +253,129 @@ var _ = Describe("Backend Tests", func() {
 			Expect(strings.Contains(string(decl), "default_pool_svc1"))
 			Expect(strings.Contains(string(decl), "default_pool_svc2"))
 		})
+		It("Validates AS3 Declaration", func() {
+			DEFAULT_PARTITION = "test"
+			DEFAULT_GTM_PARTITION = "test_gtm"
+			schemaPath, _ := filepath.Abs("../../schemas/")
+			agent.as3Validation = true
+			agent.as3SchemaURL = "file://" + schemaPath + "/" + as3SchemaFileName
+			agent.AS3VersionInfo = as3VersionInfo{
+				as3Version:       "3.45.0",
+				as3Release:       "3.45.0-5",
+				as3SchemaVersion: "3.45.0",
+			}
+
+			rsCfg := &ResourceConfig{}
+			rsCfg.MetaData.Active = true
+			rsCfg.MetaData.ResourceType = VirtualServer
+			rsCfg.Virtual.Name = "crd_vs_172.13.14.15"
+			rsCfg.Virtual.PoolName = "default_pool_svc1"
+			rsCfg.Virtual.Destination = "/test/172.13.14.5:8080"
+			rsCfg.Virtual.Enabled = true
+			rsCfg.Virtual.SNAT = DEFAULT_SNAT
+			rsCfg.Pools = Pools{
+				Pool{
+					Name:         "default_pool_svc1",
+					Members:      []PoolMember{mem1, mem2},
+					MonitorNames: []MonitorName{{Name: "/test/http_monitor"}},
+				},
+			}
+			rsCfg.Monitors = Monitors{
+				{
+					Name:     "http_monitor",
+					Interval: 10,
+					Type:     "http",
+					Timeout:  31,
+					Send:     "GET /health",
+				},
+			}
+			config := ResourceConfigRequest{
+				ltmConfig:          make(LTMConfig),
+				shareNodes:         true,
+				gtmConfig:          GTMConfig{},
+				defaultRouteDomain: 1,
+			}
+			zero := 0
+			config.ltmConfig["test"] = &PartitionConfig{ResourceMap: make(ResourceMap), Priority: &zero}
+			config.ltmConfig["test"].ResourceMap["crd_vs_172.13.14.15"] = rsCfg
+			config.ltmConfig["test2"] = &PartitionConfig{ResourceMap: make(ResourceMap), Priority: &zero}
+			rsCfg2 := &ResourceConfig{MetaData: rsCfg.MetaData, Virtual: rsCfg.Virtual}
+			rsCfg2.Virtual.Name = "crd_vs_172.13.14.16"
+			rsCfg2.Virtual.PoolName = ""
+			rsCfg2.Virtual.Destination = "/test2/172.13.14.6:8080"
+			config.ltmConfig["test2"].ResourceMap["crd_vs_172.13.14.16"] = rsCfg2
+
+			decl := agent.createTenantAS3Declaration(config)
+			Expect(agent.validateAS3Declaration(decl)).To(BeEmpty(), "Valid AS3 declaration reported invalid")
+
+			// Invalid fields are reported with their path in the declaration
+			rsCfg.Pools[0].Balance = "fastest-round-robin"
+			decl = agent.createTenantAS3Declaration(config)
+			tenantErrs := agent.validateAS3Declaration(decl)
+			Expect(tenantErrs).To(HaveLen(1), "Invalid AS3 declaration not reported")
+			Expect(tenantErrs["test"]).To(HaveLen(1))
+			Expect(tenantErrs["test"][0]).To(HavePrefix("/test/Shared/default_pool_svc1: "))
+			Expect(tenantErrs["test"][0]).To(ContainSubstring("loadBalancingMode must be one of the following"))
+
+			// Only the tenants with the invalid objects are not posted
+			decl = agent.removeInvalidTenants(tenantErrs)
+			Expect(agent.incomingTenantDeclMap).NotTo(HaveKey("test"))
+			Expect(agent.incomingTenantDeclMap).To(HaveKey("test2"))
+			Expect(string(decl)).NotTo(ContainSubstring("default_pool_svc1"))
+			Expect(string(decl)).To(ContainSubstring("crd_vs_172.13.14.16"))
+			agent.respChan = make(chan resourceStatusMeta, 1)
+			agent.notifyRscStatusHandler(1, true)
+			rscUpdateMeta := <-agent.respChan
+			Expect(rscUpdateMeta.failedTenants).To(Equal(map[string]struct{}{"test": {}}))
+			Expect(rscUpdateMeta.tenantErrors["test"]).To(Equal(tenantErrs["test"][0]))
+
+			// Validation is skipped when the AS3 on BIG-IP serves a newer schema version
+			agent.AS3VersionInfo.as3SchemaVersion = "3.50.0"
+			Expect(agent.validateAS3Declaration(decl)).To(BeEmpty())
+			agent.AS3VersionInfo.as3SchemaVersion = "3.45.0"
+
+			// Validation is skipped when disabled
+			agent.as3Validation = false
+			Expect(agent.validateAS3Declaration(decl)).To(BeEmpty())
+		})
+		It("Posts tenants with optimistic lock keys", func() {
+			agent.PostManager = &PostManager{
+				PostParams:     PostParams{AS3OptimisticLock: true},
+				tenantLockKeys: map[string]string{"test": "key1"},
+			}
+			tenantDecl := as3Tenant{"class": "Tenant"}
+			decl := agent.createAS3Declaration(map[string]as3Tenant{"test": tenantDecl, "test1": {"class": "Tenant"}})
+
+			var as3Config map[string]interface{}
+			_ = json.Unmarshal([]byte(decl), &as3Config)
+			adc := as3Config["declaration"].(map[string]interface{})
+			Expect(adc["test"].(map[string]interface{})["optimisticLockKey"]).To(Equal("key1"), "Lock key not posted")
+			Expect(adc["test1"].(map[string]interface{})).NotTo(HaveKey("optimisticLockKey"))
+			Expect(tenantDecl).NotTo(HaveKey("optimisticLockKey"), "Tenant declaration modified")
+
+			agent.AS3OptimisticLock = false
+			decl = agent.createAS3Declaration(map[string]as3Tenant{"test": tenantDecl})
+			_ = json.Unmarshal([]byte(decl), &as3Config)
+			adc = as3Config["declaration"].(map[string]interface{})
+			Expect(adc["test"].(map[string]interface{})).NotTo(HaveKey("optimisticLockKey"))
+		})
+
+		It("Pins AS3 schema version", func() {
+			agent.AS3VersionInfo = as3VersionInfo{as3SchemaVersion: "3.45.0"}
+			agent.pinAS3SchemaVersion("3.45.0")
+			Expect(agent.AS3VersionInfo.as3SchemaVersion).To(Equal("3.45.0"))
+
+			agent.as3SchemaVersion = "3.40.0"
+			agent.pinAS3SchemaVersion("3.45.0")
+			Expect(agent.AS3VersionInfo.as3SchemaVersion).To(Equal("3.40.0"))
+
+			// Schema version not supported by BIG-IP is ignored
+			agent.AS3VersionInfo = as3VersionInfo{as3SchemaVersion: "3.45.0"}
+			agent.as3SchemaVersion = "3.50.0"
+			agent.pinAS3SchemaVersion("3.45.0")
+			Expect(agent.AS3VersionInfo.as3SchemaVersion).To(Equal("3.45.0"))
+			Expect(compareAS3SchemaVersions("3.9.0", "3.10.0")).To(Equal(-1))
+		})
 		It("TransportServer Declaration", func() {
 			rsCfg := &ResourceConfig{}
 			rsCfg.MetaData.Active = true
@@ -281,6 +411,71 @@ var _ = Describe("Backend Tests", func() {
 			Expect(string(decl)).ToNot(Equal(""), "Failed to Create AS3 Declaration")
 
 		})
+		It("TransportServer Declaration with FastL4 profile", func() {
+			rsCfg := &ResourceConfig{}
+			rsCfg.Virtual.Name = "crd_vs_172.13.14.16"
+			rsCfg.Virtual.Mode = "performance"
+			rsCfg.Virtual.IpProtocol = "tcp"
+			rsCfg.Virtual.Destination = "172.13.14.6:1600"
+			rsCfg.Virtual.FastL4 = FastL4{IdleTimeout: 300, LooseInitialization: true}
+
+			sharedApp := as3Application{}
+			createTransportServiceDecl(rsCfg, sharedApp, "default")
+			svc := sharedApp["crd_vs_172.13.14.16"].(*as3Service)
+			Expect(svc.Class).To(Equal("Service_L4"))
+			Expect(svc.ProfileL4).To(Equal(&as3ResourcePointer{Use: "crd_vs_172.13.14.16_l4_profile"}))
+			Expect(sharedApp["crd_vs_172.13.14.16_l4_profile"]).To(Equal(&as3L4Profile{
+				Class:               "L4_Profile",
+				IdleTimeout:         300,
+				LooseInitialization: true,
+			}))
+
+			// FastL4 profile is valid with the AS3 schema
+			DEFAULT_PARTITION = "test"
+			DEFAULT_GTM_PARTITION = "test_gtm"
+			schemaPath, _ := filepath.Abs("../../schemas/")
+			agent.as3Validation = true
+			agent.as3SchemaURL = "file://" + schemaPath + "/" + as3SchemaFileName
+			agent.AS3VersionInfo = as3VersionInfo{as3Version: "3.45.0", as3Release: "3.45.0-5", as3SchemaVersion: "3.45.0"}
+			rsCfg.MetaData.Active = true
+			rsCfg.MetaData.ResourceType = TransportServer
+			rsCfg.Virtual.Destination = "/test/172.13.14.6:1600"
+			rsCfg.Virtual.SNAT = DEFAULT_SNAT
+			config := ResourceConfigRequest{ltmConfig: make(LTMConfig), gtmConfig: GTMConfig{}}
+			zero := 0
+			config.ltmConfig["test"] = &PartitionConfig{ResourceMap: ResourceMap{rsCfg.Virtual.Name: rsCfg}, Priority: &zero}
+			decl := agent.createTenantAS3Declaration(config)
+			Expect(string(decl)).To(ContainSubstring("crd_vs_172.13.14.16_l4_profile"))
+			Expect(agent.validateAS3Declaration(decl)).To(BeEmpty(), "FastL4 profile rejected by the AS3 schema")
+		})
+		It("TransportServer Declaration with IPv6 and IPv4 addresses", func() {
+			rsCfg := &ResourceConfig{}
+			rsCfg.Virtual.Name = "crd_2001_db8__10.2_1600"
+			rsCfg.Virtual.Partition = "default"
+			rsCfg.Virtual.Mode = "standard"
+			rsCfg.Virtual.IpProtocol = "tcp"
+			rsCfg.Virtual.SetVirtualAddress("2001:db8::10%2", 1600)
+			rsCfg.Virtual.AdditionalVirtualAddresses = []string{"172.13.14.0%2/24"}
+			Expect(rsCfg.Virtual.Destination).To(Equal("/default/2001:db8::10%2.1600"))
+
+			sharedApp := as3Application{}
+			createTransportServiceDecl(rsCfg, sharedApp, "default")
+			svc := sharedApp["crd_2001_db8__10.2_1600"].(*as3Service)
+			Expect(svc.VirtualAddresses).To(Equal([]as3MultiTypeParam{"2001:db8::10%2", "172.13.14.0%2/24"}))
+			Expect(svc.VirtualPort).To(Equal(1600))
+
+			// mask of the network virtual address is not part of the destination
+			rsCfg.Virtual.SetVirtualAddress("2001:db8::%2/64", 1600)
+			Expect(rsCfg.Virtual.Destination).To(Equal("/default/2001:db8::%2.1600"))
+			sharedApp = as3Application{}
+			createTransportServiceDecl(rsCfg, sharedApp, "default")
+			svc = sharedApp["crd_2001_db8__10.2_1600"].(*as3Service)
+			Expect(svc.VirtualAddresses[0]).To(Equal("2001:db8::%2/64"))
+
+			address, port := extractVirtualAddressAndPort("/default/::ffff:172.13.14.6.1600")
+			Expect(address).To(Equal("::ffff:172.13.14.6"))
+			Expect(port).To(Equal(1600))
+		})
 		It("Delete partition", func() {
 			config := ResourceConfigRequest{
 				ltmConfig:          make(LTMConfig),
@@ -326,6 +521,438 @@ var _ = Describe("Backend Tests", func() {
 			svc.addPersistenceMethod("pm2")
 			Expect(svc.PersistenceMethods).To(Equal(&[]as3MultiTypeParam{as3ResourcePointer{BigIP: "pm2"}}))
 		})
+		It("Handles Connection and Persistence Mirroring", func() {
+			sharedApp := as3Application{}
+			cfg := &ResourceConfig{}
+			cfg.Virtual.Name = "crd_vs_172.13.14.16"
+			cfg.Virtual.Mirroring = "L4"
+			svc := &as3Service{Class: "Service_TCP"}
+			svc.addMirroring(cfg, sharedApp, "default")
+			Expect(svc.Mirroring).To(Equal("L4"))
+			Expect(svc.PersistenceMethods).To(BeNil())
+			Expect(sharedApp).To(BeEmpty())
+
+			// Persistence records of the default persistence method are mirrored
+			cfg.Virtual.PersistenceMirroring = true
+			svc.addMirroring(cfg, sharedApp, "default")
+			Expect(sharedApp["crd_vs_172.13.14.16_persist"]).To(Equal(&as3Persist{
+				Class:             "Persist",
+				PersistenceMethod: "source-address",
+				Mirror:            true,
+			}))
+			Expect(svc.PersistenceMethods).To(Equal(&[]as3MultiTypeParam{
+				as3ResourcePointer{Use: "/default/Shared/crd_vs_172.13.14.16_persist"}}))
+
+			// Custom persistence profiles are not mirrored
+			sharedApp = as3Application{}
+			cfg.Virtual.PersistenceProfile = "/Common/pm1"
+			svc = &as3Service{Class: "Service_TCP"}
+			svc.addPersistenceMethod(cfg.Virtual.PersistenceProfile)
+			svc.addMirroring(cfg, sharedApp, "default")
+			Expect(svc.PersistenceMethods).To(Equal(&[]as3MultiTypeParam{as3ResourcePointer{BigIP: "/Common/pm1"}}))
+			Expect(sharedApp).To(BeEmpty())
+		})
+		It("Handles HTTP Profile with settings", func() {
+			xff := false
+			rsCfg := &ResourceConfig{}
+			rsCfg.MetaData.Protocol = HTTP
+			rsCfg.Virtual.Name = "crd_vs_172.13.14.18"
+			rsCfg.Virtual.Destination = "172.13.14.18:80"
+			rsCfg.Virtual.HTTPProfile = HTTPProfile{InsertXForwardedFor: &xff, MaxHeaderCount: 128, ProxyType: "reverse"}
+			sharedApp := as3Application{}
+			createServiceDecl(rsCfg, sharedApp, "default")
+			svc := sharedApp["crd_vs_172.13.14.18"].(*as3Service)
+			Expect(svc.ProfileHTTP).To(Equal(&as3ResourcePointer{Use: "crd_vs_172.13.14.18_http_profile"}))
+			Expect(sharedApp["crd_vs_172.13.14.18_http_profile"]).To(Equal(&as3HTTPProfile{
+				Class:          "HTTP_Profile",
+				XForwardedFor:  &xff,
+				MaxHeaderCount: 128,
+				ProxyType:      "reverse",
+			}))
+
+			// HTTP profile is skipped for the passthrough virtual
+			rsCfg.Virtual.TLSTermination = TLSPassthrough
+			sharedApp = as3Application{}
+			createServiceDecl(rsCfg, sharedApp, "default")
+			svc = sharedApp["crd_vs_172.13.14.18"].(*as3Service)
+			Expect(svc.Class).To(Equal("Service_TCP"))
+			Expect(svc.ProfileHTTP).To(BeNil(), "HTTP profile attached to the passthrough virtual")
+			Expect(sharedApp).NotTo(HaveKey("crd_vs_172.13.14.18_http_profile"), "HTTP profile created for the passthrough virtual")
+		})
+		It("Handles HTTP Compression and Web Acceleration Profiles", func() {
+			rsCfg := &ResourceConfig{}
+			rsCfg.MetaData.Protocol = HTTP
+			rsCfg.Virtual.Name = "crd_vs_172.13.14.19"
+			rsCfg.Virtual.Destination = "172.13.14.19:80"
+			rsCfg.Virtual.ProfileHTTPCompression = "basic"
+			rsCfg.Virtual.ProfileWebAcceleration = "/Common/optimized-caching"
+			sharedApp := as3Application{}
+			createServiceDecl(rsCfg, sharedApp, "default")
+			svc := sharedApp["crd_vs_172.13.14.19"].(*as3Service)
+			Expect(svc.ProfileHTTPCompression).To(Equal("basic"))
+			Expect(svc.ProfileHTTPAcceleration).To(Equal(&as3ResourcePointer{BigIP: "/Common/optimized-caching"}))
+		})
+		It("Handles LTM Policies of BIG-IP", func() {
+			rsCfg := &ResourceConfig{}
+			rsCfg.MetaData.Protocol = HTTP
+			rsCfg.Virtual.Name = "crd_vs_172.13.14.20"
+			rsCfg.Virtual.Destination = "172.13.14.20:80"
+			rsCfg.Virtual.Policies = []nameRef{{Name: "crd_vs_172.13.14.20_policy", Partition: "default"}}
+			rsCfg.Virtual.BigIPPolicies = []string{"/Common/geo-policy"}
+			sharedApp := as3Application{}
+			createServiceDecl(rsCfg, sharedApp, "default")
+			svc := sharedApp["crd_vs_172.13.14.20"].(*as3Service)
+			Expect(svc.PolicyEndpoint).To(Equal([]as3ResourcePointer{
+				{Use: "/default/Shared/crd_vs_172.13.14.20_policy"},
+				{BigIP: "/Common/geo-policy"},
+			}))
+		})
+		It("Handles APM Access Profile", func() {
+			rsCfg := &ResourceConfig{}
+			rsCfg.MetaData.Protocol = HTTPS
+			rsCfg.Virtual.Name = "crd_vs_172.13.14.21"
+			rsCfg.Virtual.Destination = "172.13.14.21:443"
+			rsCfg.Virtual.PolicyPerRequestAccess = "/Common/prp"
+			sharedApp := as3Application{}
+			createServiceDecl(rsCfg, sharedApp, "default")
+			svc := sharedApp["crd_vs_172.13.14.21"].(*as3Service)
+			Expect(svc.PolicyPerRequestAccess).To(BeNil(), "Per-request policy attached without access profile")
+
+			rsCfg.Virtual.ProfileAccess = "/Common/sso-access"
+			createServiceDecl(rsCfg, sharedApp, "default")
+			svc = sharedApp["crd_vs_172.13.14.21"].(*as3Service)
+			Expect(svc.ProfileAccess).To(Equal(&as3ResourcePointer{BigIP: "/Common/sso-access"}))
+			Expect(svc.PolicyPerRequestAccess).To(Equal(&as3ResourcePointer{BigIP: "/Common/prp"}))
+		})
+		It("Handles Request Logging", func() {
+			rsCfg := &ResourceConfig{}
+			rsCfg.MetaData.Protocol = HTTP
+			rsCfg.Virtual.Name = "crd_vs_172.13.14.20"
+			rsCfg.Virtual.Destination = "172.13.14.20:80"
+			rsCfg.Virtual.RequestLogging = RequestLogging{Servers: []string{"10.10.10.20"}, Port: 514, Protocol: "tcp"}
+			sharedApp := as3Application{}
+			createServiceDecl(rsCfg, sharedApp, "default")
+			svc := sharedApp["crd_vs_172.13.14.20"].(*as3Service)
+			Expect(svc.ProfileTrafficLog).To(Equal(&as3ResourcePointer{Use: "crd_vs_172.13.14.20_request_log"}))
+			Expect(sharedApp["crd_vs_172.13.14.20_request_log"]).To(Equal(&as3TrafficLogProfile{
+				Class: "Traffic_Log_Profile",
+				RequestSettings: as3TrafficLogSettings{
+					RequestEnabled:  true,
+					RequestProtocol: "mds-tcp",
+					RequestPool:     &as3ResourcePointer{Use: "crd_vs_172.13.14.20_request_log_pool"},
+					RequestTemplate: DefaultRequestLogTemplate,
+				},
+			}))
+			pool := sharedApp["crd_vs_172.13.14.20_request_log_pool"].(*as3Pool)
+			Expect(pool.Members).To(Equal([]as3PoolMember{{
+				AddressDiscovery: "static",
+				ServerAddresses:  []string{"10.10.10.20"},
+				ServicePort:      514,
+			}}))
+		})
+		It("Handles TCP Profiles with inline settings", func() {
+			sharedApp := as3Application{}
+			cfg := &ResourceConfig{}
+			cfg.Virtual.Name = "crd_vs_172.13.14.17"
+			cfg.Virtual.TCP = ProfileTCP{IdleTimeout: 600, Nagle: "disable"}
+			svc := &as3Service{Class: "Service_TCP"}
+			svc.addTCPProfile(cfg, sharedApp)
+			Expect(svc.ProfileTCP).To(Equal(&as3ResourcePointer{Use: "crd_vs_172.13.14.17_tcp_profile"}))
+			Expect(sharedApp["crd_vs_172.13.14.17_tcp_profile"]).To(Equal(&as3TCPProfile{
+				Class:       "TCP_Profile",
+				IdleTimeout: 600,
+				Nagle:       "disable",
+			}))
+
+			// Inline settings are used on the server side with client TCP profile
+			cfg.Virtual.TCP.Client = "/Common/f5-tcp-wan"
+			cfg.Virtual.TCP.CongestionControl = "bbr"
+			svc = &as3Service{Class: "Service_TCP"}
+			svc.addTCPProfile(cfg, sharedApp)
+			Expect(svc.ProfileTCP).To(Equal(as3ProfileTCP{
+				Ingress: &as3ResourcePointer{BigIP: "/Common/f5-tcp-wan"},
+				Egress:  &as3ResourcePointer{Use: "crd_vs_172.13.14.17_tcp_profile"},
+			}))
+			Expect(sharedApp["crd_vs_172.13.14.17_tcp_profile"].(*as3TCPProfile).CongestionControl).To(Equal("bbr"))
+
+			// Inline settings are not used with client and server TCP profiles
+			sharedApp = as3Application{}
+			cfg.Virtual.TCP = ProfileTCP{Client: "/Common/f5-tcp-wan", Server: "/Common/f5-tcp-lan", IdleTimeout: 600}
+			svc = &as3Service{Class: "Service_TCP"}
+			svc.addTCPProfile(cfg, sharedApp)
+			Expect(svc.ProfileTCP).To(Equal(as3ProfileTCP{
+				Ingress: &as3ResourcePointer{BigIP: "/Common/f5-tcp-wan"},
+				Egress:  &as3ResourcePointer{BigIP: "/Common/f5-tcp-lan"},
+			}))
+			Expect(sharedApp).To(BeEmpty())
+		})
+		It("Handles Inband Monitor and minimum monitors of the pool", func() {
+			sharedApp := as3Application{}
+			cfg := &ResourceConfig{}
+			cfg.Monitors = Monitors{
+				{
+					Name:     "inband_monitor",
+					Type:     InbandMonitor,
+					Interval: 30,
+					Timeout:  10,
+				},
+			}
+			createMonitorDecl(cfg, sharedApp)
+			Expect(sharedApp["inband_monitor"]).To(Equal(&as3InbandMonitor{
+				Class:           "Monitor",
+				MonitorType:     InbandMonitor,
+				FailureInterval: 30,
+				ResponseTime:    10,
+			}))
+
+			minimum := intstr.FromString("all")
+			cfg.Pools = Pools{
+				{
+					Name:            "pool1",
+					MonitorNames:    []MonitorName{{Name: "/test/inband_monitor"}, {Name: "/test/http_monitor"}},
+					MinimumMonitors: &minimum,
+				},
+				{
+					Name:         "pool2",
+					MonitorNames: []MonitorName{{Name: "/test/inband_monitor"}},
+					Members: []PoolMember{
+						{Address: "1.2.3.4", Port: 30001, Session: "user-enabled"},
+						{Address: "1.2.3.5", Port: 30001, Session: "user-disabled"},
+					},
+				},
+			}
+			createPoolDecl(cfg, sharedApp, false, "test")
+			Expect(sharedApp["pool1"].(*as3Pool).MinimumMonitors).To(Equal("all"))
+			Expect(sharedApp["pool1"].(*as3Pool).Monitors).To(HaveLen(2))
+			Expect(sharedApp["pool2"].(*as3Pool).MinimumMonitors).To(BeNil())
+			Expect(sharedApp["pool2"].(*as3Pool).Members[0].AdminState).To(BeEmpty())
+			Expect(sharedApp["pool2"].(*as3Pool).Members[1].AdminState).To(Equal("disable"))
+		})
+		It("Handles HTTPS Monitor client certificate and SNI", func() {
+			sharedApp := as3Application{}
+			cfg := &ResourceConfig{}
+			cfg.Monitors = Monitors{
+				{
+					Name:                 "mtls_monitor",
+					Type:                 "https",
+					Send:                 "GET /health",
+					ClientTLSCertificate: "crthash",
+					ClientTLSKey:         "keyhash",
+				},
+				{
+					Name:                 "sni_monitor",
+					Type:                 "https",
+					Send:                 "GET /health",
+					SNIServerName:        "foo.com",
+					ClientTLSCertificate: "crthash",
+					ClientTLSKey:         "keyhash",
+				},
+			}
+			createMonitorDecl(cfg, sharedApp)
+			Expect(sharedApp["mtls_monitor"].(*as3Monitor).ClientCertificate).To(Equal("mtls_monitor_client_certificate"))
+			Expect(sharedApp["mtls_monitor"].(*as3Monitor).ClientTLS).To(BeNil())
+			Expect(sharedApp["mtls_monitor_client_certificate"]).To(Equal(&as3Certificate{
+				Class:       "Certificate",
+				Certificate: "crthash",
+				PrivateKey:  "keyhash",
+			}))
+
+			// Client certificate is presented by the TLS client profile sending the SNI
+			Expect(sharedApp["sni_monitor"].(*as3Monitor).ClientCertificate).To(BeEmpty())
+			Expect(sharedApp["sni_monitor"].(*as3Monitor).ClientTLS).To(Equal(&as3ResourcePointer{Use: "sni_monitor_client_tls"}))
+			Expect(sharedApp["sni_monitor_client_tls"]).To(Equal(&as3TLSClient{
+				Class:             "TLS_Client",
+				ServerName:        "foo.com",
+				ClientCertificate: "sni_monitor_client_certificate",
+			}))
+		})
+		It("Shares identical pools of the virtuals", func() {
+			newVirtual := func(name, host, svc string) *ResourceConfig {
+				poolName := formatPoolName("default", svc, intstr.FromInt(80), "", host, "")
+				monitorName := formatMonitorName("default", svc, "http", intstr.FromInt(80), host, "")
+				rsCfg := &ResourceConfig{}
+				rsCfg.MetaData.ResourceType = VirtualServer
+				rsCfg.Virtual.Name = name
+				rsCfg.Virtual.PoolName = "/test/" + poolName
+				rsCfg.Pools = Pools{{
+					Name:             poolName,
+					ServiceName:      svc,
+					ServiceNamespace: "default",
+					ServicePort:      intstr.FromInt(80),
+					Members:          []PoolMember{mem1, mem2},
+					MonitorNames:     []MonitorName{{Name: "/test/" + monitorName}},
+				}}
+				rsCfg.Monitors = Monitors{{Name: monitorName, Type: "http", Interval: 5, Send: "GET /"}}
+				rsCfg.Policies = Policies{{
+					Name:  name + "_policy",
+					Rules: Rules{{Name: name + "_rule", Actions: []*action{{Forward: true, Pool: "/test/" + poolName}}}},
+				}}
+				return rsCfg
+			}
+			rsMap := ResourceMap{
+				"crd_foo_80":   newVirtual("crd_foo_80", "foo.com", "svc1"),
+				"crd_bar_80":   newVirtual("crd_bar_80", "bar.com", "svc1"),
+				"crd_baz_80":   newVirtual("crd_baz_80", "baz.com", "svc1"),
+				"crd_other_80": newVirtual("crd_other_80", "foo.com", "svc2"),
+			}
+			sharedApp := as3Application{}
+			processResourcesForAS3(rsMap, sharedApp, false, "test")
+			// pool referenced by the iRule is not shared
+			sharedApp["baz_irule"] = &as3IRules{Class: "iRule", IRule: "pool /test/Shared/svc1_80_default_baz_com"}
+			Expect(shareIdenticalPools(rsMap, sharedApp)).To(Equal(map[string]string{
+				"svc1_80_default_foo_com": "svc1_80_default_bar_com",
+			}), "Shared pool of the removed pool not returned")
+
+			Expect(sharedApp).To(HaveKey("svc1_80_default_bar_com"))
+			Expect(sharedApp).NotTo(HaveKey("svc1_80_default_foo_com"), "Identical pool should be shared")
+			Expect(sharedApp).NotTo(HaveKey("svc1_default_foo_com_http_80"), "Monitor of the shared pool should be removed")
+			Expect(sharedApp).To(HaveKey("svc1_80_default_baz_com"))
+			Expect(sharedApp).To(HaveKey("svc2_80_default_foo_com"))
+			Expect(sharedApp["crd_foo_80"].(*as3Service).Pool.Use).To(Equal("/test/Shared/svc1_80_default_bar_com"))
+			Expect(sharedApp["crd_bar_80"].(*as3Service).Pool.Use).To(Equal("/test/Shared/svc1_80_default_bar_com"))
+			Expect(sharedApp["crd_baz_80"].(*as3Service).Pool.Use).To(Equal("/test/Shared/svc1_80_default_baz_com"))
+			Expect(sharedApp["crd_other_80"].(*as3Service).Pool.Use).To(Equal("/test/Shared/svc2_80_default_foo_com"))
+			Expect(sharedApp["crd_foo_80_policy"].(*as3EndpointPolicy).Rules[0].Actions[0].Select.Pool.Use).To(
+				Equal("svc1_80_default_bar_com"))
+
+			// pools with different members are not shared
+			rsMap["crd_foo_80"].Pools[0].Members = []PoolMember{mem1}
+			sharedApp = as3Application{}
+			processResourcesForAS3(rsMap, sharedApp, false, "test")
+			Expect(shareIdenticalPools(rsMap, sharedApp)).NotTo(HaveKey("svc1_80_default_foo_com"))
+			Expect(sharedApp).To(HaveKey("svc1_80_default_foo_com"))
+			Expect(sharedApp).To(HaveKey("svc1_default_foo_com_http_80"))
+		})
+		It("Splits the declaration exceeding the size limit", func() {
+			tenantDeclMap := map[string]as3Tenant{
+				"test1": {"class": "Tenant", "remark": strings.Repeat("a", 400*1024)},
+				"test2": {"class": "Tenant", "remark": strings.Repeat("b", 400*1024)},
+				"test3": {"class": "Tenant", "remark": strings.Repeat("c", 400*1024)},
+				"test4": {"class": "Tenant", "remark": strings.Repeat("d", 1200*1024)},
+			}
+			tenants := []string{"test4", "test3", "test2", "test1"}
+			decl := agent.createAS3Declaration(tenantDeclMap)
+
+			// Size is not limited by default
+			decls, oversized := agent.splitDeclaration(decl, tenantDeclMap, tenants)
+			Expect(decls).To(HaveLen(1))
+			Expect(decls[0].decl).To(Equal(decl))
+			Expect(oversized).To(BeEmpty())
+
+			// Declaration exceeding the limit is not posted
+			agent.AS3MaxDeclarationSize = 1
+			decls, oversized = agent.splitDeclaration(decl, tenantDeclMap, tenants)
+			Expect(decls).To(BeEmpty())
+			Expect(oversized).To(Equal(tenants))
+
+			// Tenants are posted in order in the declarations within the limit
+			agent.AS3SplitTenants = true
+			decls, oversized = agent.splitDeclaration(decl, tenantDeclMap, tenants)
+			Expect(decls).To(HaveLen(2))
+			Expect(decls[0].tenants).To(Equal([]string{"test1", "test2"}))
+			Expect(decls[1].tenants).To(Equal([]string{"test3"}))
+			Expect(oversized).To(Equal([]string{"test4"}))
+			for _, tenantsDecl := range decls {
+				Expect(len(tenantsDecl.decl) <= 1024*1024).To(BeTrue(), "Declaration exceeds the limit")
+				var as3Config map[string]interface{}
+				_ = json.Unmarshal([]byte(tenantsDecl.decl), &as3Config)
+				adc := as3Config["declaration"].(map[string]interface{})
+				for _, tenant := range tenantsDecl.tenants {
+					Expect(adc).To(HaveKey(tenant))
+				}
+				Expect(adc).NotTo(HaveKey("test4"))
+			}
+
+			// Oversized tenants are failed without the retries
+			agent.tenantResponseMap = make(map[string]tenantResponse)
+			agent.retryTenantDeclMap = make(map[string]*tenantParams)
+			agent.setOversizedTenantResponse(oversized)
+			agent.updateTenantResponse(true)
+			Expect(agent.retryTenantDeclMap).To(HaveKey("test4"))
+			Expect(agent.retryTenantDeclMap["test4"].message).To(ContainSubstring("--as3-max-declaration-size"))
+			Expect(agent.isRetryExhausted(agent.retryTenantDeclMap["test4"])).To(BeTrue())
+			Expect(agent.hasPendingRetries()).To(BeFalse())
+		})
+		It("Records the audit of the declarations", func() {
+			auditDir, err := os.MkdirTemp("", "audit")
+			Expect(err).To(BeNil())
+			defer os.RemoveAll(auditDir)
+			kubeClient := k8sfake.NewSimpleClientset()
+			auditFile := filepath.Join(auditDir, "audit.log")
+			agent.BIGIPUsername = "admin"
+			agent.auditLog = newAuditLogger(AuditParams{File: auditFile, MaxSize: 1, EventsNamespace: "audit"},
+				kubeClient)
+			defer agent.auditLog.close()
+
+			agent.cachedTenantDeclMap = map[string]as3Tenant{
+				"test": {
+					"class": "Tenant",
+					"app": map[string]interface{}{
+						"class":  "Application",
+						"crd_vs": map[string]interface{}{"class": "Service_HTTP", "virtualPort": 80},
+						"pool1":  map[string]interface{}{"class": "Pool"},
+					},
+				},
+			}
+			incoming := map[string]as3Tenant{
+				"test": {
+					"class": "Tenant",
+					"app": as3Application{
+						"class":  "Application",
+						"crd_vs": &as3Service{Class: "Service_HTTP", VirtualPort: 8080},
+						"pool2":  &as3Pool{Class: "Pool"},
+					},
+				},
+			}
+			rsCfg := &ResourceConfig{}
+			rsCfg.MetaData.baseResources = map[string]string{"default/vs": VirtualServer}
+			rsConfig := &ResourceConfigRequest{
+				reqId:     5,
+				ltmConfig: LTMConfig{"test": &PartitionConfig{ResourceMap: ResourceMap{"crd_vs": rsCfg}}},
+			}
+			agent.tenantResponseMap = map[string]tenantResponse{
+				"test": {agentResponseCode: 422, message: "invalid pool"},
+			}
+			agent.auditDeclaration(auditPost, rsConfig, []string{"test"}, incoming)
+
+			data, err := os.ReadFile(auditFile)
+			Expect(err).To(BeNil())
+			var rec auditRecord
+			Expect(json.Unmarshal(data, &rec)).To(BeNil())
+			Expect(rec.Operation).To(Equal(auditPost))
+			Expect(rec.RequestID).To(Equal(5))
+			Expect(rec.BigIPUser).To(Equal("admin"))
+			Expect(rec.Tenants).To(HaveLen(1))
+			Expect(rec.Tenants[0].Resources).To(Equal([]string{"VirtualServer/default/vs"}))
+			Expect(rec.Tenants[0].Added).To(Equal([]string{"app/pool2"}))
+			Expect(rec.Tenants[0].Modified).To(Equal([]string{"app/crd_vs"}))
+			Expect(rec.Tenants[0].Deleted).To(Equal([]string{"app/pool1"}))
+			Expect(rec.Tenants[0].Code).To(Equal(422))
+			Expect(rec.Tenants[0].Message).To(Equal("invalid pool"))
+
+			// Failed declaration is recorded as warning event with the audit record
+			var events *v1.EventList
+			Eventually(func() int {
+				events, _ = kubeClient.CoreV1().Events("audit").List(context.TODO(), metav1.ListOptions{})
+				return len(events.Items)
+			}).Should(Equal(1))
+			Expect(events.Items[0].Type).To(Equal(v1.EventTypeWarning))
+			Expect(events.Items[0].Reason).To(Equal(auditEventReason))
+			Expect(events.Items[0].Annotations[auditRecordAnnotation]).To(Equal(strings.TrimSpace(string(data))))
+
+			// Audit log file is rotated once it exceeds the size limit
+			agent.auditLog.size = 1024 * 1024
+			agent.auditDeclaration(auditRetry, nil, []string{"test"}, incoming)
+			rotated, err := os.ReadFile(auditFile + ".1")
+			Expect(err).To(BeNil())
+			Expect(rotated).To(Equal(data))
+			data, err = os.ReadFile(auditFile)
+			Expect(err).To(BeNil())
+			var retryRec auditRecord
+			Expect(json.Unmarshal(data, &retryRec)).To(BeNil())
+			Expect(retryRec.Operation).To(Equal(auditRetry))
+			Expect(retryRec.Tenants[0].Resources).To(BeEmpty())
+		})
 	})
 
 	Describe("GTM Config", func() {
@@ -373,7 +1000,16 @@ var _ = Describe("Backend Tests", func() {
 					Type:     "http",
 					Send:     "GET /health",
 				},
+				{
+					Name:          "pool1_monitor1",
+					Interval:      10,
+					Timeout:       10,
+					ProbeTimeout:  5,
+					Type:          "https",
+					SNIServerName: "test.com",
+				},
 			}
+			minimum := intstr.FromString("all")
 			gtmConfig := GTMConfig{
 				DEFAULT_PARTITION: GTMPartitionConfig{
 					WideIPs: map[string]WideIP{
@@ -388,6 +1024,9 @@ var _ = Describe("Backend Tests", func() {
 									LBMethod:   "round-robin",
 									Members:    []string{"vs1", "vs2"},
 									Monitors:   monitors,
+
+									MinimumMonitors: &minimum,
+									TTL:             60,
 								},
 							},
 						},
@@ -413,6 +1052,10 @@ var _ = Describe("Backend Tests", func() {
 
 			Expect(sharedApp).To(HaveKey("pool1_monitor"))
 			Expect(sharedApp["pool1_monitor"].(as3GSLBMonitor).Class).To(Equal("GSLB_Monitor"))
+			Expect(sharedApp["pool1"].(as3GSLBPool).MinimumMonitors).To(Equal("all"))
+			Expect(sharedApp["pool1"].(as3GSLBPool).TTL).To(BeEquivalentTo(60))
+			Expect(sharedApp["pool1_monitor1"].(as3GSLBMonitor).ProbeTimeout).To(Equal(5))
+			Expect(sharedApp["pool1_monitor1"].(as3GSLBMonitor).SNIServerName).To(Equal("test.com"))
 		})
 	})
 
@@ -432,6 +1075,64 @@ var _ = Describe("Backend Tests", func() {
 			Expect(ok).To(BeTrue())
 			Expect(val).NotTo(BeNil())
 		})
+		It("Service Address declaration per virtual address", func() {
+			rsCfg := &ResourceConfig{
+				ServiceAddress: []ServiceAddress{
+					{
+						VirtualAddress:     "1.2.3.4",
+						RouteAdvertisement: "selective",
+						TrafficGroup:       "/Common/traffic-group-1",
+					},
+					{
+						RouteAdvertisement: "disable",
+						TrafficGroup:       "/Common/traffic-group-2",
+					},
+				},
+			}
+			app := as3Application{}
+			Expect(createServiceAddressDecl(rsCfg, "1.2.3.4%2", app)).To(Equal("crd_service_address_1_2_3_4.2"))
+			Expect(createServiceAddressDecl(rsCfg, "1.2.3.5", app)).To(Equal("crd_service_address_1_2_3_5"))
+
+			sa := app["crd_service_address_1_2_3_4.2"].(*as3ServiceAddress)
+			Expect(sa.VirtualAddress).To(Equal("1.2.3.4%2"))
+			Expect(sa.TrafficGroup).To(Equal("/Common/traffic-group-1"))
+			Expect(sa.RouteAdvertisement).To(Equal("selective"))
+			sa = app["crd_service_address_1_2_3_5"].(*as3ServiceAddress)
+			Expect(sa.TrafficGroup).To(Equal("/Common/traffic-group-2"))
+
+			rsCfg.ServiceAddress = rsCfg.ServiceAddress[:1]
+			Expect(createVirtualAddressDecl(rsCfg, "1.2.3.5", app)).To(Equal("1.2.3.5"),
+				"Virtual address without service address not declared as address")
+		})
+		It("Service Address declaration with traffic group of the virtual", func() {
+			rsCfg := &ResourceConfig{}
+			rsCfg.Virtual.TrafficGroup = "/Common/traffic-group-local-only"
+			app := as3Application{}
+			Expect(createVirtualAddressDecl(rsCfg, "1.2.3.4", app)).To(Equal(&as3ResourcePointer{
+				Use: "crd_service_address_1_2_3_4",
+			}))
+			sa := app["crd_service_address_1_2_3_4"].(*as3ServiceAddress)
+			Expect(sa.TrafficGroup).To(Equal("/Common/traffic-group-local-only"))
+			Expect(sa.ArpEnabled).To(BeTrue())
+
+			rsCfg.ServiceAddress = []ServiceAddress{{TrafficGroup: "/Common/traffic-group-1"}}
+			app = as3Application{}
+			createServiceAddressDecl(rsCfg, "1.2.3.4", app)
+			Expect(app["crd_service_address_1_2_3_4"].(*as3ServiceAddress).TrafficGroup).To(Equal(
+				"/Common/traffic-group-1"), "serviceAddress trafficGroup not preferred")
+
+			// virtuals sharing the address in different traffic groups
+			otherCfg := &ResourceConfig{}
+			otherCfg.Virtual.TrafficGroup = "/Common/traffic-group-2"
+			createServiceAddressDecl(otherCfg, "1.2.3.4", app)
+			Expect(app["crd_service_address_1_2_3_4"].(*as3ServiceAddress).TrafficGroup).To(Equal(
+				"/Common/traffic-group-1"), "Conflicting traffic group replaced the service address")
+			app = as3Application{}
+			createServiceAddressDecl(otherCfg, "1.2.3.4", app)
+			createServiceAddressDecl(rsCfg, "1.2.3.4", app)
+			Expect(app["crd_service_address_1_2_3_4"].(*as3ServiceAddress).TrafficGroup).To(Equal(
+				"/Common/traffic-group-1"), "Traffic group depends on the order of the virtuals")
+		})
 		It("Test Deleted Partition", func() {
 			cisLabel := "test"
 			deletedPartition := getDeletedTenantDeclaration("test", "test", cisLabel)
@@ -0,0 +1,340 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+)
+
+const (
+	// Actions of the references not in the allow-list, resources are rejected or the references are removed
+	AllowListReject = "reject"
+	AllowListStrip  = "strip"
+
+	// categories of the BIG-IP objects of the allow-list
+	allowListProfile = "profile"
+	allowListIRule   = "iRule"
+	allowListWAF     = "waf"
+)
+
+type (
+	// BigIPAllowList of the extended ConfigMap is the list of the BIG-IP profiles, iRules and WAF policies which
+	// the resources are allowed to refer, other BIG-IP objects of the categories are not referred
+	BigIPAllowList struct {
+		Action      string   `yaml:"action,omitempty"`
+		Profiles    []string `yaml:"profiles"`
+		IRules      []string `yaml:"iRules"`
+		WAFPolicies []string `yaml:"wafPolicies"`
+	}
+
+	// allowListFields are the fields of a resource referring the BIG-IP objects of the allow-list categories
+	allowListFields struct {
+		values []allowListValue
+		lists  []allowListValues
+	}
+
+	allowListValue struct {
+		category string
+		value    *string
+	}
+
+	allowListValues struct {
+		category string
+		values   *[]string
+	}
+
+	// referenceCheck is the result of the validation of the BIG-IP references of a resource, resource is not
+	// processed when rejected and message is the message of its status condition
+	referenceCheck struct {
+		rejected bool
+		reason   string
+		message  string
+	}
+)
+
+// validate validates the action and the paths of the allow-list
+func (al *BigIPAllowList) validate() error {
+	if al.Action != "" && al.Action != AllowListReject && al.Action != AllowListStrip {
+		return fmt.Errorf("invalid action %v of bigipAllowList, supported values are %v and %v", al.Action,
+			AllowListReject, AllowListStrip)
+	}
+	for _, path := range append(append(append([]string{}, al.Profiles...), al.IRules...), al.WAFPolicies...) {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("invalid path %v of bigipAllowList, full path of the BIG-IP object is required", path)
+		}
+	}
+	return nil
+}
+
+// isAllowed checks whether the BIG-IP object of the category is in the allow-list, values which are not the paths
+// of BIG-IP objects such as the AS3 built-in profiles are always allowed
+func (al *BigIPAllowList) isAllowed(category, path string) bool {
+	if al == nil || !strings.HasPrefix(path, "/") {
+		return true
+	}
+	allowed := al.Profiles
+	switch category {
+	case allowListIRule:
+		allowed = al.IRules
+	case allowListWAF:
+		allowed = al.WAFPolicies
+	}
+	for _, allowedPath := range allowed {
+		if allowedPath == path {
+			return true
+		}
+	}
+	return false
+}
+
+// getDenied returns the BIG-IP objects referred in the fields which are not in the allow-list
+func (al *BigIPAllowList) getDenied(fields *allowListFields) []string {
+	denied := make(map[string]struct{})
+	for _, field := range fields.values {
+		if !al.isAllowed(field.category, *field.value) {
+			denied[*field.value] = struct{}{}
+		}
+	}
+	for _, field := range fields.lists {
+		for _, value := range *field.values {
+			if !al.isAllowed(field.category, value) {
+				denied[value] = struct{}{}
+			}
+		}
+	}
+	var result []string
+	for path := range denied {
+		result = append(result, path)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// strip removes the BIG-IP objects which are not in the allow-list from the fields
+func (al *BigIPAllowList) strip(fields *allowListFields) {
+	for _, field := range fields.values {
+		if !al.isAllowed(field.category, *field.value) {
+			*field.value = ""
+		}
+	}
+	for _, field := range fields.lists {
+		var values []string
+		for _, value := range *field.values {
+			if al.isAllowed(field.category, value) {
+				values = append(values, value)
+			}
+		}
+		*field.values = values
+	}
+}
+
+func (fields *allowListFields) add(category string, values ...*string) {
+	for _, value := range values {
+		fields.values = append(fields.values, allowListValue{category: category, value: value})
+	}
+}
+
+func (fields *allowListFields) addList(category string, values ...*[]string) {
+	for _, value := range values {
+		fields.lists = append(fields.lists, allowListValues{category: category, values: value})
+	}
+}
+
+// addProfileSpec adds the fields of the profiles referring the BIG-IP profiles
+func (fields *allowListFields) addProfileSpec(profiles *cisapiv1.ProfileSpec) {
+	fields.add(allowListProfile, &profiles.TCP.Client, &profiles.TCP.Server, &profiles.UDP, &profiles.HTTP,
+		&profiles.HTTP2.Client, &profiles.HTTP2.Server, &profiles.RewriteProfile, &profiles.PersistenceProfile,
+		&profiles.ProfileL4, &profiles.ProfileMultiplex, &profiles.AnalyticsProfiles.HTTPAnalyticsProfile,
+		&profiles.AnalyticsProfiles.TCPAnalyticsProfile, &profiles.ProfileWebSocket,
+		&profiles.ProfileHTTPCompression, &profiles.ProfileWebAcceleration, &profiles.ProfileAccess,
+		&profiles.PolicyPerRequestAccess)
+	fields.addList(allowListProfile, &profiles.LogProfiles, &profiles.SSLProfiles.ClientProfiles,
+		&profiles.SSLProfiles.ServerProfiles)
+}
+
+// addPolicySpec adds the fields of the policy referring the BIG-IP profiles, iRules and WAF policies
+func (fields *allowListFields) addPolicySpec(spec *cisapiv1.PolicySpec) {
+	fields.add(allowListWAF, &spec.L7Policies.WAF)
+	fields.add(allowListIRule, &spec.IRules.Secure, &spec.IRules.InSecure)
+	fields.addList(allowListIRule, &spec.IRuleList)
+	fields.addProfileSpec(&spec.Profiles)
+}
+
+// getVirtualServerAllowListFields returns the fields of the spec of the VirtualServer referring the BIG-IP objects
+func getVirtualServerAllowListFields(vs *cisapiv1.VirtualServer) *allowListFields {
+	fields := &allowListFields{}
+	fields.add(allowListWAF, &vs.Spec.WAF)
+	for i := range vs.Spec.Pools {
+		fields.add(allowListWAF, &vs.Spec.Pools[i].WAF)
+	}
+	fields.addList(allowListIRule, &vs.Spec.IRules)
+	fields.add(allowListProfile, &vs.Spec.PersistenceProfile, &vs.Spec.ProfileMultiplex)
+	fields.addProfileSpec(&vs.Spec.Profiles)
+	return fields
+}
+
+// getTransportServerAllowListFields returns the fields of the spec of the TransportServer referring the
+// BIG-IP objects
+func getTransportServerAllowListFields(ts *cisapiv1.TransportServer) *allowListFields {
+	fields := &allowListFields{}
+	fields.addList(allowListIRule, &ts.Spec.IRules)
+	fields.add(allowListProfile, &ts.Spec.PersistenceProfile, &ts.Spec.ProfileL4)
+	fields.addProfileSpec(&ts.Spec.Profiles)
+	return fields
+}
+
+// getNamespacedPolicyAllowListFields returns the fields of the policy of the resource and the namespace policy
+// referring the BIG-IP objects, the default policy is managed by the administrator and not validated
+func (ctlr *Controller) getNamespacedPolicyAllowListFields(namespace, plcName string) *allowListFields {
+	fields := &allowListFields{}
+	comInf, ok := ctlr.getNamespacedCommonInformer(namespace)
+	if !ok || comInf.plcInformer == nil {
+		return fields
+	}
+	if plcName != "" && namespace+"/"+plcName != ctlr.defaultPolicy {
+		if obj, found, _ := comInf.plcInformer.GetIndexer().GetByKey(namespace + "/" + plcName); found {
+			fields.addPolicySpec(&obj.(*cisapiv1.Policy).DeepCopy().Spec)
+		}
+	}
+	if nsPlc := ctlr.getNamespacePolicy(namespace); nsPlc != nil && nsPlc.Name != plcName &&
+		namespace+"/"+nsPlc.Name != ctlr.defaultPolicy {
+		fields.addPolicySpec(&nsPlc.DeepCopy().Spec)
+	}
+	return fields
+}
+
+// checkAllowList validates the references of the resource with the allow-list, references of the spec of the
+// resource are removed with the strip action while the references of the policies and the TLSProfile which are
+// shared by the resources are always rejected. Returns whether the references of the spec are to be stripped
+func (ctlr *Controller) checkAllowList(fields, sharedFields *allowListFields) (referenceCheck, bool) {
+	al := ctlr.bigipAllowList
+	if al == nil {
+		return referenceCheck{}, false
+	}
+	if denied := al.getDenied(sharedFields); len(denied) > 0 {
+		return referenceCheck{rejected: true, reason: "NotInAllowList", message: fmt.Sprintf(
+			"BIG-IP references %v of the Policy or TLSProfile are not in the allow-list", strings.Join(denied, ", "))}, false
+	}
+	denied := al.getDenied(fields)
+	if len(denied) == 0 {
+		return referenceCheck{}, false
+	}
+	if al.Action != AllowListStrip {
+		return referenceCheck{rejected: true, reason: "NotInAllowList", message: fmt.Sprintf(
+			"BIG-IP references %v are not in the allow-list", strings.Join(denied, ", "))}, false
+	}
+	return referenceCheck{reason: "Stripped", message: fmt.Sprintf(
+		"BIG-IP references %v are not in the allow-list and removed", strings.Join(denied, ", "))}, true
+}
+
+// checkVirtualServerReferences validates the BIG-IP references of the VirtualServer, returns the VirtualServer
+// with the references stripped when they are not in the allow-list
+func (ctlr *Controller) checkVirtualServerReferences(vs *cisapiv1.VirtualServer) (*cisapiv1.VirtualServer, referenceCheck) {
+	if denied := ctlr.getDeniedVirtualServerReferences(vs); len(denied) > 0 {
+		return vs, referenceCheck{rejected: true, reason: "NotAllowed", message: fmt.Sprintf(
+			"BIG-IP references %v are not allowed for namespace %v", strings.Join(denied, ", "), vs.Namespace)}
+	}
+	if ctlr.bigipAllowList == nil {
+		return vs, referenceCheck{}
+	}
+	sharedFields := ctlr.getNamespacedPolicyAllowListFields(vs.Namespace, vs.Spec.PolicyName)
+	if vs.Spec.TLSProfileName != "" {
+		if crInf, ok := ctlr.getNamespacedCRInformer(vs.Namespace); ok && crInf.tlsInformer != nil {
+			obj, found, _ := crInf.tlsInformer.GetIndexer().GetByKey(vs.Namespace + "/" + vs.Spec.TLSProfileName)
+			if found && obj.(*cisapiv1.TLSProfile).Spec.TLS.Reference == BIGIP {
+				tls := obj.(*cisapiv1.TLSProfile).DeepCopy()
+				sharedFields.add(allowListProfile, &tls.Spec.TLS.ClientSSL, &tls.Spec.TLS.ServerSSL)
+				sharedFields.addList(allowListProfile, &tls.Spec.TLS.ClientSSLs, &tls.Spec.TLS.ServerSSLs)
+			}
+		}
+	}
+	check, strip := ctlr.checkAllowList(getVirtualServerAllowListFields(vs), sharedFields)
+	if strip {
+		vs = vs.DeepCopy()
+		ctlr.bigipAllowList.strip(getVirtualServerAllowListFields(vs))
+	}
+	return vs, check
+}
+
+// getAllowedTransportServer validates the BIG-IP references of the TransportServer, returns the TransportServer
+// with the references stripped and whether it is rejected
+func (ctlr *Controller) getAllowedTransportServer(ts *cisapiv1.TransportServer) (*cisapiv1.TransportServer, bool) {
+	if len(ctlr.bigipReferences) == 0 && ctlr.bigipAllowList == nil {
+		return ts, false
+	}
+	var check referenceCheck
+	paths := getTransportServerBigIPReferences(ts)
+	paths = append(paths, ctlr.getNamespacedPolicyBigIPReferences(ts.Namespace, ts.Spec.PolicyName)...)
+	allowed := ts
+	if denied := ctlr.bigipReferences.getDeniedReferences(ts.Namespace, paths); len(denied) > 0 {
+		check = referenceCheck{rejected: true, reason: "NotAllowed", message: fmt.Sprintf(
+			"BIG-IP references %v are not allowed for namespace %v", strings.Join(denied, ", "), ts.Namespace)}
+	} else {
+		var strip bool
+		check, strip = ctlr.checkAllowList(getTransportServerAllowListFields(ts),
+			ctlr.getNamespacedPolicyAllowListFields(ts.Namespace, ts.Spec.PolicyName))
+		if strip {
+			allowed = ts.DeepCopy()
+			ctlr.bigipAllowList.strip(getTransportServerAllowListFields(allowed))
+		}
+	}
+	ctlr.setBigIPReferenceCondition(ts, TransportServer, check)
+	return allowed, check.rejected
+}
+
+// getAllowedIngressLink returns the IngressLink with the iRules which are not in the allow-list stripped
+func (ctlr *Controller) getAllowedIngressLink(il *cisapiv1.IngressLink) *cisapiv1.IngressLink {
+	al := ctlr.bigipAllowList
+	if al == nil || al.Action != AllowListStrip {
+		return il
+	}
+	fields := &allowListFields{}
+	fields.addList(allowListIRule, &il.Spec.IRules)
+	if denied := al.getDenied(fields); len(denied) > 0 {
+		log.Warningf("IngressLink %v/%v iRules %v are not in the allow-list and removed", il.Namespace, il.Name,
+			strings.Join(denied, ", "))
+		il = il.DeepCopy()
+		fields = &allowListFields{}
+		fields.addList(allowListIRule, &il.Spec.IRules)
+		al.strip(fields)
+	}
+	return il
+}
+
+// setBigIPAllowList sets the allow-list of the global extended ConfigMap, all the VirtualServers and
+// TransportServers are processed again with the extended ConfigMap
+func (ctlr *Controller) setBigIPAllowList(al *BigIPAllowList, isDelete bool) error {
+	if isDelete || al == nil {
+		if ctlr.bigipAllowList != nil {
+			log.Infof("BIG-IP allow-list is removed, resources refer any BIG-IP object")
+		}
+		ctlr.bigipAllowList = nil
+		return nil
+	}
+	if err := al.validate(); err != nil {
+		return err
+	}
+	ctlr.bigipAllowList = al
+	log.Debugf("BIG-IP allow-list of profiles %v, iRules %v and WAF policies %v", al.Profiles, al.IRules,
+		al.WAFPolicies)
+	return nil
+}
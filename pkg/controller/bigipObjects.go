@@ -0,0 +1,232 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConditionBigIPObjectNotFound is the status condition of the VirtualServers referring the LTM policies and
+	// iRules which are not found on BIG-IP
+	ConditionBigIPObjectNotFound = "BigIPObjectNotFound"
+
+	// kinds of the LTM objects in the REST API of BIG-IP
+	bigipObjectPolicy = "policy"
+	bigipObjectIRule  = "rule"
+)
+
+type (
+	// bigipObjectRef is the LTM object of BIG-IP with its full path
+	bigipObjectRef struct {
+		kind string
+		path string
+	}
+
+	// bigipObjectStore caches the existence of the LTM objects referred by the resources, existence is verified
+	// again periodically
+	bigipObjectStore struct {
+		sync.Mutex
+		objects map[bigipObjectRef]bool
+	}
+)
+
+// getBigIPObjectURL returns the REST URL of the LTM object
+func (postMgr *PostManager) getBigIPObjectURL(ref bigipObjectRef) string {
+	return fmt.Sprintf("%v/mgmt/tm/ltm/%v/%v?$select=name", postMgr.getDeviceBaseURL(), ref.kind,
+		strings.ReplaceAll(ref.path, "/", "~"))
+}
+
+// bigipObjectExists checks whether the LTM object exists on BIG-IP
+func (postMgr *PostManager) bigipObjectExists(ref bigipObjectRef) (bool, error) {
+	req, err := http.NewRequest("GET", postMgr.getBigIPObjectURL(ref), nil)
+	if err != nil {
+		return false, err
+	}
+	postMgr.setAuthHeader(req)
+
+	httpResp, _ := postMgr.httpCachedReq(req)
+	if httpResp == nil {
+		return false, fmt.Errorf("Internal Error")
+	}
+	switch httpResp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	return false, fmt.Errorf("Error response from BIGIP with status code %v", httpResp.StatusCode)
+}
+
+// getVirtualServerBigIPObjects returns the LTM policies and iRules of BIG-IP referred by the VirtualServer,
+// iRules of the shared bundle are installed by CIS and not verified
+func (ctlr *Controller) getVirtualServerBigIPObjects(vs *cisapiv1.VirtualServer) []bigipObjectRef {
+	var refs []bigipObjectRef
+	for _, path := range appendBigIPPaths(nil, vs.Spec.BigIPPolicies...) {
+		refs = append(refs, bigipObjectRef{kind: bigipObjectPolicy, path: path})
+	}
+	for _, path := range appendBigIPPaths(nil, vs.Spec.IRules...) {
+		if !ctlr.resources.sharedBundle.hasIRule(path) {
+			refs = append(refs, bigipObjectRef{kind: bigipObjectIRule, path: path})
+		}
+	}
+	return refs
+}
+
+// getMissingBigIPObjects returns the paths of the LTM objects which are not found on BIG-IP, objects are not
+// reported missing when their existence can't be verified
+func (ctlr *Controller) getMissingBigIPObjects(refs []bigipObjectRef) []string {
+	var missing []string
+	for _, ref := range refs {
+		ctlr.bigipObjects.Lock()
+		exists, ok := ctlr.bigipObjects.objects[ref]
+		ctlr.bigipObjects.Unlock()
+		if !ok {
+			var err error
+			if exists, err = ctlr.Agent.PostManager.bigipObjectExists(ref); err != nil {
+				log.Warningf("Unable to verify the BIG-IP %v %v: %v", ref.kind, ref.path, err)
+				continue
+			}
+			ctlr.bigipObjects.Lock()
+			if ctlr.bigipObjects.objects == nil {
+				ctlr.bigipObjects.objects = make(map[bigipObjectRef]bool)
+			}
+			ctlr.bigipObjects.objects[ref] = exists
+			ctlr.bigipObjects.Unlock()
+		}
+		if !exists {
+			missing = append(missing, ref.path)
+		}
+	}
+	sort.Strings(missing)
+	return uniqueNames(missing)
+}
+
+// filterMissingBigIPObjects excludes the VirtualServers referring the LTM policies and iRules not found on BIG-IP
+// from the declaration, so that the declaration doesn't fail on BIG-IP
+func (ctlr *Controller) filterMissingBigIPObjects(virtuals []*cisapiv1.VirtualServer) []*cisapiv1.VirtualServer {
+	if ctlr.bigipObjectCheckInterval <= 0 {
+		return virtuals
+	}
+	var result []*cisapiv1.VirtualServer
+	for _, vs := range virtuals {
+		missing := ctlr.getMissingBigIPObjects(ctlr.getVirtualServerBigIPObjects(vs))
+		ingName, isIngress := getIngressNameForVirtualServer(vs)
+		if len(missing) == 0 {
+			if !isIngress {
+				ctlr.removeResourceCondition(vs, ConditionBigIPObjectNotFound)
+			}
+			result = append(result, vs)
+			continue
+		}
+		message := fmt.Sprintf("BIG-IP objects %v are not found", strings.Join(missing, ", "))
+		if isIngress {
+			// VirtualServers derived from the Ingresses have no status
+			log.Errorf("Ingress %v/%v is not processed, %v", vs.Namespace, ingName, message)
+			continue
+		}
+		log.Errorf("VirtualServer %v/%v is not processed, %v", vs.Namespace, vs.Name, message)
+		ctlr.setResourceCondition(vs, metav1.Condition{
+			Type:               ConditionBigIPObjectNotFound,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: vs.GetGeneration(),
+			Reason:             "NotFound",
+			Message:            message,
+		})
+	}
+	return result
+}
+
+// refersBigIPObjects checks whether the VirtualServer refers any of the LTM objects
+func (ctlr *Controller) refersBigIPObjects(vs *cisapiv1.VirtualServer, refs []bigipObjectRef) bool {
+	for _, ref := range ctlr.getVirtualServerBigIPObjects(vs) {
+		for _, changed := range refs {
+			if ref == changed {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getVirtualServersForBigIPObjects returns the VirtualServers referring any of the LTM objects
+func (ctlr *Controller) getVirtualServersForBigIPObjects(refs []bigipObjectRef) []*cisapiv1.VirtualServer {
+	var virtuals []*cisapiv1.VirtualServer
+	for _, vs := range ctlr.getAllVSFromMonitoredNamespaces() {
+		if ctlr.refersBigIPObjects(vs, refs) {
+			virtuals = append(virtuals, vs)
+		}
+	}
+	return virtuals
+}
+
+// refreshBigIPObjects verifies the existence of the cached LTM objects again and returns the objects created or
+// deleted on BIG-IP since the last verification
+func (ctlr *Controller) refreshBigIPObjects() []bigipObjectRef {
+	ctlr.bigipObjects.Lock()
+	refs := make([]bigipObjectRef, 0, len(ctlr.bigipObjects.objects))
+	for ref := range ctlr.bigipObjects.objects {
+		refs = append(refs, ref)
+	}
+	ctlr.bigipObjects.Unlock()
+
+	var changed []bigipObjectRef
+	for _, ref := range refs {
+		// cached response is purged so that the objects created or deleted on BIG-IP are detected
+		ctlr.Agent.PostManager.purgeBigIPResponse(ctlr.Agent.PostManager.getBigIPObjectURL(ref))
+		exists, err := ctlr.Agent.PostManager.bigipObjectExists(ref)
+		if err != nil {
+			log.Warningf("Unable to verify the BIG-IP %v %v: %v", ref.kind, ref.path, err)
+			continue
+		}
+		ctlr.bigipObjects.Lock()
+		if cached, ok := ctlr.bigipObjects.objects[ref]; ok && cached != exists {
+			if exists {
+				log.Infof("BIG-IP %v %v is created", ref.kind, ref.path)
+			} else {
+				log.Infof("BIG-IP %v %v is deleted", ref.kind, ref.path)
+			}
+			ctlr.bigipObjects.objects[ref] = exists
+			changed = append(changed, ref)
+		}
+		ctlr.bigipObjects.Unlock()
+	}
+	return changed
+}
+
+// monitorBigIPObjects verifies the existence of the LTM objects referred by the VirtualServers periodically,
+// VirtualServers referring the objects created or deleted on BIG-IP are processed again
+func (ctlr *Controller) monitorBigIPObjects() {
+	for {
+		<-time.After(time.Duration(ctlr.bigipObjectCheckInterval) * time.Second)
+		if changed := ctlr.refreshBigIPObjects(); len(changed) > 0 {
+			ctlr.resourceQueue.Add(&rqKey{
+				kind: BigIPObjectUpdate,
+				rsc:  changed,
+			})
+		}
+	}
+}
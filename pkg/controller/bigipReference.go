@@ -0,0 +1,283 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConditionBigIPReferenceDenied is the VirtualServer and TransportServer status condition set when the
+// resource refers the BIG-IP objects which are not allowed for its namespace or not in the allow-list
+const ConditionBigIPReferenceDenied = "BigIPReferenceDenied"
+
+// allNamespacesReference is the namespace of the BIG-IP references allowed for all the namespaces
+const allNamespacesReference = "*"
+
+// BigIPReferences holds the path prefixes of the BIG-IP objects, such as the profiles and iRules in /Common, which
+// the resources of the namespace are allowed to refer. Resources refer any BIG-IP object when it is empty
+type BigIPReferences map[string][]string
+
+// ParseBigIPReferences parses the BIG-IP references in the format <namespace>:<path prefix>, namespace * allows
+// the path prefix for all the namespaces
+func ParseBigIPReferences(refs []string) (BigIPReferences, error) {
+	bigipRefs := make(BigIPReferences)
+	for _, ref := range refs {
+		splits := strings.SplitN(ref, ":", 2)
+		if len(splits) != 2 || splits[0] == "" || !strings.HasPrefix(splits[1], "/") {
+			return nil, fmt.Errorf("invalid BIG-IP reference %v, format is <namespace>:<path prefix>", ref)
+		}
+		bigipRefs[splits[0]] = append(bigipRefs[splits[0]], splits[1])
+	}
+	return bigipRefs, nil
+}
+
+// isAllowed checks whether the resources of the namespace are allowed to refer the BIG-IP object path
+func (refs BigIPReferences) isAllowed(namespace, path string) bool {
+	if len(refs) == 0 {
+		return true
+	}
+	for _, prefix := range append(refs[namespace], refs[allNamespacesReference]...) {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// getDeniedReferences returns the BIG-IP object paths which the resources of the namespace are not allowed to refer
+func (refs BigIPReferences) getDeniedReferences(namespace string, paths []string) []string {
+	denied := make(map[string]struct{})
+	for _, path := range paths {
+		if !refs.isAllowed(namespace, path) {
+			denied[path] = struct{}{}
+		}
+	}
+	var result []string
+	for path := range denied {
+		result = append(result, path)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// appendBigIPPaths appends the values which are the paths of the BIG-IP objects, other values such as the
+// names of the AS3 built-in profiles or snat auto are not BIG-IP references
+func appendBigIPPaths(paths []string, values ...string) []string {
+	for _, value := range values {
+		if strings.HasPrefix(value, "/") {
+			paths = append(paths, value)
+		}
+	}
+	return paths
+}
+
+// getMonitorBigIPReferences returns the BIG-IP monitors referred by the monitors
+func getMonitorBigIPReferences(monitors ...cisapiv1.Monitor) []string {
+	var paths []string
+	for _, monitor := range monitors {
+		if monitor.Reference == BIGIP {
+			paths = appendBigIPPaths(paths, monitor.Name)
+		}
+	}
+	return paths
+}
+
+// getPoolBigIPReferences returns the BIG-IP objects referred by the pool
+func getPoolBigIPReferences(pool cisapiv1.Pool) []string {
+	paths := appendBigIPPaths(nil, pool.WAF)
+	return append(paths, getMonitorBigIPReferences(append([]cisapiv1.Monitor{pool.Monitor}, pool.Monitors...)...)...)
+}
+
+// getProfileSpecBigIPReferences returns the BIG-IP profiles referred by the profiles
+func getProfileSpecBigIPReferences(profiles cisapiv1.ProfileSpec) []string {
+	paths := appendBigIPPaths(nil, profiles.TCP.Client, profiles.TCP.Server, profiles.UDP, profiles.HTTP,
+		profiles.HTTP2.Client, profiles.HTTP2.Server, profiles.RewriteProfile, profiles.PersistenceProfile,
+		profiles.ProfileL4, profiles.ProfileMultiplex, profiles.AnalyticsProfiles.HTTPAnalyticsProfile,
+		profiles.AnalyticsProfiles.TCPAnalyticsProfile, profiles.ProfileWebSocket, profiles.ProfileHTTPCompression,
+		profiles.ProfileWebAcceleration, profiles.ProfileAccess, profiles.PolicyPerRequestAccess)
+	paths = appendBigIPPaths(paths, profiles.LogProfiles...)
+	paths = appendBigIPPaths(paths, profiles.SSLProfiles.ClientProfiles...)
+	return appendBigIPPaths(paths, profiles.SSLProfiles.ServerProfiles...)
+}
+
+// getPolicyBigIPReferences returns the BIG-IP objects referred by the policy
+func getPolicyBigIPReferences(spec cisapiv1.PolicySpec) []string {
+	paths := appendBigIPPaths(nil, spec.L7Policies.WAF, spec.L3Policies.DOS, spec.L3Policies.BotDefense,
+		spec.L3Policies.FirewallPolicy, spec.L3Policies.IpIntelligencePolicy, spec.LtmPolicies.Secure,
+		spec.LtmPolicies.InSecure, spec.IRules.Secure, spec.IRules.InSecure, spec.SNAT)
+	paths = appendBigIPPaths(paths, spec.L3Policies.AllowVlans...)
+	paths = appendBigIPPaths(paths, spec.IRuleList...)
+	return append(paths, getProfileSpecBigIPReferences(spec.Profiles)...)
+}
+
+// getTLSProfileBigIPReferences returns the BIG-IP SSL profiles and keys referred by the TLSProfile
+func getTLSProfileBigIPReferences(tls *cisapiv1.TLSProfile) []string {
+	var paths []string
+	if tls.Spec.TLS.Reference == BIGIP {
+		paths = appendBigIPPaths(paths, tls.Spec.TLS.ClientSSL, tls.Spec.TLS.ServerSSL)
+		paths = appendBigIPPaths(paths, tls.Spec.TLS.ClientSSLs...)
+		paths = appendBigIPPaths(paths, tls.Spec.TLS.ServerSSLs...)
+	}
+	for _, privateKey := range tls.Spec.TLS.PrivateKeys {
+		paths = appendBigIPPaths(paths, privateKey.BigIPKey)
+	}
+	return paths
+}
+
+// getVirtualServerBigIPReferences returns the BIG-IP objects referred in the spec of the VirtualServer
+func getVirtualServerBigIPReferences(vs *cisapiv1.VirtualServer) []string {
+	paths := appendBigIPPaths(nil, vs.Spec.WAF, vs.Spec.SNAT, vs.Spec.PersistenceProfile, vs.Spec.ProfileMultiplex,
+		vs.Spec.DOS, vs.Spec.BotDefense)
+	paths = appendBigIPPaths(paths, vs.Spec.IRules...)
+	paths = appendBigIPPaths(paths, vs.Spec.BigIPPolicies...)
+	paths = appendBigIPPaths(paths, vs.Spec.AllowVLANs...)
+	paths = appendBigIPPaths(paths, vs.Spec.RejectVLANs...)
+	paths = append(paths, getProfileSpecBigIPReferences(vs.Spec.Profiles)...)
+	for _, pool := range vs.Spec.Pools {
+		paths = append(paths, getPoolBigIPReferences(pool)...)
+	}
+	if vs.Spec.DefaultPool.Reference == BIGIP {
+		paths = appendBigIPPaths(paths, vs.Spec.DefaultPool.Name)
+	}
+	return append(paths, getMonitorBigIPReferences(vs.Spec.DefaultPool.Monitors...)...)
+}
+
+// getTransportServerBigIPReferences returns the BIG-IP objects referred in the spec of the TransportServer
+func getTransportServerBigIPReferences(ts *cisapiv1.TransportServer) []string {
+	paths := appendBigIPPaths(nil, ts.Spec.SNAT, ts.Spec.PersistenceProfile, ts.Spec.ProfileL4, ts.Spec.DOS,
+		ts.Spec.BotDefense)
+	paths = appendBigIPPaths(paths, ts.Spec.IRules...)
+	paths = appendBigIPPaths(paths, ts.Spec.AllowVLANs...)
+	paths = appendBigIPPaths(paths, ts.Spec.RejectVLANs...)
+	paths = append(paths, getProfileSpecBigIPReferences(ts.Spec.Profiles)...)
+	paths = append(paths, getPoolBigIPReferences(ts.Spec.Pool)...)
+	for _, route := range ts.Spec.SNIRoutes {
+		paths = append(paths, getPoolBigIPReferences(route.Pool)...)
+	}
+	return paths
+}
+
+// getNamespacedPolicyBigIPReferences returns the BIG-IP objects referred by the policy of the resource and the
+// namespace policy, the default policy is managed by the administrator and not validated
+func (ctlr *Controller) getNamespacedPolicyBigIPReferences(namespace, plcName string) []string {
+	var paths []string
+	comInf, ok := ctlr.getNamespacedCommonInformer(namespace)
+	if !ok || comInf.plcInformer == nil {
+		return paths
+	}
+	if plcName != "" && namespace+"/"+plcName != ctlr.defaultPolicy {
+		if obj, found, _ := comInf.plcInformer.GetIndexer().GetByKey(namespace + "/" + plcName); found {
+			paths = append(paths, getPolicyBigIPReferences(obj.(*cisapiv1.Policy).Spec)...)
+		}
+	}
+	if nsPlc := ctlr.getNamespacePolicy(namespace); nsPlc != nil && nsPlc.Name != plcName &&
+		namespace+"/"+nsPlc.Name != ctlr.defaultPolicy {
+		paths = append(paths, getPolicyBigIPReferences(nsPlc.Spec)...)
+	}
+	return paths
+}
+
+// getDeniedVirtualServerReferences returns the BIG-IP objects referred by the VirtualServer, its policies and its
+// TLSProfile which are not allowed for the namespace of the VirtualServer
+func (ctlr *Controller) getDeniedVirtualServerReferences(vs *cisapiv1.VirtualServer) []string {
+	paths := getVirtualServerBigIPReferences(vs)
+	paths = append(paths, ctlr.getNamespacedPolicyBigIPReferences(vs.Namespace, vs.Spec.PolicyName)...)
+	if vs.Spec.TLSProfileName != "" {
+		if crInf, ok := ctlr.getNamespacedCRInformer(vs.Namespace); ok && crInf.tlsInformer != nil {
+			obj, found, _ := crInf.tlsInformer.GetIndexer().GetByKey(vs.Namespace + "/" + vs.Spec.TLSProfileName)
+			if found {
+				paths = append(paths, getTLSProfileBigIPReferences(obj.(*cisapiv1.TLSProfile))...)
+			}
+		}
+	}
+	return ctlr.bigipReferences.getDeniedReferences(vs.Namespace, paths)
+}
+
+// setBigIPReferenceCondition sets the BigIPReferenceDenied condition of the resource referring the BIG-IP objects
+// which are not allowed and removes it once the resource refers only the allowed objects
+func (ctlr *Controller) setBigIPReferenceCondition(rsc metav1.Object, kind string, check referenceCheck) {
+	if check.message == "" {
+		ctlr.removeResourceCondition(rsc, ConditionBigIPReferenceDenied)
+		return
+	}
+	if check.rejected {
+		log.Errorf("%v %v/%v is not processed, %v", kind, rsc.GetNamespace(), rsc.GetName(), check.message)
+	} else {
+		log.Warningf("%v %v/%v %v", kind, rsc.GetNamespace(), rsc.GetName(), check.message)
+	}
+	ctlr.setResourceCondition(rsc, metav1.Condition{
+		Type:               ConditionBigIPReferenceDenied,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: rsc.GetGeneration(),
+		Reason:             check.reason,
+		Message:            check.message,
+	})
+}
+
+// filterAllowedBigIPReferences returns the VirtualServers referring only the BIG-IP objects allowed for their
+// namespaces and in the allow-list, VirtualServers referring the other objects are excluded from the declaration
+// or the references are stripped with the strip action of the allow-list
+func (ctlr *Controller) filterAllowedBigIPReferences(virtuals []*cisapiv1.VirtualServer) []*cisapiv1.VirtualServer {
+	if len(ctlr.bigipReferences) == 0 && ctlr.bigipAllowList == nil {
+		return virtuals
+	}
+	var result []*cisapiv1.VirtualServer
+	for _, vs := range virtuals {
+		allowed, check := ctlr.checkVirtualServerReferences(vs)
+		if ingName, ok := getIngressNameForVirtualServer(vs); ok {
+			// VirtualServers derived from the Ingresses have no status
+			if check.message != "" {
+				log.Errorf("Ingress %v/%v %v", vs.Namespace, ingName, check.message)
+			}
+		} else {
+			ctlr.setBigIPReferenceCondition(vs, VirtualServer, check)
+		}
+		if check.rejected {
+			continue
+		}
+		result = append(result, allowed)
+	}
+	return result
+}
+
+// checkIngressLinkBigIPReferences checks whether the iRules of the IngressLink are allowed for its namespace and
+// in the allow-list, iRules not in the allow-list are stripped with the strip action
+func (ctlr *Controller) checkIngressLinkBigIPReferences(il *cisapiv1.IngressLink) bool {
+	denied := ctlr.bigipReferences.getDeniedReferences(il.Namespace, appendBigIPPaths(nil, il.Spec.IRules...))
+	if len(denied) > 0 {
+		log.Errorf("IngressLink %v/%v is not processed, BIG-IP references %v are not allowed for namespace %v",
+			il.Namespace, il.Name, strings.Join(denied, ", "), il.Namespace)
+		return false
+	}
+	if al := ctlr.bigipAllowList; al != nil && al.Action != AllowListStrip {
+		fields := &allowListFields{}
+		iRules := append([]string{}, il.Spec.IRules...)
+		fields.addList(allowListIRule, &iRules)
+		if denied = al.getDenied(fields); len(denied) > 0 {
+			log.Errorf("IngressLink %v/%v is not processed, iRules %v are not in the allow-list", il.Namespace,
+				il.Name, strings.Join(denied, ", "))
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+)
+
+type (
+	// bigipResponseCache caches the responses of the GET requests to BIG-IP by URL, so that the objects referred
+	// by many resources are queried once in the TTL
+	bigipResponseCache struct {
+		sync.Mutex
+		responses map[string]bigipCachedResponse
+	}
+
+	// bigipCachedResponse is the status code and the decoded body of the cached response
+	bigipCachedResponse struct {
+		statusCode int
+		response   map[string]interface{}
+		expiry     time.Time
+	}
+)
+
+// isBigIPResponseCacheable checks whether the response is cached, failed requests are not cached so that they
+// are retried with the next request
+func isBigIPResponseCacheable(statusCode int) bool {
+	return statusCode == http.StatusOK || statusCode == http.StatusNotFound
+}
+
+// httpCachedReq returns the cached response of the GET request while the TTL is not elapsed, BIG-IP is queried
+// otherwise. Responses are not cached when BigIPResponseCacheTTL is 0
+func (postMgr *PostManager) httpCachedReq(request *http.Request) (*http.Response, map[string]interface{}) {
+	if postMgr.BigIPResponseCacheTTL <= 0 || request.Method != http.MethodGet {
+		return postMgr.httpReq(request)
+	}
+	url := request.URL.String()
+	cache := &postMgr.responseCache
+	cache.Lock()
+	cached, ok := cache.responses[url]
+	if ok && time.Now().After(cached.expiry) {
+		delete(cache.responses, url)
+		ok = false
+	}
+	cache.Unlock()
+	if ok {
+		log.Debugf("Using the cached response of GET BIGIP request on %v", url)
+		return &http.Response{StatusCode: cached.statusCode}, copyResponseMap(cached.response)
+	}
+
+	httpResp, responseMap := postMgr.httpReq(request)
+	if httpResp == nil || responseMap == nil || !isBigIPResponseCacheable(httpResp.StatusCode) {
+		return httpResp, responseMap
+	}
+	cache.Lock()
+	if cache.responses == nil {
+		cache.responses = make(map[string]bigipCachedResponse)
+	}
+	cache.responses[url] = bigipCachedResponse{
+		statusCode: httpResp.StatusCode,
+		response:   copyResponseMap(responseMap),
+		expiry:     time.Now().Add(time.Duration(postMgr.BigIPResponseCacheTTL) * time.Second),
+	}
+	cache.Unlock()
+	return httpResp, responseMap
+}
+
+// purgeBigIPResponse removes the cached response of the URL, so that BIG-IP is queried with the next request
+func (postMgr *PostManager) purgeBigIPResponse(url string) {
+	postMgr.responseCache.Lock()
+	delete(postMgr.responseCache.responses, url)
+	postMgr.responseCache.Unlock()
+}
+
+// copyResponseMap returns the copy of the top-level keys of the response, callers may update the returned
+// response without modifying the cached response
+func copyResponseMap(response map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(response))
+	for key, value := range response {
+		result[key] = value
+	}
+	return result
+}
@@ -0,0 +1,103 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
+)
+
+// getBigIPVirtualServer returns the virtual server of the resource config as it is named on BIG-IP, with the
+// full path of the policies and profiles attached to it
+func getBigIPVirtualServer(partition string, rsCfg *ResourceConfig) cisapiv1.BigIPVirtualServer {
+	virtual := rsCfg.Virtual
+	bigipVS := cisapiv1.BigIPVirtualServer{Partition: partition, Name: virtual.Name}
+	if virtual.VirtualAddress != nil {
+		bigipVS.Destination = net.JoinHostPort(virtual.VirtualAddress.BindAddr,
+			strconv.Itoa(int(virtual.VirtualAddress.Port)))
+	}
+	// objects of the declaration are created in the shared application of the tenant
+	sharedPath := func(name string) string {
+		return fmt.Sprintf("/%v/%v/%v", partition, as3SharedApplication, name)
+	}
+
+	var policies []string
+	for _, policy := range virtual.Policies {
+		policies = append(policies, sharedPath(policy.Name))
+	}
+	policies = append(policies, virtual.BigIPPolicies...)
+	policies = append(policies, virtual.WAF, virtual.Firewall, virtual.IpIntelligencePolicy,
+		virtual.PolicyPerRequestAccess)
+	bigipVS.Policies = uniqueNames(policies)
+
+	var profiles []string
+	for _, profile := range virtual.Profiles {
+		switch {
+		case profile.BigIPProfile && profile.Partition != "":
+			profiles = append(profiles, fmt.Sprintf("/%v/%v", profile.Partition, profile.Name))
+		case profile.BigIPProfile:
+			profiles = append(profiles, profile.Name)
+		case profile.Context == CustomProfileClient:
+			// clientssl profile of the secret is the TLS server of the virtual in AS3
+			profiles = append(profiles, sharedPath(virtual.Name+"_tls_server"))
+		case profile.Context == CustomProfileServer:
+			profiles = append(profiles, sharedPath(virtual.Name+"_tls_client"))
+		default:
+			_, name := getPartitionAndName(profile.Name)
+			profiles = append(profiles, sharedPath(name))
+		}
+	}
+	if virtual.HTTPProfile != (HTTPProfile{}) {
+		profiles = append(profiles, sharedPath(virtual.Name+"_http_profile"))
+	}
+	profiles = append(profiles, virtual.ProfileL4, virtual.TCP.Client, virtual.TCP.Server, virtual.HTTP2.Client,
+		virtual.HTTP2.Server, virtual.ProfileMultiplex, virtual.ProfileWebSocket, virtual.ProfileHTTPCompression,
+		virtual.ProfileWebAcceleration, virtual.ProfileAccess, virtual.ProfileDOS, virtual.ProfileBotDefense,
+		virtual.PersistenceProfile, virtual.AnalyticsProfiles.HTTPAnalyticsProfile,
+		virtual.AnalyticsProfiles.TCPAnalyticsProfile)
+	profiles = append(profiles, virtual.LogProfiles...)
+	bigipVS.Profiles = uniqueNames(profiles)
+	return bigipVS
+}
+
+// uniqueNames returns the non-empty names without the duplicates in the original order
+func uniqueNames(names []string) []string {
+	var result []string
+	found := make(map[string]struct{})
+	for _, name := range names {
+		if _, ok := found[name]; ok || name == "" {
+			continue
+		}
+		found[name] = struct{}{}
+		result = append(result, name)
+	}
+	return result
+}
+
+// sortBigIPVirtualServers sorts the BIG-IP virtual servers by partition and name for the stable status
+func sortBigIPVirtualServers(virtuals []cisapiv1.BigIPVirtualServer) {
+	sort.Slice(virtuals, func(i, j int) bool {
+		if virtuals[i].Partition != virtuals[j].Partition {
+			return virtuals[i].Partition < virtuals[j].Partition
+		}
+		return virtuals[i].Name < virtuals[j].Name
+	})
+}
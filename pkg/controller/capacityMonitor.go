@@ -0,0 +1,229 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/prometheus"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// license feature of the throughput limit of BIG-IP VE
+	throughputLicenseFeature = "perf_VE_throughput_Mbps"
+	// default percentage of the virtual server limit raising the capacity event
+	DefaultCapacityThreshold = 80
+	// namespace of the CIS pod mounted with the service account
+	serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+func (postMgr *PostManager) getBigIPProvisionURL() string {
+	return postMgr.getDeviceBaseURL() + "/mgmt/tm/sys/provision"
+}
+
+func (postMgr *PostManager) getBigIPVirtualsURL() string {
+	return postMgr.getDeviceBaseURL() + "/mgmt/tm/ltm/virtual?$select=name,partition"
+}
+
+// getBigIPItems returns the items of the BIG-IP REST collection
+func (postMgr *PostManager) getBigIPItems(url string) ([]interface{}, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Posting GET BIGIP request on %v", url)
+	postMgr.setAuthHeader(req)
+
+	httpResp, responseMap := postMgr.httpReq(req)
+	if httpResp == nil || responseMap == nil {
+		return nil, fmt.Errorf("Internal Error")
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Error response from BIGIP with status code %v", httpResp.StatusCode)
+	}
+	items, _ := responseMap["items"].([]interface{})
+	return items, nil
+}
+
+// getBigIPProvisionedModules returns the provisioning level of the modules provisioned on BIG-IP
+func (postMgr *PostManager) getBigIPProvisionedModules() (map[string]string, error) {
+	items, err := postMgr.getBigIPItems(postMgr.getBigIPProvisionURL())
+	if err != nil {
+		return nil, err
+	}
+	modules := make(map[string]string)
+	for _, item := range items {
+		module, _ := item.(map[string]interface{})
+		name, _ := module["name"].(string)
+		level, _ := module["level"].(string)
+		if name != "" && level != "" && level != "none" {
+			modules[name] = level
+		}
+	}
+	return modules, nil
+}
+
+// getBigIPVirtualServerCount returns the count of the virtual servers on BIG-IP by partition
+func (postMgr *PostManager) getBigIPVirtualServerCount() (map[string]int, error) {
+	items, err := postMgr.getBigIPItems(postMgr.getBigIPVirtualsURL())
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, item := range items {
+		virtual, _ := item.(map[string]interface{})
+		partition, _ := virtual["partition"].(string)
+		counts[partition]++
+	}
+	return counts, nil
+}
+
+// getBigIPThroughputLimit returns the throughput limit in Mbps of the BIG-IP license,
+// 0 when the license does not limit the throughput
+func (postMgr *PostManager) getBigIPThroughputLimit() (int, error) {
+	req, err := http.NewRequest("GET", postMgr.getBigipRegKeyURL(), nil)
+	if err != nil {
+		return 0, err
+	}
+	postMgr.setAuthHeader(req)
+
+	httpResp, responseMap := postMgr.httpCachedReq(req)
+	if httpResp == nil || responseMap == nil {
+		return 0, fmt.Errorf("Internal Error")
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("Error response from BIGIP with status code %v", httpResp.StatusCode)
+	}
+	features, _ := responseMap["featureFlags"].([]interface{})
+	for _, feature := range features {
+		flag, _ := feature.(map[string]interface{})
+		if flag["featureName"] != throughputLicenseFeature {
+			continue
+		}
+		value, _ := flag["featureValue"].(string)
+		return strconv.Atoi(value)
+	}
+	return 0, nil
+}
+
+// setManagedVirtualServers records the virtual servers of the configuration posted by CIS
+func (ctlr *Controller) setManagedVirtualServers(config ResourceConfigRequest) {
+	count := 0
+	for _, partitionConfig := range config.ltmConfig {
+		count += len(partitionConfig.ResourceMap)
+	}
+	ctlr.bigIPCapacity.Lock()
+	ctlr.bigIPCapacity.managedVirtuals = count
+	ctlr.bigIPCapacity.Unlock()
+}
+
+// monitorBigIPCapacity queries BIG-IP periodically for the provisioned modules, virtual servers and
+// the license limits
+func (ctlr *Controller) monitorBigIPCapacity() {
+	if ctlr.Agent.xcManager != nil || ctlr.Agent.nginxManager != nil || !ctlr.Agent.isDeviceReachable() {
+		log.Warningf("BIG-IP capacity is queried only when the declarations are posted to BIG-IP or the " +
+			"BIG-IP credentials are provided")
+		return
+	}
+	for {
+		ctlr.updateBigIPCapacity()
+		<-time.After(time.Duration(ctlr.capacityParams.Interval) * time.Second)
+	}
+}
+
+// updateBigIPCapacity updates the BIG-IP capacity metrics and checks the virtual servers against the threshold
+func (ctlr *Controller) updateBigIPCapacity() {
+	postMgr := ctlr.Agent.PostManager
+	if modules, err := postMgr.getBigIPProvisionedModules(); err != nil {
+		log.Errorf("Unable to get the provisioned modules of BIG-IP: %v", err)
+	} else {
+		prometheus.BigIPProvisionedModules.Reset()
+		for module, level := range modules {
+			prometheus.BigIPProvisionedModules.WithLabelValues(module, level).Set(1)
+		}
+	}
+	if limit, err := postMgr.getBigIPThroughputLimit(); err != nil {
+		log.Errorf("Unable to get the throughput limit of BIG-IP license: %v", err)
+	} else {
+		prometheus.BigIPThroughputLimit.Set(float64(limit))
+	}
+	counts, err := postMgr.getBigIPVirtualServerCount()
+	if err != nil {
+		log.Errorf("Unable to get the virtual servers of BIG-IP: %v", err)
+		return
+	}
+	total := 0
+	prometheus.BigIPVirtualServers.Reset()
+	for partition, count := range counts {
+		prometheus.BigIPVirtualServers.WithLabelValues(partition).Set(float64(count))
+		total += count
+	}
+	ctlr.checkCapacityThreshold(total)
+}
+
+// checkCapacityThreshold raises a warning event on the CIS pod once the virtual servers on BIG-IP reach the
+// threshold percentage of the virtual server limit, event is raised again after the count drops below it
+func (ctlr *Controller) checkCapacityThreshold(total int) {
+	ctlr.bigIPCapacity.Lock()
+	defer ctlr.bigIPCapacity.Unlock()
+	managed := ctlr.bigIPCapacity.managedVirtuals
+	prometheus.ManagedVirtualServers.Set(float64(managed))
+	if ctlr.capacityParams.VirtualServerLimit <= 0 {
+		return
+	}
+	threshold := ctlr.capacityParams.Threshold
+	if threshold <= 0 {
+		threshold = DefaultCapacityThreshold
+	}
+	if total*100 < ctlr.capacityParams.VirtualServerLimit*threshold {
+		if ctlr.bigIPCapacity.alerted {
+			log.Infof("Virtual servers on BIG-IP dropped below %v%% of the limit %v", threshold,
+				ctlr.capacityParams.VirtualServerLimit)
+		}
+		ctlr.bigIPCapacity.alerted = false
+		return
+	}
+	if ctlr.bigIPCapacity.alerted {
+		return
+	}
+	ctlr.bigIPCapacity.alerted = true
+	msg := fmt.Sprintf("BIG-IP has %v virtual servers, %v of them managed by CIS, reaching %v%% of the limit %v",
+		total, managed, threshold, ctlr.capacityParams.VirtualServerLimit)
+	log.Warningf("%v", msg)
+	if ctlr.eventRecorder != nil {
+		ctlr.eventRecorder.Event(getCISPodReference(), v1.EventTypeWarning, "CapacityThreshold", msg)
+	}
+}
+
+// getCISPodReference returns the reference of the CIS pod for the events which are not of a resource
+func getCISPodReference() *v1.ObjectReference {
+	namespace, _ := ioutil.ReadFile(serviceAccountNamespaceFile)
+	return &v1.ObjectReference{
+		Kind:       "Pod",
+		APIVersion: "v1",
+		Name:       os.Getenv("HOSTNAME"),
+		Namespace:  strings.TrimSpace(string(namespace)),
+	}
+}
@@ -23,15 +23,30 @@ const (
 	HTTP  = "http"
 	HTTPS = "https"
 
+	// InbandMonitor is the passive monitor checking the health from the traffic to the pool members
+	InbandMonitor = "inband"
+
 	defaultRouteGroupName string = "defaultRouteGroup"
 
 	//OVN K8S CNI
 	OVN_K8S                    = "ovn-k8s"
 	OVNK8sNodeSubnetAnnotation = "k8s.ovn.org/node-subnets"
 	OVNK8sNodeIPAnnotation     = "k8s.ovn.org/node-primary-ifaddr"
+	// host subnet of the Windows nodes of the OVN K8S hybrid overlay
+	OVNK8sHybridOverlayNodeSubnetAnnotation = "k8s.ovn.org/hybrid-overlay-node-subnet"
 
 	//Cilium CNI
 	CILIUM_K8S                      = "cilium-k8s"
 	CiliumK8sNodeSubnetAnnotation12 = "io.cilium.network.ipv4-pod-cidr"
 	CiliumK8sNodeSubnetAnnotation13 = "network.cilium.io/ipv4-pod-cidr"
+	// Routing modes of Cilium, pod routes are advertised by the Cilium BGP control plane in native mode
+	CiliumTunnelMode = "tunnel"
+	CiliumNativeMode = "native"
+
+	WindowsNodeOS = "windows"
+
+	// Status of the nodes in bigip_node_status metric
+	NodeStatusReady         = "ready"
+	NodeStatusUnschedulable = "unschedulable"
+	NodeStatusExcluded      = "excluded"
 )
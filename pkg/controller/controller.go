@@ -59,6 +59,16 @@ const (
 	ExternalDNS = "ExternalDNS"
 	// Policy is collection of BIG-IP profiles, LTM policies and iRules
 	CustomPolicy = "CustomPolicy"
+	// DataGroup is a F5 Custom Resource Kind
+	DataGroup = "DataGroup"
+	// TLSCertificate is a F5 Custom Resource Kind
+	TLSCertificate = "TLSCertificate"
+	// ServiceReferenceGrant is a F5 Custom Resource Kind
+	ServiceReferenceGrant = "ServiceReferenceGrant"
+	// HealthMonitor is a F5 Custom Resource Kind
+	HealthMonitor = "HealthMonitor"
+	// DeployConfig is a F5 Custom Resource Kind
+	DeployConfig = "DeployConfig"
 	// IPAM is a F5 Custom Resource Kind
 	IPAM = "IPAM"
 	// Service is a k8s native Service Resource.
@@ -75,6 +85,8 @@ const (
 	ConfigMap = "ConfigMap"
 	// Route is OpenShift Route
 	Route = "Route"
+	// Ingress is k8s native Ingress resource processed in custom resource mode
+	Ingress = "Ingress"
 	// Node update
 	NodeUpdate = "Node"
 
@@ -86,11 +98,24 @@ const (
 	PrimaryCIS    = "primary"
 	// Namespace is k8s namespace
 	HACIS = "HACIS"
+	// ClusterHealth is the health probe of the clusters in ratio mode
+	ClusterHealth = "ClusterHealth"
+	// BigIPObjectUpdate is the creation or deletion of the LTM objects referred by the VirtualServers on BIG-IP
+	BigIPObjectUpdate = "BigIPObject"
 
 	// Primary cluster health probe
 	DefaultProbeInterval = 60
 	DefaultRetryInterval = 15
 
+	// Lease of the primary CIS with lease primaryEndPoint
+	DefaultLeaseDuration      = 15
+	DefaultLeaseRenewInterval = 5
+	DefaultLeaseTimeout       = 5
+
+	// Health probe of the clusters in ratio mode
+	DefaultClusterHealthProbeInterval = 30
+	DefaultClusterHealthProbeTimeout  = 10
+
 	PolicyControlForward = "forwarding"
 	// Namespace for IPAM CRD
 	IPAMNamespace = "kube-system"
@@ -101,23 +126,85 @@ const (
 	TLSEdge             = "edge"
 	AllowSourceRange    = "allowSourceRange"
 	DefaultPool         = "defaultPool"
+	ErrorPage           = "errorPage"
 	TLSReencrypt        = "reencrypt"
 	TLSPassthrough      = "passthrough"
 	TLSRedirectInsecure = "redirect"
 	TLSAllowInsecure    = "allow"
 	TLSNoInsecure       = "none"
 
+	// dnsRecordType of the ExternalDNS serving both the A and AAAA WideIPs of the domain
+	DNSRecordTypeDualStack = "dual-stack"
+
+	// PROXY protocol versions of the header sent to the pool members
+	ProxyProtocolV1       = "v1"
+	ProxyProtocolV2       = "v2"
+	ProxyProtocolDisabled = "disabled"
+
+	// Pool path match types
+	PathTypePrefix = "Prefix"
+	PathTypeExact  = "Exact"
+	PathTypeRegex  = "Regex"
+
 	LBServiceIPAMLabelAnnotation  = "cis.f5.com/ipamLabel"
 	LBServiceHostAnnotation       = "cis.f5.com/host"
 	HealthMonitorAnnotation       = "cis.f5.com/health"
 	LBServicePolicyNameAnnotation = "cis.f5.com/policyName"
 	LegacyHealthMonitorAnnotation = "virtual-server.f5.com/health"
 
+	// GSLB annotations used to auto generate WideIPs for VirtualServers
+	GSLBZoneAnnotation       = "cis.f5.com/gslb-zone"
+	GSLBDataServerAnnotation = "cis.f5.com/gslb-data-server"
+
+	// ControllerAnnotationPrefix is the prefix of the annotations processed by CIS on the custom resources
+	ControllerAnnotationPrefix = "cis.f5.com/"
+
+	// ResourceClassAnnotation assigns a resource to the CIS instance with the same resource class
+	ResourceClassAnnotation = "cis.f5.com/resource-class"
+
+	// ResourceFinalizer blocks the deletion of a VirtualServer, TransportServer or ExternalDNS
+	// until its BIG-IP configuration is removed
+	ResourceFinalizer = "cis.f5.com/finalizer"
+
+	// PauseAnnotation pauses the processing of the updates of a VirtualServer, TransportServer or Route
+	PauseAnnotation = "cis.f5.com/pause"
+
+	// NamespacePolicyAnnotation marks a Policy as the namespace policy merged into the Policy of
+	// the resources of its namespace
+	NamespacePolicyAnnotation = "cis.f5.com/namespace-policy"
+
+	// DefaultTLSProfileAnnotation marks a TLSProfile as the default TLSProfile of the VirtualServers
+	// with host and without TLSProfile in its namespace
+	DefaultTLSProfileAnnotation = "cis.f5.com/default-tls-profile"
+
+	// Priority groups of the pool members when the pool has members of the backup clusters
+	PrimaryPriorityGroup = 2
+	BackupPriorityGroup  = 1
+
+	// Ingress annotations honoured in custom resource mode
+	IngressClassAnnotation       = "kubernetes.io/ingress.class"
+	IngressVSAddressAnnotation   = "virtual-server.f5.com/ip"
+	IngressPartitionAnnotation   = "virtual-server.f5.com/partition"
+	IngressSSLRedirectAnnotation = "ingress.kubernetes.io/ssl-redirect"
+	IngressAllowHTTPAnnotation   = "ingress.kubernetes.io/allow-http"
+	DefaultIngressClass          = "f5"
+
+	// OpenShift router annotations of Routes honoured for the migration from the default router
+	RouteRewriteTargetAnnotation = "haproxy.router.openshift.io/rewrite-target"
+	RouteTimeoutAnnotation       = "haproxy.router.openshift.io/timeout"
+	RouteRateLimitAnnotation     = "haproxy.router.openshift.io/rate-limit-connections"
+	RouteRateLimitHTTPAnnotation = "haproxy.router.openshift.io/rate-limit-connections.rate-http"
+
 	//Antrea NodePortLocal support
 	NPLPodAnnotation = "nodeportlocal.antrea.io"
 	NPLSvcAnnotation = "nodeportlocal.antrea.io/enabled"
 	NodePortLocal    = "nodeportlocal"
 
+	// Handling of the pool members of the cordoned and not ready nodes in nodeport mode
+	UnschedulableNodeDrain  = "drain"
+	UnschedulableNodeRemove = "remove"
+	UnschedulableNodeRetain = "retain"
+
 	// AS3 Related constants
 	as3SupportedVersion = 3.18
 	//Update as3Version,defaultAS3Version,defaultAS3Build while updating AS3 validation schema.
@@ -152,9 +239,33 @@ func NewController(params Params) *Controller {
 		multiClusterResources: newMultiClusterResourceStore(),
 		multiClusterMode:      params.MultiClusterMode,
 		clusterRatio:          make(map[string]*int),
+		autoGenerateWideIP:    params.AutoGenerateWideIP,
+		enableCRDIngress:      params.EnableCRDIngress,
+		enableACMESolver:      params.EnableACMESolver,
+		enforceSvcRefGrants:   params.EnforceSvcRefGrants,
+		enableFinalizers:      params.EnableFinalizers,
+		enableQuarantine:      params.EnableQuarantine,
+		secondaryNetworks:     params.EnableSecondaryNetworks,
+		dualStackMembers:      params.EnableDualStackMembers,
+		poolMemberRatio:       params.EnablePoolMemberRatio,
+		capacityParams:        params.CapacityParams,
+		memberStatsInterval:   params.PoolMemberStatsInterval,
+		nodeBatchInterval:     params.NodeEventBatchInterval,
+		unschedulableNodes:    params.UnschedulableNodeMembers,
+		poolMemberNodeOS:      params.PoolMemberNodeOS,
+		vxlanExcludedNodeOS:   params.VXLANExcludedNodeOS,
+		resourceClass:         params.ResourceClass,
+		defaultPolicy:         params.DefaultPolicy,
+		ingressClass:          params.IngressClass,
+		deployConfigCR:        params.DeployConfigCR,
+		bigipReferences:       params.BigIPReferences,
 	}
+	ctlr.tlsFiles.directory = params.TLSFileDirectory
+	ctlr.bigipObjectCheckInterval = params.BigIPObjectCheckInterval
+	ctlr.namespaceShard = params.NamespaceShard
 
 	log.Debug("Controller Created")
+	setNamingTemplates(params.NamingTemplates)
 
 	ctlr.resourceQueue = workqueue.NewNamedRateLimitingQueue(
 		workqueue.DefaultControllerRateLimiter(), "nextgen-resource-controller")
@@ -187,13 +298,24 @@ func NewController(params Params) *Controller {
 		log.Errorf("Failed to Setup Clients: %v", err)
 	}
 
-	if ctlr.namespaceLabel == "" {
+	if ctlr.Agent != nil {
+		ctlr.Agent.auditLog = newAuditLogger(params.AuditParams, ctlr.kubeClient)
+	}
+
+	if ctlr.namespaceShard.enabled() {
+		log.Infof("Watching the namespaces of shard %v of %v", ctlr.namespaceShard.Index,
+			ctlr.namespaceShard.Count)
+	}
+	// namespaces of the shard are watched with the namespace informer when all the namespaces are watched
+	if ctlr.namespaceLabel == "" && (len(params.Namespaces) != 0 || !ctlr.namespaceShard.enabled()) {
 		if len(params.Namespaces) == 0 {
 			ctlr.namespaces[""] = true
 			log.Debug("No namespaces provided. Watching all namespaces")
 		} else {
 			for _, ns := range params.Namespaces {
-				ctlr.namespaces[ns] = true
+				if ctlr.namespaceShard.hasNamespace(ns) {
+					ctlr.namespaces[ns] = true
+				}
 			}
 		}
 	} else {
@@ -203,7 +325,9 @@ func NewController(params Params) *Controller {
 			for _, nsInf := range ctlr.nsInformers {
 				for _, v := range nsInf.nsInformer.GetIndexer().List() {
 					ns := v.(*v1.Namespace)
-					ctlr.namespaces[ns.ObjectMeta.Name] = true
+					if ctlr.namespaceShard.hasNamespace(ns.ObjectMeta.Name) {
+						ctlr.namespaces[ns.ObjectMeta.Name] = true
+					}
 				}
 			}
 		}
@@ -251,6 +375,21 @@ func NewController(params Params) *Controller {
 
 	go ctlr.responseHandler(ctlr.Agent.respChan)
 
+	if ctlr.capacityParams.Interval > 0 {
+		// query BIG-IP periodically for the provisioned modules, virtual servers and license limits
+		go ctlr.monitorBigIPCapacity()
+	}
+
+	if ctlr.memberStatsInterval > 0 {
+		// export the statistics of the pool members posted by CIS
+		go ctlr.exportPoolMemberStats()
+	}
+
+	if ctlr.bigipObjectCheckInterval > 0 {
+		// verify the LTM objects referred by the VirtualServers periodically
+		go ctlr.monitorBigIPObjects()
+	}
+
 	go ctlr.Start()
 
 	go ctlr.setOtherSDNType()
@@ -371,7 +510,8 @@ func (ctlr *Controller) setupClients(config *rest.Config) error {
 		return fmt.Errorf("Failed to create Custum Resource kubeClient: %v", err)
 	}
 
-	kubeClient, err := kubernetes.NewForConfig(config)
+	// core types are served in protobuf to reduce the bandwidth and decoding cost of the informers
+	kubeClient, err := kubernetes.NewForConfig(getProtobufConfig(config))
 	if err != nil {
 		return fmt.Errorf("Failed to create kubeClient: %v", err)
 	}
@@ -397,6 +537,7 @@ func (ctlr *Controller) setupClients(config *rest.Config) error {
 	ctlr.kubeAPIClient = kubeIPAMClient
 	ctlr.kubeCRClient = kubeCRClient
 	ctlr.kubeClient = kubeClient
+	ctlr.eventRecorder = newEventRecorder(kubeClient)
 	ctlr.routeClientV1 = rclient
 	return nil
 }
@@ -411,6 +552,9 @@ func (ctlr *Controller) setupInformers() error {
 	nodeInf := ctlr.getNodeInformer("")
 	ctlr.nodeInformer = &nodeInf
 	ctlr.addNodeEventUpdateHandler(ctlr.nodeInformer)
+	if ctlr.deployConfigCR != "" {
+		ctlr.dcInformer = ctlr.newDeployConfigInformer()
+	}
 	return nil
 }
 
@@ -428,6 +572,10 @@ func (ctlr *Controller) Start() {
 	// start nodeinformer in all modes
 	ctlr.nodeInformer.start()
 
+	if ctlr.dcInformer != nil {
+		ctlr.dcInformer.start()
+	}
+
 	// start comInformers for all modes
 	for _, inf := range ctlr.comInformers {
 		inf.start()
@@ -486,6 +634,10 @@ func (ctlr *Controller) Stop() {
 	// stop node Informer
 	ctlr.nodeInformer.stop()
 
+	if ctlr.dcInformer != nil {
+		ctlr.dcInformer.stop()
+	}
+
 	// stop multi cluster informers
 	for _, poolInformers := range ctlr.multiClusterPoolInformers {
 		for _, inf := range poolInformers {
@@ -493,6 +645,10 @@ func (ctlr *Controller) Stop() {
 		}
 	}
 
+	if ctlr.tlsFiles.watcher != nil {
+		ctlr.tlsFiles.watcher.Close()
+	}
+
 	ctlr.Agent.Stop()
 	if ctlr.ipamCli != nil {
 		ctlr.ipamCli.Stop()
@@ -11,6 +11,7 @@ import (
 	routeapi "github.com/openshift/api/route/v1"
 	"io/ioutil"
 	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
 	"net/http"
 	"testing"
 )
@@ -312,6 +313,114 @@ func (m *mockController) deleteIngressLink(il *cisapiv1.IngressLink) {
 	}
 }
 
+func (m *mockController) addIngress(ing *netv1.Ingress) {
+	cusInf, _ := m.getNamespacedCRInformer(ing.ObjectMeta.Namespace)
+	cusInf.ingInformer.GetStore().Add(ing)
+
+	if m.resourceQueue != nil {
+		m.enqueueIngress(ing, Create)
+	}
+}
+
+func (m *mockController) updateIngress(oldIng *netv1.Ingress, newIng *netv1.Ingress) {
+	cusInf, _ := m.getNamespacedCRInformer(oldIng.ObjectMeta.Namespace)
+	cusInf.ingInformer.GetStore().Update(newIng)
+
+	if m.resourceQueue != nil {
+		m.enqueueUpdatedIngress(oldIng, newIng)
+	}
+}
+
+func (m *mockController) deleteIngress(ing *netv1.Ingress) {
+	cusInf, _ := m.getNamespacedCRInformer(ing.ObjectMeta.Namespace)
+	cusInf.ingInformer.GetStore().Delete(ing)
+
+	if m.resourceQueue != nil {
+		m.enqueueIngress(ing, Delete)
+	}
+}
+
+func (m *mockController) addDataGroup(dg *cisapiv1.DataGroup) {
+	cusInf, _ := m.getNamespacedCRInformer(dg.ObjectMeta.Namespace)
+	cusInf.dgInformer.GetStore().Add(dg)
+
+	if m.resourceQueue != nil {
+		m.enqueueDataGroup(dg, Create)
+	}
+}
+
+func (m *mockController) updateDataGroup(dg *cisapiv1.DataGroup) {
+	cusInf, _ := m.getNamespacedCRInformer(dg.ObjectMeta.Namespace)
+	cusInf.dgInformer.GetStore().Update(dg)
+
+	if m.resourceQueue != nil {
+		m.enqueueDataGroup(dg, Update)
+	}
+}
+
+func (m *mockController) deleteDataGroup(dg *cisapiv1.DataGroup) {
+	cusInf, _ := m.getNamespacedCRInformer(dg.ObjectMeta.Namespace)
+	cusInf.dgInformer.GetStore().Delete(dg)
+
+	if m.resourceQueue != nil {
+		m.enqueueDataGroup(dg, Delete)
+	}
+}
+
+func (m *mockController) addTLSCertificate(cert *cisapiv1.TLSCertificate) {
+	cusInf, _ := m.getNamespacedCRInformer(cert.ObjectMeta.Namespace)
+	cusInf.certInformer.GetStore().Add(cert)
+
+	if m.resourceQueue != nil {
+		m.enqueueTLSCertificate(cert, Create)
+	}
+}
+
+func (m *mockController) deleteTLSCertificate(cert *cisapiv1.TLSCertificate) {
+	cusInf, _ := m.getNamespacedCRInformer(cert.ObjectMeta.Namespace)
+	cusInf.certInformer.GetStore().Delete(cert)
+
+	if m.resourceQueue != nil {
+		m.enqueueTLSCertificate(cert, Delete)
+	}
+}
+
+func (m *mockController) addServiceReferenceGrant(grant *cisapiv1.ServiceReferenceGrant) {
+	cusInf, _ := m.getNamespacedCRInformer(grant.ObjectMeta.Namespace)
+	cusInf.srgInformer.GetStore().Add(grant)
+
+	if m.resourceQueue != nil {
+		m.enqueueServiceReferenceGrant(grant, Create)
+	}
+}
+
+func (m *mockController) deleteServiceReferenceGrant(grant *cisapiv1.ServiceReferenceGrant) {
+	cusInf, _ := m.getNamespacedCRInformer(grant.ObjectMeta.Namespace)
+	cusInf.srgInformer.GetStore().Delete(grant)
+
+	if m.resourceQueue != nil {
+		m.enqueueServiceReferenceGrant(grant, Delete)
+	}
+}
+
+func (m *mockController) addHealthMonitor(hm *cisapiv1.HealthMonitor) {
+	cusInf, _ := m.getNamespacedCRInformer(hm.ObjectMeta.Namespace)
+	cusInf.hmInformer.GetStore().Add(hm)
+
+	if m.resourceQueue != nil {
+		m.enqueueHealthMonitor(hm, Create)
+	}
+}
+
+func (m *mockController) deleteHealthMonitor(hm *cisapiv1.HealthMonitor) {
+	cusInf, _ := m.getNamespacedCRInformer(hm.ObjectMeta.Namespace)
+	cusInf.hmInformer.GetStore().Delete(hm)
+
+	if m.resourceQueue != nil {
+		m.enqueueHealthMonitor(hm, Delete)
+	}
+}
+
 func (m *mockController) addPod(pod *v1.Pod) {
 	cusInf, _ := m.getNamespacedCommonInformer(pod.ObjectMeta.Namespace)
 	cusInf.podInformer.GetStore().Add(pod)
@@ -0,0 +1,68 @@
+/*-
+* Copyright (c) 2016-2021, F5 Networks, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+)
+
+// getDataGroupName returns the name of the data group created for the DataGroup
+func getDataGroupName(namespace, name string) string {
+	return AS3NameFormatter(fmt.Sprintf("%s_%s", namespace, name))
+}
+
+// processDataGroup renders the DataGroup as a data group in the shared application of its partition
+func (ctlr *Controller) processDataGroup(dg *cisapiv1.DataGroup, isDGDeleted bool) {
+	startTime := time.Now()
+	defer func() {
+		endTime := time.Now()
+		log.Debugf("Finished syncing DataGroup %v/%v (%v)",
+			dg.Namespace, dg.Name, endTime.Sub(startTime))
+	}()
+
+	if !isDGDeleted && !ctlr.checkValidDataGroup(dg) {
+		// Remove the data group rendered earlier for the DataGroup
+		isDGDeleted = true
+	}
+
+	dgKey := dg.Namespace + "/" + dg.Name
+	partition := ctlr.getCRPartition(dg.Spec.Partition)
+	// Remove the data group from the partitions it is no longer part of
+	for _, prtn := range ctlr.resources.getLTMPartitions() {
+		if prtn == partition && !isDGDeleted {
+			continue
+		}
+		delete(ctlr.resources.ltmConfig[prtn].DataGroups, dgKey)
+	}
+	if isDGDeleted {
+		return
+	}
+
+	idg := &InternalDataGroup{
+		Name:      getDataGroupName(dg.Namespace, dg.Name),
+		Partition: partition,
+		Type:      dg.Spec.Type,
+	}
+	for key, value := range dg.Spec.Records {
+		idg.AddOrUpdateRecord(key, value)
+	}
+	ctlr.resources.getPartitionConfig(partition).DataGroups[dgKey] = idg
+}
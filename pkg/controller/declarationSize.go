@@ -0,0 +1,157 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/prometheus"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+)
+
+// declarations larger than the percentage of the size limit are warned
+const declarationSizeWarningPercent = 80
+
+// tenantsDeclaration is the declaration of the tenants posted in a single request
+type tenantsDeclaration struct {
+	decl    as3Declaration
+	tenants []string
+}
+
+// getMaxDeclarationSize returns the size limit of the AS3 declarations in bytes, 0 when the size is not limited
+func (postMgr *PostManager) getMaxDeclarationSize() int {
+	return postMgr.AS3MaxDeclarationSize * 1024 * 1024
+}
+
+// checkDeclarationSize checks whether the declaration is within the size limit, BIG-IP fails the larger requests
+// with an internal error of restjavad. Declarations close to the limit are warned
+func (agent *Agent) checkDeclarationSize(size int) bool {
+	prometheus.AS3DeclarationSize.Set(float64(size))
+	maxSize := agent.getMaxDeclarationSize()
+	log.Debugf("[AS3] Declaration size is %v bytes", size)
+	if maxSize == 0 {
+		return true
+	}
+	if size > maxSize {
+		return false
+	}
+	if size*100 > maxSize*declarationSizeWarningPercent {
+		log.Warningf("[AS3] Declaration size of %v bytes is close to the limit of %v MB, increase "+
+			"--as3-max-declaration-size along with the request size limit of BIG-IP or enable "+
+			"--as3-split-tenants", size, agent.AS3MaxDeclarationSize)
+	}
+	return true
+}
+
+// splitDeclaration returns the declarations of the tenants posted one after the other. Declaration within the size
+// limit is posted as is, otherwise the tenants are batched in order into the declarations within the limit when
+// the tenants are split. Tenants which can not be posted within the limit are returned as oversized
+func (agent *Agent) splitDeclaration(decl as3Declaration, tenantDeclMap map[string]as3Tenant,
+	tenants []string) ([]tenantsDeclaration, []string) {
+	if agent.checkDeclarationSize(len(decl)) {
+		return []tenantsDeclaration{{decl, tenants}}, nil
+	}
+	if !agent.AS3SplitTenants {
+		log.Errorf("[AS3] Declaration size of %v bytes exceeds the limit of %v MB, skipping the post of "+
+			"tenants %v to BIG-IP", len(decl), agent.AS3MaxDeclarationSize, tenants)
+		return nil, tenants
+	}
+
+	sortedTenants := append([]string{}, tenants...)
+	sort.Strings(sortedTenants)
+	maxSize := agent.getMaxDeclarationSize()
+	baseSize := len(agent.createAS3Declaration(nil))
+	var batches [][]string
+	var oversized []string
+	var batch []string
+	batchSize := baseSize
+	for _, tenant := range sortedTenants {
+		data, _ := json.Marshal(agent.withTenantLockKey(tenant, tenantDeclMap[tenant]))
+		// tenant is added with its name, the separators and the declaration
+		size := len(tenant) + len(data) + 4
+		if baseSize+size > maxSize {
+			log.Errorf("[AS3] Declaration of tenant %v exceeds the limit of %v MB, skipping the post of "+
+				"the tenant to BIG-IP", tenant, agent.AS3MaxDeclarationSize)
+			oversized = append(oversized, tenant)
+			continue
+		}
+		if batchSize+size > maxSize {
+			batches = append(batches, batch)
+			batch = nil
+			batchSize = baseSize
+		}
+		batch = append(batch, tenant)
+		batchSize += size
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+
+	var decls []tenantsDeclaration
+	for _, batch := range batches {
+		batchDeclMap := make(map[string]as3Tenant, len(batch))
+		for _, tenant := range batch {
+			batchDeclMap[tenant] = tenantDeclMap[tenant]
+		}
+		decls = append(decls, tenantsDeclaration{agent.createAS3Declaration(batchDeclMap), batch})
+	}
+	if len(decls) > 1 {
+		log.Infof("[AS3] Declaration size of %v bytes exceeds the limit of %v MB, posting the tenants in %v "+
+			"declarations", len(decl), agent.AS3MaxDeclarationSize, len(decls))
+	}
+	return decls, oversized
+}
+
+// setOversizedTenantResponse fails the tenants not posted as their declaration exceeds the size limit, such
+// tenants are not retried until the next configuration update
+func (agent *Agent) setOversizedTenantResponse(tenants []string) {
+	message := fmt.Sprintf("AS3 declaration exceeds the limit of %v MB of --as3-max-declaration-size",
+		agent.AS3MaxDeclarationSize)
+	for _, tenant := range tenants {
+		agent.tenantResponseMap[tenant] = tenantResponse{agentResponseCode: http.StatusRequestEntityTooLarge,
+			message: message}
+	}
+}
+
+// postTenants posts the tenants of the declaration, declaration exceeding the size limit is posted in the
+// declarations of the tenant batches, each batch is processed by BIG-IP before the next batch is posted
+func (agent *Agent) postTenants(decl as3Declaration, rsConfig ResourceConfigRequest, tenants []string) {
+	decls, oversized := agent.splitDeclaration(decl, agent.incomingTenantDeclMap, tenants)
+	if len(decls) == 1 && len(oversized) == 0 {
+		agent.postTenantsDeclaration(decls[0].decl, rsConfig, decls[0].tenants)
+		return
+	}
+	if len(oversized) > 0 {
+		agent.tenantResponseMap = make(map[string]tenantResponse)
+		agent.setOversizedTenantResponse(oversized)
+		agent.updateTenantResponse(true)
+		if len(decls) == 0 {
+			agent.notifyRscStatusHandler(rsConfig.reqId, true)
+		}
+	}
+	for _, tenantsDecl := range decls {
+		// responses of the batch update only the tenants of the batch
+		agent.tenantResponseMap = make(map[string]tenantResponse)
+		for _, tenant := range tenantsDecl.tenants {
+			agent.tenantResponseMap[tenant] = tenantResponse{}
+		}
+		agent.postTenantsDeclaration(tenantsDecl.decl, rsConfig, tenantsDecl.tenants)
+	}
+}
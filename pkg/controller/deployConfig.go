@@ -0,0 +1,87 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"reflect"
+	"strings"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+)
+
+// processDeployConfig applies the changes of the tunable fields of the DeployConfig while the controller
+// is running, changes of the other fields take effect only after the controller is restarted.
+// Arguments are already set from the first version of the DeployConfig observed, so it is only recorded
+func (ctlr *Controller) processDeployConfig(dc *cisapiv1.DeployConfig) {
+	spec := dc.Spec.DeepCopy()
+	if ctlr.deployConfigSpec == nil {
+		ctlr.deployConfigSpec = spec
+		return
+	}
+	current := ctlr.deployConfigSpec
+	ctlr.deployConfigSpec = spec
+
+	if !reflect.DeepEqual(getDeployConfigStaticSpec(current), getDeployConfigStaticSpec(spec)) {
+		log.Warningf("[DeployConfig] Changes to DeployConfig %v other than logLevel and as3Config, "+
+			"including the intervals, require a restart of the controller", dc.ObjectMeta.Name)
+	}
+
+	if spec.BaseConfig.LogLevel != current.BaseConfig.LogLevel && spec.BaseConfig.LogLevel != "" {
+		if ll := log.NewLogLevel(spec.BaseConfig.LogLevel); nil != ll {
+			log.SetLogLevel(*ll)
+			log.Infof("[DeployConfig] Log level set to %v", spec.BaseConfig.LogLevel)
+		} else {
+			log.Errorf("[DeployConfig] Unknown log level %v in DeployConfig %v, valid log levels are: "+
+				"AS3DEBUG, DEBUG, INFO, WARNING, ERROR, CRITICAL", spec.BaseConfig.LogLevel, dc.ObjectMeta.Name)
+		}
+	}
+
+	if ctlr.Agent == nil || ctlr.Agent.PostManager == nil {
+		return
+	}
+	// post parameters are read by the agent while posting, these are updated together under the lock
+	postMgr := ctlr.Agent.PostManager
+	postDelay, logRequest, logResponse := postMgr.getAS3PostDelay(), postMgr.isAS3RequestLogged(),
+		postMgr.isAS3ResponseLogged()
+	if spec.AS3Config.PostDelay != current.AS3Config.PostDelay {
+		postDelay = spec.AS3Config.PostDelay
+		log.Infof("[DeployConfig] AS3 post delay set to %v seconds", postDelay)
+	}
+	as3Debug := strings.ToUpper(spec.BaseConfig.LogLevel) == "AS3DEBUG"
+	wasAS3Debug := strings.ToUpper(current.BaseConfig.LogLevel) == "AS3DEBUG"
+	if !reflect.DeepEqual(spec.AS3Config.LogRequest, current.AS3Config.LogRequest) || as3Debug != wasAS3Debug {
+		logRequest = isBoolSet(spec.AS3Config.LogRequest) || as3Debug
+	}
+	if !reflect.DeepEqual(spec.AS3Config.LogResponse, current.AS3Config.LogResponse) || as3Debug != wasAS3Debug {
+		logResponse = isBoolSet(spec.AS3Config.LogResponse) || as3Debug
+	}
+	postMgr.setAS3PostParams(postDelay, logRequest, logResponse)
+}
+
+// isBoolSet checks whether the optional value of the DeployConfig is set to true
+func isBoolSet(value *bool) bool {
+	return value != nil && *value
+}
+
+// getDeployConfigStaticSpec returns the spec without the fields applied while the controller is running
+func getDeployConfigStaticSpec(spec *cisapiv1.DeployConfigSpec) *cisapiv1.DeployConfigSpec {
+	staticSpec := spec.DeepCopy()
+	staticSpec.BaseConfig.LogLevel = ""
+	staticSpec.AS3Config = cisapiv1.AS3Config{}
+	return staticSpec
+}
@@ -0,0 +1,118 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"net"
+
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// address families of the pool members
+	AddressFamilyIPv4       = "ipv4"
+	AddressFamilyIPv6       = "ipv6"
+	AddressFamilyPreferIPv6 = "prefer-ipv6"
+)
+
+// getAddressFamilyMembers returns the pool members of the service with the addresses of the pods of the address
+// family, members of the pods without an address of the family are skipped. Endpoints have the addresses of the
+// primary family of the service only, so the other addresses of the dual-stack pods are found with the pod informer
+func (ctlr *Controller) getAddressFamilyMembers(namespace, service, family string,
+	members []PoolMember) []PoolMember {
+	if !ctlr.dualStackMembers || ctlr.PoolMemberType != Cluster {
+		return filterAddressFamilyMembers(members, family)
+	}
+	if comInf, ok := ctlr.getNamespacedCommonInformer(namespace); !ok || comInf.podInformer == nil {
+		log.Warningf("Pod informer not found for namespace %v, using the endpoint addresses for the address "+
+			"family %v of service %v", namespace, family, service)
+		return filterAddressFamilyMembers(members, family)
+	}
+	addrs := make(map[string]string)
+	for _, pod := range ctlr.GetPodsForService(namespace, service, false) {
+		ip := getPodAddressFamilyIP(pod, family)
+		if ip == "" {
+			continue
+		}
+		if pod.Status.PodIP != "" {
+			addrs[pod.Status.PodIP] = ip
+		}
+		for _, podIP := range pod.Status.PodIPs {
+			addrs[podIP.IP] = ip
+		}
+	}
+	var familyMembers []PoolMember
+	for _, member := range members {
+		ip, ok := addrs[member.Address]
+		if !ok {
+			log.Debugf("Address family %v not found for the pool member %v of service %v/%v",
+				family, member.Address, namespace, service)
+			continue
+		}
+		member.Address = ip
+		familyMembers = append(familyMembers, member)
+	}
+	return familyMembers
+}
+
+// getPodAddressFamilyIP returns the address of the pod of the address family, the IPv4 address is returned for
+// prefer-ipv6 when the pod has no IPv6 address
+func getPodAddressFamilyIP(pod *v1.Pod, family string) string {
+	ips := make([]string, 0, len(pod.Status.PodIPs)+1)
+	for _, podIP := range pod.Status.PodIPs {
+		ips = append(ips, podIP.IP)
+	}
+	if len(ips) == 0 && pod.Status.PodIP != "" {
+		ips = append(ips, pod.Status.PodIP)
+	}
+	var ipv4 string
+	for _, ip := range ips {
+		if isIPv6Address(ip) {
+			if family != AddressFamilyIPv4 {
+				return ip
+			}
+		} else if ipv4 == "" {
+			ipv4 = ip
+		}
+	}
+	if family == AddressFamilyIPv6 {
+		return ""
+	}
+	return ipv4
+}
+
+// filterAddressFamilyMembers returns the pool members with the addresses of the address family, all the members
+// are returned for prefer-ipv6
+func filterAddressFamilyMembers(members []PoolMember, family string) []PoolMember {
+	if family == AddressFamilyPreferIPv6 {
+		return members
+	}
+	var familyMembers []PoolMember
+	for _, member := range members {
+		if isIPv6Address(member.Address) == (family == AddressFamilyIPv6) {
+			familyMembers = append(familyMembers, member)
+		}
+	}
+	return familyMembers
+}
+
+// isIPv6Address checks whether the address is an IPv6 address
+func isIPv6Address(address string) bool {
+	ip := net.ParseIP(address)
+	return ip != nil && ip.To4() == nil
+}
@@ -0,0 +1,184 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// hasResourceFinalizer checks whether the finalizers of the resource contain the CIS finalizer
+func hasResourceFinalizer(meta metav1.ObjectMeta) bool {
+	for _, finalizer := range meta.Finalizers {
+		if finalizer == ResourceFinalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// addResourceFinalizer adds the CIS finalizer to the processed resource so that its deletion
+// waits for the removal of the BIG-IP configuration
+func (ctlr *Controller) addResourceFinalizer(rscRef resourceRef, meta metav1.ObjectMeta) {
+	if !ctlr.enableFinalizers || meta.DeletionTimestamp != nil || hasResourceFinalizer(meta) {
+		return
+	}
+	if err := ctlr.updateResourceFinalizer(rscRef, true); err != nil {
+		log.Errorf("Unable to add finalizer to %v %v/%v: %v", rscRef.kind, rscRef.namespace, rscRef.name, err)
+		return
+	}
+	log.Debugf("Added finalizer %v to %v %v/%v", ResourceFinalizer, rscRef.kind, rscRef.namespace, rscRef.name)
+}
+
+// recordResourceFinalizer records the deleted resource with the CIS finalizer, finalizer is removed once
+// the configuration without the resource is posted to the partition. Resources moved out of the resource
+// class of CIS are recorded as well so that their finalizer is not left behind
+func (ctlr *Controller) recordResourceFinalizer(rscRef resourceRef, meta metav1.ObjectMeta, partition string) {
+	if !hasResourceFinalizer(meta) {
+		return
+	}
+	if meta.DeletionTimestamp == nil && ctlr.isManagedResourceClass(meta.Annotations) {
+		return
+	}
+	ctlr.resourceFinalizers.Lock()
+	defer ctlr.resourceFinalizers.Unlock()
+	if ctlr.resourceFinalizers.resources == nil {
+		ctlr.resourceFinalizers.resources = make(map[resourceRef]finalizerMeta)
+	}
+	ctlr.resourceFinalizers.resources[rscRef] = finalizerMeta{partition: partition}
+}
+
+// setResourceFinalizersRequest assigns the request posting the removal to the recorded resources
+func (ctlr *Controller) setResourceFinalizersRequest(reqId int) {
+	ctlr.resourceFinalizers.Lock()
+	defer ctlr.resourceFinalizers.Unlock()
+	for rscRef, meta := range ctlr.resourceFinalizers.resources {
+		if meta.reqId == 0 {
+			meta.reqId = reqId
+			ctlr.resourceFinalizers.resources[rscRef] = meta
+		}
+	}
+}
+
+// removeResourceFinalizers removes the finalizers of the recorded resources posted with the request or
+// the earlier requests, resources of the failed tenants are retained until their tenant is posted.
+// Request id 0 removes the finalizers of the recorded resources which did not change the configuration
+func (ctlr *Controller) removeResourceFinalizers(reqId int, failedTenants map[string]struct{}) {
+	ctlr.resourceFinalizers.Lock()
+	defer ctlr.resourceFinalizers.Unlock()
+	for rscRef, meta := range ctlr.resourceFinalizers.resources {
+		if reqId == 0 && meta.reqId != 0 || reqId != 0 && (meta.reqId == 0 || meta.reqId > reqId) {
+			continue
+		}
+		if _, found := failedTenants[meta.partition]; found {
+			continue
+		}
+		if err := ctlr.updateResourceFinalizer(rscRef, false); err != nil && !errors.IsNotFound(err) {
+			log.Errorf("Unable to remove finalizer from %v %v/%v: %v", rscRef.kind, rscRef.namespace,
+				rscRef.name, err)
+			continue
+		}
+		log.Debugf("Removed finalizer %v from %v %v/%v", ResourceFinalizer, rscRef.kind, rscRef.namespace,
+			rscRef.name)
+		delete(ctlr.resourceFinalizers.resources, rscRef)
+	}
+}
+
+// updateResourceFinalizer adds or removes the CIS finalizer of the resource, only the finalizers are patched
+// so that the spec of the resource is not sent back to the API server
+func (ctlr *Controller) updateResourceFinalizer(rscRef resourceRef, add bool) error {
+	cisV1 := ctlr.kubeCRClient.CisV1()
+	var meta metav1.ObjectMeta
+	switch rscRef.kind {
+	case VirtualServer:
+		vs, err := cisV1.VirtualServers(rscRef.namespace).Get(context.TODO(), rscRef.name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		meta = vs.ObjectMeta
+	case TransportServer:
+		ts, err := cisV1.TransportServers(rscRef.namespace).Get(context.TODO(), rscRef.name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		meta = ts.ObjectMeta
+	case ExternalDNS:
+		edns, err := cisV1.ExternalDNSes(rscRef.namespace).Get(context.TODO(), rscRef.name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		meta = edns.ObjectMeta
+	default:
+		return fmt.Errorf("finalizer not supported for %v", rscRef.kind)
+	}
+	finalizers, updated := updateFinalizers(meta.Finalizers, add)
+	if !updated {
+		return nil
+	}
+	patch, err := getFinalizersPatch(finalizers)
+	if err != nil {
+		return err
+	}
+	switch rscRef.kind {
+	case VirtualServer:
+		_, err = cisV1.VirtualServers(rscRef.namespace).Patch(context.TODO(), rscRef.name, types.MergePatchType,
+			patch, metav1.PatchOptions{})
+	case TransportServer:
+		_, err = cisV1.TransportServers(rscRef.namespace).Patch(context.TODO(), rscRef.name, types.MergePatchType,
+			patch, metav1.PatchOptions{})
+	case ExternalDNS:
+		_, err = cisV1.ExternalDNSes(rscRef.namespace).Patch(context.TODO(), rscRef.name, types.MergePatchType,
+			patch, metav1.PatchOptions{})
+	}
+	return err
+}
+
+// getFinalizersPatch returns the merge patch replacing the finalizers of the metadata
+func getFinalizersPatch(finalizers []string) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"finalizers": finalizers,
+		},
+	})
+}
+
+// updateFinalizers returns the finalizers with the CIS finalizer added or removed and
+// whether the finalizers are changed
+func updateFinalizers(finalizers []string, add bool) ([]string, bool) {
+	var updated []string
+	found := false
+	for _, finalizer := range finalizers {
+		if finalizer == ResourceFinalizer {
+			found = true
+			continue
+		}
+		updated = append(updated, finalizer)
+	}
+	if add {
+		if found {
+			return finalizers, false
+		}
+		return append(finalizers, ResourceFinalizer), true
+	}
+	return updated, found
+}
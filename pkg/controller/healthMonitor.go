@@ -0,0 +1,188 @@
+/*-
+* Copyright (c) 2016-2021, F5 Networks, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+)
+
+const (
+	// gRPC health checks of the HealthMonitor are done with the HTTP/2 monitor
+	GRPCMonitor  = "grpc"
+	HTTP2Monitor = "http2"
+)
+
+// getHealthMonitorName returns the name of the monitor created for the HealthMonitor
+func getHealthMonitorName(namespace, name string) string {
+	return AS3NameFormatter(fmt.Sprintf("%s_%s", namespace, name))
+}
+
+// getHealthMonitor returns the HealthMonitor in the namespace
+func (ctlr *Controller) getHealthMonitor(namespace, name string) *cisapiv1.HealthMonitor {
+	crInf, ok := ctlr.getNamespacedCRInformer(namespace)
+	if !ok || crInf.hmInformer == nil {
+		return nil
+	}
+	obj, found, err := crInf.hmInformer.GetIndexer().GetByKey(namespace + "/" + name)
+	if err != nil || !found {
+		return nil
+	}
+	return obj.(*cisapiv1.HealthMonitor)
+}
+
+// getMonitorForHealthMonitor returns the monitor of the HealthMonitor shared by the pools
+func getMonitorForHealthMonitor(hm *cisapiv1.HealthMonitor, partition string) Monitor {
+	monitor := Monitor{
+		Name:       getHealthMonitorName(hm.Namespace, hm.Name),
+		Partition:  partition,
+		Type:       hm.Spec.Type,
+		Interval:   hm.Spec.Interval,
+		Send:       hm.Spec.Send,
+		Recv:       hm.Spec.Recv,
+		Timeout:    hm.Spec.Timeout,
+		TargetPort: hm.Spec.TargetPort,
+		Ciphers:    hm.Spec.Ciphers,
+	}
+	if hm.Spec.Type == GRPCMonitor {
+		monitor.Type = HTTP2Monitor
+	}
+	if hm.Spec.ClientCertificate != "" {
+		monitor.ClientCertificate = getTLSCertificateName(hm.Namespace, hm.Spec.ClientCertificate)
+	}
+	return monitor
+}
+
+// processHealthMonitor renders the HealthMonitor as a monitor in the shared application of its partition
+func (ctlr *Controller) processHealthMonitor(hm *cisapiv1.HealthMonitor, isHMDeleted bool) {
+	startTime := time.Now()
+	defer func() {
+		endTime := time.Now()
+		log.Debugf("Finished syncing HealthMonitor %v/%v (%v)",
+			hm.Namespace, hm.Name, endTime.Sub(startTime))
+	}()
+
+	if !isHMDeleted && !ctlr.checkValidHealthMonitor(hm) {
+		// Remove the monitor rendered earlier for the HealthMonitor
+		isHMDeleted = true
+	}
+
+	hmKey := hm.Namespace + "/" + hm.Name
+	partition := ctlr.getCRPartition(hm.Spec.Partition)
+	// Remove the monitor from the partitions it is no longer part of
+	for _, prtn := range ctlr.resources.getLTMPartitions() {
+		if prtn == partition && !isHMDeleted {
+			continue
+		}
+		delete(ctlr.resources.ltmConfig[prtn].Monitors, hmKey)
+	}
+	if isHMDeleted {
+		return
+	}
+
+	ctlr.resources.getPartitionConfig(partition).Monitors[hmKey] = getMonitorForHealthMonitor(hm, partition)
+}
+
+// addHealthMonitorReference adds the monitor of the HealthMonitor referred by the pool monitor
+func (ctlr *Controller) addHealthMonitorReference(
+	monitor cisapiv1.Monitor,
+	pool *Pool,
+	rsCfg *ResourceConfig,
+	namespace, rsName string,
+) {
+	hm := ctlr.getHealthMonitor(namespace, monitor.Name)
+	if hm == nil {
+		log.Errorf("HealthMonitor %v not found, skipping monitor for %v/%v", monitor.Name, namespace, rsName)
+		return
+	}
+	if !ctlr.checkValidHealthMonitor(hm) {
+		log.Errorf("Invalid HealthMonitor %v, skipping monitor for %v/%v", monitor.Name, namespace, rsName)
+		return
+	}
+	if ctlr.getCRPartition(hm.Spec.Partition) != rsCfg.Virtual.Partition {
+		log.Errorf("HealthMonitor %v is not in the partition %v, skipping monitor for %v/%v",
+			monitor.Name, rsCfg.Virtual.Partition, namespace, rsName)
+		return
+	}
+	pool.MonitorNames = append(pool.MonitorNames, MonitorName{
+		Name: JoinBigipPath(rsCfg.Virtual.Partition, getHealthMonitorName(hm.Namespace, hm.Name)),
+	})
+}
+
+// refersHealthMonitor checks whether any of the monitors refers the HealthMonitor
+func refersHealthMonitor(hm *cisapiv1.HealthMonitor, monitors ...cisapiv1.Monitor) bool {
+	for _, monitor := range monitors {
+		if monitor.Reference == HealthMonitorRef && monitor.Name == hm.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// getVirtualServersForHealthMonitor returns the VirtualServers with the pools referring the HealthMonitor
+func (ctlr *Controller) getVirtualServersForHealthMonitor(hm *cisapiv1.HealthMonitor) []*cisapiv1.VirtualServer {
+	var virtuals []*cisapiv1.VirtualServer
+	for _, vs := range ctlr.getAllVirtualServers(hm.Namespace) {
+		refers := refersHealthMonitor(hm, vs.Spec.DefaultPool.Monitors...)
+		for _, pool := range vs.Spec.Pools {
+			if refersHealthMonitor(hm, append([]cisapiv1.Monitor{pool.Monitor}, pool.Monitors...)...) {
+				refers = true
+			}
+		}
+		if refers {
+			virtuals = append(virtuals, vs)
+		}
+	}
+	return virtuals
+}
+
+// getTransportServersForHealthMonitor returns the TransportServers with the pool referring the HealthMonitor
+func (ctlr *Controller) getTransportServersForHealthMonitor(hm *cisapiv1.HealthMonitor) []*cisapiv1.TransportServer {
+	var virtuals []*cisapiv1.TransportServer
+	for _, ts := range ctlr.getAllTransportServers(hm.Namespace) {
+		if refersHealthMonitor(hm, append([]cisapiv1.Monitor{ts.Spec.Pool.Monitor}, ts.Spec.Pool.Monitors...)...) {
+			virtuals = append(virtuals, ts)
+		}
+	}
+	return virtuals
+}
+
+// getHealthMonitorsForTLSCertificate returns the HealthMonitors using the TLSCertificate as client certificate
+func (ctlr *Controller) getHealthMonitorsForTLSCertificate(cert *cisapiv1.TLSCertificate) []*cisapiv1.HealthMonitor {
+	var monitors []*cisapiv1.HealthMonitor
+
+	crInf, ok := ctlr.getNamespacedCRInformer(cert.Namespace)
+	if !ok || crInf.hmInformer == nil {
+		return nil
+	}
+	objs, err := crInf.hmInformer.GetIndexer().ByIndex("namespace", cert.Namespace)
+	if err != nil {
+		log.Errorf("Unable to get list of HealthMonitors for namespace '%v': %v",
+			cert.Namespace, err)
+		return nil
+	}
+	for _, obj := range objs {
+		hm := obj.(*cisapiv1.HealthMonitor)
+		if hm.Spec.ClientCertificate == cert.Name {
+			monitors = append(monitors, hm)
+		}
+	}
+	return monitors
+}
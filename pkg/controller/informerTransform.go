@@ -0,0 +1,104 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+// protobufContentType is the content type of the core types negotiated with kube-apiserver, JSON is accepted
+// as the fallback
+const protobufContentType = runtime.ContentTypeProtobuf + "," + runtime.ContentTypeJSON
+
+// getProtobufConfig returns the copy of the config negotiating protobuf with kube-apiserver, only the clients of
+// the core types are created with this config as the custom resources are served in JSON
+func getProtobufConfig(config *rest.Config) *rest.Config {
+	protoConfig := rest.CopyConfig(config)
+	protoConfig.AcceptContentTypes = protobufContentType
+	protoConfig.ContentType = runtime.ContentTypeProtobuf
+	return protoConfig
+}
+
+// transformObject strips the managed fields and the last applied configuration of the object, CIS doesn't
+// process them and they are not to be stored in the informer caches
+func transformObject(obj runtime.Object) {
+	objMeta, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	objMeta.SetManagedFields(nil)
+	if annotations := objMeta.GetAnnotations(); annotations != nil {
+		if _, ok := annotations[corev1.LastAppliedConfigAnnotation]; ok {
+			delete(annotations, corev1.LastAppliedConfigAnnotation)
+			objMeta.SetAnnotations(annotations)
+		}
+	}
+}
+
+// newTransformListWatch transforms the objects listed and watched before they are stored by the informer
+func newTransformListWatch(lw *cache.ListWatch) *cache.ListWatch {
+	listFunc, watchFunc := lw.ListFunc, lw.WatchFunc
+	lw.ListFunc = func(options metav1.ListOptions) (runtime.Object, error) {
+		list, err := listFunc(options)
+		if err != nil {
+			return list, err
+		}
+		_ = meta.EachListItem(list, func(obj runtime.Object) error {
+			transformObject(obj)
+			return nil
+		})
+		return list, nil
+	}
+	lw.WatchFunc = func(options metav1.ListOptions) (watch.Interface, error) {
+		w, err := watchFunc(options)
+		if err != nil {
+			return w, err
+		}
+		return watch.Filter(w, func(event watch.Event) (watch.Event, bool) {
+			if event.Type != watch.Error && event.Object != nil {
+				transformObject(event.Object)
+			}
+			return event, true
+		}), nil
+	}
+	return lw
+}
+
+// newCRListWatch returns the ListWatch of the custom resources with the list options tweaked, the custom
+// resources are transformed with newTransformListWatch as the core types
+func newCRListWatch(
+	tweakListOptions func(*metav1.ListOptions),
+	listFunc func(options metav1.ListOptions) (runtime.Object, error),
+	watchFunc func(options metav1.ListOptions) (watch.Interface, error),
+) *cache.ListWatch {
+	return newTransformListWatch(&cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			tweakListOptions(&options)
+			return listFunc(options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			tweakListOptions(&options)
+			return watchFunc(options)
+		},
+	})
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"k8s.io/client-go/rest"
 	"reflect"
+	"strings"
 	"time"
 
 	routeapi "github.com/openshift/api/route/v1"
@@ -29,10 +30,11 @@ import (
 
 	ficV1 "github.com/F5Networks/f5-ipam-controller/pkg/ipamapis/apis/fic/v1"
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
-	cisinfv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/client/informers/externalversions/cis/v1"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
 	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/client-go/tools/cache"
 )
 
@@ -130,6 +132,31 @@ func (crInfr *CRInformer) start() {
 		go crInfr.ilInformer.Run(crInfr.stopCh)
 		cacheSyncs = append(cacheSyncs, crInfr.ilInformer.HasSynced)
 	}
+	if crInfr.ingInformer != nil {
+		log.Infof("Starting Ingress Informer")
+		go crInfr.ingInformer.Run(crInfr.stopCh)
+		cacheSyncs = append(cacheSyncs, crInfr.ingInformer.HasSynced)
+	}
+	if crInfr.dgInformer != nil {
+		log.Infof("Starting DataGroup Informer")
+		go crInfr.dgInformer.Run(crInfr.stopCh)
+		cacheSyncs = append(cacheSyncs, crInfr.dgInformer.HasSynced)
+	}
+	if crInfr.certInformer != nil {
+		log.Infof("Starting TLSCertificate Informer")
+		go crInfr.certInformer.Run(crInfr.stopCh)
+		cacheSyncs = append(cacheSyncs, crInfr.certInformer.HasSynced)
+	}
+	if crInfr.srgInformer != nil {
+		log.Infof("Starting ServiceReferenceGrant Informer")
+		go crInfr.srgInformer.Run(crInfr.stopCh)
+		cacheSyncs = append(cacheSyncs, crInfr.srgInformer.HasSynced)
+	}
+	if crInfr.hmInformer != nil {
+		log.Infof("Starting HealthMonitor Informer")
+		go crInfr.hmInformer.Run(crInfr.stopCh)
+		cacheSyncs = append(cacheSyncs, crInfr.hmInformer.HasSynced)
+	}
 	cache.WaitForNamedCacheSync(
 		"F5 CIS CRD Controller",
 		crInfr.stopCh,
@@ -334,35 +361,136 @@ func (ctlr *Controller) newNamespacedCustomResourceInformer(
 		stopCh:    make(chan struct{}),
 	}
 
-	crInf.ilInformer = cisinfv1.NewFilteredIngressLinkInformer(
-		ctlr.kubeCRClient,
-		namespace,
+	crInf.ilInformer = cache.NewSharedIndexInformer(
+		newCRListWatch(
+			everything,
+			func(options metav1.ListOptions) (runtime.Object, error) {
+				return ctlr.kubeCRClient.CisV1().IngressLinks(namespace).List(context.TODO(), options)
+			},
+			func(options metav1.ListOptions) (watch.Interface, error) {
+				return ctlr.kubeCRClient.CisV1().IngressLinks(namespace).Watch(context.TODO(), options)
+			},
+		),
+		&cisapiv1.IngressLink{},
 		resyncPeriod,
 		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
-		everything,
 	)
 
-	crInf.vsInformer = cisinfv1.NewFilteredVirtualServerInformer(
-		ctlr.kubeCRClient,
-		namespace,
+	crInf.vsInformer = cache.NewSharedIndexInformer(
+		newCRListWatch(
+			crOptions,
+			func(options metav1.ListOptions) (runtime.Object, error) {
+				return ctlr.kubeCRClient.CisV1().VirtualServers(namespace).List(context.TODO(), options)
+			},
+			func(options metav1.ListOptions) (watch.Interface, error) {
+				return ctlr.kubeCRClient.CisV1().VirtualServers(namespace).Watch(context.TODO(), options)
+			},
+		),
+		&cisapiv1.VirtualServer{},
 		resyncPeriod,
 		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
-		crOptions,
 	)
-	crInf.tlsInformer = cisinfv1.NewFilteredTLSProfileInformer(
-		ctlr.kubeCRClient,
-		namespace,
+	crInf.tlsInformer = cache.NewSharedIndexInformer(
+		newCRListWatch(
+			crOptions,
+			func(options metav1.ListOptions) (runtime.Object, error) {
+				return ctlr.kubeCRClient.CisV1().TLSProfiles(namespace).List(context.TODO(), options)
+			},
+			func(options metav1.ListOptions) (watch.Interface, error) {
+				return ctlr.kubeCRClient.CisV1().TLSProfiles(namespace).Watch(context.TODO(), options)
+			},
+		),
+		&cisapiv1.TLSProfile{},
 		resyncPeriod,
 		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
-		crOptions,
 	)
-	crInf.tsInformer = cisinfv1.NewFilteredTransportServerInformer(
-		ctlr.kubeCRClient,
-		namespace,
+	crInf.tsInformer = cache.NewSharedIndexInformer(
+		newCRListWatch(
+			crOptions,
+			func(options metav1.ListOptions) (runtime.Object, error) {
+				return ctlr.kubeCRClient.CisV1().TransportServers(namespace).List(context.TODO(), options)
+			},
+			func(options metav1.ListOptions) (watch.Interface, error) {
+				return ctlr.kubeCRClient.CisV1().TransportServers(namespace).Watch(context.TODO(), options)
+			},
+		),
+		&cisapiv1.TransportServer{},
+		resyncPeriod,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+	crInf.dgInformer = cache.NewSharedIndexInformer(
+		newCRListWatch(
+			crOptions,
+			func(options metav1.ListOptions) (runtime.Object, error) {
+				return ctlr.kubeCRClient.CisV1().DataGroups(namespace).List(context.TODO(), options)
+			},
+			func(options metav1.ListOptions) (watch.Interface, error) {
+				return ctlr.kubeCRClient.CisV1().DataGroups(namespace).Watch(context.TODO(), options)
+			},
+		),
+		&cisapiv1.DataGroup{},
+		resyncPeriod,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+	crInf.certInformer = cache.NewSharedIndexInformer(
+		newCRListWatch(
+			crOptions,
+			func(options metav1.ListOptions) (runtime.Object, error) {
+				return ctlr.kubeCRClient.CisV1().TLSCertificates(namespace).List(context.TODO(), options)
+			},
+			func(options metav1.ListOptions) (watch.Interface, error) {
+				return ctlr.kubeCRClient.CisV1().TLSCertificates(namespace).Watch(context.TODO(), options)
+			},
+		),
+		&cisapiv1.TLSCertificate{},
 		resyncPeriod,
 		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
-		crOptions,
 	)
+	crInf.hmInformer = cache.NewSharedIndexInformer(
+		newCRListWatch(
+			crOptions,
+			func(options metav1.ListOptions) (runtime.Object, error) {
+				return ctlr.kubeCRClient.CisV1().HealthMonitors(namespace).List(context.TODO(), options)
+			},
+			func(options metav1.ListOptions) (watch.Interface, error) {
+				return ctlr.kubeCRClient.CisV1().HealthMonitors(namespace).Watch(context.TODO(), options)
+			},
+		),
+		&cisapiv1.HealthMonitor{},
+		resyncPeriod,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+	// ServiceReferenceGrants are watched only when the grants are enforced
+	if ctlr.enforceSvcRefGrants {
+		crInf.srgInformer = cache.NewSharedIndexInformer(
+			newCRListWatch(
+				crOptions,
+				func(options metav1.ListOptions) (runtime.Object, error) {
+					return ctlr.kubeCRClient.CisV1().ServiceReferenceGrants(namespace).List(context.TODO(), options)
+				},
+				func(options metav1.ListOptions) (watch.Interface, error) {
+					return ctlr.kubeCRClient.CisV1().ServiceReferenceGrants(namespace).Watch(context.TODO(), options)
+				},
+			),
+			&cisapiv1.ServiceReferenceGrant{},
+			resyncPeriod,
+			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+		)
+	}
+	// Ingress resources are processed in custom resource mode only when enabled
+	if ctlr.enableCRDIngress {
+		crInf.ingInformer = cache.NewSharedIndexInformer(
+			newTransformListWatch(cache.NewFilteredListWatchFromClient(
+				ctlr.kubeClient.NetworkingV1().RESTClient(),
+				"ingresses",
+				namespace,
+				everything,
+			)),
+			&netv1.Ingress{},
+			resyncPeriod,
+			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+		)
+	}
 	return crInf
 }
 
@@ -380,7 +508,7 @@ func (ctlr *Controller) newNamespacedNativeResourceInformer(
 		// Ensure the default server cert is loaded
 		//appMgr.loadDefaultCert() why?
 		nrInformer.routeInformer = cache.NewSharedIndexInformer(
-			&cache.ListWatch{
+			newTransformListWatch(&cache.ListWatch{
 				ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
 					options.LabelSelector = ctlr.routeLabel
 					return ctlr.routeClientV1.Routes(namespace).List(context.TODO(), options)
@@ -389,7 +517,7 @@ func (ctlr *Controller) newNamespacedNativeResourceInformer(
 					options.LabelSelector = ctlr.routeLabel
 					return ctlr.routeClientV1.Routes(namespace).Watch(context.TODO(), options)
 				},
-			},
+			}),
 			&routeapi.Route{},
 			resyncPeriod,
 			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
@@ -414,12 +542,12 @@ func (ctlr *Controller) getNodeInformer(clusterName string) NodeInformer {
 	}
 	return NodeInformer{stopCh: make(chan struct{}),
 		nodeInformer: cache.NewSharedIndexInformer(
-			cache.NewFilteredListWatchFromClient(
+			newTransformListWatch(cache.NewFilteredListWatchFromClient(
 				restClientv1,
 				"nodes",
 				"",
 				nodeOptions,
-			),
+			)),
 			&corev1.Node{},
 			resyncPeriod,
 			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
@@ -432,9 +560,9 @@ func (ctlr *Controller) addNodeEventUpdateHandler(nodeInformer *NodeInformer) {
 	if nodeInformer.nodeInformer != nil {
 		nodeInformer.nodeInformer.AddEventHandler(
 			&cache.ResourceEventHandlerFuncs{
-				AddFunc:    func(obj interface{}) { ctlr.SetupNodeProcessing(nodeInformer.clusterName) },
-				UpdateFunc: func(obj, cur interface{}) { ctlr.SetupNodeProcessing(nodeInformer.clusterName) },
-				DeleteFunc: func(obj interface{}) { ctlr.SetupNodeProcessing(nodeInformer.clusterName) },
+				AddFunc:    func(obj interface{}) { ctlr.enqueueNodeEvent(nodeInformer) },
+				UpdateFunc: func(obj, cur interface{}) { ctlr.enqueueNodeEvent(nodeInformer) },
+				DeleteFunc: func(obj interface{}) { ctlr.enqueueNodeEvent(nodeInformer) },
 			},
 		)
 	}
@@ -456,23 +584,23 @@ func (ctlr *Controller) newNamespacedCommonResourceInformer(
 		namespace: namespace,
 		stopCh:    make(chan struct{}),
 		svcInformer: cache.NewSharedIndexInformer(
-			cache.NewFilteredListWatchFromClient(
+			newTransformListWatch(cache.NewFilteredListWatchFromClient(
 				restClientv1,
 				"services",
 				namespace,
 				everything,
-			),
+			)),
 			&corev1.Service{},
 			resyncPeriod,
 			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
 		),
 		secretsInformer: cache.NewSharedIndexInformer(
-			cache.NewFilteredListWatchFromClient(
+			newTransformListWatch(cache.NewFilteredListWatchFromClient(
 				restClientv1,
 				"secrets",
 				namespace,
 				everything,
-			),
+			)),
 			&corev1.Secret{},
 			resyncPeriod,
 			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
@@ -483,32 +611,46 @@ func (ctlr *Controller) newNamespacedCommonResourceInformer(
 		log.Debugf("[Multicluster] Skipping endpoint informer creation for namespace %v in %v mode", namespace, ctlr.mode)
 	} else {
 		comInf.epsInformer = cache.NewSharedIndexInformer(
-			cache.NewFilteredListWatchFromClient(
+			newTransformListWatch(cache.NewFilteredListWatchFromClient(
 				restClientv1,
 				"endpoints",
 				namespace,
 				everything,
-			),
+			)),
 			&corev1.Endpoints{},
 			resyncPeriod,
 			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
 		)
 	}
 
-	comInf.ednsInformer = cisinfv1.NewFilteredExternalDNSInformer(
-		ctlr.kubeCRClient,
-		namespace,
+	comInf.ednsInformer = cache.NewSharedIndexInformer(
+		newCRListWatch(
+			crOptions,
+			func(options metav1.ListOptions) (runtime.Object, error) {
+				return ctlr.kubeCRClient.CisV1().ExternalDNSes(namespace).List(context.TODO(), options)
+			},
+			func(options metav1.ListOptions) (watch.Interface, error) {
+				return ctlr.kubeCRClient.CisV1().ExternalDNSes(namespace).Watch(context.TODO(), options)
+			},
+		),
+		&cisapiv1.ExternalDNS{},
 		resyncPeriod,
 		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
-		crOptions,
 	)
 
-	comInf.plcInformer = cisinfv1.NewFilteredPolicyInformer(
-		ctlr.kubeCRClient,
-		namespace,
+	comInf.plcInformer = cache.NewSharedIndexInformer(
+		newCRListWatch(
+			crOptions,
+			func(options metav1.ListOptions) (runtime.Object, error) {
+				return ctlr.kubeCRClient.CisV1().Policies(namespace).List(context.TODO(), options)
+			},
+			func(options metav1.ListOptions) (watch.Interface, error) {
+				return ctlr.kubeCRClient.CisV1().Policies(namespace).Watch(context.TODO(), options)
+			},
+		),
+		&cisapiv1.Policy{},
 		resyncPeriod,
 		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
-		crOptions,
 	)
 	// start the cm informer if it's specified in deployment
 	if ctlr.globalExtendedCMKey != "" {
@@ -516,26 +658,28 @@ func (ctlr *Controller) newNamespacedCommonResourceInformer(
 			options.LabelSelector = ctlr.nativeResourceSelector.String()
 		}
 		comInf.cmInformer = cache.NewSharedIndexInformer(
-			cache.NewFilteredListWatchFromClient(
+			newTransformListWatch(cache.NewFilteredListWatchFromClient(
 				restClientv1,
 				"configmaps",
 				namespace,
 				nrOptions,
-			),
+			)),
 			&corev1.ConfigMap{},
 			resyncPeriod,
 			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
 		)
 	}
-	//enable pod informer for nodeport local mode and openshift mode
-	if ctlr.PoolMemberType == NodePortLocal || ctlr.mode == OpenShiftMode {
+	//enable pod informer for nodeport local mode, openshift mode, the secondary networks, the dual-stack
+	//addresses and the ratio of the pods
+	if ctlr.PoolMemberType == NodePortLocal || ctlr.mode == OpenShiftMode || ctlr.secondaryNetworks ||
+		ctlr.dualStackMembers || ctlr.poolMemberRatio {
 		comInf.podInformer = cache.NewSharedIndexInformer(
-			cache.NewFilteredListWatchFromClient(
+			newTransformListWatch(cache.NewFilteredListWatchFromClient(
 				restClientv1,
 				"pods",
 				namespace,
 				everything,
-			),
+			)),
 			&corev1.Pod{},
 			resyncPeriod,
 			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
@@ -559,7 +703,7 @@ func (ctlr *Controller) addCustomResourceEventHandlers(crInf *CRInformer) {
 		crInf.tlsInformer.AddEventHandler(
 			&cache.ResourceEventHandlerFuncs{
 				AddFunc:    func(obj interface{}) { ctlr.enqueueTLSProfile(obj, Create) },
-				UpdateFunc: func(old, cur interface{}) { ctlr.enqueueTLSProfile(cur, Update) },
+				UpdateFunc: func(old, cur interface{}) { ctlr.enqueueUpdatedTLSProfile(old, cur) },
 				// DeleteFunc: func(obj interface{}) { ctlr.enqueueTLSProfile(obj) },
 			},
 		)
@@ -584,6 +728,56 @@ func (ctlr *Controller) addCustomResourceEventHandlers(crInf *CRInformer) {
 			},
 		)
 	}
+
+	if crInf.ingInformer != nil {
+		crInf.ingInformer.AddEventHandler(
+			&cache.ResourceEventHandlerFuncs{
+				AddFunc:    func(obj interface{}) { ctlr.enqueueIngress(obj, Create) },
+				UpdateFunc: func(oldObj, newObj interface{}) { ctlr.enqueueUpdatedIngress(oldObj, newObj) },
+				DeleteFunc: func(obj interface{}) { ctlr.enqueueIngress(obj, Delete) },
+			},
+		)
+	}
+
+	if crInf.dgInformer != nil {
+		crInf.dgInformer.AddEventHandler(
+			&cache.ResourceEventHandlerFuncs{
+				AddFunc:    func(obj interface{}) { ctlr.enqueueDataGroup(obj, Create) },
+				UpdateFunc: func(obj, cur interface{}) { ctlr.enqueueDataGroup(cur, Update) },
+				DeleteFunc: func(obj interface{}) { ctlr.enqueueDataGroup(obj, Delete) },
+			},
+		)
+	}
+
+	if crInf.certInformer != nil {
+		crInf.certInformer.AddEventHandler(
+			&cache.ResourceEventHandlerFuncs{
+				AddFunc:    func(obj interface{}) { ctlr.enqueueTLSCertificate(obj, Create) },
+				UpdateFunc: func(obj, cur interface{}) { ctlr.enqueueTLSCertificate(cur, Update) },
+				DeleteFunc: func(obj interface{}) { ctlr.enqueueTLSCertificate(obj, Delete) },
+			},
+		)
+	}
+
+	if crInf.srgInformer != nil {
+		crInf.srgInformer.AddEventHandler(
+			&cache.ResourceEventHandlerFuncs{
+				AddFunc:    func(obj interface{}) { ctlr.enqueueServiceReferenceGrant(obj, Create) },
+				UpdateFunc: func(obj, cur interface{}) { ctlr.enqueueServiceReferenceGrant(cur, Update) },
+				DeleteFunc: func(obj interface{}) { ctlr.enqueueServiceReferenceGrant(obj, Delete) },
+			},
+		)
+	}
+
+	if crInf.hmInformer != nil {
+		crInf.hmInformer.AddEventHandler(
+			&cache.ResourceEventHandlerFuncs{
+				AddFunc:    func(obj interface{}) { ctlr.enqueueHealthMonitor(obj, Create) },
+				UpdateFunc: func(obj, cur interface{}) { ctlr.enqueueHealthMonitor(cur, Update) },
+				DeleteFunc: func(obj interface{}) { ctlr.enqueueHealthMonitor(obj, Delete) },
+			},
+		)
+	}
 }
 
 func (ctlr *Controller) addCommonResourceEventHandlers(comInf *CommonInformer) {
@@ -620,7 +814,7 @@ func (ctlr *Controller) addCommonResourceEventHandlers(comInf *CommonInformer) {
 		comInf.plcInformer.AddEventHandler(
 			&cache.ResourceEventHandlerFuncs{
 				AddFunc:    func(obj interface{}) { ctlr.enqueuePolicy(obj, Create) },
-				UpdateFunc: func(obj, cur interface{}) { ctlr.enqueuePolicy(cur, Update) },
+				UpdateFunc: func(obj, cur interface{}) { ctlr.enqueueUpdatedPolicy(obj, cur) },
 				DeleteFunc: func(obj interface{}) { ctlr.enqueueDeletedPolicy(obj) },
 			},
 		)
@@ -742,6 +936,14 @@ func (ctlr *Controller) enqueueDeletedIPAM(obj interface{}) {
 
 func (ctlr *Controller) enqueueVirtualServer(obj interface{}) {
 	vs := obj.(*cisapiv1.VirtualServer)
+	// VirtualServer deleted while CIS was down is held by the finalizer
+	if vs.DeletionTimestamp != nil {
+		ctlr.enqueueDeletedVirtualServer(obj)
+		return
+	}
+	if !ctlr.isManagedResourceClass(vs.Annotations) {
+		return
+	}
 	log.Debugf("Enqueueing VirtualServer: %v", vs)
 	key := &rqKey{
 		namespace: vs.ObjectMeta.Namespace,
@@ -754,11 +956,58 @@ func (ctlr *Controller) enqueueVirtualServer(obj interface{}) {
 	ctlr.resourceQueue.Add(key)
 }
 
+// isSpecUpdated checks whether the spec or the annotations processed by CIS of the custom resource are updated.
+// Generation of the custom resources with the status subresource is incremented only on the updates of the spec,
+// spec is compared for the resources without the generation. Annotations are compared as their updates don't
+// increment the generation, updates of the labels are skipped
+func isSpecUpdated(oldObj, newObj metav1.Object, oldSpec, newSpec interface{}) bool {
+	if !reflect.DeepEqual(getControllerAnnotations(oldObj), getControllerAnnotations(newObj)) {
+		return true
+	}
+	if oldObj.GetGeneration() != 0 && newObj.GetGeneration() != 0 {
+		return oldObj.GetGeneration() != newObj.GetGeneration()
+	}
+	return !reflect.DeepEqual(oldSpec, newSpec)
+}
+
+// getControllerAnnotations returns the annotations of the object processed by CIS, the annotations of the other
+// controllers and tools are not returned
+func getControllerAnnotations(obj metav1.Object) map[string]string {
+	annotations := make(map[string]string)
+	for key, value := range obj.GetAnnotations() {
+		if strings.HasPrefix(key, ControllerAnnotationPrefix) {
+			annotations[key] = value
+		}
+	}
+	return annotations
+}
+
 func (ctlr *Controller) enqueueUpdatedVirtualServer(oldObj, newObj interface{}) {
 	oldVS := oldObj.(*cisapiv1.VirtualServer)
 	newVS := newObj.(*cisapiv1.VirtualServer)
-	// Skip virtual servers on status updates
-	if reflect.DeepEqual(oldVS.Spec, newVS.Spec) && reflect.DeepEqual(oldVS.Labels, newVS.Labels) {
+	// Virtual server with the finalizer is deleted
+	if newVS.DeletionTimestamp != nil {
+		if oldVS.DeletionTimestamp == nil {
+			ctlr.enqueueDeletedVirtualServer(newObj)
+		}
+		return
+	}
+	// Handle virtual servers moving in or out of the resource class
+	oldManaged := ctlr.isManagedResourceClass(oldVS.Annotations)
+	newManaged := ctlr.isManagedResourceClass(newVS.Annotations)
+	if oldManaged != newManaged {
+		if oldManaged {
+			ctlr.enqueueDeletedVirtualServer(oldObj)
+		} else {
+			ctlr.enqueueVirtualServer(newObj)
+		}
+		return
+	}
+	if !newManaged {
+		return
+	}
+	// Skip virtual servers on status and metadata updates
+	if !isSpecUpdated(oldVS, newVS, oldVS.Spec, newVS.Spec) {
 		return
 	}
 	updateEvent := true
@@ -806,6 +1055,9 @@ func (ctlr *Controller) enqueueUpdatedVirtualServer(oldObj, newObj interface{})
 
 func (ctlr *Controller) enqueueDeletedVirtualServer(obj interface{}) {
 	vs := obj.(*cisapiv1.VirtualServer)
+	if !ctlr.isManagedResourceClass(vs.Annotations) {
+		return
+	}
 	log.Debugf("Enqueueing VirtualServer: %v", vs)
 	key := &rqKey{
 		namespace: vs.ObjectMeta.Namespace,
@@ -832,8 +1084,27 @@ func (ctlr *Controller) enqueueTLSProfile(obj interface{}, event string) {
 	ctlr.resourceQueue.Add(key)
 }
 
+// enqueueUpdatedTLSProfile enqueues the updated TLSProfile, TLSProfile which is no longer the default TLSProfile
+// is enqueued as well so that the VirtualServers using it are processed without it
+func (ctlr *Controller) enqueueUpdatedTLSProfile(oldObj, newObj interface{}) {
+	oldTLS := oldObj.(*cisapiv1.TLSProfile)
+	newTLS := newObj.(*cisapiv1.TLSProfile)
+	if isDefaultTLSProfile(oldTLS) && !isDefaultTLSProfile(newTLS) {
+		ctlr.enqueueTLSProfile(oldObj, Update)
+	}
+	ctlr.enqueueTLSProfile(newObj, Update)
+}
+
 func (ctlr *Controller) enqueueTransportServer(obj interface{}) {
 	ts := obj.(*cisapiv1.TransportServer)
+	// TransportServer deleted while CIS was down is held by the finalizer
+	if ts.DeletionTimestamp != nil {
+		ctlr.enqueueDeletedTransportServer(obj)
+		return
+	}
+	if !ctlr.isManagedResourceClass(ts.Annotations) {
+		return
+	}
 	log.Infof("Enqueueing TransportServer: %v", ts)
 	key := &rqKey{
 		namespace: ts.ObjectMeta.Namespace,
@@ -849,8 +1120,29 @@ func (ctlr *Controller) enqueueTransportServer(obj interface{}) {
 func (ctlr *Controller) enqueueUpdatedTransportServer(oldObj, newObj interface{}) {
 	oldVS := oldObj.(*cisapiv1.TransportServer)
 	newVS := newObj.(*cisapiv1.TransportServer)
-	// Skip transport servers on status updates
-	if reflect.DeepEqual(oldVS.Spec, newVS.Spec) && reflect.DeepEqual(oldVS.Labels, newVS.Labels) {
+	// Transport server with the finalizer is deleted
+	if newVS.DeletionTimestamp != nil {
+		if oldVS.DeletionTimestamp == nil {
+			ctlr.enqueueDeletedTransportServer(newObj)
+		}
+		return
+	}
+	// Handle transport servers moving in or out of the resource class
+	oldManaged := ctlr.isManagedResourceClass(oldVS.Annotations)
+	newManaged := ctlr.isManagedResourceClass(newVS.Annotations)
+	if oldManaged != newManaged {
+		if oldManaged {
+			ctlr.enqueueDeletedTransportServer(oldObj)
+		} else {
+			ctlr.enqueueTransportServer(newObj)
+		}
+		return
+	}
+	if !newManaged {
+		return
+	}
+	// Skip transport servers on status and metadata updates
+	if !isSpecUpdated(oldVS, newVS, oldVS.Spec, newVS.Spec) {
 		return
 	}
 	updateEvent := true
@@ -896,6 +1188,9 @@ func (ctlr *Controller) enqueueUpdatedTransportServer(oldObj, newObj interface{}
 
 func (ctlr *Controller) enqueueDeletedTransportServer(obj interface{}) {
 	vs := obj.(*cisapiv1.TransportServer)
+	if !ctlr.isManagedResourceClass(vs.Annotations) {
+		return
+	}
 	log.Debugf("Enqueueing TransportServer: %v", vs)
 	key := &rqKey{
 		namespace: vs.ObjectMeta.Namespace,
@@ -922,6 +1217,17 @@ func (ctlr *Controller) enqueuePolicy(obj interface{}, event string) {
 	ctlr.resourceQueue.Add(key)
 }
 
+// enqueueUpdatedPolicy enqueues the updated policy, policy which is no longer the namespace policy is
+// enqueued as well so that the resources of the namespace are processed without it
+func (ctlr *Controller) enqueueUpdatedPolicy(oldObj, newObj interface{}) {
+	oldPol := oldObj.(*cisapiv1.Policy)
+	newPol := newObj.(*cisapiv1.Policy)
+	if isNamespacePolicy(oldPol) && !isNamespacePolicy(newPol) {
+		ctlr.enqueuePolicy(oldObj, Update)
+	}
+	ctlr.enqueuePolicy(newObj, Update)
+}
+
 func (ctlr *Controller) enqueueDeletedPolicy(obj interface{}) {
 	pol := obj.(*cisapiv1.Policy)
 	log.Infof("Enqueueing Policy: %v", pol)
@@ -936,6 +1242,62 @@ func (ctlr *Controller) enqueueDeletedPolicy(obj interface{}) {
 	ctlr.resourceQueue.Add(key)
 }
 
+func (ctlr *Controller) enqueueDataGroup(obj interface{}, event string) {
+	dg := obj.(*cisapiv1.DataGroup)
+	log.Debugf("Enqueueing DataGroup: %v/%v", dg.ObjectMeta.Namespace, dg.ObjectMeta.Name)
+	key := &rqKey{
+		namespace: dg.ObjectMeta.Namespace,
+		kind:      DataGroup,
+		rscName:   dg.ObjectMeta.Name,
+		rsc:       obj,
+		event:     event,
+	}
+
+	ctlr.resourceQueue.Add(key)
+}
+
+func (ctlr *Controller) enqueueTLSCertificate(obj interface{}, event string) {
+	cert := obj.(*cisapiv1.TLSCertificate)
+	log.Debugf("Enqueueing TLSCertificate: %v/%v", cert.ObjectMeta.Namespace, cert.ObjectMeta.Name)
+	key := &rqKey{
+		namespace: cert.ObjectMeta.Namespace,
+		kind:      TLSCertificate,
+		rscName:   cert.ObjectMeta.Name,
+		rsc:       obj,
+		event:     event,
+	}
+
+	ctlr.resourceQueue.Add(key)
+}
+
+func (ctlr *Controller) enqueueServiceReferenceGrant(obj interface{}, event string) {
+	grant := obj.(*cisapiv1.ServiceReferenceGrant)
+	log.Debugf("Enqueueing ServiceReferenceGrant: %v/%v", grant.ObjectMeta.Namespace, grant.ObjectMeta.Name)
+	key := &rqKey{
+		namespace: grant.ObjectMeta.Namespace,
+		kind:      ServiceReferenceGrant,
+		rscName:   grant.ObjectMeta.Name,
+		rsc:       obj,
+		event:     event,
+	}
+
+	ctlr.resourceQueue.Add(key)
+}
+
+func (ctlr *Controller) enqueueHealthMonitor(obj interface{}, event string) {
+	hm := obj.(*cisapiv1.HealthMonitor)
+	log.Debugf("Enqueueing HealthMonitor: %v/%v", hm.ObjectMeta.Namespace, hm.ObjectMeta.Name)
+	key := &rqKey{
+		namespace: hm.ObjectMeta.Namespace,
+		kind:      HealthMonitor,
+		rscName:   hm.ObjectMeta.Name,
+		rsc:       obj,
+		event:     event,
+	}
+
+	ctlr.resourceQueue.Add(key)
+}
+
 func (ctlr *Controller) enqueueIngressLink(obj interface{}) {
 	ingLink := obj.(*cisapiv1.IngressLink)
 	log.Infof("Enqueueing IngressLink: %v", ingLink)
@@ -967,6 +1329,10 @@ func (ctlr *Controller) enqueueDeletedIngressLink(obj interface{}) {
 func (ctlr *Controller) enqueueUpdatedIngressLink(oldObj, newObj interface{}) {
 	oldIngLink := oldObj.(*cisapiv1.IngressLink)
 	newIngLink := newObj.(*cisapiv1.IngressLink)
+	// Skip ingress links on status and metadata updates
+	if !isSpecUpdated(oldIngLink, newIngLink, oldIngLink.Spec, newIngLink.Spec) {
+		return
+	}
 
 	oldILPartition := ctlr.getCRPartition(oldIngLink.Spec.Partition)
 	newILPartition := ctlr.getCRPartition(newIngLink.Spec.Partition)
@@ -1004,6 +1370,11 @@ func (ctlr *Controller) enqueueUpdatedIngressLink(oldObj, newObj interface{}) {
 
 func (ctlr *Controller) enqueueExternalDNS(obj interface{}) {
 	edns := obj.(*cisapiv1.ExternalDNS)
+	// ExternalDNS deleted while CIS was down is held by the finalizer
+	if edns.DeletionTimestamp != nil {
+		ctlr.enqueueDeletedExternalDNS(obj)
+		return
+	}
 	log.Infof("Enqueueing ExternalDNS: %v", edns)
 	key := &rqKey{
 		namespace: edns.ObjectMeta.Namespace,
@@ -1019,6 +1390,13 @@ func (ctlr *Controller) enqueueExternalDNS(obj interface{}) {
 func (ctlr *Controller) enqueueUpdatedExternalDNS(oldObj, newObj interface{}) {
 	oldEDNS := oldObj.(*cisapiv1.ExternalDNS)
 	edns := newObj.(*cisapiv1.ExternalDNS)
+	// ExternalDNS with the finalizer is deleted
+	if edns.DeletionTimestamp != nil {
+		if oldEDNS.DeletionTimestamp == nil {
+			ctlr.enqueueDeletedExternalDNS(newObj)
+		}
+		return
+	}
 
 	if oldEDNS.Spec.DomainName != edns.Spec.DomainName {
 		key := &rqKey{
@@ -1191,6 +1569,9 @@ func (ctlr *Controller) enqueueSecret(obj interface{}, event string) {
 
 func (ctlr *Controller) enqueueRoute(obj interface{}, event string) {
 	rt := obj.(*routeapi.Route)
+	if !ctlr.isManagedResourceClass(rt.Annotations) {
+		return
+	}
 	log.Debugf("Enqueueing Route: %v/%v", rt.ObjectMeta.Namespace, rt.ObjectMeta.Name)
 	key := &rqKey{
 		namespace: rt.ObjectMeta.Namespace,
@@ -1209,6 +1590,10 @@ func (ctlr *Controller) enqueueUpdatedRoute(old, cur interface{}) {
 	if reflect.DeepEqual(oldRoute.Spec, newRoute.Spec) && reflect.DeepEqual(oldRoute.Annotations, newRoute.Annotations) {
 		return
 	}
+	// Skip routes which neither belonged nor belong to the resource class
+	if !ctlr.isManagedResourceClass(oldRoute.Annotations) && !ctlr.isManagedResourceClass(newRoute.Annotations) {
+		return
+	}
 	log.Debugf("Enqueueing Route: %v/%v", newRoute.ObjectMeta.Namespace, newRoute.ObjectMeta.Name)
 	key := &rqKey{
 		namespace: newRoute.ObjectMeta.Namespace,
@@ -1220,6 +1605,63 @@ func (ctlr *Controller) enqueueUpdatedRoute(old, cur interface{}) {
 	ctlr.resourceQueue.Add(key)
 }
 
+func (ctlr *Controller) enqueueIngress(obj interface{}, event string) {
+	ing := obj.(*netv1.Ingress)
+	if !ctlr.isManagedIngress(ing) {
+		return
+	}
+	log.Debugf("Enqueueing Ingress: %v/%v", ing.ObjectMeta.Namespace, ing.ObjectMeta.Name)
+	key := &rqKey{
+		namespace: ing.ObjectMeta.Namespace,
+		kind:      Ingress,
+		rscName:   ing.ObjectMeta.Name,
+		rsc:       obj,
+		event:     event,
+	}
+	ctlr.resourceQueue.Add(key)
+}
+
+func (ctlr *Controller) enqueueUpdatedIngress(oldObj, newObj interface{}) {
+	oldIng := oldObj.(*netv1.Ingress)
+	newIng := newObj.(*netv1.Ingress)
+	// Skip ingresses on status updates
+	if reflect.DeepEqual(oldIng.Spec, newIng.Spec) && reflect.DeepEqual(oldIng.Annotations, newIng.Annotations) {
+		return
+	}
+	// Handle ingresses moving in or out of the ingress class
+	oldManaged := ctlr.isManagedIngress(oldIng)
+	newManaged := ctlr.isManagedIngress(newIng)
+	if oldManaged != newManaged {
+		if oldManaged {
+			ctlr.enqueueIngress(oldObj, Delete)
+		} else {
+			ctlr.enqueueIngress(newObj, Create)
+		}
+		return
+	}
+	updateEvent := true
+	if !reflect.DeepEqual(getIngressHosts(oldIng), getIngressHosts(newIng)) ||
+		oldIng.Annotations[IngressVSAddressAnnotation] != newIng.Annotations[IngressVSAddressAnnotation] ||
+		oldIng.Annotations[LBServiceIPAMLabelAnnotation] != newIng.Annotations[LBServiceIPAMLabelAnnotation] ||
+		oldIng.Annotations[IngressPartitionAnnotation] != newIng.Annotations[IngressPartitionAnnotation] {
+		log.Debugf("Enqueueing Old Ingress: %v/%v", oldIng.ObjectMeta.Namespace, oldIng.ObjectMeta.Name)
+
+		oldPartition := ctlr.getCRPartition(oldIng.Annotations[IngressPartitionAnnotation])
+		newPartition := ctlr.getCRPartition(newIng.Annotations[IngressPartitionAnnotation])
+		// delete ingress from previous partition on priority when partition is changed
+		if oldPartition != newPartition {
+			ctlr.resources.updatePartitionPriority(oldPartition, 1)
+		}
+		ctlr.enqueueIngress(oldObj, Delete)
+		updateEvent = false
+	}
+	if updateEvent {
+		ctlr.enqueueIngress(newObj, Update)
+	} else {
+		ctlr.enqueueIngress(newObj, Create)
+	}
+}
+
 func (ctlr *Controller) enqueueConfigmap(obj interface{}, event string) {
 	cm := obj.(*corev1.ConfigMap)
 
@@ -1257,6 +1699,9 @@ func (ctlr *Controller) enqueueDeletedConfigmap(obj interface{}) {
 
 func (ctlr *Controller) enqueueDeletedRoute(obj interface{}) {
 	rt := obj.(*routeapi.Route)
+	if !ctlr.isManagedResourceClass(rt.Annotations) {
+		return
+	}
 
 	log.Debugf("Enqueueing Deleted Route: %v/%v", rt.ObjectMeta.Namespace, rt.ObjectMeta.Name)
 	key := &rqKey{
@@ -1343,6 +1788,53 @@ func (nodeInfr *NodeInformer) stop() {
 	close(nodeInfr.stopCh)
 }
 
+func (dcInfr *DeployConfigInformer) start() {
+	if dcInfr.dcInformer != nil {
+		log.Infof("Starting DeployConfig Informer")
+		go dcInfr.dcInformer.Run(dcInfr.stopCh)
+	}
+}
+
+func (dcInfr *DeployConfigInformer) stop() {
+	close(dcInfr.stopCh)
+}
+
+// newDeployConfigInformer watches the DeployConfig of the controller to apply its changes at runtime
+func (ctlr *Controller) newDeployConfigInformer() *DeployConfigInformer {
+	resyncPeriod := 0 * time.Second
+	dcOptions := func(options *metav1.ListOptions) {
+		options.FieldSelector = fields.OneTermEqualSelector("metadata.name", ctlr.deployConfigCR).String()
+	}
+	dcInfr := &DeployConfigInformer{
+		stopCh: make(chan struct{}),
+		dcInformer: cache.NewSharedIndexInformer(
+			newCRListWatch(
+				dcOptions,
+				func(options metav1.ListOptions) (runtime.Object, error) {
+					return ctlr.kubeCRClient.CisV1().DeployConfigs().List(context.TODO(), options)
+				},
+				func(options metav1.ListOptions) (watch.Interface, error) {
+					return ctlr.kubeCRClient.CisV1().DeployConfigs().Watch(context.TODO(), options)
+				},
+			),
+			&cisapiv1.DeployConfig{},
+			resyncPeriod,
+			cache.Indexers{},
+		),
+	}
+	dcInfr.dcInformer.AddEventHandler(
+		&cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { ctlr.processDeployConfig(obj.(*cisapiv1.DeployConfig)) },
+			UpdateFunc: func(old, cur interface{}) { ctlr.processDeployConfig(cur.(*cisapiv1.DeployConfig)) },
+			DeleteFunc: func(obj interface{}) {
+				log.Warningf("[DeployConfig] DeployConfig %v is deleted, controller continues with its last configuration",
+					ctlr.deployConfigCR)
+			},
+		},
+	)
+	return dcInfr
+}
+
 func (ctlr *Controller) createNamespaceLabeledInformer(label string) error {
 	selector, err := createLabelSelector(label)
 	if err != nil {
@@ -1363,12 +1855,12 @@ func (ctlr *Controller) createNamespaceLabeledInformer(label string) error {
 	ctlr.nsInformers[label] = &NSInformer{
 		stopCh: make(chan struct{}),
 		nsInformer: cache.NewSharedIndexInformer(
-			cache.NewFilteredListWatchFromClient(
+			newTransformListWatch(cache.NewFilteredListWatchFromClient(
 				restClientv1,
 				"namespaces",
 				"",
 				namespaceOptions,
-			),
+			)),
 			&corev1.Namespace{},
 			resyncPeriod,
 			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
@@ -1388,6 +1880,9 @@ func (ctlr *Controller) createNamespaceLabeledInformer(label string) error {
 
 func (ctlr *Controller) enqueueNamespace(obj interface{}) {
 	ns := obj.(*corev1.Namespace)
+	if !ctlr.namespaceShard.hasNamespace(ns.ObjectMeta.Name) {
+		return
+	}
 	log.Infof("Enqueueing Namespace: %v", ns)
 	key := &rqKey{
 		namespace: ns.ObjectMeta.Namespace,
@@ -1401,6 +1896,9 @@ func (ctlr *Controller) enqueueNamespace(obj interface{}) {
 
 func (ctlr *Controller) enqueueDeletedNamespace(obj interface{}) {
 	ns := obj.(*corev1.Namespace)
+	if !ctlr.namespaceShard.hasNamespace(ns.ObjectMeta.Name) {
+		return
+	}
 	log.Infof("Enqueueing Namespace: %v on Delete", ns)
 	key := &rqKey{
 		namespace: ns.ObjectMeta.Namespace,
@@ -1412,6 +1910,12 @@ func (ctlr *Controller) enqueueDeletedNamespace(obj interface{}) {
 	ctlr.resourceQueue.Add(key)
 }
 
+// isManagedResourceClass checks whether the resource belongs to the resource class of CIS.
+// Resources without resource class are processed only by CIS without resource class
+func (ctlr *Controller) isManagedResourceClass(annotations map[string]string) bool {
+	return annotations[ResourceClassAnnotation] == ctlr.resourceClass
+}
+
 func (ctlr *Controller) checkCoreserviceLabels(labels map[string]string) bool {
 	for _, v := range labels {
 		if _, ok := K8SCoreServices[v]; ok {
@@ -1426,6 +1930,13 @@ func (ctlr *Controller) checkCoreserviceLabels(labels map[string]string) bool {
 	return false
 }
 
+func (ctlr *Controller) enqueueClusterHealthProbeEvent() {
+	key := &rqKey{
+		kind: ClusterHealth,
+	}
+	ctlr.resourceQueue.Add(key)
+}
+
 func (ctlr *Controller) enqueuePrimaryClusterProbeEvent() {
 	log.Infof("[MultiCluster] Enqueueing on primary cluster down event")
 	key := &rqKey{
@@ -8,14 +8,20 @@ import (
 	crdfake "github.com/F5Networks/k8s-bigip-ctlr/v2/config/client/clientset/versioned/fake"
 	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/teem"
 	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/test"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	routeapi "github.com/openshift/api/route/v1"
 	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
+	"strconv"
 	"sync"
 )
 
@@ -55,6 +61,49 @@ var _ = Describe("Informers Tests", func() {
 			err = mockCtlr.createNamespaceLabeledInformer("app=test")
 			Expect(err).To(BeNil(), "Failed to Create Namespace Informer")
 		})
+
+		It("DeployConfig Informer", func() {
+			mockCtlr.deployConfigCR = "cisconfig"
+			mockCtlr.Agent = &Agent{PostManager: &PostManager{}}
+			dcInf := mockCtlr.newDeployConfigInformer()
+			Expect(dcInf.dcInformer).ToNot(BeNil(), "Failed to Create DeployConfig Informer")
+
+			// Arguments are set from the first DeployConfig observed
+			dc := test.NewDeployConfig("cisconfig", cisapiv1.DeployConfigSpec{
+				BaseConfig:  cisapiv1.BaseConfig{LogLevel: "INFO"},
+				BigIPConfig: cisapiv1.BigIPConfig{BigIPURL: "https://10.1.1.1"},
+			})
+			mockCtlr.processDeployConfig(dc)
+			Expect(mockCtlr.deployConfigSpec).To(Equal(&dc.Spec))
+			Expect(mockCtlr.Agent.AS3PostDelay).To(BeZero())
+
+			// Log level and AS3 configuration are applied
+			defer log.SetLogLevel(log.GetLogLevel())
+			dc = dc.DeepCopy()
+			dc.Spec.BaseConfig.LogLevel = "AS3DEBUG"
+			dc.Spec.AS3Config = cisapiv1.AS3Config{PostDelay: 10}
+			mockCtlr.processDeployConfig(dc)
+			Expect(log.GetLogLevel()).To(Equal(log.LogLevel(log.LL_DEBUG)))
+			Expect(mockCtlr.Agent.getAS3PostDelay()).To(Equal(10))
+			Expect(mockCtlr.Agent.isAS3RequestLogged()).To(BeTrue())
+			Expect(mockCtlr.Agent.isAS3ResponseLogged()).To(BeTrue())
+
+			dc = dc.DeepCopy()
+			dc.Spec.BaseConfig.LogLevel = "DEBUG"
+			logResponse := true
+			dc.Spec.AS3Config.LogResponse = &logResponse
+			mockCtlr.processDeployConfig(dc)
+			Expect(mockCtlr.Agent.isAS3RequestLogged()).To(BeFalse())
+			Expect(mockCtlr.Agent.isAS3ResponseLogged()).To(BeTrue())
+
+			// Other fields are not applied at runtime
+			dc = dc.DeepCopy()
+			dc.Spec.BigIPConfig.BigIPURL = "https://10.1.1.2"
+			dc.Spec.BaseConfig.LogLevel = "invalid"
+			mockCtlr.processDeployConfig(dc)
+			Expect(mockCtlr.deployConfigSpec.BigIPConfig.BigIPURL).To(Equal("https://10.1.1.2"))
+			Expect(log.GetLogLevel()).To(Equal(log.LogLevel(log.LL_DEBUG)))
+		})
 	})
 
 	Describe("Custom Resource Queueing", func() {
@@ -178,20 +227,33 @@ var _ = Describe("Informers Tests", func() {
 			mockCtlr.enqueueUpdatedVirtualServer(updatedVS2, updatedStatusVS)
 			Expect(mockCtlr.resourceQueue.Len()).To(Equal(0), "VS status update should be skipped")
 
-			// Verify VS Label update event is queued for processing
-			updatedLabelVS := test.NewVirtualServer(
-				"SampleVS",
-				namespace,
-				cisapiv1.VirtualServerSpec{
-					Host:                 "test.com",
-					VirtualServerAddress: "5.6.7.8",
-					SNAT:                 "none",
-				})
-			labels := make(map[string]string)
-			labels["f5cr"] = "false"
-			updatedLabelVS.Labels = labels
-			mockCtlr.enqueueUpdatedVirtualServer(updatedStatusVS, updatedLabelVS)
-			Expect(mockCtlr.resourceQueue.Len()).To(Equal(1), "VS label update should not be skipped")
+			// Verify VS annotation update of the other controllers is skipped as the generation is not changed
+			updatedStatusVS.Generation = 2
+			updatedAnnotationVS := updatedStatusVS.DeepCopy()
+			updatedAnnotationVS.Annotations = map[string]string{"kubectl.kubernetes.io/restartedAt": "now"}
+			mockCtlr.enqueueUpdatedVirtualServer(updatedStatusVS, updatedAnnotationVS)
+			Expect(mockCtlr.resourceQueue.Len()).To(Equal(0), "VS annotation update should be skipped")
+
+			// Verify VS Label update event is skipped as the generation is not changed
+			updatedLabelVS := updatedAnnotationVS.DeepCopy()
+			updatedLabelVS.Labels = map[string]string{"app": "test"}
+			mockCtlr.enqueueUpdatedVirtualServer(updatedAnnotationVS, updatedLabelVS)
+			Expect(mockCtlr.resourceQueue.Len()).To(Equal(0), "VS label update should be skipped")
+
+			// Verify VS GSLB annotation update event is queued for processing without the generation change
+			updatedGSLBVS := updatedLabelVS.DeepCopy()
+			updatedGSLBVS.Annotations[GSLBZoneAnnotation] = "test.com"
+			mockCtlr.enqueueUpdatedVirtualServer(updatedLabelVS, updatedGSLBVS)
+			Expect(mockCtlr.resourceQueue.Len()).To(Equal(1), "VS GSLB annotation update should not be skipped")
+			key, _ = mockCtlr.resourceQueue.Get()
+			mockCtlr.resourceQueue.Done(key)
+			mockCtlr.resourceQueue.Forget(key)
+
+			// Verify VS spec update event with the new generation is queued for processing
+			updatedSpecVS := updatedGSLBVS.DeepCopy()
+			updatedSpecVS.Generation = 3
+			mockCtlr.enqueueUpdatedVirtualServer(updatedGSLBVS, updatedSpecVS)
+			Expect(mockCtlr.resourceQueue.Len()).To(Equal(1), "VS spec update should not be skipped")
 		})
 
 		It("TLS Profile", func() {
@@ -263,14 +325,72 @@ var _ = Describe("Informers Tests", func() {
 			mockCtlr.enqueueUpdatedTransportServer(tsWithPartition, updatedStatusTS)
 			Expect(mockCtlr.resourceQueue.Len()).To(Equal(queueLen), "TS status update should be skipped")
 
-			// Verify TS Label update event is queued for processing
+			// Verify TS Label update event is skipped as the generation is not changed
+			updatedStatusTS.Generation = 2
 			updatedLabelTS := updatedStatusTS.DeepCopy()
-			labels := make(map[string]string)
-			labels["f5cr"] = "false"
-			updatedLabelTS.Labels = labels
+			updatedLabelTS.Labels = map[string]string{"app": "test"}
 			mockCtlr.enqueueUpdatedTransportServer(updatedStatusTS, updatedLabelTS)
-			Expect(mockCtlr.resourceQueue.Len()).To(Equal(queueLen+1), "TS label update should not be skipped")
+			Expect(mockCtlr.resourceQueue.Len()).To(Equal(queueLen), "TS label update should be skipped")
+
+			// Verify TS spec update event with the new generation is queued for processing
+			updatedSpecTS := updatedLabelTS.DeepCopy()
+			updatedSpecTS.Generation = 3
+			mockCtlr.enqueueUpdatedTransportServer(updatedLabelTS, updatedSpecTS)
+			Expect(mockCtlr.resourceQueue.Len()).To(Equal(queueLen+1), "TS spec update should not be skipped")
+
+		})
+
+		It("Resource Class", func() {
+			mockCtlr.resourceClass = "cis-a"
+			defer func() { mockCtlr.resourceClass = "" }()
+
+			vs := test.NewVirtualServer(
+				"SampleVS",
+				namespace,
+				cisapiv1.VirtualServerSpec{
+					Host:                 "test.com",
+					VirtualServerAddress: "1.2.3.4",
+				})
+			mockCtlr.enqueueVirtualServer(vs)
+			Expect(mockCtlr.resourceQueue.Len()).To(Equal(0), "VS without resource class should be skipped")
+
+			classVS := vs.DeepCopy()
+			classVS.Annotations = map[string]string{ResourceClassAnnotation: "cis-b"}
+			mockCtlr.enqueueVirtualServer(classVS)
+			mockCtlr.enqueueDeletedVirtualServer(classVS)
+			Expect(mockCtlr.resourceQueue.Len()).To(Equal(0), "VS of other resource class should be skipped")
+
+			managedVS := vs.DeepCopy()
+			managedVS.Annotations = map[string]string{ResourceClassAnnotation: "cis-a"}
+			mockCtlr.enqueueUpdatedVirtualServer(classVS, managedVS)
+			Expect(mockCtlr.resourceQueue.Len()).To(Equal(1), "VS moved to resource class should be queued")
+			key, _ := mockCtlr.resourceQueue.Get()
+			Expect(key.(*rqKey).event).To(Equal(Create))
+			mockCtlr.resourceQueue.Done(key)
+
+			mockCtlr.enqueueUpdatedVirtualServer(managedVS, classVS)
+			Expect(mockCtlr.resourceQueue.Len()).To(Equal(1), "VS moved out of resource class should be queued")
+			key, _ = mockCtlr.resourceQueue.Get()
+			Expect(key.(*rqKey).event).To(Equal(Delete))
+			mockCtlr.resourceQueue.Done(key)
 
+			ts := test.NewTransportServer(
+				"SampleTS",
+				namespace,
+				cisapiv1.TransportServerSpec{
+					SNAT:                 "auto",
+					VirtualServerAddress: "1.2.3.4",
+				})
+			mockCtlr.enqueueTransportServer(ts)
+			Expect(mockCtlr.resourceQueue.Len()).To(Equal(0), "TS without resource class should be skipped")
+			ts.Annotations = map[string]string{ResourceClassAnnotation: "cis-a"}
+			mockCtlr.enqueueTransportServer(ts)
+			Expect(mockCtlr.resourceQueue.Len()).To(Equal(1), "TS of resource class should be queued")
+
+			ing := test.NewIngressNetV1("ing1", "1", namespace, netv1.IngressSpec{}, nil)
+			Expect(mockCtlr.isManagedIngress(ing)).To(BeFalse(), "Ingress without class should be skipped")
+			ing.Annotations = map[string]string{IngressClassAnnotation: "cis-a"}
+			Expect(mockCtlr.isManagedIngress(ing)).To(BeTrue(), "Ingress of resource class should be managed")
 		})
 
 		It("IngressLink", func() {
@@ -307,6 +427,12 @@ var _ = Describe("Informers Tests", func() {
 					IRules:               iRules,
 				},
 			)
+			// IngressLink is not processed on the status update
+			ilWithStatus := il.DeepCopy()
+			ilWithStatus.Status.VSAddress = "1.2.3.4"
+			mockCtlr.enqueueUpdatedIngressLink(il, ilWithStatus)
+			Expect(mockCtlr.resourceQueue.Len()).To(Equal(0), "IngressLink enqueued on status update")
+
 			mockCtlr.enqueueUpdatedIngressLink(il, newIL)
 			key, quit = mockCtlr.resourceQueue.Get()
 			Expect(key).ToNot(BeNil(), "Enqueue Updated IL Failed")
@@ -570,6 +696,41 @@ var _ = Describe("Informers Tests", func() {
 			//Expect(mockCtlr.processResources()).To(Equal(true))
 		})
 
+		It("Namespace of the shard", func() {
+			shards := []NamespaceShard{{Count: 3, Index: 0}, {Count: 3, Index: 1}, {Count: 3, Index: 2}}
+			for i := 0; i < 30; i++ {
+				ns := "team-" + strconv.Itoa(i)
+				assigned := 0
+				for _, shard := range shards {
+					if shard.hasNamespace(ns) {
+						assigned++
+					}
+				}
+				Expect(assigned).To(Equal(1), "Namespace not assigned to a single shard")
+			}
+			Expect(NamespaceShard{Count: 1}.hasNamespace("SampleNS")).To(BeTrue())
+
+			for _, shard := range shards {
+				mockCtlr.namespaceShard = shard
+				ns := test.NewNamespace("SampleNS", "1", map[string]string{})
+				mockCtlr.enqueueNamespace(ns)
+				mockCtlr.enqueueDeletedNamespace(ns)
+				if shard.hasNamespace("SampleNS") {
+					Expect(mockCtlr.resourceQueue.Len()).To(Equal(2), "Namespace of the shard not enqueued")
+				} else {
+					Expect(mockCtlr.resourceQueue.Len()).To(BeZero(), "Namespace of the other shard enqueued")
+				}
+				for mockCtlr.resourceQueue.Len() > 0 {
+					key, _ := mockCtlr.resourceQueue.Get()
+					mockCtlr.resourceQueue.Done(key)
+				}
+			}
+
+			Expect(GetShardIndex("k8s-bigip-ctlr-2")).To(Equal(2))
+			_, err := GetShardIndex("k8s-bigip-ctlr-7d9f8c6b5-x2xkq")
+			Expect(err).ToNot(BeNil(), "Shard index of the Deployment pod name")
+		})
+
 		It("IPAM", func() {
 			mockCtlr.ipamCR = "default/SampleIPAM"
 
@@ -661,6 +822,78 @@ var _ = Describe("Informers Tests", func() {
 			Expect(nrInr).ToNot(BeNil(), "Finding Informer Failed")
 			Expect(found).To(BeTrue(), "Finding Informer Failed")
 		})
+		It("Strips the managed fields and the last applied configuration", func() {
+			newSecret := func(name string) *v1.Secret {
+				return &v1.Secret{ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+					Annotations: map[string]string{
+						v1.LastAppliedConfigAnnotation: `{"kind":"Secret"}`,
+						"cis.f5.com/test":              "true",
+					},
+					ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply}},
+				}}
+			}
+			assertStripped := func(obj runtime.Object) {
+				secret := obj.(*v1.Secret)
+				Expect(secret.ManagedFields).To(BeNil())
+				Expect(secret.Annotations).To(Equal(map[string]string{"cis.f5.com/test": "true"}))
+			}
+			secrets := mockCtlr.kubeClient.CoreV1().Secrets(namespace)
+			_, _ = secrets.Create(context.TODO(), newSecret("secret1"), metav1.CreateOptions{})
+			lw := newTransformListWatch(&cache.ListWatch{
+				ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+					return secrets.List(context.TODO(), options)
+				},
+				WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+					return secrets.Watch(context.TODO(), options)
+				},
+			})
+			list, err := lw.List(metav1.ListOptions{})
+			Expect(err).To(BeNil())
+			Expect(list.(*v1.SecretList).Items).To(HaveLen(1))
+			assertStripped(&list.(*v1.SecretList).Items[0])
+
+			w, err := lw.Watch(metav1.ListOptions{})
+			Expect(err).To(BeNil())
+			defer w.Stop()
+			_, _ = secrets.Create(context.TODO(), newSecret("secret2"), metav1.CreateOptions{})
+			var event watch.Event
+			Eventually(w.ResultChan()).Should(Receive(&event))
+			Expect(event.Type).To(Equal(watch.Added))
+			assertStripped(event.Object)
+
+			// core types are negotiated in protobuf without modifying the config of the custom resources
+			config := &rest.Config{Host: "https://kube-apiserver"}
+			protoConfig := getProtobufConfig(config)
+			Expect(protoConfig.ContentType).To(Equal(runtime.ContentTypeProtobuf))
+			Expect(protoConfig.AcceptContentTypes).To(Equal("application/vnd.kubernetes.protobuf,application/json"))
+			Expect(config.ContentType).To(BeEmpty())
+		})
+		It("Strips the managed fields of the custom resources", func() {
+			mockCtlr.customResourceSelector, _ = createLabelSelector(DefaultCustomResourceLabel)
+			newVS := func(name string, labels map[string]string) *cisapiv1.VirtualServer {
+				vs := test.NewVirtualServer(name, namespace, cisapiv1.VirtualServerSpec{Host: "foo.com"})
+				vs.Labels = labels
+				vs.Annotations = map[string]string{v1.LastAppliedConfigAnnotation: `{"kind":"VirtualServer"}`}
+				vs.ManagedFields = []metav1.ManagedFieldsEntry{{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply}}
+				return vs
+			}
+			virtuals := mockCtlr.kubeCRClient.CisV1().VirtualServers(namespace)
+			_, _ = virtuals.Create(context.TODO(), newVS("vs1", map[string]string{"f5cr": "true"}), metav1.CreateOptions{})
+			_, _ = virtuals.Create(context.TODO(), newVS("vs2", nil), metav1.CreateOptions{})
+
+			crInf := mockCtlr.newNamespacedCustomResourceInformer(namespace)
+			go crInf.vsInformer.Run(crInf.stopCh)
+			defer close(crInf.stopCh)
+			Eventually(crInf.vsInformer.HasSynced).Should(BeTrue())
+			objs := crInf.vsInformer.GetStore().List()
+			Expect(objs).To(HaveLen(1), "Label selector of the custom resources not applied")
+			vs := objs[0].(*cisapiv1.VirtualServer)
+			Expect(vs.Name).To(Equal("vs1"))
+			Expect(vs.ManagedFields).To(BeNil())
+			Expect(vs.Annotations).To(BeEmpty())
+		})
 	})
 
 	Describe("Native Resource Queueing", func() {
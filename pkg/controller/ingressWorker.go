@@ -0,0 +1,276 @@
+/*-
+* Copyright (c) 2016-2021, F5 Networks, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// getAllIngresses returns list of all Ingresses in the namespace
+func (ctlr *Controller) getAllIngresses(namespace string) []*netv1.Ingress {
+	var allIngresses []*netv1.Ingress
+
+	crInf, ok := ctlr.getNamespacedCRInformer(namespace)
+	if !ok || crInf.ingInformer == nil {
+		return nil
+	}
+
+	var ingresses []interface{}
+	var err error
+	if namespace == "" {
+		ingresses = crInf.ingInformer.GetIndexer().List()
+	} else {
+		ingresses, err = crInf.ingInformer.GetIndexer().ByIndex("namespace", namespace)
+		if err != nil {
+			log.Errorf("Unable to get list of Ingresses for namespace '%v': %v",
+				namespace, err)
+			return nil
+		}
+	}
+
+	for _, obj := range ingresses {
+		allIngresses = append(allIngresses, obj.(*netv1.Ingress))
+	}
+	return allIngresses
+}
+
+// getIngressVirtualServers returns the VirtualServers derived from all
+// the Ingresses in the namespace
+func (ctlr *Controller) getIngressVirtualServers(namespace string) []*cisapiv1.VirtualServer {
+	var virtuals []*cisapiv1.VirtualServer
+	for _, ing := range ctlr.getAllIngresses(namespace) {
+		virtuals = append(virtuals, ctlr.getVirtualServersForIngress(ing)...)
+	}
+	return virtuals
+}
+
+// getIngressClass returns the ingress class managed by CIS, resource class
+// takes precedence over the ingress class
+func (ctlr *Controller) getIngressClass() string {
+	if ctlr.resourceClass != "" {
+		return ctlr.resourceClass
+	}
+	if ctlr.ingressClass != "" {
+		return ctlr.ingressClass
+	}
+	return DefaultIngressClass
+}
+
+// isManagedIngress checks whether the Ingress belongs to the ingress class of CIS.
+// Ingresses without class are processed only by CIS without resource class
+func (ctlr *Controller) isManagedIngress(ing *netv1.Ingress) bool {
+	class := ing.Annotations[IngressClassAnnotation]
+	if ing.Spec.IngressClassName != nil {
+		class = *ing.Spec.IngressClassName
+	}
+	if class == "" {
+		return ctlr.resourceClass == ""
+	}
+	return class == ctlr.getIngressClass()
+}
+
+// getIngressHosts returns the hosts of the Ingress rules in the order of appearance
+func getIngressHosts(ing *netv1.Ingress) []string {
+	var hosts []string
+	seen := make(map[string]struct{})
+	for _, rule := range ing.Spec.Rules {
+		if _, ok := seen[rule.Host]; ok {
+			continue
+		}
+		seen[rule.Host] = struct{}{}
+		hosts = append(hosts, rule.Host)
+	}
+	if len(hosts) == 0 && ing.Spec.DefaultBackend != nil {
+		// Ingress with only a default backend is served by a hostless virtual
+		hosts = append(hosts, "")
+	}
+	return hosts
+}
+
+// getIngressNameForVirtualServer returns the name of the Ingress
+// the VirtualServer is derived from
+func getIngressNameForVirtualServer(vs *cisapiv1.VirtualServer) (string, bool) {
+	for _, owner := range vs.OwnerReferences {
+		if owner.Kind == Ingress {
+			return owner.Name, true
+		}
+	}
+	return "", false
+}
+
+// getIngressBackendPort returns the service port referred by the Ingress backend
+func getIngressBackendPort(backend *netv1.IngressServiceBackend) intstr.IntOrString {
+	if backend.Port.Name != "" {
+		return intstr.FromString(backend.Port.Name)
+	}
+	return intstr.FromInt(int(backend.Port.Number))
+}
+
+// getIngressHTTPTraffic returns the HTTPTraffic of a TLS virtual based on the Ingress annotations
+func getIngressHTTPTraffic(ing *netv1.Ingress) string {
+	if ing.Annotations[IngressSSLRedirectAnnotation] != "false" {
+		return TLSRedirectInsecure
+	}
+	if ing.Annotations[IngressAllowHTTPAnnotation] == "true" {
+		return TLSAllowInsecure
+	}
+	return TLSNoInsecure
+}
+
+// getVirtualServersForIngress translates the Ingress into VirtualServers, one for each of
+// the Ingress hosts, so that Ingress resources are processed like VirtualServers
+func (ctlr *Controller) getVirtualServersForIngress(ing *netv1.Ingress) []*cisapiv1.VirtualServer {
+	if !ctlr.isManagedIngress(ing) {
+		log.Debugf("Skipping Ingress %v/%v as it does not belong to ingress class %v",
+			ing.Namespace, ing.Name, ctlr.getIngressClass())
+		return nil
+	}
+
+	tlsSecrets := make(map[string]string)
+	for _, tls := range ing.Spec.TLS {
+		if tls.SecretName == "" {
+			continue
+		}
+		for _, host := range tls.Hosts {
+			tlsSecrets[host] = tls.SecretName
+		}
+	}
+
+	var defaultPool cisapiv1.DefaultPool
+	if ing.Spec.DefaultBackend != nil && ing.Spec.DefaultBackend.Service != nil {
+		defaultPool = cisapiv1.DefaultPool{
+			Reference:   ServiceRef,
+			Service:     ing.Spec.DefaultBackend.Service.Name,
+			ServicePort: getIngressBackendPort(ing.Spec.DefaultBackend.Service),
+		}
+	}
+
+	var virtuals []*cisapiv1.VirtualServer
+	for _, host := range getIngressHosts(ing) {
+		vsName := ing.Name
+		if host != "" {
+			vsName = fmt.Sprintf("%s_%s", ing.Name, host)
+		}
+		vs := &cisapiv1.VirtualServer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        vsName,
+				Namespace:   ing.Namespace,
+				Labels:      ing.Labels,
+				Annotations: ing.Annotations,
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "networking.k8s.io/v1",
+					Kind:       Ingress,
+					Name:       ing.Name,
+					UID:        ing.UID,
+				}},
+			},
+			Spec: cisapiv1.VirtualServerSpec{
+				Host:                 host,
+				VirtualServerAddress: ing.Annotations[IngressVSAddressAnnotation],
+				IPAMLabel:            ing.Annotations[LBServiceIPAMLabelAnnotation],
+				Partition:            ing.Annotations[IngressPartitionAnnotation],
+				DefaultPool:          defaultPool,
+			},
+		}
+		if secret, ok := tlsSecrets[host]; ok {
+			vs.Spec.TLSProfileName = secret
+			vs.Spec.HTTPTraffic = getIngressHTTPTraffic(ing)
+		}
+
+		for _, rule := range ing.Spec.Rules {
+			if rule.Host != host || rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				if path.Backend.Service == nil {
+					log.Warningf("Skipping path %v of Ingress %v/%v as only service backends are supported",
+						path.Path, ing.Namespace, ing.Name)
+					continue
+				}
+				pool := cisapiv1.Pool{
+					Path:        path.Path,
+					PathType:    PathTypePrefix,
+					Service:     path.Backend.Service.Name,
+					ServicePort: getIngressBackendPort(path.Backend.Service),
+				}
+				if pool.Path == "" {
+					pool.Path = "/"
+				}
+				if path.PathType != nil && *path.PathType == netv1.PathTypeExact {
+					pool.PathType = PathTypeExact
+				}
+				vs.Spec.Pools = append(vs.Spec.Pools, pool)
+			}
+		}
+		virtuals = append(virtuals, vs)
+	}
+	return virtuals
+}
+
+// getTLSProfileForIngressVirtualServer returns an edge TLSProfile referring
+// the Ingress TLS secret of the VirtualServer host
+func getTLSProfileForIngressVirtualServer(vs *cisapiv1.VirtualServer) *cisapiv1.TLSProfile {
+	return &cisapiv1.TLSProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      vs.Spec.TLSProfileName,
+			Namespace: vs.Namespace,
+		},
+		Spec: cisapiv1.TLSProfileSpec{
+			Hosts: []string{vs.Spec.Host},
+			TLS: cisapiv1.TLS{
+				Termination: TLSEdge,
+				Reference:   "secret",
+				ClientSSL:   vs.Spec.TLSProfileName,
+			},
+		},
+	}
+}
+
+// getIngressVirtualServersForSecret returns the Ingress VirtualServers affected by the secret
+func (ctlr *Controller) getIngressVirtualServersForSecret(secret *v1.Secret) []*cisapiv1.VirtualServer {
+	var virtuals []*cisapiv1.VirtualServer
+	for _, vs := range ctlr.getIngressVirtualServers(secret.Namespace) {
+		if vs.Spec.TLSProfileName == secret.Name {
+			virtuals = append(virtuals, vs)
+		}
+	}
+	return virtuals
+}
+
+// updateIngressStatus sets the Ingress load balancer status to the virtual address
+func (ctlr *Controller) updateIngressStatus(ing *netv1.Ingress, ip string) {
+	if ip == "" {
+		return
+	}
+	if len(ing.Status.LoadBalancer.Ingress) == 1 && ing.Status.LoadBalancer.Ingress[0].IP == ip {
+		return
+	}
+	ing = ing.DeepCopy()
+	ing.Status.LoadBalancer.Ingress = []v1.LoadBalancerIngress{{IP: ip}}
+	_, updateErr := ctlr.kubeClient.NetworkingV1().Ingresses(ing.ObjectMeta.Namespace).UpdateStatus(context.TODO(), ing, metav1.UpdateOptions{})
+	if nil != updateErr {
+		log.Debugf("Error while updating ingress status:%v", updateErr)
+	}
+}
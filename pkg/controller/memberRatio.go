@@ -0,0 +1,234 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// PoolMemberRatioAnnotation is the annotation of the pods with the ratio of their pool members
+	PoolMemberRatioAnnotation = "cis.f5.com/pool-member-ratio"
+
+	// sources of the ratio of the pool members
+	MemberRatioAnnotation  = "annotation"
+	MemberRatioCPURequests = "cpu-requests"
+
+	// range of the ratio of the AS3 pool members
+	minMemberRatio = 1
+	maxMemberRatio = 100
+
+	// ratio of the members warming up is raised in the steps of the warm-up period
+	warmUpSteps = 10
+)
+
+// warmUpStore has the pods warming up with the pending update of the ratio of their pool members
+type warmUpStore struct {
+	sync.Mutex
+	pending map[string]struct{}
+}
+
+// setPoolMemberRatio sets the ratio of the pool members of the service from the pod annotation or proportional to
+// the CPU requests of the pods, the pod with the largest CPU requests has the maximum ratio. Members of the pods
+// without the ratio have the default ratio of BIG-IP
+func (ctlr *Controller) setPoolMemberRatio(namespace, service, source string, members []PoolMember) []PoolMember {
+	if !ctlr.poolMemberRatio || ctlr.PoolMemberType != Cluster {
+		return members
+	}
+	if comInf, ok := ctlr.getNamespacedCommonInformer(namespace); !ok || comInf.podInformer == nil {
+		log.Warningf("Pod informer not found for namespace %v, using the default ratio for the pool members of "+
+			"service %v", namespace, service)
+		return members
+	}
+	pods := ctlr.GetPodsForService(namespace, service, false)
+	weights := make(map[string]int64)
+	var maxWeight int64
+	for _, pod := range pods {
+		var weight int64
+		switch source {
+		case MemberRatioAnnotation:
+			weight = getPodRatioAnnotation(pod)
+		case MemberRatioCPURequests:
+			weight = getPodCPURequests(pod)
+		}
+		if weight <= 0 {
+			continue
+		}
+		if weight > maxWeight {
+			maxWeight = weight
+		}
+		if pod.Status.PodIP != "" {
+			weights[pod.Status.PodIP] = weight
+		}
+		for _, podIP := range pod.Status.PodIPs {
+			weights[podIP.IP] = weight
+		}
+	}
+	ratioMembers := make([]PoolMember, 0, len(members))
+	for _, member := range members {
+		if weight, ok := weights[member.Address]; ok {
+			if source == MemberRatioCPURequests {
+				// CPU requests are scaled to the range of the ratio
+				weight = (weight*maxMemberRatio + maxWeight/2) / maxWeight
+				if weight < minMemberRatio {
+					weight = minMemberRatio
+				}
+			}
+			member.Ratio = int(weight)
+		}
+		ratioMembers = append(ratioMembers, member)
+	}
+	return ratioMembers
+}
+
+// getPodRatioAnnotation returns the ratio of the pool member annotation of the pod, invalid ratios are ignored
+func getPodRatioAnnotation(pod *v1.Pod) int64 {
+	annotation, ok := pod.Annotations[PoolMemberRatioAnnotation]
+	if !ok {
+		return 0
+	}
+	ratio, err := strconv.ParseInt(annotation, 10, 64)
+	if err != nil || ratio < minMemberRatio || ratio > maxMemberRatio {
+		log.Warningf("Invalid %v annotation %q of pod %v/%v, ratio must be from %v to %v",
+			PoolMemberRatioAnnotation, annotation, pod.Namespace, pod.Name, minMemberRatio, maxMemberRatio)
+		return 0
+	}
+	return ratio
+}
+
+// getPodCPURequests returns the CPU requests of the containers of the pod in millicores
+func getPodCPURequests(pod *v1.Pod) int64 {
+	var requests int64
+	for _, container := range pod.Spec.Containers {
+		if cpu, ok := container.Resources.Requests[v1.ResourceCPU]; ok {
+			requests += cpu.MilliValue()
+		}
+	}
+	return requests
+}
+
+// setWarmUpMemberRatio skips the pool members of the pods whose readiness gates are not passed and raises the
+// ratio of the members of the pods ready since less than the warm-up period gradually, so that the new pods of a
+// scale-up are not flooded with the traffic. Members without the ratio set by memberRatio have the maximum ratio
+func (ctlr *Controller) setWarmUpMemberRatio(namespace, service string, period int32,
+	members []PoolMember) []PoolMember {
+	if !ctlr.poolMemberRatio || ctlr.PoolMemberType != Cluster {
+		return members
+	}
+	if comInf, ok := ctlr.getNamespacedCommonInformer(namespace); !ok || comInf.podInformer == nil {
+		log.Warningf("Pod informer not found for namespace %v, warm-up of the pool members of service %v is "+
+			"skipped", namespace, service)
+		return members
+	}
+	pods := make(map[string]*v1.Pod)
+	for _, pod := range ctlr.GetPodsForService(namespace, service, false) {
+		if pod.Status.PodIP != "" {
+			pods[pod.Status.PodIP] = pod
+		}
+		for _, podIP := range pod.Status.PodIPs {
+			pods[podIP.IP] = pod
+		}
+	}
+	window := time.Duration(period) * time.Second
+	warmUpMembers := make([]PoolMember, 0, len(members))
+	for _, member := range members {
+		if member.Ratio == 0 {
+			member.Ratio = maxMemberRatio
+		}
+		if pod, ok := pods[member.Address]; ok {
+			if !isPodReadinessGatesPassed(pod) {
+				log.Debugf("Readiness gates of pod %v/%v are not passed, skipping the pool member %v of service %v",
+					pod.Namespace, pod.Name, member.Address, service)
+				continue
+			}
+			if readyTime := getPodReadyTime(pod); !readyTime.IsZero() {
+				if elapsed := time.Since(readyTime); elapsed < window {
+					member.Ratio = int(int64(member.Ratio) * int64(elapsed) / int64(window))
+					if member.Ratio < minMemberRatio {
+						member.Ratio = minMemberRatio
+					}
+					ctlr.scheduleWarmUp(pod, window/warmUpSteps)
+				}
+			}
+		}
+		warmUpMembers = append(warmUpMembers, member)
+	}
+	return warmUpMembers
+}
+
+// scheduleWarmUp processes the pod again after the step of the warm-up period to raise the ratio of its pool
+// members, pods are scheduled once for the pools of the service
+func (ctlr *Controller) scheduleWarmUp(pod *v1.Pod, step time.Duration) {
+	if step < time.Second {
+		step = time.Second
+	}
+	key := pod.Namespace + "/" + pod.Name
+	store := &ctlr.warmUpPods
+	store.Lock()
+	defer store.Unlock()
+	if _, ok := store.pending[key]; ok {
+		return
+	}
+	if store.pending == nil {
+		store.pending = make(map[string]struct{})
+	}
+	store.pending[key] = struct{}{}
+	time.AfterFunc(step, func() {
+		store.Lock()
+		delete(store.pending, key)
+		store.Unlock()
+		comInf, ok := ctlr.getNamespacedCommonInformer(pod.Namespace)
+		if !ok || comInf.podInformer == nil {
+			return
+		}
+		if obj, exists, err := comInf.podInformer.GetIndexer().GetByKey(key); err == nil && exists {
+			ctlr.enqueuePod(obj, "")
+		}
+	})
+}
+
+// isPodReadinessGatesPassed checks whether the conditions of the readiness gates of the pod are true
+func isPodReadinessGatesPassed(pod *v1.Pod) bool {
+	for _, gate := range pod.Spec.ReadinessGates {
+		passed := false
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == gate.ConditionType && condition.Status == v1.ConditionTrue {
+				passed = true
+				break
+			}
+		}
+		if !passed {
+			return false
+		}
+	}
+	return true
+}
+
+// getPodReadyTime returns the time when the pod is ready, zero time is returned for the pods not ready
+func getPodReadyTime(pod *v1.Pod) time.Time {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady && condition.Status == v1.ConditionTrue {
+			return condition.LastTransitionTime.Time
+		}
+	}
+	return time.Time{}
+}
@@ -1,7 +1,13 @@
 package controller
 
 import (
+	"context"
+	"fmt"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	"net"
 	"net/http"
 	"os"
@@ -18,6 +24,8 @@ func (postMgr *PostManager) checkPrimaryClusterHealthStatus() bool {
 			status = postMgr.getPrimaryClusterHealthStatusFromHTTPEndPoint()
 		case "tcp":
 			status = postMgr.getPrimaryClusterHealthStatusFromTCPEndPoint()
+		case "lease":
+			status = postMgr.getPrimaryClusterHealthStatusFromLease()
 		case "", "default":
 			log.Debugf("[MultiCluster] unsupported primaryEndPoint specified under highAvailabilityCIS section: %v", postMgr.PrimaryClusterHealthProbeParams.EndPoint)
 			return false
@@ -32,7 +40,7 @@ func (postMgr *PostManager) checkPrimaryClusterHealthStatus() bool {
 }
 
 // getPrimaryClusterHealthCheckEndPointType method determines type of probe to be done from CIS parameters
-// http/tcp are the supported types
+// http/tcp/lease are the supported types
 // when cis runs in primary mode this method should never be called
 // should be called only when cis is running in secondary mode
 func (postMgr *PostManager) setPrimaryClusterHealthCheckEndPointType() {
@@ -41,9 +49,18 @@ func (postMgr *PostManager) setPrimaryClusterHealthCheckEndPointType() {
 			postMgr.PrimaryClusterHealthProbeParams.EndPointType = "tcp"
 		} else if strings.HasPrefix(postMgr.PrimaryClusterHealthProbeParams.EndPoint, "http://") {
 			postMgr.PrimaryClusterHealthProbeParams.EndPointType = "http"
+		} else if strings.HasPrefix(postMgr.PrimaryClusterHealthProbeParams.EndPoint, "lease://") {
+			namespace, name, err := parseLeaseEndPoint(postMgr.PrimaryClusterHealthProbeParams.EndPoint)
+			if err != nil {
+				log.Errorf("[MultiCluster] %v", err)
+				os.Exit(1)
+			}
+			postMgr.PrimaryClusterHealthProbeParams.EndPointType = "lease"
+			postMgr.PrimaryClusterHealthProbeParams.leaseNamespace = namespace
+			postMgr.PrimaryClusterHealthProbeParams.leaseName = name
 		} else {
 			log.Debugf("[MultiCluster] unsupported primaryEndPoint protocol type configured under highAvailabilityCIS section. EndPoint: %v \n "+
-				"supported protocols:[http, tcp] ", postMgr.PrimaryClusterHealthProbeParams.EndPoint)
+				"supported protocols:[http, tcp, lease] ", postMgr.PrimaryClusterHealthProbeParams.EndPoint)
 			os.Exit(1)
 		}
 	}
@@ -107,6 +124,125 @@ func (postMgr *PostManager) getPrimaryClusterHealthStatusFromTCPEndPoint() bool
 	return true
 }
 
+// getPrimaryClusterHealthStatusFromLease check the primary cluster health using the lease renewed by primary CIS in
+// the primary cluster, primary cluster is down if its API server is unreachable or the lease isn't renewed for the lease
+// duration. Renewal is tracked with the local clock to avoid the clock skew between the clusters
+func (postMgr *PostManager) getPrimaryClusterHealthStatusFromLease() bool {
+	if postMgr.PrimaryClusterHealthProbeParams.leaseClient == nil {
+		log.Debugf("[MultiCluster] primary cluster kubeconfig not found for primaryEndPoint lease: %v",
+			postMgr.PrimaryClusterHealthProbeParams.EndPoint)
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultLeaseTimeout*time.Second)
+	defer cancel()
+	lease, err := postMgr.PrimaryClusterHealthProbeParams.leaseClient.CoordinationV1().Leases(
+		postMgr.PrimaryClusterHealthProbeParams.leaseNamespace).Get(ctx, postMgr.PrimaryClusterHealthProbeParams.leaseName,
+		metav1.GetOptions{})
+	if err != nil {
+		log.Debugf("[MultiCluster] error fetching primaryEndPoint lease: %v, error: %v", postMgr.PrimaryClusterHealthProbeParams.EndPoint, err)
+		return false
+	}
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return false
+	}
+	now := time.Now()
+	if !lease.Spec.RenewTime.Time.Equal(postMgr.PrimaryClusterHealthProbeParams.leaseRenewTime) {
+		postMgr.PrimaryClusterHealthProbeParams.leaseRenewTime = lease.Spec.RenewTime.Time
+		postMgr.PrimaryClusterHealthProbeParams.leaseObservedTime = now
+	}
+	if now.Sub(postMgr.PrimaryClusterHealthProbeParams.leaseObservedTime) >= time.Duration(*lease.Spec.LeaseDurationSeconds)*time.Second {
+		log.Debugf("[MultiCluster] primaryEndPoint lease: %v is not renewed since %v", postMgr.PrimaryClusterHealthProbeParams.EndPoint,
+			postMgr.PrimaryClusterHealthProbeParams.leaseRenewTime)
+		return false
+	}
+	return true
+}
+
+// parseLeaseEndPoint returns the namespace and name of the lease endpoint in the format lease://namespace/name
+func parseLeaseEndPoint(endPoint string) (string, string, error) {
+	splits := strings.Split(strings.TrimPrefix(endPoint, "lease://"), "/")
+	if len(splits) != 2 || splits[0] == "" || splits[1] == "" {
+		return "", "", fmt.Errorf("invalid primaryEndPoint lease: %v, lease should be in the format lease://namespace/name", endPoint)
+	}
+	return splits[0], splits[1], nil
+}
+
+// updatePrimaryClusterLease updates the lease renewed by the primary CIS with lease primaryEndPoint, renewal of the
+// lease is started with the first lease endpoint
+func (ctlr *Controller) updatePrimaryClusterLease(endPoint string, holder string) {
+	ctlr.primaryLease.Lock()
+	defer ctlr.primaryLease.Unlock()
+	ctlr.primaryLease.namespace = ""
+	ctlr.primaryLease.name = ""
+	if !strings.HasPrefix(endPoint, "lease://") {
+		return
+	}
+	namespace, name, err := parseLeaseEndPoint(endPoint)
+	if err != nil {
+		log.Errorf("[MultiCluster] %v", err)
+		return
+	}
+	ctlr.primaryLease.namespace = namespace
+	ctlr.primaryLease.name = name
+	ctlr.primaryLease.holder = holder
+	if !ctlr.primaryLease.renewing {
+		ctlr.primaryLease.renewing = true
+		go ctlr.renewPrimaryClusterLease()
+	}
+}
+
+// renewPrimaryClusterLease runs as a thread and renews the lease of the primary CIS periodically, secondary CIS takes
+// over the BIG-IP when the lease isn't renewed any more or the primary cluster is unreachable
+func (ctlr *Controller) renewPrimaryClusterLease() {
+	for {
+		ctlr.primaryLease.Lock()
+		namespace, name, holder := ctlr.primaryLease.namespace, ctlr.primaryLease.name, ctlr.primaryLease.holder
+		ctlr.primaryLease.Unlock()
+		if name != "" {
+			if err := ctlr.renewLease(namespace, name, holder); err != nil {
+				log.Warningf("[MultiCluster] Unable to renew the primaryEndPoint lease %v/%v: %v", namespace, name, err)
+			}
+		}
+		time.Sleep(DefaultLeaseRenewInterval * time.Second)
+	}
+}
+
+// renewLease creates or renews the lease with the holder
+func (ctlr *Controller) renewLease(namespace, name, holder string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultLeaseTimeout*time.Second)
+	defer cancel()
+	leaseDuration := int32(DefaultLeaseDuration)
+	renewTime := metav1.NewMicroTime(time.Now())
+	leases := ctlr.kubeClient.CoordinationV1().Leases(namespace)
+	lease, err := leases.Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = leases.Create(ctx, &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &holder,
+				LeaseDurationSeconds: &leaseDuration,
+				AcquireTime:          &renewTime,
+				RenewTime:            &renewTime,
+			},
+		}, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != holder {
+		lease.Spec.AcquireTime = &renewTime
+	}
+	lease.Spec.HolderIdentity = &holder
+	lease.Spec.LeaseDurationSeconds = &leaseDuration
+	lease.Spec.RenewTime = &renewTime
+	_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
 func (postMgr *PostManager) httpGetReq(request *http.Request) *http.Response {
 	httpResp, err := postMgr.httpClient.Do(request)
 
@@ -181,3 +317,91 @@ func (ctlr *Controller) firstPollPrimaryClusterHealthStatus() {
 	ctlr.Agent.PrimaryClusterHealthProbeParams.statusRunning = ctlr.Agent.checkPrimaryClusterHealthStatus()
 	ctlr.Agent.PrimaryClusterHealthProbeParams.statusChanged = true
 }
+
+// probeClusterHealthStatus runs as a thread and enqueues the health probe of the clusters periodically
+// when CIS is running in ratio mode, probe is processed by the resource worker along with the other resources
+func (ctlr *Controller) probeClusterHealthStatus() {
+	for {
+		time.Sleep(DefaultClusterHealthProbeInterval * time.Second)
+		if ctlr.initState || ctlr.haModeType != Ratio {
+			continue
+		}
+		ctlr.enqueueClusterHealthProbeEvent()
+	}
+}
+
+// processClusterHealthStatus probes the health of the clusters, ratio of a cluster which is down is considered as 0
+// so that no traffic is distributed to it. Resources are re-processed whenever the health of any cluster changes
+func (ctlr *Controller) processClusterHealthStatus() {
+	if ctlr.haModeType != Ratio || ctlr.multiClusterConfigs == nil {
+		return
+	}
+	type clusterHealthStatus struct {
+		clusterName string
+		running     bool
+	}
+	statusCh := make(chan clusterHealthStatus, len(ctlr.multiClusterConfigs.ClusterConfigs))
+	for clusterName, config := range ctlr.multiClusterConfigs.ClusterConfigs {
+		go func(clusterName string, kubeClient kubernetes.Interface) {
+			statusCh <- clusterHealthStatus{clusterName: clusterName, running: checkClusterHealthStatus(kubeClient)}
+		}(clusterName, config.KubeClient)
+	}
+	downClusters := make(map[string]struct{})
+	for range ctlr.multiClusterConfigs.ClusterConfigs {
+		status := <-statusCh
+		if !status.running {
+			downClusters[status.clusterName] = struct{}{}
+		}
+	}
+
+	statusChanged := false
+	for clusterName := range downClusters {
+		if _, ok := ctlr.downClusters[clusterName]; !ok {
+			log.Warningf("[MultiCluster] Cluster %v is down, excluding it from the traffic distribution", clusterName)
+			statusChanged = true
+		}
+	}
+	for clusterName := range ctlr.downClusters {
+		if _, ok := downClusters[clusterName]; !ok {
+			log.Infof("[MultiCluster] Cluster %v is up, including it in the traffic distribution", clusterName)
+			statusChanged = true
+		}
+	}
+	ctlr.downClusters = downClusters
+	if !statusChanged {
+		return
+	}
+	// Re-process the resources to update the ratio of the clusters
+	if ctlr.mode == OpenShiftMode {
+		for routeGroupKey := range ctlr.resources.extdSpecMap {
+			if err := ctlr.processRoutes(routeGroupKey, false); err != nil {
+				log.Errorf("[MultiCluster] Failed to process RouteGroup: %v on cluster health update", routeGroupKey)
+			}
+		}
+	} else {
+		ctlr.enqueueProcessedVirtualAndTransportServers()
+	}
+}
+
+// checkClusterHealthStatus checks the health of the cluster by listing its nodes, which CIS is allowed to watch
+// in all the clusters
+func checkClusterHealthStatus(kubeClient kubernetes.Interface) bool {
+	if kubeClient == nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultClusterHealthProbeTimeout*time.Second)
+	defer cancel()
+	_, err := kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{Limit: 1})
+	return err == nil
+}
+
+// getClusterRatio returns the ratio of the cluster, ratio of a cluster which is down is 0
+func (ctlr *Controller) getClusterRatio(clusterName string) int {
+	if _, ok := ctlr.downClusters[clusterName]; ok {
+		return 0
+	}
+	if ratio, ok := ctlr.clusterRatio[clusterName]; ok && ratio != nil {
+		return *ratio
+	}
+	return 0
+}
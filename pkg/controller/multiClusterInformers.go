@@ -114,12 +114,12 @@ func (ctlr *Controller) newMultiClusterNamespacedPoolInformer(
 		clusterName: clusterName,
 		stopCh:      make(chan struct{}),
 		svcInformer: cache.NewSharedIndexInformer(
-			cache.NewFilteredListWatchFromClient(
+			newTransformListWatch(cache.NewFilteredListWatchFromClient(
 				restClientv1,
 				"services",
 				namespace,
 				everything,
-			),
+			)),
 			&corev1.Service{},
 			resyncPeriod,
 			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
@@ -128,12 +128,12 @@ func (ctlr *Controller) newMultiClusterNamespacedPoolInformer(
 	//enable pod informer for nodeport local mode and openshift mode
 	if ctlr.PoolMemberType == NodePortLocal {
 		comInf.podInformer = cache.NewSharedIndexInformer(
-			cache.NewFilteredListWatchFromClient(
+			newTransformListWatch(cache.NewFilteredListWatchFromClient(
 				restClientv1,
 				"pods",
 				namespace,
 				everything,
-			),
+			)),
 			&corev1.Pod{},
 			resyncPeriod,
 			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
@@ -142,12 +142,12 @@ func (ctlr *Controller) newMultiClusterNamespacedPoolInformer(
 	// enable endpoint informer in the cluster and nextGen routes mode only
 	if ctlr.PoolMemberType == Cluster {
 		comInf.epsInformer = cache.NewSharedIndexInformer(
-			cache.NewFilteredListWatchFromClient(
+			newTransformListWatch(cache.NewFilteredListWatchFromClient(
 				restClientv1,
 				"endpoints",
 				namespace,
 				everything,
-			),
+			)),
 			&corev1.Endpoints{},
 			resyncPeriod,
 			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
@@ -259,6 +259,17 @@ func (ctlr *Controller) setupAndStartHAClusterInformers(clusterName string) erro
 	return nil
 }
 
+// setupAndStartBackupClusterInformers sets up and starts informers for the backup cluster in all the namespaces
+// watched by CIS, as services of the backup cluster are looked up for every pool
+func (ctlr *Controller) setupAndStartBackupClusterInformers(mcc ExternalClusterConfig) {
+	if !mcc.Backup || ctlr.haModeType == Ratio {
+		return
+	}
+	if err := ctlr.setupAndStartHAClusterInformers(mcc.ClusterName); err != nil {
+		log.Warningf("[MultiCluster] unable to setup informers for backup cluster: %v, Error: %v", mcc.ClusterName, err)
+	}
+}
+
 // setupMultiClusterNodeInformers sets up and starts node informers for cluster if it hasn't been started
 func (ctlr *Controller) setupMultiClusterNodeInformers(clusterName string) error {
 	if _, ok := ctlr.multiClusterNodeInformers[clusterName]; !ok {
@@ -295,8 +306,9 @@ func (ctlr *Controller) checkSecondaryCISConfig() {
 func (ctlr *Controller) getNamespaceMultiClusterPoolInformer(
 	namespace string, clusterName string,
 ) (*MultiClusterPoolInformer, bool) {
-	// CIS may be watching all namespaces in case of HA clusters only
-	if clusterName == ctlr.multiClusterConfigs.HAPairClusterName && ctlr.watchingAllNamespaces() {
+	// CIS may be watching all namespaces in case of HA clusters and backup clusters only
+	if (clusterName == ctlr.multiClusterConfigs.HAPairClusterName || ctlr.isBackupCluster(clusterName)) &&
+		ctlr.watchingAllNamespaces() {
 		namespace = ""
 	}
 	nsPoolInf, ok := ctlr.multiClusterPoolInformers[clusterName]
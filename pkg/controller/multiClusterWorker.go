@@ -1,6 +1,8 @@
 package controller
 
 import (
+	"sort"
+
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
 )
@@ -113,11 +115,37 @@ func (ctlr *Controller) deleteUnrefereedMultiClusterInformers() {
 
 	for clusterName, svcs := range ctlr.multiClusterResources.clusterSvcMap {
 		// If no services are referenced from this cluster and this isn't HA peer cluster in case of active-active/ratio
-		// then remove the clusterName key from the clusterSvcMap and stop the informers for this cluster
+		// or a backup cluster then remove the clusterName key from the clusterSvcMap and stop the informers for this cluster
 		if len(svcs) == 0 && ((ctlr.haModeType == StandAloneCIS || ctlr.haModeType == StandBy) ||
-			ctlr.multiClusterConfigs.HAPairClusterName != clusterName) {
+			ctlr.multiClusterConfigs.HAPairClusterName != clusterName) && !ctlr.isBackupCluster(clusterName) {
 			delete(ctlr.multiClusterResources.clusterSvcMap, clusterName)
 			ctlr.stopMultiClusterInformers(clusterName)
 		}
 	}
 }
+
+// getBackupClusterNames returns the external clusters configured as backup clusters, services of the
+// backup clusters are used as the backup pool members in all the HA modes except the ratio mode
+func (ctlr *Controller) getBackupClusterNames() []string {
+	if ctlr.haModeType == Ratio || ctlr.multiClusterConfigs == nil {
+		return nil
+	}
+	var clusterNames []string
+	for clusterName, mcc := range ctlr.resources.externalClustersConfig {
+		if _, ok := ctlr.multiClusterConfigs.ClusterConfigs[clusterName]; ok && mcc.Backup {
+			clusterNames = append(clusterNames, clusterName)
+		}
+	}
+	sort.Strings(clusterNames)
+	return clusterNames
+}
+
+// isBackupCluster checks whether the cluster is configured as a backup cluster
+func (ctlr *Controller) isBackupCluster(clusterName string) bool {
+	for _, backupCluster := range ctlr.getBackupClusterNames() {
+		if backupCluster == clusterName {
+			return true
+		}
+	}
+	return false
+}
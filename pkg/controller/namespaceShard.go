@@ -0,0 +1,59 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// NamespaceShard is the shard of the namespaces managed by a CIS replica, namespaces are assigned to the Count
+// replicas by the hash of their names, so that the replicas manage the disjoint sets of namespaces
+type NamespaceShard struct {
+	Count int
+	// Index of the replica from 0 to Count-1
+	Index int
+}
+
+// enabled checks whether the namespaces are sharded across the replicas
+func (shard NamespaceShard) enabled() bool {
+	return shard.Count > 1
+}
+
+// hasNamespace checks whether the namespace is assigned to the shard, all the namespaces are assigned to the
+// replica without sharding
+func (shard NamespaceShard) hasNamespace(namespace string) bool {
+	if !shard.enabled() {
+		return true
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(namespace))
+	return int(hash.Sum32()%uint32(shard.Count)) == shard.Index
+}
+
+// GetShardIndex returns the index of the shard from the ordinal of the StatefulSet pod name, e.g. 2 for
+// k8s-bigip-ctlr-2
+func GetShardIndex(podName string) (int, error) {
+	ordinal := podName[strings.LastIndex(podName, "-")+1:]
+	index, err := strconv.Atoi(ordinal)
+	if err != nil || index < 0 {
+		return 0, fmt.Errorf("unable to get the shard index from the ordinal of pod name %v", podName)
+	}
+	return index, nil
+}
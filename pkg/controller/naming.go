@@ -0,0 +1,144 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"text/template"
+
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+)
+
+// maxObjectNameLength is the length limit of the AS3 object names, longer names are truncated with the hash
+// of the name to keep them unique
+const maxObjectNameLength = 189
+
+// NamingTemplates are the Go templates of the names of the virtual servers and pools generated by CIS.
+// Profiles, policies and other objects of the virtual are named after the virtual server
+type NamingTemplates struct {
+	// VirtualServer template with the fields Name, Address and Port
+	VirtualServer string
+	// Pool template with the fields Name, Namespace, Service, Port, Host and Cluster
+	Pool string
+}
+
+// virtualNameFields are the fields of the virtual server naming template
+type virtualNameFields struct {
+	// Name is the default name crd_<address>_<port>
+	Name    string
+	Address string
+	Port    int32
+}
+
+// poolNameFields are the fields of the pool naming template
+type poolNameFields struct {
+	// Name is the default name <service>_<port>_<namespace>_<cluster>_<host>_<nodeMemberLabel>
+	Name      string
+	Namespace string
+	Service   string
+	Port      string
+	Host      string
+	Cluster   string
+}
+
+var (
+	virtualNameTemplate *template.Template
+	poolNameTemplate    *template.Template
+)
+
+// Validate parses the naming templates and executes them with the sample fields
+func (nt NamingTemplates) Validate() error {
+	_, _, err := nt.parse()
+	return err
+}
+
+func (nt NamingTemplates) parse() (*template.Template, *template.Template, error) {
+	var virtual, pool *template.Template
+	var err error
+	if nt.VirtualServer != "" {
+		virtual, err = parseNamingTemplate("virtual", nt.VirtualServer,
+			virtualNameFields{Name: "crd_10_1_1_1_80", Address: "10.1.1.1", Port: 80})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if nt.Pool != "" {
+		pool, err = parseNamingTemplate("pool", nt.Pool, poolNameFields{Name: "svc_80_default_foo_com",
+			Namespace: "default", Service: "svc", Port: "80", Host: "foo.com", Cluster: "cluster1"})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return virtual, pool, nil
+}
+
+func parseNamingTemplate(name, text string, sample interface{}) (*template.Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, sample); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(buf.String()) == "" {
+		return nil, fmt.Errorf("%v name template generates an empty name", name)
+	}
+	return tmpl, nil
+}
+
+// setNamingTemplates sets the templates used to name the virtual servers and pools, default names are used
+// when the templates are not valid
+func setNamingTemplates(nt NamingTemplates) {
+	var err error
+	virtualNameTemplate, poolNameTemplate, err = nt.parse()
+	if err != nil {
+		log.Errorf("Invalid naming template, using the default names: %v", err)
+	}
+}
+
+// executeNamingTemplate returns the name generated with the template, default name is returned without the
+// template or when it fails to generate the name
+func executeNamingTemplate(tmpl *template.Template, fields interface{}, defaultName string) string {
+	if tmpl == nil {
+		return defaultName
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, fields); err != nil {
+		log.Errorf("Unable to generate the %v name with the template, using %v: %v", tmpl.Name(), defaultName, err)
+		return defaultName
+	}
+	name := AS3NameFormatter(strings.TrimSpace(buf.String()))
+	if name == "" {
+		return defaultName
+	}
+	return name
+}
+
+// shortenObjectName truncates the names longer than the AS3 limit with the hash of the full name, so that the
+// name is stable across the restarts and unique
+func shortenObjectName(name string) string {
+	if len(name) <= maxObjectNameLength {
+		return name
+	}
+	hash := sha256.Sum256([]byte(name))
+	return strings.TrimRight(name[:maxObjectNameLength-9], "_") + "_" + hex.EncodeToString(hash[:])[:8]
+}
@@ -225,6 +225,10 @@ func (ctlr *Controller) getGroupedRoutes(routeGroup string,
 	// Get the route group
 	for _, namespace := range ctlr.resources.extdSpecMap[routeGroup].namespaces {
 		orderedRoutes := ctlr.getOrderedRoutes(namespace)
+		for i, route := range orderedRoutes {
+			rscRef := resourceRef{kind: Route, namespace: route.Namespace, name: route.Name}
+			orderedRoutes[i] = ctlr.getPausedResource(rscRef, route.Annotations, route).(*routeapi.Route)
+		}
 		ctlr.TeemData.Lock()
 		ctlr.TeemData.ResourceType.NativeRoutes[namespace] = len(orderedRoutes)
 		ctlr.TeemData.Unlock()
@@ -265,6 +269,7 @@ func (ctlr *Controller) handleRouteGroupExtendedSpec(rsCfg *ResourceConfig, plc
 		if err != nil {
 			return err
 		}
+		policy = ctlr.mergeDefaultPolicy(policy)
 	} else {
 		policy = plc
 	}
@@ -273,18 +278,27 @@ func (ctlr *Controller) handleRouteGroupExtendedSpec(rsCfg *ResourceConfig, plc
 		if err != nil {
 			return err
 		}
+	}
 
-		// If allowOverride is true and routes use WAF annotation then WAF specified in policy CR is deprioritized
-		if allowOverride, err := strconv.ParseBool(extdSpec.AllowOverride); err == nil && allowOverride && au.WAF {
-			rsCfg.Virtual.WAF = ""
-		}
+	// WAF and allowVlans of the route group take precedence over the policy CR
+	if extdSpec.WAF != "" {
+		rsCfg.Virtual.WAF = extdSpec.WAF
+	}
+	if len(extdSpec.AllowVlans) > 0 {
+		rsCfg.Virtual.AllowVLANs = extdSpec.AllowVlans
+	}
 
-		// If allowOverride is true and routes use allow-source-range annotation then allow-source-range specified
-		// in policy CR is deprioritized
-		if allowOverride, err := strconv.ParseBool(extdSpec.AllowOverride); err == nil && allowOverride &&
-			au.AllowSourceRange {
-			rsCfg.Virtual.AllowSourceRange = nil
-		}
+	// If allowOverride is true and routes use WAF annotation then WAF specified in policy CR or
+	// route group is deprioritized
+	if allowOverride, err := strconv.ParseBool(extdSpec.AllowOverride); err == nil && allowOverride && au.WAF {
+		rsCfg.Virtual.WAF = ""
+	}
+
+	// If allowOverride is true and routes use allow-source-range annotation then allow-source-range specified
+	// in policy CR is deprioritized
+	if allowOverride, err := strconv.ParseBool(extdSpec.AllowOverride); err == nil && allowOverride &&
+		au.AllowSourceRange {
+		rsCfg.Virtual.AllowSourceRange = nil
 	}
 	return nil
 }
@@ -296,9 +310,13 @@ func (ctlr *Controller) getRouteGroupPolicy(extdSpec *ExtendedRouteGroupSpec) (*
 		if len(splits) != 2 {
 			return nil, fmt.Errorf("Policy %v not in the format <namespace>/<policy-name>", policy)
 		}
-		return ctlr.getPolicy(splits[0], splits[1])
+		plc, err := ctlr.getPolicy(splits[0], splits[1])
+		if err != nil {
+			return nil, err
+		}
+		return ctlr.mergeDefaultPolicy(plc), nil
 	}
-	return nil, nil
+	return ctlr.mergeDefaultPolicy(nil), nil
 }
 
 // gets the target port for the route
@@ -448,6 +466,11 @@ func (ctlr *Controller) prepareResourceConfigFromRoute(
 					ctlr.updateMultiClusterResourceServiceMap(rsCfg, rsRef, bs.Name, route.Spec.Path, pool, servicePort,
 						ctlr.multiClusterConfigs.HAPairClusterName)
 				}
+				// update the multicluster resource serviceMap with backup cluster services
+				for _, clusterName := range ctlr.getBackupClusterNames() {
+					ctlr.updateMultiClusterResourceServiceMap(rsCfg, rsRef, bs.Name, route.Spec.Path, pool, servicePort,
+						clusterName)
+				}
 			} else {
 				// Update the multiCluster resource service map for each pool which constitutes a service in case of ratio mode
 				ctlr.updateMultiClusterResourceServiceMap(rsCfg, rsRef, bs.Name, route.Spec.Path, pool, servicePort, bs.Cluster)
@@ -561,6 +584,20 @@ func (ctlr *Controller) prepareResourceConfigFromRoute(
 		}
 		policyName := formatPolicyName(route.Spec.Host, route.Namespace, rsCfg.Virtual.Name)
 		rsCfg.AddRuleToPolicy(policyName, rsCfg.Virtual.Partition, rules)
+		ctlr.handleRouteTrafficAnnotations(rsCfg, route)
+
+		// Handle AB datagroup for the insecure traffic served on HTTP virtual
+		if portStruct.protocol == HTTP && (isRouteABDeployment(route) || ctlr.haModeType == Ratio) &&
+			(route.Spec.TLS == nil || route.Spec.TLS.InsecureEdgeTerminationPolicy == routeapi.InsecureEdgeTerminationPolicyAllow) {
+			ctlr.updateDataGroupForABRoute(route,
+				getRSCfgResName(rsCfg.Virtual.Name, AbDeploymentDgName),
+				rsCfg.Virtual.Partition,
+				route.Namespace,
+				rsCfg.IntDgMap,
+				servicePort,
+			)
+			ctlr.addABPathIRule(rsCfg)
+		}
 	}
 	return nil
 }
@@ -657,8 +694,12 @@ func (ctlr *Controller) prepareRouteLTMRules(
 		return nil
 	}
 
-	// Handle url-rewrite annotation
-	if rewritePath, ok := route.Annotations[resource.F5VsURLRewriteAnnotation]; ok {
+	// Handle url-rewrite annotation, rewrite-target annotation of the OpenShift router is used otherwise
+	rewritePath, ok := route.Annotations[resource.F5VsURLRewriteAnnotation]
+	if !ok {
+		rewritePath, ok = route.Annotations[RouteRewriteTargetAnnotation]
+	}
+	if ok {
 		rewriteActions, err := getRewriteActions(
 			path,
 			rewritePath,
@@ -920,7 +961,7 @@ func (ctlr *Controller) setNamespaceLabelMode(cm *v1.ConfigMap) error {
 	}
 	namespace, namespaceLabel := false, false
 	//Either defaultRouteGroup or ExtendedRouteGroupConfigs are allowed
-	if es.BaseRouteConfig.DefaultRouteGroupConfig != (DefaultRouteGroupConfig{}) && len(es.ExtendedRouteGroupConfigs) > 0 {
+	if !reflect.DeepEqual(es.BaseRouteConfig.DefaultRouteGroupConfig, DefaultRouteGroupConfig{}) && len(es.ExtendedRouteGroupConfigs) > 0 {
 		return fmt.Errorf("can not specify both defaultRouteGroup and ExtendedRouteGroupConfigs in extended configmap %v/%v", cm.Namespace, cm.Name)
 	}
 	for rg := range es.ExtendedRouteGroupConfigs {
@@ -988,7 +1029,7 @@ func (ctlr *Controller) processRouteConfigFromGlobalCM(es extendedSpec, isDelete
 		partition = ctlr.Partition
 	}
 
-	if es.BaseRouteConfig.DefaultRouteGroupConfig != (DefaultRouteGroupConfig{}) {
+	if !reflect.DeepEqual(es.BaseRouteConfig.DefaultRouteGroupConfig, DefaultRouteGroupConfig{}) {
 		newExtdSpecMap[defaultRouteGroupName] = &extendedParsedSpec{
 			override:   false,
 			local:      nil,
@@ -1248,7 +1289,7 @@ func (ctlr *Controller) readBaseRouteConfigFromGlobalCM(baseRouteConfig BaseRout
 	}
 	ctlr.resources.baseRouteConfig.DefaultTLS = DefaultSSLProfile{}
 	ctlr.resources.baseRouteConfig.DefaultRouteGroupConfig = DefaultRouteGroupConfig{}
-	if (baseRouteConfig != BaseRouteConfig{}) {
+	if !reflect.DeepEqual(baseRouteConfig, BaseRouteConfig{}) {
 		if baseRouteConfig.TLSCipher.TLSVersion != "" {
 			ctlr.resources.baseRouteConfig.TLSCipher.TLSVersion = baseRouteConfig.TLSCipher.TLSVersion
 		}
@@ -1265,10 +1306,12 @@ func (ctlr *Controller) readBaseRouteConfigFromGlobalCM(baseRouteConfig BaseRout
 		ctlr.resources.baseRouteConfig.DefaultTLS.ServerSSL = baseRouteConfig.DefaultTLS.ServerSSL
 		ctlr.resources.baseRouteConfig.DefaultTLS.Reference = baseRouteConfig.DefaultTLS.Reference
 	}
-	if baseRouteConfig.DefaultRouteGroupConfig != (DefaultRouteGroupConfig{}) {
+	if !reflect.DeepEqual(baseRouteConfig.DefaultRouteGroupConfig, DefaultRouteGroupConfig{}) {
 		ctlr.resources.baseRouteConfig.DefaultRouteGroupConfig.DefaultRouteGroupSpec.VServerName = baseRouteConfig.DefaultRouteGroupConfig.DefaultRouteGroupSpec.VServerName
 		ctlr.resources.baseRouteConfig.DefaultRouteGroupConfig.DefaultRouteGroupSpec.VServerAddr = baseRouteConfig.DefaultRouteGroupConfig.DefaultRouteGroupSpec.VServerAddr
 		ctlr.resources.baseRouteConfig.DefaultRouteGroupConfig.DefaultRouteGroupSpec.Policy = baseRouteConfig.DefaultRouteGroupConfig.DefaultRouteGroupSpec.Policy
+		ctlr.resources.baseRouteConfig.DefaultRouteGroupConfig.DefaultRouteGroupSpec.WAF = baseRouteConfig.DefaultRouteGroupConfig.DefaultRouteGroupSpec.WAF
+		ctlr.resources.baseRouteConfig.DefaultRouteGroupConfig.DefaultRouteGroupSpec.AllowVlans = baseRouteConfig.DefaultRouteGroupConfig.DefaultRouteGroupSpec.AllowVlans
 		ctlr.resources.baseRouteConfig.DefaultRouteGroupConfig.BigIpPartition = baseRouteConfig.DefaultRouteGroupConfig.BigIpPartition
 	}
 }
@@ -1411,6 +1454,10 @@ func (ctlr *Controller) getOrderedRoutes(namespace string) []*routeapi.Route {
 
 	for _, obj := range resources {
 		rt := obj.(*routeapi.Route)
+		// Skip the Routes of other resource classes
+		if !ctlr.isManagedResourceClass(rt.Annotations) {
+			continue
+		}
 		allRoutes = append(allRoutes, rt)
 	}
 	sort.Slice(allRoutes, func(i, j int) bool {
@@ -1506,7 +1553,73 @@ func frameRouteVSName(vServerName string,
 	return rsName
 }
 
-// update route admit status
+// handleRouteTrafficAnnotations updates the route traffic data group with the timeout and the rate limit
+// of the OpenShift router annotations of the route and attaches the iRule enforcing them
+func (ctlr *Controller) handleRouteTrafficAnnotations(rsCfg *ResourceConfig, route *routeapi.Route) {
+	timeout, rateLimit, err := getRouteTrafficLimits(route)
+	if err != nil || (timeout == 0 && rateLimit == 0) {
+		return
+	}
+	routePath := strings.TrimSuffix(strings.ToLower(route.Spec.Host)+route.Spec.Path, "/")
+	updateDataGroup(rsCfg.IntDgMap, getRSCfgResName(rsCfg.Virtual.Name, RouteTrafficDgName),
+		rsCfg.Virtual.Partition, route.Namespace, routePath, fmt.Sprintf("%d,%d", timeout, rateLimit), DataGroupType)
+
+	iRuleName := getRSCfgResName(rsCfg.Virtual.Name, RouteTrafficIRuleName)
+	rsCfg.addIRule(iRuleName, rsCfg.Virtual.Partition, ctlr.GetRouteTrafficIRule(rsCfg.Virtual.Name, rsCfg.Virtual.Partition))
+	rsCfg.Virtual.AddIRule(JoinBigipPath(rsCfg.Virtual.Partition, iRuleName))
+}
+
+// getRouteTrafficLimits returns the server timeout in seconds and the HTTP requests allowed per client
+// in 3 seconds from the OpenShift router annotations of the route, 0 if the annotation is not set
+func getRouteTrafficLimits(route *routeapi.Route) (int, int, error) {
+	var timeout, rateLimit int
+	if value, ok := route.Annotations[RouteTimeoutAnnotation]; ok {
+		duration, err := parseRouteTimeout(value)
+		if err != nil {
+			return 0, 0, fmt.Errorf("annotation %v has invalid timeout %v", RouteTimeoutAnnotation, value)
+		}
+		timeout = int((duration + time.Second - 1) / time.Second)
+	}
+	if enabled, _ := strconv.ParseBool(route.Annotations[RouteRateLimitAnnotation]); enabled {
+		if value, ok := route.Annotations[RouteRateLimitHTTPAnnotation]; ok {
+			rate, err := strconv.Atoi(value)
+			if err != nil || rate <= 0 {
+				return 0, 0, fmt.Errorf("annotation %v has invalid rate %v", RouteRateLimitHTTPAnnotation, value)
+			}
+			rateLimit = rate
+		}
+	}
+	return timeout, rateLimit, nil
+}
+
+// parseRouteTimeout parses the timeout in the format of the OpenShift router, the timeout without
+// unit is in milliseconds and the units us, ms, s, m, h and d are supported
+func parseRouteTimeout(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, fmt.Errorf("empty timeout")
+	}
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil {
+			return 0, err
+		}
+		value = fmt.Sprintf("%dh", days*24)
+	} else if _, err := strconv.Atoi(value); err == nil {
+		value += "ms"
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("timeout should be greater than zero")
+	}
+	return duration, nil
+}
+
+// update route admit status, the route ingress entry of CIS is replaced so that the host, reason and
+// message reflect the latest processing of the route while the entries of the other routers are retained
 func (ctlr *Controller) updateRouteAdmitStatus(
 	rscKey string,
 	reason string,
@@ -1515,7 +1628,7 @@ func (ctlr *Controller) updateRouteAdmitStatus(
 ) {
 	defer func() {
 		if r := recover(); r != nil {
-			log.Errorf("CIS recovered from the panic caused by route status update: %v\n")
+			log.Errorf("CIS recovered from the panic caused by route status update: %v\n", r)
 		}
 	}()
 	for retryCount := 0; retryCount < 3; retryCount++ {
@@ -1523,32 +1636,41 @@ func (ctlr *Controller) updateRouteAdmitStatus(
 		if route == nil {
 			return
 		}
-		Admitted := false
 		now := metaV1.Now().Rfc3339Copy()
+		transitionTime := &now
+		upToDate := false
+		var ingresses []routeapi.RouteIngress
 		for _, routeIngress := range route.Status.Ingress {
-			if routeIngress.RouterName == F5RouterName {
-				for _, condition := range routeIngress.Conditions {
-					if condition.Status == status {
-						Admitted = true
-					} else {
-						// remove all multiple route admit status submitted earlier
-						ctlr.eraseRouteAdmitStatus(rscKey)
-					}
+			if routeIngress.RouterName != F5RouterName {
+				ingresses = append(ingresses, routeIngress)
+				continue
+			}
+			for _, condition := range routeIngress.Conditions {
+				if condition.Type != routeapi.RouteAdmitted || condition.Status != status {
+					continue
+				}
+				upToDate = routeIngress.Host == route.Spec.Host && condition.Reason == reason &&
+					condition.Message == message && len(routeIngress.Conditions) == 1
+				// retain the transition time as the admit status is not changed
+				if condition.LastTransitionTime != nil {
+					transitionTime = condition.LastTransitionTime
 				}
 			}
 		}
-		if Admitted {
+		// route is already admitted with the same status by a single entry of CIS
+		if upToDate && len(ingresses) == len(route.Status.Ingress)-1 {
 			return
 		}
-		route.Status.Ingress = append(route.Status.Ingress, routeapi.RouteIngress{
-			RouterName: F5RouterName,
-			Host:       route.Spec.Host,
+		route.Status.Ingress = append(ingresses, routeapi.RouteIngress{
+			RouterName:     F5RouterName,
+			Host:           route.Spec.Host,
+			WildcardPolicy: route.Spec.WildcardPolicy,
 			Conditions: []routeapi.RouteIngressCondition{{
 				Type:               routeapi.RouteAdmitted,
 				Status:             status,
 				Reason:             reason,
 				Message:            message,
-				LastTransitionTime: &now,
+				LastTransitionTime: transitionTime,
 			}},
 		})
 		_, err := ctlr.routeClientV1.Routes(route.ObjectMeta.Namespace).UpdateStatus(context.TODO(), route, metaV1.UpdateOptions{})
@@ -1755,6 +1877,19 @@ func (ctlr *Controller) checkValidRoute(route *routeapi.Route, plcSSLProfiles rg
 			return false
 		}
 	}
+	// Validate the annotations of the OpenShift router
+	if rewriteTarget, ok := route.Annotations[RouteRewriteTargetAnnotation]; ok && rewriteTarget == "" {
+		message := fmt.Sprintf("Discarding route %v as annotation %v is empty", route.Name, RouteRewriteTargetAnnotation)
+		log.Errorf(message)
+		go ctlr.updateRouteAdmitStatus(fmt.Sprintf("%v/%v", route.Namespace, route.Name), "InvalidAnnotation", message, v1.ConditionFalse)
+		return false
+	}
+	if _, _, err := getRouteTrafficLimits(route); err != nil {
+		message := fmt.Sprintf("Discarding route %v as %v", route.Name, err)
+		log.Errorf(message)
+		go ctlr.updateRouteAdmitStatus(fmt.Sprintf("%v/%v", route.Namespace, route.Name), "InvalidAnnotation", message, v1.ConditionFalse)
+		return false
+	}
 	// Validate multiCluster service annotation has valid cluster names
 	if ctlr.multiClusterMode != "" {
 		if annotation := route.Annotations[resource.MultiClusterServicesAnnotation]; annotation != "" {
@@ -1863,6 +1998,11 @@ func (ctlr *Controller) getNamespacesForRouteGroup(namespaceGroup string) []stri
 func (ctlr *Controller) getRouteGroupForCustomPolicy(policy string) []string {
 	var routeGroups []string
 	for rg, extdSpec := range ctlr.resources.extdSpecMap {
+		// default policy is merged into the policy of all the route groups
+		if ctlr.defaultPolicy != "" && ctlr.defaultPolicy == policy {
+			routeGroups = append(routeGroups, rg)
+			continue
+		}
 		if extdSpec.override {
 			// continue if extended spec is not set
 			if extdSpec.local == nil {
@@ -2005,6 +2145,8 @@ func (ctlr *Controller) readMultiClusterConfigFromGlobalCM(haClusterConfig HAClu
 			}
 			ctlr.multiClusterConfigs.HAPairClusterName = haClusterConfig.SecondaryCluster.ClusterName
 			ctlr.multiClusterConfigs.LocalClusterName = primaryClusterName
+			// Renew the lease probed by the secondary CIS in case of lease primaryEndPoint
+			ctlr.updatePrimaryClusterLease(haClusterConfig.PrimaryClusterEndPoint, primaryClusterName)
 		}
 		if ctlr.multiClusterMode == SecondaryCIS && haClusterConfig.PrimaryCluster != (ClusterDetails{}) {
 			// Both cluster name and secret are mandatory
@@ -2038,6 +2180,11 @@ func (ctlr *Controller) readMultiClusterConfigFromGlobalCM(haClusterConfig HAClu
 			}
 			ctlr.multiClusterConfigs.HAPairClusterName = haClusterConfig.PrimaryCluster.ClusterName
 			ctlr.multiClusterConfigs.LocalClusterName = secondaryClusterName
+			// Lease primaryEndPoint is probed in the primary cluster
+			ctlr.Agent.PrimaryClusterHealthProbeParams.paramLock.Lock()
+			ctlr.Agent.PrimaryClusterHealthProbeParams.leaseClient =
+				ctlr.multiClusterConfigs.ClusterConfigs[haClusterConfig.PrimaryCluster.ClusterName].KubeClient
+			ctlr.Agent.PrimaryClusterHealthProbeParams.paramLock.Unlock()
 		}
 	}
 
@@ -2098,10 +2245,8 @@ func (ctlr *Controller) readMultiClusterConfigFromGlobalCM(haClusterConfig HAClu
 			continue
 		}
 
-		// Update the new valid cluster config to the externalClustersConfig cache if not already present
-		if _, ok := ctlr.resources.externalClustersConfig[mcc.ClusterName]; !ok {
-			ctlr.resources.externalClustersConfig[mcc.ClusterName] = mcc
-		}
+		// Update the new valid cluster config to the externalClustersConfig cache
+		ctlr.resources.externalClustersConfig[mcc.ClusterName] = mcc
 
 		// If cluster config has been processed already and kubeclient has been created then skip it
 		if _, ok := ctlr.multiClusterConfigs.ClusterConfigs[mcc.ClusterName]; ok {
@@ -2117,6 +2262,7 @@ func (ctlr *Controller) readMultiClusterConfigFromGlobalCM(haClusterConfig HAClu
 					ctlr.clusterRatio[mcc.ClusterName] = &one
 				}
 			}
+			ctlr.setupAndStartBackupClusterInformers(mcc)
 			continue
 		}
 
@@ -2135,6 +2281,7 @@ func (ctlr *Controller) readMultiClusterConfigFromGlobalCM(haClusterConfig HAClu
 				ctlr.clusterRatio[mcc.ClusterName] = &one
 			}
 		}
+		ctlr.setupAndStartBackupClusterInformers(mcc)
 	}
 	// Check if a cluster config has been removed then remove the data associated with it from the externalClustersConfig store
 	for clusterName, _ := range ctlr.resources.externalClustersConfig {
@@ -12,6 +12,7 @@ import (
 	. "github.com/onsi/gomega"
 	routeapi "github.com/openshift/api/route/v1"
 	fakeRouteClient "github.com/openshift/client-go/route/clientset/versioned/fake"
+	"gopkg.in/yaml.v2"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -146,6 +147,88 @@ var _ = Describe("Routes", func() {
 			Expect(dg[ns].Records[0].Data).To(BeEquivalentTo("foo_80_default"), "Invalid vsHostname in datagroup")
 		})
 
+		It("Route with OpenShift router annotations", func() {
+			mockCtlr.resources.extdSpecMap[ns] = &extendedParsedSpec{
+				override: false,
+				global: &ExtendedRouteGroupSpec{
+					VServerName:   "samplevs",
+					VServerAddr:   "10.10.10.10",
+					AllowOverride: "False",
+				},
+				namespaces: []string{ns},
+				partition:  "test",
+			}
+			spec1 := routeapi.RouteSpec{
+				Host: "foo.com",
+				Path: "/foo",
+				To: routeapi.RouteTargetReference{
+					Kind: "Service",
+					Name: "foo",
+				},
+			}
+			route1 := test.NewRoute("route1", "1", ns, spec1, nil)
+			route1.Annotations = map[string]string{
+				RouteRewriteTargetAnnotation: "/bar",
+				RouteTimeoutAnnotation:       "1500ms",
+				RouteRateLimitAnnotation:     "true",
+				RouteRateLimitHTTPAnnotation: "20",
+			}
+			mockCtlr.addRoute(route1)
+			fooPorts := []v1.ServicePort{{Port: 80, NodePort: 30001}}
+			foo := test.NewService("foo", "1", ns, "NodePort", fooPorts)
+			mockCtlr.addService(foo)
+			fooEndpts := test.NewEndpoints(
+				"foo", "1", "node0", ns, []string{"10.1.1.1"}, []string{},
+				convertSvcPortsToEndpointPorts(fooPorts))
+			mockCtlr.addEndpoints(fooEndpts)
+			mockCtlr.resources.invertedNamespaceLabelMap[ns] = ns
+
+			err := mockCtlr.processRoutes(ns, false)
+			Expect(err).To(BeNil(), "Failed to process routes")
+			rsCfg := mockCtlr.resources.ltmConfig["test"].ResourceMap["samplevs_80"]
+			Expect(rsCfg).NotTo(BeNil(), "HTTP virtual server should be created")
+			Expect(rsCfg.Virtual.IRules).To(ContainElement("/test/samplevs_80_"+RouteTrafficIRuleName),
+				"Route traffic iRule should be attached")
+			dg, ok := rsCfg.IntDgMap[NameRef{Name: "samplevs_80_" + RouteTrafficDgName, Partition: "test"}]
+			Expect(ok).To(BeTrue(), "Route traffic data group should be created")
+			Expect(dg[ns].Records).To(Equal(InternalDataGroupRecords{{Name: "foo.com/foo", Data: "2,20"}}),
+				"Incorrect route traffic data group")
+			var rewriteFound bool
+			for _, pl := range rsCfg.Policies {
+				for _, rl := range pl.Rules {
+					for _, act := range rl.Actions {
+						if act.HTTPURI && act.Replace && act.Path == "/foo" {
+							rewriteFound = true
+							Expect(act.Value).To(ContainSubstring("/bar"), "Incorrect rewrite target")
+						}
+					}
+				}
+			}
+			Expect(rewriteFound).To(BeTrue(), "Rewrite action should be created for the rewrite target")
+
+			// Invalid timeout discards the route
+			route1.Annotations[RouteTimeoutAnnotation] = "5x"
+			Expect(mockCtlr.checkValidRoute(route1, rgPlcSSLProfiles{})).To(BeFalse(), "Route with invalid timeout should be discarded")
+		})
+
+		It("Parse OpenShift router timeout", func() {
+			for value, expected := range map[string]time.Duration{
+				"500":  500 * time.Millisecond,
+				"30s":  30 * time.Second,
+				"5m":   5 * time.Minute,
+				"1d":   24 * time.Hour,
+				"10us": 10 * time.Microsecond,
+			} {
+				duration, err := parseRouteTimeout(value)
+				Expect(err).To(BeNil(), "Failed to parse timeout %v", value)
+				Expect(duration).To(Equal(expected), "Incorrect timeout %v", value)
+			}
+			for _, value := range []string{"", "-5s", "0", "abc", "xd"} {
+				_, err := parseRouteTimeout(value)
+				Expect(err).NotTo(BeNil(), "Timeout %v should be invalid", value)
+			}
+		})
+
 		It("Route Admit Status", func() {
 			spec1 := routeapi.RouteSpec{
 				Host: "foo.com",
@@ -172,6 +255,56 @@ var _ = Describe("Routes", func() {
 			Expect(mockCtlr.fetchRoute(fmt.Sprintf("%v-invalid", rskey))).To(BeNil(), "We should not be able to fetch the route")
 
 		})
+		It("Route Admit Status with other routers", func() {
+			spec1 := routeapi.RouteSpec{
+				Host: "bar.com",
+				Path: "/foo",
+				To: routeapi.RouteTargetReference{
+					Kind: "Service",
+					Name: "foo",
+				},
+			}
+			route1 := test.NewRoute("route1", "1", "default", spec1, nil)
+			admitTime := metav1.NewTime(time.Now().Add(-time.Hour)).Rfc3339Copy()
+			route1.Status.Ingress = []routeapi.RouteIngress{
+				{
+					RouterName: "default",
+					Host:       "bar.com",
+					Conditions: []routeapi.RouteIngressCondition{{
+						Type:   routeapi.RouteAdmitted,
+						Status: v1.ConditionTrue,
+					}},
+				},
+				{
+					RouterName: F5RouterName,
+					Host:       "foo.com",
+					Conditions: []routeapi.RouteIngressCondition{{
+						Type:               routeapi.RouteAdmitted,
+						Status:             v1.ConditionTrue,
+						LastTransitionTime: &admitTime,
+					}},
+				},
+			}
+			mockCtlr.addRoute(route1)
+			rskey := fmt.Sprintf("%v/%v", route1.Namespace, route1.Name)
+			// Host of the route is updated
+			mockCtlr.updateRouteAdmitStatus(rskey, "", "", v1.ConditionTrue)
+			route := mockCtlr.fetchRoute(rskey)
+			Expect(len(route.Status.Ingress)).To(BeEquivalentTo(2), "Incorrect route ingress entries")
+			Expect(route.Status.Ingress[0].RouterName).To(BeEquivalentTo("default"), "Route ingress of other router should be retained")
+			Expect(route.Status.Ingress[1].RouterName).To(BeEquivalentTo(F5RouterName), "Incorrect router name")
+			Expect(route.Status.Ingress[1].Host).To(BeEquivalentTo("bar.com"), "Incorrect route admit host")
+			Expect(route.Status.Ingress[1].Conditions[0].LastTransitionTime.Time).To(BeTemporally("==", admitTime.Time),
+				"Transition time should be retained")
+			// Route admission fails
+			mockCtlr.updateRouteAdmitStatus(rskey, "ExtendedValidationFailed", "Testing", v1.ConditionFalse)
+			route = mockCtlr.fetchRoute(rskey)
+			Expect(len(route.Status.Ingress)).To(BeEquivalentTo(2), "Incorrect route ingress entries")
+			Expect(route.Status.Ingress[1].Conditions[0].Status).To(BeEquivalentTo(v1.ConditionFalse), "Incorrect route admit status")
+			Expect(route.Status.Ingress[1].Conditions[0].Message).To(BeEquivalentTo("Testing"), "Incorrect route admit message")
+			Expect(route.Status.Ingress[1].Conditions[0].LastTransitionTime.Time).To(BeTemporally(">", admitTime.Time),
+				"Transition time should be updated")
+		})
 		It("Erase All Route Admit Status", func() {
 			spec1 := routeapi.RouteSpec{
 				Host: "foo.com",
@@ -878,6 +1011,99 @@ extendedRouteSpec:
 
 		})
 
+		It("Check Insecure Route A/B Deploy", func() {
+			routeGroup := "default"
+			weight := int32(20)
+			primaryWeight := int32(80)
+			spec := routeapi.RouteSpec{
+				Host: "foo.com",
+				Path: "/foo",
+				To: routeapi.RouteTargetReference{
+					Kind:   "Service",
+					Name:   "foo",
+					Weight: &primaryWeight,
+				},
+				AlternateBackends: []routeapi.RouteTargetReference{
+					{Kind: "Service", Name: "bar", Weight: &weight},
+				},
+			}
+			route := test.NewRoute("route1", "1", routeGroup, spec, nil)
+
+			rsCfg := &ResourceConfig{}
+			rsCfg.Virtual.Partition = routeGroup
+			rsCfg.MetaData.ResourceType = VirtualServer
+			rsCfg.Virtual.Enabled = true
+			rsCfg.Virtual.Name = "newroutes_80"
+			rsCfg.MetaData.Protocol = HTTP
+			rsCfg.Virtual.SetVirtualAddress("10.8.3.11", DEFAULT_HTTP_PORT)
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			rsCfg.IRulesMap = make(IRulesMap)
+			ps := portStruct{HTTP, DEFAULT_HTTP_PORT}
+			Expect(mockCtlr.prepareResourceConfigFromRoute(rsCfg, route, intstr.IntOrString{IntVal: 80}, ps)).To(BeNil())
+
+			dgName := NameRef{Name: getRSCfgResName(rsCfg.Virtual.Name, AbDeploymentDgName), Partition: routeGroup}
+			Expect(rsCfg.IntDgMap).To(HaveKey(dgName), "AB datagroup not created for insecure route")
+			records := rsCfg.IntDgMap[dgName][routeGroup].Records
+			Expect(len(records)).To(Equal(1))
+			Expect(records[0].Name).To(Equal("foo.com/foo"))
+			Expect(strings.Split(records[0].Data, ";")).To(HaveLen(2), "Alternate backend not weighted")
+			abPathIRule := getRSCfgResName(rsCfg.Virtual.Name, ABPathIRuleName)
+			Expect(rsCfg.IRulesMap).To(HaveKey(NameRef{abPathIRule, routeGroup}), "AB iRule not created for insecure route")
+			Expect(rsCfg.Virtual.IRules).To(ContainElement(JoinBigipPath(routeGroup, abPathIRule)))
+		})
+
+		It("Check Reencrypt Route destination CA verification", func() {
+			rsCfg := &ResourceConfig{}
+			rsCfg.Virtual.Partition = "test"
+			rsCfg.MetaData.ResourceType = VirtualServer
+			rsCfg.Virtual.Enabled = true
+			rsCfg.Virtual.Name = "newroutes_443"
+			rsCfg.Virtual.SetVirtualAddress("10.8.3.11", DEFAULT_HTTPS_PORT)
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			rsCfg.IRulesMap = make(IRulesMap)
+			rsCfg.customProfiles = make(map[SecretKey]CustomProfile)
+			tlsContext := TLSContext{
+				name:          "route1",
+				namespace:     "default",
+				resourceType:  Route,
+				referenceType: Certificate,
+				vsHostname:    "foo.com",
+				httpsPort:     DEFAULT_HTTPS_PORT,
+				httpPort:      DEFAULT_HTTP_PORT,
+				ipAddress:     "10.8.3.11",
+				termination:   TLSReencrypt,
+				httpTraffic:   TLSRedirectInsecure,
+				poolPathRefs: []poolPathRef{
+					{path: "/foo", poolName: "foo_80_default", aliasHostnames: []string{"foo.com"}},
+				},
+				bigIPSSLProfiles: BigIPSSLProfiles{
+					certificate:              "cert",
+					key:                      "key",
+					destinationCACertificate: "destca",
+				},
+			}
+			Expect(mockCtlr.handleTLS(rsCfg, tlsContext)).To(BeTrue(), "Failed to handle TLS of the route")
+			serverProf, ok := rsCfg.customProfiles[SecretKey{Name: "route1-serverssl", ResourceName: rsCfg.GetName()}]
+			Expect(ok).To(BeTrue(), "Server SSL profile not created")
+			Expect(serverProf.ValidateCertificate).To(BeTrue(), "Server certificate should be verified")
+
+			tlsClientName := getRouteTLSClientName(rsCfg.Virtual.Name, "route1-serverssl")
+			dg := rsCfg.IntDgMap[NameRef{Name: getRSCfgResName(rsCfg.Virtual.Name, ReencryptServerSslDgName), Partition: "test"}]
+			Expect(dg["default"].Records).To(Equal(InternalDataGroupRecords{{Name: "foo.com/foo", Data: tlsClientName}}),
+				"Route TLS client not selected for the route")
+
+			rsMap := ResourceMap{rsCfg.Virtual.Name: rsCfg}
+			sharedApp := as3Application{}
+			processResourcesForAS3(rsMap, sharedApp, false, "test")
+			processCustomProfilesForAS3(rsMap, sharedApp, 3.44)
+			tlsClient, ok := sharedApp[tlsClientName].(*as3TLSClient)
+			Expect(ok).To(BeTrue(), "Route TLS client not created")
+			Expect(tlsClient.ValidateCertificate).To(BeTrue(), "Route TLS client should verify the server certificate")
+			caBundle, ok := sharedApp[tlsClient.TrustCA.Use].(*as3CABundle)
+			Expect(ok).To(BeTrue(), "Route CA bundle not created")
+			Expect(caBundle.Bundle).To(Equal("\ndestca"), "Route CA bundle should trust only the destination CA")
+		})
+
 		It("Check Route TLS", func() {
 
 			annotation1 := make(map[string]string)
@@ -1681,6 +1907,66 @@ extendedRouteSpec:
 			Expect(len(createdSpecs)).To(BeZero())
 		})
 
+		It("Route group WAF and allowVlans", func() {
+			es := extendedSpec{}
+			err := yaml.UnmarshalStrict([]byte(`
+extendedRouteSpec:
+    - namespace: default
+      vserverAddr: 10.8.3.11
+      vserverName: nextgenroutes
+      allowOverride: true
+      policyCR: default/policy
+      waf: /Common/WAF_RouteGroup
+      allowVlans:
+        - /Common/internal
+`), &es)
+			Expect(err).To(BeNil(), "Failed to parse extended spec")
+			extdSpec := es.ExtendedRouteGroupConfigs[0].ExtendedRouteGroupSpec
+			Expect(extdSpec.WAF).To(Equal("/Common/WAF_RouteGroup"))
+			Expect(extdSpec.AllowVlans).To(Equal([]string{"/Common/internal"}))
+
+			plc := &cisapiv1.Policy{
+				ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: "default"},
+				Spec: cisapiv1.PolicySpec{
+					L7Policies: cisapiv1.L7PolicySpec{WAF: "/Common/WAF_Policy"},
+					L3Policies: cisapiv1.L3PolicySpec{AllowVlans: []string{"/Common/external"}},
+				},
+			}
+			rsCfg := &ResourceConfig{}
+			rsCfg.Virtual.Name = "nextgenroutes_443"
+			rsCfg.MetaData.Protocol = HTTPS
+			err = mockCtlr.handleRouteGroupExtendedSpec(rsCfg, plc, &AnnotationsUsed{}, &extdSpec)
+			Expect(err).To(BeNil())
+			Expect(rsCfg.Virtual.WAF).To(Equal("/Common/WAF_RouteGroup"), "WAF of route group should take precedence")
+			Expect(rsCfg.Virtual.AllowVLANs).To(Equal([]string{"/Common/internal"}), "allowVlans of route group should take precedence")
+
+			// Route group without WAF and allowVlans uses the policy CR
+			rsCfg = &ResourceConfig{}
+			extdSpec.WAF = ""
+			extdSpec.AllowVlans = nil
+			err = mockCtlr.handleRouteGroupExtendedSpec(rsCfg, plc, &AnnotationsUsed{}, &extdSpec)
+			Expect(err).To(BeNil())
+			Expect(rsCfg.Virtual.WAF).To(Equal("/Common/WAF_Policy"))
+			Expect(rsCfg.Virtual.AllowVLANs).To(Equal([]string{"/Common/external"}))
+
+			// WAF annotation of the routes takes precedence with allowOverride
+			rsCfg = &ResourceConfig{}
+			extdSpec.WAF = "/Common/WAF_RouteGroup"
+			err = mockCtlr.handleRouteGroupExtendedSpec(rsCfg, nil, &AnnotationsUsed{WAF: true}, &extdSpec)
+			Expect(err).To(BeNil())
+			Expect(rsCfg.Virtual.WAF).To(BeEmpty(), "WAF annotation of the routes should take precedence")
+
+			// Update to WAF of the route group reprocesses the routes without deleting the virtuals
+			cachedSpec := extdSpec
+			cachedSpec.WAF = "/Common/WAF_Old"
+			_, modifiedSpecs, updatedSpecs, _ := getOperationalExtendedConfigMapSpecs(
+				extendedSpecMap{"default": &extendedParsedSpec{global: &cachedSpec}},
+				extendedSpecMap{"default": &extendedParsedSpec{global: &extdSpec}}, false,
+			)
+			Expect(modifiedSpecs).To(BeEmpty())
+			Expect(updatedSpecs).To(Equal([]string{"default"}))
+		})
+
 		It("Global ConfigMap with base route config", func() {
 			data["extendedSpec"] = `
 baseRouteSpec: 
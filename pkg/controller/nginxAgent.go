@@ -0,0 +1,221 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+)
+
+const (
+	nginxHTTPUpstreams   = "http"
+	nginxStreamUpstreams = "stream"
+
+	DefaultNginxPlusAPIVersion = 8
+)
+
+func NewNginxManager(params AgentParams, httpClient *http.Client) *NginxManager {
+	ngxMgr := &NginxManager{
+		httpClient:       httpClient,
+		NginxParams:      params.NginxParams,
+		managedUpstreams: make(map[string]struct{}),
+	}
+	if ngxMgr.NginxPlusAPIVersion == 0 {
+		ngxMgr.NginxPlusAPIVersion = DefaultNginxPlusAPIVersion
+	}
+	return ngxMgr
+}
+
+// postNginxConfig renders the pools of the configuration as upstreams and updates their servers in NGINX Plus
+func (ngxMgr *NginxManager) postNginxConfig(rsConfig ResourceConfigRequest) (map[string]struct{}, bool) {
+	return ngxMgr.syncNginxUpstreams(createNginxUpstreams(rsConfig.ltmConfig))
+}
+
+// createNginxUpstreams renders the pools of the VirtualServers as http upstreams and the pools of the
+// TransportServers as stream upstreams, key of the upstreams is http/name or stream/name
+func createNginxUpstreams(ltmConfig LTMConfig) map[string]nginxUpstream {
+	upstreams := make(map[string]nginxUpstream)
+	for partition, partitionConfig := range ltmConfig {
+		for _, cfg := range partitionConfig.ResourceMap {
+			var kind string
+			switch cfg.MetaData.ResourceType {
+			case VirtualServer:
+				kind = nginxHTTPUpstreams
+			case TransportServer:
+				kind = nginxStreamUpstreams
+			default:
+				continue
+			}
+			for _, pool := range cfg.Pools {
+				upstream := nginxUpstream{partition: partition}
+				servers := make(map[string]struct{})
+				for _, member := range pool.Members {
+					server := net.JoinHostPort(member.Address, strconv.Itoa(int(member.Port)))
+					if _, ok := servers[server]; ok {
+						continue
+					}
+					servers[server] = struct{}{}
+					upstream.servers = append(upstream.servers, server)
+				}
+				sort.Strings(upstream.servers)
+				upstreams[kind+"/"+pool.Name] = upstream
+			}
+		}
+	}
+	return upstreams
+}
+
+func (ngxMgr *NginxManager) getUpstreamServersURL(key string) string {
+	kind := strings.Split(key, "/")[0]
+	return fmt.Sprintf("%s/api/%d/%s/upstreams/%s/servers",
+		strings.TrimSuffix(ngxMgr.NginxPlusAPIURL, "/"), ngxMgr.NginxPlusAPIVersion, kind,
+		strings.TrimPrefix(key, kind+"/"))
+}
+
+// syncNginxUpstreams updates the servers of the upstreams in NGINX Plus, servers of the upstreams
+// of the deleted pools are removed. It returns the partitions of the failed upstreams and false when
+// any request failed
+func (ngxMgr *NginxManager) syncNginxUpstreams(upstreams map[string]nginxUpstream) (map[string]struct{}, bool) {
+	failedPartitions := make(map[string]struct{})
+	ok := true
+	for key := range ngxMgr.managedUpstreams {
+		if _, found := upstreams[key]; !found {
+			upstreams[key] = nginxUpstream{}
+		}
+	}
+	keys := make([]string, 0, len(upstreams))
+	for key := range upstreams {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		upstream := upstreams[key]
+		found, updated := ngxMgr.updateUpstreamServers(key, upstream.servers)
+		if !updated {
+			if upstream.partition != "" {
+				failedPartitions[upstream.partition] = struct{}{}
+			}
+			ok = false
+			continue
+		}
+		if !found {
+			if len(upstream.servers) > 0 {
+				log.Warningf("[NGINX] Upstream %v is not present in NGINX Plus with a shared memory zone, "+
+					"skipping the pool", key)
+			}
+			delete(ngxMgr.managedUpstreams, key)
+			continue
+		}
+		if upstream.partition == "" {
+			// pool is deleted
+			delete(ngxMgr.managedUpstreams, key)
+			continue
+		}
+		ngxMgr.managedUpstreams[key] = struct{}{}
+	}
+	return failedPartitions, ok
+}
+
+// updateUpstreamServers adds and removes the servers of the upstream as per the pool members,
+// it returns whether the upstream is present in NGINX Plus and whether the servers are updated
+func (ngxMgr *NginxManager) updateUpstreamServers(key string, servers []string) (bool, bool) {
+	apiURL := ngxMgr.getUpstreamServersURL(key)
+	status, body := ngxMgr.nginxRequest(http.MethodGet, apiURL, nil)
+	switch status {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return false, true
+	default:
+		return true, false
+	}
+	var current []nginxUpstreamServer
+	if err := json.Unmarshal(body, &current); err != nil {
+		log.Errorf("[NGINX] Response body unmarshal failed for upstream %v: %v", key, err)
+		return true, false
+	}
+
+	updated := true
+	desired := make(map[string]struct{})
+	for _, server := range servers {
+		desired[server] = struct{}{}
+	}
+	existing := make(map[string]struct{})
+	for _, server := range current {
+		if _, ok := desired[server.Server]; ok {
+			existing[server.Server] = struct{}{}
+			continue
+		}
+		status, _ = ngxMgr.nginxRequest(http.MethodDelete, fmt.Sprintf("%s/%d", apiURL, server.ID), nil)
+		if status != http.StatusOK && status != http.StatusNoContent && status != http.StatusNotFound {
+			updated = false
+			continue
+		}
+		log.Debugf("[NGINX] Removed server %v from upstream %v", server.Server, key)
+	}
+	for _, server := range servers {
+		if _, ok := existing[server]; ok {
+			continue
+		}
+		data, _ := json.Marshal(nginxUpstreamServer{Server: server})
+		status, _ = ngxMgr.nginxRequest(http.MethodPost, apiURL, data)
+		if status != http.StatusCreated && status != http.StatusOK {
+			updated = false
+			continue
+		}
+		log.Debugf("[NGINX] Added server %v to upstream %v", server, key)
+	}
+	return true, updated
+}
+
+// nginxRequest sends the request to NGINX Plus API and returns the status code and response body
+func (ngxMgr *NginxManager) nginxRequest(method, apiURL string, data []byte) (int, []byte) {
+	req, err := http.NewRequest(method, apiURL, bytes.NewBuffer(data))
+	if err != nil {
+		log.Errorf("[NGINX] Creating new HTTP request error: %v ", err)
+		return 0, nil
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	log.Debugf("[NGINX] %v request on %v", method, apiURL)
+	httpResp, err := ngxMgr.httpClient.Do(req)
+	if err != nil {
+		log.Errorf("[NGINX] REST call error: %v ", err)
+		return 0, nil
+	}
+	defer httpResp.Body.Close()
+
+	body, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		log.Errorf("[NGINX] REST call response error: %v ", err)
+		return 0, nil
+	}
+	if httpResp.StatusCode >= http.StatusBadRequest && httpResp.StatusCode != http.StatusNotFound {
+		log.Errorf("[NGINX] %v request on %v failed with status code %v: %v",
+			method, apiURL, httpResp.StatusCode, string(body))
+	}
+	return httpResp.StatusCode, body
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+
+	mockhc "github.com/f5devcentral/mockhttpclient"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NGINX Plus Agent", func() {
+	var ngxMgr *NginxManager
+	var ltmConfig LTMConfig
+
+	newResponse := func(status int, body string) *http.Response {
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+		}
+	}
+
+	BeforeEach(func() {
+		ngxMgr = NewNginxManager(AgentParams{
+			NginxParams: NginxParams{NginxPlusAPIURL: "http://nginx.example.com:8080/"},
+		}, nil)
+
+		rsCfg := &ResourceConfig{}
+		rsCfg.MetaData.ResourceType = VirtualServer
+		rsCfg.Pools = Pools{
+			{
+				Name: "svc1_80_default_foo_com",
+				Members: []PoolMember{
+					{Address: "10.244.1.2", Port: 8080},
+					{Address: "10.244.1.1", Port: 8080},
+					{Address: "10.244.1.1", Port: 8080},
+				},
+			},
+		}
+		tsCfg := &ResourceConfig{}
+		tsCfg.MetaData.ResourceType = TransportServer
+		tsCfg.Pools = Pools{
+			{
+				Name:    "svc2_53_default",
+				Members: []PoolMember{{Address: "2001::1", Port: 53}},
+			},
+		}
+		hmCfg := &ResourceConfig{}
+		hmCfg.MetaData.ResourceType = HealthMonitor
+
+		ltmConfig = LTMConfig{
+			"test": &PartitionConfig{ResourceMap: ResourceMap{
+				"crd_10_1_1_1_80":  rsCfg,
+				"crd_10_1_1_1_53":  tsCfg,
+				"hm_healthmonitor": hmCfg,
+			}},
+		}
+	})
+
+	It("Renders the pools as upstreams", func() {
+		upstreams := createNginxUpstreams(ltmConfig)
+		Expect(upstreams).To(Equal(map[string]nginxUpstream{
+			"http/svc1_80_default_foo_com": {partition: "test", servers: []string{"10.244.1.1:8080", "10.244.1.2:8080"}},
+			"stream/svc2_53_default":       {partition: "test", servers: []string{"[2001::1]:53"}},
+		}))
+		Expect(ngxMgr.getUpstreamServersURL("http/svc1_80_default_foo_com")).To(
+			Equal("http://nginx.example.com:8080/api/8/http/upstreams/svc1_80_default_foo_com/servers"))
+	})
+
+	It("Updates the servers of the upstreams", func() {
+		responseMap := mockhc.ResponseConfigMap{
+			http.MethodGet: &mockhc.ResponseConfig{
+				Responses: []*http.Response{
+					newResponse(http.StatusOK, `[{"id": 0, "server": "10.244.1.1:8080"}, {"id": 1, "server": "10.244.1.3:8080"}]`),
+					newResponse(http.StatusNotFound, `{"error": {"status": 404, "code": "UpstreamNotFound"}}`),
+				},
+			},
+			http.MethodDelete: &mockhc.ResponseConfig{
+				Responses: []*http.Response{newResponse(http.StatusOK, `[]`)},
+			},
+			http.MethodPost: &mockhc.ResponseConfig{
+				Responses: []*http.Response{newResponse(http.StatusCreated, `{"id": 2, "server": "10.244.1.2:8080"}`)},
+			},
+		}
+		client, _ := mockhc.NewMockHTTPClient(responseMap)
+		ngxMgr.httpClient = client
+
+		failedPartitions, ok := ngxMgr.postNginxConfig(ResourceConfigRequest{ltmConfig: ltmConfig})
+		Expect(ok).To(BeTrue())
+		Expect(failedPartitions).To(BeEmpty())
+		// stream upstream is not present in NGINX Plus
+		Expect(ngxMgr.managedUpstreams).To(Equal(map[string]struct{}{"http/svc1_80_default_foo_com": {}}))
+
+		// Servers of the deleted pool are removed and failures are reported for the partition
+		responseMap = mockhc.ResponseConfigMap{
+			http.MethodGet: &mockhc.ResponseConfig{
+				Responses: []*http.Response{
+					newResponse(http.StatusOK, `[{"id": 0, "server": "10.244.1.1:8080"}, {"id": 2, "server": "10.244.1.2:8080"}]`),
+					newResponse(http.StatusBadGateway, `{}`),
+				},
+			},
+			http.MethodDelete: &mockhc.ResponseConfig{
+				Responses: []*http.Response{newResponse(http.StatusOK, `[]`), newResponse(http.StatusOK, `[]`)},
+			},
+		}
+		client, _ = mockhc.NewMockHTTPClient(responseMap)
+		ngxMgr.httpClient = client
+		delete(ltmConfig["test"].ResourceMap, "crd_10_1_1_1_80")
+		failedPartitions, ok = ngxMgr.postNginxConfig(ResourceConfigRequest{ltmConfig: ltmConfig})
+		Expect(ok).To(BeFalse())
+		Expect(failedPartitions).To(HaveKey("test"))
+		Expect(ngxMgr.managedUpstreams).To(BeEmpty())
+	})
+})
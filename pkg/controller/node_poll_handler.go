@@ -6,6 +6,7 @@ import (
 	bigIPPrometheus "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/prometheus"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"net"
 	"reflect"
 	"sort"
@@ -45,11 +46,53 @@ func (ctlr *Controller) SetupNodeProcessing(clusterName string) error {
 		}
 	} else if ctlr.vxlanMgr != nil {
 		// Register vxMgr to watch for node updates to process fdb records
-		ctlr.vxlanMgr.ProcessNodeUpdate(nodesList)
+		ctlr.vxlanMgr.ProcessNodeUpdate(ctlr.getOverlayNodes(nodesList))
 	}
 	return nil
 }
 
+// enqueueNodeEvent batches the node events of the cluster so that the nodes are processed once for all the
+// events received in the batch interval, such as the node events of the cluster autoscaler scaling the cluster.
+// Node events are not batched until the initial nodes are processed so that the pool members are not posted
+// with the partial node cache
+func (ctlr *Controller) enqueueNodeEvent(nodeInformer *NodeInformer) {
+	clusterName := nodeInformer.clusterName
+	if ctlr.nodeBatchInterval <= 0 || ctlr.initState || !nodeInformer.nodeInformer.HasSynced() {
+		ctlr.nodeEvents.process.Lock()
+		defer ctlr.nodeEvents.process.Unlock()
+		ctlr.SetupNodeProcessing(clusterName)
+		return
+	}
+	ctlr.nodeEvents.Lock()
+	defer ctlr.nodeEvents.Unlock()
+	if ctlr.nodeEvents.pending == nil {
+		ctlr.nodeEvents.pending = make(map[string]int)
+	}
+	if _, ok := ctlr.nodeEvents.pending[clusterName]; ok {
+		ctlr.nodeEvents.pending[clusterName]++
+		bigIPPrometheus.SuppressedNodeEvents.Inc()
+		return
+	}
+	ctlr.nodeEvents.pending[clusterName] = 0
+	time.AfterFunc(time.Duration(ctlr.nodeBatchInterval)*time.Second, func() {
+		ctlr.processNodeEventBatch(clusterName)
+	})
+}
+
+// processNodeEventBatch processes the nodes of the cluster for the batched node events
+func (ctlr *Controller) processNodeEventBatch(clusterName string) {
+	ctlr.nodeEvents.Lock()
+	suppressed := ctlr.nodeEvents.pending[clusterName]
+	delete(ctlr.nodeEvents.pending, clusterName)
+	ctlr.nodeEvents.Unlock()
+
+	ctlr.nodeEvents.process.Lock()
+	defer ctlr.nodeEvents.process.Unlock()
+	log.Debugf("%v Processing the batch of %v node events %v", ctlr.getMultiClusterLog(), suppressed+1,
+		getClusterLog(clusterName))
+	ctlr.SetupNodeProcessing(clusterName)
+}
+
 // ProcessNodeUpdate Check for a change in Node state
 func (ctlr *Controller) ProcessNodeUpdate(obj interface{}, clusterName string) {
 	newNodes, err := ctlr.getNodes(obj)
@@ -96,12 +139,13 @@ func (ctlr *Controller) ProcessNodeUpdate(obj interface{}, clusterName string) {
 			}
 		}
 	}
+	ctlr.updateNodeStatusMetrics()
 }
 
 func (ctlr *Controller) UpdatePoolMembersForNodeUpdate(clusterName string) {
 	if svcKeys, ok := ctlr.multiClusterResources.clusterSvcMap[clusterName]; ok {
 		for svcKey, _ := range svcKeys {
-			ctlr.updatePoolMembersForService(svcKey)
+			ctlr.updatePoolMembersForService(svcKey, false)
 		}
 		key := &rqKey{
 			kind: NodeUpdate,
@@ -161,9 +205,11 @@ func (ctlr *Controller) getNodes(
 		for _, addr := range nodeAddrs {
 			if addr.Type == addrType {
 				n := Node{
-					Name:   node.ObjectMeta.Name,
-					Addr:   addr.Address,
-					Labels: make(map[string]string),
+					Name:          node.ObjectMeta.Name,
+					Addr:          addr.Address,
+					OS:            getNodeOS(&node),
+					Labels:        make(map[string]string),
+					Unschedulable: isNodeUnschedulable(&node),
 				}
 				for k, v := range node.ObjectMeta.Labels {
 					n.Labels[k] = v
@@ -176,20 +222,101 @@ func (ctlr *Controller) getNodes(
 	return watchedNodes, nil
 }
 
+// isNodeUnschedulable returns true for the cordoned nodes and the nodes whose Ready condition is not true
+func isNodeUnschedulable(node *v1.Node) bool {
+	if node.Spec.Unschedulable {
+		return true
+	}
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == v1.NodeReady {
+			return cond.Status != v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// getNodeOS returns the operating system of the node from the well known label or the node info
+func getNodeOS(node *v1.Node) string {
+	if os, ok := node.Labels[v1.LabelOSStable]; ok {
+		return os
+	}
+	return node.Status.NodeInfo.OperatingSystem
+}
+
+// isPoolMemberNodeOS returns true when the nodes of the operating system are used as the pool members
+func (ctlr *Controller) isPoolMemberNodeOS(os string) bool {
+	if len(ctlr.poolMemberNodeOS) == 0 {
+		return true
+	}
+	for _, memberOS := range ctlr.poolMemberNodeOS {
+		if strings.EqualFold(memberOS, os) {
+			return true
+		}
+	}
+	return false
+}
+
+// getOverlayNodes returns the nodes participating in the VxLAN overlay
+func (ctlr *Controller) getOverlayNodes(nodes []v1.Node) []v1.Node {
+	if len(ctlr.vxlanExcludedNodeOS) == 0 {
+		return nodes
+	}
+	var overlayNodes []v1.Node
+	for _, node := range nodes {
+		excluded := false
+		for _, os := range ctlr.vxlanExcludedNodeOS {
+			if strings.EqualFold(os, getNodeOS(&node)) {
+				excluded = true
+				break
+			}
+		}
+		if excluded {
+			log.Debugf("[VxLAN] Skipping FDB of the %v node %v", getNodeOS(&node), node.Name)
+			continue
+		}
+		overlayNodes = append(overlayNodes, node)
+	}
+	return overlayNodes
+}
+
+// getNodeStatus returns the status of the node as the pool member
+func (ctlr *Controller) getNodeStatus(node Node) string {
+	if !ctlr.isPoolMemberNodeOS(node.OS) {
+		return NodeStatusExcluded
+	}
+	if node.Unschedulable {
+		return NodeStatusUnschedulable
+	}
+	return NodeStatusReady
+}
+
+// updateNodeStatusMetrics updates the status of the nodes of all the clusters in bigip_node_status metric
+func (ctlr *Controller) updateNodeStatusMetrics() {
+	bigIPPrometheus.NodeStatus.Reset()
+	setStatus := func(clusterName string, nodes []Node) {
+		for _, node := range nodes {
+			bigIPPrometheus.NodeStatus.WithLabelValues(clusterName, node.Name, node.OS, ctlr.getNodeStatus(node)).Set(1)
+		}
+	}
+	setStatus("", ctlr.oldNodes)
+	for clusterName, nodeInf := range ctlr.multiClusterNodeInformers {
+		setStatus(clusterName, nodeInf.oldNodes)
+	}
+}
+
 func (ctlr *Controller) getNodesWithLabel(
 	nodeMemberLabel, clusterName string,
 ) []Node {
 	allNodes := ctlr.getNodesFromCache(clusterName)
-	label := strings.Split(nodeMemberLabel, "=")
-	if len(label) != 2 {
-		log.Warningf("Invalid NodeMemberLabel: %v %v", nodeMemberLabel, getClusterLog(clusterName))
+	// NodeMemberLabel is a label selector, "key=value" of the earlier releases is a valid selector
+	selector, err := labels.Parse(nodeMemberLabel)
+	if err != nil {
+		log.Warningf("Invalid NodeMemberLabel: %v %v: %v", nodeMemberLabel, getClusterLog(clusterName), err)
 		return nil
 	}
-	labelKey := label[0]
-	labelValue := label[1]
 	var nodes []Node
 	for _, node := range allNodes {
-		if node.Labels[labelKey] == labelValue {
+		if selector.Matches(labels.Set(node.Labels)) {
 			nodes = append(nodes, node)
 		}
 	}
@@ -230,8 +357,23 @@ func (ctlr *Controller) processStaticRouteUpdate(
 			continue
 		}
 		route := routeConfig{}
-		// For ovn-k8s get pod subnet and node ip from annotation
-		if ctlr.OrchestrationCNI == OVN_K8S {
+		if ctlr.OrchestrationCNI == OVN_K8S && getNodeOS(node) == WindowsNodeOS {
+			// Windows nodes of the hybrid overlay have the host subnet in the hybrid overlay annotation
+			nodesubnet, ok := node.Annotations[OVNK8sHybridOverlayNodeSubnetAnnotation]
+			if !ok {
+				log.Warningf("Node subnet annotation %v not found on windows node %v static route not added",
+					OVNK8sHybridOverlayNodeSubnetAnnotation, node.Name)
+				continue
+			}
+			route.Network = nodesubnet
+			for _, addr := range node.Status.Addresses {
+				if addr.Type == addrType {
+					route.Gateway = addr.Address
+					route.Name = fmt.Sprintf("k8s-%v-%v", node.Name, addr.Address)
+				}
+			}
+		} else if ctlr.OrchestrationCNI == OVN_K8S {
+			// For ovn-k8s get pod subnet and node ip from annotation
 			annotations := node.Annotations
 			if nodeSubnetAnn, ok := annotations[OVNK8sNodeSubnetAnnotation]; !ok {
 				log.Warningf("Node subnet annotation %v not found on node %v static route not added", OVNK8sNodeSubnetAnnotation, node.Name)
@@ -1,11 +1,18 @@
 package controller
 
 import (
+	"context"
+	"time"
+
 	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/test"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
 )
 
 var _ = Describe("Node Poller Handler", func() {
@@ -91,9 +98,121 @@ var _ = Describe("Node Poller Handler", func() {
 		Expect(nodes).ToNot(BeNil(), "Failed to get Nodes with Label")
 
 		nodes = mockCtlr.getNodesWithLabel("app", "")
+		Expect(len(nodes)).To(Equal(1), "Failed to get Nodes with Label")
+
+		nodes = mockCtlr.getNodesWithLabel("app in (test,prod)", "")
+		Expect(len(nodes)).To(Equal(1), "Failed to get Nodes with Label")
+
+		nodes = mockCtlr.getNodesWithLabel("app!=test", "")
+		Expect(len(nodes)).To(Equal(1), "Failed to get Nodes with Label")
+
+		nodes = mockCtlr.getNodesWithLabel("app in test", "")
 		Expect(nodes).To(BeNil(), "Failed to Validate Nodes with Label")
 	})
 
+	It("Pool members of the unschedulable nodes", func() {
+		mockCtlr.UseNodeInternal = true
+		notReady := test.NewNode("worker2", "1", false,
+			[]v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "1.2.3.5"}}, nil)
+		notReady.Status.Conditions = []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionFalse}}
+		nodes, err := mockCtlr.getNodes([]v1.Node{
+			*test.NewNode("worker1", "1", false, []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "1.2.3.4"}}, nil),
+			*notReady,
+			*test.NewNode("worker3", "1", true, []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "1.2.3.6"}}, nil),
+		})
+		Expect(err).To(BeNil())
+		Expect(nodes[0].Unschedulable).To(BeFalse())
+		Expect(nodes[1].Unschedulable).To(BeTrue(), "Not ready node is schedulable")
+		Expect(nodes[2].Unschedulable).To(BeTrue(), "Cordoned node is schedulable")
+		mockCtlr.oldNodes = nodes
+
+		mockCtlr.unschedulableNodes = UnschedulableNodeRetain
+		members := mockCtlr.getEndpointsForNodePort(30001, "", "")
+		Expect(members).To(HaveLen(3))
+		Expect(members[2].Session).To(Equal("user-enabled"))
+
+		mockCtlr.unschedulableNodes = UnschedulableNodeDrain
+		members = mockCtlr.getEndpointsForNodePort(30001, "", "")
+		Expect(members).To(HaveLen(3))
+		Expect(members[0].Session).To(Equal("user-enabled"))
+		Expect(members[1].Session).To(Equal("user-disabled"))
+		Expect(members[2].Session).To(Equal("user-disabled"))
+
+		mockCtlr.unschedulableNodes = UnschedulableNodeRemove
+		members = mockCtlr.getEndpointsForNodePort(30001, "", "")
+		Expect(members).To(HaveLen(1))
+		Expect(members[0].Address).To(Equal("1.2.3.4"))
+	})
+
+	It("Nodes of the operating systems", func() {
+		mockCtlr.UseNodeInternal = true
+		windows := test.NewNode("worker2", "1", false,
+			[]v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "1.2.3.5"}}, nil)
+		windows.Labels = map[string]string{v1.LabelOSStable: WindowsNodeOS}
+		linux := test.NewNode("worker1", "1", false,
+			[]v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "1.2.3.4"}}, nil)
+		linux.Status.NodeInfo.OperatingSystem = "linux"
+		nodes, err := mockCtlr.getNodes([]v1.Node{*linux, *windows})
+		Expect(err).To(BeNil())
+		Expect(nodes[0].OS).To(Equal("linux"))
+		Expect(nodes[1].OS).To(Equal(WindowsNodeOS))
+		mockCtlr.oldNodes = nodes
+		Expect(mockCtlr.getEndpointsForNodePort(30001, "", "")).To(HaveLen(2))
+		Expect(mockCtlr.getNodeStatus(nodes[1])).To(Equal(NodeStatusReady))
+
+		mockCtlr.poolMemberNodeOS = []string{"linux"}
+		members := mockCtlr.getEndpointsForNodePort(30001, "", "")
+		Expect(members).To(HaveLen(1))
+		Expect(members[0].Address).To(Equal("1.2.3.4"))
+		Expect(mockCtlr.getNodeStatus(nodes[1])).To(Equal(NodeStatusExcluded))
+
+		Expect(mockCtlr.getOverlayNodes([]v1.Node{*linux, *windows})).To(HaveLen(2))
+		mockCtlr.vxlanExcludedNodeOS = []string{WindowsNodeOS}
+		overlayNodes := mockCtlr.getOverlayNodes([]v1.Node{*linux, *windows})
+		Expect(overlayNodes).To(HaveLen(1))
+		Expect(overlayNodes[0].Name).To(Equal("worker1"))
+	})
+
+	It("Batches the node events", func() {
+		node := test.NewNode("worker1", "1", false,
+			[]v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "1.2.3.4"}}, nil)
+		mockCtlr.kubeClient = k8sfake.NewSimpleClientset(node)
+		mockCtlr.UseNodeInternal = true
+		mockCtlr.initState = false
+		mockCtlr.nodeBatchInterval = 1
+		inf := cache.NewSharedIndexInformer(
+			&cache.ListWatch{
+				ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+					return mockCtlr.kubeClient.CoreV1().Nodes().List(context.TODO(), options)
+				},
+				WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+					return mockCtlr.kubeClient.CoreV1().Nodes().Watch(context.TODO(), options)
+				},
+			},
+			&v1.Node{},
+			0,
+			cache.Indexers{},
+		)
+		stopCh := make(chan struct{})
+		defer close(stopCh)
+		go inf.Run(stopCh)
+		Expect(cache.WaitForCacheSync(stopCh, inf.HasSynced)).To(BeTrue())
+		mockCtlr.nodeInformer = &NodeInformer{nodeInformer: inf}
+
+		// Node events in the batch interval are processed once
+		mockCtlr.enqueueNodeEvent(mockCtlr.nodeInformer)
+		mockCtlr.enqueueNodeEvent(mockCtlr.nodeInformer)
+		mockCtlr.enqueueNodeEvent(mockCtlr.nodeInformer)
+		mockCtlr.nodeEvents.Lock()
+		Expect(mockCtlr.nodeEvents.pending).To(Equal(map[string]int{"": 2}))
+		mockCtlr.nodeEvents.Unlock()
+		Expect(mockCtlr.getNodesFromCache("")).To(BeEmpty())
+		Eventually(func() []Node { return mockCtlr.getNodesFromCache("") }, 3*time.Second).Should(HaveLen(1))
+		mockCtlr.nodeEvents.Lock()
+		Expect(mockCtlr.nodeEvents.pending).To(BeEmpty())
+		mockCtlr.nodeEvents.Unlock()
+	})
+
 	It("Nodes Update processing", func() {
 		nodeInf := mockCtlr.getNodeInformer("")
 		mockCtlr.nodeInformer = &nodeInf
@@ -230,6 +349,28 @@ var _ = Describe("Node Poller Handler", func() {
 		}
 		Expect(mockWriter.Sections["static-routes"]).To(Equal(expectedRouteSection))
 
+		// OrchestrationCNI = OVN_K8S windows node with the hybrid overlay annotation
+		for i, _ := range nodeObjs {
+			nodeObjs[i].Labels = map[string]string{v1.LabelOSStable: WindowsNodeOS}
+			nodeObjs[i].Annotations = map[string]string{OVNK8sHybridOverlayNodeSubnetAnnotation: "10.132.0.0/24"}
+			mockCtlr.updateNode(&nodeObjs[i], namespace)
+		}
+		mockCtlr.SetupNodeProcessing("")
+		expectedRouteSection = routeSection{
+			Entries: []routeConfig{
+				{
+					Name:    "k8s-worker1-1.2.3.4",
+					Network: "10.132.0.0/24",
+					Gateway: "1.2.3.4",
+				},
+			},
+		}
+		Expect(mockWriter.Sections["static-routes"]).To(Equal(expectedRouteSection))
+		for i, _ := range nodeObjs {
+			nodeObjs[i].Labels = nil
+			mockCtlr.updateNode(&nodeObjs[i], namespace)
+		}
+
 		// OrchestrationCNI = CILIUM_K8S with no valid cilium-k8s annotation
 		mockCtlr.OrchestrationCNI = CILIUM_K8S
 		mockCtlr.UseNodeInternal = true
@@ -0,0 +1,213 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/prometheus"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+	v1 "k8s.io/api/core/v1"
+)
+
+// poolMemberStats are the statistics of a pool member on BIG-IP
+type poolMemberStats struct {
+	currentConns float64
+	totalConns   float64
+	available    bool
+}
+
+func (postMgr *PostManager) getPoolMemberStatsURL(partition, pool string) string {
+	return fmt.Sprintf("%v/mgmt/tm/ltm/pool/~%v~Shared~%v/members/stats", postMgr.getDeviceBaseURL(), partition, pool)
+}
+
+// getPoolMemberStats returns the statistics of the members of the pool with the address:port of the members
+func (postMgr *PostManager) getPoolMemberStats(partition, pool string) (map[string]poolMemberStats, error) {
+	url := postMgr.getPoolMemberStatsURL(partition, pool)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Posting GET BIGIP pool member stats request on %v", url)
+	postMgr.setAuthHeader(req)
+
+	httpResp, responseMap := postMgr.httpReq(req)
+	if httpResp == nil || responseMap == nil {
+		return nil, fmt.Errorf("Internal Error")
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Error response from BIGIP with status code %v", httpResp.StatusCode)
+	}
+	members := make(map[string]poolMemberStats)
+	entries, _ := responseMap["entries"].(map[string]interface{})
+	for _, entry := range entries {
+		nestedStats, _ := entry.(map[string]interface{})["nestedStats"].(map[string]interface{})
+		stats, _ := nestedStats["entries"].(map[string]interface{})
+		addr := getStatDescription(stats, "addr")
+		if addr == "" {
+			continue
+		}
+		// route domain of the address is not part of the pool member address of CIS
+		addr = strings.Split(addr, "%")[0]
+		members[fmt.Sprintf("%v:%v", addr, getStatValue(stats, "port"))] = poolMemberStats{
+			currentConns: getStatValue(stats, "serverside.curConns"),
+			totalConns:   getStatValue(stats, "serverside.totConns"),
+			available:    getStatDescription(stats, "status.availabilityState") == "available",
+		}
+	}
+	return members, nil
+}
+
+func getStatValue(stats map[string]interface{}, name string) float64 {
+	stat, _ := stats[name].(map[string]interface{})
+	value, _ := stat["value"].(float64)
+	return value
+}
+
+func getStatDescription(stats map[string]interface{}, name string) string {
+	stat, _ := stats[name].(map[string]interface{})
+	description, _ := stat["description"].(string)
+	return description
+}
+
+// setManagedPoolMembers records the members of the pools of the configuration posted by CIS with
+// their service and pod
+func (ctlr *Controller) setManagedPoolMembers(config ResourceConfigRequest) {
+	if ctlr.memberStatsInterval <= 0 {
+		return
+	}
+	pools := make(map[poolStatsKey]map[string]poolMemberMeta)
+	for partition, partitionConfig := range config.ltmConfig {
+		for _, rsCfg := range partitionConfig.ResourceMap {
+			for _, pool := range rsCfg.Pools {
+				key := poolStatsKey{partition: partition, name: pool.Name}
+				if _, ok := pools[key]; ok {
+					continue
+				}
+				pods := ctlr.getPoolMemberPods(pool)
+				members := make(map[string]poolMemberMeta)
+				for _, mem := range pool.Members {
+					member := fmt.Sprintf("%v:%v", mem.Address, mem.Port)
+					members[member] = poolMemberMeta{
+						namespace: pool.ServiceNamespace,
+						service:   pool.ServiceName,
+						pod:       pods[member],
+					}
+				}
+				pools[key] = members
+			}
+		}
+	}
+	ctlr.memberStats.Lock()
+	ctlr.memberStats.pools = pools
+	ctlr.memberStats.Unlock()
+}
+
+// getPoolMemberPods returns the pods of the pool members of the local cluster with the address:port of
+// the members. Members are the pods in cluster mode and the NodePortLocal ports of the pods in nodeportlocal
+// mode, members of nodeport mode are the nodes
+func (ctlr *Controller) getPoolMemberPods(pool Pool) map[string]string {
+	pods := make(map[string]string)
+	if pool.Cluster != "" {
+		return pods
+	}
+	switch ctlr.PoolMemberType {
+	case Cluster:
+		comInf, ok := ctlr.getNamespacedCommonInformer(pool.ServiceNamespace)
+		if !ok || comInf.epsInformer == nil {
+			return pods
+		}
+		obj, found, _ := comInf.epsInformer.GetIndexer().GetByKey(pool.ServiceNamespace + "/" + pool.ServiceName)
+		if !found {
+			return pods
+		}
+		podIPs := make(map[string]string)
+		for _, subset := range obj.(*v1.Endpoints).Subsets {
+			for _, addr := range subset.Addresses {
+				if addr.TargetRef != nil && addr.TargetRef.Kind == "Pod" {
+					podIPs[addr.IP] = addr.TargetRef.Name
+				}
+			}
+		}
+		for _, mem := range pool.Members {
+			if pod, ok := podIPs[mem.Address]; ok {
+				pods[fmt.Sprintf("%v:%v", mem.Address, mem.Port)] = pod
+			}
+		}
+	case NodePortLocal:
+		for _, pod := range ctlr.GetPodsForService(pool.ServiceNamespace, pool.ServiceName, true) {
+			for _, annotation := range ctlr.resources.nplStore[pod.Namespace+"/"+pod.Name] {
+				pods[fmt.Sprintf("%v:%v", annotation.NodeIP, annotation.NodePort)] = pod.Name
+			}
+		}
+	}
+	return pods
+}
+
+// exportPoolMemberStats polls BIG-IP periodically for the statistics of the pool members posted by CIS
+func (ctlr *Controller) exportPoolMemberStats() {
+	if ctlr.Agent.xcManager != nil || ctlr.Agent.nginxManager != nil || !ctlr.Agent.isDeviceReachable() {
+		log.Warningf("Pool member statistics are exported only when the declarations are posted to BIG-IP or the " +
+			"BIG-IP credentials are provided")
+		return
+	}
+	for {
+		<-time.After(time.Duration(ctlr.memberStatsInterval) * time.Second)
+		ctlr.updatePoolMemberStats()
+	}
+}
+
+// updatePoolMemberStats updates the pool member metrics with the statistics of the members on BIG-IP
+func (ctlr *Controller) updatePoolMemberStats() {
+	ctlr.memberStats.Lock()
+	pools := ctlr.memberStats.pools
+	ctlr.memberStats.Unlock()
+
+	poolStats := make(map[poolStatsKey]map[string]poolMemberStats)
+	for key := range pools {
+		// pools shared with the identical pools are not on BIG-IP, the members are of the shared pool
+		key.name = ctlr.Agent.getSharedPool(key.partition, key.name)
+		if _, ok := poolStats[key]; ok {
+			continue
+		}
+		stats, err := ctlr.Agent.getPoolMemberStats(key.partition, key.name)
+		if err != nil {
+			log.Debugf("Unable to get the member statistics of pool /%v/Shared/%v: %v", key.partition, key.name, err)
+			continue
+		}
+		poolStats[key] = stats
+	}
+	prometheus.PoolMemberCurrentConnections.Reset()
+	prometheus.PoolMemberTotalConnections.Reset()
+	prometheus.PoolMemberAvailable.Reset()
+	for key, stats := range poolStats {
+		for member, stat := range stats {
+			meta := pools[key][member]
+			labels := []string{key.partition, key.name, member, meta.namespace, meta.service, meta.pod}
+			prometheus.PoolMemberCurrentConnections.WithLabelValues(labels...).Set(stat.currentConns)
+			prometheus.PoolMemberTotalConnections.WithLabelValues(labels...).Set(stat.totalConns)
+			available := 0.0
+			if stat.available {
+				available = 1
+			}
+			prometheus.PoolMemberAvailable.WithLabelValues(labels...).Set(available)
+		}
+	}
+}
@@ -18,18 +18,19 @@ package controller
 
 import (
 	"bytes"
-	"crypto/tls"
-	"crypto/x509"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
+	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/httpclient"
 	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/prometheus"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 const (
@@ -37,66 +38,105 @@ const (
 	timeoutLarge  = 180 * time.Second
 )
 
+const (
+	bigIQAuthTokenHeader = "X-F5-Auth-Token"
+	// default validity of the BIG-IQ auth token
+	bigIQAuthTokenTimeout = 5 * time.Minute
+)
+
 func NewPostManager(params AgentParams) *PostManager {
 	pm := &PostManager{
 		PostParams:                      params.PostParams,
 		firstPost:                       true,
 		PrimaryClusterHealthProbeParams: params.PrimaryClusterHealthProbeParams,
 	}
+	pm.ctx, pm.cancel = context.WithCancel(context.Background())
 	pm.setupBIGIPRESTClient()
 
 	return pm
 }
 
+// getAS3PostDelay returns the seconds waited before posting the AS3 declaration
+func (postMgr *PostManager) getAS3PostDelay() int {
+	postMgr.postParamsLock.RLock()
+	defer postMgr.postParamsLock.RUnlock()
+	return postMgr.AS3PostDelay
+}
+
+// isAS3RequestLogged checks whether the AS3 declarations are logged
+func (postMgr *PostManager) isAS3RequestLogged() bool {
+	postMgr.postParamsLock.RLock()
+	defer postMgr.postParamsLock.RUnlock()
+	return postMgr.LogAS3Request
+}
+
+// isAS3ResponseLogged checks whether the AS3 responses are logged
+func (postMgr *PostManager) isAS3ResponseLogged() bool {
+	postMgr.postParamsLock.RLock()
+	defer postMgr.postParamsLock.RUnlock()
+	return postMgr.LogAS3Response
+}
+
+// setAS3PostParams updates the post delay and the logging of the AS3 declarations while the agent is posting
+func (postMgr *PostManager) setAS3PostParams(postDelay int, logRequest, logResponse bool) {
+	postMgr.postParamsLock.Lock()
+	defer postMgr.postParamsLock.Unlock()
+	postMgr.AS3PostDelay = postDelay
+	postMgr.LogAS3Request = logRequest
+	postMgr.LogAS3Response = logResponse
+}
+
 func (postMgr *PostManager) setupBIGIPRESTClient() {
-	// Get the SystemCertPool, continue with an empty pool on error
-	rootCAs, _ := x509.SystemCertPool()
-	if rootCAs == nil {
-		rootCAs = x509.NewCertPool()
-	}
-	// TODO: Make sure appMgr sets certificates in bigipInfo
-	certs := []byte(postMgr.TrustedCerts)
-
-	// Append our certs to the system pool
-	if ok := rootCAs.AppendCertsFromPEM(certs); !ok {
-		log.Debug("[AS3] No certs appended, using only system certs")
-	}
-
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: postMgr.SSLInsecure,
-			RootCAs:            rootCAs,
-		},
-	}
-
-	if postMgr.HTTPClientMetrics {
-		log.Debug("[BIGIP] Http client instrumented with metrics!")
-		instrumentedRoundTripper := promhttp.InstrumentRoundTripperInFlight(prometheus.ClientInFlightGauge,
-			promhttp.InstrumentRoundTripperCounter(prometheus.ClientAPIRequestsCounter,
-				promhttp.InstrumentRoundTripperTrace(prometheus.ClientTrace,
-					promhttp.InstrumentRoundTripperDuration(prometheus.ClientHistVec, tr),
-				),
-			),
-		)
-		postMgr.httpClient = &http.Client{
-			Transport: instrumentedRoundTripper,
-			Timeout:   timeoutLarge,
-		}
-	} else {
-		postMgr.httpClient = &http.Client{
-			Transport: tr,
-			Timeout:   timeoutLarge,
-		}
-	}
+	params := postMgr.ClientParams
+	params.TrustedCerts = postMgr.TrustedCerts
+	params.SSLInsecure = postMgr.SSLInsecure
+	params.Metrics = postMgr.HTTPClientMetrics
+	postMgr.httpClient = httpclient.NewClient(params)
+}
+
+// withRequestContext returns the request with the timeout of the REST calls, REST calls in flight are cancelled
+// when the agent is stopped
+func (postMgr *PostManager) withRequestContext(req *http.Request) (*http.Request, context.CancelFunc) {
+	return httpclient.WithRequestContext(postMgr.ctx, req, postMgr.ClientParams.GetRequestTimeout())
 }
 
 func (postMgr *PostManager) getAS3APIURL(tenants []string) string {
-	apiURL := postMgr.BIGIPURL + "/mgmt/shared/appsvcs/declare/" + strings.Join(tenants, ",")
+	apiURL := postMgr.getAPIBaseURL() + "/mgmt/shared/appsvcs/declare/"
+	// BIG-IQ deploys the tenants of the declaration to the target BIG-IP without the tenants in URL
+	if postMgr.BIGIQURL == "" {
+		apiURL += strings.Join(tenants, ",")
+	}
+	if postMgr.AS3OptimisticLock {
+		// AS3 returns the optimistic lock keys of the tenants only with showHash
+		apiURL += "?showHash=true"
+	}
 	return apiURL
 }
 
+// getAPIBaseURL returns the BIG-IQ URL when the declarations are posted through BIG-IQ, BIG-IP URL otherwise.
+// Only the AS3 declarations, their tasks and the AS3 info are served by BIG-IQ
+func (postMgr *PostManager) getAPIBaseURL() string {
+	if postMgr.BIGIQURL != "" {
+		return postMgr.BIGIQURL
+	}
+	return postMgr.BIGIPURL
+}
+
+// getDeviceBaseURL returns the URL of the target BIG-IP, REST calls reading or modifying the configuration of
+// BIG-IP are sent to BIG-IP as BIG-IQ doesn't proxy them to the target
+func (postMgr *PostManager) getDeviceBaseURL() string {
+	return postMgr.BIGIPURL
+}
+
+// isDeviceReachable checks whether the REST calls can be sent to the target BIG-IP, BIG-IP credentials are
+// not required when the declarations are posted through BIG-IQ
+func (postMgr *PostManager) isDeviceReachable() bool {
+	return postMgr.BIGIQURL == "" || (postMgr.BIGIPURL != "" && postMgr.BIGIPUsername != "" &&
+		postMgr.BIGIPPassword != "")
+}
+
 func (postMgr *PostManager) getAS3TaskIdURL(taskId string) string {
-	apiURL := postMgr.BIGIPURL + "/mgmt/shared/appsvcs/task/" + taskId
+	apiURL := postMgr.getAPIBaseURL() + "/mgmt/shared/appsvcs/task/" + taskId
 	return apiURL
 }
 
@@ -109,7 +149,7 @@ func (postMgr *PostManager) publishConfig(cfg agentConfig) {
 
 func (postMgr *PostManager) postConfig(cfg *agentConfig) {
 	// log as3 request if it's set
-	if postMgr.LogAS3Request {
+	if postMgr.isAS3RequestLogged() {
 		postMgr.logAS3Request(cfg.data)
 	}
 	httpReqBody := bytes.NewBuffer([]byte(cfg.data))
@@ -119,7 +159,7 @@ func (postMgr *PostManager) postConfig(cfg *agentConfig) {
 		return
 	}
 	log.Debugf("[AS3] posting request to %v", cfg.as3APIURL)
-	req.SetBasicAuth(postMgr.BIGIPUsername, postMgr.BIGIPPassword)
+	postMgr.setAuthHeader(req)
 
 	httpResp, responseMap := postMgr.httpPOST(req)
 	if httpResp == nil || responseMap == nil {
@@ -156,23 +196,29 @@ func updateTenantDeletion(tenant string, declaration map[string]interface{}) boo
 }
 
 func (postMgr *PostManager) httpPOST(request *http.Request) (*http.Response, map[string]interface{}) {
+	request, cancel := postMgr.withRequestContext(request)
+	defer cancel()
 	httpResp, err := postMgr.httpClient.Do(request)
+	// REST call errors of the unreachable BIG-IP open the circuit breaker
+	postMgr.recordRESTCallResult(err == nil)
 	if err != nil {
 		log.Errorf("[AS3] REST call error: %v ", err)
 		return nil, nil
 	}
 	defer httpResp.Body.Close()
+	postMgr.checkBigIQAuthToken(httpResp)
 
 	body, err := ioutil.ReadAll(httpResp.Body)
 	if err != nil {
 		log.Errorf("[AS3] REST call response error: %v ", err)
 		return nil, nil
 	}
+	postMgr.checkRESTFrameworkHealth(httpResp.StatusCode, body)
 	var response map[string]interface{}
 	err = json.Unmarshal(body, &response)
 	if err != nil {
 		log.Errorf("[AS3] Response body unmarshal failed: %v\n", err)
-		if postMgr.LogAS3Response {
+		if postMgr.isAS3ResponseLogged() {
 			log.Errorf("[AS3] Raw response from Big-IP: %v", string(body))
 		}
 		return nil, nil
@@ -183,10 +229,10 @@ func (postMgr *PostManager) httpPOST(request *http.Request) (*http.Response, map
 func (postMgr *PostManager) updateTenantResponse(code int, id string, tenant string, isDeleted bool) {
 	// Update status for a specific tenant if mentioned, else update the response for all tenants
 	if tenant != "" {
-		postMgr.tenantResponseMap[tenant] = tenantResponse{code, id, isDeleted}
+		postMgr.tenantResponseMap[tenant] = tenantResponse{code, id, isDeleted, ""}
 	} else {
 		for tenant := range postMgr.tenantResponseMap {
-			postMgr.tenantResponseMap[tenant] = tenantResponse{code, id, false}
+			postMgr.tenantResponseMap[tenant] = tenantResponse{code, id, false, ""}
 		}
 	}
 }
@@ -199,6 +245,7 @@ func (postMgr *PostManager) handleResponseStatusOK(responseMap map[string]interf
 		v := value.(map[string]interface{})
 		log.Debugf("[AS3] Response from BIG-IP: code: %v --- tenant:%v --- message: %v", v["code"], v["tenant"], v["message"])
 		postMgr.updateTenantResponse(int(v["code"].(float64)), "", v["tenant"].(string), updateTenantDeletion(v["tenant"].(string), declaration))
+		postMgr.updateTenantLockKey(v["tenant"].(string), declaration)
 	}
 }
 
@@ -209,7 +256,7 @@ func (postMgr *PostManager) getTenantConfigStatus(id string) {
 		return
 	}
 	log.Debugf("[AS3] posting request with taskId to %v", postMgr.getAS3TaskIdURL(id))
-	req.SetBasicAuth(postMgr.BIGIPUsername, postMgr.BIGIPPassword)
+	postMgr.setAuthHeader(req)
 
 	httpResp, responseMap := postMgr.httpPOST(req)
 	if httpResp == nil || responseMap == nil {
@@ -226,6 +273,12 @@ func (postMgr *PostManager) getTenantConfigStatus(id string) {
 			} else {
 				// reset task id, so that any failed tenants will go to post call in the next retry
 				postMgr.updateTenantResponse(int(v["code"].(float64)), "", v["tenant"].(string), updateTenantDeletion(v["tenant"].(string), declaration))
+				if v["code"].(float64) == 200 {
+					postMgr.updateTenantLockKey(v["tenant"].(string), declaration)
+				} else {
+					postMgr.updateTenantErrorMessage(v)
+					postMgr.handleTenantLockConflict(v)
+				}
 				if _, ok := v["response"]; ok {
 					log.Debugf("[AS3] Response from BIG-IP: code: %v --- tenant:%v --- message: %v %v", v["code"], v["tenant"], v["message"], v["response"])
 				} else {
@@ -248,8 +301,11 @@ func (postMgr *PostManager) handleMultiStatus(responseMap map[string]interface{}
 			if v["code"].(float64) != 200 {
 				postMgr.updateTenantResponse(int(v["code"].(float64)), "", v["tenant"].(string), false)
 				log.Errorf("[AS3] Error response from BIG-IP: code: %v --- tenant:%v --- message: %v", v["code"], v["tenant"], v["message"])
+				postMgr.updateTenantErrorMessage(v)
+				postMgr.handleTenantLockConflict(v)
 			} else {
 				postMgr.updateTenantResponse(int(v["code"].(float64)), "", v["tenant"].(string), updateTenantDeletion(v["tenant"].(string), declaration))
+				postMgr.updateTenantLockKey(v["tenant"].(string), declaration)
 				log.Debugf("[AS3] Response from BIG-IP: code: %v --- tenant:%v --- message: %v", v["code"], v["tenant"], v["message"])
 			}
 		}
@@ -260,6 +316,12 @@ func (postMgr *PostManager) handleResponseAccepted(responseMap map[string]interf
 	// traverse all response results
 	if respId, ok := (responseMap["id"]).(string); ok {
 		postMgr.updateTenantResponse(http.StatusAccepted, respId, "", false)
+		if postMgr.BIGIQURL != "" {
+			// BIG-IQ task deploys the declaration to the target BIG-IP
+			log.Debugf("[AS3] Response from BIG-IQ: code 202 id %v for target %v, waiting %v seconds to poll response",
+				respId, postMgr.getBigIQTarget(), timeoutMedium)
+			return
+		}
 		log.Debugf("[AS3] Response from BIG-IP: code 201 id %v, waiting %v seconds to poll response", respId, timeoutMedium)
 	}
 }
@@ -278,14 +340,14 @@ func (postMgr *PostManager) handleResponseStatusNotFound(responseMap map[string]
 	} else {
 		log.Errorf("[AS3] Big-IP Responded with error code: %v", http.StatusNotFound)
 	}
-	if postMgr.LogAS3Response {
+	if postMgr.isAS3ResponseLogged() {
 		postMgr.logAS3Response(responseMap)
 	}
 	postMgr.updateTenantResponse(http.StatusNotFound, "", "", false)
 }
 
 func (postMgr *PostManager) handleResponseOthers(responseMap map[string]interface{}, cfg *agentConfig) {
-	if postMgr.LogAS3Response {
+	if postMgr.isAS3ResponseLogged() {
 		postMgr.logAS3Response(responseMap)
 	}
 	if results, ok := (responseMap["results"]).([]interface{}); ok {
@@ -293,6 +355,8 @@ func (postMgr *PostManager) handleResponseOthers(responseMap map[string]interfac
 			v := value.(map[string]interface{})
 			log.Errorf("[AS3] Response from BIG-IP: code: %v --- tenant:%v --- message: %v", v["code"], v["tenant"], v["message"])
 			postMgr.updateTenantResponse(int(v["code"].(float64)), "", v["tenant"].(string), false)
+			postMgr.updateTenantErrorMessage(v)
+			postMgr.handleTenantLockConflict(v)
 		}
 	} else if err, ok := (responseMap["error"]).(map[string]interface{}); ok {
 		log.Errorf("[AS3] Big-IP Responded with error code: %v", err["code"])
@@ -300,9 +364,89 @@ func (postMgr *PostManager) handleResponseOthers(responseMap map[string]interfac
 	} else {
 		log.Errorf("[AS3] Big-IP Responded with code: %v", responseMap["code"])
 		postMgr.updateTenantResponse(int(responseMap["code"].(float64)), "", "", false)
+		if errs, ok := responseMap["errors"].([]interface{}); ok {
+			postMgr.updateDeclarationErrorMessages(errs)
+		}
 	}
 }
 
+// updateTenantErrorMessage records the error message of the failed tenant in the AS3 result,
+// the message is mapped to the resources of the objects referred in it
+func (postMgr *PostManager) updateTenantErrorMessage(result map[string]interface{}) {
+	tenant, _ := result["tenant"].(string)
+	resp, ok := postMgr.tenantResponseMap[tenant]
+	if !ok {
+		return
+	}
+	resp.message = fmt.Sprintf("%v", result["message"])
+	if response, ok := result["response"]; ok {
+		resp.message = fmt.Sprintf("%v: %v", resp.message, response)
+	}
+	postMgr.tenantResponseMap[tenant] = resp
+}
+
+// updateDeclarationErrorMessages records the errors of the invalid declaration as the error messages of
+// the tenants referred in the paths of the errors
+func (postMgr *PostManager) updateDeclarationErrorMessages(errs []interface{}) {
+	for tenant, resp := range postMgr.tenantResponseMap {
+		var msgs []string
+		for _, err := range errs {
+			if msg := fmt.Sprintf("%v", err); strings.HasPrefix(msg, "/"+tenant+"/") {
+				msgs = append(msgs, msg)
+			}
+		}
+		if len(msgs) > 0 {
+			resp.message = strings.Join(msgs, "; ")
+			postMgr.tenantResponseMap[tenant] = resp
+		}
+	}
+}
+
+// updateTenantLockKey stores the optimistic lock key of the tenant returned by AS3,
+// the key is posted with the next declaration of the tenant
+func (postMgr *PostManager) updateTenantLockKey(tenant string, declaration map[string]interface{}) {
+	if !postMgr.AS3OptimisticLock {
+		return
+	}
+	if postMgr.tenantLockKeys == nil {
+		postMgr.tenantLockKeys = make(map[string]string)
+	}
+	tenantDecl, ok := declaration[tenant].(map[string]interface{})
+	if !ok {
+		// Tenant is deleted
+		delete(postMgr.tenantLockKeys, tenant)
+		return
+	}
+	if key, ok := tenantDecl["optimisticLockKey"].(string); ok && key != "" {
+		postMgr.tenantLockKeys[tenant] = key
+	}
+}
+
+// isTenantLockConflict checks whether AS3 rejected the tenant as it is modified on BIG-IP
+// after the optimistic lock key was returned
+func isTenantLockConflict(result map[string]interface{}) bool {
+	response := strings.ToLower(fmt.Sprintf("%v %v", result["message"], result["response"]))
+	return strings.Contains(response, "optimistic")
+}
+
+// handleTenantLockConflict alerts when the tenant is modified on BIG-IP by another client
+// and re-reads the lock key of the tenant, so that the retry reconciles the tenant with the CIS configuration
+func (postMgr *PostManager) handleTenantLockConflict(result map[string]interface{}) {
+	tenant, _ := result["tenant"].(string)
+	if !postMgr.AS3OptimisticLock || tenant == "" || !isTenantLockConflict(result) {
+		return
+	}
+	log.Warningf("[AS3] Tenant %v is modified on BIG-IP by another client since the last declaration posted by CIS", tenant)
+	prometheus.AS3OptimisticLockConflicts.WithLabelValues(tenant).Inc()
+
+	declaration, err := postMgr.GetAS3DeclarationFromBigIP()
+	if err != nil {
+		log.Errorf("[AS3] Could not fetch the optimistic lock key of tenant %v from BIG-IP: %v", tenant, err)
+		return
+	}
+	postMgr.updateTenantLockKey(tenant, declaration)
+}
+
 func (postMgr *PostManager) GetBigipAS3Version() (string, string, string, error) {
 	url := postMgr.getAS3VersionURL()
 	req, err := http.NewRequest("GET", url, nil)
@@ -312,9 +456,9 @@ func (postMgr *PostManager) GetBigipAS3Version() (string, string, string, error)
 	}
 
 	log.Debugf("[AS3] posting GET BIGIP AS3 Version request on %v", url)
-	req.SetBasicAuth(postMgr.BIGIPUsername, postMgr.BIGIPPassword)
+	postMgr.setAuthHeader(req)
 
-	httpResp, responseMap := postMgr.httpReq(req)
+	httpResp, responseMap := postMgr.httpCachedReq(req)
 	if httpResp == nil || responseMap == nil {
 		return "", "", "", fmt.Errorf("Internal Error")
 	}
@@ -349,9 +493,9 @@ func (postMgr *PostManager) GetBigipRegKey() (string, error) {
 	}
 
 	log.Debugf("Posting GET BIGIP Reg Key request on %v", url)
-	req.SetBasicAuth(postMgr.BIGIPUsername, postMgr.BIGIPPassword)
+	postMgr.setAuthHeader(req)
 
-	httpResp, responseMap := postMgr.httpReq(req)
+	httpResp, responseMap := postMgr.httpCachedReq(req)
 	if httpResp == nil || responseMap == nil {
 		return "", fmt.Errorf("Internal Error")
 	}
@@ -372,7 +516,14 @@ func (postMgr *PostManager) GetBigipRegKey() (string, error) {
 }
 
 func (postMgr *PostManager) GetAS3DeclarationFromBigIP() (map[string]interface{}, error) {
-	url := postMgr.getAS3APIURL([]string{})
+	if !postMgr.isDeviceReachable() {
+		return nil, fmt.Errorf("BIG-IP credentials are not provided to fetch the declaration from the target BIG-IP")
+	}
+	// declarations of the target BIG-IP are fetched from BIG-IP, BIG-IQ returns the declarations of all its targets
+	url := postMgr.getDeviceBaseURL() + "/mgmt/shared/appsvcs/declare/"
+	if postMgr.AS3OptimisticLock {
+		url += "?showHash=true"
+	}
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		log.Errorf("[AS3] Creating new HTTP request error: %v ", err)
@@ -380,7 +531,7 @@ func (postMgr *PostManager) GetAS3DeclarationFromBigIP() (map[string]interface{}
 	}
 
 	log.Debugf("[AS3] posting GET BIGIP AS3 declaration request on %v", url)
-	req.SetBasicAuth(postMgr.BIGIPUsername, postMgr.BIGIPPassword)
+	postMgr.setAuthHeader(req)
 
 	httpResp, responseMap := postMgr.httpReq(req)
 	if httpResp == nil || responseMap == nil {
@@ -401,23 +552,27 @@ func (postMgr *PostManager) GetAS3DeclarationFromBigIP() (map[string]interface{}
 }
 
 func (postMgr *PostManager) httpReq(request *http.Request) (*http.Response, map[string]interface{}) {
+	request, cancel := postMgr.withRequestContext(request)
+	defer cancel()
 	httpResp, err := postMgr.httpClient.Do(request)
 	if err != nil {
 		log.Errorf("REST call error: %v ", err)
 		return nil, nil
 	}
 	defer httpResp.Body.Close()
+	postMgr.checkBigIQAuthToken(httpResp)
 
 	body, err := ioutil.ReadAll(httpResp.Body)
 	if err != nil {
 		log.Errorf("REST call response error: %v ", err)
 		return nil, nil
 	}
+	postMgr.checkRESTFrameworkHealth(httpResp.StatusCode, body)
 	var response map[string]interface{}
 	err = json.Unmarshal(body, &response)
 	if err != nil {
 		log.Errorf("Response body unmarshal failed: %v\n", err)
-		if postMgr.LogAS3Response {
+		if postMgr.isAS3ResponseLogged() {
 			log.Errorf("Raw response from Big-IP: %v", string(body))
 		}
 		return nil, nil
@@ -426,15 +581,113 @@ func (postMgr *PostManager) httpReq(request *http.Request) (*http.Response, map[
 }
 
 func (postMgr *PostManager) getAS3VersionURL() string {
-	apiURL := postMgr.BIGIPURL + "/mgmt/shared/appsvcs/info"
+	apiURL := postMgr.getAPIBaseURL() + "/mgmt/shared/appsvcs/info"
 	return apiURL
 }
 
 func (postMgr *PostManager) getBigipRegKeyURL() string {
-	apiURL := postMgr.BIGIPURL + "/mgmt/tm/shared/licensing/registration"
+	apiURL := postMgr.getDeviceBaseURL() + "/mgmt/tm/shared/licensing/registration"
 	return apiURL
 }
 
+// setAuthHeader sets the BIG-IQ auth token for the requests to BIG-IQ when the declarations are posted through
+// BIG-IQ, basic auth of the BIG-IP user for the requests to BIG-IP
+func (postMgr *PostManager) setAuthHeader(req *http.Request) {
+	if postMgr.BIGIQURL == "" || !strings.HasPrefix(req.URL.String(), postMgr.BIGIQURL) {
+		req.SetBasicAuth(postMgr.BIGIPUsername, postMgr.BIGIPPassword)
+		return
+	}
+	token, err := postMgr.getBigIQAuthToken()
+	if err != nil {
+		log.Errorf("[AS3] Unable to get the BIG-IQ auth token: %v", err)
+		return
+	}
+	req.Header.Set(bigIQAuthTokenHeader, token)
+}
+
+// getBigIQAuthToken returns the BIG-IQ auth token, BIG-IQ is logged in again when the token is about to expire
+func (postMgr *PostManager) getBigIQAuthToken() (string, error) {
+	postMgr.bigIQTokenLock.Lock()
+	defer postMgr.bigIQTokenLock.Unlock()
+
+	if postMgr.bigIQToken != "" && time.Now().Before(postMgr.bigIQTokenExpiry) {
+		return postMgr.bigIQToken, nil
+	}
+
+	loginProvider := postMgr.BIGIQLoginProvider
+	if loginProvider == "" {
+		loginProvider = "local"
+	}
+	body, _ := json.Marshal(map[string]string{
+		"username":          postMgr.BIGIQUsername,
+		"password":          postMgr.BIGIQPassword,
+		"loginProviderName": loginProvider,
+	})
+	loginURL := postMgr.BIGIQURL + "/mgmt/shared/authn/login"
+	req, err := http.NewRequest("POST", loginURL, bytes.NewBuffer(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	log.Debugf("[AS3] posting BIG-IQ login request on %v", loginURL)
+
+	req, cancel := postMgr.withRequestContext(req)
+	defer cancel()
+	httpResp, err := postMgr.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer httpResp.Body.Close()
+	respBody, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return "", err
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("login failed with status code %v", httpResp.StatusCode)
+	}
+
+	var response struct {
+		Token struct {
+			Token   string `json:"token"`
+			Timeout int    `json:"timeout"`
+		} `json:"token"`
+	}
+	if err = json.Unmarshal(respBody, &response); err != nil || response.Token.Token == "" {
+		return "", fmt.Errorf("invalid login response from BIG-IQ")
+	}
+	timeout := time.Duration(response.Token.Timeout) * time.Second
+	if timeout == 0 {
+		timeout = bigIQAuthTokenTimeout
+	}
+	postMgr.bigIQToken = response.Token.Token
+	// Refresh the token ahead of its expiry, so that the requests in progress are not rejected
+	postMgr.bigIQTokenExpiry = time.Now().Add(timeout - timeout/10)
+	return postMgr.bigIQToken, nil
+}
+
+// checkBigIQAuthToken discards the BIG-IQ auth token rejected by BIG-IQ, so that the next request logs in again
+func (postMgr *PostManager) checkBigIQAuthToken(httpResp *http.Response) {
+	if postMgr.BIGIQURL == "" || httpResp.StatusCode != http.StatusUnauthorized {
+		return
+	}
+	log.Debugf("[AS3] BIG-IQ auth token is rejected, logging in to BIG-IQ again")
+	postMgr.bigIQTokenLock.Lock()
+	postMgr.bigIQToken = ""
+	postMgr.bigIQTokenLock.Unlock()
+}
+
+// getBigIQTarget returns the target of the declarations posted through BIG-IQ, the BIG-IP managed by BIG-IQ
+func (postMgr *PostManager) getBigIQTarget() map[string]interface{} {
+	host := strings.TrimPrefix(postMgr.BIGIPURL, "https://")
+	if u, err := url.Parse(postMgr.BIGIPURL); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+	if net.ParseIP(host) != nil {
+		return map[string]interface{}{"address": host}
+	}
+	return map[string]interface{}{"hostname": host}
+}
+
 func (postMgr *PostManager) logAS3Response(responseMap map[string]interface{}) {
 	// removing the certificates/privateKey from response log
 	if declaration, ok := (responseMap["declaration"]).([]interface{}); ok {
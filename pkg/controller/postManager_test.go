@@ -1,10 +1,19 @@
 package controller
 
 import (
+	"bytes"
 	"fmt"
+	bigIPPrometheus "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/prometheus"
+	mockhc "github.com/f5devcentral/mockhttpclient"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"k8s.io/client-go/tools/record"
 )
 
 var _ = Describe("PostManager Tests", func() {
@@ -128,6 +137,31 @@ var _ = Describe("PostManager Tests", func() {
 			Expect(mockPM.tenantResponseMap[tnt].agentResponseCode).To(Equal(http.StatusAlreadyReported))
 		})
 
+		It("Records the error messages of the failed tenants", func() {
+			tnt := "test"
+			mockPM.setResponses([]responceCtx{
+				{
+					tenant: tnt,
+					status: http.StatusUnprocessableEntity,
+					body: fmt.Sprintf(`{"results":[{"code":422,"message":"declaration failed", "response": "01020066:3: The requested Pool (/%s/Shared/svc1_80_default) already exists", "tenant": "%s"}]}`,
+						tnt, tnt),
+				},
+				{
+					tenant: tnt,
+					status: http.StatusUnprocessableEntity,
+					body:   fmt.Sprintf(`{"code":422,"message":"declaration is invalid","errors":["/%s/Shared/crd_1_2_3_4_80/virtualAddresses/0: should match format \"f5ip\"","/other/Shared/app: invalid"]}`, tnt),
+				},
+			}, http.MethodPost)
+			mockPM.tenantResponseMap = map[string]tenantResponse{tnt: {}}
+			mockPM.publishConfig(agentCfg)
+			Expect(mockPM.tenantResponseMap[tnt].message).To(Equal(
+				"declaration failed: 01020066:3: The requested Pool (/test/Shared/svc1_80_default) already exists"))
+
+			mockPM.publishConfig(agentCfg)
+			Expect(mockPM.tenantResponseMap[tnt].message).To(Equal(
+				`/test/Shared/crd_1_2_3_4_80/virtualAddresses/0: should match format "f5ip"`))
+		})
+
 		It("Handle Multiple HTTP Responses", func() {
 			tnt := "test"
 			mockPM.setResponses([]responceCtx{{
@@ -139,6 +173,136 @@ var _ = Describe("PostManager Tests", func() {
 			mockPM.publishConfig(agentCfg)
 			Expect(len(mockPM.tenantResponseMap)).To(Equal(1), "Posting Failed")
 		})
+
+		It("Handle Optimistic Lock Keys", func() {
+			tnt := "test"
+			mockPM.AS3OptimisticLock = true
+			Expect(mockPM.getAS3APIURL([]string{tnt})).To(Equal("bigip.com/mgmt/shared/appsvcs/declare/test?showHash=true"))
+
+			newResponse := func(status int, body string) *http.Response {
+				return &http.Response{
+					StatusCode: status,
+					Header:     http.Header{},
+					Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+				}
+			}
+			responseMap := mockhc.ResponseConfigMap{
+				http.MethodPost: &mockhc.ResponseConfig{
+					Responses: []*http.Response{
+						newResponse(http.StatusOK, fmt.Sprintf(`{"results":[{"code":200,"message":"success", "tenant": "%s"}],"declaration": {"%s": {"class": "Tenant", "optimisticLockKey": "key1"}}}`, tnt, tnt)),
+						newResponse(http.StatusUnprocessableEntity, fmt.Sprintf(`{"results":[{"code":422,"message":"declaration failed", "response": "optimisticLockKey does not match", "tenant": "%s"}]}`, tnt)),
+					},
+				},
+				http.MethodGet: &mockhc.ResponseConfig{
+					Responses: []*http.Response{
+						newResponse(http.StatusOK, fmt.Sprintf(`{"class": "ADC", "%s": {"class": "Tenant", "optimisticLockKey": "key2"}}`, tnt)),
+					},
+				},
+			}
+			client, _ := mockhc.NewMockHTTPClient(responseMap)
+			mockPM.httpClient = client
+
+			mockPM.publishConfig(agentCfg)
+			Expect(mockPM.tenantResponseMap[tnt].agentResponseCode).To(Equal(http.StatusOK))
+			Expect(mockPM.tenantLockKeys[tnt]).To(Equal("key1"), "Lock key not stored")
+
+			// Tenant modified on BIG-IP by another client
+			mockPM.publishConfig(agentCfg)
+			Expect(mockPM.tenantResponseMap[tnt].agentResponseCode).To(Equal(http.StatusUnprocessableEntity))
+			Expect(mockPM.tenantLockKeys[tnt]).To(Equal("key2"), "Lock key not re-read from BIG-IP")
+		})
+	})
+
+	Describe("BIG-IQ", func() {
+		BeforeEach(func() {
+			mockPM.BIGIPURL = "https://10.1.1.1"
+			mockPM.BIGIQURL = "https://bigiq.com"
+			mockPM.BIGIQUsername = "user"
+			mockPM.BIGIQPassword = "pswd"
+		})
+
+		It("Post Config through BIG-IQ", func() {
+			tnt := "test"
+			Expect(mockPM.getAS3APIURL([]string{tnt})).To(Equal("https://bigiq.com/mgmt/shared/appsvcs/declare/"))
+			Expect(mockPM.getAS3TaskIdURL("100")).To(Equal("https://bigiq.com/mgmt/shared/appsvcs/task/100"))
+			Expect(mockPM.getBigIQTarget()).To(Equal(map[string]interface{}{"address": "10.1.1.1"}))
+			mockPM.BIGIPURL = "https://bigip.com:8443"
+			Expect(mockPM.getBigIQTarget()).To(Equal(map[string]interface{}{"hostname": "bigip.com"}))
+
+			newResponse := func(status int, body string) *http.Response {
+				return &http.Response{
+					StatusCode: status,
+					Header:     http.Header{},
+					Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+				}
+			}
+			responseMap := mockhc.ResponseConfigMap{
+				http.MethodPost: &mockhc.ResponseConfig{
+					Responses: []*http.Response{
+						newResponse(http.StatusOK, `{"token": {"token": "token1", "timeout": 1200}}`),
+						newResponse(http.StatusAccepted, `{"id": "100"}`),
+						newResponse(http.StatusUnauthorized, `{"code": 401}`),
+						newResponse(http.StatusOK, `{"token": {"token": "token2", "timeout": 1200}}`),
+						newResponse(http.StatusOK, fmt.Sprintf(`{"results":[{"code":200,"message":"success", "tenant": "%s"}],"declaration": {"%s": {"class": "Tenant"}}}`, tnt, tnt)),
+					},
+				},
+			}
+			client, _ := mockhc.NewMockHTTPClient(responseMap)
+			mockPM.httpClient = client
+			agentCfg := agentConfig{
+				data:      "{}",
+				as3APIURL: mockPM.getAS3APIURL([]string{tnt}),
+			}
+			mockPM.tenantResponseMap = map[string]tenantResponse{tnt: {}}
+
+			// BIG-IQ task deploying the declaration to BIG-IP
+			mockPM.publishConfig(agentCfg)
+			Expect(mockPM.bigIQToken).To(Equal("token1"))
+			Expect(mockPM.tenantResponseMap[tnt].taskId).To(Equal("100"))
+
+			// Expired token is discarded
+			mockPM.publishConfig(agentCfg)
+			Expect(mockPM.bigIQToken).To(BeEmpty())
+
+			mockPM.publishConfig(agentCfg)
+			Expect(mockPM.bigIQToken).To(Equal("token2"))
+			Expect(mockPM.tenantResponseMap[tnt].agentResponseCode).To(Equal(http.StatusOK))
+		})
+
+		It("Handle BIG-IQ login failure", func() {
+			mockPM.setResponses([]responceCtx{{
+				tenant: "test",
+				status: http.StatusUnauthorized,
+				body:   `{"code": 401}`,
+			}}, http.MethodPost)
+			_, err := mockPM.getBigIQAuthToken()
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("Sends the REST calls other than the AS3 declarations to the target BIG-IP", func() {
+			Expect(mockPM.getAS3VersionURL()).To(Equal("https://bigiq.com/mgmt/shared/appsvcs/info"))
+			Expect(mockPM.getBigipRegKeyURL()).To(Equal("https://10.1.1.1/mgmt/tm/shared/licensing/registration"))
+			Expect(mockPM.getBigIPProvisionURL()).To(Equal("https://10.1.1.1/mgmt/tm/sys/provision"))
+			Expect(mockPM.getBigIPObjectURL(bigipObjectRef{kind: bigipObjectIRule, path: "/Common/irule1"})).To(
+				Equal("https://10.1.1.1/mgmt/tm/ltm/rule/~Common~irule1?$select=name"))
+			Expect(mockPM.getPoolMemberStatsURL("test", "pool1")).To(HavePrefix("https://10.1.1.1/mgmt/tm/"))
+
+			// BIG-IP is not reachable without the BIG-IP credentials
+			Expect(mockPM.isDeviceReachable()).To(BeFalse())
+			_, err := mockPM.GetAS3DeclarationFromBigIP()
+			Expect(err).NotTo(BeNil())
+
+			// BIG-IP requests are authenticated with the BIG-IP user instead of the BIG-IQ token
+			mockPM.BIGIPUsername = "admin"
+			mockPM.BIGIPPassword = "admin"
+			Expect(mockPM.isDeviceReachable()).To(BeTrue())
+			req, _ := http.NewRequest("GET", mockPM.getBigipRegKeyURL(), nil)
+			mockPM.setAuthHeader(req)
+			user, _, ok := req.BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(user).To(Equal("admin"))
+			Expect(req.Header.Get(bigIQAuthTokenHeader)).To(BeEmpty())
+		})
 	})
 
 	Describe("BIGIP Queries", func() {
@@ -268,4 +432,284 @@ var _ = Describe("PostManager Tests", func() {
 			mockPM.logAS3Request(as3config)
 		})
 	})
+
+	Describe("BIGIP Response Cache", func() {
+		BeforeEach(func() {
+			mockPM.BIGIPURL = "bigip.com"
+		})
+		It("Caches the responses of the GET requests with TTL", func() {
+			mockPM.BigIPResponseCacheTTL = 30
+			mockPM.setResponses([]responceCtx{
+				{
+					status: http.StatusServiceUnavailable,
+					body:   fmt.Sprintf(`{"code":%d}`, http.StatusServiceUnavailable),
+				},
+				{
+					status: http.StatusOK,
+					body:   `{"version":"v1", "release":"r1", "schemaCurrent":"3.40.0"}`,
+				},
+				{
+					status: http.StatusOK,
+					body:   `{"version":"v2", "release":"r1", "schemaCurrent":"3.41.0"}`,
+				},
+			}, http.MethodGet)
+			// failed responses are not cached
+			_, _, _, err := mockPM.GetBigipAS3Version()
+			Expect(err).NotTo(BeNil())
+			version, _, _, err := mockPM.GetBigipAS3Version()
+			Expect(err).To(BeNil())
+			Expect(version).To(Equal("v1"))
+			version, _, schema, err := mockPM.GetBigipAS3Version()
+			Expect(err).To(BeNil())
+			Expect(version).To(Equal("v1"), "Response not served from the cache")
+			Expect(schema).To(Equal("3.40.0"))
+
+			// BIG-IP is queried again once the TTL is elapsed
+			url := mockPM.getAS3VersionURL()
+			cached := mockPM.responseCache.responses[url]
+			cached.expiry = time.Now().Add(-time.Second)
+			mockPM.responseCache.responses[url] = cached
+			version, _, _, err = mockPM.GetBigipAS3Version()
+			Expect(err).To(BeNil())
+			Expect(version).To(Equal("v2"))
+
+			mockPM.purgeBigIPResponse(url)
+			Expect(mockPM.responseCache.responses).NotTo(HaveKey(url))
+		})
+
+		It("Caches the not found responses without sharing the cached response", func() {
+			mockPM.BigIPResponseCacheTTL = 30
+			mockPM.setResponses([]responceCtx{
+				{
+					status: http.StatusNotFound,
+					body:   fmt.Sprintf(`{"code":%d}`, http.StatusNotFound),
+				},
+			}, http.MethodGet)
+			for i := 0; i < 2; i++ {
+				_, _, _, err := mockPM.GetBigipAS3Version()
+				Expect(err).To(MatchError(ContainSubstring("AS3 RPM is not installed")))
+			}
+			Expect(mockPM.responseCache.responses[mockPM.getAS3VersionURL()].response["code"]).To(
+				Equal(float64(http.StatusNotFound)))
+		})
+	})
+
+	Describe("Retry Policy and Circuit Breaker", func() {
+		It("Computes the retry interval with backoff and jitter", func() {
+			Expect(mockPM.getRetryInterval(3)).To(Equal(timeoutMedium))
+			mockPM.AS3RetryInterval = 10
+			mockPM.AS3RetryMaxInterval = 60
+			Expect(mockPM.getRetryInterval(0)).To(Equal(10 * time.Second))
+			Expect(mockPM.getRetryInterval(1)).To(Equal(20 * time.Second))
+			Expect(mockPM.getRetryInterval(3)).To(Equal(60 * time.Second))
+			mockPM.AS3RetryJitter = 0.5
+			interval := mockPM.getRetryInterval(0)
+			Expect(interval >= 10*time.Second && interval <= 15*time.Second).To(BeTrue())
+
+			// Accepted tenants are polled irrespective of the retries
+			mockPM.AS3MaxRetries = 2
+			Expect(mockPM.isRetryExhausted(&tenantParams{retries: 1})).To(BeFalse())
+			Expect(mockPM.isRetryExhausted(&tenantParams{retries: 2})).To(BeTrue())
+			Expect(mockPM.isRetryExhausted(&tenantParams{tenantResponse: tenantResponse{taskId: "100"}, retries: 2})).To(BeFalse())
+		})
+
+		It("Opens the circuit breaker for the unreachable BIG-IP", func() {
+			agent := &Agent{PostManager: mockPM.PostManager, postChan: make(chan ResourceConfigRequest, 1),
+				cachedTenantDeclMap: map[string]as3Tenant{"test": {"class": "Tenant"}}}
+			// Circuit breaker is disabled by default
+			mockPM.recordRESTCallResult(false)
+			Expect(mockPM.isCircuitOpen()).To(BeFalse())
+
+			mockPM.CircuitBreakerThreshold = 2
+			mockPM.recordRESTCallResult(false)
+			Expect(mockPM.isCircuitOpen()).To(BeFalse())
+			mockPM.recordRESTCallResult(false)
+			Expect(mockPM.isCircuitOpen()).To(BeTrue())
+			rec := httptest.NewRecorder()
+			agent.readinessHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/ready", nil))
+			Expect(rec.Code).To(Equal(http.StatusServiceUnavailable))
+			Expect(mockPM.isResyncRequired()).To(BeFalse())
+
+			// Circuit breaker is closed once BIG-IP is reachable and the tenants are posted again
+			mockPM.recordRESTCallResult(true)
+			Expect(mockPM.isCircuitOpen()).To(BeFalse())
+			rec = httptest.NewRecorder()
+			agent.readinessHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/ready", nil))
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(mockPM.isResyncRequired()).To(BeTrue())
+			Expect(mockPM.isResyncRequired()).To(BeFalse())
+
+			agent.lastConfig = ResourceConfigRequest{ltmConfig: make(LTMConfig), reqId: 5}
+			agent.resyncTenants()
+			Expect(agent.cachedTenantDeclMap).To(HaveKey("test"))
+			Expect(agent.cachedTenantDeclMap["test"]).To(BeNil())
+			rsConfig := <-agent.postChan
+			Expect(rsConfig.reqId).To(Equal(0))
+		})
+
+		It("Detects the unhealthy REST framework of BIG-IP", func() {
+			// AS3 busy with the earlier declaration is not the unhealthy REST framework
+			Expect(isRESTFrameworkError(http.StatusServiceUnavailable, []byte(`{"code":503,"message":"busy"}`))).To(BeFalse())
+			Expect(isRESTFrameworkError(http.StatusServiceUnavailable, []byte("<html>Service Unavailable</html>"))).To(BeTrue())
+			Expect(isRESTFrameworkError(http.StatusBadGateway, nil)).To(BeTrue())
+			Expect(isRESTFrameworkError(http.StatusInternalServerError,
+				[]byte(`{"code":500,"message":"java.lang.OutOfMemoryError: Java heap space"}`))).To(BeTrue())
+			Expect(isRESTFrameworkError(http.StatusUnprocessableEntity, []byte(`{"code":422}`))).To(BeFalse())
+
+			Expect(mockPM.getRESTFrameworkBackoff()).To(BeZero())
+			mockPM.checkRESTFrameworkHealth(http.StatusBadGateway, nil)
+			Expect(mockPM.isRESTFrameworkUnhealthy()).To(BeTrue())
+			Expect(mockPM.getRESTFrameworkBackoff()).To(Equal(timeoutMedium))
+			mockPM.checkRESTFrameworkHealth(http.StatusBadGateway, nil)
+			Expect(mockPM.getRESTFrameworkBackoff()).To(Equal(2 * timeoutMedium))
+			for i := 0; i < 5; i++ {
+				mockPM.checkRESTFrameworkHealth(http.StatusServiceUnavailable, []byte("Service Unavailable"))
+			}
+			Expect(mockPM.getRESTFrameworkBackoff()).To(Equal(restFrameworkMaxBackoff))
+
+			// sys db variables of restjavad memory are set with the remediation
+			mockPM.BIGIPURL = "bigip.com"
+			mockPM.RESTJavadExtraMB = 1000
+			mockPM.setResponses([]responceCtx{
+				{status: http.StatusOK, body: `{"name":"restjavad.useextramb","value":"true"}`},
+				{status: http.StatusOK, body: `{"name":"provision.extramb","value":"1000"}`},
+			}, "PATCH")
+			Expect(mockPM.remediateRESTFramework()).To(BeNil())
+			// REST framework is healthy with the successful response
+			Expect(mockPM.isRESTFrameworkUnhealthy()).To(BeFalse())
+			Expect(mockPM.getRESTFrameworkBackoff()).To(BeZero())
+
+			mockPM.setResponses([]responceCtx{{status: http.StatusBadGateway, body: "<html>Bad Gateway</html>"}}, "PATCH")
+			Expect(mockPM.remediateRESTFramework()).NotTo(BeNil())
+			Expect(mockPM.isRESTFrameworkUnhealthy()).To(BeTrue())
+		})
+	})
+
+	Describe("BIGIP Capacity", func() {
+		BeforeEach(func() {
+			mockPM.BIGIPURL = "bigip.com"
+		})
+		It("Get BIG-IP provisioned modules, virtual servers and throughput limit", func() {
+			mockPM.setResponses([]responceCtx{
+				{
+					status: http.StatusOK,
+					body:   `{"items": [{"name": "ltm", "level": "nominal"}, {"name": "asm", "level": "none"}]}`,
+				},
+				{
+					status: http.StatusOK,
+					body:   `{"items": [{"name": "vs1", "partition": "test"}, {"name": "vs2", "partition": "test"}, {"name": "vs3", "partition": "Common"}]}`,
+				},
+				{
+					status: http.StatusOK,
+					body:   `{"registrationKey": "sfiifhanji", "featureFlags": [{"featureName": "perf_VE_throughput_Mbps", "featureValue": "200"}]}`,
+				},
+				{
+					status: http.StatusNotFound,
+					body:   fmt.Sprintf(`{"code":%d}`, http.StatusNotFound),
+				},
+			}, http.MethodGet)
+			modules, err := mockPM.getBigIPProvisionedModules()
+			Expect(err).To(BeNil())
+			Expect(modules).To(Equal(map[string]string{"ltm": "nominal"}))
+			counts, err := mockPM.getBigIPVirtualServerCount()
+			Expect(err).To(BeNil())
+			Expect(counts).To(Equal(map[string]int{"test": 2, "Common": 1}))
+			limit, err := mockPM.getBigIPThroughputLimit()
+			Expect(err).To(BeNil())
+			Expect(limit).To(Equal(200))
+			_, err = mockPM.getBigIPProvisionedModules()
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("Raises the event once the virtual servers reach the threshold", func() {
+			recorder := record.NewFakeRecorder(10)
+			ctlr := &Controller{capacityParams: CapacityParams{VirtualServerLimit: 10}, eventRecorder: recorder}
+			ctlr.setManagedVirtualServers(ResourceConfigRequest{ltmConfig: LTMConfig{
+				"test": &PartitionConfig{ResourceMap: ResourceMap{"vs1": &ResourceConfig{}, "vs2": &ResourceConfig{}}},
+			}})
+			ctlr.checkCapacityThreshold(7)
+			Expect(recorder.Events).NotTo(Receive())
+			ctlr.checkCapacityThreshold(8)
+			Expect(recorder.Events).To(Receive(ContainSubstring("Warning CapacityThreshold BIG-IP has 8 virtual servers, 2 of them managed by CIS")))
+			// Event is not raised again until the virtual servers drop below the threshold
+			ctlr.checkCapacityThreshold(9)
+			Expect(recorder.Events).NotTo(Receive())
+			ctlr.checkCapacityThreshold(5)
+			ctlr.checkCapacityThreshold(8)
+			Expect(recorder.Events).To(Receive())
+		})
+	})
+
+	Describe("Pool Member Statistics", func() {
+		BeforeEach(func() {
+			mockPM.BIGIPURL = "bigip.com"
+		})
+		It("Get the statistics of the pool members", func() {
+			mockPM.setResponses([]responceCtx{
+				{
+					status: http.StatusOK,
+					body: `{"entries": {"https://localhost/mgmt/tm/ltm/pool/~test~Shared~pool1/members/~test~10.1.1.1%0:80/stats": {"nestedStats": {"entries": {
+						"addr": {"description": "10.1.1.1%0"}, "port": {"value": 80}, "serverside.curConns": {"value": 5},
+						"serverside.totConns": {"value": 120}, "status.availabilityState": {"description": "available"}}}},
+						"https://localhost/mgmt/tm/ltm/pool/~test~Shared~pool1/members/~test~10.1.1.2:80/stats": {"nestedStats": {"entries": {
+						"addr": {"description": "10.1.1.2"}, "port": {"value": 80}, "serverside.curConns": {"value": 0},
+						"serverside.totConns": {"value": 3}, "status.availabilityState": {"description": "offline"}}}}}}`,
+				},
+				{
+					status: http.StatusNotFound,
+					body:   fmt.Sprintf(`{"code":%d}`, http.StatusNotFound),
+				},
+			}, http.MethodGet)
+			stats, err := mockPM.getPoolMemberStats("test", "pool1")
+			Expect(err).To(BeNil())
+			Expect(stats).To(Equal(map[string]poolMemberStats{
+				"10.1.1.1:80": {currentConns: 5, totalConns: 120, available: true},
+				"10.1.1.2:80": {currentConns: 0, totalConns: 3, available: false},
+			}))
+			_, err = mockPM.getPoolMemberStats("test", "pool2")
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("Records the pool members of the configuration", func() {
+			ctlr := &Controller{memberStatsInterval: 30, PoolMemberType: NodePort}
+			pool := Pool{Name: "pool1", ServiceName: "svc1", ServiceNamespace: "default",
+				Members: []PoolMember{{Address: "10.1.1.1", Port: 30080}}}
+			ctlr.setManagedPoolMembers(ResourceConfigRequest{ltmConfig: LTMConfig{
+				"test": &PartitionConfig{ResourceMap: ResourceMap{
+					"vs1": &ResourceConfig{Pools: Pools{pool}},
+					"vs2": &ResourceConfig{Pools: Pools{pool}},
+				}},
+			}})
+			Expect(ctlr.memberStats.pools).To(Equal(map[poolStatsKey]map[string]poolMemberMeta{
+				{partition: "test", name: "pool1"}: {"10.1.1.1:30080": {namespace: "default", service: "svc1"}},
+			}))
+		})
+
+		It("Polls the shared pool of the identical pools", func() {
+			ctlr := &Controller{Agent: &Agent{PostManager: mockPM.PostManager}}
+			meta := map[string]poolMemberMeta{"10.1.1.1:80": {namespace: "default", service: "svc1"}}
+			ctlr.memberStats.pools = map[poolStatsKey]map[string]poolMemberMeta{
+				{partition: "test", name: "pool1"}: meta,
+				{partition: "test", name: "pool2"}: meta,
+			}
+			ctlr.Agent.setSharedPools("test", map[string]string{"pool2": "pool1"})
+			Expect(ctlr.Agent.getSharedPool("test", "pool2")).To(Equal("pool1"))
+			Expect(ctlr.Agent.getSharedPool("test", "pool1")).To(Equal("pool1"))
+			mockPM.setResponses([]responceCtx{
+				{
+					status: http.StatusOK,
+					body: `{"entries": {"https://localhost/mgmt/tm/ltm/pool/~test~Shared~pool1/members/~test~10.1.1.1:80/stats": {"nestedStats": {"entries": {
+						"addr": {"description": "10.1.1.1"}, "port": {"value": 80}, "serverside.curConns": {"value": 5},
+						"serverside.totConns": {"value": 120}, "status.availabilityState": {"description": "available"}}}}}}`,
+				},
+			}, http.MethodGet)
+			ctlr.updatePoolMemberStats()
+			Expect(testutil.CollectAndCount(bigIPPrometheus.PoolMemberTotalConnections)).To(Equal(1))
+			Expect(testutil.ToFloat64(bigIPPrometheus.PoolMemberTotalConnections.WithLabelValues(
+				"test", "pool1", "10.1.1.1:80", "default", "svc1", ""))).To(Equal(float64(120)))
+
+			ctlr.Agent.setSharedPools("test", nil)
+			Expect(ctlr.Agent.getSharedPool("test", "pool2")).To(Equal("pool2"))
+		})
+	})
 })
@@ -1,10 +1,19 @@
 package controller
 
 import (
+	"os"
+	"path/filepath"
+	"time"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
+	crdfake "github.com/F5Networks/k8s-bigip-ctlr/v2/config/client/clientset/versioned/fake"
+	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/test"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/util/workqueue"
 )
 
 var _ = Describe("Profile", func() {
@@ -79,6 +88,141 @@ var _ = Describe("Profile", func() {
 
 	})
 
+	It("Client SSL with the private keys on BIG-IP", func() {
+		mockCtlr.kubeClient = k8sfake.NewSimpleClientset()
+		mockCtlr.comInformers = make(map[string]*CommonInformer)
+		mockCtlr.comInformers["default"] = mockCtlr.newNamespacedCommonResourceInformer("default")
+		rsCfg := &ResourceConfig{
+			MetaData: metaData{
+				ResourceType: VirtualServer,
+			},
+			Virtual: Virtual{
+				Name:      "crd_virtual_server",
+				Partition: "test",
+				Profiles:  ProfileRefs{},
+			},
+			customProfiles: make(map[SecretKey]CustomProfile),
+		}
+		hsmSecret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "hsm-secret", Namespace: "default"},
+			Data:       map[string][]byte{"tls.crt": []byte("hsm-cert")},
+		}
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "SampleSecret", Namespace: "default"},
+			Data:       map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")},
+		}
+		privateKeys := []cisapiv1.TLSPrivateKey{{Secret: "hsm-secret", BigIPKey: "/Common/hsm.key",
+			PassphraseSecret: "passphrase-secret"}}
+		tlsCipher := mockCtlr.resources.supplementContextCache.baseRouteConfig.TLSCipher
+
+		// Passphrase secret is not found
+		err, _ := mockCtlr.createPrivateKeyClientSSLProfile(rsCfg, []*v1.Secret{hsmSecret, secret}, privateKeys,
+			tlsCipher, CustomProfileClient)
+		Expect(err).ToNot(BeNil(), "Failed to Validate Client SSL")
+
+		mockCtlr.addSecret(&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "passphrase-secret", Namespace: "default"},
+			Data:       map[string][]byte{"passphrase": []byte("f5f5")},
+		})
+		err, _ = mockCtlr.createPrivateKeyClientSSLProfile(rsCfg, []*v1.Secret{hsmSecret, secret}, privateKeys,
+			tlsCipher, CustomProfileClient)
+		Expect(err).To(BeNil(), "Failed to Create Client SSL")
+		prof := rsCfg.customProfiles[SecretKey{Name: "hsm-secret", ResourceName: rsCfg.GetName()}]
+		Expect(prof.Certificates).To(Equal([]certificate{
+			{Cert: "hsm-cert", BigIPKey: "/Common/hsm.key", Passphrase: "f5f5"},
+			{Cert: "cert", Key: "key"},
+		}))
+
+		sharedApp := as3Application{"crd_virtual_server": &as3Service{}}
+		Expect(createUpdateTLSServer(prof, "crd_virtual_server", sharedApp)).To(BeTrue())
+		createCertificateDecl(prof, sharedApp)
+		cert := sharedApp["hsm-secret_0"].(*as3Certificate)
+		Expect(cert.PrivateKey).To(Equal(&as3ResourcePointer{BigIP: "/Common/hsm.key"}))
+		Expect(cert.Passphrase).To(Equal(&as3Passphrase{Ciphertext: "ZjVmNQ==", Protected: as3PassphraseProtected}))
+		cert = sharedApp["hsm-secret_1"].(*as3Certificate)
+		Expect(cert.PrivateKey).To(Equal("key"))
+		Expect(cert.Passphrase).To(BeNil())
+
+		// Key of the secret without the private key on BIG-IP is required
+		delete(secret.Data, "tls.key")
+		err, _ = mockCtlr.createPrivateKeyClientSSLProfile(rsCfg, []*v1.Secret{hsmSecret, secret}, privateKeys,
+			tlsCipher, CustomProfileClient)
+		Expect(err).ToNot(BeNil(), "Failed to Validate Client SSL")
+
+		tlsProfile := test.NewTLSProfile("sampleTLS", "default", cisapiv1.TLSProfileSpec{
+			TLS: cisapiv1.TLS{Termination: TLSEdge, Reference: BIGIP, ClientSSLs: []string{"hsm-secret"},
+				PrivateKeys: privateKeys},
+		})
+		Expect(validateTLSProfile(tlsProfile)).To(BeFalse(), "privateKeys are supported only with secrets")
+		tlsProfile.Spec.TLS.Reference = Secret
+		Expect(validateTLSProfile(tlsProfile)).To(BeTrue())
+		tlsProfile.Spec.TLS.PrivateKeys = []cisapiv1.TLSPrivateKey{{Secret: "other-secret", BigIPKey: "/Common/hsm.key"}}
+		Expect(validateTLSProfile(tlsProfile)).To(BeFalse(), "privateKeys should refer the clientSSL secrets")
+	})
+
+	It("SSL profiles with the TLS files", func() {
+		mockCtlr.kubeCRClient = crdfake.NewSimpleClientset()
+		mockCtlr.kubeClient = k8sfake.NewSimpleClientset()
+		mockCtlr.crInformers = make(map[string]*CRInformer)
+		mockCtlr.comInformers = make(map[string]*CommonInformer)
+		mockCtlr.nativeResourceSelector, _ = createLabelSelector(DefaultCustomResourceLabel)
+		_ = mockCtlr.addNamespacedInformers("default", false)
+		mockCtlr.resourceQueue = workqueue.NewNamedRateLimitingQueue(
+			workqueue.DefaultControllerRateLimiter(), "custom-resource-controller")
+		baseDir, err := os.MkdirTemp("", "tls-files")
+		Expect(err).To(BeNil())
+		defer os.RemoveAll(baseDir)
+		mockCtlr.tlsFiles.directory = baseDir
+		defer func() {
+			if mockCtlr.tlsFiles.watcher != nil {
+				mockCtlr.tlsFiles.watcher.Close()
+			}
+		}()
+		Expect(os.MkdirAll(filepath.Join(baseDir, "apps", "coffee"), 0755)).To(BeNil())
+		Expect(os.WriteFile(filepath.Join(baseDir, "apps", "coffee", "tls.crt"), []byte("cert"), 0644)).To(BeNil())
+		Expect(os.WriteFile(filepath.Join(baseDir, "apps", "coffee", "tls.key"), []byte("key"), 0644)).To(BeNil())
+		Expect(os.MkdirAll(filepath.Join(baseDir, "coffee-ca"), 0755)).To(BeNil())
+		Expect(os.WriteFile(filepath.Join(baseDir, "coffee-ca", "tls.crt"), []byte("ca"), 0644)).To(BeNil())
+
+		rsCfg := &ResourceConfig{
+			MetaData: metaData{
+				ResourceType: VirtualServer,
+			},
+			Virtual: Virtual{
+				Name:      "crd_virtual_server",
+				Partition: "test",
+				Profiles:  ProfileRefs{},
+			},
+			customProfiles: make(map[SecretKey]CustomProfile),
+		}
+		Expect(mockCtlr.createTLSFileProfiles(rsCfg, "default", []string{"apps/coffee"},
+			[]string{filepath.Join(baseDir, "coffee-ca")})).To(BeNil())
+		prof := rsCfg.customProfiles[SecretKey{Name: "apps_coffee", ResourceName: rsCfg.GetName()}]
+		Expect(prof.Context).To(Equal(CustomProfileClient))
+		Expect(prof.Certificates).To(Equal([]certificate{{Cert: "cert", Key: "key"}}))
+		prof = rsCfg.customProfiles[SecretKey{Name: "coffee_ca", ResourceName: rsCfg.GetName()}]
+		Expect(prof.Context).To(Equal(CustomProfileServer))
+		Expect(prof.Certificates).To(Equal([]certificate{{Cert: "ca"}}))
+		Expect(len(rsCfg.customProfiles)).To(Equal(4), "clientssl, serverssl and their default profiles")
+
+		// Directories outside --tls-file-directory are not referred
+		Expect(mockCtlr.createTLSFileProfiles(rsCfg, "default", []string{"../coffee"}, nil)).ToNot(BeNil())
+		Expect(mockCtlr.createTLSFileProfiles(rsCfg, "default", []string{"/etc"}, nil)).ToNot(BeNil())
+		Expect(mockCtlr.createTLSFileProfiles(rsCfg, "default", []string{"tea"}, nil)).ToNot(BeNil())
+
+		// TLSProfile referring the directory is processed on the rotation of the files
+		mockCtlr.addTLSProfile(test.NewTLSProfile("sampleTLS", "default", cisapiv1.TLSProfileSpec{
+			TLS: cisapiv1.TLS{Termination: TLSEdge, Reference: FileRef, ClientSSL: "apps/coffee"},
+		}))
+		key, _ := mockCtlr.resourceQueue.Get()
+		mockCtlr.resourceQueue.Done(key)
+		Expect(os.WriteFile(filepath.Join(baseDir, "apps", "coffee", "tls.crt"), []byte("new-cert"), 0644)).To(BeNil())
+		Eventually(mockCtlr.resourceQueue.Len, 5*time.Second).Should(Equal(1))
+		key, _ = mockCtlr.resourceQueue.Get()
+		Expect(key.(*rqKey).kind).To(Equal(TLSProfile))
+		Expect(key.(*rqKey).event).To(Equal(Update))
+	})
+
 	It("Server SSL", func() {
 		rsCfg := &ResourceConfig{
 			MetaData: metaData{
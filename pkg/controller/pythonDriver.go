@@ -207,6 +207,8 @@ func (agent *Agent) healthCheckPythonDriver() {
 		SubPID: agent.PythonDriverPID,
 	}
 	http.Handle("/health", hc.HealthCheckHandler())
+	// Report the controller degraded while BIG-IP is unreachable
+	http.Handle("/ready", agent.readinessHandler())
 	bigIPPrometheus.RegisterMetrics(agent.PostManager.HTTPClientMetrics)
 	log.Fatal(http.ListenAndServe(agent.HttpAddress, nil).Error())
 }
@@ -214,6 +216,8 @@ func (agent *Agent) healthCheckPythonDriver() {
 func (agent *Agent) enableMetrics() {
 	// Expose Prometheus metrics
 	http.Handle("/metrics", promhttp.Handler())
+	// Report the controller degraded while BIG-IP is unreachable
+	http.Handle("/ready", agent.readinessHandler())
 	bigIPPrometheus.RegisterMetrics(agent.PostManager.HTTPClientMetrics)
 	log.Fatal(http.ListenAndServe(agent.HttpAddress, nil).Error())
 }
@@ -0,0 +1,124 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ConditionQuarantined is the VirtualServer and TransportServer status condition set when the resource
+// is excluded from the declarations for the AS3 error of its objects
+const ConditionQuarantined = "Quarantined"
+
+// quarantineResources excludes the resources of the objects failing the AS3 declaration from the declarations
+// until their spec is updated, resources are processed again so that the declaration of the other resources
+// of the tenant is posted without them
+func (ctlr *Controller) quarantineResources(rscs map[string]string, message string) {
+	if !ctlr.enableQuarantine {
+		return
+	}
+	for key, kind := range rscs {
+		rsc := ctlr.getBaseResource(kind, key)
+		if rsc == nil {
+			continue
+		}
+		rscRef := resourceRef{kind: kind, namespace: rsc.GetNamespace(), name: rsc.GetName()}
+		ctlr.resourceQuarantine.Lock()
+		if ctlr.resourceQuarantine.resources == nil {
+			ctlr.resourceQuarantine.resources = make(map[resourceRef]int64)
+		}
+		if generation, ok := ctlr.resourceQuarantine.resources[rscRef]; ok && generation == rsc.GetGeneration() {
+			ctlr.resourceQuarantine.Unlock()
+			continue
+		}
+		ctlr.resourceQuarantine.resources[rscRef] = rsc.GetGeneration()
+		ctlr.resourceQuarantine.Unlock()
+
+		log.Warningf("Quarantining %v %v, it is excluded from the declarations until it is updated: %v",
+			kind, key, message)
+		ctlr.setResourceCondition(rsc, metav1.Condition{
+			Type:               ConditionQuarantined,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: rsc.GetGeneration(),
+			Reason:             "AS3Error",
+			Message:            message,
+		})
+		if obj, ok := rsc.(runtime.Object); ok && ctlr.eventRecorder != nil {
+			ctlr.eventRecorder.Event(obj, v1.EventTypeWarning, "Quarantined", message)
+		}
+		switch kind {
+		case VirtualServer:
+			ctlr.enqueueVirtualServer(rsc)
+		case TransportServer:
+			ctlr.enqueueTransportServer(rsc)
+		case Ingress:
+			ctlr.enqueueIngress(rsc, Update)
+		}
+	}
+}
+
+// isResourceQuarantined checks whether the resource is excluded from the declarations, resource is released
+// from the quarantine once its generation is changed with the update of its spec or it is deleted
+func (ctlr *Controller) isResourceQuarantined(kind, namespace, name string) bool {
+	if !ctlr.enableQuarantine {
+		return false
+	}
+	rscRef := resourceRef{kind: kind, namespace: namespace, name: name}
+	ctlr.resourceQuarantine.Lock()
+	generation, ok := ctlr.resourceQuarantine.resources[rscRef]
+	if !ok {
+		ctlr.resourceQuarantine.Unlock()
+		return false
+	}
+	rsc := ctlr.getBaseResource(kind, namespace+"/"+name)
+	if rsc != nil && rsc.GetGeneration() == generation {
+		ctlr.resourceQuarantine.Unlock()
+		return true
+	}
+	delete(ctlr.resourceQuarantine.resources, rscRef)
+	ctlr.resourceQuarantine.Unlock()
+
+	log.Infof("Releasing %v %v/%v from the quarantine", kind, namespace, name)
+	if rsc != nil {
+		ctlr.removeResourceCondition(rsc, ConditionQuarantined)
+	}
+	return false
+}
+
+// filterQuarantinedVirtualServers returns the VirtualServers which are not quarantined
+func (ctlr *Controller) filterQuarantinedVirtualServers(virtuals []*cisapiv1.VirtualServer) []*cisapiv1.VirtualServer {
+	if !ctlr.enableQuarantine {
+		return virtuals
+	}
+	var result []*cisapiv1.VirtualServer
+	for _, vs := range virtuals {
+		kind, name := VirtualServer, vs.Name
+		if ingName, ok := getIngressNameForVirtualServer(vs); ok {
+			kind, name = Ingress, ingName
+		}
+		if ctlr.isResourceQuarantined(kind, vs.Namespace, name) {
+			log.Debugf("Skipping quarantined %v %v/%v", kind, vs.Namespace, name)
+			continue
+		}
+		result = append(result, vs)
+	}
+	return result
+}
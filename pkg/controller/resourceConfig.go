@@ -17,6 +17,7 @@
 package controller
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 
@@ -24,6 +25,7 @@ import (
 
 	"net"
 	"reflect"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -66,6 +68,8 @@ func (rs *ResourceStore) Init() {
 	rs.invertedNamespaceLabelMap = make(map[string]string)
 	rs.ipamContext = make(map[string]ficV1.IPSpec)
 	rs.processedNativeResources = make(map[resourceRef]struct{})
+	rs.pausedResources = make(map[resourceRef]interface{})
+	rs.addressConflicts = make(map[resourceRef]string)
 	rs.externalClustersConfig = make(map[string]ExternalClusterConfig)
 }
 
@@ -100,6 +104,39 @@ const (
 	HttpsRedirectDgName = "https_redirect_dg"
 	TLSIRuleName        = "tls_irule"
 	ABPathIRuleName     = "ab_deployment_path_irule"
+	RegexPathIRuleName  = "regex_path_irule"
+	ErrorPageIRuleName  = "error_page_irule"
+
+	// Internal data group for the paths excluded from https redirect
+	HttpsRedirectExclusionsDgName = "https_redirect_exclusions_dg"
+
+	// Internal data group and iRule for the maintenance response of the hosts
+	MaintenanceDgName    = "maintenance_dg"
+	MaintenanceIRuleName = "maintenance_irule"
+
+	// Keys of the maintenance response in the maintenance secret
+	MaintenanceBodyKey        = "body"
+	MaintenanceContentTypeKey = "contentType"
+
+	// Internal data group and iRule for the mirror pools of the pools
+	PoolMirrorDgName    = "pool_mirror_dg"
+	PoolMirrorIRuleName = "pool_mirror_irule"
+
+	// Internal data group and iRule for the timeout and rate limit of the routes
+	RouteTrafficDgName    = "route_traffic_dg"
+	RouteTrafficIRuleName = "route_traffic_irule"
+
+	// Internal data group and iRule for the SNI routes of the TLS passthrough traffic of the TransportServer
+	SNIRoutesDgName    = "sni_routes_dg"
+	SNIRoutesIRuleName = "sni_routes_irule"
+
+	// iRule sending the PROXY protocol header with the client address to the pool members
+	ProxyProtocolIRuleName = "proxy_protocol_irule"
+
+	DefaultMaintenanceBody = "<html><body><h1>Service Unavailable</h1><p>The service is under maintenance.</p></body></html>"
+
+	// Request log of the virtual with request logging to the remote syslog servers
+	DefaultRequestLogTemplate = "$CLIENT_IP [$DATE_NCSA] \"$HTTP_REQUEST\" $VIRTUAL_NAME $SERVER_IP:$SERVER_PORT"
 )
 
 // constants for TLS references
@@ -110,8 +147,14 @@ const (
 	Secret = "secret"
 	// reference for routes
 	Certificate = "certificate"
+	// reference for certificates stored as TLSCertificate custom resources
+	TLSCertificateRef = "tlscertificate"
+	// reference for certificates mounted as files by the Secrets Store CSI driver
+	FileRef = "file"
 	// reference for service“
 	ServiceRef = "service"
+	// reference for monitors stored as HealthMonitor custom resources
+	HealthMonitorRef = "healthmonitor"
 )
 
 // constants for SSL options
@@ -216,6 +259,27 @@ func (v *Virtual) AddIRule(ruleName string) bool {
 	return true
 }
 
+// AttachIRules attaches the user specified iRules in the given order, iRules with
+// high priority are attached ahead of the iRules already attached to the virtual
+func (v *Virtual) AttachIRules(iRules []string, priority string) {
+	var merged []string
+	if priority == "high" {
+		merged = append(append(merged, iRules...), v.IRules...)
+	} else {
+		merged = append(append(merged, v.IRules...), iRules...)
+	}
+	// Retain only the first occurrence of an iRule so that the order is preserved
+	seen := make(map[string]struct{})
+	v.IRules = nil
+	for _, irule := range merged {
+		if _, ok := seen[irule]; ok {
+			continue
+		}
+		seen[irule] = struct{}{}
+		v.IRules = append(v.IRules, irule)
+	}
+}
+
 func (slice ProfileRefs) Less(i, j int) bool {
 	return ((slice[i].Partition < slice[j].Partition) ||
 		(slice[i].Partition == slice[j].Partition &&
@@ -271,8 +335,9 @@ func formatVirtualServerName(ip string, port int32) string {
 	// Strip any bracket characters; replace special characters ". : /"
 	// with "-" and "%" with ".", for naming purposes
 	ip = strings.Trim(ip, "[]")
-	ip = AS3NameFormatter(ip)
-	return fmt.Sprintf("crd_%s_%d", ip, port)
+	name := fmt.Sprintf("crd_%s_%d", AS3NameFormatter(ip), port)
+	name = executeNamingTemplate(virtualNameTemplate, virtualNameFields{Name: name, Address: ip, Port: port}, name)
+	return shortenObjectName(name)
 }
 
 // format the virtual server name for an VirtualServer
@@ -332,6 +397,9 @@ func (ctlr *Controller) framePoolNameForVs(ns string, pool cisapiv1.Pool, host s
 	return poolName
 }
 
+// nodeMemberLabelChars matches the characters of the NodeMemberLabel which are replaced in the pool name
+var nodeMemberLabelChars = regexp.MustCompile("[^a-zA-Z0-9_]")
+
 // format the pool name for an VirtualServer
 func formatPoolName(namespace, svc string, port intstr.IntOrString, nodeMemberLabel string, host, cluster string) string {
 	servicePort := fetchPortString(port)
@@ -344,10 +412,14 @@ func formatPoolName(namespace, svc string, port intstr.IntOrString, nodeMemberLa
 
 	}
 	if nodeMemberLabel != "" {
-		nodeMemberLabel = strings.ReplaceAll(nodeMemberLabel, "=", "_")
+		// operators and spaces of the label selector are not allowed in the pool name
+		nodeMemberLabel = nodeMemberLabelChars.ReplaceAllString(nodeMemberLabel, "_")
 		poolName = fmt.Sprintf("%s_%s", poolName, nodeMemberLabel)
 	}
-	return AS3NameFormatter(poolName)
+	poolName = AS3NameFormatter(poolName)
+	poolName = executeNamingTemplate(poolNameTemplate, poolNameFields{Name: poolName, Namespace: namespace,
+		Service: svc, Port: servicePort, Host: host, Cluster: strings.TrimPrefix(cluster, "_")}, poolName)
+	return shortenObjectName(poolName)
 }
 
 // format the monitor name for an VirtualServer pool
@@ -431,6 +503,7 @@ func (ctlr *Controller) fetchTargetPort(namespace, svcName string, servicePort i
 			}
 		}
 	}
+	log.Debugf("servicePort %v not found in service '%v'", servicePort.String(), svcKey)
 	return targetPort
 }
 
@@ -477,9 +550,13 @@ func (ctlr *Controller) prepareRSConfigFromVirtualServer(
 				ServiceNamespace:  svcNamespace,
 				ServicePort:       targetPort,
 				NodeMemberLabel:   pl.NodeMemberLabel,
+				NetworkAttachment: pl.NetworkAttachment,
+				AddressFamily:     pl.AddressFamily,
+				MemberRatio:       pl.MemberRatio,
+				WarmUpPeriod:      pl.WarmUpPeriod,
 				Balance:           pl.Balance,
 				ReselectTries:     pl.ReselectTries,
-				ServiceDownAction: pl.ServiceDownAction,
+				ServiceDownAction: getServiceDownAction(pl.ServiceDownAction, vs.Namespace, vs.Name),
 				Cluster:           SvcBackend.Cluster, // In all modes other than ratio, the cluster is ""
 			}
 
@@ -522,6 +599,10 @@ func (ctlr *Controller) prepareRSConfigFromVirtualServer(
 						ctlr.updateMultiClusterResourceServiceMap(rsCfg, rsRef, pl.Service, pl.Path, pool, pl.ServicePort,
 							ctlr.multiClusterConfigs.HAPairClusterName)
 					}
+					// update the multicluster resource serviceMap with backup cluster services
+					for _, clusterName := range ctlr.getBackupClusterNames() {
+						ctlr.updateMultiClusterResourceServiceMap(rsCfg, rsRef, pl.Service, pl.Path, pool, pl.ServicePort, clusterName)
+					}
 				} else {
 					// Update the multiCluster resource service map for each pool which constitutes a service in case of ratio mode
 					ctlr.updateMultiClusterResourceServiceMap(rsCfg, rsRef, SvcBackend.Name, pl.Path, pool, pl.ServicePort, SvcBackend.Cluster)
@@ -537,7 +618,7 @@ func (ctlr *Controller) prepareRSConfigFromVirtualServer(
 
 			if !reflect.DeepEqual(pl.Monitor, cisapiv1.Monitor{}) {
 				ctlr.createVirtualServerMonitor(pl.Monitor, &pool, rsCfg, pl.ServicePort, vs.Spec.Host, pl.Path,
-					vs.ObjectMeta.Namespace+"/"+vs.ObjectMeta.Name)
+					vs.ObjectMeta.Namespace, vs.ObjectMeta.Name)
 			} else if pl.Monitors != nil {
 				var formatPort intstr.IntOrString
 				for _, monitor := range pl.Monitors {
@@ -547,15 +628,19 @@ func (ctlr *Controller) prepareRSConfigFromVirtualServer(
 						formatPort = pl.ServicePort
 					}
 					ctlr.createVirtualServerMonitor(monitor, &pool, rsCfg, formatPort, vs.Spec.Host, pl.Path,
-						vs.ObjectMeta.Namespace+"/"+vs.ObjectMeta.Name)
+						vs.ObjectMeta.Namespace, vs.ObjectMeta.Name)
 				}
 			}
+			setPoolMinimumMonitors(&pool, pl.MinimumMonitors, vs.ObjectMeta.Namespace, vs.ObjectMeta.Name)
 			pools = append(pools, pool)
 		}
 	}
 
 	rsCfg.Pools = append(rsCfg.Pools, pools...)
 
+	// mirror the requests of the pools to the mirror services
+	ctlr.handlePoolMirrors(rsCfg, vs, rsRef)
+
 	// handle the default pool for virtual
 	ctlr.handleDefaultPool(rsCfg, vs, rsRef)
 
@@ -573,23 +658,34 @@ func (ctlr *Controller) prepareRSConfigFromVirtualServer(
 			rsCfg.ServiceAddress = append(rsCfg.ServiceAddress, ServiceAddress(sa))
 		}
 	}
+	if vs.Spec.TrafficGroup != "" {
+		rsCfg.Virtual.TrafficGroup = vs.Spec.TrafficGroup
+	}
 
 	// set the WAF policy
 	if vs.Spec.WAF != "" {
 		rsCfg.Virtual.WAF = vs.Spec.WAF
 	}
+	// attach the LTM policies of BIG-IP
+	if len(vs.Spec.BigIPPolicies) > 0 {
+		rsCfg.Virtual.BigIPPolicies = vs.Spec.BigIPPolicies
+	}
 
 	//Attach allowVlans.
 	if len(vs.Spec.AllowVLANs) > 0 {
 		rsCfg.Virtual.AllowVLANs = vs.Spec.AllowVLANs
 	}
+	// rejectVlans and allowVlans are mutually exclusive
+	if len(vs.Spec.RejectVLANs) > 0 {
+		rsCfg.Virtual.RejectVLANs = vs.Spec.RejectVLANs
+		rsCfg.Virtual.AllowVLANs = nil
+	}
 	if vs.Spec.PersistenceProfile != "" {
 		rsCfg.Virtual.PersistenceProfile = vs.Spec.PersistenceProfile
 	}
 
-	if len(vs.Spec.Profiles.TCP.Client) > 0 || len(vs.Spec.Profiles.TCP.Server) > 0 {
-		rsCfg.Virtual.TCP.Client = vs.Spec.Profiles.TCP.Client
-		rsCfg.Virtual.TCP.Server = vs.Spec.Profiles.TCP.Server
+	if vs.Spec.Profiles.TCP != (cisapiv1.ProfileTCP{}) {
+		rsCfg.Virtual.TCP = ProfileTCP(vs.Spec.Profiles.TCP)
 	}
 
 	if len(vs.Spec.Profiles.HTTP2.Client) > 0 || len(vs.Spec.Profiles.HTTP2.Server) > 0 {
@@ -597,6 +693,19 @@ func (ctlr *Controller) prepareRSConfigFromVirtualServer(
 		rsCfg.Virtual.HTTP2.Server = vs.Spec.Profiles.HTTP2.Server
 	}
 
+	// Analytics profiles of the VirtualServer take precedence over the Policy CR
+	if vs.Spec.Profiles.AnalyticsProfiles.HTTPAnalyticsProfile != "" {
+		rsCfg.Virtual.AnalyticsProfiles.HTTPAnalyticsProfile = vs.Spec.Profiles.AnalyticsProfiles.HTTPAnalyticsProfile
+	}
+	if vs.Spec.Profiles.AnalyticsProfiles.TCPAnalyticsProfile != "" {
+		rsCfg.Virtual.AnalyticsProfiles.TCPAnalyticsProfile = vs.Spec.Profiles.AnalyticsProfiles.TCPAnalyticsProfile
+	}
+	// APM access profile and per-request policy of the VirtualServer take precedence over the Policy CR
+	if vs.Spec.Profiles.ProfileAccess != "" {
+		rsCfg.Virtual.ProfileAccess = vs.Spec.Profiles.ProfileAccess
+		rsCfg.Virtual.PolicyPerRequestAccess = vs.Spec.Profiles.PolicyPerRequestAccess
+	}
+
 	if vs.Spec.DOS != "" {
 		rsCfg.Virtual.ProfileDOS = vs.Spec.DOS
 	}
@@ -616,15 +725,39 @@ func (ctlr *Controller) prepareRSConfigFromVirtualServer(
 	if vs.Spec.VirtualServerHTTPPort != 0 {
 		httpPort = vs.Spec.VirtualServerHTTPPort
 	}
+	// serve the ACME HTTP-01 challenges on HTTP virtual
+	if ctlr.enableACMESolver && rsCfg.Virtual.VirtualAddress.Port == httpPort {
+		ctlr.handleACMESolver(rsCfg, vs, rsRef)
+	}
 	// Do not Create Virtual Server L7 Forwarding policies if HTTPTraffic is set to None or Redirect
 	if len(vs.Spec.TLSProfileName) > 0 &&
 		rsCfg.Virtual.VirtualAddress.Port == httpPort &&
 		(vs.Spec.HTTPTraffic == TLSNoInsecure || vs.Spec.HTTPTraffic == TLSRedirectInsecure) {
+		// Paths excluded from redirect are forwarded to the pools on the HTTP virtual
+		if vs.Spec.HTTPTraffic == TLSRedirectInsecure && len(vs.Spec.HTTPRedirect.ExcludePaths) > 0 {
+			excludedVS := vs.DeepCopy()
+			excludedVS.Spec.Pools = getRedirectExcludedPools(vs)
+			if len(excludedVS.Spec.Pools) > 0 {
+				rules = ctlr.prepareVirtualServerRules(excludedVS, rsCfg)
+				if rules == nil {
+					return fmt.Errorf("failed to create LTM Rules")
+				}
+				policyName := formatPolicyName(vs.Spec.Host, vs.Spec.HostGroup, rsCfg.Virtual.Name)
+				rsCfg.AddRuleToPolicy(policyName, vs.Namespace, rules)
+			}
+		}
 		return nil
 	}
 
 	// skip the policy creation for passthrough termination
-	if !passthroughVS {
+	if passthroughVS && isMaintenanceModeEnabled(vs) {
+		log.Warningf("maintenanceMode is not supported with passthrough termination for VirtualServer %s/%s",
+			vs.Namespace, vs.Name)
+	}
+	if !passthroughVS && isMaintenanceModeEnabled(vs) {
+		// respond with the maintenance response instead of forwarding the requests to the pools
+		ctlr.handleMaintenanceMode(rsCfg, vs)
+	} else if !passthroughVS {
 		rules = ctlr.prepareVirtualServerRules(vs, rsCfg)
 		if rules == nil {
 			return fmt.Errorf("failed to create LTM Rules")
@@ -633,11 +766,28 @@ func (ctlr *Controller) prepareRSConfigFromVirtualServer(
 		policyName := formatPolicyName(vs.Spec.Host, vs.Spec.HostGroup, rsCfg.Virtual.Name)
 
 		rsCfg.AddRuleToPolicy(policyName, vs.Namespace, rules)
+
+		// Split the traffic across the pools sharing the same path based on weights
+		for _, weightedPools := range ctlr.getWeightedPools(vs) {
+			ctlr.updateDataGroupForWeightedPools(vs, weightedPools,
+				getRSCfgResName(rsCfg.Virtual.Name, AbDeploymentDgName),
+				rsCfg.Virtual.Partition,
+				rsCfg.IntDgMap,
+			)
+			ctlr.addABPathIRule(rsCfg)
+		}
+
+		ctlr.handleRegexPathPools(rsCfg, vs)
+
+		// respond with the error page to the requests not served by any pool
+		ctlr.handleErrorPage(rsCfg, vs)
 	}
 
+	ctlr.handleProxyProtocol(rsCfg, vs.Spec.ProxyProtocol)
+
 	// Attach user specified iRules
 	if len(vs.Spec.IRules) > 0 {
-		rsCfg.Virtual.IRules = append(rsCfg.Virtual.IRules, vs.Spec.IRules...)
+		rsCfg.Virtual.AttachIRules(vs.Spec.IRules, vs.Spec.IRulesPriority)
 	}
 
 	// Append all the hosts from a host group/ single host
@@ -648,18 +798,20 @@ func (ctlr *Controller) prepareRSConfigFromVirtualServer(
 }
 
 func (ctlr *Controller) createVirtualServerMonitor(monitor cisapiv1.Monitor, pool *Pool, rsCfg *ResourceConfig,
-	formatPort intstr.IntOrString, host, path, vsName string) {
+	formatPort intstr.IntOrString, host, path, vsNamespace, vsName string) {
 	if !reflect.DeepEqual(monitor, Monitor{}) {
 		if monitor.Reference == BIGIP {
 			if monitor.Name != "" {
 				pool.MonitorNames = append(pool.MonitorNames, MonitorName{Name: monitor.Name, Reference: monitor.Reference})
 			} else {
-				log.Errorf("missing monitor name with bigip reference in virtual server: %v", vsName)
+				log.Errorf("missing monitor name with bigip reference in virtual server: %v", vsNamespace+"/"+vsName)
 				return
 			}
+		} else if monitor.Reference == HealthMonitorRef {
+			ctlr.addHealthMonitorReference(monitor, pool, rsCfg, vsNamespace, vsName)
 		} else {
 			if (monitor.Type == HTTPS || monitor.Type == HTTP) && monitor.Send == "" {
-				log.Errorf("missing send string for monitor. skipping monitor for virtual server: %v", vsName)
+				log.Errorf("missing send string for monitor. skipping monitor for virtual server: %v", vsNamespace+"/"+vsName)
 				return
 			}
 
@@ -669,7 +821,7 @@ func (ctlr *Controller) createVirtualServerMonitor(monitor cisapiv1.Monitor, poo
 			}
 
 			pool.MonitorNames = append(pool.MonitorNames, MonitorName{Name: JoinBigipPath(rsCfg.Virtual.Partition, monitorName)})
-			monitor := Monitor{
+			mntr := Monitor{
 				Name:       monitorName,
 				Partition:  rsCfg.Virtual.Partition,
 				Type:       monitor.Type,
@@ -679,7 +831,8 @@ func (ctlr *Controller) createVirtualServerMonitor(monitor cisapiv1.Monitor, poo
 				Timeout:    monitor.Timeout,
 				TargetPort: monitor.TargetPort,
 			}
-			rsCfg.Monitors = append(rsCfg.Monitors, monitor)
+			ctlr.setMonitorClientTLS(monitor, &mntr, vsNamespace, vsName)
+			rsCfg.Monitors = append(rsCfg.Monitors, mntr)
 		}
 	}
 }
@@ -694,6 +847,8 @@ func (ctlr *Controller) createTransportServerMonitor(monitor cisapiv1.Monitor, p
 				log.Errorf("missing monitor name with bigip reference in transport server: %v", vsNamespace+"/"+vsName)
 				return
 			}
+		} else if monitor.Reference == HealthMonitorRef {
+			ctlr.addHealthMonitorReference(monitor, pool, rsCfg, vsNamespace, vsName)
 		} else {
 			monitorName := monitor.Name
 			if monitorName == "" {
@@ -701,7 +856,7 @@ func (ctlr *Controller) createTransportServerMonitor(monitor cisapiv1.Monitor, p
 			}
 
 			pool.MonitorNames = append(pool.MonitorNames, MonitorName{Name: JoinBigipPath(rsCfg.Virtual.Partition, monitorName)})
-			monitor := Monitor{
+			mntr := Monitor{
 				Name:       monitorName,
 				Partition:  rsCfg.Virtual.Partition,
 				Type:       monitor.Type,
@@ -711,9 +866,82 @@ func (ctlr *Controller) createTransportServerMonitor(monitor cisapiv1.Monitor, p
 				Timeout:    monitor.Timeout,
 				TargetPort: monitor.TargetPort,
 			}
-			rsCfg.Monitors = append(rsCfg.Monitors, monitor)
+			ctlr.setMonitorClientTLS(monitor, &mntr, vsNamespace, vsName)
+			rsCfg.Monitors = append(rsCfg.Monitors, mntr)
+		}
+	}
+}
+
+// getServiceDownAction returns the connection handling of the pool when its member goes down,
+// invalid actions are ignored and BIG-IP default none is used
+func getServiceDownAction(action, namespace, rsName string) string {
+	switch action {
+	case "", "none", "reset", "drop", "reselect":
+		return action
+	}
+	log.Errorf("invalid serviceDownAction %v in %v/%v, allowed values are none, reset, drop and reselect",
+		action, namespace, rsName)
+	return ""
+}
+
+// setPoolMinimumMonitors sets the number of monitors that must pass for the pool member to be up,
+// either a number up to the monitors of the pool or all
+func setPoolMinimumMonitors(pool *Pool, minimum intstr.IntOrString, namespace, rsName string) {
+	if !isValidMinimumMonitors(minimum, len(pool.MonitorNames), pool.Name, namespace, rsName) {
+		return
+	}
+	pool.MinimumMonitors = &minimum
+}
+
+// isValidMinimumMonitors checks the minimumMonitors of the pool of the resource against the monitors of the pool,
+// unset minimumMonitors is not valid to be set on the pool
+func isValidMinimumMonitors(minimum intstr.IntOrString, monitors int, poolName, namespace, rsName string) bool {
+	if minimum == (intstr.IntOrString{}) {
+		return false
+	}
+	if minimum.Type == intstr.String {
+		if minimum.StrVal != "all" {
+			log.Errorf("invalid minimumMonitors %v for pool %v in %v/%v, allowed values are a number or all",
+				minimum.StrVal, poolName, namespace, rsName)
+			return false
 		}
+	} else if minimum.IntVal < 1 || int(minimum.IntVal) > monitors {
+		log.Errorf("minimumMonitors %v for pool %v in %v/%v must be between 1 and the %v monitors of the pool",
+			minimum.IntVal, poolName, namespace, rsName, monitors)
+		return false
+	}
+	return true
+}
+
+// setMonitorClientTLS sets the client certificate and the SNI server name presented
+// by the https monitor to the pool members
+func (ctlr *Controller) setMonitorClientTLS(mtr cisapiv1.Monitor, monitor *Monitor, namespace, rsName string) {
+	if mtr.ClientTLS == "" && mtr.SNIServerName == "" {
+		return
+	}
+	if mtr.Type != HTTPS {
+		log.Errorf("clientTLS and sniServerName are supported only for https monitor, skipping them for %v/%v",
+			namespace, rsName)
+		return
+	}
+	monitor.SNIServerName = mtr.SNIServerName
+	if mtr.ClientTLS == "" {
+		return
+	}
+	comInf, ok := ctlr.getNamespacedCommonInformer(namespace)
+	if !ok || comInf.secretsInformer == nil {
+		log.Errorf("Informer not found for namespace: %v", namespace)
+		return
+	}
+	secretKey := namespace + "/" + mtr.ClientTLS
+	obj, found, err := comInf.secretsInformer.GetIndexer().GetByKey(secretKey)
+	if err != nil || !found {
+		log.Errorf("monitor clientTLS secret %s not found for %v/%v", secretKey, namespace, rsName)
+		return
 	}
+	secret := obj.(*v1.Secret)
+	monitor.ClientTLSCertificate = string(secret.Data["tls.crt"])
+	monitor.ClientTLSKey = string(secret.Data["tls.key"])
 }
 
 // Handle the default pool for virtual server
@@ -749,13 +977,15 @@ func (ctlr *Controller) handleDefaultPool(
 				NodeMemberLabel:   vs.Spec.DefaultPool.NodeMemberLabel,
 				Balance:           vs.Spec.DefaultPool.Balance,
 				ReselectTries:     vs.Spec.DefaultPool.ReselectTries,
-				ServiceDownAction: vs.Spec.DefaultPool.ServiceDownAction,
+				ServiceDownAction: getServiceDownAction(vs.Spec.DefaultPool.ServiceDownAction, vs.Namespace, vs.Name),
 			}
 			if vs.Spec.DefaultPool.Monitors != nil {
 				for _, mtr := range vs.Spec.DefaultPool.Monitors {
 					var monitorName string
 					if mtr.Name != "" && mtr.Reference == BIGIP {
 						pool.MonitorNames = append(pool.MonitorNames, MonitorName{Name: mtr.Name, Reference: mtr.Reference})
+					} else if mtr.Reference == HealthMonitorRef {
+						ctlr.addHealthMonitorReference(mtr, &pool, rsCfg, vs.Namespace, vs.Name)
 					} else {
 						var formatPort intstr.IntOrString
 						if mtr.TargetPort != 0 {
@@ -777,10 +1007,12 @@ func (ctlr *Controller) handleDefaultPool(
 							Timeout:    mtr.Timeout,
 							TargetPort: mtr.TargetPort,
 						}
+						ctlr.setMonitorClientTLS(mtr, &mntr, vs.Namespace, vs.Name)
 						rsCfg.Monitors = append(rsCfg.Monitors, mntr)
 					}
 				}
 			}
+			setPoolMinimumMonitors(&pool, vs.Spec.DefaultPool.MinimumMonitors, vs.Namespace, vs.Name)
 			ctlr.updateMultiClusterResourceServiceMap(rsCfg, rsRef, vs.Spec.DefaultPool.Service, "", pool, vs.Spec.DefaultPool.ServicePort, "")
 			// Update the pool Members
 			ctlr.updatePoolMembersForResources(&pool)
@@ -859,7 +1091,14 @@ func (ctlr *Controller) handleTLS(
 						}
 						secrets = append(secrets, obj.(*v1.Secret))
 					}
-					err, _ := ctlr.createSecretClientSSLProfile(rsCfg, secrets, ctlr.resources.baseRouteConfig.TLSCipher, CustomProfileClient)
+					var err error
+					if len(tlsContext.bigIPSSLProfiles.privateKeys) > 0 {
+						// Private keys of the certificates are stored on BIG-IP
+						err, _ = ctlr.createPrivateKeyClientSSLProfile(rsCfg, secrets,
+							tlsContext.bigIPSSLProfiles.privateKeys, ctlr.resources.baseRouteConfig.TLSCipher, CustomProfileClient)
+					} else {
+						err, _ = ctlr.createSecretClientSSLProfile(rsCfg, secrets, ctlr.resources.baseRouteConfig.TLSCipher, CustomProfileClient)
+					}
 					if err != nil {
 						log.Errorf("error %v encountered while creating clientssl profile for '%s' '%s'/'%s'",
 							err, tlsContext.resourceType, tlsContext.namespace, tlsContext.name)
@@ -890,6 +1129,22 @@ func (ctlr *Controller) handleTLS(
 					}
 				}
 
+			case TLSCertificateRef:
+				// Process ClientSSL and ServerSSL referring the TLSCertificates
+				err := ctlr.createTLSCertificateProfiles(rsCfg, tlsContext.namespace, clientSSL, serverSSL)
+				if err != nil {
+					log.Errorf("error %v encountered while creating ssl profiles for '%s' '%s'/'%s'",
+						err, tlsContext.resourceType, tlsContext.namespace, tlsContext.name)
+					return false
+				}
+			case FileRef:
+				// Process ClientSSL and ServerSSL from the files of the certificates and keys
+				err := ctlr.createTLSFileProfiles(rsCfg, tlsContext.namespace, clientSSL, serverSSL)
+				if err != nil {
+					log.Errorf("error %v encountered while creating ssl profiles for '%s' '%s'/'%s'",
+						err, tlsContext.resourceType, tlsContext.namespace, tlsContext.name)
+					return false
+				}
 			case Certificate:
 				// Prepare SSL Transient Context
 				if tlsContext.bigIPSSLProfiles.key != "" && tlsContext.bigIPSSLProfiles.certificate != "" {
@@ -904,20 +1159,20 @@ func (ctlr *Controller) handleTLS(
 				}
 				// Create Server SSL profile for bigip
 				if tlsContext.bigIPSSLProfiles.destinationCACertificate != "" {
-					var err error
 					cert := certificate{Cert: tlsContext.bigIPSSLProfiles.destinationCACertificate}
-					if tlsContext.bigIPSSLProfiles.caCertificate != "" {
-						err, _ = ctlr.createServerSSLProfile(rsCfg, []certificate{cert},
-							tlsContext.bigIPSSLProfiles.caCertificate, tlsContext.name, tlsContext.namespace, ctlr.resources.baseRouteConfig.TLSCipher, CustomProfileServer)
-					} else {
-						err, _ = ctlr.createServerSSLProfile(rsCfg, []certificate{cert},
-							"", fmt.Sprintf("%s-serverssl", tlsContext.name), tlsContext.namespace, ctlr.resources.baseRouteConfig.TLSCipher, CustomProfileServer)
-					}
+					profileName := getDestinationCAProfileName(tlsContext)
+					err, _ := ctlr.createServerSSLProfile(rsCfg, []certificate{cert},
+						tlsContext.bigIPSSLProfiles.caCertificate, profileName, tlsContext.namespace, ctlr.resources.baseRouteConfig.TLSCipher, CustomProfileServer)
 					if err != nil {
 						log.Debugf("error %v encountered while creating serverssl profile  for '%s' '%s'/'%s'",
 							err, tlsContext.resourceType, tlsContext.namespace, tlsContext.name)
 						return false
 					}
+					// destination CA certificate verifies the certificate of the backends of the route
+					skey := SecretKey{Name: profileName, ResourceName: rsCfg.GetName()}
+					prof := rsCfg.customProfiles[skey]
+					prof.ValidateCertificate = true
+					rsCfg.customProfiles[skey] = prof
 				}
 			default:
 				log.Errorf("Invalid reference type provided for  '%s' '%s'/'%s'",
@@ -955,6 +1210,11 @@ func (ctlr *Controller) handleTLS(
 									rsCfg.Virtual.Partition, tlsContext.namespace, sslPath, profileName, DataGroupType)
 							}
 
+						} else if tlsContext.referenceType == Certificate && tlsContext.bigIPSSLProfiles.destinationCACertificate != "" {
+							// route with destination CA certificate uses its own TLS client verifying the backends
+							profileName := getRouteTLSClientName(rsCfg.Virtual.Name, getDestinationCAProfileName(tlsContext))
+							updateDataGroup(rsCfg.IntDgMap, getRSCfgResName(rsCfg.Virtual.Name, ReencryptServerSslDgName),
+								rsCfg.Virtual.Partition, tlsContext.namespace, sslPath, profileName, DataGroupType)
 						}
 					}
 				}
@@ -991,15 +1251,14 @@ func (ctlr *Controller) handleTLS(
 				tlsContext.httpPort,
 			)
 		case TLSPassthrough:
-			updateDataGroupOfDgName(
+			updatePassthroughDataGroup(
 				rsCfg.IntDgMap,
 				tlsContext.poolPathRefs,
 				rsCfg.Virtual.Name,
-				PassthroughHostsDgName,
+				tlsContext.vsHostname,
 				tlsContext.namespace,
 				rsCfg.Virtual.Partition,
-				[]string{},
-				tlsContext.httpPort)
+			)
 		}
 		if len(rsCfg.Virtual.AllowSourceRange) > 0 {
 			updateDataGroupOfDgName(
@@ -1017,6 +1276,11 @@ func (ctlr *Controller) handleTLS(
 			updateDataGroup(rsCfg.IntDgMap, getRSCfgResName(rsCfg.Virtual.Name, DefaultPoolsDgName),
 				rsCfg.Virtual.Partition, tlsContext.namespace, DefaultPool, rsCfg.Virtual.PoolName, DataGroupType)
 		}
+		// create data group entry for error page, so that the requests for unknown hosts are not rejected
+		if tlsContext.errorPage != nil && tlsContext.errorPage.StatusCode != 0 && len(rsCfg.Virtual.PoolName) == 0 {
+			updateDataGroup(rsCfg.IntDgMap, getRSCfgResName(rsCfg.Virtual.Name, DefaultPoolsDgName),
+				rsCfg.Virtual.Partition, tlsContext.namespace, ErrorPage, "true", DataGroupType)
+		}
 		ctlr.handleDataGroupIRules(
 			rsCfg,
 			tlsContext.vsHostname,
@@ -1040,10 +1304,18 @@ func (ctlr *Controller) handleTLS(
 			var ruleName string
 			if tlsContext.vsHostname == "" {
 				ruleName = fmt.Sprintf("%s_%d", getRSCfgResName(rsCfg.Virtual.Name, HttpRedirectNoHostIRuleName), tlsContext.httpsPort)
-				rsCfg.addIRule(ruleName, rsCfg.Virtual.Partition, httpRedirectIRuleNoHost(tlsContext.httpsPort))
+				rsCfg.addIRule(ruleName, rsCfg.Virtual.Partition, httpRedirectIRuleNoHost(tlsContext.httpsPort,
+					rsCfg.Virtual.Name, rsCfg.Virtual.Partition, tlsContext.httpRedirect.StatusCode))
+				// paths excluded from redirect by the virtuals without host are merged in the exclusions data group
+				if len(tlsContext.httpRedirect.ExcludePaths) > 0 {
+					addRedirectExclusions(rsCfg, tlsContext.namespace, HostlessABPathKeyPrefix,
+						tlsContext.httpRedirect.ExcludePaths)
+				}
 			} else {
 				ruleName = fmt.Sprintf("%s_%d", getRSCfgResName(rsCfg.Virtual.Name, HttpRedirectIRuleName), tlsContext.httpsPort)
-				rsCfg.addIRule(ruleName, rsCfg.Virtual.Partition, httpRedirectIRule(tlsContext.httpsPort, rsCfg.Virtual.Name, rsCfg.Virtual.Partition))
+				rsCfg.addIRule(ruleName, rsCfg.Virtual.Partition, httpRedirectIRule(tlsContext.httpsPort, rsCfg.Virtual.Name,
+					rsCfg.Virtual.Partition, tlsContext.httpRedirect.StatusCode))
+				updateDataGroupForRedirectExclusions(rsCfg, tlsContext)
 			}
 			ruleName = JoinBigipPath(rsCfg.Virtual.Partition, ruleName)
 			rsCfg.Virtual.AddIRule(ruleName)
@@ -1112,6 +1384,7 @@ func (ctlr *Controller) handleVirtualServerTLS(
 	} else if tls.Spec.TLS.ServerSSL != "" {
 		bigIPSSLProfiles.serverSSLs = append(bigIPSSLProfiles.serverSSLs, tls.Spec.TLS.ServerSSL)
 	}
+	bigIPSSLProfiles.privateKeys = tls.Spec.TLS.PrivateKeys
 	var poolPathRefs []poolPathRef
 	for _, pl := range vs.Spec.Pools {
 		poolBackends := ctlr.GetPoolBackends(&pl)
@@ -1143,11 +1416,11 @@ func (ctlr *Controller) handleVirtualServerTLS(
 			if (isVsPathBasedABDeployment(&pl) || isVsPathBasedRatioDeployment(&pl, ctlr.haModeType)) &&
 				(tls.Spec.TLS.Termination == TLSEdge ||
 					(tls.Spec.TLS.Termination == TLSReencrypt && strings.ToLower(vs.Spec.HTTPTraffic) != TLSAllowInsecure)) {
-				ctlr.HandlePathBasedABIRule(rsCfg, vs.Spec.Host, tls.Spec.TLS.Termination)
+				ctlr.HandlePathBasedABIRule(rsCfg, tls.Spec.TLS.Termination)
 			}
 			// handle AB traffic for edge termination with allow
 			if (isVSABDeployment(&pl) || ctlr.haModeType == Ratio) && rsCfg.Virtual.VirtualAddress.Port == httpPort && strings.ToLower(vs.Spec.HTTPTraffic) == TLSAllowInsecure {
-				ctlr.HandlePathBasedABIRule(rsCfg, vs.Spec.Host, tls.Spec.TLS.Termination)
+				ctlr.HandlePathBasedABIRule(rsCfg, tls.Spec.TLS.Termination)
 			}
 		}
 	}
@@ -1163,6 +1436,8 @@ func (ctlr *Controller) handleVirtualServerTLS(
 		httpTraffic:      vs.Spec.HTTPTraffic,
 		poolPathRefs:     poolPathRefs,
 		bigIPSSLProfiles: bigIPSSLProfiles,
+		httpRedirect:     vs.Spec.HTTPRedirect,
+		errorPage:        vs.Spec.ErrorPage,
 	})
 }
 
@@ -1197,7 +1472,7 @@ func validateTLSProfile(tls *cisapiv1.TLSProfile) bool {
 			return false
 		}
 	}
-	return true
+	return validateTLSPrivateKeys(tls)
 }
 
 // ConvertStringToProfileRef converts strings to profile references
@@ -1267,8 +1542,9 @@ func (v *Virtual) SetVirtualAddress(bindAddr string, port int32) {
 			BindAddr: bindAddr,
 			Port:     port,
 		}
-		// Validate the IP address, and create the destination
-		ip, rd := split_ip_with_route_domain(bindAddr)
+		// Validate the IP address, and create the destination, mask of the address is not part of the destination
+		address, _ := splitVirtualAddressMask(bindAddr)
+		ip, rd := split_ip_with_route_domain(address)
 		if len(rd) > 0 {
 			rd = "%" + rd
 		}
@@ -1285,6 +1561,18 @@ func (v *Virtual) SetVirtualAddress(bindAddr string, port int32) {
 	}
 }
 
+// getVirtualAddressWithMask returns the virtual address of the destination with the mask of the bind address,
+// virtual addresses without the mask are the host addresses
+func (v *Virtual) getVirtualAddressWithMask(address string) string {
+	if v.VirtualAddress == nil || address == "" {
+		return address
+	}
+	if _, mask := splitVirtualAddressMask(v.VirtualAddress.BindAddr); mask != "" {
+		return address + "/" + mask
+	}
+	return address
+}
+
 // SetPolicy sets a policy
 func (rc *ResourceConfig) SetPolicy(policy Policy) {
 	toFind := nameRef{
@@ -1323,13 +1611,45 @@ func (rc *ResourceConfig) FindPolicy(controlType string) *Policy {
 }
 
 func (rs *ResourceStore) getPartitionResourceMap(partition string) ResourceMap {
-	_, ok := rs.ltmConfig[partition]
+	return rs.getPartitionConfig(partition).ResourceMap
+}
+
+// getPartitionConfig returns the config of the partition, partition is created if it doesn't exist
+func (rs *ResourceStore) getPartitionConfig(partition string) *PartitionConfig {
+	partitionConfig, ok := rs.ltmConfig[partition]
 	if !ok {
 		zero := 0
-		rs.ltmConfig[partition] = &PartitionConfig{ResourceMap: make(ResourceMap), Priority: &zero}
+		partitionConfig = &PartitionConfig{ResourceMap: make(ResourceMap), Priority: &zero}
+		rs.ltmConfig[partition] = partitionConfig
+	}
+	if partitionConfig.DataGroups == nil {
+		partitionConfig.DataGroups = make(map[string]*InternalDataGroup)
+		partitionConfig.Certificates = make(map[string]*SharedCertificate)
+		partitionConfig.Monitors = make(map[string]Monitor)
 	}
+	return partitionConfig
+}
 
-	return rs.ltmConfig[partition].ResourceMap
+// hasConfig checks whether the partition has the virtuals or the shared objects
+func (partitionConfig *PartitionConfig) hasConfig() bool {
+	return len(partitionConfig.ResourceMap) > 0 || len(partitionConfig.DataGroups) > 0 ||
+		len(partitionConfig.Certificates) > 0 || len(partitionConfig.Monitors) > 0
+}
+
+// copySharedObjects copies the references of the shared objects of the partition config
+func (partitionConfig *PartitionConfig) copySharedObjects(src *PartitionConfig) {
+	partitionConfig.DataGroups = make(map[string]*InternalDataGroup, len(src.DataGroups))
+	for key, dg := range src.DataGroups {
+		partitionConfig.DataGroups[key] = dg
+	}
+	partitionConfig.Certificates = make(map[string]*SharedCertificate, len(src.Certificates))
+	for key, cert := range src.Certificates {
+		partitionConfig.Certificates[key] = cert
+	}
+	partitionConfig.Monitors = make(map[string]Monitor, len(src.Monitors))
+	for key, monitor := range src.Monitors {
+		partitionConfig.Monitors[key] = monitor
+	}
 }
 
 func (rs *ResourceStore) getLTMPartitions() []string {
@@ -1368,12 +1688,13 @@ func (rs *ResourceStore) getSanitizedLTMConfigCopy() LTMConfig {
 	ltmConfig := make(LTMConfig)
 	var deletePartitions []string
 	for prtn, partitionConfig := range rs.ltmConfig {
-		// copy only those partitions where virtual server exists otherwise remove from ltmConfig
-		if len(partitionConfig.ResourceMap) > 0 {
+		// copy only those partitions where virtual server or shared objects exist otherwise remove from ltmConfig
+		if partitionConfig.hasConfig() {
 			ltmConfig[prtn] = &PartitionConfig{ResourceMap: make(ResourceMap), Priority: partitionConfig.Priority}
 			for rsName, res := range partitionConfig.ResourceMap {
 				ltmConfig[prtn].ResourceMap[rsName] = res
 			}
+			ltmConfig[prtn].copySharedObjects(partitionConfig)
 		} else {
 			// Delete partition from ltmConfig only if the priority is 0 else don't delete it
 			partitionConfig.PriorityMutex.RLock()
@@ -1390,18 +1711,21 @@ func (rs *ResourceStore) getSanitizedLTMConfigCopy() LTMConfig {
 	return ltmConfig
 }
 
-// getLTMConfigDeepCopy is a Resource reference copy of LTMConfig
-func (rs *ResourceStore) getLTMConfigDeepCopy() LTMConfig {
-	ltmConfig := make(LTMConfig)
+// getLTMConfigSnapshot is a snapshot of LTMConfig for the declarations posted by the agent. ResourceConfigs of
+// the store are not updated once stored, every update frames a fresh ResourceConfig or a copy of it with
+// copyConfig, so the snapshot shares them with the store instead of copying them for every post. Agents only
+// read the shared ResourceConfigs, defaults of the declarations are not to be written back to them
+func (rs *ResourceStore) getLTMConfigSnapshot() LTMConfig {
+	ltmConfig := make(LTMConfig, len(rs.ltmConfig))
 	for prtn, partitionConfig := range rs.ltmConfig {
 		partitionConfig.PriorityMutex.RLock()
-		ltmConfig[prtn] = &PartitionConfig{ResourceMap: make(ResourceMap), Priority: partitionConfig.Priority}
+		ltmConfig[prtn] = &PartitionConfig{ResourceMap: make(ResourceMap, len(partitionConfig.ResourceMap)),
+			Priority: partitionConfig.Priority}
 		partitionConfig.PriorityMutex.RUnlock()
 		for rsName, res := range partitionConfig.ResourceMap {
-			copyRes := &ResourceConfig{}
-			copyRes.copyConfig(res)
-			ltmConfig[prtn].ResourceMap[rsName] = copyRes
+			ltmConfig[prtn].ResourceMap[rsName] = res
 		}
+		ltmConfig[prtn].copySharedObjects(partitionConfig)
 	}
 	return ltmConfig
 }
@@ -1425,11 +1749,13 @@ func (rs *ResourceStore) updateCaches() {
 	// No need to deep copy as each RsCfg will be framed in a fresh memory block while creating live ltmConfig
 	rs.ltmConfigCache = rs.getSanitizedLTMConfigCopy()
 	rs.gtmConfigCache = rs.getGTMConfigCopy()
+	rs.sharedBundleCache = rs.sharedBundle
 }
 
 func (rs *ResourceStore) isConfigUpdated() bool {
 	return !reflect.DeepEqual(rs.ltmConfig, rs.ltmConfigCache) ||
-		!reflect.DeepEqual(rs.gtmConfig, rs.gtmConfigCache)
+		!reflect.DeepEqual(rs.gtmConfig, rs.gtmConfigCache) ||
+		rs.sharedBundle != rs.sharedBundleCache
 }
 
 // Deletes respective VirtualServer resource configuration from  ResourceStore
@@ -1509,9 +1835,15 @@ func (rc *ResourceConfig) copyConfig(cfg *ResourceConfig) {
 	//IRules
 	rc.Virtual.IRules = make([]string, len(cfg.Virtual.IRules))
 	copy(rc.Virtual.IRules, cfg.Virtual.IRules)
+	//BigIPPolicies
+	rc.Virtual.BigIPPolicies = make([]string, len(cfg.Virtual.BigIPPolicies))
+	copy(rc.Virtual.BigIPPolicies, cfg.Virtual.BigIPPolicies)
 	//LogProfiles
 	rc.Virtual.LogProfiles = make([]string, len(cfg.Virtual.LogProfiles))
 	copy(rc.Virtual.LogProfiles, cfg.Virtual.LogProfiles)
+	//RequestLogging
+	rc.Virtual.RequestLogging.Servers = make([]string, len(cfg.Virtual.RequestLogging.Servers))
+	copy(rc.Virtual.RequestLogging.Servers, cfg.Virtual.RequestLogging.Servers)
 	//AllowVLANS
 	rc.Virtual.AllowVLANs = make([]string, len(cfg.Virtual.AllowVLANs))
 	copy(rc.Virtual.AllowVLANs, cfg.Virtual.AllowVLANs)
@@ -1621,6 +1953,40 @@ func split_ip_with_route_domain(address string) (ip string, rd string) {
 	return
 }
 
+// splitVirtualAddressMask splits the virtual address of the form <ipv4_or_ipv6>[%<routeDomainID>][/<mask>]
+// into the address and the mask (optional)
+func splitVirtualAddressMask(address string) (addr string, mask string) {
+	if i := strings.LastIndex(address, "/"); i >= 0 {
+		return address[:i], address[i+1:]
+	}
+	return address, ""
+}
+
+// getVirtualAddressIP returns the ip of the virtual address without the route domain and the mask
+func getVirtualAddressIP(address string) net.IP {
+	addr, _ := splitVirtualAddressMask(address)
+	ip, _ := split_ip_with_route_domain(addr)
+	return net.ParseIP(ip)
+}
+
+// appendRouteDomain appends the route domain to the ip address unless
+// the ip address has a route domain already
+func appendRouteDomain(address string, routeDomain int32) string {
+	if routeDomain == 0 || address == "" {
+		return address
+	}
+	addr, mask := splitVirtualAddressMask(address)
+	if _, rd := split_ip_with_route_domain(addr); rd != "" {
+		return address
+	}
+	addr = fmt.Sprintf("%s%%%d", addr, routeDomain)
+	if mask != "" {
+		// route domain precedes the mask
+		addr += "/" + mask
+	}
+	return addr
+}
+
 func (pol *Policy) mergeRules(rls *Rules) Rules {
 	existingRlMap := make(ruleMap)
 	// populate existing rules into a map
@@ -1718,6 +2084,14 @@ const AllowSourceRangeDgName = "allowSourceRange"
 // Internal data group for ab deployment routes.
 const AbDeploymentDgName = "ab_deployment_dg"
 
+// HostlessABPathKeyPrefix is the host of the AB deployment and the redirect exclusions data group entries of the
+// virtuals without host, the entry of the root path is the prefix itself. It is not a valid host name character
+// and not stripped as the wildcard host
+const HostlessABPathKeyPrefix = "~"
+
+// Internal data group for regex paths that maps the host and path regex to the pool
+const RegexPathDgName = "regex_path_dg"
+
 func (slice InternalDataGroupRecords) Less(i, j int) bool {
 	return slice[i].Name < slice[j].Name
 }
@@ -1754,6 +2128,14 @@ func (idg *InternalDataGroup) AddOrUpdateRecord(name, data string) bool {
 	return true
 }
 
+func (idg *InternalDataGroup) hasRecord(name string) bool {
+	// The records are maintained as a sorted array.
+	i := sort.Search(idg.Records.Len(), func(i int) bool {
+		return idg.Records[i].Name >= name
+	})
+	return i < idg.Records.Len() && idg.Records[i].Name == name
+}
+
 func (idg *InternalDataGroup) RemoveRecord(name string) bool {
 	// The records are maintained as a sorted array.
 	nameKeyFunc := func(i int) bool {
@@ -1774,6 +2156,19 @@ func (idg *InternalDataGroup) RemoveRecord(name string) bool {
 
 // AS3NameFormatter formarts resources names according to AS3 convention
 // TODO: Should we use this? Or this will be done in agent?
+// getDestinationCAProfileName returns the name of the serverssl profile of the destination CA certificate
+func getDestinationCAProfileName(tlsContext TLSContext) string {
+	if tlsContext.bigIPSSLProfiles.caCertificate != "" {
+		return tlsContext.name
+	}
+	return fmt.Sprintf("%s-serverssl", tlsContext.name)
+}
+
+// getRouteTLSClientName returns the name of the TLS client of the serverssl profile of a route
+func getRouteTLSClientName(svcName, profileName string) string {
+	return AS3NameFormatter(fmt.Sprintf("%s_%s_tls_client", svcName, profileName))
+}
+
 func AS3NameFormatter(name string) string {
 	modifySpecialChars := map[string]string{
 		".":  "_",
@@ -1821,19 +2216,195 @@ func (ctlr *Controller) handleDataGroupIRules(
 
 func (ctlr *Controller) HandlePathBasedABIRule(
 	rsCfg *ResourceConfig,
-	vsHost string,
 	tlsTerminationType string,
 ) {
 	// For https
 	if "" != tlsTerminationType && tlsTerminationType != TLSPassthrough {
-		rsCfg.addIRule(
-			getRSCfgResName(rsCfg.Virtual.Name, ABPathIRuleName), rsCfg.Virtual.Partition, ctlr.GetPathBasedABDeployIRule(rsCfg.Virtual.Name, rsCfg.Virtual.Partition))
-		if vsHost != "" {
-			abPathIRule := JoinBigipPath(rsCfg.Virtual.Partition,
-				getRSCfgResName(rsCfg.Virtual.Name, ABPathIRuleName))
-			rsCfg.Virtual.AddIRule(abPathIRule)
+		ctlr.addABPathIRule(rsCfg)
+	}
+}
+
+// addABPathIRule adds the iRule selecting the pool based on the weights in AB deployment data group,
+// the pools of the weighted paths are not selected by the policy rules, so the iRule is attached to
+// the virtuals without host as well
+func (ctlr *Controller) addABPathIRule(rsCfg *ResourceConfig) {
+	rsCfg.addIRule(
+		getRSCfgResName(rsCfg.Virtual.Name, ABPathIRuleName), rsCfg.Virtual.Partition, ctlr.GetPathBasedABDeployIRule(rsCfg.Virtual.Name, rsCfg.Virtual.Partition))
+	abPathIRule := JoinBigipPath(rsCfg.Virtual.Partition,
+		getRSCfgResName(rsCfg.Virtual.Name, ABPathIRuleName))
+	rsCfg.Virtual.AddIRule(abPathIRule)
+}
+
+// handleRegexPathPools updates the regex path data group with the VirtualServer pools
+// matched by host or path regex and attaches the regex path iRule to the virtual
+func (ctlr *Controller) handleRegexPathPools(rsCfg *ResourceConfig, vs *cisapiv1.VirtualServer) {
+	var regexPoolFound bool
+	for i, pl := range vs.Spec.Pools {
+		if !isRegexPool(&pl) || pl.Service == "" {
+			continue
 		}
+		backends := ctlr.GetPoolBackends(&pl)
+		poolName := ctlr.framePoolNameForVs(vs.Namespace, pl, vs.Spec.Host, backends[0])
+		hostRegex, pathRegex := getPoolRegex(vs.Spec.Host, &pl)
+		updateDataGroup(rsCfg.IntDgMap,
+			getRSCfgResName(rsCfg.Virtual.Name, RegexPathDgName),
+			rsCfg.Virtual.Partition,
+			vs.Namespace,
+			// index of the pool orders the records, so that the first matching pool of the spec is selected
+			fmt.Sprintf("%04d %s %s", i, hostRegex, pathRegex),
+			fmt.Sprintf("%s,%t", poolName, pl.CaseSensitive),
+			DataGroupType,
+		)
+		regexPoolFound = true
+	}
+	if !regexPoolFound {
+		return
+	}
+	iRuleName := getRSCfgResName(rsCfg.Virtual.Name, RegexPathIRuleName)
+	rsCfg.addIRule(iRuleName, rsCfg.Virtual.Partition, ctlr.GetRegexPathIRule(rsCfg.Virtual.Name, rsCfg.Virtual.Partition))
+	rsCfg.Virtual.AddIRule(JoinBigipPath(rsCfg.Virtual.Partition, iRuleName))
+}
+
+// handlePoolMirrors creates the pools of the mirror services and updates the pool mirror
+// data group with the mirror pool and the percentage of requests mirrored for each pool
+func (ctlr *Controller) handlePoolMirrors(rsCfg *ResourceConfig, vs *cisapiv1.VirtualServer, rsRef resourceRef) {
+	var mirrorFound bool
+	for _, pl := range vs.Spec.Pools {
+		if pl.Mirror == nil || pl.Mirror.Service == "" || pl.Service == "" {
+			continue
+		}
+		svcNamespace := vs.Namespace
+		if pl.Mirror.ServiceNamespace != "" {
+			svcNamespace = pl.Mirror.ServiceNamespace
+		}
+		mirrorPool := Pool{
+			Name:             formatPoolName(svcNamespace, pl.Mirror.Service, pl.Mirror.ServicePort, "", vs.Spec.Host, ""),
+			Partition:        rsCfg.Virtual.Partition,
+			ServiceName:      pl.Mirror.Service,
+			ServiceNamespace: svcNamespace,
+			ServicePort:      ctlr.fetchTargetPort(svcNamespace, pl.Mirror.Service, pl.Mirror.ServicePort),
+		}
+		// mirror service may be shared across the pools or serve a pool of the virtual
+		var poolFound bool
+		for _, pool := range rsCfg.Pools {
+			if pool.Name == mirrorPool.Name {
+				poolFound = true
+				break
+			}
+		}
+		if !poolFound {
+			ctlr.updateMultiClusterResourceServiceMap(rsCfg, rsRef, pl.Mirror.Service, pl.Path, mirrorPool, pl.Mirror.ServicePort, "")
+			ctlr.updatePoolMembersForResources(&mirrorPool)
+			rsCfg.Pools = append(rsCfg.Pools, mirrorPool)
+		}
+
+		percentage := pl.Mirror.Percentage
+		if percentage == 0 {
+			percentage = 100
+		}
+		for _, backend := range ctlr.GetPoolBackends(&pl) {
+			poolName := ctlr.framePoolNameForVs(vs.Namespace, pl, vs.Spec.Host, backend)
+			updateDataGroup(rsCfg.IntDgMap,
+				getRSCfgResName(rsCfg.Virtual.Name, PoolMirrorDgName),
+				rsCfg.Virtual.Partition,
+				vs.Namespace,
+				strings.Join([]string{"", rsCfg.Virtual.Partition, Shared, poolName}, "/"),
+				fmt.Sprintf("%s,%d", strings.Join([]string{"", rsCfg.Virtual.Partition, Shared, mirrorPool.Name}, "/"), percentage),
+				DataGroupType,
+			)
+		}
+		mirrorFound = true
+	}
+	if !mirrorFound {
+		return
+	}
+	iRuleName := getRSCfgResName(rsCfg.Virtual.Name, PoolMirrorIRuleName)
+	rsCfg.addIRule(iRuleName, rsCfg.Virtual.Partition, ctlr.GetPoolMirrorIRule(rsCfg.Virtual.Name, rsCfg.Virtual.Partition))
+	rsCfg.Virtual.AddIRule(JoinBigipPath(rsCfg.Virtual.Partition, iRuleName))
+}
+
+// handleErrorPage attaches the iRule responding with the error page of the VirtualServer
+// to the requests not served by any pool, default pool takes precedence over the error page
+func (ctlr *Controller) handleErrorPage(rsCfg *ResourceConfig, vs *cisapiv1.VirtualServer) {
+	if vs.Spec.ErrorPage == nil || vs.Spec.ErrorPage.StatusCode == 0 || rsCfg.Virtual.PoolName != "" {
+		return
+	}
+	iRuleName := getRSCfgResName(rsCfg.Virtual.Name, ErrorPageIRuleName)
+	rsCfg.addIRule(iRuleName, rsCfg.Virtual.Partition, ctlr.GetErrorPageIRule(*vs.Spec.ErrorPage))
+	rsCfg.Virtual.AddIRule(JoinBigipPath(rsCfg.Virtual.Partition, iRuleName))
+}
+
+// handleProxyProtocol attaches the iRule sending the PROXY protocol header of the version to the pool members,
+// so that the backends receive the original client address with SNAT
+func (ctlr *Controller) handleProxyProtocol(rsCfg *ResourceConfig, proxyProtocol string) {
+	if proxyProtocol == "" || proxyProtocol == ProxyProtocolDisabled {
+		return
+	}
+	if rsCfg.IRulesMap == nil {
+		rsCfg.IRulesMap = make(IRulesMap)
+	}
+	iRuleName := getRSCfgResName(rsCfg.Virtual.Name, ProxyProtocolIRuleName)
+	if iRule, ok := rsCfg.IRulesMap[NameRef{Name: iRuleName, Partition: rsCfg.Virtual.Partition}]; ok &&
+		iRule.Code != ctlr.GetProxyProtocolIRule(proxyProtocol) {
+		log.Warningf("proxyProtocol %v is ignored for virtual %v, PROXY protocol of another resource of the "+
+			"virtual is used", proxyProtocol, rsCfg.Virtual.Name)
+		return
+	}
+	rsCfg.addIRule(iRuleName, rsCfg.Virtual.Partition, ctlr.GetProxyProtocolIRule(proxyProtocol))
+	rsCfg.Virtual.AddIRule(JoinBigipPath(rsCfg.Virtual.Partition, iRuleName))
+}
+
+// isMaintenanceModeEnabled checks whether the VirtualServer is in maintenance
+func isMaintenanceModeEnabled(vs *cisapiv1.VirtualServer) bool {
+	return vs.Spec.MaintenanceMode != nil && vs.Spec.MaintenanceMode.Enabled
+}
+
+// handleMaintenanceMode updates the maintenance data group with the maintenance response
+// of the VirtualServer host and attaches the iRule responding with it
+func (ctlr *Controller) handleMaintenanceMode(rsCfg *ResourceConfig, vs *cisapiv1.VirtualServer) {
+	contentType, body := ctlr.getMaintenanceResponse(vs)
+	// hostless VirtualServer is in maintenance for all the hosts
+	host := "/"
+	if vs.Spec.Host != "" {
+		host = strings.ToLower(vs.Spec.Host)
 	}
+	// body is base64 encoded to avoid escaping of the TCL special characters
+	updateDataGroup(rsCfg.IntDgMap, getRSCfgResName(rsCfg.Virtual.Name, MaintenanceDgName),
+		rsCfg.Virtual.Partition, vs.Namespace, host,
+		contentType+"|"+base64.StdEncoding.EncodeToString(body), DataGroupType)
+
+	iRuleName := getRSCfgResName(rsCfg.Virtual.Name, MaintenanceIRuleName)
+	rsCfg.addIRule(iRuleName, rsCfg.Virtual.Partition, ctlr.GetMaintenanceIRule(rsCfg.Virtual.Name, rsCfg.Virtual.Partition))
+	rsCfg.Virtual.AddIRule(JoinBigipPath(rsCfg.Virtual.Partition, iRuleName))
+}
+
+// getMaintenanceResponse returns the content type and the body of the maintenance response
+// from the maintenance secret of the VirtualServer, default response is used otherwise
+func (ctlr *Controller) getMaintenanceResponse(vs *cisapiv1.VirtualServer) (string, []byte) {
+	contentType, body := "text/html", []byte(DefaultMaintenanceBody)
+	if vs.Spec.MaintenanceMode.Secret == "" {
+		return contentType, body
+	}
+	comInf, ok := ctlr.getNamespacedCommonInformer(vs.Namespace)
+	if !ok || comInf.secretsInformer == nil {
+		log.Errorf("Informer not found for namespace: %v", vs.Namespace)
+		return contentType, body
+	}
+	secretKey := vs.Namespace + "/" + vs.Spec.MaintenanceMode.Secret
+	obj, found, err := comInf.secretsInformer.GetIndexer().GetByKey(secretKey)
+	if err != nil || !found {
+		log.Errorf("maintenance secret %s not found for VirtualServer %s/%s, using the default response",
+			secretKey, vs.Namespace, vs.Name)
+		return contentType, body
+	}
+	secret := obj.(*v1.Secret)
+	if data, ok := secret.Data[MaintenanceBodyKey]; ok {
+		body = data
+	}
+	if data, ok := secret.Data[MaintenanceContentTypeKey]; ok && len(data) > 0 {
+		contentType = string(data)
+	}
+	return contentType, body
 }
 
 func (ctlr *Controller) deleteVirtualServer(partition, rsName string) {
@@ -1869,9 +2440,13 @@ func (ctlr *Controller) prepareRSConfigFromTransportServer(
 		ServiceNamespace:  svcNamespace,
 		ServicePort:       targetPort,
 		NodeMemberLabel:   vs.Spec.Pool.NodeMemberLabel,
+		NetworkAttachment: vs.Spec.Pool.NetworkAttachment,
+		AddressFamily:     vs.Spec.Pool.AddressFamily,
+		MemberRatio:       vs.Spec.Pool.MemberRatio,
+		WarmUpPeriod:      vs.Spec.Pool.WarmUpPeriod,
 		Balance:           vs.Spec.Pool.Balance,
 		ReselectTries:     vs.Spec.Pool.ReselectTries,
-		ServiceDownAction: vs.Spec.Pool.ServiceDownAction,
+		ServiceDownAction: getServiceDownAction(vs.Spec.Pool.ServiceDownAction, vs.Namespace, vs.Name),
 	}
 	svcKey := MultiClusterServiceKey{
 		serviceName: vs.Spec.Pool.Service,
@@ -1917,6 +2492,11 @@ func (ctlr *Controller) prepareRSConfigFromTransportServer(
 			ctlr.updateMultiClusterResourceServiceMap(rsCfg, rsRef, vs.Spec.Pool.Service, "", pool, vs.Spec.Pool.ServicePort,
 				ctlr.multiClusterConfigs.HAPairClusterName)
 		}
+		// update the multicluster resource serviceMap with backup cluster services
+		for _, clusterName := range ctlr.getBackupClusterNames() {
+			ctlr.updateMultiClusterResourceServiceMap(rsCfg, rsRef, vs.Spec.Pool.Service, "", pool, vs.Spec.Pool.ServicePort,
+				clusterName)
+		}
 	} else {
 		ctlr.updateMultiClusterResourceServiceMap(rsCfg, rsRef, vs.Spec.Pool.Service, vs.Spec.Pool.Path, pool, vs.Spec.Pool.ServicePort, "")
 	}
@@ -1941,15 +2521,22 @@ func (ctlr *Controller) prepareRSConfigFromTransportServer(
 				vs.ObjectMeta.Namespace, vs.ObjectMeta.Name)
 		}
 	}
+	setPoolMinimumMonitors(&pool, vs.Spec.Pool.MinimumMonitors, vs.ObjectMeta.Namespace, vs.ObjectMeta.Name)
 
 	rsCfg.Virtual.Mode = vs.Spec.Mode
 	rsCfg.Virtual.IpProtocol = vs.Spec.Type
 	rsCfg.Virtual.PoolName = pool.Name
 	rsCfg.Pools = append(rsCfg.Pools, pool)
+	ctlr.handleTransportServerSNIRoutes(rsCfg, vs)
 
 	if vs.Spec.ProfileL4 != "" {
 		rsCfg.Virtual.ProfileL4 = vs.Spec.ProfileL4
 	}
+	// FastL4 profile settings take precedence over the profileL4 of policy CR
+	if vs.Spec.FastL4 != (cisapiv1.FastL4{}) {
+		rsCfg.Virtual.FastL4 = FastL4(vs.Spec.FastL4)
+		rsCfg.Virtual.ProfileL4 = ""
+	}
 	// Replace SNAT set from policy CR to the one defined by user in the TS spec
 	if vs.Spec.SNAT == "" {
 		if rsCfg.Virtual.SNAT == "" {
@@ -1967,9 +2554,11 @@ func (ctlr *Controller) prepareRSConfigFromTransportServer(
 		rsCfg.Virtual.ProfileBotDefense = vs.Spec.BotDefense
 	}
 
-	if len(vs.Spec.Profiles.TCP.Client) > 0 || len(vs.Spec.Profiles.TCP.Server) > 0 {
-		rsCfg.Virtual.TCP.Client = vs.Spec.Profiles.TCP.Client
-		rsCfg.Virtual.TCP.Server = vs.Spec.Profiles.TCP.Server
+	if vs.Spec.Profiles.TCP != (cisapiv1.ProfileTCP{}) {
+		rsCfg.Virtual.TCP = ProfileTCP(vs.Spec.Profiles.TCP)
+	}
+	if vs.Spec.Profiles.AnalyticsProfiles.TCPAnalyticsProfile != "" {
+		rsCfg.Virtual.AnalyticsProfiles.TCPAnalyticsProfile = vs.Spec.Profiles.AnalyticsProfiles.TCPAnalyticsProfile
 	}
 
 	if len(rsCfg.ServiceAddress) == 0 {
@@ -1977,22 +2566,101 @@ func (ctlr *Controller) prepareRSConfigFromTransportServer(
 			rsCfg.ServiceAddress = append(rsCfg.ServiceAddress, ServiceAddress(sa))
 		}
 	}
+	if vs.Spec.TrafficGroup != "" {
+		rsCfg.Virtual.TrafficGroup = vs.Spec.TrafficGroup
+	}
 
 	//set allowed VLAN's per TS config
 	if len(vs.Spec.AllowVLANs) > 0 {
 		rsCfg.Virtual.AllowVLANs = vs.Spec.AllowVLANs
 	}
+	//set rejected VLAN's per TS config, rejectVlans and allowVlans are mutually exclusive
+	if len(vs.Spec.RejectVLANs) > 0 {
+		rsCfg.Virtual.RejectVLANs = vs.Spec.RejectVLANs
+		rsCfg.Virtual.AllowVLANs = nil
+	}
 	if vs.Spec.PersistenceProfile != "" {
 		rsCfg.Virtual.PersistenceProfile = vs.Spec.PersistenceProfile
 	}
+	// set the connection and persistence mirroring for the stateful failover
+	if vs.Spec.Mirroring != "" {
+		rsCfg.Virtual.Mirroring = vs.Spec.Mirroring
+	}
+	if vs.Spec.PersistenceMirroring {
+		rsCfg.Virtual.PersistenceMirroring = true
+	}
+
+	ctlr.handleProxyProtocol(rsCfg, vs.Spec.ProxyProtocol)
 
 	// Attach user specified iRules
 	if len(vs.Spec.IRules) > 0 {
-		rsCfg.Virtual.IRules = append(rsCfg.Virtual.IRules, vs.Spec.IRules...)
+		rsCfg.Virtual.AttachIRules(vs.Spec.IRules, vs.Spec.IRulesPriority)
 	}
 	return nil
 }
 
+// handleTransportServerSNIRoutes adds the pools of the SNI routes of the TransportServer and attaches the iRule
+// selecting the pool with the server name of the TLS ClientHello, pool of the TransportServer is the default
+func (ctlr *Controller) handleTransportServerSNIRoutes(rsCfg *ResourceConfig, vs *cisapiv1.TransportServer) {
+	if len(vs.Spec.SNIRoutes) == 0 {
+		return
+	}
+	if rsCfg.IntDgMap == nil {
+		rsCfg.IntDgMap = make(InternalDataGroupMap)
+	}
+	if rsCfg.IRulesMap == nil {
+		rsCfg.IRulesMap = make(IRulesMap)
+	}
+	rsRef := resourceRef{
+		name:      vs.Name,
+		namespace: vs.Namespace,
+		kind:      TransportServer,
+	}
+	for _, route := range vs.Spec.SNIRoutes {
+		pl := route.Pool
+		svcNamespace := vs.Namespace
+		if pl.ServiceNamespace != "" {
+			svcNamespace = pl.ServiceNamespace
+		}
+		pool := Pool{
+			Name:              ctlr.framePoolName(vs.Namespace, pl, route.Host),
+			Partition:         rsCfg.Virtual.Partition,
+			ServiceName:       pl.Service,
+			ServiceNamespace:  svcNamespace,
+			ServicePort:       ctlr.fetchTargetPort(svcNamespace, pl.Service, pl.ServicePort),
+			NodeMemberLabel:   pl.NodeMemberLabel,
+			NetworkAttachment: pl.NetworkAttachment,
+			AddressFamily:     pl.AddressFamily,
+			MemberRatio:       pl.MemberRatio,
+			WarmUpPeriod:      pl.WarmUpPeriod,
+			Balance:           pl.Balance,
+			ReselectTries:     pl.ReselectTries,
+			ServiceDownAction: getServiceDownAction(pl.ServiceDownAction, vs.Namespace, vs.Name),
+		}
+		poolFound := false
+		for _, rsPool := range rsCfg.Pools {
+			if rsPool.Name == pool.Name {
+				poolFound = true
+				break
+			}
+		}
+		if !poolFound {
+			ctlr.updateMultiClusterResourceServiceMap(rsCfg, rsRef, pl.Service, "", pool, pl.ServicePort, "")
+			ctlr.updatePoolMembersForResources(&pool)
+			if !reflect.DeepEqual(pl.Monitor, cisapiv1.Monitor{}) {
+				ctlr.createTransportServerMonitor(pl.Monitor, &pool, rsCfg, pl.ServicePort, vs.Namespace, vs.Name)
+			}
+			setPoolMinimumMonitors(&pool, pl.MinimumMonitors, vs.Namespace, vs.Name)
+			rsCfg.Pools = append(rsCfg.Pools, pool)
+		}
+		updateDataGroup(rsCfg.IntDgMap, getRSCfgResName(rsCfg.Virtual.Name, SNIRoutesDgName),
+			rsCfg.Virtual.Partition, vs.Namespace, strings.ToLower(route.Host), pool.Name, DataGroupType)
+	}
+	iRuleName := getRSCfgResName(rsCfg.Virtual.Name, SNIRoutesIRuleName)
+	rsCfg.addIRule(iRuleName, rsCfg.Virtual.Partition, ctlr.GetSNIRoutesIRule(rsCfg.Virtual.Name, rsCfg.Virtual.Partition))
+	rsCfg.Virtual.AddIRule(JoinBigipPath(rsCfg.Virtual.Partition, iRuleName))
+}
+
 // Prepares resource config based on VirtualServer resource config
 func (ctlr *Controller) prepareRSConfigFromLBService(
 	rsCfg *ResourceConfig,
@@ -2086,14 +2754,19 @@ func (ctlr *Controller) handleVSResourceConfigForPolicy(
 	rsCfg.Virtual.ProfileMultiplex = plc.Spec.Profiles.ProfileMultiplex
 	rsCfg.Virtual.ProfileDOS = plc.Spec.L3Policies.DOS
 	rsCfg.Virtual.ProfileBotDefense = plc.Spec.L3Policies.BotDefense
-	rsCfg.Virtual.TCP.Client = plc.Spec.Profiles.TCP.Client
-	rsCfg.Virtual.TCP.Server = plc.Spec.Profiles.TCP.Server
+	rsCfg.Virtual.TCP = ProfileTCP(plc.Spec.Profiles.TCP)
 	rsCfg.Virtual.HTTP2.Client = plc.Spec.Profiles.HTTP2.Client
 	rsCfg.Virtual.HTTP2.Server = plc.Spec.Profiles.HTTP2.Server
 	rsCfg.Virtual.AllowSourceRange = plc.Spec.L3Policies.AllowSourceRange
 	rsCfg.Virtual.AllowVLANs = plc.Spec.L3Policies.AllowVlans
 	rsCfg.Virtual.IpIntelligencePolicy = plc.Spec.L3Policies.IpIntelligencePolicy
 	rsCfg.Virtual.AutoLastHop = plc.Spec.AutoLastHop
+	if plc.Spec.Mirroring != "" {
+		rsCfg.Virtual.Mirroring = plc.Spec.Mirroring
+	}
+	if plc.Spec.PersistenceMirroring {
+		rsCfg.Virtual.PersistenceMirroring = true
+	}
 	if rsCfg.Virtual.HttpMrfRoutingEnabled == nil && plc.Spec.Profiles.HttpMrfRoutingEnabled != nil {
 		rsCfg.Virtual.HttpMrfRoutingEnabled = plc.Spec.Profiles.HttpMrfRoutingEnabled
 	}
@@ -2102,15 +2775,29 @@ func (ctlr *Controller) handleVSResourceConfigForPolicy(
 		(rsCfg.MetaData.Protocol == HTTP || rsCfg.MetaData.Protocol == HTTPS) {
 		rsCfg.Virtual.AnalyticsProfiles.HTTPAnalyticsProfile = plc.Spec.Profiles.AnalyticsProfiles.HTTPAnalyticsProfile
 	}
+	rsCfg.Virtual.AnalyticsProfiles.TCPAnalyticsProfile = plc.Spec.Profiles.AnalyticsProfiles.TCPAnalyticsProfile
 
 	//profileWebSocket is supported for service_HTTP and service_HTTPS
 	if plc.Spec.Profiles.ProfileWebSocket != "" &&
 		(rsCfg.MetaData.Protocol == HTTP || rsCfg.MetaData.Protocol == HTTPS) {
 		rsCfg.Virtual.ProfileWebSocket = plc.Spec.Profiles.ProfileWebSocket
 	}
+	//profileHTTPCompression, profileWebAcceleration and access profiles are supported for service_HTTP and service_HTTPS
+	if rsCfg.MetaData.Protocol == HTTP || rsCfg.MetaData.Protocol == HTTPS {
+		rsCfg.Virtual.ProfileHTTPCompression = plc.Spec.Profiles.ProfileHTTPCompression
+		rsCfg.Virtual.ProfileWebAcceleration = plc.Spec.Profiles.ProfileWebAcceleration
+		rsCfg.Virtual.ProfileAccess = plc.Spec.Profiles.ProfileAccess
+		rsCfg.Virtual.PolicyPerRequestAccess = plc.Spec.Profiles.PolicyPerRequestAccess
+	}
 	if len(plc.Spec.Profiles.LogProfiles) > 0 {
 		rsCfg.Virtual.LogProfiles = append(rsCfg.Virtual.LogProfiles, plc.Spec.Profiles.LogProfiles...)
 	}
+	if logging := plc.Spec.Profiles.RequestLogging; logging != nil && len(logging.Servers) > 0 {
+		if err := validateRequestLogging(*logging); err != nil {
+			return fmt.Errorf("Invalid requestLogging in Policy %s/%s: %v", plc.Namespace, plc.Name, err)
+		}
+		rsCfg.Virtual.RequestLogging = RequestLogging(*logging)
+	}
 	var iRule []string
 	// Profiles common for both HTTP and HTTPS
 	// service_HTTP supports profileTCP and profileHTTP
@@ -2121,6 +2808,9 @@ func (ctlr *Controller) handleVSResourceConfigForPolicy(
 			Context:      "http",
 			BigIPProfile: true,
 		})
+	} else if plc.Spec.Profiles.HTTPProfile != (cisapiv1.HTTPProfile{}) {
+		// HTTP profile is created with the settings unless an existing HTTP profile is referred
+		rsCfg.Virtual.HTTPProfile = HTTPProfile(plc.Spec.Profiles.HTTPProfile)
 	}
 
 	switch rsCfg.MetaData.Protocol {
@@ -2165,10 +2855,16 @@ func (ctlr *Controller) handleTSResourceConfigForPolicy(
 	rsCfg.Virtual.ProfileL4 = plc.Spec.Profiles.ProfileL4
 	rsCfg.Virtual.ProfileDOS = plc.Spec.L3Policies.DOS
 	rsCfg.Virtual.ProfileBotDefense = plc.Spec.L3Policies.BotDefense
-	rsCfg.Virtual.TCP.Client = plc.Spec.Profiles.TCP.Client
-	rsCfg.Virtual.TCP.Server = plc.Spec.Profiles.TCP.Server
+	rsCfg.Virtual.TCP = ProfileTCP(plc.Spec.Profiles.TCP)
 	rsCfg.Virtual.AllowVLANs = plc.Spec.L3Policies.AllowVlans
 	rsCfg.Virtual.IpIntelligencePolicy = plc.Spec.L3Policies.IpIntelligencePolicy
+	if plc.Spec.Mirroring != "" {
+		rsCfg.Virtual.Mirroring = plc.Spec.Mirroring
+	}
+	if plc.Spec.PersistenceMirroring {
+		rsCfg.Virtual.PersistenceMirroring = true
+	}
+	rsCfg.Virtual.AnalyticsProfiles.TCPAnalyticsProfile = plc.Spec.Profiles.AnalyticsProfiles.TCPAnalyticsProfile
 
 	if len(plc.Spec.Profiles.LogProfiles) > 0 {
 		rsCfg.Virtual.LogProfiles = append(rsCfg.Virtual.LogProfiles, plc.Spec.Profiles.LogProfiles...)
@@ -2311,9 +3007,9 @@ func (ctlr *Controller) handleRouteTLS(
 			bigIPSSLProfiles.destinationCACertificate = route.Spec.TLS.DestinationCACertificate
 		}
 		// Set DependsOnTLS to true in case of route certificate and defaultSSLProfile
-		if ctlr.resources.baseRouteConfig != (BaseRouteConfig{}) {
+		if !reflect.DeepEqual(ctlr.resources.baseRouteConfig, BaseRouteConfig{}) {
 			//set for default routegroup
-			if ctlr.resources.baseRouteConfig.DefaultRouteGroupConfig != (DefaultRouteGroupConfig{}) {
+			if !reflect.DeepEqual(ctlr.resources.baseRouteConfig.DefaultRouteGroupConfig, DefaultRouteGroupConfig{}) {
 				//Flag to track the route groups which are using TLS profiles.
 				if ctlr.resources.extdSpecMap[ctlr.resources.supplementContextCache.invertedNamespaceLabelMap[route.Namespace]].defaultrg != nil {
 					ctlr.resources.extdSpecMap[ctlr.resources.supplementContextCache.invertedNamespaceLabelMap[route.Namespace]].defaultrg.Meta = Meta{
@@ -2342,9 +3038,9 @@ func (ctlr *Controller) handleRouteTLS(
 			bigIPSSLProfiles.serverSSLs = append(bigIPSSLProfiles.serverSSLs, ctlr.resources.baseRouteConfig.DefaultTLS.ServerSSL)
 		}
 		// Set DependsOnTLS to true in case of route certificate and defaultSSLProfile
-		if ctlr.resources.baseRouteConfig != (BaseRouteConfig{}) {
+		if !reflect.DeepEqual(ctlr.resources.baseRouteConfig, BaseRouteConfig{}) {
 			//Flag to track the route groups which are using TLS Ciphers
-			if ctlr.resources.baseRouteConfig.DefaultRouteGroupConfig != (DefaultRouteGroupConfig{}) {
+			if !reflect.DeepEqual(ctlr.resources.baseRouteConfig.DefaultRouteGroupConfig, DefaultRouteGroupConfig{}) {
 				if ctlr.resources.extdSpecMap[ctlr.resources.supplementContextCache.invertedNamespaceLabelMap[route.Namespace]].defaultrg != nil {
 					ctlr.resources.extdSpecMap[ctlr.resources.supplementContextCache.invertedNamespaceLabelMap[route.Namespace]].defaultrg.Meta = Meta{
 						DependsOnTLS: true,
@@ -2401,7 +3097,7 @@ func (ctlr *Controller) handleRouteTLS(
 		if (isRoutePathBasedABDeployment(route) || isRoutePathBasedRatioDeployment(route, ctlr.haModeType)) &&
 			(route.Spec.TLS.Termination == TLSEdge ||
 				(route.Spec.TLS.Termination == TLSReencrypt && strings.ToLower(string(route.Spec.TLS.InsecureEdgeTerminationPolicy)) != TLSAllowInsecure)) {
-			ctlr.HandlePathBasedABIRule(rsCfg, route.Spec.Host, string(route.Spec.TLS.Termination))
+			ctlr.HandlePathBasedABIRule(rsCfg, string(route.Spec.TLS.Termination))
 		}
 	}
 
@@ -2417,6 +3113,8 @@ func (ctlr *Controller) handleRouteTLS(
 		strings.ToLower(string(route.Spec.TLS.InsecureEdgeTerminationPolicy)),
 		poolPathRefs,
 		bigIPSSLProfiles,
+		cisapiv1.HTTPRedirect{},
+		nil,
 	})
 }
 
@@ -2435,7 +3133,7 @@ func (ctlr *Controller) getSSLProfileOption(route *routeapi.Route, plcSSLProfile
 		sslProfileOption = AnnotationSSLOption
 	} else if route.Spec.TLS != nil && route.Spec.TLS.Key != "" && route.Spec.TLS.Certificate != "" {
 		sslProfileOption = RouteCertificateSSLOption
-	} else if ctlr.resources != nil && ctlr.resources.baseRouteConfig != (BaseRouteConfig{}) &&
+	} else if ctlr.resources != nil && !reflect.DeepEqual(ctlr.resources.baseRouteConfig, BaseRouteConfig{}) &&
 		ctlr.resources.baseRouteConfig.DefaultTLS != (DefaultSSLProfile{}) &&
 		ctlr.resources.baseRouteConfig.DefaultTLS.Reference == BIGIP {
 		sslProfileOption = DefaultSSLOption
@@ -2525,12 +3223,12 @@ func (ctlr *Controller) GetPoolBackends(pool *cisapiv1.Pool) []SvcBackendCxt {
 	clusterSvcMap := make(map[string]struct{})
 	clusterSvcMap[""] = struct{}{} // "" is used as key for the local cluster where this CIS is running
 	// totalClusterRatio stores the sum total of all the ratio of clusters contributing services to this VS
-	totalClusterRatio := float64(*ctlr.clusterRatio[ctlr.multiClusterConfigs.LocalClusterName])
+	totalClusterRatio := float64(ctlr.getClusterRatio(ctlr.multiClusterConfigs.LocalClusterName))
 	// totalSvcWeights stores the sum total of all the weights of services associated with this VS
 	totalSvcWeights := 0.0
 	// Include HA partner cluster ratio in the totalClusterRatio calculation
 	if ctlr.multiClusterConfigs.HAPairClusterName != "" {
-		totalClusterRatio += float64(*ctlr.clusterRatio[ctlr.multiClusterConfigs.HAPairClusterName])
+		totalClusterRatio += float64(ctlr.getClusterRatio(ctlr.multiClusterConfigs.HAPairClusterName))
 	}
 	if pool.Weight != nil {
 		totalSvcWeights = float64(*pool.Weight) * float64(factor)
@@ -2548,9 +3246,9 @@ func (ctlr *Controller) GetPoolBackends(pool *cisapiv1.Pool) []SvcBackendCxt {
 			continue
 		}
 		if _, ok := clusterSvcMap[svc.ClusterName]; !ok {
-			if r, ok := ctlr.clusterRatio[svc.ClusterName]; ok {
+			if _, ok := ctlr.clusterRatio[svc.ClusterName]; ok {
 				clusterSvcMap[svc.ClusterName] = struct{}{}
-				totalClusterRatio += float64(*r)
+				totalClusterRatio += float64(ctlr.getClusterRatio(svc.ClusterName))
 			} else {
 				// Service is from unknown cluster. This case should not arise, but if it does then consider weight to
 				// be 0 as most probably the cluster config may not have been provided in the extended configmap, in
@@ -2591,10 +3289,10 @@ func (ctlr *Controller) GetPoolBackends(pool *cisapiv1.Pool) []SvcBackendCxt {
 	sbcs[beIdx].Name = pool.Service
 	if pool.Weight != nil {
 		sbcs[beIdx].Weight = (float64(*pool.Weight) / totalSvcWeights) *
-			(float64(*ctlr.clusterRatio[ctlr.multiClusterConfigs.LocalClusterName]) / totalClusterRatio)
+			(float64(ctlr.getClusterRatio(ctlr.multiClusterConfigs.LocalClusterName)) / totalClusterRatio)
 	} else {
 		sbcs[beIdx].Weight = (float64(defaultWeight) / totalSvcWeights) *
-			(float64(*ctlr.clusterRatio[ctlr.multiClusterConfigs.LocalClusterName]) / totalClusterRatio)
+			(float64(ctlr.getClusterRatio(ctlr.multiClusterConfigs.LocalClusterName)) / totalClusterRatio)
 	}
 	// VS backend service in HA partner cluster
 	if ctlr.multiClusterConfigs.HAPairClusterName != "" {
@@ -2602,10 +3300,10 @@ func (ctlr *Controller) GetPoolBackends(pool *cisapiv1.Pool) []SvcBackendCxt {
 		sbcs[beIdx].Name = pool.Service
 		if pool.Weight != nil {
 			sbcs[beIdx].Weight = (float64(*pool.Weight) / totalSvcWeights) *
-				(float64(*ctlr.clusterRatio[ctlr.multiClusterConfigs.HAPairClusterName]) / totalClusterRatio)
+				(float64(ctlr.getClusterRatio(ctlr.multiClusterConfigs.HAPairClusterName)) / totalClusterRatio)
 		} else {
 			sbcs[beIdx].Weight = (float64(defaultWeight) / totalSvcWeights) *
-				(float64(*ctlr.clusterRatio[ctlr.multiClusterConfigs.HAPairClusterName]) / totalClusterRatio)
+				(float64(ctlr.getClusterRatio(ctlr.multiClusterConfigs.HAPairClusterName)) / totalClusterRatio)
 		}
 		sbcs[beIdx].Cluster = ctlr.multiClusterConfigs.HAPairClusterName
 	}
@@ -2617,10 +3315,10 @@ func (ctlr *Controller) GetPoolBackends(pool *cisapiv1.Pool) []SvcBackendCxt {
 			sbcs[beIdx].Name = svc.Service
 			if svc.Weight != nil {
 				sbcs[beIdx].Weight = (float64(*svc.Weight) / totalSvcWeights) *
-					(float64(*ctlr.clusterRatio[ctlr.multiClusterConfigs.LocalClusterName]) / totalClusterRatio)
+					(float64(ctlr.getClusterRatio(ctlr.multiClusterConfigs.LocalClusterName)) / totalClusterRatio)
 			} else {
 				sbcs[beIdx].Weight = (float64(defaultWeight) / totalSvcWeights) *
-					(float64(*ctlr.clusterRatio[ctlr.multiClusterConfigs.LocalClusterName]) / totalClusterRatio)
+					(float64(ctlr.getClusterRatio(ctlr.multiClusterConfigs.LocalClusterName)) / totalClusterRatio)
 			}
 			// HA partner cluster
 			if ctlr.multiClusterConfigs.HAPairClusterName != "" {
@@ -2628,10 +3326,10 @@ func (ctlr *Controller) GetPoolBackends(pool *cisapiv1.Pool) []SvcBackendCxt {
 				sbcs[beIdx].Name = svc.Service
 				if svc.Weight != nil {
 					sbcs[beIdx].Weight = (float64(*svc.Weight) / totalSvcWeights) *
-						(float64(*ctlr.clusterRatio[ctlr.multiClusterConfigs.HAPairClusterName]) / totalClusterRatio)
+						(float64(ctlr.getClusterRatio(ctlr.multiClusterConfigs.HAPairClusterName)) / totalClusterRatio)
 				} else {
 					sbcs[beIdx].Weight = (float64(defaultWeight) / totalSvcWeights) *
-						(float64(*ctlr.clusterRatio[ctlr.multiClusterConfigs.HAPairClusterName]) / totalClusterRatio)
+						(float64(ctlr.getClusterRatio(ctlr.multiClusterConfigs.HAPairClusterName)) / totalClusterRatio)
 				}
 				sbcs[beIdx].Cluster = ctlr.multiClusterConfigs.HAPairClusterName
 			}
@@ -2645,10 +3343,10 @@ func (ctlr *Controller) GetPoolBackends(pool *cisapiv1.Pool) []SvcBackendCxt {
 		}
 		beIdx = beIdx + 1
 		sbcs[beIdx].Name = svc.SvcName
-		if r, ok := ctlr.clusterRatio[svc.ClusterName]; ok {
+		if _, ok := ctlr.clusterRatio[svc.ClusterName]; ok {
 			// Here we don't need to check if Weight is nil or not as we have already assigned the default value in case of nil
 			sbcs[beIdx].Weight = (float64(*svc.Weight) / totalSvcWeights) *
-				(float64(*r) / totalClusterRatio)
+				(float64(ctlr.getClusterRatio(svc.ClusterName)) / totalClusterRatio)
 		} else {
 			// Service is from unknown cluster, so set weight to zero which is already set
 			sbcs[beIdx].Weight = 0
@@ -1,9 +1,12 @@
 package controller
 
 import (
+	"encoding/base64"
+	"fmt"
 	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/clustermanager"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sort"
+	"strings"
 
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
 	crdfake "github.com/F5Networks/k8s-bigip-ctlr/v2/config/client/clientset/versioned/fake"
@@ -11,6 +14,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 )
 
@@ -105,11 +109,46 @@ var _ = Describe("Resource Config Tests", func() {
 		It("Pool Name", func() {
 			name := formatPoolName(namespace, "svc1", intstr.IntOrString{IntVal: 80}, "app=test", "foo", "")
 			Expect(name).To(Equal("svc1_80_default_foo_app_test"), "Invalid Pool Name")
+			name = formatPoolName(namespace, "svc1", intstr.IntOrString{IntVal: 80}, "app in (test, prod)", "foo", "")
+			Expect(name).To(Equal("svc1_80_default_foo_app_in__test__prod_"), "Invalid Pool Name")
 		})
 		It("Monitor Name", func() {
 			name := formatMonitorName(namespace, "svc1", "http", intstr.IntOrString{IntVal: 80}, "foo.com", "path")
 			Expect(name).To(Equal("svc1_default_foo_com_path_http_80"), "Invalid Monitor Name")
 		})
+		It("Name Templates", func() {
+			defer setNamingTemplates(NamingTemplates{})
+			setNamingTemplates(NamingTemplates{
+				VirtualServer: "corp_vs_{{.Address}}_{{.Port}}",
+				Pool:          "corp_{{.Namespace}}_{{.Service}}_{{.Port}}{{if .Cluster}}_{{.Cluster}}{{end}}",
+			})
+			Expect(formatVirtualServerName("1.2.3.4", 80)).To(Equal("corp_vs_1_2_3_4_80"), "Invalid VirtualServer Name")
+			Expect(formatPoolName(namespace, "svc1", intstr.IntOrString{IntVal: 80}, "", "foo", "")).To(
+				Equal("corp_default_svc1_80"), "Invalid Pool Name")
+			Expect(formatPoolName(namespace, "svc1", intstr.IntOrString{IntVal: 80}, "", "foo", "cluster2")).To(
+				Equal("corp_default_svc1_80_cluster2"), "Invalid Pool Name")
+
+			// default name is used when the template generates an empty name
+			setNamingTemplates(NamingTemplates{Pool: "{{if .Host}}{{.Name}}{{end}}"})
+			Expect(formatVirtualServerName("1.2.3.4", 80)).To(Equal("crd_1_2_3_4_80"), "Invalid VirtualServer Name")
+			Expect(formatPoolName(namespace, "svc1", intstr.IntOrString{IntVal: 80}, "", "", "")).To(
+				Equal("svc1_80_default"), "Invalid Pool Name")
+
+			Expect(NamingTemplates{VirtualServer: "{{.Unknown}}"}.Validate()).NotTo(BeNil())
+			Expect(NamingTemplates{Pool: "{{.Name"}.Validate()).NotTo(BeNil())
+			Expect(NamingTemplates{Pool: "{{if false}}pool{{end}}"}.Validate()).NotTo(BeNil())
+			Expect(NamingTemplates{VirtualServer: "vs_{{.Port}}", Pool: "{{.Name}}"}.Validate()).To(BeNil())
+		})
+		It("Long Names", func() {
+			svc := strings.Repeat("svc", 70)
+			name := formatPoolName(namespace, svc, intstr.IntOrString{IntVal: 80}, "", "foo.com", "")
+			Expect(len(name)).To(Equal(maxObjectNameLength), "Invalid Pool Name length")
+			Expect(name).To(HavePrefix(svc[:100]))
+			Expect(name).To(Equal(formatPoolName(namespace, svc, intstr.IntOrString{IntVal: 80}, "", "foo.com", "")),
+				"Pool Name should be stable")
+			Expect(name).NotTo(Equal(formatPoolName(namespace, svc, intstr.IntOrString{IntVal: 80}, "", "bar.com", "")),
+				"Pool Names should be unique")
+		})
 		It("Rule Name", func() {
 			name := formatVirtualServerRuleName("test.com", "", "", "sample_pool")
 			Expect(name).To(Equal("vs_test_com_sample_pool"))
@@ -153,6 +192,24 @@ var _ = Describe("Resource Config Tests", func() {
 			Expect(len(rsCfg.IRulesMap)).To(Equal(0), "Failed to remove iRule")
 		})
 
+		It("Attach iRules in order", func() {
+			rsCfg.Virtual.IRules = []string{"/test/My_VS_80_tls_irule", "/Common/policy-irule"}
+			rsCfg.Virtual.AttachIRules([]string{"/Common/irule-1", "/Common/policy-irule", "/Common/irule-2"}, "")
+			Expect(rsCfg.Virtual.IRules).To(Equal([]string{"/test/My_VS_80_tls_irule", "/Common/policy-irule",
+				"/Common/irule-1", "/Common/irule-2"}), "Failed to append iRules")
+
+			rsCfg.Virtual.IRules = []string{"/test/My_VS_80_tls_irule", "/Common/policy-irule"}
+			rsCfg.Virtual.AttachIRules([]string{"/Common/irule-1", "/Common/policy-irule", "/Common/irule-2"}, "high")
+			Expect(rsCfg.Virtual.IRules).To(Equal([]string{"/Common/irule-1", "/Common/policy-irule",
+				"/Common/irule-2", "/test/My_VS_80_tls_irule"}), "Failed to attach iRules with high priority")
+
+			svc := &as3Service{}
+			rsCfg.Virtual.IRules = append(rsCfg.Virtual.IRules, "/Common/irule-1", "none")
+			processIrulesForCRD(rsCfg, svc)
+			Expect(len(svc.IRules.([]interface{}))).To(Equal(4), "Failed to skip repeated iRules")
+			Expect(svc.IRules.([]interface{})[3]).To(Equal("My_VS_80_tls_irule"), "Invalid iRule order")
+		})
+
 		It("Handle DataGroup", func() {
 			dgName := "http_vs_dg"
 			rsCfg.addInternalDataGroup(dgName, partition)
@@ -307,6 +364,465 @@ var _ = Describe("Resource Config Tests", func() {
 			Expect(rsCfg.Pools[0].ServiceNamespace).To(Equal("test"), "Incorrect namespace defined for pool")
 			Expect(rsCfg.Pools[1].ServiceNamespace).To(Equal("test2"), "Incorrect namespace defined for pool")
 		})
+		It("Validate Virtual server config with weighted pools", func() {
+			rsCfg.MetaData.ResourceType = VirtualServer
+			rsCfg.Virtual.Enabled = true
+			rsCfg.Virtual.Name = formatCustomVirtualServerName("My_VS", 80)
+			rsCfg.Virtual.Partition = "test"
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			rsCfg.IRulesMap = make(IRulesMap)
+			weight1 := int32(80)
+			weight2 := int32(20)
+			vs := test.NewVirtualServer(
+				"SampleVS",
+				namespace,
+				cisapiv1.VirtualServerSpec{
+					Host: "test.com",
+					Pools: []cisapiv1.Pool{
+						{
+							Path:        "/foo",
+							Service:     "svc1",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+							Weight:      &weight1,
+						},
+						{
+							Path:        "/foo",
+							Service:     "svc2",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+							Weight:      &weight2,
+						},
+						{
+							Path:        "/bar",
+							Service:     "svc3",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+						},
+					},
+				},
+			)
+			err := mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false)
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+			Expect(len(rsCfg.Pools)).To(Equal(3), "Weighted pools not processed")
+
+			dgName := NameRef{Name: getRSCfgResName(rsCfg.Virtual.Name, AbDeploymentDgName), Partition: "test"}
+			Expect(rsCfg.IntDgMap).To(HaveKey(dgName), "Weighted pools data group not created")
+			records := rsCfg.IntDgMap[dgName][namespace].Records
+			Expect(len(records)).To(Equal(1), "Only the shared path should be weighted")
+			Expect(records[0].Name).To(Equal("test.com/foo"))
+			Expect(records[0].Data).To(Equal(fmt.Sprintf("%s,0.800;%s,1.000",
+				rsCfg.Pools[0].Name, rsCfg.Pools[1].Name)), "Incorrect pool weights")
+			Expect(rsCfg.Virtual.IRules).To(ContainElement(
+				JoinBigipPath("test", getRSCfgResName(rsCfg.Virtual.Name, ABPathIRuleName))), "AB iRule not attached")
+
+			// Virtual server without host matches the weighted paths without the host
+			rsCfg.Pools = nil
+			rsCfg.Virtual.IRules = nil
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			rsCfg.IRulesMap = make(IRulesMap)
+			vs.Spec.Host = ""
+			vs.Spec.Pools[2].Path = "/foo"
+			vs.Spec.Pools[0].Path = "/"
+			vs.Spec.Pools[1].Path = "/"
+			err = mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false)
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+			records = rsCfg.IntDgMap[dgName][namespace].Records
+			Expect(len(records)).To(Equal(1), "Only the shared path should be weighted")
+			Expect(records[0].Name).To(Equal(HostlessABPathKeyPrefix), "Invalid key of the root path without host")
+			Expect(rsCfg.Virtual.IRules).To(ContainElement(
+				JoinBigipPath("test", getRSCfgResName(rsCfg.Virtual.Name, ABPathIRuleName))),
+				"AB iRule not attached to the virtual without host")
+			iRule := rsCfg.IRulesMap[NameRef{Name: getRSCfgResName(rsCfg.Virtual.Name, ABPathIRuleName), Partition: "test"}]
+			Expect(iRule).NotTo(BeNil(), "AB iRule not created")
+			Expect(iRule.Code).To(ContainSubstring(`lappend paths "~[string range $path [string length $host] end]"`),
+				"Entries without host not matched")
+		})
+		It("Validate Virtual server config with path based routing", func() {
+			rsCfg.MetaData.ResourceType = VirtualServer
+			rsCfg.Virtual.Enabled = true
+			rsCfg.Virtual.Name = formatCustomVirtualServerName("My_VS", 80)
+			rsCfg.Virtual.Partition = "test"
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			rsCfg.IRulesMap = make(IRulesMap)
+			vs := test.NewVirtualServer(
+				"SampleVS",
+				namespace,
+				cisapiv1.VirtualServerSpec{
+					Host: "test.com",
+					Pools: []cisapiv1.Pool{
+						{
+							Path:        "/foo",
+							Service:     "svc1",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+						},
+						{
+							Path:          "/foo/bar",
+							PathType:      PathTypeExact,
+							CaseSensitive: true,
+							Service:       "svc2",
+							ServicePort:   intstr.IntOrString{IntVal: 80},
+						},
+						{
+							Path:        "^/api/v[0-9]+/",
+							PathType:    PathTypeRegex,
+							Service:     "svc3",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+						},
+					},
+				},
+			)
+			Expect(mockCtlr.checkValidVirtualServerPaths(vs)).To(BeTrue(), "Valid paths not accepted")
+			err := mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false)
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+			Expect(len(rsCfg.Pools)).To(Equal(3), "Path based pools not processed")
+
+			// Longer and exact paths are matched ahead of the prefix paths
+			rules := rsCfg.Policies[0].Rules
+			Expect(len(rules)).To(Equal(2), "Regex path should not be added to LTM policy")
+			Expect(rules[0].FullURI).To(Equal("test.com/foo/bar"))
+			Expect(rules[1].FullURI).To(Equal("test.com/foo"))
+			for _, cnd := range rules[0].Conditions {
+				if cnd.Path || cnd.PathSegment {
+					Expect(cnd.CaseSensitive).To(BeTrue(), "Case sensitive path not set")
+				}
+			}
+
+			dgName := NameRef{Name: getRSCfgResName(rsCfg.Virtual.Name, RegexPathDgName), Partition: "test"}
+			Expect(rsCfg.IntDgMap).To(HaveKey(dgName), "Regex path data group not created")
+			records := rsCfg.IntDgMap[dgName][namespace].Records
+			Expect(len(records)).To(Equal(1))
+			Expect(records[0].Name).To(Equal(`0002 ^test\.com$ ^/api/v[0-9]+/`))
+			Expect(records[0].Data).To(Equal(rsCfg.Pools[2].Name + ",false"))
+			Expect(rsCfg.Virtual.IRules).To(ContainElement(
+				JoinBigipPath("test", getRSCfgResName(rsCfg.Virtual.Name, RegexPathIRuleName))), "Regex path iRule not attached")
+
+			vs.Spec.Pools[2].Path = `^/api\\b\.json$`
+			Expect(mockCtlr.checkValidVirtualServerPaths(vs)).To(BeTrue(), "Escaped backslash not accepted")
+			vs.Spec.Pools[2].Path = `^/api\b`
+			Expect(mockCtlr.checkValidVirtualServerPaths(vs)).To(BeFalse(), "Regex not supported by TCL accepted")
+			vs.Spec.Pools[2].Path = "(?i)^/api/"
+			Expect(mockCtlr.checkValidVirtualServerPaths(vs)).To(BeFalse(), "Regex with embedded flags accepted")
+			vs.Spec.Pools[2].Path = "^/api/(v[0-9]+"
+			Expect(mockCtlr.checkValidVirtualServerPaths(vs)).To(BeFalse(), "Invalid regex path accepted")
+			vs.Spec.Pools[2].PathType = "Suffix"
+			Expect(mockCtlr.checkValidVirtualServerPaths(vs)).To(BeFalse(), "Invalid pathType accepted")
+			vs.Spec.Pools[2].PathType = PathTypePrefix
+			Expect(mockCtlr.checkValidVirtualServerPaths(vs)).To(BeFalse(), "Regex accepted as prefix path")
+		})
+		It("Validate Virtual server config with host and path regex", func() {
+			rsCfg.MetaData.ResourceType = VirtualServer
+			rsCfg.Virtual.Enabled = true
+			rsCfg.Virtual.Name = formatCustomVirtualServerName("My_VS", 80)
+			rsCfg.Virtual.Partition = "test"
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			rsCfg.IRulesMap = make(IRulesMap)
+			vs := test.NewVirtualServer(
+				"SampleVS",
+				namespace,
+				cisapiv1.VirtualServerSpec{
+					Host: "test.com",
+					Pools: []cisapiv1.Pool{
+						{
+							Path:        "/foo",
+							HostRegex:   `^(www|api)\.test\.com$`,
+							Service:     "svc1",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+						},
+						{
+							PathRegex:   `\.php$`,
+							Service:     "svc2",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+						},
+						{
+							Path:        "/bar",
+							Service:     "svc3",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+						},
+					},
+				},
+			)
+			Expect(mockCtlr.checkValidVirtualServerPaths(vs)).To(BeTrue(), "Valid regex not accepted")
+			err := mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false)
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+			Expect(len(rsCfg.Pools)).To(Equal(3), "Regex pools not processed")
+			Expect(len(rsCfg.Policies[0].Rules)).To(Equal(1), "Regex pools should not be added to LTM policy")
+
+			dgName := NameRef{Name: getRSCfgResName(rsCfg.Virtual.Name, RegexPathDgName), Partition: "test"}
+			var keys []string
+			for _, record := range rsCfg.IntDgMap[dgName][namespace].Records {
+				keys = append(keys, record.Name)
+			}
+			// records are ordered as the pools of the spec
+			Expect(keys).To(Equal([]string{`0000 ^(www|api)\.test\.com$ ^/foo(/|$)`, `0001 ^test\.com$ \.php$`}))
+			iRule := rsCfg.IRulesMap[NameRef{Name: getRSCfgResName(rsCfg.Virtual.Name, RegexPathIRuleName), Partition: "test"}]
+			Expect(iRule.Code).To(ContainSubstring(`foreach record [lsort -index 0 [class get /test/Shared/`+
+				rsCfg.Virtual.Name+`_regex_path_dg]]`), "Regex records not matched in order")
+
+			vs.Spec.Pools[0].HostRegex = "^www .test.com$"
+			Expect(mockCtlr.checkValidVirtualServerPaths(vs)).To(BeFalse(), "Host regex with whitespace accepted")
+			vs.Spec.Pools[0].HostRegex = `^\pL+\.test\.com$`
+			Expect(mockCtlr.checkValidVirtualServerPaths(vs)).To(BeFalse(), "Host regex not supported by TCL accepted")
+			vs.Spec.Pools[0].HostRegex = ""
+			vs.Spec.Pools[1].PathRegex = `\.php\z`
+			Expect(mockCtlr.checkValidVirtualServerPaths(vs)).To(BeFalse(), "Path regex not supported by TCL accepted")
+			vs.Spec.Pools[1].PathRegex = "(.php"
+			Expect(mockCtlr.checkValidVirtualServerPaths(vs)).To(BeFalse(), "Invalid path regex accepted")
+		})
+		It("Validate Virtual server config with wildcard host", func() {
+			rsCfg.MetaData.ResourceType = VirtualServer
+			rsCfg.Virtual.Enabled = true
+			rsCfg.Virtual.Name = formatCustomVirtualServerName("My_VS", 80)
+			rsCfg.Virtual.Partition = "test"
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			rsCfg.IRulesMap = make(IRulesMap)
+			wildcardVS := test.NewVirtualServer(
+				"WildcardVS",
+				namespace,
+				cisapiv1.VirtualServerSpec{
+					Host: "*.test.com",
+					Pools: []cisapiv1.Pool{
+						{
+							Path:        "/foo",
+							Service:     "svc1",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+						},
+					},
+				},
+			)
+			exactVS := test.NewVirtualServer(
+				"ExactVS",
+				namespace,
+				cisapiv1.VirtualServerSpec{
+					Host: "app.test.com",
+					Pools: []cisapiv1.Pool{
+						{
+							Path:        "/",
+							Service:     "svc2",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+						},
+					},
+				},
+			)
+			err := mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, wildcardVS, false)
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+			err = mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, exactVS, false)
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+
+			// Exact host is matched ahead of the wildcard host with longer path
+			rules := rsCfg.Policies[0].Rules
+			Expect(len(rules)).To(Equal(2))
+			Expect(rules[0].FullURI).To(Equal("app.test.com"))
+			Expect(rules[1].FullURI).To(Equal("*.test.com/foo"))
+
+			iRule := mockCtlr.GetPathBasedABDeployIRule(rsCfg.Virtual.Name, rsCfg.Virtual.Partition)
+			Expect(iRule).To(ContainSubstring(`lappend paths ".[domain $host`), "Wildcard host lookup missing in A/B iRule")
+			iRule = mockCtlr.getTLSIRule(rsCfg.Virtual.Name, rsCfg.Virtual.Partition, nil)
+			Expect(iRule).To(ContainSubstring(`class match -value $wc_host equals $passthru_class`), "Wildcard host lookup missing in passthrough")
+		})
+		It("Validate Virtual server config with error page", func() {
+			rsCfg.MetaData.ResourceType = VirtualServer
+			rsCfg.Virtual.Enabled = true
+			rsCfg.Virtual.Name = formatCustomVirtualServerName("My_VS", 80)
+			rsCfg.Virtual.Partition = "test"
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			rsCfg.IRulesMap = make(IRulesMap)
+			vs := test.NewVirtualServer(
+				"SampleVS",
+				namespace,
+				cisapiv1.VirtualServerSpec{
+					Host: "test.com",
+					Pools: []cisapiv1.Pool{
+						{
+							Path:        "/foo",
+							Service:     "svc1",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+						},
+					},
+					ErrorPage: &cisapiv1.ErrorPage{
+						StatusCode:  404,
+						ContentType: "application/json",
+						Body:        `{"error": "not found"}`,
+					},
+				},
+			)
+			Expect(checkValidErrorPage(vs)).To(BeTrue(), "Valid error page not accepted")
+			err := mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false)
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+
+			iRuleName := getRSCfgResName(rsCfg.Virtual.Name, ErrorPageIRuleName)
+			Expect(rsCfg.Virtual.IRules).To(ContainElement(JoinBigipPath("test", iRuleName)), "Error page iRule not attached")
+			iRule := rsCfg.IRulesMap[NameRef{Name: iRuleName, Partition: "test"}]
+			Expect(iRule).NotTo(BeNil(), "Error page iRule not created")
+			Expect(iRule.Code).To(ContainSubstring(`HTTP::respond 404 content [b64decode "eyJlcnJvciI6ICJub3QgZm91bmQifQ=="]`))
+			Expect(iRule.Code).To(ContainSubstring(`"Content-Type" "application/json"`))
+
+			// error page entry is created from the spec of the VirtualServer
+			rsCfg.Virtual.SetVirtualAddress("10.8.3.11", DEFAULT_HTTPS_PORT)
+			tlsContext := TLSContext{
+				name:          vs.Name,
+				namespace:     namespace,
+				resourceType:  VirtualServer,
+				referenceType: BIGIP,
+				vsHostname:    vs.Spec.Host,
+				httpsPort:     DEFAULT_HTTPS_PORT,
+				httpPort:      DEFAULT_HTTP_PORT,
+				ipAddress:     "10.8.3.11",
+				termination:   TLSEdge,
+				poolPathRefs: []poolPathRef{
+					{path: "/foo", poolName: "svc1_80_default", aliasHostnames: []string{vs.Spec.Host}},
+				},
+				bigIPSSLProfiles: BigIPSSLProfiles{clientSSLs: []string{"/Common/clientssl"}},
+				errorPage:        vs.Spec.ErrorPage,
+			}
+			Expect(mockCtlr.handleTLS(rsCfg, tlsContext)).To(BeTrue(), "Failed to handle TLS of the VirtualServer")
+			dgName := getRSCfgResName(rsCfg.Virtual.Name, DefaultPoolsDgName)
+			dg, ok := rsCfg.IntDgMap[NameRef{Name: dgName, Partition: "test"}]
+			Expect(ok).To(BeTrue(), "Default pool data group not created")
+			Expect(dg[namespace].Records).To(ContainElement(InternalDataGroupRecord{Name: ErrorPage, Data: "true"}),
+				"Error page entry missing in the data group")
+			tlsIRule := mockCtlr.getTLSIRule(rsCfg.Virtual.Name, rsCfg.Virtual.Partition, nil)
+			Expect(tlsIRule).To(ContainSubstring(`if { not $error_page } {
+                        set dflt_pool [class match -value "defaultPool" equals $default_class]`),
+				"Default pool not selected without the error page")
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			tlsContext.errorPage = nil
+			Expect(mockCtlr.handleTLS(rsCfg, tlsContext)).To(BeTrue(), "Failed to handle TLS of the VirtualServer")
+			Expect(rsCfg.IntDgMap).NotTo(HaveKey(NameRef{Name: dgName, Partition: "test"}),
+				"Error page entry created without the error page")
+
+			// Default pool takes precedence over the error page
+			rsCfg.Virtual.IRules = nil
+			rsCfg.IRulesMap = make(IRulesMap)
+			vs.Spec.DefaultPool = cisapiv1.DefaultPool{
+				Reference: BIGIP,
+				Name:      "/Common/default_pool",
+			}
+			err = mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false)
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+			Expect(rsCfg.IRulesMap).NotTo(HaveKey(NameRef{Name: iRuleName, Partition: "test"}), "Error page iRule created with default pool")
+
+			vs.Spec.ErrorPage.StatusCode = 700
+			Expect(checkValidErrorPage(vs)).To(BeFalse(), "Invalid status code accepted")
+			vs.Spec.ErrorPage.StatusCode = 503
+			vs.Spec.ErrorPage.ContentType = `text/html" "Connection`
+			Expect(checkValidErrorPage(vs)).To(BeFalse(), "Invalid content type accepted")
+		})
+		It("Validate Virtual server config with pool mirror", func() {
+			rsCfg.MetaData.ResourceType = VirtualServer
+			rsCfg.Virtual.Enabled = true
+			rsCfg.Virtual.Name = formatCustomVirtualServerName("My_VS", 80)
+			rsCfg.Virtual.Partition = "test"
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			rsCfg.IRulesMap = make(IRulesMap)
+			vs := test.NewVirtualServer(
+				"SampleVS",
+				namespace,
+				cisapiv1.VirtualServerSpec{
+					Host: "test.com",
+					Pools: []cisapiv1.Pool{
+						{
+							Path:        "/foo",
+							Service:     "svc1",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+							Mirror: &cisapiv1.Mirror{
+								Service:     "svc-shadow",
+								ServicePort: intstr.IntOrString{IntVal: 8080},
+								Percentage:  10,
+							},
+						},
+						{
+							Path:        "/",
+							Service:     "svc2",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+							Mirror: &cisapiv1.Mirror{
+								Service:     "svc-shadow",
+								ServicePort: intstr.IntOrString{IntVal: 8080},
+							},
+						},
+					},
+				},
+			)
+			Expect(checkValidPoolMirrors(vs)).To(BeTrue(), "Valid pool mirror not accepted")
+			err := mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false)
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+
+			// mirror pool is shared by the pools mirrored to the same service
+			Expect(rsCfg.Pools).To(HaveLen(3), "Mirror pool not created")
+			mirrorPool := "/test/Shared/" + formatPoolName(namespace, "svc-shadow", intstr.IntOrString{IntVal: 8080}, "", "test.com", "")
+			dgName := NameRef{Name: getRSCfgResName(rsCfg.Virtual.Name, PoolMirrorDgName), Partition: "test"}
+			Expect(rsCfg.IntDgMap[dgName][namespace].Records).To(ConsistOf(
+				InternalDataGroupRecord{
+					Name: "/test/Shared/" + formatPoolName(namespace, "svc1", intstr.IntOrString{IntVal: 80}, "", "test.com", ""),
+					Data: mirrorPool + ",10",
+				},
+				InternalDataGroupRecord{
+					Name: "/test/Shared/" + formatPoolName(namespace, "svc2", intstr.IntOrString{IntVal: 80}, "", "test.com", ""),
+					Data: mirrorPool + ",100",
+				},
+			))
+			iRuleName := getRSCfgResName(rsCfg.Virtual.Name, PoolMirrorIRuleName)
+			Expect(rsCfg.Virtual.IRules).To(ContainElement(JoinBigipPath("test", iRuleName)), "Pool mirror iRule not attached")
+			Expect(rsCfg.IRulesMap).To(HaveKey(NameRef{Name: iRuleName, Partition: "test"}), "Pool mirror iRule not created")
+
+			vs.Spec.Pools[0].Mirror.Percentage = 101
+			Expect(checkValidPoolMirrors(vs)).To(BeFalse(), "Invalid percentage accepted")
+			vs.Spec.Pools[0].Mirror.Percentage = 10
+			vs.Spec.Pools[0].Mirror.Service = ""
+			Expect(checkValidPoolMirrors(vs)).To(BeFalse(), "Mirror without service accepted")
+		})
+		It("Validate Virtual server config with maintenance mode", func() {
+			rsCfg.MetaData.ResourceType = VirtualServer
+			rsCfg.Virtual.Enabled = true
+			rsCfg.Virtual.Name = formatCustomVirtualServerName("My_VS", 80)
+			rsCfg.Virtual.Partition = "test"
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			rsCfg.IRulesMap = make(IRulesMap)
+			vs := test.NewVirtualServer(
+				"SampleVS",
+				namespace,
+				cisapiv1.VirtualServerSpec{
+					Host: "test.com",
+					Pools: []cisapiv1.Pool{
+						{
+							Path:        "/foo",
+							Service:     "svc1",
+							ServicePort: intstr.IntOrString{IntVal: 80},
+						},
+					},
+					MaintenanceMode: &cisapiv1.MaintenanceMode{
+						Enabled: true,
+						Secret:  "maintenance",
+					},
+				},
+			)
+			dgName := NameRef{Name: getRSCfgResName(rsCfg.Virtual.Name, MaintenanceDgName), Partition: "test"}
+
+			// Default response is used when the secret is not found
+			err := mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false)
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+			Expect(rsCfg.Pools).To(HaveLen(1), "Pools not preserved in maintenance mode")
+			Expect(rsCfg.Policies).To(BeEmpty(), "Policy rules created in maintenance mode")
+			Expect(rsCfg.IntDgMap[dgName][namespace].Records).To(Equal(InternalDataGroupRecords{{
+				Name: "test.com",
+				Data: "text/html|" + base64.StdEncoding.EncodeToString([]byte(DefaultMaintenanceBody)),
+			}}))
+			iRuleName := getRSCfgResName(rsCfg.Virtual.Name, MaintenanceIRuleName)
+			Expect(rsCfg.Virtual.IRules).To(ContainElement(JoinBigipPath("test", iRuleName)), "Maintenance iRule not attached")
+			Expect(rsCfg.IRulesMap[NameRef{Name: iRuleName, Partition: "test"}].Code).To(ContainSubstring("HTTP::respond 503"))
+
+			mockCtlr.addSecret(&v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "maintenance", Namespace: namespace},
+				Data: map[string][]byte{
+					MaintenanceBodyKey:        []byte(`{"status": "maintenance"}`),
+					MaintenanceContentTypeKey: []byte("application/json"),
+				},
+			})
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			err = mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false)
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+			Expect(rsCfg.IntDgMap[dgName][namespace].Records).To(Equal(InternalDataGroupRecords{{
+				Name: "test.com",
+				Data: "application/json|eyJzdGF0dXMiOiAibWFpbnRlbmFuY2UifQ==",
+			}}))
+		})
 		It("Validate Virtual server config with multiple monitors(tcp and http)", func() {
 			rsCfg.MetaData.ResourceType = VirtualServer
 			rsCfg.Virtual.Enabled = true
@@ -366,6 +882,98 @@ var _ = Describe("Resource Config Tests", func() {
 
 		})
 
+		It("Validate Virtual server config with minimum monitors and inband monitor", func() {
+			rsCfg.MetaData.ResourceType = VirtualServer
+			rsCfg.Virtual.Enabled = true
+			rsCfg.Virtual.Name = formatCustomVirtualServerName("My_VS", 80)
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			rsCfg.IRulesMap = make(IRulesMap)
+
+			monitors := []cisapiv1.Monitor{
+				{
+					Type:     "http",
+					Send:     "GET /health",
+					Interval: 15,
+					Timeout:  10,
+				},
+				{
+					Type:     InbandMonitor,
+					Interval: 30,
+					Timeout:  10,
+				},
+			}
+			vs := test.NewVirtualServer(
+				"SampleVS",
+				namespace,
+				cisapiv1.VirtualServerSpec{
+					Host: "test.com",
+					Pools: []cisapiv1.Pool{
+						{
+							Path:            "/foo",
+							Service:         "svc1",
+							Monitors:        monitors,
+							MinimumMonitors: intstr.FromInt(1),
+						},
+						{
+							Path:            "/bar",
+							Service:         "svc2",
+							Monitors:        monitors,
+							MinimumMonitors: intstr.FromString("all"),
+						},
+						{
+							Path:            "/baz",
+							Service:         "svc3",
+							Monitors:        monitors,
+							MinimumMonitors: intstr.FromInt(3),
+						},
+					},
+				},
+			)
+			err := mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false)
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+			Expect(len(rsCfg.Pools)).To(Equal(3))
+			Expect(rsCfg.Pools[0].MonitorNames).To(HaveLen(2))
+			Expect(*rsCfg.Pools[0].MinimumMonitors).To(Equal(intstr.FromInt(1)))
+			Expect(*rsCfg.Pools[1].MinimumMonitors).To(Equal(intstr.FromString("all")))
+			// minimumMonitors more than the monitors of the pool is ignored
+			Expect(rsCfg.Pools[2].MinimumMonitors).To(BeNil())
+		})
+
+		It("Validate Virtual server config with service down action", func() {
+			rsCfg.MetaData.ResourceType = VirtualServer
+			rsCfg.Virtual.Enabled = true
+			rsCfg.Virtual.Name = formatCustomVirtualServerName("My_VS", 80)
+			rsCfg.IntDgMap = make(InternalDataGroupMap)
+			rsCfg.IRulesMap = make(IRulesMap)
+
+			vs := test.NewVirtualServer(
+				"SampleVS",
+				namespace,
+				cisapiv1.VirtualServerSpec{
+					Host: "test.com",
+					Pools: []cisapiv1.Pool{
+						{
+							Path:              "/foo",
+							Service:           "svc1",
+							ServiceDownAction: "reselect",
+							ReselectTries:     3,
+						},
+						{
+							Path:              "/bar",
+							Service:           "svc2",
+							ServiceDownAction: "restart",
+						},
+					},
+				},
+			)
+			err := mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false)
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+			Expect(len(rsCfg.Pools)).To(Equal(2))
+			Expect(rsCfg.Pools[0].ServiceDownAction).To(Equal("reselect"))
+			Expect(rsCfg.Pools[0].ReselectTries).To(Equal(int32(3)))
+			Expect(rsCfg.Pools[1].ServiceDownAction).To(BeEmpty(), "Invalid serviceDownAction should be ignored")
+		})
+
 		It("Validate default pool in Virtual server with svc", func() {
 			rsCfg.MetaData.ResourceType = VirtualServer
 			rsCfg.Virtual.Enabled = true
@@ -562,6 +1170,65 @@ var _ = Describe("Resource Config Tests", func() {
 			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from TransportServer")
 		})
 
+		It("Prepare Resource Config with PROXY protocol", func() {
+			rsCfg.Virtual.Name = "SampleTS_443"
+			rsCfg.Virtual.Partition = "test"
+			ts := test.NewTransportServer(
+				"SampleTS",
+				namespace,
+				cisapiv1.TransportServerSpec{
+					Pool:          cisapiv1.Pool{Service: "svc1", ServicePort: intstr.IntOrString{IntVal: 443}},
+					ProxyProtocol: ProxyProtocolV2,
+				},
+			)
+			err := mockCtlr.prepareRSConfigFromTransportServer(rsCfg, ts)
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from TransportServer")
+			iRuleName := getRSCfgResName(rsCfg.Virtual.Name, ProxyProtocolIRuleName)
+			iRule := rsCfg.IRulesMap[NameRef{Name: iRuleName, Partition: rsCfg.Virtual.Partition}]
+			Expect(iRule).NotTo(BeNil(), "PROXY protocol iRule not created")
+			Expect(iRule.Code).To(Equal(mockCtlr.GetProxyProtocolIRule(ProxyProtocolV2)), "Invalid PROXY protocol iRule")
+			Expect(rsCfg.Virtual.IRules).To(Equal([]string{JoinBigipPath(rsCfg.Virtual.Partition, iRuleName)}))
+
+			// PROXY protocol of the first resource of the virtual is retained
+			mockCtlr.handleProxyProtocol(rsCfg, ProxyProtocolV1)
+			Expect(iRule.Code).To(Equal(mockCtlr.GetProxyProtocolIRule(ProxyProtocolV2)), "PROXY protocol updated")
+			Expect(mockCtlr.GetProxyProtocolIRule(ProxyProtocolV1)).To(ContainSubstring("PROXY $proto"))
+
+			rsCfg = &ResourceConfig{}
+			mockCtlr.handleProxyProtocol(rsCfg, ProxyProtocolDisabled)
+			Expect(rsCfg.Virtual.IRules).To(BeEmpty(), "PROXY protocol iRule attached when disabled")
+		})
+
+		It("Prepare Resource Config from a TransportServer with SNI routes", func() {
+			ts := test.NewTransportServer(
+				"SampleTS",
+				namespace,
+				cisapiv1.TransportServerSpec{
+					Pool: cisapiv1.Pool{Service: "svc1", ServicePort: intstr.IntOrString{IntVal: 443}},
+					SNIRoutes: []cisapiv1.SNIRoute{
+						{Host: "foo.com", Pool: cisapiv1.Pool{Service: "svc2", ServicePort: intstr.IntOrString{IntVal: 443}}},
+						{Host: "*.Bar.com", Pool: cisapiv1.Pool{Service: "svc3", ServicePort: intstr.IntOrString{IntVal: 443}}},
+					},
+				},
+			)
+			err := mockCtlr.prepareRSConfigFromTransportServer(rsCfg, ts)
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from TransportServer")
+			Expect(len(rsCfg.Pools)).To(Equal(3), "Pools of the SNI routes not created")
+			Expect(rsCfg.Virtual.PoolName).To(Equal(rsCfg.Pools[0].Name), "Invalid default pool")
+
+			dgName := getRSCfgResName(rsCfg.Virtual.Name, SNIRoutesDgName)
+			dg := rsCfg.IntDgMap[NameRef{Name: dgName, Partition: rsCfg.Virtual.Partition}][namespace]
+			Expect(dg).NotTo(BeNil(), "SNI routes data group not created")
+			Expect(dg.Records).To(Equal(InternalDataGroupRecords{
+				{Name: ".bar.com", Data: rsCfg.Pools[2].Name},
+				{Name: "foo.com", Data: rsCfg.Pools[1].Name},
+			}), "Invalid SNI routes data group")
+
+			iRuleName := getRSCfgResName(rsCfg.Virtual.Name, SNIRoutesIRuleName)
+			Expect(rsCfg.IRulesMap).To(HaveKey(NameRef{Name: iRuleName, Partition: rsCfg.Virtual.Partition}))
+			Expect(rsCfg.Virtual.IRules).To(ContainElement(JoinBigipPath(rsCfg.Virtual.Partition, iRuleName)))
+		})
+
 		It("Prepare Resource Config from a Service", func() {
 			svcPort := v1.ServicePort{
 				Name:     "port1",
@@ -756,6 +1423,36 @@ var _ = Describe("Resource Config Tests", func() {
 			Expect(removed).To(BeFalse(), "Validation Failed while Removing Record from DataGroup ")
 
 		})
+
+		It("Passthrough DataGroup of the VirtualServers sharing the virtual", func() {
+			intDgMap := make(InternalDataGroupMap)
+			hosts := []string{"foo.com", "bar.com", "*.baz.com"}
+			// VirtualServer of foo.com with the TLSProfile of all the hosts
+			updatePassthroughDataGroup(intDgMap, []poolPathRef{{"/", "foo_pool", hosts}}, "vs_443", "foo.com",
+				"ns1", DEFAULT_PARTITION)
+			mapKey := NameRef{Name: getRSCfgResName("vs_443", PassthroughHostsDgName), Partition: DEFAULT_PARTITION}
+			Expect(intDgMap[mapKey]["ns1"].Records).To(Equal(InternalDataGroupRecords{
+				{Name: ".baz.com", Data: "foo_pool"},
+				{Name: "bar.com", Data: "foo_pool"},
+				{Name: "foo.com", Data: "foo_pool"},
+			}), "Invalid passthrough records")
+
+			// VirtualServer of bar.com claims its host, other hosts are retained
+			updatePassthroughDataGroup(intDgMap, []poolPathRef{{"/", "bar_pool", hosts}}, "vs_443", "bar.com",
+				"ns2", DEFAULT_PARTITION)
+			Expect(intDgMap[mapKey]["ns1"].Records).To(Equal(InternalDataGroupRecords{
+				{Name: ".baz.com", Data: "foo_pool"},
+				{Name: "foo.com", Data: "foo_pool"},
+			}), "Host of the VirtualServer not removed from the other namespace")
+			Expect(intDgMap[mapKey]["ns2"].Records).To(Equal(InternalDataGroupRecords{
+				{Name: "bar.com", Data: "bar_pool"},
+			}), "Invalid passthrough records")
+
+			// hostless VirtualServer does not add the empty host
+			updatePassthroughDataGroup(intDgMap, []poolPathRef{{"/", "default_pool", []string{""}}}, "vs_443", "",
+				"ns2", DEFAULT_PARTITION)
+			Expect(intDgMap[mapKey]["ns2"].Records).To(HaveLen(1), "Empty host added to passthrough records")
+		})
 	})
 
 	It("Validate TLS Profiles", func() {
@@ -1055,9 +1752,58 @@ var _ = Describe("Resource Config Tests", func() {
 				},
 			}
 
-			ltmCfg := rs.getLTMConfigDeepCopy()
+			ltmCfg := rs.getLTMConfigSnapshot()
 			Expect(len(ltmCfg)).To(Equal(1), "Wrong number of Partitions")
 			Expect(len(ltmCfg["default"].ResourceMap)).To(Equal(2), "Wrong number of ResourceConfigs")
+			Expect(ltmCfg["default"].ResourceMap["virtualServer1"]).To(BeIdenticalTo(
+				rs.ltmConfig["default"].ResourceMap["virtualServer1"]), "ResourceConfig copied in the snapshot")
+
+			// snapshot is not updated with the store
+			rs.ltmConfig["default"].ResourceMap["virtualServer1"] = &ResourceConfig{
+				Virtual: Virtual{
+					Name: "VirtualServer3",
+				},
+			}
+			delete(rs.ltmConfig["default"].ResourceMap, "virtualServer2")
+			Expect(ltmCfg["default"].ResourceMap["virtualServer1"].Virtual.Name).To(Equal("VirtualServer1"))
+			Expect(len(ltmCfg["default"].ResourceMap)).To(Equal(2), "Snapshot updated with the store")
+		})
+
+		It("Builds the declarations from the snapshot without updating the store", func() {
+			zero := 0
+			newRsCfg := func() *ResourceConfig {
+				rsCfg := &ResourceConfig{}
+				rsCfg.MetaData.ResourceType = VirtualServer
+				rsCfg.MetaData.Protocol = "https"
+				rsCfg.Virtual.Name = "crd_1_2_3_4_443"
+				rsCfg.Virtual.Destination = "/default/1.2.3.4:443"
+				rsCfg.Virtual.PoolName = "default_svc1_80"
+				rsCfg.Virtual.TLSTermination = TLSPassthrough
+				rsCfg.Virtual.PersistenceMirroring = true
+				rsCfg.Pools = Pools{{Name: "default_svc1_80", Partition: "default",
+					Members: []PoolMember{{Address: "10.1.1.1", Port: 8080}}}}
+				return rsCfg
+			}
+			rs.ltmConfig["default"] = &PartitionConfig{ResourceMap: ResourceMap{"crd_1_2_3_4_443": newRsCfg()},
+				Priority: &zero}
+			ltmCfg := rs.getLTMConfigSnapshot()
+
+			// declaration is built by the agent while the controller reads the store
+			done := make(chan as3Application)
+			go func() {
+				sharedApp := as3Application{}
+				processResourcesForAS3(ltmCfg["default"].ResourceMap, sharedApp, false, "default")
+				done <- sharedApp
+			}()
+			Expect(rs.ltmConfig["default"].ResourceMap["crd_1_2_3_4_443"]).To(Equal(newRsCfg()))
+			sharedApp := <-done
+			_, ok := sharedApp["crd_1_2_3_4_443"].(*as3Service)
+			Expect(ok).To(BeTrue(), "Service not declared")
+			persist, ok := sharedApp["crd_1_2_3_4_443_persist"].(*as3Persist)
+			Expect(ok).To(BeTrue(), "Persistence of the passthrough virtual not mirrored")
+			Expect(persist.PersistenceMethod).To(Equal("tls-session-id"))
+			Expect(rs.ltmConfig["default"].ResourceMap["crd_1_2_3_4_443"]).To(Equal(newRsCfg()),
+				"Defaults of the declaration written to the store")
 		})
 	})
 
@@ -1241,10 +1987,92 @@ var _ = Describe("Resource Config Tests", func() {
 			tlsProf.Spec.TLS.ClientSSL = "/Common/clientssl"
 			tlsProf.Spec.TLS.ServerSSL = "/Common/serverssl"
 
+			vs.Spec.HTTPRedirect.ExcludePaths = []string{"/static/"}
+
 			ok := mockCtlr.handleVirtualServerTLS(inSecRsCfg, vs, tlsProf, ip)
 			Expect(ok).To(BeTrue(), "Failed to Handle insecure virtual with Redirect config")
 			Expect(len(inSecRsCfg.IRulesMap)).To(Equal(1))
 			Expect(len(inSecRsCfg.Virtual.IRules)).To(Equal(1))
+
+			// paths excluded by the virtuals without host sharing the iRule are merged
+			vs2 := vs.DeepCopy()
+			vs2.Name = "SampleVS2"
+			vs2.Spec.HTTPRedirect.ExcludePaths = []string{"/.well-known/acme-challenge"}
+			ok = mockCtlr.handleVirtualServerTLS(inSecRsCfg, vs2, tlsProf, ip)
+			Expect(ok).To(BeTrue(), "Failed to Handle insecure virtual with Redirect config")
+			Expect(len(inSecRsCfg.IRulesMap)).To(Equal(1))
+			for _, iRule := range inSecRsCfg.IRulesMap {
+				Expect(iRule.Code).To(Equal(httpRedirectIRuleNoHost(DEFAULT_HTTPS_PORT, inSecRsCfg.Virtual.Name,
+					inSecRsCfg.Virtual.Partition, 0)))
+				Expect(iRule.Code).NotTo(ContainSubstring("/static"))
+			}
+			dgName := NameRef{Name: getRSCfgResName(inSecRsCfg.Virtual.Name, HttpsRedirectExclusionsDgName), Partition: inSecRsCfg.Virtual.Partition}
+			Expect(inSecRsCfg.IntDgMap).To(HaveKey(dgName), "Redirect exclusions data group not created")
+			records := inSecRsCfg.IntDgMap[dgName][namespace].Records
+			Expect(len(records)).To(Equal(1))
+			Expect(records[0].Name).To(Equal(HostlessABPathKeyPrefix))
+			Expect(records[0].Data).To(Equal("/static|/.well-known/acme-challenge"))
+		})
+
+		It("Handle HTTP Server when Redirect with status code and excluded paths", func() {
+			vs.Spec.TLSProfileName = "SampleTLS"
+			vs.Spec.HTTPTraffic = TLSRedirectInsecure
+			vs.Spec.Pools = []cisapiv1.Pool{
+				{
+					Path:    "/",
+					Service: "svc1",
+				},
+				{
+					Path:    "/.well-known/acme-challenge",
+					Service: "cm-acme-http-solver",
+				},
+			}
+			vs.Spec.HTTPRedirect = cisapiv1.HTTPRedirect{
+				StatusCode:   308,
+				ExcludePaths: []string{"/.well-known/acme-challenge", "/static/"},
+			}
+			Expect(checkValidHTTPRedirect(vs)).To(BeTrue(), "Valid httpRedirect not accepted")
+			tlsProf.Spec.TLS.Termination = TLSEdge
+			tlsProf.Spec.TLS.Reference = BIGIP
+			tlsProf.Spec.TLS.ClientSSL = "/Common/clientssl"
+
+			// Excluded paths are forwarded on the HTTP virtual
+			mockCtlr.multiClusterResources = newMultiClusterResourceStore()
+			err := mockCtlr.prepareRSConfigFromVirtualServer(inSecRsCfg, vs, false)
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+			Expect(len(inSecRsCfg.Policies)).To(Equal(1), "Policy not created for excluded paths")
+			rules := inSecRsCfg.Policies[0].Rules
+			Expect(len(rules)).To(Equal(2))
+			Expect(rules[0].FullURI).To(Equal("test.com/.well-known/acme-challenge"))
+			Expect(rules[0].Actions[0].Pool).To(ContainSubstring("cm_acme_http_solver"))
+			Expect(rules[1].FullURI).To(Equal("test.com/static"))
+			Expect(rules[1].Actions[0].Pool).To(ContainSubstring("svc1"))
+
+			ok := mockCtlr.handleVirtualServerTLS(inSecRsCfg, vs, tlsProf, ip)
+			Expect(ok).To(BeTrue(), "Failed to Handle insecure virtual with Redirect config")
+			ruleName := fmt.Sprintf("%s_%d", getRSCfgResName(inSecRsCfg.Virtual.Name, HttpRedirectIRuleName), DEFAULT_HTTPS_PORT)
+			iRule := inSecRsCfg.IRulesMap[NameRef{Name: ruleName, Partition: inSecRsCfg.Virtual.Partition}]
+			Expect(iRule).NotTo(BeNil(), "Redirect iRule not created")
+			Expect(iRule.Code).To(ContainSubstring(`HTTP::respond 308 Location "https://[getfield [HTTP::host] ":" 1]:443[HTTP::uri]"`))
+			Expect(iRule.Code).NotTo(ContainSubstring("HTTP::redirect"))
+
+			dgName := NameRef{Name: getRSCfgResName(inSecRsCfg.Virtual.Name, HttpsRedirectExclusionsDgName), Partition: inSecRsCfg.Virtual.Partition}
+			Expect(inSecRsCfg.IntDgMap).To(HaveKey(dgName), "Redirect exclusions data group not created")
+			records := inSecRsCfg.IntDgMap[dgName][namespace].Records
+			Expect(len(records)).To(Equal(1))
+			Expect(records[0].Name).To(Equal("test.com"))
+			Expect(records[0].Data).To(Equal("/.well-known/acme-challenge|/static"))
+
+			iRuleCode := httpRedirectIRuleNoHost(DEFAULT_HTTPS_PORT, inSecRsCfg.Virtual.Name, inSecRsCfg.Virtual.Partition, 301)
+			Expect(iRuleCode).To(ContainSubstring(fmt.Sprintf(`class match -value "%s" equals /%s/Shared/%s`,
+				HostlessABPathKeyPrefix, inSecRsCfg.Virtual.Partition, dgName.Name)))
+			Expect(iRuleCode).To(ContainSubstring("HTTP::respond 301 Location"))
+
+			vs.Spec.HTTPRedirect.StatusCode = 304
+			Expect(checkValidHTTPRedirect(vs)).To(BeFalse(), "Invalid redirect status code accepted")
+			vs.Spec.HTTPRedirect.StatusCode = 301
+			vs.Spec.HTTPRedirect.ExcludePaths = []string{"static"}
+			Expect(checkValidHTTPRedirect(vs)).To(BeFalse(), "Invalid excluded path accepted")
 		})
 
 		It("Handle HTTP Server when Allow with Edge", func() {
@@ -1464,6 +2292,16 @@ var _ = Describe("Resource Config Tests", func() {
 			Expect(err).To(BeNil(), "Failed to handle VirtualServer for policy")
 			Expect(rsCfg.Virtual.SNAT).To(Equal(plc.Spec.SNAT), "SNAT should be set to none")
 
+			// mirroring of the virtual is not reset by the policy without mirroring
+			rsCfg.Virtual.Mirroring = "L4"
+			err = mockCtlr.handleVSResourceConfigForPolicy(rsCfg, plc)
+			Expect(err).To(BeNil(), "Failed to handle VirtualServer for policy")
+			Expect(rsCfg.Virtual.Mirroring).To(Equal("L4"), "Mirroring reset by the policy")
+			plc.Spec.Mirroring = "none"
+			err = mockCtlr.handleVSResourceConfigForPolicy(rsCfg, plc)
+			Expect(err).To(BeNil(), "Failed to handle VirtualServer for policy")
+			Expect(rsCfg.Virtual.Mirroring).To(Equal("none"), "Mirroring of the policy not set")
+
 			plc.Spec.SNAT = "/Common/snatpool"
 			err = mockCtlr.handleVSResourceConfigForPolicy(rsCfg, plc)
 			Expect(err).To(BeNil(), "Failed to handle VirtualServer for policy")
@@ -1500,6 +2338,63 @@ var _ = Describe("Resource Config Tests", func() {
 
 		})
 
+		It("Verifies analytics profiles of Policy and VirtualServer", func() {
+			rsCfg.MetaData.Protocol = HTTP
+			plc.Spec.Profiles.AnalyticsProfiles = cisapiv1.AnalyticsProfiles{
+				HTTPAnalyticsProfile: "/Common/plc-http-analytics",
+				TCPAnalyticsProfile:  "/Common/plc-tcp-analytics",
+			}
+			err := mockCtlr.handleVSResourceConfigForPolicy(rsCfg, plc)
+			Expect(err).To(BeNil(), "Failed to handle VirtualServer for policy")
+			Expect(rsCfg.Virtual.AnalyticsProfiles.TCPAnalyticsProfile).To(Equal("/Common/plc-tcp-analytics"))
+
+			vs := test.NewVirtualServer(
+				"SamplevS",
+				namespace,
+				cisapiv1.VirtualServerSpec{
+					Profiles: cisapiv1.ProfileSpec{
+						AnalyticsProfiles: cisapiv1.AnalyticsProfiles{TCPAnalyticsProfile: "/Common/vs-tcp-analytics"},
+					},
+				},
+			)
+			err = mockCtlr.prepareRSConfigFromVirtualServer(rsCfg, vs, false)
+			Expect(err).To(BeNil(), "Failed to Prepare Resource Config from VirtualServer")
+			Expect(rsCfg.Virtual.AnalyticsProfiles).To(Equal(AnalyticsProfiles{
+				HTTPAnalyticsProfile: "/Common/plc-http-analytics",
+				TCPAnalyticsProfile:  "/Common/vs-tcp-analytics",
+			}), "VirtualServer analytics profile should take precedence over Policy")
+		})
+
+		It("Verifies request logging of Policy", func() {
+			plc.Spec.Profiles.RequestLogging = &cisapiv1.RequestLogging{
+				Servers:  []string{"10.10.10.20"},
+				Port:     514,
+				Protocol: "tcp",
+			}
+			err := mockCtlr.handleVSResourceConfigForPolicy(rsCfg, plc)
+			Expect(err).To(BeNil(), "Failed to handle VirtualServer for policy")
+			Expect(rsCfg.Virtual.RequestLogging).To(Equal(RequestLogging(*plc.Spec.Profiles.RequestLogging)))
+
+			rsCfg.Virtual.RequestLogging = RequestLogging{}
+			plc.Spec.Profiles.RequestLogging.Port = 0
+			err = mockCtlr.handleVSResourceConfigForPolicy(rsCfg, plc)
+			Expect(err).To(MatchError(ContainSubstring("port 0 is not in the range 1-65535")))
+			Expect(rsCfg.Virtual.RequestLogging.Servers).To(BeEmpty())
+
+			plc.Spec.Profiles.RequestLogging.Port = 65536
+			err = mockCtlr.handleVSResourceConfigForPolicy(rsCfg, plc)
+			Expect(err).To(MatchError(ContainSubstring("port 65536 is not in the range 1-65535")))
+
+			plc.Spec.Profiles.RequestLogging.Port = 514
+			plc.Spec.Profiles.RequestLogging.Protocol = "http"
+			err = mockCtlr.handleVSResourceConfigForPolicy(rsCfg, plc)
+			Expect(err).To(MatchError(ContainSubstring("protocol http is not supported")))
+
+			plc.Spec.Profiles.RequestLogging.Protocol = ""
+			err = mockCtlr.handleVSResourceConfigForPolicy(rsCfg, plc)
+			Expect(err).To(BeNil(), "Failed to handle VirtualServer for policy")
+		})
+
 		It("Verifies SNAT whether is set properly for TransportServer", func() {
 			err := mockCtlr.handleTSResourceConfigForPolicy(rsCfg, plc)
 			Expect(err).To(BeNil(), "Failed to handle TransportServer for policy")
@@ -9,28 +9,53 @@ import (
 
 	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
 	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
 
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
 )
 
 func (ctlr *Controller) enqueueReq(config ResourceConfigRequest) int {
 	rm := requestMeta{
-		partitionMap: make(map[string]map[string]string, len(config.ltmConfig)),
+		partitionMap:     make(map[string]map[string]string, len(config.ltmConfig)),
+		ingressAddresses: make(map[string]string),
+		as3Objects:       make(map[string]map[string]map[string]string, len(config.ltmConfig)),
+		virtualServers:   make(map[string][]cisapiv1.BigIPVirtualServer),
 	}
 	if ctlr.requestQueue.Len() == 0 {
 		rm.id = 1
 	} else {
 		rm.id = ctlr.requestQueue.Back().Value.(requestMeta).id + 1
 	}
+	ctlr.setResourceFinalizersRequest(rm.id)
+	ctlr.setManagedVirtualServers(config)
+	ctlr.setManagedPoolMembers(config)
 
 	for partition, partitionConfig := range config.ltmConfig {
 		rm.partitionMap[partition] = make(map[string]string)
+		rm.as3Objects[partition] = make(map[string]map[string]string)
 		for _, cfg := range partitionConfig.ResourceMap {
+			for _, name := range getAS3ObjectNames(cfg) {
+				if rm.as3Objects[partition][name] == nil {
+					rm.as3Objects[partition][name] = make(map[string]string)
+				}
+				for key, val := range cfg.MetaData.baseResources {
+					rm.as3Objects[partition][name][key] = val
+				}
+			}
 			for key, val := range cfg.MetaData.baseResources {
 				rm.partitionMap[partition][key] = val
+				if val == Ingress && cfg.Virtual.VirtualAddress != nil {
+					rm.ingressAddresses[key] = cfg.Virtual.VirtualAddress.BindAddr
+				}
+				if val == VirtualServer {
+					rm.virtualServers[key] = append(rm.virtualServers[key], getBigIPVirtualServer(partition, cfg))
+				}
 			}
 		}
 	}
+	for _, virtuals := range rm.virtualServers {
+		sortBigIPVirtualServers(virtuals)
+	}
 
 	ctlr.requestQueue.Lock()
 	ctlr.requestQueue.PushBack(rm)
@@ -45,6 +70,16 @@ func (ctlr *Controller) responseHandler(respChan chan resourceStatusMeta) {
 	for rscUpdateMeta := range respChan {
 
 		rm := ctlr.dequeueReq(rscUpdateMeta.id, len(rscUpdateMeta.failedTenants))
+		if rm.id != 0 {
+			// remove the finalizers of the deleted resources once their configuration is removed from BIG-IP
+			ctlr.removeResourceFinalizers(rm.id, rscUpdateMeta.failedTenants)
+		}
+		// record the AS3 errors of the failed tenants on the resources of the objects in the errors
+		for tenant, message := range rscUpdateMeta.tenantErrors {
+			rscs := ctlr.updateAS3ErrorStatus(rm, tenant, message)
+			ctlr.quarantineResources(rscs, message)
+		}
+		ctlr.updateRESTFrameworkStatus(rm, rscUpdateMeta.failedTenants)
 		for partition, meta := range rm.partitionMap {
 			// Check if it's a priority tenant and not in failedTenants map, if so then update the priority back to zero
 			// Priority tenant doesn't have any meta
@@ -77,7 +112,7 @@ func (ctlr *Controller) responseHandler(respChan chan resourceStatusMeta) {
 					if virtual.Namespace+"/"+virtual.Name == rscKey {
 						if _, found := rscUpdateMeta.failedTenants[partition]; !found {
 							// update the status for virtual server as tenant posting is success
-							ctlr.updateVirtualServerStatus(virtual, virtual.Status.VSAddress, "Ok")
+							ctlr.updateVirtualServerStatus(virtual, virtual.Status.VSAddress, "Ok", rm.virtualServers[rscKey])
 							// Update Corresponding Service Status of Type LB
 							for _, pool := range virtual.Spec.Pools {
 								var svcNamespace string
@@ -128,6 +163,26 @@ func (ctlr *Controller) responseHandler(respChan chan resourceStatusMeta) {
 							}
 						}
 					}
+				case Ingress:
+					// update status
+					crInf, ok := ctlr.getNamespacedCRInformer(ns)
+					if !ok || crInf.ingInformer == nil {
+						log.Debugf("Ingress Informer not found for namespace: %v", ns)
+						continue
+					}
+					obj, exist, err := crInf.ingInformer.GetIndexer().GetByKey(rscKey)
+					if err != nil {
+						log.Debugf("Could not fetch Ingress: %v: %v", rscKey, err)
+						continue
+					}
+					if !exist {
+						log.Debugf("Ingress Not Found: %v", rscKey)
+						continue
+					}
+					if _, found := rscUpdateMeta.failedTenants[partition]; !found {
+						// update the status for ingress as tenant posting is success
+						ctlr.updateIngressStatus(obj.(*netv1.Ingress), rm.ingressAddresses[rscKey])
+					}
 				case Route:
 					if _, found := rscUpdateMeta.failedTenants[partition]; found {
 						// TODO : distinguish between a 503 and an actual failure
@@ -175,7 +230,7 @@ func (ctlr *Controller) dequeueReq(id int, failedTenantsLen int) requestMeta {
 
 func (ctlr *Controller) removeUnusedIPAMEntries(kind string) {
 	// Remove Unused IPAM entries in IPAM CR after CIS restarts, applicable to only first PostCall
-	if !ctlr.firstPostResponse && ctlr.ipamCli != nil && (kind == VirtualServer || kind == TransportServer) {
+	if !ctlr.firstPostResponse && ctlr.ipamCli != nil && (kind == VirtualServer || kind == TransportServer || kind == Ingress) {
 		ctlr.firstPostResponse = true
 		toRemoveIPAMEntries := &ficV1.IPAM{
 			ObjectMeta: metav1.ObjectMeta{
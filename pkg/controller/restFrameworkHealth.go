@@ -0,0 +1,195 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/prometheus"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConditionBigIPRESTUnhealthy is the VirtualServer and TransportServer status condition set when the
+	// declaration of the resource fails as the REST framework of BIG-IP is not serving the REST calls
+	ConditionBigIPRESTUnhealthy = "BigIPRESTUnhealthy"
+	// maximum interval the posts are delayed while the REST framework of BIG-IP is unhealthy
+	restFrameworkMaxBackoff = 5 * time.Minute
+	// remediation hint of the memory exhaustion of restjavad
+	restFrameworkRemediationHint = "increase the memory of restjavad with 'tmsh modify sys db " +
+		"restjavad.useextramb value true' and 'tmsh modify sys db provision.extramb value <MB>', then " +
+		"restart the REST framework with 'bigstart restart restjavad restnoded'"
+)
+
+// restFrameworkErrors are the messages of the responses of restjavad and restnoded out of memory
+var restFrameworkErrors = []string{"restjavad", "restnoded", "outofmemoryerror", "java heap space"}
+
+// isRESTFrameworkError checks whether the response is of the REST framework of BIG-IP not serving the REST calls.
+// httpd of BIG-IP responds with 502 and 503 without the JSON body of the REST framework when restjavad or
+// restnoded are down, such as when restjavad runs out of memory with large declarations
+func isRESTFrameworkError(statusCode int, body []byte) bool {
+	lowerBody := strings.ToLower(string(body))
+	for _, msg := range restFrameworkErrors {
+		if statusCode >= http.StatusInternalServerError && strings.Contains(lowerBody, msg) {
+			return true
+		}
+	}
+	switch statusCode {
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return true
+	case http.StatusServiceUnavailable:
+		// AS3 busy with the earlier declaration responds with 503 and the JSON body
+		return !json.Valid(bytes.TrimSpace(body))
+	}
+	return false
+}
+
+// checkRESTFrameworkHealth records the health of the REST framework of BIG-IP with the response of the REST call
+func (postMgr *PostManager) checkRESTFrameworkHealth(statusCode int, body []byte) {
+	health := &postMgr.restFrameworkHealth
+	health.Lock()
+	defer health.Unlock()
+	if !isRESTFrameworkError(statusCode, body) {
+		if health.unhealthy {
+			log.Infof("[AS3] REST framework of BIG-IP is serving the REST calls again")
+			prometheus.BigIPRESTFrameworkUnhealthy.Set(0)
+		}
+		health.unhealthy = false
+		health.failures = 0
+		return
+	}
+	health.failures++
+	prometheus.BigIPRESTFrameworkErrors.WithLabelValues(fmt.Sprintf("%v", statusCode)).Inc()
+	if health.unhealthy {
+		return
+	}
+	health.unhealthy = true
+	prometheus.BigIPRESTFrameworkUnhealthy.Set(1)
+	log.Errorf("[AS3] REST framework of BIG-IP is not serving the REST calls, responded with code %v. "+
+		"restjavad may be out of memory, %v", statusCode, restFrameworkRemediationHint)
+}
+
+// isRESTFrameworkUnhealthy checks whether the REST framework of BIG-IP failed the last REST call
+func (postMgr *PostManager) isRESTFrameworkUnhealthy() bool {
+	postMgr.restFrameworkHealth.Lock()
+	defer postMgr.restFrameworkHealth.Unlock()
+	return postMgr.restFrameworkHealth.unhealthy
+}
+
+// getRESTFrameworkBackoff returns the interval the posts are delayed while the REST framework of BIG-IP is
+// unhealthy, interval is doubled with every failed REST call up to the maximum interval
+func (postMgr *PostManager) getRESTFrameworkBackoff() time.Duration {
+	postMgr.restFrameworkHealth.Lock()
+	defer postMgr.restFrameworkHealth.Unlock()
+	if !postMgr.restFrameworkHealth.unhealthy {
+		return 0
+	}
+	backoff := timeoutMedium
+	for i := 1; i < postMgr.restFrameworkHealth.failures && backoff < restFrameworkMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > restFrameworkMaxBackoff {
+		backoff = restFrameworkMaxBackoff
+	}
+	return backoff
+}
+
+// backOffRESTFramework delays the post while the REST framework of BIG-IP is unhealthy, so that restjavad
+// recovers without the load of the declarations. sys db variables of restjavad memory are set once after the
+// delay when the remediation is enabled
+func (postMgr *PostManager) backOffRESTFramework() {
+	backoff := postMgr.getRESTFrameworkBackoff()
+	if backoff == 0 {
+		return
+	}
+	log.Warningf("[AS3] REST framework of BIG-IP is unhealthy, delaying the post to BIG-IP for %v", backoff)
+	<-time.After(backoff)
+	if postMgr.RESTJavadExtraMB <= 0 || postMgr.restFrameworkHealth.remediated {
+		return
+	}
+	if err := postMgr.remediateRESTFramework(); err != nil {
+		log.Errorf("[AS3] Unable to increase the memory of restjavad: %v", err)
+		return
+	}
+	postMgr.restFrameworkHealth.remediated = true
+	log.Warningf("[AS3] Set restjavad.useextramb to true and provision.extramb to %v MB on BIG-IP, restart the "+
+		"REST framework with 'bigstart restart restjavad restnoded' to apply", postMgr.RESTJavadExtraMB)
+}
+
+// remediateRESTFramework sets the sys db variables of BIG-IP increasing the memory of restjavad
+func (postMgr *PostManager) remediateRESTFramework() error {
+	dbValues := []struct {
+		name  string
+		value string
+	}{
+		{"restjavad.useextramb", "true"},
+		{"provision.extramb", fmt.Sprintf("%v", postMgr.RESTJavadExtraMB)},
+	}
+	for _, db := range dbValues {
+		body := fmt.Sprintf(`{"value":"%v"}`, db.value)
+		req, err := http.NewRequest("PATCH", postMgr.getDeviceBaseURL()+"/mgmt/tm/sys/db/"+db.name,
+			strings.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		postMgr.setAuthHeader(req)
+		httpResp, _ := postMgr.httpReq(req)
+		if httpResp == nil {
+			return fmt.Errorf("Internal Error")
+		}
+		if httpResp.StatusCode != http.StatusOK {
+			return fmt.Errorf("Error response from BIGIP for %v with status code %v", db.name, httpResp.StatusCode)
+		}
+	}
+	return nil
+}
+
+// updateRESTFrameworkStatus sets the status condition of the VirtualServers and TransportServers of the failed
+// tenants while the REST framework of BIG-IP is unhealthy, condition is removed once the tenant is posted
+func (ctlr *Controller) updateRESTFrameworkStatus(rm requestMeta, failedTenants map[string]struct{}) {
+	unhealthy := ctlr.Agent != nil && ctlr.Agent.PostManager != nil && ctlr.Agent.isRESTFrameworkUnhealthy()
+	for partition, meta := range rm.partitionMap {
+		_, failed := failedTenants[partition]
+		for rscKey, kind := range meta {
+			if kind != VirtualServer && kind != TransportServer {
+				continue
+			}
+			rsc := ctlr.getBaseResource(kind, rscKey)
+			if rsc == nil {
+				continue
+			}
+			if !failed || !unhealthy {
+				ctlr.removeResourceCondition(rsc, ConditionBigIPRESTUnhealthy)
+				continue
+			}
+			ctlr.setResourceCondition(rsc, metav1.Condition{
+				Type:               ConditionBigIPRESTUnhealthy,
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: rsc.GetGeneration(),
+				Reason:             "RESTFrameworkUnavailable",
+				Message:            "REST framework of BIG-IP is not serving the declarations, " + restFrameworkRemediationHint,
+			})
+		}
+	}
+}
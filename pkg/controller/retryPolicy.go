@@ -0,0 +1,181 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/prometheus"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+)
+
+// getRetryInterval returns the interval before the retry of the failed tenants. Interval is doubled with every
+// retry up to the maximum interval and the jitter adds up to the given fraction of the interval
+func (postMgr *PostManager) getRetryInterval(retries int) time.Duration {
+	interval := timeoutMedium
+	if postMgr.AS3RetryInterval > 0 {
+		interval = time.Duration(postMgr.AS3RetryInterval) * time.Second
+	}
+	baseInterval := interval
+	maxInterval := time.Duration(postMgr.AS3RetryMaxInterval) * time.Second
+	for i := 0; i < retries && interval < maxInterval; i++ {
+		interval *= 2
+	}
+	if interval > maxInterval && maxInterval > baseInterval {
+		interval = maxInterval
+	}
+	if postMgr.AS3RetryJitter > 0 {
+		interval += time.Duration(rand.Float64() * postMgr.AS3RetryJitter * float64(interval))
+	}
+	return interval
+}
+
+// isRetryExhausted checks whether the failed tenant is retried for the maximum retries, such tenants are
+// posted again with the next configuration update. Tenants exceeding the declaration size limit are not retried
+func (postMgr *PostManager) isRetryExhausted(cfg *tenantParams) bool {
+	if cfg.agentResponseCode == http.StatusRequestEntityTooLarge {
+		return true
+	}
+	return postMgr.AS3MaxRetries > 0 && cfg.taskId == "" && cfg.retries >= postMgr.AS3MaxRetries
+}
+
+// hasPendingRetries checks whether any of the failed tenants is to be retried or polled
+func (agent *Agent) hasPendingRetries() bool {
+	for _, cfg := range agent.retryTenantDeclMap {
+		if !agent.isRetryExhausted(cfg) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordRESTCallResult records whether BIG-IP is reachable for the circuit breaker. Circuit breaker is opened
+// after the consecutive REST call errors and closed with the first REST call reaching BIG-IP
+func (postMgr *PostManager) recordRESTCallResult(reachable bool) {
+	if postMgr.CircuitBreakerThreshold <= 0 {
+		return
+	}
+	cb := &postMgr.circuitBreaker
+	cb.Lock()
+	defer cb.Unlock()
+	if reachable {
+		if cb.open {
+			log.Infof("[AS3] BIG-IP is reachable, closing the circuit breaker")
+			cb.open = false
+			cb.resync = true
+			prometheus.CircuitBreakerOpen.Set(0)
+		}
+		cb.failures = 0
+		return
+	}
+	cb.failures++
+	if !cb.open && cb.failures >= postMgr.CircuitBreakerThreshold {
+		log.Errorf("[AS3] BIG-IP is unreachable after %v consecutive REST call errors, opening the circuit breaker",
+			cb.failures)
+		cb.open = true
+		prometheus.CircuitBreakerOpen.Set(1)
+	}
+}
+
+// isCircuitOpen checks whether the declarations are not posted to the unreachable BIG-IP
+func (postMgr *PostManager) isCircuitOpen() bool {
+	postMgr.circuitBreaker.Lock()
+	defer postMgr.circuitBreaker.Unlock()
+	return postMgr.circuitBreaker.open
+}
+
+// isResyncRequired checks and resets whether the tenants are to be posted again after the circuit breaker is closed
+func (postMgr *PostManager) isResyncRequired() bool {
+	postMgr.circuitBreaker.Lock()
+	defer postMgr.circuitBreaker.Unlock()
+	resync := postMgr.circuitBreaker.resync
+	postMgr.circuitBreaker.resync = false
+	return resync
+}
+
+// getCircuitBreakerCooldown returns the interval of probing the unreachable BIG-IP
+func (postMgr *PostManager) getCircuitBreakerCooldown() time.Duration {
+	if postMgr.CircuitBreakerCooldown > 0 {
+		return time.Duration(postMgr.CircuitBreakerCooldown) * time.Second
+	}
+	return timeoutMedium
+}
+
+// probeBIGIP queries the AS3 info of BIG-IP so that the circuit breaker is closed once BIG-IP is reachable
+func (postMgr *PostManager) probeBIGIP() {
+	req, err := http.NewRequest("GET", postMgr.getAS3VersionURL(), nil)
+	if err != nil {
+		log.Errorf("[AS3] Creating new HTTP request error: %v ", err)
+		return
+	}
+	postMgr.setAuthHeader(req)
+	postMgr.httpPOST(req)
+}
+
+// circuitBreakerWorker probes BIG-IP while the circuit breaker is open and resumes the posts with
+// the full-state sync once BIG-IP is reachable again
+func (agent *Agent) circuitBreakerWorker() {
+	for {
+		<-time.After(agent.getCircuitBreakerCooldown())
+		if agent.isCircuitOpen() {
+			log.Debugf("[AS3] Probing the unreachable BIG-IP")
+			agent.probeBIGIP()
+		}
+		if agent.isResyncRequired() {
+			agent.resyncTenants()
+		}
+	}
+}
+
+// resyncTenants posts the last configuration request again with all the tenants, as the configuration
+// of BIG-IP is not known after the outage
+func (agent *Agent) resyncTenants() {
+	agent.declUpdate.Lock()
+	defer agent.declUpdate.Unlock()
+	log.Infof("[AS3] Resuming the posts to BIG-IP with the full-state sync of the tenants")
+	// tenants are retained in the cache so that the deleted tenants are still removed
+	for tenant := range agent.cachedTenantDeclMap {
+		agent.cachedTenantDeclMap[tenant] = nil
+	}
+	agent.retryTenantDeclMap = make(map[string]*tenantParams)
+	if agent.lastConfig.ltmConfig == nil {
+		return
+	}
+	rsConfig := agent.lastConfig
+	// as with the retries, the response updates the status of the request retained for the failed tenants
+	rsConfig.reqId = 0
+	select {
+	case agent.postChan <- rsConfig:
+	default:
+		// a newer configuration request is already queued
+	}
+}
+
+// readinessHandler reports the controller degraded while the circuit breaker is open
+func (agent *Agent) readinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if agent.PostManager != nil && agent.isCircuitOpen() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("Degraded: BIG-IP is unreachable"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("Ok"))
+	})
+}
@@ -22,8 +22,11 @@ import (
 	routeapi "github.com/openshift/api/route/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
+	"encoding/base64"
 	"encoding/json"
+	"net/http"
 	"net/url"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -57,11 +60,16 @@ func (ctlr *Controller) prepareVirtualServerRules(
 
 	}
 
+	weightedPools := ctlr.getWeightedPools(vs)
 	for _, pl := range vs.Spec.Pools {
 		// Service cannot be empty
 		if pl.Service == "" {
 			continue
 		}
+		// Regex paths can't be expressed by LTM policy, these are handled by regex path iRule
+		if isRegexPool(&pl) {
+			continue
+		}
 		// If not using WAF from policy CR, use Pool Based WAF from VS
 		wafPolicy := ""
 		if rsCfg.Virtual.WAF == "" {
@@ -89,6 +97,9 @@ func (ctlr *Controller) prepareVirtualServerRules(
 		if (pl.AlternateBackends != nil && len(pl.AlternateBackends) > 0) || ctlr.haModeType == Ratio {
 			skipPool = true
 		}
+		if _, ok := weightedPools[pl.Path]; ok {
+			skipPool = true
+		}
 		for _, backend := range poolBackends {
 			poolName := ctlr.framePoolNameForVs(
 				vs.ObjectMeta.Namespace,
@@ -103,6 +114,12 @@ func (ctlr *Controller) prepareVirtualServerRules(
 				log.Errorf("Error configuring rule: %v", err)
 				return nil
 			}
+			if pl.PathType == PathTypeExact {
+				setExactPathCondition(rl, pl.Path)
+			}
+			if pl.CaseSensitive {
+				setCaseSensitivePathConditions(rl)
+			}
 			if pl.HostRewrite != "" {
 				hostRewriteActions, err := getHostRewriteActions(
 					pl.HostRewrite,
@@ -142,7 +159,8 @@ func (ctlr *Controller) prepareVirtualServerRules(
 		return nil
 	}
 
-	if rlMap[vs.Spec.Host] == nil && len(redirects) == 2 {
+	// root path rules of the weighted pools are in redirects without the app root
+	if vs.Spec.RewriteAppRoot != "" && rlMap[vs.Spec.Host] == nil && len(redirects) == 2 {
 		rl := &Rule{
 			Name:    formatVirtualServerRuleName(vs.Spec.Host, vs.Spec.HostGroup, "", redirects[1].Actions[0].Pool),
 			FullURI: vs.Spec.Host,
@@ -297,6 +315,30 @@ func createRule(uri, poolName, ruleName string, allowSourceRange []string, wafPo
 	return &rl, nil
 }
 
+// setExactPathCondition adds a condition matching the complete path to the rule,
+// path segment conditions are retained so that the exact path rule is ordered
+// ahead of the prefix rules of the same path
+func setExactPathCondition(rl *Rule, path string) {
+	rl.Conditions = append(rl.Conditions, &condition{
+		Name:    "0",
+		Equals:  true,
+		HTTPURI: true,
+		Index:   0,
+		Path:    true,
+		Request: true,
+		Values:  []string{path},
+	})
+}
+
+// setCaseSensitivePathConditions enables case sensitive match of the rule path conditions
+func setCaseSensitivePathConditions(rl *Rule) {
+	for _, cnd := range rl.Conditions {
+		if cnd.Path || cnd.PathSegment {
+			cnd.CaseSensitive = true
+		}
+	}
+}
+
 func createPathSegmentConditions(u *url.URL) []*condition {
 
 	var c []*condition
@@ -461,6 +503,22 @@ func (rules Rules) Len() int {
 func (rules Rules) Less(i, j int) bool {
 	ruleI := rules[i]
 	ruleJ := rules[j]
+	// Strategy 0: Rules with wildcard host are evaluated after the other rules,
+	// so that exact host takes precedence irrespective of the path
+	wildcardHost := func(rule *Rule) bool {
+		for _, cnd := range rule.Conditions {
+			if cnd.Host && cnd.EndsWith {
+				return true
+			}
+		}
+		return false
+	}
+	wcI := wildcardHost(ruleI)
+	wcJ := wildcardHost(ruleJ)
+	if wcI != wcJ {
+		return wcJ
+	}
+
 	// Strategy 1: Rule with Highest number of conditions
 	l1 := len(ruleI.Conditions)
 	l2 := len(ruleJ.Conditions)
@@ -526,32 +584,70 @@ func (rules Rules) Swap(i, j int) {
 	rules[i], rules[j] = rules[j], rules[i]
 }
 
+// httpRedirectCommand returns the iRule command redirecting the request
+// to the location with the status code, HTTP::redirect responds with 302
+func httpRedirectCommand(statusCode int32, location string) string {
+	if statusCode == 0 || statusCode == http.StatusFound {
+		return fmt.Sprintf("HTTP::redirect %s", location)
+	}
+	return fmt.Sprintf(`HTTP::respond %d Location "%s"`, statusCode, location)
+}
+
 // httpRedirectIRuleNoHost redirects traffic to BIG-IP https vs
 // for hostLess CRDs.
-func httpRedirectIRuleNoHost(port int32) string {
-	// The key in the data group is the host name or * to match all.
-	// The data is a list of paths for the host delimited by '|' or '/' for all.
+func httpRedirectIRuleNoHost(port int32, rsVSName string, partition string, statusCode int32) string {
+	// The key of the paths excluded from redirect in the exclusions data group is HostlessABPathKeyPrefix and
+	// the data is a list of paths excluded by all the virtuals without host delimited by '|'
+	exclusionsDgName := "/" + partition + "/" + Shared + "/" + rsVSName + "_" + HttpsRedirectExclusionsDgName
 	iRuleCode := fmt.Sprintf(`
 		when HTTP_REQUEST {
-			HTTP::redirect https://[getfield [HTTP::host] ":" 1]:%d[HTTP::uri]	
-		}`, port)
+			# skip the redirect for the paths excluded from redirect
+			if { [class exists %[1]s] } {
+				foreach exclude_path [split [class match -value "%[2]s" equals %[1]s] "|"] {
+					if {[HTTP::path] equals $exclude_path || [HTTP::path] starts_with "$exclude_path/"} {
+						return
+					}
+				}
+			}
+			%[3]s
+		}`, exclusionsDgName, HostlessABPathKeyPrefix,
+		httpRedirectCommand(statusCode, fmt.Sprintf(`https://[getfield [HTTP::host] ":" 1]:%d[HTTP::uri]`, port)))
 	return iRuleCode
 }
 
 // httpRedirectIRule redirects traffic to BIG-IP https vs
 // except for the hostLess CRDs.
-func httpRedirectIRule(port int32, rsVSName string, partition string) string {
+func httpRedirectIRule(port int32, rsVSName string, partition string, statusCode int32) string {
 	// The key in the data group is the host name or * to match all.
 	// The data is a list of paths for the host delimited by '|' or '/' for all.
 	dgName := "/" + partition + "/" + Shared + "/" + rsVSName + "_https_redirect_dg"
+	// The key in the exclusions data group is the host name and
+	// the data is a list of paths excluded from redirect delimited by '|'
+	exclusionsDgName := "/" + partition + "/" + Shared + "/" + rsVSName + "_" + HttpsRedirectExclusionsDgName
 	iRuleCode := fmt.Sprintf(`
 		when HTTP_REQUEST {
 			
+			# skip the redirect for the paths excluded from redirect
+			if { [class exists %[3]s] } {
+				set exclusion_host [string tolower [getfield [HTTP::host] ":" 1]]
+				set exclusions [class match -value $exclusion_host equals %[3]s]
+				if {$exclusions == ""} {
+					# Check for wildcard domain
+					set domain_length [llength [split $exclusion_host "."]]
+					set exclusions [class match -value ".[domain $exclusion_host [expr {$domain_length - 1}]]" equals %[3]s]
+				}
+				foreach exclude_path [split $exclusions "|"] {
+					if {[HTTP::path] equals $exclude_path || [HTTP::path] starts_with "$exclude_path/"} {
+						return
+					}
+				}
+			}
+
 			# check if there is an entry in data-groups to accept requests from all domains.
 			# */ represents [* -> Any host / -> default path]
 			set allHosts [class match -value "*/" equals %[1]s]
 			if {$allHosts != ""} {
-				HTTP::redirect https://[getfield [HTTP::host] ":" 1]:443[HTTP::uri]
+				%[4]s
 				return
 			}
 			set host [HTTP::host]
@@ -611,10 +707,12 @@ func httpRedirectIRule(port int32, rsVSName string, partition string) string {
 					}
 				}
 				if {$redir == 1} {
-					HTTP::redirect https://[getfield [HTTP::host] ":" 1]:%[2]d[HTTP::uri]
+					%[5]s
 				}
 			}
-		}`, dgName, port)
+		}`, dgName, port, exclusionsDgName,
+		httpRedirectCommand(statusCode, `https://[getfield [HTTP::host] ":" 1]:443[HTTP::uri]`),
+		httpRedirectCommand(statusCode, fmt.Sprintf(`https://[getfield [HTTP::host] ":" 1]:%d[HTTP::uri]`, port)))
 
 	return iRuleCode
 }
@@ -623,33 +721,45 @@ func (ctlr *Controller) GetPathBasedABDeployIRule(rsVSName string, partition str
 	dgPath := strings.Join([]string{partition, Shared}, "/")
 
 	iRule := fmt.Sprintf(`proc select_ab_pool {path default_pool } {
-			set last_slash [string length $path]
 			set ab_class "/%[1]s/%[2]s_ab_deployment_dg"
-			while {$last_slash >= 0} {
-				if {[class match $path equals $ab_class]} then {
-					break
-				}
-				set last_slash [string last "/" $path $last_slash]
-				incr last_slash -1
-				set path [string range $path 0 $last_slash]
+			# Entries of the exact host take precedence over the wildcard host entries
+			set paths [list $path]
+			set host [lindex [split $path "/"] 0]
+			set domain_length [llength [split $host "."]]
+			if {$domain_length > 1} {
+				lappend paths ".[domain $host [expr {$domain_length - 1}]][string range $path [string length $host] end]"
 			}
-			if {$last_slash >= 0} {
-				set ab_rule [class match -value $path equals $ab_class]
-				if {$ab_rule != ""} then {
-					set weight_selection [expr {rand()}]
-					set service_rules [split $ab_rule ";"]
-					foreach service_rule $service_rules {
-						set fields [split $service_rule ","]
-						set pool_name [lindex $fields 0]
-						set weight [expr {double([lindex $fields 1])}]
-						if {$weight_selection <= $weight} then {
-							return $pool_name
+			# Entries of the virtuals without host are matched last
+			lappend paths "%[3]s[string range $path [string length $host] end]"
+			foreach path $paths {
+				set last_slash [string length $path]
+				while {$last_slash >= 0} {
+					if {[class match $path equals $ab_class]} then {
+						break
+					}
+					set last_slash [string last "/" $path $last_slash]
+					incr last_slash -1
+					set path [string range $path 0 $last_slash]
+				}
+				if {$last_slash >= 0} {
+					set ab_rule [class match -value $path equals $ab_class]
+					if {$ab_rule != ""} then {
+						set weight_selection [expr {rand()}]
+						set service_rules [split $ab_rule ";"]
+						foreach service_rule $service_rules {
+							set fields [split $service_rule ","]
+							set pool_name [lindex $fields 0]
+							set weight [expr {double([lindex $fields 1])}]
+							if {$weight_selection <= $weight} then {
+								return $pool_name
+							}
 						}
 					}
+					# If we had a match, but all weights were 0 then
+					# retrun a 503 (Service Unavailable)
+					HTTP::respond 503
+					return $default_pool
 				}
-				# If we had a match, but all weights were 0 then
-				# retrun a 503 (Service Unavailable)
-				HTTP::respond 503
 			}
 			return $default_pool
 		}
@@ -660,11 +770,343 @@ func (ctlr *Controller) GetPathBasedABDeployIRule(rsVSName string, partition str
 				pool $selected_pool
 				event disable
 			}
+		}`, dgPath, rsVSName, HostlessABPathKeyPrefix)
+
+	return iRule
+}
+
+// GetErrorPageIRule returns the iRule responding with the error page
+// to the requests for which no pool is selected
+func (ctlr *Controller) GetErrorPageIRule(errorPage cisapiv1.ErrorPage) string {
+	contentType := errorPage.ContentType
+	if contentType == "" {
+		contentType = "text/plain"
+	}
+	// body is base64 encoded to avoid escaping of the TCL special characters
+	iRule := fmt.Sprintf(`when HTTP_REQUEST priority 900 {
+			if { [LB::server pool] equals "" } then {
+				HTTP::respond %[1]d content [b64decode "%[2]s"] noserver "Content-Type" "%[3]s" "Connection" "Close"
+				event disable
+			}
+		}`, errorPage.StatusCode, base64.StdEncoding.EncodeToString([]byte(errorPage.Body)), contentType)
+
+	return iRule
+}
+
+// GetMaintenanceIRule returns the iRule responding with the maintenance response
+// of the host in maintenance data group
+func (ctlr *Controller) GetMaintenanceIRule(rsVSName string, partition string) string {
+	dgPath := strings.Join([]string{partition, Shared}, "/")
+
+	// ACME HTTP-01 challenges are served during maintenance for the certificate renewals
+	var acmeChallenge string
+	if ctlr.enableACMESolver {
+		acmeChallenge = fmt.Sprintf(`
+			if { [HTTP::path] starts_with "%s/" } {
+				return
+			}`, ACMEChallengePath)
+	}
+	iRule := fmt.Sprintf(`when HTTP_REQUEST priority 50 {%[3]s
+			set maintenance_class "/%[1]s/%[2]s_%[4]s"
+			if { [class exists $maintenance_class] } {
+				set maintenance_host [string tolower [getfield [HTTP::host] ":" 1]]
+				set response [class match -value $maintenance_host equals $maintenance_class]
+				if { $response == "" } {
+					# Check for wildcard domain
+					set domain_length [llength [split $maintenance_host "."]]
+					set response [class match -value ".[domain $maintenance_host [expr {$domain_length - 1}]]" equals $maintenance_class]
+				}
+				if { $response == "" } {
+					# Check for hostless virtual server
+					set response [class match -value "/" equals $maintenance_class]
+				}
+				if { $response != "" } {
+					set idx [string first "|" $response]
+					HTTP::respond 503 content [b64decode [string range $response [expr {$idx + 1}] end]] noserver "Content-Type" [string range $response 0 [expr {$idx - 1}]] "Connection" "Close"
+					event disable all
+					return
+				}
+			}
+		}`, dgPath, rsVSName, acmeChallenge, MaintenanceDgName)
+
+	return iRule
+}
+
+// GetPoolMirrorIRule returns the iRule sending a copy of the requests to a member of the
+// mirror pool of the selected pool in pool mirror data group, the mirror responses are discarded
+func (ctlr *Controller) GetPoolMirrorIRule(rsVSName string, partition string) string {
+	dgPath := strings.Join([]string{partition, Shared}, "/")
+
+	iRule := fmt.Sprintf(`when HTTP_REQUEST priority 950 {
+			# only the requests without payload are mirrored
+			set mirror_request ""
+			if { not [HTTP::header exists "Transfer-Encoding"] &&
+				([HTTP::header value "Content-Length"] equals "" || [HTTP::header value "Content-Length"] == 0) } {
+				set mirror_request [HTTP::request]
+			}
+		}
+		when HTTP_REQUEST_RELEASE {
+			set mirror_class "/%[1]s/%[2]s_%[3]s"
+			if { [info exists mirror_request] && $mirror_request != "" && [class exists $mirror_class] } {
+				set mirror [class match -value [LB::server pool] equals $mirror_class]
+				if { $mirror != "" } {
+					set fields [split $mirror ","]
+					if { [expr {rand() * 100}] < [lindex $fields 1] } {
+						set members [active_members -list [lindex $fields 0]]
+						if { [llength $members] > 0 } {
+							set member [lindex $members [expr {int(rand() * [llength $members])}]]
+							set mirror_conn [connect -timeout 100 -idle 5 -status mirror_status [lindex $member 0]:[lindex $member 1]]
+							if { $mirror_status equals "connected" } {
+								send -timeout 100 $mirror_conn $mirror_request
+								close $mirror_conn
+							}
+						}
+					}
+				}
+			}
+		}`, dgPath, rsVSName, PoolMirrorDgName)
+
+	return iRule
+}
+
+// GetSNIRoutesIRule returns the iRule selecting the pool of the server name of the TLS ClientHello in SNI routes
+// data group, the TLS traffic is passed through to the pool without the decryption
+func (ctlr *Controller) GetSNIRoutesIRule(rsVSName string, partition string) string {
+	dgPath := strings.Join([]string{partition, Shared}, "/")
+
+	iRule := fmt.Sprintf(`when CLIENT_ACCEPTED {
+			TCP::collect
+		}
+		when CLIENT_DATA {
+			# Byte 0 is the content type, 22 is the handshake. Byte 5 is the handshake type, 1 is the ClientHello
+			binary scan [TCP::payload] c@5c tls_content_type tls_handshake_type
+			if { [info exists tls_handshake_type] && $tls_content_type == 22 && $tls_handshake_type == 1 } {
+				# Byte 43 is the session ID length, followed by the cipher suites, compression methods and extensions
+				set offset 43
+				binary scan [TCP::payload] @${offset}c session_id_len
+				incr offset [expr {1 + ($session_id_len & 0xff)}]
+				binary scan [TCP::payload] @${offset}S cipher_suites_len
+				incr offset [expr {2 + ($cipher_suites_len & 0xffff)}]
+				binary scan [TCP::payload] @${offset}c compression_methods_len
+				incr offset [expr {1 + ($compression_methods_len & 0xff)}]
+				binary scan [TCP::payload] @${offset}S extensions_len
+				incr offset 2
+				set extensions_end [expr {$offset + ($extensions_len & 0xffff)}]
+				while { [expr {$offset + 4}] <= $extensions_end } {
+					if { [binary scan [TCP::payload] @${offset}SS extension_type extension_len] != 2 } {
+						break
+					}
+					# Extension type 0 is the server name, host name follows the list length, name type and name length
+					if { $extension_type == 0 } {
+						binary scan [TCP::payload] @[expr {$offset + 7}]S sni_len
+						binary scan [TCP::payload] @[expr {$offset + 9}]a[expr {$sni_len & 0xffff}] tls_servername
+						break
+					}
+					incr offset [expr {4 + ($extension_len & 0xffff)}]
+				}
+			}
+			set sni_class "/%[1]s/%[2]s_%[3]s"
+			if { [info exists tls_servername] && [class exists $sni_class] } {
+				set servername_lower [string tolower $tls_servername]
+				set sni_pool [class match -value $servername_lower equals $sni_class]
+				if { $sni_pool equals "" } {
+					# Fall back to the wildcard host entry
+					set domain_length [llength [split $servername_lower "."]]
+					set wc_host ".[domain $servername_lower [expr {$domain_length - 1}]]"
+					set sni_pool [class match -value $wc_host equals $sni_class]
+				}
+				if { $sni_pool equals "" } {
+					log local0.debug "Failed to find the SNI route for $servername_lower, using the default pool"
+				} else {
+					pool $sni_pool
+				}
+			}
+			TCP::release
+		}`, dgPath, rsVSName, SNIRoutesDgName)
+
+	return iRule
+}
+
+// GetProxyProtocolIRule returns the iRule sending the PROXY protocol header of the version with the client and
+// virtual addresses to the pool members once the server side connection is established
+func (ctlr *Controller) GetProxyProtocolIRule(version string) string {
+	if version == ProxyProtocolV2 {
+		return `proc proxy_addr { addr } {
+			set addr [getfield $addr "%" 1]
+			if { [string first ":" $addr] < 0 } {
+				return [binary format c4 [split $addr "."]]
+			}
+			# expand the zero compression of the IPv6 address to the 8 groups
+			set groups [split [string map {"::" ":z:"} $addr] ":"]
+			set count 0
+			foreach group $groups {
+				if { $group ne "" && $group ne "z" } {
+					incr count
+				}
+			}
+			set words {}
+			foreach group $groups {
+				if { $group eq "z" } {
+					for { set i $count } { $i < 8 } { incr i } {
+						lappend words 0
+					}
+				} elseif { $group ne "" } {
+					lappend words [expr 0x$group]
+				}
+			}
+			return [binary format S8 $words]
+		}
+		when SERVER_CONNECTED {
+			set src_addr [call proxy_addr [clientside {IP::remote_addr}]]
+			set dst_addr [call proxy_addr [clientside {IP::local_addr}]]
+			set ports [binary format SS [clientside {TCP::remote_port}] [clientside {TCP::local_port}]]
+			# signature of the version 2 header followed by the PROXY command, address family and length
+			set signature "\r\n\r\n\x00\r\nQUIT\n"
+			if { [string length $src_addr] != [string length $dst_addr] } {
+				TCP::respond [binary format a12ccS $signature 0x20 0x00 0]
+			} elseif { [string length $src_addr] == 4 } {
+				TCP::respond [binary format a12ccSa4a4a4 $signature 0x21 0x11 12 $src_addr $dst_addr $ports]
+			} else {
+				TCP::respond [binary format a12ccSa16a16a4 $signature 0x21 0x21 36 $src_addr $dst_addr $ports]
+			}
+		}`
+	}
+	return `when SERVER_CONNECTED {
+			set src_addr [getfield [clientside {IP::remote_addr}] "%" 1]
+			set dst_addr [getfield [clientside {IP::local_addr}] "%" 1]
+			set src_ipv6 [expr {[string first ":" $src_addr] >= 0}]
+			set dst_ipv6 [expr {[string first ":" $dst_addr] >= 0}]
+			if { $src_ipv6 != $dst_ipv6 } {
+				TCP::respond "PROXY UNKNOWN\r\n"
+			} else {
+				set proto "TCP4"
+				if { $src_ipv6 } {
+					set proto "TCP6"
+				}
+				TCP::respond "PROXY $proto $src_addr $dst_addr [clientside {TCP::remote_port}] [clientside {TCP::local_port}]\r\n"
+			}
+		}`
+}
+
+// GetRouteTrafficIRule returns the iRule applying the server timeout and the rate limit of the HTTP requests
+// per client of the longest host and path of the request in route traffic data group
+func (ctlr *Controller) GetRouteTrafficIRule(rsVSName string, partition string) string {
+	dgPath := strings.Join([]string{partition, Shared}, "/")
+
+	iRule := fmt.Sprintf(`when HTTP_REQUEST priority 300 {
+			set route_class "/%[1]s/%[2]s_%[3]s"
+			set route_timeout 0
+			if { ![class exists $route_class] } {
+				return
+			}
+			set path [string tolower [getfield [HTTP::host] ":" 1]][HTTP::path]
+			set last_slash [string length $path]
+			while {$last_slash >= 0} {
+				if {[class match $path equals $route_class]} then {
+					break
+				}
+				set last_slash [string last "/" $path $last_slash]
+				incr last_slash -1
+				set path [string range $path 0 $last_slash]
+			}
+			if {$last_slash < 0} then {
+				return
+			}
+			set fields [split [class match -value $path equals $route_class] ","]
+			set route_timeout [lindex $fields 0]
+			set rate_limit [lindex $fields 1]
+			if {$rate_limit > 0} then {
+				set rate_key "route_rate:[IP::client_addr]:$path"
+				set requests [table incr -notouch $rate_key]
+				if {$requests == 1} then {
+					table lifetime $rate_key 3
+				}
+				if {$requests > $rate_limit} then {
+					HTTP::respond 429 noserver "Retry-After" "3" "Connection" "Close"
+					event disable all
+					return
+				}
+			}
+		}
+		when SERVER_CONNECTED {
+			if { [info exists route_timeout] && $route_timeout > 0 } {
+				IP::idle_timeout $route_timeout
+			}
+		}`, dgPath, rsVSName, RouteTrafficDgName)
+
+	return iRule
+}
+
+// GetRegexPathIRule returns the iRule selecting the pool of the first host and path regex
+// matching the request in regex path data group, records are matched in the order of the index keys
+func (ctlr *Controller) GetRegexPathIRule(rsVSName string, partition string) string {
+	dgPath := strings.Join([]string{partition, Shared}, "/")
+
+	iRule := fmt.Sprintf(`when HTTP_REQUEST priority 150 {
+			set host [string tolower [getfield [HTTP::host] ":" 1]]
+			set path [HTTP::path]
+			foreach record [lsort -index 0 [class get /%[1]s/%[2]s_regex_path_dg]] {
+				set key [lindex $record 0]
+				set key [string range $key [expr {[string first " " $key] + 1}] end]
+				set idx [string first " " $key]
+				set host_regex [string range $key 0 [expr {$idx - 1}]]
+				set path_regex [string range $key [expr {$idx + 1}] end]
+				set fields [split [lindex $record 1] ","]
+				if {[lindex $fields 1] == "true"} then {
+					set path_match [regexp -- $path_regex $path]
+				} else {
+					set path_match [regexp -nocase -- $path_regex $path]
+				}
+				if {$path_match && [regexp -nocase -- $host_regex $host]} then {
+					pool [lindex $fields 0]
+					event disable
+					return
+				}
+			}
 		}`, dgPath, rsVSName)
 
 	return iRule
 }
 
+// isRegexPool checks whether the traffic of the pool is selected by the host and path regex
+func isRegexPool(pool *cisapiv1.Pool) bool {
+	return pool.PathType == PathTypeRegex || pool.HostRegex != "" || pool.PathRegex != ""
+}
+
+// getPoolRegex returns the host and path regex matching the traffic of the pool,
+// the host and path of the pool are translated to regex unless the regex is specified
+func getPoolRegex(host string, pool *cisapiv1.Pool) (string, string) {
+	hostRegex := pool.HostRegex
+	if hostRegex == "" {
+		hostRegex = getHostRegex(host)
+	}
+	pathRegex := pool.PathRegex
+	if pathRegex == "" {
+		switch {
+		case pool.PathType == PathTypeRegex:
+			pathRegex = pool.Path
+		case pool.PathType == PathTypeExact:
+			pathRegex = "^" + regexp.QuoteMeta(pool.Path) + "$"
+		case pool.Path == "" || pool.Path == "/":
+			pathRegex = ".*"
+		default:
+			pathRegex = "^" + regexp.QuoteMeta(strings.TrimSuffix(pool.Path, "/")) + "(/|$)"
+		}
+	}
+	return hostRegex, pathRegex
+}
+
+// getHostRegex returns the regex matching the VirtualServer host
+func getHostRegex(host string) string {
+	if host == "" {
+		return ".*"
+	}
+	if strings.HasPrefix(host, "*.") {
+		return "^[^.]+" + regexp.QuoteMeta(strings.TrimPrefix(host, "*")) + "$"
+	}
+	return "^" + regexp.QuoteMeta(host) + "$"
+}
+
 func (ctlr *Controller) getTLSIRule(rsVSName string, partition string, allowSourceRange []string) string {
 	dgPath := strings.Join([]string{partition, Shared}, "/")
 
@@ -761,6 +1203,12 @@ func (ctlr *Controller) getTLSIRule(rsVSName string, partition string, allowSour
 
 									# Disable Serverside SSL for Passthrough Class
 									set dflt_pool_passthrough [class match -value $servername_lower equals $passthru_class]
+									if { $dflt_pool_passthrough equals "" } {
+										# Fall back to the wildcard host entry
+										set domain_length [llength [split $servername_lower "."]]
+										set wc_host ".[domain $servername_lower [expr {$domain_length - 1}]]"
+										set dflt_pool_passthrough [class match -value $wc_host equals $passthru_class]
+									}
 									if { not ($dflt_pool_passthrough equals "") } {
 										SSL::disable
 										HTTP::disable
@@ -871,17 +1319,22 @@ func (ctlr *Controller) getTLSIRule(rsVSName string, partition string, allowSour
 						}
 					}
                 }
-				# handle the default pool for virtual server
+				# handle the default pool and error page for virtual server
 				set default_class "/%[1]s/%[2]s_default_pool_servername_dg"
+				set error_page 0
                  if { [class exists $default_class] } { 
-                    set dflt_pool [class match -value "defaultPool" equals $default_class]
+                    set error_page [class match "errorPage" equals $default_class]
+                    if { not $error_page } {
+                        set dflt_pool [class match -value "defaultPool" equals $default_class]
+                    }
                  }
                 
                 # Handle requests sent to unknown hosts.
                 # For valid hosts, Send the request to respective pool.
                 if { not [info exists dflt_pool] } then {
                 	 # Allowing HTTP2 traffic to be handled by policies and closing the connection for HTTP/1.1 unknown hosts.
-                	 if { not ([SSL::payload] starts_with "PRI * HTTP/2.0") } {
+                	 # Unknown hosts are responded with the error page when configured.
+                	 if { not ([SSL::payload] starts_with "PRI * HTTP/2.0") && not $error_page } {
                 	    reject ; event disable all; return;
                     }
                 } else {
@@ -972,37 +1425,48 @@ func (ctlr *Controller) selectPoolIRuleFunc(rsVSName string, dgPath string) stri
 
 	iRuleFunc := fmt.Sprintf(`
 		proc select_ab_pool {path default_pool } {
-			set last_slash [string length $path]
 			set ab_class "/%[1]s/%[2]s_ab_deployment_dg"
-			while {$last_slash >= 0} {
-				if {[class match $path equals $ab_class]} then {
-					break
-				}
-				set last_slash [string last "/" $path $last_slash]
-				incr last_slash -1
-				set path [string range $path 0 $last_slash]
+			# Entries of the exact host take precedence over the wildcard host entries
+			set paths [list $path]
+			set host [lindex [split $path "/"] 0]
+			set domain_length [llength [split $host "."]]
+			if {$domain_length > 1} {
+				lappend paths ".[domain $host [expr {$domain_length - 1}]][string range $path [string length $host] end]"
 			}
-
-			if {$last_slash >= 0} {
-				set ab_rule [class match -value $path equals $ab_class]
-				if {$ab_rule != ""} then {
-					set weight_selection [expr {rand()}]
-					set service_rules [split $ab_rule ";"]
-					foreach service_rule $service_rules {
-						set fields [split $service_rule ","]
-						set pool_name [lindex $fields 0]
-						set weight [expr {double([lindex $fields 1])}]
-						if {$weight_selection <= $weight} then {
-							return $pool_name
+			# Entries of the virtuals without host are matched last
+			lappend paths "%[3]s[string range $path [string length $host] end]"
+			foreach path $paths {
+				set last_slash [string length $path]
+				while {$last_slash >= 0} {
+					if {[class match $path equals $ab_class]} then {
+						break
+					}
+					set last_slash [string last "/" $path $last_slash]
+					incr last_slash -1
+					set path [string range $path 0 $last_slash]
+				}
+				if {$last_slash >= 0} {
+					set ab_rule [class match -value $path equals $ab_class]
+					if {$ab_rule != ""} then {
+						set weight_selection [expr {rand()}]
+						set service_rules [split $ab_rule ";"]
+						foreach service_rule $service_rules {
+							set fields [split $service_rule ","]
+							set pool_name [lindex $fields 0]
+							set weight [expr {double([lindex $fields 1])}]
+							if {$weight_selection <= $weight} then {
+								return $pool_name
+							}
 						}
 					}
+					# If we had a match, but all weights were 0 then
+					# retrun a 503 (Service Unavailable)
+					HTTP::respond 503
+					return $default_pool
 				}
-				# If we had a match, but all weights were 0 then
-				# retrun a 503 (Service Unavailable)
-				HTTP::respond 503
 			}
 			return $default_pool
-		}`, dgPath, rsVSName)
+		}`, dgPath, rsVSName, HostlessABPathKeyPrefix)
 
 	return iRuleFunc
 }
@@ -1073,6 +1537,130 @@ func updateDataGroupOfDgName(
 	}
 }
 
+// updatePassthroughDataGroup updates the passthrough data group with the hosts of the pools routed by SNI.
+// Host of the resource takes precedence over the other hosts of the TLSProfile, which are claimed only when
+// no other resource sharing the virtual has the record of the host
+func updatePassthroughDataGroup(
+	intDgMap InternalDataGroupMap,
+	poolPathRefs []poolPathRef,
+	rsVSName string,
+	vsHostname string,
+	namespace string,
+	partition string,
+) {
+	rsDGName := getRSCfgResName(rsVSName, PassthroughHostsDgName)
+	mapKey := NameRef{Name: rsDGName, Partition: partition}
+	for _, pl := range poolPathRefs {
+		for _, hostName := range pl.aliasHostnames {
+			// hostless resource has no server name to route the passthrough traffic
+			if hostName == "" {
+				continue
+			}
+			key := strings.TrimPrefix(hostName, "*")
+			owner := hostName == vsHostname
+			claimed := false
+			for ns, dg := range intDgMap[mapKey] {
+				if !dg.hasRecord(key) {
+					continue
+				}
+				if owner && ns != namespace {
+					dg.RemoveRecord(key)
+				} else {
+					claimed = true
+				}
+			}
+			if owner || !claimed {
+				updateDataGroup(intDgMap, rsDGName, partition, namespace, hostName, pl.poolName, DataGroupType)
+			}
+		}
+	}
+}
+
+// updateDataGroupForRedirectExclusions updates the https redirect exclusions data group
+// with the paths excluded from redirect for the hosts of the TLS context
+func updateDataGroupForRedirectExclusions(rsCfg *ResourceConfig, tlsContext TLSContext) {
+	if len(tlsContext.httpRedirect.ExcludePaths) == 0 {
+		return
+	}
+	for _, pl := range tlsContext.poolPathRefs {
+		for _, hostName := range pl.aliasHostnames {
+			addRedirectExclusions(rsCfg, tlsContext.namespace, hostName, tlsContext.httpRedirect.ExcludePaths)
+		}
+	}
+}
+
+// addRedirectExclusions adds the paths to the paths excluded from redirect for the host
+func addRedirectExclusions(rsCfg *ResourceConfig, namespace, hostName string, excludePaths []string) {
+	dgName := getRSCfgResName(rsCfg.Virtual.Name, HttpsRedirectExclusionsDgName)
+	hostName = strings.ToLower(hostName)
+	var paths []string
+	seen := make(map[string]struct{})
+	if dg, ok := rsCfg.IntDgMap[NameRef{Name: dgName, Partition: rsCfg.Virtual.Partition}][namespace]; ok {
+		for _, record := range dg.Records {
+			if record.Name == strings.TrimPrefix(hostName, "*") {
+				for _, path := range strings.Split(record.Data, "|") {
+					seen[path] = struct{}{}
+					paths = append(paths, path)
+				}
+			}
+		}
+	}
+	for _, path := range excludePaths {
+		path = strings.TrimSuffix(path, "/")
+		if _, ok := seen[path]; ok {
+			continue
+		}
+		seen[path] = struct{}{}
+		paths = append(paths, path)
+	}
+	updateDataGroup(rsCfg.IntDgMap, dgName, rsCfg.Virtual.Partition, namespace, hostName, strings.Join(paths, "|"), DataGroupType)
+}
+
+// getRedirectExcludedPools returns the VirtualServer pools serving the paths excluded from redirect,
+// pools under the excluded paths are served as is, and the excluded path is served by
+// the pool of the longest prefix path otherwise
+func getRedirectExcludedPools(vs *cisapiv1.VirtualServer) []cisapiv1.Pool {
+	var pools []cisapiv1.Pool
+	seen := make(map[int]struct{})
+	for _, excludePath := range vs.Spec.HTTPRedirect.ExcludePaths {
+		excludePath = strings.TrimSuffix(excludePath, "/")
+		prefixPool := -1
+		pathServed := false
+		for i, pl := range vs.Spec.Pools {
+			if isRegexPool(&pl) {
+				continue
+			}
+			plPath := strings.TrimSuffix(pl.Path, "/")
+			if hasPathPrefix(plPath, excludePath) {
+				if plPath == excludePath {
+					pathServed = true
+				}
+				if _, ok := seen[i]; !ok {
+					seen[i] = struct{}{}
+					pools = append(pools, pl)
+				}
+			} else if pl.PathType != PathTypeExact && hasPathPrefix(excludePath, plPath) {
+				if prefixPool == -1 || len(plPath) > len(strings.TrimSuffix(vs.Spec.Pools[prefixPool].Path, "/")) {
+					prefixPool = i
+				}
+			}
+		}
+		if !pathServed && prefixPool != -1 {
+			pl := vs.Spec.Pools[prefixPool]
+			pl.Path = excludePath
+			pl.PathType = PathTypePrefix
+			pools = append(pools, pl)
+		}
+	}
+	return pools
+}
+
+// hasPathPrefix checks whether the path is same as or under the prefix path,
+// paths are expected without trailing slash
+func hasPathPrefix(path, prefix string) bool {
+	return prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
 // Add or update a data group record
 func updateDataGroup(
 	intDgMap InternalDataGroupMap,
@@ -1202,6 +1790,27 @@ func isVsPathBasedABDeployment(pool *cisapiv1.Pool) bool {
 	return pool.AlternateBackends != nil && len(pool.AlternateBackends) > 0 && (pool.Path != "" && pool.Path != "/")
 }
 
+// getWeightedPools returns the pools of VirtualServer grouped by the paths shared by
+// multiple pools, traffic of these paths is split across the pools based on weights
+func (ctlr *Controller) getWeightedPools(vs *cisapiv1.VirtualServer) map[string][]cisapiv1.Pool {
+	if ctlr.haModeType == Ratio {
+		return nil
+	}
+	weightedPools := make(map[string][]cisapiv1.Pool)
+	for _, pl := range vs.Spec.Pools {
+		if isRegexPool(&pl) {
+			continue
+		}
+		weightedPools[pl.Path] = append(weightedPools[pl.Path], pl)
+	}
+	for path, pools := range weightedPools {
+		if len(pools) < 2 {
+			delete(weightedPools, path)
+		}
+	}
+	return weightedPools
+}
+
 func isVsPathBasedRatioDeployment(pool *cisapiv1.Pool, mode HAModeType) bool {
 	return mode == Ratio && (pool.Path != "" && pool.Path != "/")
 }
@@ -1291,14 +1900,14 @@ func (ctlr *Controller) GetRouteBackends(route *routeapi.Route, clusterSvcs []ci
 	clusterSvcMap := make(map[string]struct{})
 	clusterSvcMap[""] = struct{}{} // "" is used as key for the local cluster where this CIS is running
 	// totalClusterRatio stores the sum total of all the ratio of clusters contributing services to this route
-	totalClusterRatio := float64(*ctlr.clusterRatio[ctlr.multiClusterConfigs.LocalClusterName])
+	totalClusterRatio := float64(ctlr.getClusterRatio(ctlr.multiClusterConfigs.LocalClusterName))
 	// totalSvcWeights stores the sum total of all the weights of services associated with this route
 	totalSvcWeights := float64(*(route.Spec.To.Weight)) * float64(factor)
 	// count of valid external multiCluster services
 	validExtSvcCount := 0
 	// Include HA partner cluster ratio in the totalClusterRatio calculation
 	if ctlr.multiClusterConfigs.HAPairClusterName != "" {
-		totalClusterRatio += float64(*ctlr.clusterRatio[ctlr.multiClusterConfigs.HAPairClusterName])
+		totalClusterRatio += float64(ctlr.getClusterRatio(ctlr.multiClusterConfigs.HAPairClusterName))
 	}
 	// Process multiCluster services
 	for i, svc := range clusterSvcs {
@@ -1309,9 +1918,9 @@ func (ctlr *Controller) GetRouteBackends(route *routeapi.Route, clusterSvcs []ci
 			continue
 		}
 		if _, ok := clusterSvcMap[svc.ClusterName]; !ok {
-			if r, ok := ctlr.clusterRatio[svc.ClusterName]; ok {
+			if _, ok := ctlr.clusterRatio[svc.ClusterName]; ok {
 				clusterSvcMap[svc.ClusterName] = struct{}{}
-				totalClusterRatio += float64(*r)
+				totalClusterRatio += float64(ctlr.getClusterRatio(svc.ClusterName))
 			} else {
 				// Service is from unknown cluster. This case should not arise, but if it does then consider weight to
 				// be 0 as most probably the cluster config may not have been provided in the extended configmap, in
@@ -1349,13 +1958,13 @@ func (ctlr *Controller) GetRouteBackends(route *routeapi.Route, clusterSvcs []ci
 	if route.Spec.To.Weight != nil {
 		// Route backend service in local cluster
 		rbcs[beIdx].Weight = (float64(*(route.Spec.To.Weight)) / totalSvcWeights) *
-			(float64(*ctlr.clusterRatio[ctlr.multiClusterConfigs.LocalClusterName]) / totalClusterRatio)
+			(float64(ctlr.getClusterRatio(ctlr.multiClusterConfigs.LocalClusterName)) / totalClusterRatio)
 		// Route backend service in HA partner cluster
 		if ctlr.multiClusterConfigs.HAPairClusterName != "" {
 			beIdx++
 			rbcs[beIdx].Name = route.Spec.To.Name
 			rbcs[beIdx].Weight = (float64(*(route.Spec.To.Weight)) / totalSvcWeights) *
-				(float64(*ctlr.clusterRatio[ctlr.multiClusterConfigs.HAPairClusterName]) / totalClusterRatio)
+				(float64(ctlr.getClusterRatio(ctlr.multiClusterConfigs.HAPairClusterName)) / totalClusterRatio)
 			rbcs[beIdx].Cluster = ctlr.multiClusterConfigs.HAPairClusterName
 		}
 	} else {
@@ -1377,13 +1986,13 @@ func (ctlr *Controller) GetRouteBackends(route *routeapi.Route, clusterSvcs []ci
 			beIdx = beIdx + 1
 			rbcs[beIdx].Name = svc.Name
 			rbcs[beIdx].Weight = (float64(*(svc.Weight)) / totalSvcWeights) *
-				(float64(*ctlr.clusterRatio[ctlr.multiClusterConfigs.LocalClusterName]) / totalClusterRatio)
+				(float64(ctlr.getClusterRatio(ctlr.multiClusterConfigs.LocalClusterName)) / totalClusterRatio)
 			// HA partner cluster
 			if ctlr.multiClusterConfigs.HAPairClusterName != "" {
 				beIdx = beIdx + 1
 				rbcs[beIdx].Name = svc.Name
 				rbcs[beIdx].Weight = (float64(*(svc.Weight)) / totalSvcWeights) *
-					(float64(*ctlr.clusterRatio[ctlr.multiClusterConfigs.HAPairClusterName]) / totalClusterRatio)
+					(float64(ctlr.getClusterRatio(ctlr.multiClusterConfigs.HAPairClusterName)) / totalClusterRatio)
 				rbcs[beIdx].Cluster = ctlr.multiClusterConfigs.HAPairClusterName
 			}
 		}
@@ -1396,9 +2005,9 @@ func (ctlr *Controller) GetRouteBackends(route *routeapi.Route, clusterSvcs []ci
 		}
 		beIdx = beIdx + 1
 		rbcs[beIdx].Name = svc.SvcName
-		if r, ok := ctlr.clusterRatio[svc.ClusterName]; ok {
+		if _, ok := ctlr.clusterRatio[svc.ClusterName]; ok {
 			rbcs[beIdx].Weight = (float64(*svc.Weight) / totalSvcWeights) *
-				(float64(*r) / totalClusterRatio)
+				(float64(ctlr.getClusterRatio(svc.ClusterName)) / totalClusterRatio)
 		} else {
 			// Service is from unknown cluster, so set weight to zero which is already set
 			rbcs[beIdx].Weight = 0
@@ -1408,6 +2017,57 @@ func (ctlr *Controller) GetRouteBackends(route *routeapi.Route, clusterSvcs []ci
 	return rbcs
 }
 
+// updateDataGroupForWeightedPools updates the data group map with the weighted selection
+// of the VirtualServer pools sharing the same path
+func (ctlr *Controller) updateDataGroupForWeightedPools(
+	vs *cisapiv1.VirtualServer,
+	pools []cisapiv1.Pool,
+	dgName string,
+	partition string,
+	dgMap InternalDataGroupMap,
+) {
+	weightTotal := 0.0
+	for _, pl := range pools {
+		for _, svc := range ctlr.GetPoolBackends(&pl) {
+			weightTotal = weightTotal + svc.Weight
+		}
+	}
+
+	path := pools[0].Path
+	if path == "/" {
+		path = ""
+	}
+	key := vs.Spec.Host + path
+	if vs.Spec.Host == "" {
+		key = HostlessABPathKeyPrefix + path
+	}
+
+	if weightTotal == 0 {
+		// If all pools have 0 weight, 503 will be returned
+		updateDataGroup(dgMap, dgName, partition, vs.Namespace, key, "", "")
+		return
+	}
+	// Place each pool in a segment between 0.0 and 1.0 that corresponds to
+	// it's ratio percentage in ascending order
+	var entries []string
+	runningWeightTotal := 0.0
+	for _, pl := range pools {
+		for _, be := range ctlr.GetPoolBackends(&pl) {
+			if be.Weight == 0 {
+				continue
+			}
+			runningWeightTotal = runningWeightTotal + be.Weight
+			weightedSliceThreshold := runningWeightTotal / weightTotal
+			poolName := ctlr.framePoolNameForVs(vs.Namespace, pl, vs.Spec.Host, be)
+			entry := fmt.Sprintf("%s,%4.3f", poolName, weightedSliceThreshold)
+			entries = append(entries, entry)
+		}
+	}
+	value := strings.Join(entries, ";")
+	updateDataGroup(dgMap, dgName,
+		partition, vs.Namespace, key, value, "string")
+}
+
 // updateDataGroupForABVirtualServer updates the data group map based on alternativeBackends of route.
 func (ctlr *Controller) updateDataGroupForABVirtualServer(
 	pool *cisapiv1.Pool,
@@ -0,0 +1,105 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"encoding/json"
+	"strings"
+
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// network status annotation of the pods set by Multus
+	MultusNetworkStatusAnnotation = "k8s.v1.cni.cncf.io/network-status"
+	// network status annotation of the earlier Multus releases
+	MultusNetworksStatusAnnotation = "k8s.v1.cni.cncf.io/networks-status"
+)
+
+// multusNetworkStatus is a network of the pod in the Multus network status annotation
+type multusNetworkStatus struct {
+	Name      string   `json:"name"`
+	Interface string   `json:"interface,omitempty"`
+	IPs       []string `json:"ips,omitempty"`
+	Default   bool     `json:"default,omitempty"`
+}
+
+// getNetworkAttachmentMembers returns the pool members of the service with the addresses of the pods on the
+// secondary network of the network attachment, members of the pods without the network are skipped
+func (ctlr *Controller) getNetworkAttachmentMembers(namespace, service, attachment string,
+	members []PoolMember) []PoolMember {
+	if !ctlr.secondaryNetworks || ctlr.PoolMemberType != Cluster {
+		return members
+	}
+	if comInf, ok := ctlr.getNamespacedCommonInformer(namespace); !ok || comInf.podInformer == nil {
+		log.Warningf("Pod informer not found for namespace %v, using the primary network addresses for "+
+			"the network attachment %v of service %v", namespace, attachment, service)
+		return members
+	}
+	addrs := make(map[string]string)
+	for _, pod := range ctlr.GetPodsForService(namespace, service, false) {
+		ip := getPodNetworkAttachmentIP(pod, attachment)
+		if ip == "" {
+			continue
+		}
+		if pod.Status.PodIP != "" {
+			addrs[pod.Status.PodIP] = ip
+		}
+		for _, podIP := range pod.Status.PodIPs {
+			addrs[podIP.IP] = ip
+		}
+	}
+	var attachmentMembers []PoolMember
+	for _, member := range members {
+		ip, ok := addrs[member.Address]
+		if !ok {
+			log.Debugf("Network attachment %v not found for the pool member %v of service %v/%v",
+				attachment, member.Address, namespace, service)
+			continue
+		}
+		member.Address = ip
+		attachmentMembers = append(attachmentMembers, member)
+	}
+	return attachmentMembers
+}
+
+// getPodNetworkAttachmentIP returns the address of the pod on the network of the network attachment from the
+// Multus network status annotation, the network attachment is in the namespace of the pod unless qualified
+func getPodNetworkAttachmentIP(pod *v1.Pod, attachment string) string {
+	annotation, ok := pod.Annotations[MultusNetworkStatusAnnotation]
+	if !ok {
+		annotation, ok = pod.Annotations[MultusNetworksStatusAnnotation]
+	}
+	if !ok {
+		return ""
+	}
+	var networks []multusNetworkStatus
+	if err := json.Unmarshal([]byte(annotation), &networks); err != nil {
+		log.Debugf("Unable to parse the network status annotation of pod %v/%v: %v", pod.Namespace, pod.Name, err)
+		return ""
+	}
+	if !strings.Contains(attachment, "/") {
+		attachment = pod.Namespace + "/" + attachment
+	}
+	for _, network := range networks {
+		if network.Name == attachment && len(network.IPs) > 0 {
+			return network.IPs[0]
+		}
+	}
+	return ""
+}
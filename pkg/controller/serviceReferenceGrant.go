@@ -0,0 +1,213 @@
+/*-
+* Copyright (c) 2016-2021, F5 Networks, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package controller
+
+import (
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+)
+
+// getServiceReferenceGrants returns the ServiceReferenceGrants of the namespace
+func (ctlr *Controller) getServiceReferenceGrants(namespace string) []*cisapiv1.ServiceReferenceGrant {
+	crInf, ok := ctlr.getNamespacedCRInformer(namespace)
+	if !ok || crInf.srgInformer == nil {
+		return nil
+	}
+	objs, err := crInf.srgInformer.GetIndexer().ByIndex("namespace", namespace)
+	if err != nil {
+		log.Errorf("Unable to get list of ServiceReferenceGrants for namespace '%v': %v",
+			namespace, err)
+		return nil
+	}
+	var grants []*cisapiv1.ServiceReferenceGrant
+	for _, obj := range objs {
+		grants = append(grants, obj.(*cisapiv1.ServiceReferenceGrant))
+	}
+	return grants
+}
+
+// isServiceReferenceGranted checks whether the VirtualServers of the namespace can refer the service.
+// Services of the same namespace are always allowed, services of other namespaces need a
+// ServiceReferenceGrant in the service namespace when the grants are enforced
+func (ctlr *Controller) isServiceReferenceGranted(namespace, svcNamespace, svcName string) bool {
+	if !ctlr.enforceSvcRefGrants || svcNamespace == "" || svcNamespace == namespace {
+		return true
+	}
+	for _, grant := range ctlr.getServiceReferenceGrants(svcNamespace) {
+		var fromNamespace bool
+		for _, from := range grant.Spec.From {
+			if from.Namespace == namespace {
+				fromNamespace = true
+				break
+			}
+		}
+		if !fromNamespace {
+			continue
+		}
+		// Grant without services allows all the services of the namespace
+		if len(grant.Spec.Services) == 0 {
+			return true
+		}
+		for _, svc := range grant.Spec.Services {
+			if svc == svcName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// filterGrantedServiceReferences removes the references to the services of other namespaces
+// not allowed by the ServiceReferenceGrants from the VirtualServers
+func (ctlr *Controller) filterGrantedServiceReferences(virtuals []*cisapiv1.VirtualServer) []*cisapiv1.VirtualServer {
+	if !ctlr.enforceSvcRefGrants {
+		return virtuals
+	}
+	var result []*cisapiv1.VirtualServer
+	for _, vs := range virtuals {
+		granted := func(svcNamespace, svcName string) bool {
+			if ctlr.isServiceReferenceGranted(vs.Namespace, svcNamespace, svcName) {
+				return true
+			}
+			log.Errorf("Service %v/%v referred by VirtualServer %v/%v is not allowed by a ServiceReferenceGrant",
+				svcNamespace, svcName, vs.Namespace, vs.Name)
+			return false
+		}
+		// VirtualServer is copied only when one of its references is not allowed
+		var filtered *cisapiv1.VirtualServer
+		copyVS := func() {
+			if filtered == nil {
+				filtered = vs.DeepCopy()
+			}
+		}
+
+		var pools []cisapiv1.Pool
+		for _, pool := range vs.Spec.Pools {
+			if !granted(pool.ServiceNamespace, pool.Service) {
+				copyVS()
+				continue
+			}
+			var backends []cisapiv1.AlternateBackend
+			for _, ab := range pool.AlternateBackends {
+				if !granted(ab.ServiceNamespace, ab.Service) {
+					copyVS()
+					continue
+				}
+				backends = append(backends, ab)
+			}
+			pool.AlternateBackends = backends
+			if pool.Mirror != nil && pool.Mirror.Service != "" && !granted(pool.Mirror.ServiceNamespace, pool.Mirror.Service) {
+				copyVS()
+				pool.Mirror = nil
+			}
+			pools = append(pools, pool)
+		}
+		defaultPool := vs.Spec.DefaultPool
+		if defaultPool.Reference == ServiceRef && !granted(defaultPool.ServiceNamespace, defaultPool.Service) {
+			copyVS()
+			defaultPool = cisapiv1.DefaultPool{}
+		}
+
+		if filtered == nil {
+			result = append(result, vs)
+			continue
+		}
+		filtered.Spec.Pools = pools
+		filtered.Spec.DefaultPool = defaultPool
+		result = append(result, filtered)
+	}
+	return result
+}
+
+// getGrantedTransportServer returns the TransportServer with the SNI routes to the services of other namespaces
+// not allowed by the ServiceReferenceGrants removed, the TransportServer is denied when the service of its pool
+// is not allowed
+func (ctlr *Controller) getGrantedTransportServer(ts *cisapiv1.TransportServer) (*cisapiv1.TransportServer, bool) {
+	if !ctlr.enforceSvcRefGrants {
+		return ts, false
+	}
+	granted := func(svcNamespace, svcName string) bool {
+		if ctlr.isServiceReferenceGranted(ts.Namespace, svcNamespace, svcName) {
+			return true
+		}
+		log.Errorf("Service %v/%v referred by TransportServer %v/%v is not allowed by a ServiceReferenceGrant",
+			svcNamespace, svcName, ts.Namespace, ts.Name)
+		return false
+	}
+	if !granted(ts.Spec.Pool.ServiceNamespace, ts.Spec.Pool.Service) {
+		return ts, true
+	}
+	var routes []cisapiv1.SNIRoute
+	for _, route := range ts.Spec.SNIRoutes {
+		if granted(route.Pool.ServiceNamespace, route.Pool.Service) {
+			routes = append(routes, route)
+		}
+	}
+	if len(routes) == len(ts.Spec.SNIRoutes) {
+		return ts, false
+	}
+	filtered := ts.DeepCopy()
+	filtered.Spec.SNIRoutes = routes
+	return filtered, false
+}
+
+// getTransportServersForServiceReferenceGrant returns the TransportServers of other namespaces
+// referring the services of the ServiceReferenceGrant namespace
+func (ctlr *Controller) getTransportServersForServiceReferenceGrant(grant *cisapiv1.ServiceReferenceGrant) []*cisapiv1.TransportServer {
+	var virtuals []*cisapiv1.TransportServer
+	for _, ts := range ctlr.getAllTSFromMonitoredNamespaces() {
+		if ts.Namespace == grant.Namespace {
+			continue
+		}
+		refers := ts.Spec.Pool.ServiceNamespace == grant.Namespace
+		for _, route := range ts.Spec.SNIRoutes {
+			if route.Pool.ServiceNamespace == grant.Namespace {
+				refers = true
+			}
+		}
+		if refers {
+			virtuals = append(virtuals, ts)
+		}
+	}
+	return virtuals
+}
+
+// getVirtualServersForServiceReferenceGrant returns the VirtualServers of other namespaces
+// referring the services of the ServiceReferenceGrant namespace
+func (ctlr *Controller) getVirtualServersForServiceReferenceGrant(grant *cisapiv1.ServiceReferenceGrant) []*cisapiv1.VirtualServer {
+	var virtuals []*cisapiv1.VirtualServer
+	for _, vs := range ctlr.getAllVSFromMonitoredNamespaces() {
+		if vs.Namespace == grant.Namespace {
+			continue
+		}
+		refers := vs.Spec.DefaultPool.ServiceNamespace == grant.Namespace
+		for _, pool := range vs.Spec.Pools {
+			if pool.ServiceNamespace == grant.Namespace || (pool.Mirror != nil && pool.Mirror.ServiceNamespace == grant.Namespace) {
+				refers = true
+			}
+			for _, ab := range pool.AlternateBackends {
+				if ab.ServiceNamespace == grant.Namespace {
+					refers = true
+				}
+			}
+		}
+		if refers {
+			virtuals = append(virtuals, vs)
+		}
+	}
+	return virtuals
+}
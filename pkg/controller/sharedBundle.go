@@ -0,0 +1,180 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+)
+
+// shared bundle is installed in the Shared application of the Common partition, the only partition whose
+// objects can be referred by the virtual servers of the other partitions
+const sharedBundlePartition = "Common"
+
+// names of the AS3 objects
+var as3ObjectNameRegex = regexp.MustCompile(`^[A-Za-z][0-9A-Za-z_.-]*$`)
+
+type (
+	// SharedBundle of the global extended ConfigMap is the bundle of the iRules and profiles installed once by CIS
+	// in /Common/Shared, resources refer them as /Common/Shared/<name> instead of duplicating them in the tenants
+	SharedBundle struct {
+		IRules   []SharedIRule   `yaml:"iRules"`
+		Profiles []SharedProfile `yaml:"profiles"`
+	}
+
+	// SharedIRule is the iRule of the shared bundle
+	SharedIRule struct {
+		Name string `yaml:"name"`
+		Code string `yaml:"code"`
+	}
+
+	// SharedProfile is the profile of the shared bundle, properties are the properties of its AS3 class
+	SharedProfile struct {
+		Name       string                 `yaml:"name"`
+		Class      string                 `yaml:"class"`
+		Properties map[string]interface{} `yaml:"properties,omitempty"`
+	}
+)
+
+// validate validates the names of the objects of the shared bundle and their contents
+func (sb *SharedBundle) validate() error {
+	names := make(map[string]struct{})
+	checkName := func(name string) error {
+		if !as3ObjectNameRegex.MatchString(name) {
+			return fmt.Errorf("invalid name %q of sharedBundle object", name)
+		}
+		if _, ok := names[name]; ok {
+			return fmt.Errorf("duplicate name %v of sharedBundle object", name)
+		}
+		names[name] = struct{}{}
+		return nil
+	}
+	for _, iRule := range sb.IRules {
+		if err := checkName(iRule.Name); err != nil {
+			return err
+		}
+		if iRule.Code == "" {
+			return fmt.Errorf("code of sharedBundle iRule %v is not provided", iRule.Name)
+		}
+	}
+	for _, prof := range sb.Profiles {
+		if err := checkName(prof.Name); err != nil {
+			return err
+		}
+		if prof.Class == "" {
+			return fmt.Errorf("class of sharedBundle profile %v is not provided", prof.Name)
+		}
+	}
+	return nil
+}
+
+// setSharedBundle sets the shared bundle of the global extended ConfigMap, objects of the bundle are removed
+// from BIG-IP once the bundle is removed
+func (ctlr *Controller) setSharedBundle(sb *SharedBundle, isDelete bool) error {
+	if isDelete || sb == nil || (len(sb.IRules) == 0 && len(sb.Profiles) == 0) {
+		if ctlr.resources.sharedBundle != nil {
+			log.Infof("Shared bundle is removed from /%v/%v", sharedBundlePartition, as3SharedApplication)
+		}
+		ctlr.resources.sharedBundle = nil
+		return nil
+	}
+	if err := sb.validate(); err != nil {
+		return err
+	}
+	// bundle is replaced only when updated, so that the unchanged bundle is not posted again
+	if reflect.DeepEqual(ctlr.resources.sharedBundle, sb) {
+		return nil
+	}
+	log.Infof("Shared bundle of %v iRules and %v profiles is installed in /%v/%v", len(sb.IRules),
+		len(sb.Profiles), sharedBundlePartition, as3SharedApplication)
+	ctlr.resources.sharedBundle = sb
+	return nil
+}
+
+// hasIRule checks whether the iRule with the full path is an iRule of the shared bundle
+func (sb *SharedBundle) hasIRule(path string) bool {
+	if sb == nil {
+		return false
+	}
+	for _, iRule := range sb.IRules {
+		if path == fmt.Sprintf("/%v/%v/%v", sharedBundlePartition, as3SharedApplication, iRule.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// createSharedBundleTenantDecl returns the declaration of the Common tenant with the objects of the shared bundle,
+// Common tenant is flushed when the bundle is nil
+func createSharedBundleTenantDecl(sb *SharedBundle) as3Tenant {
+	sharedApp := as3Application{}
+	sharedApp["class"] = "Application"
+	sharedApp["template"] = "shared"
+	if sb != nil {
+		for _, iRule := range sb.IRules {
+			sharedApp[iRule.Name] = &as3IRules{
+				Class: "iRule",
+				IRule: iRule.Code,
+			}
+		}
+		for _, prof := range sb.Profiles {
+			profDecl := make(map[string]interface{}, len(prof.Properties)+1)
+			for key, value := range prof.Properties {
+				profDecl[key] = getAS3PropertyValue(value)
+			}
+			profDecl["class"] = prof.Class
+			sharedApp[prof.Name] = profDecl
+		}
+	}
+	return as3Tenant{
+		"class":              "Tenant",
+		as3SharedApplication: sharedApp,
+	}
+}
+
+// getAS3PropertyValue returns the value of the profile property with the maps decoded from YAML converted to
+// the JSON objects
+func getAS3PropertyValue(value interface{}) interface{} {
+	switch val := value.(type) {
+	case map[interface{}]interface{}:
+		obj := make(map[string]interface{}, len(val))
+		for key, v := range val {
+			obj[fmt.Sprint(key)] = getAS3PropertyValue(v)
+		}
+		return obj
+	case []interface{}:
+		list := make([]interface{}, len(val))
+		for i, v := range val {
+			list[i] = getAS3PropertyValue(v)
+		}
+		return list
+	}
+	return value
+}
+
+// isSharedBundleTenant checks whether the tenant is the Common tenant of the shared bundle, Common tenant is
+// posted only to install or remove the shared bundle
+func (agent *Agent) isSharedBundleTenant(rsConfig ResourceConfigRequest, tenant string) bool {
+	if tenant != sharedBundlePartition {
+		return false
+	}
+	_, installed := agent.cachedTenantDeclMap[sharedBundlePartition]
+	return rsConfig.sharedBundle != nil || installed
+}
@@ -0,0 +1,180 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+)
+
+// sharedPoolKey identifies the pools which are shared, pools of the same service with the same members,
+// settings and monitors are identical on BIG-IP irrespective of their names
+type sharedPoolKey struct {
+	Service  string        `json:"service"`
+	Pool     as3Pool       `json:"pool"`
+	Monitors []interface{} `json:"monitors"`
+}
+
+// shareIdenticalPools replaces the identical pools framed for the different virtuals and hosts of the tenant
+// with a single pool, the pool with the first name is shared by the virtual servers and policies referencing the
+// others. Pools referenced by the iRules, data groups or any other objects are not shared. The name of the shared
+// pool is returned with the names of the removed pools
+func shareIdenticalPools(rsMap ResourceMap, sharedApp as3Application) map[string]string {
+	// services of the pools framed by CIS, other pools such as the request logging pools are not shared
+	poolServices := make(map[string]string)
+	for _, rsCfg := range rsMap {
+		for _, pool := range rsCfg.Pools {
+			poolServices[pool.Name] = fmt.Sprintf("%v/%v/%v/%v", pool.Cluster, pool.ServiceNamespace,
+				pool.ServiceName, pool.ServicePort.String())
+		}
+	}
+	references := getPoolReferences(sharedApp)
+
+	identicalPools := make(map[string][]string)
+	for name, obj := range sharedApp {
+		pool, ok := obj.(*as3Pool)
+		if !ok {
+			continue
+		}
+		svc, found := poolServices[name]
+		if !found || strings.Contains(references, name) {
+			continue
+		}
+		key := sharedPoolKey{Service: svc, Pool: *pool}
+		key.Pool.Monitors = nil
+		for _, monitor := range pool.Monitors {
+			if monitor.Use == "" {
+				key.Monitors = append(key.Monitors, monitor)
+				continue
+			}
+			use := strings.Split(monitor.Use, "/")
+			key.Monitors = append(key.Monitors, sharedApp[use[len(use)-1]])
+		}
+		data, err := json.Marshal(key)
+		if err != nil {
+			continue
+		}
+		identicalPools[string(data)] = append(identicalPools[string(data)], name)
+	}
+
+	sharedPools := make(map[string]string)
+	for _, names := range identicalPools {
+		if len(names) < 2 {
+			continue
+		}
+		sort.Strings(names)
+		for _, name := range names[1:] {
+			sharedPools[name] = names[0]
+		}
+		log.Debugf("[AS3] Pools %v are shared as %v", names[1:], names[0])
+	}
+	if len(sharedPools) == 0 {
+		return nil
+	}
+
+	// monitors of the removed pools are removed unless they are used by the other pools
+	removedMonitors := make(map[string]struct{})
+	for name := range sharedPools {
+		for _, monitor := range sharedApp[name].(*as3Pool).Monitors {
+			use := strings.Split(monitor.Use, "/")
+			removedMonitors[use[len(use)-1]] = struct{}{}
+		}
+		delete(sharedApp, name)
+	}
+	sharePool := func(pool *as3ResourcePointer) {
+		if pool == nil || pool.Use == "" {
+			return
+		}
+		use := strings.Split(pool.Use, "/")
+		name := use[len(use)-1]
+		if shared, ok := sharedPools[name]; ok {
+			pool.Use = strings.TrimSuffix(pool.Use, name) + shared
+		}
+	}
+	for _, obj := range sharedApp {
+		switch obj := obj.(type) {
+		case *as3Service:
+			sharePool(obj.Pool)
+		case *as3EndpointPolicy:
+			for _, rule := range obj.Rules {
+				for _, action := range rule.Actions {
+					if action.Select != nil {
+						sharePool(action.Select.Pool)
+					}
+				}
+			}
+		case *as3Pool:
+			for _, monitor := range obj.Monitors {
+				use := strings.Split(monitor.Use, "/")
+				delete(removedMonitors, use[len(use)-1])
+			}
+		}
+	}
+	for name := range removedMonitors {
+		if _, ok := sharedApp[name].(*as3Monitor); ok && !strings.Contains(references, name) {
+			delete(sharedApp, name)
+		}
+	}
+	return sharedPools
+}
+
+// setSharedPools records the pools shared in the declaration of the partition
+func (agent *Agent) setSharedPools(partition string, sharedPools map[string]string) {
+	agent.sharedPools.Lock()
+	defer agent.sharedPools.Unlock()
+	if agent.sharedPools.pools == nil {
+		agent.sharedPools.pools = make(map[string]map[string]string)
+	}
+	if len(sharedPools) == 0 {
+		delete(agent.sharedPools.pools, partition)
+		return
+	}
+	agent.sharedPools.pools[partition] = sharedPools
+}
+
+// getSharedPool returns the name of the pool posted to BIG-IP for the pool of the partition, the pool is
+// replaced with the shared pool when it is identical to the shared pool
+func (agent *Agent) getSharedPool(partition, pool string) string {
+	agent.sharedPools.RLock()
+	defer agent.sharedPools.RUnlock()
+	if shared, ok := agent.sharedPools.pools[partition][pool]; ok {
+		return shared
+	}
+	return pool
+}
+
+// getPoolReferences returns the objects of the application which may refer the pools by name, other than the
+// virtual servers, policies and pools
+func getPoolReferences(sharedApp as3Application) string {
+	var references strings.Builder
+	for _, obj := range sharedApp {
+		switch obj.(type) {
+		case *as3Service, *as3EndpointPolicy, *as3Pool, *as3Monitor, string:
+			continue
+		}
+		data, err := json.Marshal(obj)
+		if err != nil {
+			continue
+		}
+		references.Write(data)
+	}
+	return references.String()
+}
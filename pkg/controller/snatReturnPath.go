@@ -0,0 +1,92 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"fmt"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// ConditionSNATReturnPathUnverified is the VirtualServer and TransportServer status condition set when
+	// the virtual preserves the client address with snat none, but CIS cannot verify that the response of
+	// the pool members is routed back through BIG-IP
+	ConditionSNATReturnPathUnverified = "SNATReturnPathUnverified"
+	// SNAT value of the virtual preserving the client address
+	SNATNone = "none"
+)
+
+// getSNATReturnPathError returns the reason the response of the pool members to the client address may not be
+// routed through BIG-IP, empty when BIG-IP routes the pod network with the static routes or the tunnel of CIS.
+// Response not routed through BIG-IP is dropped by the client, as it is not from the virtual address
+func (ctlr *Controller) getSNATReturnPathError() string {
+	switch ctlr.PoolMemberType {
+	case NodePort, NodePortLocal:
+		return fmt.Sprintf("pool members of %v mode are the nodes, which route the response to the client "+
+			"address using their own gateway", ctlr.PoolMemberType)
+	case Cluster:
+		if ctlr.StaticRoutingMode || ctlr.vxlanMgr != nil {
+			return ""
+		}
+		return "neither the static routes nor the tunnel of BIG-IP to the pod network are configured by CIS"
+	}
+	return ""
+}
+
+// checkSNATReturnPath sets the warning condition on the resource of the virtual with snat none when the return
+// path of the pool members through BIG-IP is not verified, condition is removed once it is verified or the
+// client address is translated
+func (ctlr *Controller) checkSNATReturnPath(rsc metav1.Object, snat string) {
+	reason := ""
+	if snat == SNATNone {
+		reason = ctlr.getSNATReturnPathError()
+	}
+	if reason == "" {
+		ctlr.removeResourceCondition(rsc, ConditionSNATReturnPathUnverified)
+		return
+	}
+	message := fmt.Sprintf("snat none preserves the client address, but the return traffic may not reach BIG-IP: "+
+		"%v. Route the pool member traffic to the client addresses through BIG-IP or use snat auto", reason)
+	var conditions []metav1.Condition
+	switch obj := rsc.(type) {
+	case *cisapiv1.VirtualServer:
+		conditions = obj.Status.Conditions
+	case *cisapiv1.TransportServer:
+		conditions = obj.Status.Conditions
+	}
+	if cond := meta.FindStatusCondition(conditions, ConditionSNATReturnPathUnverified); cond != nil &&
+		cond.Message == message {
+		return
+	}
+	log.Warningf("%v/%v: %v", rsc.GetNamespace(), rsc.GetName(), message)
+	ctlr.setResourceCondition(rsc, metav1.Condition{
+		Type:               ConditionSNATReturnPathUnverified,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: rsc.GetGeneration(),
+		Reason:             "SNATNone",
+		Message:            message,
+	})
+	if obj, ok := rsc.(runtime.Object); ok && ctlr.eventRecorder != nil {
+		ctlr.eventRecorder.Event(obj, v1.EventTypeWarning, "SNATReturnPathUnverified", message)
+	}
+}
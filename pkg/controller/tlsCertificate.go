@@ -0,0 +1,258 @@
+/*-
+* Copyright (c) 2016-2021, F5 Networks, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// TLSCertificate types
+	CertificateType = "certificate"
+	CABundleType    = "caBundle"
+)
+
+// getTLSCertificateName returns the name of the certificate created for the TLSCertificate
+func getTLSCertificateName(namespace, name string) string {
+	return AS3NameFormatter(fmt.Sprintf("%s_%s", namespace, name))
+}
+
+// getTLSCertificateType returns the type of the TLSCertificate, certificate by default
+func getTLSCertificateType(cert *cisapiv1.TLSCertificate) string {
+	if cert.Spec.Type == "" {
+		return CertificateType
+	}
+	return cert.Spec.Type
+}
+
+// getTLSCertificate returns the TLSCertificate in the namespace
+func (ctlr *Controller) getTLSCertificate(namespace, name string) *cisapiv1.TLSCertificate {
+	crInf, ok := ctlr.getNamespacedCRInformer(namespace)
+	if !ok || crInf.certInformer == nil {
+		return nil
+	}
+	obj, found, err := crInf.certInformer.GetIndexer().GetByKey(namespace + "/" + name)
+	if err != nil || !found {
+		return nil
+	}
+	return obj.(*cisapiv1.TLSCertificate)
+}
+
+// getSharedCertificate returns the certificate or the CA bundle of the TLSCertificate
+// specified inline or in the secret
+func (ctlr *Controller) getSharedCertificate(cert *cisapiv1.TLSCertificate) (*SharedCertificate, error) {
+	sc := &SharedCertificate{
+		Name:        getTLSCertificateName(cert.Namespace, cert.Name),
+		Type:        getTLSCertificateType(cert),
+		Certificate: cert.Spec.Certificate,
+		PrivateKey:  cert.Spec.PrivateKey,
+	}
+	if cert.Spec.Secret != "" {
+		if cert.Spec.Certificate != "" || cert.Spec.PrivateKey != "" {
+			return nil, fmt.Errorf("secret can not be used along with the inline certificate")
+		}
+		comInf, ok := ctlr.getNamespacedCommonInformer(cert.Namespace)
+		if !ok || comInf.secretsInformer == nil {
+			return nil, fmt.Errorf("secret informer not found for namespace %v", cert.Namespace)
+		}
+		obj, found, err := comInf.secretsInformer.GetIndexer().GetByKey(cert.Namespace + "/" + cert.Spec.Secret)
+		if err != nil || !found {
+			return nil, fmt.Errorf("secret %v not found", cert.Spec.Secret)
+		}
+		secret := obj.(*v1.Secret)
+		sc.Certificate = string(secret.Data["tls.crt"])
+		sc.PrivateKey = string(secret.Data["tls.key"])
+		if sc.Type == CABundleType {
+			// CA bundles are usually stored as ca.crt
+			if caCert, ok := secret.Data["ca.crt"]; ok {
+				sc.Certificate = string(caCert)
+			}
+			sc.PrivateKey = ""
+		}
+	}
+	if sc.Certificate == "" {
+		return nil, fmt.Errorf("certificate not specified")
+	}
+	if sc.Type == CertificateType && sc.PrivateKey == "" {
+		return nil, fmt.Errorf("private key not specified")
+	}
+	return sc, nil
+}
+
+// processTLSCertificate renders the TLSCertificate in the shared application of its partition
+func (ctlr *Controller) processTLSCertificate(cert *cisapiv1.TLSCertificate, isCertDeleted bool) {
+	startTime := time.Now()
+	defer func() {
+		endTime := time.Now()
+		log.Debugf("Finished syncing TLSCertificate %v/%v (%v)",
+			cert.Namespace, cert.Name, endTime.Sub(startTime))
+	}()
+
+	var sc *SharedCertificate
+	if !isCertDeleted {
+		var err error
+		sc, err = ctlr.getSharedCertificate(cert)
+		if err != nil {
+			log.Errorf("Invalid TLSCertificate %v/%v: %v", cert.Namespace, cert.Name, err)
+			// Remove the certificate rendered earlier for the TLSCertificate
+			isCertDeleted = true
+		}
+	}
+
+	certKey := cert.Namespace + "/" + cert.Name
+	partition := ctlr.getCRPartition(cert.Spec.Partition)
+	// Remove the certificate from the partitions it is no longer part of
+	for _, prtn := range ctlr.resources.getLTMPartitions() {
+		if prtn == partition && !isCertDeleted {
+			continue
+		}
+		delete(ctlr.resources.ltmConfig[prtn].Certificates, certKey)
+	}
+	if isCertDeleted {
+		return
+	}
+
+	ctlr.resources.getPartitionConfig(partition).Certificates[certKey] = sc
+}
+
+// getTLSProfilesForTLSCertificate returns the TLSProfiles referring the TLSCertificate
+func (ctlr *Controller) getTLSProfilesForTLSCertificate(cert *cisapiv1.TLSCertificate) []*cisapiv1.TLSProfile {
+	var allTLSProfiles []*cisapiv1.TLSProfile
+
+	crInf, ok := ctlr.getNamespacedCRInformer(cert.Namespace)
+	if !ok {
+		log.Errorf("Informer not found for namespace: %v", cert.Namespace)
+		return nil
+	}
+	orderedTLS, err := crInf.tlsInformer.GetIndexer().ByIndex("namespace", cert.Namespace)
+	if err != nil {
+		log.Errorf("Unable to get list of TLS Profiles for namespace '%v': %v",
+			cert.Namespace, err)
+		return nil
+	}
+
+	for _, obj := range orderedTLS {
+		tlsProfile := obj.(*cisapiv1.TLSProfile)
+		if tlsProfile.Spec.TLS.Reference != TLSCertificateRef {
+			continue
+		}
+		names := append([]string{tlsProfile.Spec.TLS.ClientSSL, tlsProfile.Spec.TLS.ServerSSL},
+			tlsProfile.Spec.TLS.ClientSSLs...)
+		names = append(names, tlsProfile.Spec.TLS.ServerSSLs...)
+		for _, name := range names {
+			if name == cert.Name {
+				allTLSProfiles = append(allTLSProfiles, tlsProfile)
+				break
+			}
+		}
+	}
+	return allTLSProfiles
+}
+
+// getTLSCertificatesForSecret returns the TLSCertificates stored in the secret
+func (ctlr *Controller) getTLSCertificatesForSecret(secret *v1.Secret) []*cisapiv1.TLSCertificate {
+	var certs []*cisapiv1.TLSCertificate
+
+	crInf, ok := ctlr.getNamespacedCRInformer(secret.Namespace)
+	if !ok || crInf.certInformer == nil {
+		return nil
+	}
+	objs, err := crInf.certInformer.GetIndexer().ByIndex("namespace", secret.Namespace)
+	if err != nil {
+		log.Errorf("Unable to get list of TLSCertificates for namespace '%v': %v",
+			secret.Namespace, err)
+		return nil
+	}
+	for _, obj := range objs {
+		cert := obj.(*cisapiv1.TLSCertificate)
+		if cert.Spec.Secret == secret.Name {
+			certs = append(certs, cert)
+		}
+	}
+	return certs
+}
+
+// getReferredSharedCertificate returns the certificate of the TLSCertificate referred by the TLSProfile
+func (ctlr *Controller) getReferredSharedCertificate(
+	rsCfg *ResourceConfig,
+	namespace, name, certType string,
+) (*SharedCertificate, error) {
+	cert := ctlr.getTLSCertificate(namespace, name)
+	if cert == nil {
+		return nil, fmt.Errorf("TLSCertificate %v not found", name)
+	}
+	if getTLSCertificateType(cert) != certType {
+		return nil, fmt.Errorf("TLSCertificate %v is not of type %v", name, certType)
+	}
+	if ctlr.getCRPartition(cert.Spec.Partition) != rsCfg.Virtual.Partition {
+		return nil, fmt.Errorf("TLSCertificate %v is not in the partition %v", name, rsCfg.Virtual.Partition)
+	}
+	return ctlr.getSharedCertificate(cert)
+}
+
+// createTLSCertificateProfiles creates the SSL profiles referring the certificates
+// of the TLSCertificates instead of the certificates of each virtual
+func (ctlr *Controller) createTLSCertificateProfiles(
+	rsCfg *ResourceConfig,
+	namespace string,
+	clientSSLs, serverSSLs []string,
+) error {
+	if len(clientSSLs) > 0 {
+		var certNames []string
+		for _, name := range clientSSLs {
+			sc, err := ctlr.getReferredSharedCertificate(rsCfg, namespace, name, CertificateType)
+			if err != nil {
+				return err
+			}
+			certNames = append(certNames, sc.Name)
+		}
+		err, _ := ctlr.createClientSSLProfile(rsCfg, nil, clientSSLs[0], namespace,
+			ctlr.resources.baseRouteConfig.TLSCipher, CustomProfileClient)
+		if err != nil {
+			return err
+		}
+		skey := SecretKey{Name: clientSSLs[0], ResourceName: rsCfg.GetName()}
+		cp := rsCfg.customProfiles[skey]
+		cp.SharedCertificates = certNames
+		rsCfg.customProfiles[skey] = cp
+	}
+	if len(serverSSLs) > 0 {
+		// TLS Client trusts a single CA bundle
+		if len(serverSSLs) > 1 {
+			return fmt.Errorf("only one TLSCertificate of type %v is supported as serverSSL", CABundleType)
+		}
+		sc, err := ctlr.getReferredSharedCertificate(rsCfg, namespace, serverSSLs[0], CABundleType)
+		if err != nil {
+			return err
+		}
+		err, _ = ctlr.createServerSSLProfile(rsCfg, nil, "", serverSSLs[0], namespace,
+			ctlr.resources.baseRouteConfig.TLSCipher, CustomProfileServer)
+		if err != nil {
+			return err
+		}
+		skey := SecretKey{Name: serverSSLs[0], ResourceName: rsCfg.GetName()}
+		cp := rsCfg.customProfiles[skey]
+		cp.SharedCABundle = sc.Name
+		rsCfg.customProfiles[skey] = cp
+	}
+	return nil
+}
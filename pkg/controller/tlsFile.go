@@ -0,0 +1,235 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	// files of the certificate and the key in the directory mounted by the Secrets Store CSI driver
+	tlsCertFile = "tls.crt"
+	tlsKeyFile  = "tls.key"
+	// the files of a directory are updated together on rotation, TLSProfiles are processed once after the delay
+	tlsFileEventDelay = 2 * time.Second
+)
+
+// getTLSFileDirectory returns the directory of the certificate referred by the TLSProfile, relative directories are
+// in --tls-file-directory and no directory outside it is referred
+func (ctlr *Controller) getTLSFileDirectory(name string) (string, error) {
+	baseDir := ctlr.tlsFiles.directory
+	if baseDir == "" {
+		return "", fmt.Errorf("--tls-file-directory is not set to refer the files")
+	}
+	dir := name
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(baseDir, dir)
+	}
+	dir = filepath.Clean(dir)
+	rel, err := filepath.Rel(baseDir, dir)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("directory %v is not in --tls-file-directory %v", name, baseDir)
+	}
+	return dir, nil
+}
+
+// getTLSFileProfileName returns the name of the profile of the certificate directory
+func (ctlr *Controller) getTLSFileProfileName(dir string) string {
+	rel, _ := filepath.Rel(ctlr.tlsFiles.directory, dir)
+	return AS3NameFormatter(rel)
+}
+
+// readTLSFiles reads the certificate and the key of the directory, the directory is watched for the rotation
+func (ctlr *Controller) readTLSFiles(dir string, keyRequired bool) (certificate, error) {
+	var cert certificate
+	if err := ctlr.watchTLSFileDirectory(dir); err != nil {
+		return cert, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, tlsCertFile))
+	if err != nil {
+		return cert, fmt.Errorf("unable to read %v of directory %v: %v", tlsCertFile, dir, err)
+	}
+	cert.Cert = string(data)
+	if !keyRequired {
+		return cert, nil
+	}
+	data, err = os.ReadFile(filepath.Join(dir, tlsKeyFile))
+	if err != nil {
+		return cert, fmt.Errorf("unable to read %v of directory %v: %v", tlsKeyFile, dir, err)
+	}
+	cert.Key = string(data)
+	return cert, nil
+}
+
+// createTLSFileProfiles creates the SSL profiles with the certificates and keys of the files mounted by the Secrets
+// Store CSI driver, for the environments where the keys can not be stored as kubernetes secrets
+func (ctlr *Controller) createTLSFileProfiles(
+	rsCfg *ResourceConfig,
+	namespace string,
+	clientSSLs, serverSSLs []string,
+) error {
+	if len(clientSSLs) > 0 {
+		var certificates []certificate
+		var profileName string
+		for _, name := range clientSSLs {
+			dir, err := ctlr.getTLSFileDirectory(name)
+			if err != nil {
+				return err
+			}
+			cert, err := ctlr.readTLSFiles(dir, true)
+			if err != nil {
+				return err
+			}
+			if profileName == "" {
+				profileName = ctlr.getTLSFileProfileName(dir)
+			}
+			certificates = append(certificates, cert)
+		}
+		err, _ := ctlr.createClientSSLProfile(rsCfg, certificates, profileName, namespace,
+			ctlr.resources.baseRouteConfig.TLSCipher, CustomProfileClient)
+		if err != nil {
+			return err
+		}
+	}
+	if len(serverSSLs) > 0 {
+		var certificates []certificate
+		var profileName string
+		for _, name := range serverSSLs {
+			dir, err := ctlr.getTLSFileDirectory(name)
+			if err != nil {
+				return err
+			}
+			// tls.key is not mandatory for ServerSSL Profile
+			cert, err := ctlr.readTLSFiles(dir, false)
+			if err != nil {
+				return err
+			}
+			if profileName == "" {
+				profileName = ctlr.getTLSFileProfileName(dir)
+			}
+			certificates = append(certificates, cert)
+		}
+		err, _ := ctlr.createServerSSLProfile(rsCfg, certificates, "", profileName, namespace,
+			ctlr.resources.baseRouteConfig.TLSCipher, CustomProfileServer)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// watchTLSFileDirectory watches the directory of the certificate for the rotation of the files
+func (ctlr *Controller) watchTLSFileDirectory(dir string) error {
+	store := &ctlr.tlsFiles
+	store.Lock()
+	defer store.Unlock()
+	if _, ok := store.watched[dir]; ok {
+		return nil
+	}
+	if store.watcher == nil {
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			return fmt.Errorf("unable to watch the TLS files: %v", err)
+		}
+		store.watcher = watcher
+		store.watched = make(map[string]struct{})
+		store.pending = make(map[string]struct{})
+		go ctlr.processTLSFileEvents(watcher)
+	}
+	if err := store.watcher.Add(dir); err != nil {
+		return fmt.Errorf("unable to watch directory %v: %v", dir, err)
+	}
+	store.watched[dir] = struct{}{}
+	log.Debugf("Watching the TLS files of directory %v", dir)
+	return nil
+}
+
+// processTLSFileEvents processes the TLSProfiles referring the directories of the updated files
+func (ctlr *Controller) processTLSFileEvents(watcher *fsnotify.Watcher) {
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			dir := filepath.Dir(event.Name)
+			store := &ctlr.tlsFiles
+			store.Lock()
+			if _, ok := store.pending[dir]; !ok {
+				store.pending[dir] = struct{}{}
+				time.AfterFunc(tlsFileEventDelay, func() {
+					store.Lock()
+					delete(store.pending, dir)
+					store.Unlock()
+					ctlr.enqueueTLSProfilesForDirectory(dir)
+				})
+			}
+			store.Unlock()
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Errorf("Error watching the TLS files: %v", err)
+		}
+	}
+}
+
+// enqueueTLSProfilesForDirectory enqueues the TLSProfiles referring the files of the directory
+func (ctlr *Controller) enqueueTLSProfilesForDirectory(dir string) {
+	for _, tlsProfile := range ctlr.getTLSProfilesForDirectory(dir) {
+		log.Infof("TLS files of directory %v are updated, processing TLSProfile %v/%v", dir,
+			tlsProfile.Namespace, tlsProfile.Name)
+		ctlr.enqueueTLSProfile(tlsProfile, Update)
+	}
+}
+
+// getTLSProfilesForDirectory returns the TLSProfiles with reference file referring the directory
+func (ctlr *Controller) getTLSProfilesForDirectory(dir string) []*cisapiv1.TLSProfile {
+	var allTLSProfiles []*cisapiv1.TLSProfile
+	for _, crInf := range ctlr.crInformers {
+		if crInf.tlsInformer == nil {
+			continue
+		}
+		for _, obj := range crInf.tlsInformer.GetIndexer().List() {
+			tlsProfile := obj.(*cisapiv1.TLSProfile)
+			if tlsProfile.Spec.TLS.Reference != FileRef {
+				continue
+			}
+			names := append([]string{tlsProfile.Spec.TLS.ClientSSL, tlsProfile.Spec.TLS.ServerSSL},
+				tlsProfile.Spec.TLS.ClientSSLs...)
+			names = append(names, tlsProfile.Spec.TLS.ServerSSLs...)
+			for _, name := range names {
+				if name == "" {
+					continue
+				}
+				if refDir, err := ctlr.getTLSFileDirectory(name); err == nil && refDir == dir {
+					allTLSProfiles = append(allTLSProfiles, tlsProfile)
+					break
+				}
+			}
+		}
+	}
+	return allTLSProfiles
+}
@@ -0,0 +1,182 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+
+	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// field of the passphrase secret holding the passphrase of the private key
+	passphraseField = "passphrase"
+	// JWE protected header of the AS3 passphrase which is not encrypted, {"alg":"dir","enc":"none"}
+	as3PassphraseProtected = "eyJhbGciOiJkaXIiLCJlbmMiOiJub25lIn0"
+)
+
+// hasPrivateKey checks whether the private key of the certificate is specified, either with the certificate
+// or on BIG-IP
+func (cert certificate) hasPrivateKey() bool {
+	return len(cert.Key) > 0 || len(cert.BigIPKey) > 0
+}
+
+// newAS3Passphrase returns the AS3 passphrase of the private key
+func newAS3Passphrase(passphrase string) *as3Passphrase {
+	if passphrase == "" {
+		return nil
+	}
+	return &as3Passphrase{
+		Ciphertext: base64.StdEncoding.EncodeToString([]byte(passphrase)),
+		Protected:  as3PassphraseProtected,
+	}
+}
+
+// getTLSPrivateKey returns the private key stored on BIG-IP of the certificate of the clientSSL secret
+func getTLSPrivateKey(privateKeys []cisapiv1.TLSPrivateKey, secret string) *cisapiv1.TLSPrivateKey {
+	for i := range privateKeys {
+		if privateKeys[i].Secret == secret {
+			return &privateKeys[i]
+		}
+	}
+	return nil
+}
+
+// validateTLSPrivateKeys validates the private keys stored on BIG-IP of the TLSProfile, keys are supported only
+// for the certificates of the clientSSL secrets
+func validateTLSPrivateKeys(tls *cisapiv1.TLSProfile) bool {
+	if len(tls.Spec.TLS.PrivateKeys) == 0 {
+		return true
+	}
+	if tls.Spec.TLS.Reference != Secret || tls.Spec.TLS.Termination == TLSPassthrough {
+		log.Errorf("TLSProfile %s with privateKeys should refer the clientSSL secrets", tls.ObjectMeta.Name)
+		return false
+	}
+	clientSSLs := make(map[string]struct{})
+	for _, secret := range append([]string{tls.Spec.TLS.ClientSSL}, tls.Spec.TLS.ClientSSLs...) {
+		clientSSLs[secret] = struct{}{}
+	}
+	for _, privateKey := range tls.Spec.TLS.PrivateKeys {
+		if privateKey.BigIPKey == "" {
+			log.Errorf("TLSProfile %s should specify the bigipKey of the secret %s", tls.ObjectMeta.Name,
+				privateKey.Secret)
+			return false
+		}
+		if _, ok := clientSSLs[privateKey.Secret]; !ok || privateKey.Secret == "" {
+			log.Errorf("TLSProfile %s refers the key of the secret %s which is not a clientSSL",
+				tls.ObjectMeta.Name, privateKey.Secret)
+			return false
+		}
+	}
+	return true
+}
+
+// checkSecretCertificateHost validates the certificate of the clientSSL secret with the host, only the certificate
+// is validated when its private key is stored on BIG-IP
+func checkSecretCertificateHost(host string, secret *v1.Secret, privateKeys []cisapiv1.TLSPrivateKey) bool {
+	if getTLSPrivateKey(privateKeys, secret.Name) == nil {
+		return checkCertificateHost(host, secret.Data["tls.crt"], secret.Data["tls.key"])
+	}
+	block, _ := pem.Decode(secret.Data["tls.crt"])
+	if block == nil {
+		log.Errorf("Failed to validate TLS cert of secret %v: certificate not found", secret.Name)
+		return false
+	}
+	x509cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		log.Errorf("failed to parse certificate; %s", err)
+		return false
+	}
+	return checkX509CertificateHost(host, x509cert)
+}
+
+// getKeyPassphrase returns the passphrase of the private key stored in the secret
+func (ctlr *Controller) getKeyPassphrase(namespace, name string) (string, error) {
+	comInf, ok := ctlr.getNamespacedCommonInformer(namespace)
+	if !ok || comInf.secretsInformer == nil {
+		return "", fmt.Errorf("secret informer not found for namespace %v", namespace)
+	}
+	obj, found, err := comInf.secretsInformer.GetIndexer().GetByKey(namespace + "/" + name)
+	if err != nil || !found {
+		return "", fmt.Errorf("secret %v not found", name)
+	}
+	passphrase, ok := obj.(*v1.Secret).Data[passphraseField]
+	if !ok {
+		return "", fmt.Errorf("Invalid Secret '%v': '%v' field not specified.", name, passphraseField)
+	}
+	return string(passphrase), nil
+}
+
+// createPrivateKeyClientSSLProfile creates a new ClientSSL profile from the Secrets, private keys referred in the
+// TLSProfile are used from BIG-IP instead of tls.key of the Secrets, such as the keys in the FIPS partition or
+// the external HSM which can not exist in kubernetes
+func (ctlr *Controller) createPrivateKeyClientSSLProfile(
+	rsCfg *ResourceConfig,
+	secrets []*v1.Secret,
+	privateKeys []cisapiv1.TLSPrivateKey,
+	tlsCipher TLSCipher,
+	context string,
+) (error, bool) {
+
+	var certificates []certificate
+	for _, secret := range secrets {
+		if _, ok := secret.Data["tls.crt"]; !ok {
+			err := fmt.Errorf("Invalid Secret '%v': 'tls.crt' field not specified.",
+				secret.ObjectMeta.Name)
+			return err, false
+		}
+		cert := certificate{Cert: string(secret.Data["tls.crt"])}
+		privateKey := getTLSPrivateKey(privateKeys, secret.Name)
+		if privateKey == nil {
+			// certificate and key both are stored in the secret
+			if _, ok := secret.Data["tls.key"]; !ok {
+				err := fmt.Errorf("Invalid Secret '%v': 'tls.key' field not specified.",
+					secret.ObjectMeta.Name)
+				return err, false
+			}
+			cert.Key = string(secret.Data["tls.key"])
+			certificates = append(certificates, cert)
+			continue
+		}
+		cert.BigIPKey = privateKey.BigIPKey
+		if privateKey.PassphraseSecret != "" {
+			passphrase, err := ctlr.getKeyPassphrase(secret.Namespace, privateKey.PassphraseSecret)
+			if err != nil {
+				return err, false
+			}
+			cert.Passphrase = passphrase
+		}
+		certificates = append(certificates, cert)
+	}
+
+	return ctlr.createClientSSLProfile(rsCfg, certificates, secrets[0].ObjectMeta.Name, secrets[0].ObjectMeta.Namespace, tlsCipher, context)
+}
+
+// refersPassphraseSecret checks whether the TLSProfile refers the secret for the passphrase of a private key
+func refersPassphraseSecret(tlsProfile *cisapiv1.TLSProfile, secret string) bool {
+	for _, privateKey := range tlsProfile.Spec.TLS.PrivateKeys {
+		if privateKey.PassphraseSecret == secret {
+			return true
+		}
+	}
+	return false
+}
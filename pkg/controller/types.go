@@ -18,10 +18,13 @@ package controller
 
 import (
 	"container/list"
+	"context"
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
+	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/httpclient"
 	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vxlan"
 	"net/http"
 	"sync"
+	"time"
 
 	ficV1 "github.com/F5Networks/f5-ipam-controller/pkg/ipamapis/apis/fic/v1"
 
@@ -29,7 +32,9 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/fsnotify/fsnotify"
 	routeclient "github.com/openshift/client-go/route/clientset/versioned/typed/route/v1"
+	"github.com/xeipuuv/gojsonschema"
 
 	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/teem"
 
@@ -46,6 +51,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 )
 
@@ -56,6 +62,7 @@ type (
 		resources              *ResourceStore
 		kubeCRClient           versioned.Interface
 		kubeClient             kubernetes.Interface
+		eventRecorder          record.EventRecorder
 		kubeAPIClient          *extClient.Clientset
 		eventNotifier          *apm.EventNotifier
 		nativeResourceSelector labels.Selector
@@ -92,6 +99,46 @@ type (
 		multiClusterMode       string
 		haModeType             HAModeType
 		clusterRatio           map[string]*int
+		downClusters           map[string]struct{}
+		primaryLease           primaryClusterLease
+		autoGenerateWideIP     bool
+		enableCRDIngress       bool
+		enableACMESolver       bool
+		enforceSvcRefGrants    bool
+		enableFinalizers       bool
+		resourceFinalizers     finalizerStore
+		enableQuarantine       bool
+		secondaryNetworks      bool
+		dualStackMembers       bool
+		poolMemberRatio        bool
+		warmUpPods             warmUpStore
+		resourceQuarantine     quarantineStore
+		capacityParams         CapacityParams
+		bigIPCapacity          capacityStore
+		memberStatsInterval    int
+		memberStats            poolMemberStatsStore
+		nodeBatchInterval      int
+		nodeEvents             nodeEventBatch
+		unschedulableNodes     string
+		poolMemberNodeOS       []string
+		vxlanExcludedNodeOS    []string
+		resourceClass          string
+		defaultPolicy          string
+		ingressClass           string
+		deployConfigCR         string
+		deployConfigSpec       *cisapiv1.DeployConfigSpec
+		dcInformer             *DeployConfigInformer
+		// tlsFiles watches the certificates and keys mounted by the Secrets Store CSI driver
+		tlsFiles tlsFileStore
+		// bigipReferences restricts the BIG-IP objects referred by the resources of the namespaces
+		bigipReferences BigIPReferences
+		// bigipAllowList of the global extended ConfigMap restricts the BIG-IP objects referred by the resources
+		bigipAllowList *BigIPAllowList
+		// LTM policies and iRules referred by the VirtualServers are verified on BIG-IP with the interval
+		bigipObjectCheckInterval int
+		bigipObjects             bigipObjectStore
+		// namespaceShard of the replica, namespaces of the other shards are not watched
+		namespaceShard NamespaceShard
 		resourceContext
 	}
 	resourceContext struct {
@@ -133,16 +180,46 @@ type (
 		StaticRoutingMode           bool
 		OrchestrationCNI            string
 		MultiClusterMode            string
+		AutoGenerateWideIP          bool
+		EnableCRDIngress            bool
+		EnableACMESolver            bool
+		EnforceSvcRefGrants         bool
+		EnableFinalizers            bool
+		EnableQuarantine            bool
+		EnableSecondaryNetworks     bool
+		EnableDualStackMembers      bool
+		EnablePoolMemberRatio       bool
+		CapacityParams              CapacityParams
+		PoolMemberStatsInterval     int
+		NodeEventBatchInterval      int
+		UnschedulableNodeMembers    string
+		PoolMemberNodeOS            []string
+		VXLANExcludedNodeOS         []string
+		ResourceClass               string
+		DefaultPolicy               string
+		IngressClass                string
+		DeployConfigCR              string
+		NamingTemplates             NamingTemplates
+		TLSFileDirectory            string
+		AuditParams                 AuditParams
+		BigIPReferences             BigIPReferences
+		BigIPObjectCheckInterval    int
+		NamespaceShard              NamespaceShard
 	}
 
 	// CRInformer defines the structure of Custom Resource Informer
 	CRInformer struct {
-		namespace   string
-		stopCh      chan struct{}
-		vsInformer  cache.SharedIndexInformer
-		tlsInformer cache.SharedIndexInformer
-		tsInformer  cache.SharedIndexInformer
-		ilInformer  cache.SharedIndexInformer
+		namespace    string
+		stopCh       chan struct{}
+		vsInformer   cache.SharedIndexInformer
+		tlsInformer  cache.SharedIndexInformer
+		tsInformer   cache.SharedIndexInformer
+		ilInformer   cache.SharedIndexInformer
+		ingInformer  cache.SharedIndexInformer
+		dgInformer   cache.SharedIndexInformer
+		certInformer cache.SharedIndexInformer
+		srgInformer  cache.SharedIndexInformer
+		hmInformer   cache.SharedIndexInformer
 	}
 
 	CommonInformer struct {
@@ -171,6 +248,12 @@ type (
 		oldNodes     []Node
 	}
 
+	// DeployConfigInformer defines the structure of the DeployConfig Informer
+	DeployConfigInformer struct {
+		stopCh     chan struct{}
+		dcInformer cache.SharedIndexInformer
+	}
+
 	NSInformer struct {
 		stopCh     chan struct{}
 		cluster    string
@@ -214,11 +297,19 @@ type (
 		AdditionalVirtualAddresses []string              `json:"additionalVirtualAddresses,omitempty"`
 		SNAT                       string                `json:"snat,omitempty"`
 		WAF                        string                `json:"waf,omitempty"`
+		BigIPPolicies              []string              `json:"bigipPolicies,omitempty"`
 		Firewall                   string                `json:"firewallPolicy,omitempty"`
 		LogProfiles                []string              `json:"logProfiles,omitempty"`
+		RequestLogging             RequestLogging        `json:"requestLogging,omitempty"`
 		ProfileL4                  string                `json:"profileL4,omitempty"`
+		FastL4                     FastL4                `json:"fastL4,omitempty"`
+		HTTPProfile                HTTPProfile           `json:"httpProfile,omitempty"`
 		ProfileMultiplex           string                `json:"profileMultiplex,omitempty"`
 		ProfileWebSocket           string                `json:"profileWebSocket,omitempty"`
+		ProfileHTTPCompression     string                `json:"profileHTTPCompression,omitempty"`
+		ProfileWebAcceleration     string                `json:"profileWebAcceleration,omitempty"`
+		ProfileAccess              string                `json:"profileAccess,omitempty"`
+		PolicyPerRequestAccess     string                `json:"policyPerRequestAccess,omitempty"`
 		ProfileDOS                 string                `json:"profileDOS,omitempty"`
 		ProfileBotDefense          string                `json:"profileBotDefense,omitempty"`
 		TCP                        ProfileTCP            `json:"tcp,omitempty"`
@@ -228,6 +319,8 @@ type (
 		TranslateServerPort        bool                  `json:"translateServerPort"`
 		Source                     string                `json:"source,omitempty"`
 		AllowVLANs                 []string              `json:"allowVlans,omitempty"`
+		RejectVLANs                []string              `json:"rejectVlans,omitempty"`
+		TrafficGroup               string                `json:"trafficGroup,omitempty"`
 		PersistenceProfile         string                `json:"persistenceProfile,omitempty"`
 		TLSTermination             string                `json:"-"`
 		AllowSourceRange           []string              `json:"allowSourceRange,omitempty"`
@@ -235,17 +328,23 @@ type (
 		IpIntelligencePolicy       string                `json:"ipIntelligencePolicy,omitempty"`
 		AutoLastHop                string                `json:"lastHop,omitempty"`
 		AnalyticsProfiles          AnalyticsProfiles     `json:"analyticsProfiles,omitempty"`
+		Mirroring                  string                `json:"mirroring,omitempty"`
+		PersistenceMirroring       bool                  `json:"persistenceMirroring,omitempty"`
 	}
 	// Virtuals is slice of virtuals
 	Virtuals []Virtual
 
 	AnalyticsProfiles struct {
 		HTTPAnalyticsProfile string `json:"http,omitempty"`
+		TCPAnalyticsProfile  string `json:"tcp,omitempty"`
 	}
 
 	ProfileTCP struct {
-		Client string `json:"client,omitempty"`
-		Server string `json:"server,omitempty"`
+		Client            string `json:"client,omitempty"`
+		Server            string `json:"server,omitempty"`
+		IdleTimeout       int32  `json:"idleTimeout,omitempty"`
+		Nagle             string `json:"nagle,omitempty"`
+		CongestionControl string `json:"congestionControl,omitempty"`
 	}
 
 	ProfileHTTP2 struct {
@@ -253,8 +352,33 @@ type (
 		Server string `json:"server,omitempty"`
 	}
 
+	// RequestLogging defines the request logging of the virtual to the remote syslog servers
+	RequestLogging struct {
+		Servers  []string `json:"servers"`
+		Port     int32    `json:"port"`
+		Protocol string   `json:"protocol,omitempty"`
+		Template string   `json:"template,omitempty"`
+	}
+
+	// HTTPProfile defines the settings of the HTTP profile of the virtual
+	HTTPProfile struct {
+		InsertXForwardedFor *bool  `json:"insertXForwardedFor,omitempty"`
+		MaxHeaderSize       int32  `json:"maxHeaderSize,omitempty"`
+		MaxHeaderCount      int32  `json:"maxHeaderCount,omitempty"`
+		RewriteRedirects    string `json:"rewriteRedirects,omitempty"`
+		ProxyType           string `json:"proxyType,omitempty"`
+	}
+
+	// FastL4 defines the settings of the FastL4 profile of the virtual
+	FastL4 struct {
+		IdleTimeout         int32 `json:"idleTimeout,omitempty"`
+		LooseInitialization bool  `json:"looseInitialization,omitempty"`
+		LooseClose          bool  `json:"looseClose,omitempty"`
+	}
+
 	// ServiceAddress Service IP address definition (BIG-IP virtual-address).
 	ServiceAddress struct {
+		VirtualAddress     string `json:"virtualAddress,omitempty"`
 		ArpEnabled         bool   `json:"arpEnabled,omitempty"`
 		ICMPEcho           string `json:"icmpEcho,omitempty"`
 		RouteAdvertisement string `json:"routeAdvertisement,omitempty"`
@@ -301,7 +425,10 @@ type (
 		ltmConfigCache LTMConfig
 		gtmConfig      GTMConfig
 		gtmConfigCache GTMConfig
-		nplStore       NPLStore
+		// shared bundle of the global extended ConfigMap, bundle is replaced on every update
+		sharedBundle      *SharedBundle
+		sharedBundleCache *SharedBundle
+		nplStore          NPLStore
 		supplementContextCache
 	}
 
@@ -310,7 +437,12 @@ type (
 
 	// PartitionConfig contains ResourceMap and priority of partition
 	PartitionConfig struct {
-		ResourceMap   ResourceMap
+		ResourceMap ResourceMap
+		// DataGroups, Certificates and Monitors are the objects of the DataGroup, TLSCertificate and
+		// HealthMonitor resources shared by the virtuals of the partition, key is namespace/name of the resource
+		DataGroups    map[string]*InternalDataGroup
+		Certificates  map[string]*SharedCertificate
+		Monitors      map[string]Monitor
 		Priority      *int
 		PriorityMutex sync.RWMutex
 	}
@@ -375,6 +507,10 @@ type (
 		Members        []string  `json:"members"`
 		Monitors       []Monitor `json:"monitors,omitempty"`
 		DataServer     string
+		// seconds the DNS answers of the pool are cached by the resolvers
+		TTL uint32 `json:"ttl,omitempty"`
+		// number of the monitors, or all, that must pass for the pool member to be available
+		MinimumMonitors *intstr.IntOrString `json:"minimumMonitors,omitempty"`
 	}
 
 	ResourceConfigRequest struct {
@@ -383,11 +519,95 @@ type (
 		gtmConfig          GTMConfig
 		defaultRouteDomain int
 		reqId              int
+		// shared bundle of the iRules and profiles installed in /Common/Shared
+		sharedBundle *SharedBundle
 	}
 
 	resourceStatusMeta struct {
 		id            int
 		failedTenants map[string]struct{}
+		// AS3 error messages of the failed tenants
+		tenantErrors map[string]string
+	}
+
+	// finalizerStore holds the deleted resources with the finalizer until the removal
+	// of their BIG-IP configuration is confirmed
+	finalizerStore struct {
+		sync.Mutex
+		resources map[resourceRef]finalizerMeta
+	}
+
+	// quarantineStore holds the resources excluded from the declarations for the AS3 errors,
+	// value is the generation of the resource when it is quarantined
+	quarantineStore struct {
+		sync.Mutex
+		resources map[resourceRef]int64
+	}
+
+	// CapacityParams are the thresholds of the BIG-IP capacity queried periodically
+	CapacityParams struct {
+		// Interval in seconds of querying BIG-IP, capacity is not queried when 0
+		Interval int
+		// Virtual servers supported by BIG-IP and the percentage of the limit raising the event
+		VirtualServerLimit int
+		Threshold          int
+	}
+
+	// tlsFileStore holds the directories of the certificates referred by the TLSProfiles with reference file
+	tlsFileStore struct {
+		sync.Mutex
+		directory string
+		watcher   *fsnotify.Watcher
+		watched   map[string]struct{}
+		// directories with the file events not processed yet
+		pending map[string]struct{}
+	}
+
+	// capacityStore holds the virtual servers managed by CIS and whether the threshold event is raised
+	capacityStore struct {
+		sync.Mutex
+		managedVirtuals int
+		alerted         bool
+	}
+
+	// poolMemberStatsStore holds the members of the pools posted by CIS for the pool member statistics
+	poolMemberStatsStore struct {
+		sync.Mutex
+		pools map[poolStatsKey]map[string]poolMemberMeta
+	}
+
+	poolStatsKey struct {
+		partition string
+		name      string
+	}
+
+	// sharedPoolStore holds the name of the pool shared with the identical pools removed from the
+	// declaration of the partition
+	sharedPoolStore struct {
+		sync.RWMutex
+		pools map[string]map[string]string
+	}
+
+	// poolMemberMeta is the service and pod of the pool member
+	poolMemberMeta struct {
+		namespace string
+		service   string
+		pod       string
+	}
+
+	// nodeEventBatch holds the clusters with the node events pending in the batch interval and
+	// the count of the events suppressed by the batch
+	nodeEventBatch struct {
+		sync.Mutex
+		pending map[string]int
+		// serializes the processing of the node batches
+		process sync.Mutex
+	}
+
+	finalizerMeta struct {
+		partition string
+		// id of the request posting the removal, 0 until the request is enqueued
+		reqId int
 	}
 
 	resourceRef struct {
@@ -398,6 +618,16 @@ type (
 
 	VSSpecProperties struct {
 		PoolWAF bool
+		// VirtualServers discarded for the paths claimed by other VirtualServers
+		PathConflicts []vsPathConflict
+	}
+
+	// vsPathConflict is a path of the VirtualServer host claimed by another VirtualServer
+	vsPathConflict struct {
+		virtual *cisapiv1.VirtualServer
+		owner   *cisapiv1.VirtualServer
+		host    string
+		path    string
 	}
 
 	// Pool config
@@ -410,7 +640,12 @@ type (
 		Balance              string                                  `json:"loadBalancingMethod,omitempty"`
 		Members              []PoolMember                            `json:"members"`
 		NodeMemberLabel      string                                  `json:"-"`
+		NetworkAttachment    string                                  `json:"-"`
+		AddressFamily        string                                  `json:"-"`
+		MemberRatio          string                                  `json:"-"`
+		WarmUpPeriod         int32                                   `json:"-"`
 		MonitorNames         []MonitorName                           `json:"monitors,omitempty"`
+		MinimumMonitors      *intstr.IntOrString                     `json:"minimumMonitors,omitempty"`
 		ReselectTries        int32                                   `json:"reselectTries,omitempty"`
 		ServiceDownAction    string                                  `json:"serviceDownAction,omitempty"`
 		Weight               int32                                   `json:"weight,omitempty"`
@@ -453,6 +688,15 @@ type (
 		Timeout    int    `json:"timeout,omitempty"`
 		TargetPort int32  `json:"targetPort,omitempty"`
 		Path       string `json:"path,omitempty"`
+		// SSL options of the https and http2 monitors
+		Ciphers           string `json:"ciphers,omitempty"`
+		ClientCertificate string `json:"clientCertificate,omitempty"`
+		SNIServerName     string `json:"sniServerName,omitempty"`
+		// seconds the GTM monitor waits for the probe response
+		ProbeTimeout int `json:"probeTimeout,omitempty"`
+		// Client certificate and key read from the clientTLS secret of the https monitor
+		ClientTLSCertificate string `json:"-"`
+		ClientTLSKey         string `json:"-"`
 	}
 	MonitorName struct {
 		Name string `json:"name"`
@@ -471,6 +715,10 @@ type (
 		// key of the map is IPSpec.Key
 		ipamContext              map[string]ficV1.IPSpec
 		processedNativeResources map[resourceRef]struct{}
+		// resources observed when their updates are paused with the pause annotation
+		pausedResources map[resourceRef]interface{}
+		// resources discarded for the virtual server address claimed by another resource, value is the address
+		addressConflicts map[resourceRef]string
 		// stores valid externalClustersConfig from extendendCM
 		externalClustersConfig map[string]ExternalClusterConfig
 	}
@@ -533,6 +781,7 @@ type (
 		Name            string   `json:"name"`
 		Address         bool     `json:"address,omitempty"`
 		CaseInsensitive bool     `json:"caseInsensitive,omitempty"`
+		CaseSensitive   bool     `json:"caseSensitive,omitempty"`
 		Equals          bool     `json:"equals,omitempty"`
 		EndsWith        bool     `json:"endsWith,omitempty"`
 		External        bool     `json:"external,omitempty"`
@@ -635,11 +884,27 @@ type (
 		CAFile        string `json:"caFile,omitempty"`
 		ChainCA       string `json:"chainCA,omitempty"`
 		Certificates  []certificate
+		// Certificates and CA bundle of the TLSCertificate resources
+		SharedCertificates []string `json:"sharedCertificates,omitempty"`
+		SharedCABundle     string   `json:"sharedCABundle,omitempty"`
+		// Certificate of the backends is verified with the CA certificates of the serverssl profile
+		ValidateCertificate bool `json:"validateCertificate,omitempty"`
 	}
 
 	certificate struct {
 		Cert string `json:"cert"`
 		Key  string `json:"key"`
+		// Key stored on BIG-IP in the FIPS partition or the external HSM, used instead of Key
+		BigIPKey   string `json:"bigipKey,omitempty"`
+		Passphrase string `json:"passphrase,omitempty"`
+	}
+
+	// SharedCertificate is a certificate or a CA bundle shared by the TLSProfiles
+	SharedCertificate struct {
+		Name        string
+		Type        string
+		Certificate string
+		PrivateKey  string
 	}
 
 	portStruct struct {
@@ -654,13 +919,22 @@ type (
 
 	requestMeta struct {
 		partitionMap map[string]map[string]string
-		id           int
+		// virtual addresses of the Ingresses in the request
+		ingressAddresses map[string]string
+		// base resources of the AS3 objects in the request, key is partition and AS3 object name
+		as3Objects map[string]map[string]map[string]string
+		// BIG-IP virtual servers of the VirtualServers in the request, key is namespace/name
+		virtualServers map[string][]cisapiv1.BigIPVirtualServer
+		id             int
 	}
 
 	Node struct {
 		Name   string
 		Addr   string
+		OS     string
 		Labels map[string]string
+		// Unschedulable is set for the cordoned nodes and the nodes which are not ready
+		Unschedulable bool
 	}
 	// NPL information from pod annotation
 	NPLAnnotation struct {
@@ -722,6 +996,26 @@ type (
 		disableARP         bool
 		bigIPAS3Version    float64
 		HAMode             bool
+		// as3SchemaVersion pins the schemaVersion of the declarations
+		as3SchemaVersion string
+		// as3Validation validates the declarations with the AS3 schema before posting to BIG-IP
+		as3Validation bool
+		as3SchemaURL  string
+		as3Schema     *gojsonschema.Schema
+		// invalidTenantErrors holds the schema errors of the tenants of the request which are not posted
+		invalidTenantErrors map[string]string
+		// sharePools shares the identical pools of the virtuals of a tenant as a single pool
+		sharePools bool
+		// sharedPools are the pools shared with the identical pools of the last declaration of the tenants
+		sharedPools sharedPoolStore
+		// xcManager posts the configuration to F5 Distributed Cloud instead of AS3
+		xcManager *XCManager
+		// nginxManager updates the servers of the NGINX Plus upstreams instead of AS3
+		nginxManager *NginxManager
+		// lastConfig is the last configuration request, posted again with the full-state sync
+		lastConfig ResourceConfigRequest
+		// auditLog records the declarations posted to BIG-IP, nil when the audit log is not enabled
+		auditLog *auditLogger
 	}
 
 	AgentParams struct {
@@ -742,14 +1036,56 @@ type (
 		StaticRoutingMode  bool
 		SharedStaticRoutes bool
 		MultiClusterMode   string
+		AS3SchemaVersion   string
+		AS3DeclValidation  bool
+		SchemaLocal        string
+		SharePools         bool
+		XCParams           XCParams
+		NginxParams        NginxParams
 	}
 
 	PostManager struct {
 		httpClient        *http.Client
 		tenantResponseMap map[string]tenantResponse
+		// optimistic lock keys of the tenants returned by AS3
+		tenantLockKeys map[string]string
+		// auth token of BIG-IQ when the declarations are posted through BIG-IQ
+		bigIQToken       string
+		bigIQTokenExpiry time.Time
+		bigIQTokenLock   sync.Mutex
 		PostParams
+		// guards AS3PostDelay, LogAS3Request and LogAS3Response of PostParams updated by DeployConfig
+		postParamsLock                  sync.RWMutex
 		PrimaryClusterHealthProbeParams PrimaryClusterHealthProbeParams
 		firstPost                       bool
+		// circuit breaker of the REST calls to BIG-IP
+		circuitBreaker circuitBreaker
+		// context of the REST calls, cancelled when the agent is stopped
+		ctx    context.Context
+		cancel context.CancelFunc
+		// health of restjavad and restnoded serving the REST calls of BIG-IP
+		restFrameworkHealth restFrameworkHealth
+		// responses of the GET requests to BIG-IP cached for BigIPResponseCacheTTL
+		responseCache bigipResponseCache
+	}
+
+	// restFrameworkHealth tracks the consecutive REST calls failed by the REST framework of BIG-IP
+	restFrameworkHealth struct {
+		sync.Mutex
+		failures  int
+		unhealthy bool
+		// remediated is set once the sys db variables of restjavad memory are set on BIG-IP
+		remediated bool
+	}
+
+	// circuitBreaker stops posting the declarations after the consecutive REST call errors
+	// until BIG-IP is reachable again
+	circuitBreaker struct {
+		sync.Mutex
+		failures int
+		open     bool
+		// resync is set when the circuit breaker is closed, all the tenants are posted again
+		resync bool
 	}
 
 	PrimaryClusterHealthProbeParams struct {
@@ -760,6 +1096,21 @@ type (
 		statusChanged bool
 		probeInterval int
 		retryInterval int
+		// lease endpoint of the primary CIS, probed with the kube client of the primary cluster
+		leaseClient       kubernetes.Interface
+		leaseNamespace    string
+		leaseName         string
+		leaseRenewTime    time.Time
+		leaseObservedTime time.Time
+	}
+
+	// primaryClusterLease is the lease renewed by the primary CIS in the primary cluster
+	primaryClusterLease struct {
+		sync.Mutex
+		namespace string
+		name      string
+		holder    string
+		renewing  bool
 	}
 
 	PostParams struct {
@@ -773,6 +1124,33 @@ type (
 		LogAS3Response    bool
 		LogAS3Request     bool
 		HTTPClientMetrics bool
+		// Post the declarations with the optimistic lock keys of the tenants
+		AS3OptimisticLock bool
+		// Post the declarations through BIG-IQ to the BIG-IP
+		BIGIQURL           string
+		BIGIQUsername      string
+		BIGIQPassword      string
+		BIGIQLoginProvider string
+		// Retry policy of the failed declarations, failed tenants are retried without a limit when AS3MaxRetries is 0
+		AS3MaxRetries       int
+		AS3RetryInterval    int
+		AS3RetryMaxInterval int
+		AS3RetryJitter      float64
+		// Consecutive REST call errors opening the circuit breaker, circuit breaker is disabled when 0
+		CircuitBreakerThreshold int
+		CircuitBreakerCooldown  int
+		// Size limit of the AS3 declarations in MB, declaration size is not limited when 0. Tenants of the larger
+		// declarations are posted in separate declarations when AS3SplitTenants is set, not posted otherwise
+		AS3MaxDeclarationSize int
+		AS3SplitTenants       bool
+		// Memory in MB of provision.extramb sys db variable set on BIG-IP when the REST framework is unhealthy,
+		// sys db variables are not changed when 0
+		RESTJavadExtraMB int
+		// TTL in seconds of the cached responses of the GET requests to BIG-IP verifying the referred objects, AS3
+		// info and license, responses are not cached when 0
+		BigIPResponseCacheTTL int
+		// Timeouts, keep-alive and proxy of the http client of the REST calls
+		ClientParams httpclient.Params
 	}
 
 	GTMParams struct {
@@ -785,11 +1163,15 @@ type (
 		agentResponseCode int
 		taskId            string
 		isDeleted         bool
+		// error message of the failed tenant returned by AS3
+		message string
 	}
 
 	tenantParams struct {
 		as3Decl interface{} // to update cachedTenantDeclMap on success
 		tenantResponse
+		// retries of the failed tenant declaration
+		retries int
 	}
 
 	agentConfig struct {
@@ -863,6 +1245,46 @@ type (
 		Egress  *as3ResourcePointer `json:"egress,omitempty"`
 	}
 
+	// as3TrafficLogProfile maps to Traffic_Log_Profile in AS3 Resources
+	as3TrafficLogProfile struct {
+		Class           string                `json:"class,omitempty"`
+		RequestSettings as3TrafficLogSettings `json:"requestSettings"`
+	}
+
+	// as3TrafficLogSettings maps to Traffic_Log_Profile_requestSettings in AS3 Resources
+	as3TrafficLogSettings struct {
+		RequestEnabled  bool                `json:"requestEnabled"`
+		RequestProtocol string              `json:"requestProtocol,omitempty"`
+		RequestPool     *as3ResourcePointer `json:"requestPool,omitempty"`
+		RequestTemplate string              `json:"requestTemplate,omitempty"`
+	}
+
+	// as3HTTPProfile maps to HTTP_Profile in AS3 Resources
+	as3HTTPProfile struct {
+		Class            string `json:"class,omitempty"`
+		XForwardedFor    *bool  `json:"xForwardedFor,omitempty"`
+		MaxHeaderSize    int32  `json:"maxHeaderSize,omitempty"`
+		MaxHeaderCount   int32  `json:"maxHeaderCount,omitempty"`
+		RewriteRedirects string `json:"rewriteRedirects,omitempty"`
+		ProxyType        string `json:"proxyType,omitempty"`
+	}
+
+	// as3L4Profile maps to L4_Profile in AS3 Resources
+	as3L4Profile struct {
+		Class               string `json:"class,omitempty"`
+		IdleTimeout         int32  `json:"idleTimeout,omitempty"`
+		LooseInitialization bool   `json:"looseInitialization,omitempty"`
+		LooseClose          bool   `json:"looseClose,omitempty"`
+	}
+
+	// as3TCPProfile maps to TCP_Profile in AS3 Resources
+	as3TCPProfile struct {
+		Class             string `json:"class,omitempty"`
+		IdleTimeout       int32  `json:"idleTimeout,omitempty"`
+		Nagle             string `json:"nagle,omitempty"`
+		CongestionControl string `json:"congestionControl,omitempty"`
+	}
+
 	as3ProfileHTTP2 struct {
 		Ingress *as3ResourcePointer `json:"ingress,omitempty"`
 		Egress  *as3ResourcePointer `json:"egress,omitempty"`
@@ -932,6 +1354,7 @@ type (
 		LoadBalancingMode string               `json:"loadBalancingMode,omitempty"`
 		Members           []as3PoolMember      `json:"members,omitempty"`
 		Monitors          []as3ResourcePointer `json:"monitors,omitempty"`
+		MinimumMonitors   as3MultiTypeParam    `json:"minimumMonitors,omitempty"`
 		ServiceDownAction string               `json:"serviceDownAction,omitempty"`
 		ReselectTries     int32                `json:"reselectTries,omitempty"`
 	}
@@ -942,6 +1365,9 @@ type (
 		ServerAddresses  []string `json:"serverAddresses,omitempty"`
 		ServicePort      int32    `json:"servicePort,omitempty"`
 		ShareNodes       bool     `json:"shareNodes,omitempty"`
+		PriorityGroup    int      `json:"priorityGroup,omitempty"`
+		Ratio            int      `json:"ratio,omitempty"`
+		AdminState       string   `json:"adminState,omitempty"`
 	}
 
 	// as3ResourcePointer maps to following in AS3 Resources
@@ -961,38 +1387,53 @@ type (
 	// - Service_TCP
 	// - Service_UDP
 	as3Service struct {
-		Layer4                 string               `json:"layer4,omitempty"`
-		Source                 string               `json:"source,omitempty"`
-		TranslateServerAddress bool                 `json:"translateServerAddress,omitempty"`
-		TranslateServerPort    bool                 `json:"translateServerPort,omitempty"`
-		Class                  string               `json:"class,omitempty"`
-		VirtualAddresses       []as3MultiTypeParam  `json:"virtualAddresses,omitempty"`
-		VirtualPort            int                  `json:"virtualPort,omitempty"`
-		AutoLastHop            string               `json:"lastHop,omitempty"`
-		SNAT                   as3MultiTypeParam    `json:"snat,omitempty"`
-		PolicyEndpoint         as3MultiTypeParam    `json:"policyEndpoint,omitempty"`
-		ClientTLS              as3MultiTypeParam    `json:"clientTLS,omitempty"`
-		ServerTLS              as3MultiTypeParam    `json:"serverTLS,omitempty"`
-		IRules                 as3MultiTypeParam    `json:"iRules,omitempty"`
-		Redirect80             *bool                `json:"redirect80,omitempty"`
-		Pool                   *as3ResourcePointer  `json:"pool,omitempty"`
-		WAF                    as3MultiTypeParam    `json:"policyWAF,omitempty"`
-		Firewall               as3MultiTypeParam    `json:"policyFirewallEnforced,omitempty"`
-		LogProfiles            []as3ResourcePointer `json:"securityLogProfiles,omitempty"`
-		ProfileL4              as3MultiTypeParam    `json:"profileL4,omitempty"`
-		AllowVLANs             []as3ResourcePointer `json:"allowVlans,omitempty"`
-		PersistenceMethods     *[]as3MultiTypeParam `json:"persistenceMethods,omitempty"`
-		ProfileTCP             as3MultiTypeParam    `json:"profileTCP,omitempty"`
-		ProfileUDP             as3MultiTypeParam    `json:"profileUDP,omitempty"`
-		ProfileHTTP            as3MultiTypeParam    `json:"profileHTTP,omitempty"`
-		ProfileHTTP2           as3MultiTypeParam    `json:"profileHTTP2,omitempty"`
-		ProfileMultiplex       as3MultiTypeParam    `json:"profileMultiplex,omitempty"`
-		ProfileDOS             as3MultiTypeParam    `json:"profileDOS,omitempty"`
-		ProfileBotDefense      as3MultiTypeParam    `json:"profileBotDefense,omitempty"`
-		HttpMrfRoutingEnabled  bool                 `json:"httpMrfRoutingEnabled,omitempty"`
-		IpIntelligencePolicy   as3MultiTypeParam    `json:"ipIntelligencePolicy,omitempty"`
-		HttpAnalyticsProfile   *as3ResourcePointer  `json:"profileAnalytics,omitempty"`
-		ProfileWebSocket       as3MultiTypeParam    `json:"profileWebSocket,omitempty"`
+		Layer4                  string               `json:"layer4,omitempty"`
+		Source                  string               `json:"source,omitempty"`
+		TranslateServerAddress  bool                 `json:"translateServerAddress,omitempty"`
+		TranslateServerPort     bool                 `json:"translateServerPort,omitempty"`
+		Class                   string               `json:"class,omitempty"`
+		VirtualAddresses        []as3MultiTypeParam  `json:"virtualAddresses,omitempty"`
+		VirtualPort             int                  `json:"virtualPort,omitempty"`
+		AutoLastHop             string               `json:"lastHop,omitempty"`
+		SNAT                    as3MultiTypeParam    `json:"snat,omitempty"`
+		PolicyEndpoint          as3MultiTypeParam    `json:"policyEndpoint,omitempty"`
+		ClientTLS               as3MultiTypeParam    `json:"clientTLS,omitempty"`
+		ServerTLS               as3MultiTypeParam    `json:"serverTLS,omitempty"`
+		IRules                  as3MultiTypeParam    `json:"iRules,omitempty"`
+		Redirect80              *bool                `json:"redirect80,omitempty"`
+		Pool                    *as3ResourcePointer  `json:"pool,omitempty"`
+		WAF                     as3MultiTypeParam    `json:"policyWAF,omitempty"`
+		Firewall                as3MultiTypeParam    `json:"policyFirewallEnforced,omitempty"`
+		LogProfiles             []as3ResourcePointer `json:"securityLogProfiles,omitempty"`
+		ProfileL4               as3MultiTypeParam    `json:"profileL4,omitempty"`
+		AllowVLANs              []as3ResourcePointer `json:"allowVlans,omitempty"`
+		RejectVLANs             []as3ResourcePointer `json:"rejectVlans,omitempty"`
+		PersistenceMethods      *[]as3MultiTypeParam `json:"persistenceMethods,omitempty"`
+		ProfileTCP              as3MultiTypeParam    `json:"profileTCP,omitempty"`
+		ProfileUDP              as3MultiTypeParam    `json:"profileUDP,omitempty"`
+		ProfileHTTP             as3MultiTypeParam    `json:"profileHTTP,omitempty"`
+		ProfileHTTP2            as3MultiTypeParam    `json:"profileHTTP2,omitempty"`
+		ProfileMultiplex        as3MultiTypeParam    `json:"profileMultiplex,omitempty"`
+		ProfileDOS              as3MultiTypeParam    `json:"profileDOS,omitempty"`
+		ProfileBotDefense       as3MultiTypeParam    `json:"profileBotDefense,omitempty"`
+		HttpMrfRoutingEnabled   bool                 `json:"httpMrfRoutingEnabled,omitempty"`
+		IpIntelligencePolicy    as3MultiTypeParam    `json:"ipIntelligencePolicy,omitempty"`
+		HttpAnalyticsProfile    *as3ResourcePointer  `json:"profileAnalytics,omitempty"`
+		TCPAnalyticsProfile     *as3ResourcePointer  `json:"profileAnalyticsTcp,omitempty"`
+		ProfileWebSocket        as3MultiTypeParam    `json:"profileWebSocket,omitempty"`
+		ProfileHTTPCompression  as3MultiTypeParam    `json:"profileHTTPCompression,omitempty"`
+		ProfileTrafficLog       as3MultiTypeParam    `json:"profileTrafficLog,omitempty"`
+		ProfileAccess           *as3ResourcePointer  `json:"profileAccess,omitempty"`
+		PolicyPerRequestAccess  *as3ResourcePointer  `json:"policyPerRequestAccess,omitempty"`
+		ProfileHTTPAcceleration as3MultiTypeParam    `json:"profileHTTPAcceleration,omitempty"`
+		Mirroring               string               `json:"mirroring,omitempty"`
+	}
+
+	// as3Persist maps to Persist in AS3 Resources
+	as3Persist struct {
+		Class             string `json:"class,omitempty"`
+		PersistenceMethod string `json:"persistenceMethod,omitempty"`
+		Mirror            bool   `json:"mirror"`
 	}
 
 	// as3ServiceAddress maps to VirtualAddress in AS3 Resources
@@ -1011,19 +1452,28 @@ type (
 	// - Monitor_HTTP
 	// - Monitor_HTTPS
 	as3Monitor struct {
-		Class             string  `json:"class,omitempty"`
-		Interval          int     `json:"interval,omitempty"`
-		MonitorType       string  `json:"monitorType,omitempty"`
-		TargetAddress     *string `json:"targetAddress,omitempty"`
-		Timeout           int     `json:"timeout,omitempty"`
-		TimeUnitilUp      *int    `json:"timeUntilUp,omitempty"`
-		Adaptive          *bool   `json:"adaptive,omitempty"`
-		Dscp              *int    `json:"dscp,omitempty"`
-		Receive           string  `json:"receive"`
-		Send              string  `json:"send"`
-		TargetPort        int32   `json:"targetPort,omitempty"`
-		ClientCertificate string  `json:"clientCertificate,omitempty"`
-		Ciphers           string  `json:"ciphers,omitempty"`
+		Class             string              `json:"class,omitempty"`
+		Interval          int                 `json:"interval,omitempty"`
+		MonitorType       string              `json:"monitorType,omitempty"`
+		TargetAddress     *string             `json:"targetAddress,omitempty"`
+		Timeout           int                 `json:"timeout,omitempty"`
+		TimeUnitilUp      *int                `json:"timeUntilUp,omitempty"`
+		Adaptive          *bool               `json:"adaptive,omitempty"`
+		Dscp              *int                `json:"dscp,omitempty"`
+		Receive           string              `json:"receive"`
+		Send              string              `json:"send"`
+		TargetPort        int32               `json:"targetPort,omitempty"`
+		ClientCertificate string              `json:"clientCertificate,omitempty"`
+		Ciphers           string              `json:"ciphers,omitempty"`
+		ClientTLS         *as3ResourcePointer `json:"clientTLS,omitempty"`
+	}
+
+	// as3InbandMonitor maps to Monitor of type inband in AS3 Resources
+	as3InbandMonitor struct {
+		Class           string `json:"class,omitempty"`
+		MonitorType     string `json:"monitorType,omitempty"`
+		FailureInterval int    `json:"failureInterval,omitempty"`
+		ResponseTime    int    `json:"responseTime,omitempty"`
 	}
 
 	// as3CABundle maps to CA_Bundle in AS3 Resources
@@ -1038,6 +1488,13 @@ type (
 		Certificate as3MultiTypeParam `json:"certificate,omitempty"`
 		PrivateKey  as3MultiTypeParam `json:"privateKey,omitempty"`
 		ChainCA     as3MultiTypeParam `json:"chainCA,omitempty"`
+		Passphrase  *as3Passphrase    `json:"passphrase,omitempty"`
+	}
+
+	// as3Passphrase maps to Property_Passphrase in AS3 Resources
+	as3Passphrase struct {
+		Ciphertext string `json:"ciphertext"`
+		Protected  string `json:"protected"`
 	}
 
 	// as3TLSServer maps to TLS_Server in AS3 Resources
@@ -1063,6 +1520,8 @@ type (
 		Ciphers             string              `json:"ciphers,omitempty"`
 		CipherGroup         *as3ResourcePointer `json:"cipherGroup,omitempty"`
 		TLS1_3Enabled       bool                `json:"tls1_3Enabled,omitempty"`
+		ClientCertificate   string              `json:"clientCertificate,omitempty"`
+		ServerName          string              `json:"serverName,omitempty"`
 	}
 
 	// as3DataGroup maps to Data_Group in AS3 Resources
@@ -1089,6 +1548,10 @@ type (
 		Port    int32  `json:"port"`
 		SvcPort int32  `json:"svcPort,omitempty"`
 		Session string `json:"session,omitempty"`
+		// PriorityGroup is set only for the pools having members of the backup clusters
+		PriorityGroup int `json:"priorityGroup,omitempty"`
+		// Ratio is set only for the pools with the ratio of the members from the pods
+		Ratio int `json:"ratio,omitempty"`
 	}
 )
 
@@ -1122,6 +1585,9 @@ type (
 		LBModeFallback string               `json:"lbModeFallback"`
 		Members        []as3GSLBPoolMemberA `json:"members"`
 		Monitors       []as3ResourcePointer `json:"monitors"`
+		// MinimumMonitors is a number or all
+		MinimumMonitors as3MultiTypeParam `json:"minimumMonitors,omitempty"`
+		TTL             uint32            `json:"ttl,omitempty"`
 	}
 
 	// as3GSLBPoolMemberA maps to GSLB_Pool_Member_A in AS3 Resources
@@ -1138,6 +1604,9 @@ type (
 		Send     string `json:"send"`
 		Receive  string `json:"receive"`
 		Timeout  int    `json:"timeout"`
+		// probe timeout and SNI of the https monitor
+		ProbeTimeout  int    `json:"probeTimeout,omitempty"`
+		SNIServerName string `json:"sniServerName,omitempty"`
 	}
 
 	// as3GSLBServer maps to GSLB_Server in AS3 Resources
@@ -1178,6 +1647,7 @@ type (
 		caCertificate            string
 		destinationCACertificate string
 		tlsCipher                TLSCipher
+		privateKeys              []cisapiv1.TLSPrivateKey
 	}
 
 	rgPlcSSLProfiles struct {
@@ -1206,6 +1676,8 @@ type (
 		httpTraffic      string
 		poolPathRefs     []poolPathRef
 		bigIPSSLProfiles BigIPSSLProfiles
+		httpRedirect     cisapiv1.HTTPRedirect
+		errorPage        *cisapiv1.ErrorPage
 	}
 )
 
@@ -1217,6 +1689,8 @@ type (
 		HAClusterConfig           HAClusterConfig         `yaml:"highAvailabilityCIS"`
 		HAMode                    HAModeType              `yaml:"mode"`
 		LocalClusterRatio         *int                    `yaml:"localClusterRatio"`
+		BigIPAllowList            *BigIPAllowList         `yaml:"bigipAllowList,omitempty"`
+		SharedBundle              *SharedBundle           `yaml:"sharedBundle,omitempty"`
 	}
 
 	ExtendedRouteGroupConfig struct {
@@ -1227,11 +1701,13 @@ type (
 	}
 
 	ExtendedRouteGroupSpec struct {
-		VServerName        string `yaml:"vserverName"`
-		VServerAddr        string `yaml:"vserverAddr"`
-		AllowOverride      string `yaml:"allowOverride"`
-		Policy             string `yaml:"policyCR,omitempty"`
-		HTTPServerPolicyCR string `yaml:"httpServerPolicyCR,omitempty"`
+		VServerName        string   `yaml:"vserverName"`
+		VServerAddr        string   `yaml:"vserverAddr"`
+		AllowOverride      string   `yaml:"allowOverride"`
+		Policy             string   `yaml:"policyCR,omitempty"`
+		HTTPServerPolicyCR string   `yaml:"httpServerPolicyCR,omitempty"`
+		WAF                string   `yaml:"waf,omitempty"`
+		AllowVlans         []string `yaml:"allowVlans,omitempty"`
 		Meta               Meta
 	}
 
@@ -1285,6 +1761,8 @@ type (
 		ClusterName string `yaml:"clusterName"`
 		Secret      string `yaml:"secret"`
 		Ratio       *int   `yaml:"ratio"`
+		// Services of the backup cluster are added as the lower priority group pool members
+		Backup bool `yaml:"backup"`
 	}
 
 	HAClusterConfig struct {
@@ -1338,3 +1816,152 @@ type (
 		podInformer cache.SharedIndexInformer
 	}
 )
+
+type (
+	// XCParams holds the parameters of the F5 Distributed Cloud tenant
+	XCParams struct {
+		XCAPIURL    string
+		XCAPIToken  string
+		XCNamespace string
+		// site of the origin servers, origin servers are public IPs when empty
+		XCSite string
+	}
+
+	// XCManager manages the F5 Distributed Cloud objects of the CIS configuration
+	XCManager struct {
+		httpClient *http.Client
+		XCParams
+		// owner label of the objects, CIS default partition
+		owner string
+		// xcObjectCache holds the objects created by CIS, key is kind/name and value is the posted object
+		xcObjectCache map[string]string
+		// objects of previous CIS runs are fetched on the first sync
+		cacheSynced bool
+	}
+
+	// xcObject maps to the F5 Distributed Cloud config API object
+	xcObject struct {
+		Metadata xcMetadata  `json:"metadata"`
+		Spec     interface{} `json:"spec"`
+	}
+
+	xcMetadata struct {
+		Name        string            `json:"name"`
+		Namespace   string            `json:"namespace"`
+		Labels      map[string]string `json:"labels,omitempty"`
+		Description string            `json:"description,omitempty"`
+	}
+
+	xcObjectRef struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	}
+
+	// xcOriginPool maps to the spec of origin_pool
+	xcOriginPool struct {
+		OriginServers         []xcOriginServer `json:"origin_servers"`
+		Port                  int32            `json:"port"`
+		LoadBalancerAlgorithm string           `json:"loadbalancer_algorithm"`
+		EndpointSelection     string           `json:"endpoint_selection"`
+		NoTLS                 *struct{}        `json:"no_tls,omitempty"`
+	}
+
+	xcOriginServer struct {
+		PublicIP  *xcOriginServerIP `json:"public_ip,omitempty"`
+		PrivateIP *xcOriginServerIP `json:"private_ip,omitempty"`
+	}
+
+	xcOriginServerIP struct {
+		IP            string         `json:"ip"`
+		SiteLocator   *xcSiteLocator `json:"site_locator,omitempty"`
+		InsideNetwork *struct{}      `json:"inside_network,omitempty"`
+	}
+
+	xcSiteLocator struct {
+		Site xcObjectRef `json:"site"`
+	}
+
+	xcPoolWeight struct {
+		Pool     xcObjectRef `json:"pool"`
+		Weight   int32       `json:"weight"`
+		Priority int32       `json:"priority"`
+	}
+
+	// xcHTTPLoadBalancer maps to the spec of http_loadbalancer
+	xcHTTPLoadBalancer struct {
+		Domains                     []string         `json:"domains"`
+		HTTP                        *xcHTTP          `json:"http,omitempty"`
+		HTTPSAutoCert               *xcHTTPSAutoCert `json:"https_auto_cert,omitempty"`
+		AdvertiseOnPublicDefaultVIP *struct{}        `json:"advertise_on_public_default_vip,omitempty"`
+		DefaultRoutePools           []xcPoolWeight   `json:"default_route_pools,omitempty"`
+		Routes                      []xcRoute        `json:"routes,omitempty"`
+	}
+
+	xcHTTP struct {
+		Port               int  `json:"port"`
+		DNSVolterraManaged bool `json:"dns_volterra_managed"`
+	}
+
+	xcHTTPSAutoCert struct {
+		Port         int  `json:"port"`
+		HTTPRedirect bool `json:"http_redirect"`
+	}
+
+	xcRoute struct {
+		SimpleRoute xcSimpleRoute `json:"simple_route"`
+	}
+
+	xcSimpleRoute struct {
+		HTTPMethod  string          `json:"http_method"`
+		Path        xcPathMatch     `json:"path"`
+		Headers     []xcHeaderMatch `json:"headers,omitempty"`
+		OriginPools []xcPoolWeight  `json:"origin_pools"`
+	}
+
+	xcPathMatch struct {
+		Prefix string `json:"prefix,omitempty"`
+		Path   string `json:"path,omitempty"`
+	}
+
+	xcHeaderMatch struct {
+		Name  string `json:"name"`
+		Exact string `json:"exact,omitempty"`
+		Regex string `json:"regex,omitempty"`
+	}
+
+	// xcTCPLoadBalancer maps to the spec of tcp_loadbalancer
+	xcTCPLoadBalancer struct {
+		Domains                     []string       `json:"domains,omitempty"`
+		ListenPort                  int            `json:"listen_port"`
+		AdvertiseOnPublicDefaultVIP *struct{}      `json:"advertise_on_public_default_vip,omitempty"`
+		OriginPoolsWeights          []xcPoolWeight `json:"origin_pools_weights"`
+	}
+)
+
+type (
+	// NginxParams holds the parameters of the NGINX Plus API
+	NginxParams struct {
+		NginxPlusAPIURL     string
+		NginxPlusAPIVersion int
+	}
+
+	// NginxManager manages the servers of the NGINX Plus upstreams rendered for the CIS pools
+	NginxManager struct {
+		httpClient *http.Client
+		NginxParams
+		// managedUpstreams holds the upstreams updated by CIS, key is http/name or stream/name
+		managedUpstreams map[string]struct{}
+	}
+
+	// nginxUpstream holds the servers of the upstream rendered for the pool
+	nginxUpstream struct {
+		partition string
+		servers   []string
+	}
+
+	// nginxUpstreamServer maps to the server of the upstream in NGINX Plus API
+	nginxUpstreamServer struct {
+		ID     int    `json:"id,omitempty"`
+		Server string `json:"server"`
+	}
+)
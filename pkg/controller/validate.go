@@ -18,6 +18,12 @@ package controller
 
 import (
 	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
 	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -37,7 +43,15 @@ func (ctlr *Controller) checkValidVirtualServer(
 		return false
 	}
 	// Check if the virtual exists and valid for us.
-	_, virtualFound, _ := crInf.vsInformer.GetIndexer().GetByKey(vkey)
+	var virtualFound bool
+	if ingName, ok := getIngressNameForVirtualServer(vsResource); ok {
+		// VirtualServers derived from an Ingress are valid as long as the Ingress exists
+		if crInf.ingInformer != nil {
+			_, virtualFound, _ = crInf.ingInformer.GetIndexer().GetByKey(vsNamespace + "/" + ingName)
+		}
+	} else {
+		_, virtualFound, _ = crInf.vsInformer.GetIndexer().GetByKey(vkey)
+	}
 	if !virtualFound {
 		log.Infof("VirtualServer %s is invalid", vsName)
 		return false
@@ -64,6 +78,33 @@ func (ctlr *Controller) checkValidVirtualServer(
 			return false
 		}
 	}
+	if !ctlr.checkValidVirtualServerPaths(vsResource) {
+		return false
+	}
+	if !checkValidErrorPage(vsResource) {
+		return false
+	}
+	if !checkValidHTTPRedirect(vsResource) {
+		return false
+	}
+	if !checkValidPoolMirrors(vsResource) {
+		return false
+	}
+	if len(vsResource.Spec.AllowVLANs) > 0 && len(vsResource.Spec.RejectVLANs) > 0 {
+		log.Errorf("allowVlans and rejectVlans are mutually exclusive for the virtual server %s", vsName)
+		return false
+	}
+	if !checkValidServiceAddresses(vsResource.Spec.ServiceIPAddress, vsName) {
+		return false
+	}
+	if !checkValidVirtualAddresses(append([]string{bindAddr}, vsResource.Spec.AdditionalVirtualServerAddresses...),
+		vsName) {
+		return false
+	}
+	if vsResource.Spec.Profiles.PolicyPerRequestAccess != "" && vsResource.Spec.Profiles.ProfileAccess == "" {
+		log.Errorf("policyPerRequestAccess requires profileAccess for the virtual server %s", vsName)
+		return false
+	}
 	for _, pool := range vsResource.Spec.Pools {
 		if pool.MultiClusterServices == nil {
 			continue
@@ -82,6 +123,216 @@ func (ctlr *Controller) checkValidVirtualServer(
 	return true
 }
 
+// vsPoolPathRegex is the format of the VirtualServer pool paths other than regex paths
+var vsPoolPathRegex = regexp.MustCompile(`^\/([A-z0-9-_+]+\/)*([-A-z0-9_.:]+\/?)*$`)
+
+// tclRegexUnsupported matches the unescaped constructs of Go regexp which the TCL regexp of BIG-IP doesn't support
+// or evaluates differently, \b and \B are the backspace and the backslash in TCL
+var tclRegexUnsupported = regexp.MustCompile(`(^|[^\\])(\\\\)*(\\[bBzpPQE]|\\x\{|\(\?[A-Za-z<])`)
+
+// checkValidTCLRegex validates the regex matched by the TCL regexp of the regex path iRule
+func checkValidTCLRegex(regex string) error {
+	if _, err := regexp.Compile(regex); err != nil {
+		return err
+	}
+	if match := tclRegexUnsupported.FindStringSubmatch(regex); match != nil {
+		return fmt.Errorf("%v is not supported by the TCL regexp of BIG-IP", match[3])
+	}
+	return nil
+}
+
+// checkValidVirtualServerPaths validates the path and pathType of the VirtualServer pools
+func (ctlr *Controller) checkValidVirtualServerPaths(vsResource *cisapiv1.VirtualServer) bool {
+	_, fromIngress := getIngressNameForVirtualServer(vsResource)
+	for _, pool := range vsResource.Spec.Pools {
+		switch pool.PathType {
+		case "", PathTypePrefix, PathTypeExact:
+			// Ingress paths are validated by the Kubernetes API server
+			if !fromIngress && pool.Path != "" && !vsPoolPathRegex.MatchString(pool.Path) {
+				log.Errorf("Invalid path %v in pool of VirtualServer %s/%s",
+					pool.Path, vsResource.Namespace, vsResource.Name)
+				return false
+			}
+		case PathTypeRegex:
+			if err := checkValidTCLRegex(pool.Path); err != nil {
+				log.Errorf("Invalid regex path %v in pool of VirtualServer %s/%s: %v",
+					pool.Path, vsResource.Namespace, vsResource.Name, err)
+				return false
+			}
+		default:
+			log.Errorf("Invalid pathType %v in pool of VirtualServer %s/%s, supported values are %v, %v and %v",
+				pool.PathType, vsResource.Namespace, vsResource.Name, PathTypePrefix, PathTypeExact, PathTypeRegex)
+			return false
+		}
+		if pool.HostRegex != "" {
+			if strings.ContainsAny(pool.HostRegex, " \t") {
+				log.Errorf("Invalid hostRegex %v in pool of VirtualServer %s/%s: whitespace is not allowed",
+					pool.HostRegex, vsResource.Namespace, vsResource.Name)
+				return false
+			}
+			if err := checkValidTCLRegex(pool.HostRegex); err != nil {
+				log.Errorf("Invalid hostRegex %v in pool of VirtualServer %s/%s: %v",
+					pool.HostRegex, vsResource.Namespace, vsResource.Name, err)
+				return false
+			}
+		}
+		if pool.PathRegex != "" {
+			if err := checkValidTCLRegex(pool.PathRegex); err != nil {
+				log.Errorf("Invalid pathRegex %v in pool of VirtualServer %s/%s: %v",
+					pool.PathRegex, vsResource.Namespace, vsResource.Name, err)
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// vsErrorPageContentType is the format of the VirtualServer error page content type
+var vsErrorPageContentType = regexp.MustCompile(`^[\w.+-]+/[\w.+-]+(\s*;\s*[\w.+-]+=[\w.+-]+)*$`)
+
+// checkValidErrorPage validates the status code and content type of the VirtualServer error page
+func checkValidErrorPage(vsResource *cisapiv1.VirtualServer) bool {
+	errorPage := vsResource.Spec.ErrorPage
+	if errorPage == nil {
+		return true
+	}
+	if errorPage.StatusCode < 100 || errorPage.StatusCode > 599 {
+		log.Errorf("Invalid statusCode %v in errorPage of VirtualServer %s/%s",
+			errorPage.StatusCode, vsResource.Namespace, vsResource.Name)
+		return false
+	}
+	if errorPage.ContentType != "" && !vsErrorPageContentType.MatchString(errorPage.ContentType) {
+		log.Errorf("Invalid contentType %v in errorPage of VirtualServer %s/%s",
+			errorPage.ContentType, vsResource.Namespace, vsResource.Name)
+		return false
+	}
+	return true
+}
+
+// checkValidHTTPRedirect validates the status code and excluded paths of the VirtualServer HTTP redirect
+func checkValidHTTPRedirect(vsResource *cisapiv1.VirtualServer) bool {
+	httpRedirect := vsResource.Spec.HTTPRedirect
+	switch httpRedirect.StatusCode {
+	case 0, http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		log.Errorf("Invalid statusCode %v in httpRedirect of VirtualServer %s/%s, supported values are 301, 302, 303, 307 and 308",
+			httpRedirect.StatusCode, vsResource.Namespace, vsResource.Name)
+		return false
+	}
+	for _, path := range httpRedirect.ExcludePaths {
+		if !vsPoolPathRegex.MatchString(path) {
+			log.Errorf("Invalid excludePath %v in httpRedirect of VirtualServer %s/%s",
+				path, vsResource.Namespace, vsResource.Name)
+			return false
+		}
+	}
+	return true
+}
+
+// checkValidPoolMirrors validates the service and percentage of the VirtualServer pool mirrors
+func checkValidPoolMirrors(vsResource *cisapiv1.VirtualServer) bool {
+	for _, pool := range vsResource.Spec.Pools {
+		if pool.Mirror == nil {
+			continue
+		}
+		if pool.Mirror.Service == "" {
+			log.Errorf("Missing service in mirror of pool %v in VirtualServer %s/%s",
+				pool.Path, vsResource.Namespace, vsResource.Name)
+			return false
+		}
+		if pool.Mirror.Percentage < 0 || pool.Mirror.Percentage > 100 {
+			log.Errorf("Invalid percentage %v in mirror of pool %v in VirtualServer %s/%s",
+				pool.Mirror.Percentage, pool.Path, vsResource.Namespace, vsResource.Name)
+			return false
+		}
+	}
+	return true
+}
+
+// checkValidServiceAddresses validates that a virtual address has only one service address
+func checkValidServiceAddresses(serviceAddresses []cisapiv1.ServiceAddress, vsName string) bool {
+	addresses := make(map[string]struct{})
+	for _, sa := range serviceAddresses {
+		if _, ok := addresses[sa.VirtualAddress]; ok {
+			if sa.VirtualAddress == "" {
+				log.Errorf("Multiple serviceAddress without virtualAddress found for %s", vsName)
+			} else {
+				log.Errorf("Multiple serviceAddress found for virtualAddress %s of %s", sa.VirtualAddress, vsName)
+			}
+			return false
+		}
+		addresses[sa.VirtualAddress] = struct{}{}
+	}
+	return true
+}
+
+// checkValidVirtualAddresses validates the IPv4 and IPv6 virtual addresses of the resource, the addresses allocated
+// by IPAM are not validated
+func checkValidVirtualAddresses(addresses []string, rscName string) bool {
+	unique := make(map[string]struct{})
+	for _, address := range addresses {
+		if address == "" {
+			continue
+		}
+		if err := validateVirtualAddress(address); err != nil {
+			log.Errorf("Invalid virtual address %s of %s: %v", address, rscName, err)
+			return false
+		}
+		if _, ok := unique[address]; ok {
+			log.Errorf("Multiple virtual address %s found for %s", address, rscName)
+			return false
+		}
+		unique[address] = struct{}{}
+	}
+	return true
+}
+
+// validateVirtualAddress validates the virtual address of the form <ipv4_or_ipv6>[%<routeDomainID>][/<mask>],
+// mask defaults to the host mask of the address family and the address of the network mask is the network address
+func validateVirtualAddress(address string) error {
+	addr, mask := splitVirtualAddressMask(address)
+	ip, rd := split_ip_with_route_domain(addr)
+	if rd != "" {
+		if id, _ := strconv.Atoi(rd); id > 65534 {
+			return fmt.Errorf("route domain %s is out of range", rd)
+		}
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return fmt.Errorf("%s is not an IPv4 or IPv6 address", ip)
+	}
+	bits := net.IPv6len * 8
+	if !strings.Contains(ip, ":") {
+		bits = net.IPv4len * 8
+		parsed = parsed.To4()
+	}
+	if mask == "" {
+		return nil
+	}
+	ones, err := strconv.Atoi(mask)
+	if err != nil || ones < 0 || ones > bits {
+		return fmt.Errorf("mask %s is not in the range 0-%d", mask, bits)
+	}
+	if !parsed.Equal(parsed.Mask(net.CIDRMask(ones, bits))) {
+		return fmt.Errorf("%s is not the network address of the mask /%d", ip, ones)
+	}
+	return nil
+}
+
+// validateRequestLogging validates the port and protocol of the remote syslog servers of the request logging
+func validateRequestLogging(logging cisapiv1.RequestLogging) error {
+	if logging.Port < 1 || logging.Port > 65535 {
+		return fmt.Errorf("port %d is not in the range 1-65535", logging.Port)
+	}
+	switch logging.Protocol {
+	case "", "tcp", "udp":
+	default:
+		return fmt.Errorf("protocol %s is not supported, supported values are tcp and udp", logging.Protocol)
+	}
+	return nil
+}
+
 func (ctlr *Controller) checkValidTransportServer(
 	tsResource *cisapiv1.TransportServer,
 ) bool {
@@ -119,12 +370,62 @@ func (ctlr *Controller) checkValidTransportServer(
 		}
 	}
 
+	if len(tsResource.Spec.AllowVLANs) > 0 && len(tsResource.Spec.RejectVLANs) > 0 {
+		log.Errorf("allowVlans and rejectVlans are mutually exclusive for the transport server %s", vsName)
+		return false
+	}
+	if !checkValidServiceAddresses(tsResource.Spec.ServiceIPAddress, vsName) {
+		return false
+	}
+	if !checkValidVirtualAddresses(append([]string{bindAddr}, tsResource.Spec.AdditionalVirtualServerAddresses...),
+		vsName) {
+		return false
+	}
+	if tsResource.Spec.FastL4 != (cisapiv1.FastL4{}) {
+		if tsResource.Spec.Mode != "performance" {
+			log.Errorf("fastL4 is supported only in performance mode for the transport server %s", vsName)
+			return false
+		}
+		if tsResource.Spec.ProfileL4 != "" {
+			log.Errorf("profileL4 and fastL4 are mutually exclusive for the transport server %s", vsName)
+			return false
+		}
+		// idle timeout of AS3 is between 1 and 86400 seconds, -1 is the infinite timeout
+		if idleTimeout := tsResource.Spec.FastL4.IdleTimeout; idleTimeout < -1 || idleTimeout > 86400 {
+			log.Errorf("Invalid fastL4 idleTimeout %v for the transport server %s, it should be between 1 and "+
+				"86400 or -1", idleTimeout, vsName)
+			return false
+		}
+	}
+
 	if tsResource.Spec.Type == "" {
 		tsResource.Spec.Type = "tcp"
 	} else if !(tsResource.Spec.Type == "udp" || tsResource.Spec.Type == "tcp" || tsResource.Spec.Type == "sctp") {
 		log.Errorf("Invalid type value for transport server %s. Supported values are tcp, udp and sctp only", vsName)
 		return false
 	}
+	if tsResource.Spec.ProxyProtocol != "" && tsResource.Spec.ProxyProtocol != ProxyProtocolDisabled &&
+		(tsResource.Spec.Mode != "standard" || tsResource.Spec.Type != "tcp") {
+		// PROXY protocol header is sent on the TCP connection of the pool member, which is not proxied in performance mode
+		log.Errorf("proxyProtocol is supported only in standard mode with tcp type for the transport server %s", vsName)
+		return false
+	}
+	if len(tsResource.Spec.SNIRoutes) > 0 {
+		// server name is read from the TCP payload of the ClientHello, which is not collected in performance mode
+		if tsResource.Spec.Mode != "standard" || tsResource.Spec.Type != "tcp" {
+			log.Errorf("sniRoutes are supported only in standard mode with tcp type for the transport server %s", vsName)
+			return false
+		}
+		hosts := make(map[string]struct{})
+		for _, route := range tsResource.Spec.SNIRoutes {
+			host := strings.ToLower(route.Host)
+			if _, ok := hosts[host]; ok {
+				log.Errorf("Duplicate host %s in sniRoutes of the transport server %s", route.Host, vsName)
+				return false
+			}
+			hosts[host] = struct{}{}
+		}
+	}
 	if tsResource.Spec.Pool.MultiClusterServices != nil {
 		for _, mcs := range tsResource.Spec.Pool.MultiClusterServices {
 			if !ctlr.checkValidExtendedService(mcs) {
@@ -173,6 +474,75 @@ func (ctlr *Controller) checkValidIngressLink(
 			return false
 		}
 	}
+	if !checkValidVirtualAddresses(append([]string{bindAddr}, il.Spec.AdditionalVirtualServerAddresses...), ilName) {
+		return false
+	}
+	return ctlr.checkIngressLinkBigIPReferences(il)
+}
+
+// checkValidDataGroup checks whether the records of the DataGroup match its type
+func (ctlr *Controller) checkValidDataGroup(dg *cisapiv1.DataGroup) bool {
+	dgKey := fmt.Sprintf("%s/%s", dg.Namespace, dg.Name)
+	if len(dg.Spec.Records) == 0 {
+		log.Errorf("No records were specified for the DataGroup %s", dgKey)
+		return false
+	}
+	for key := range dg.Spec.Records {
+		switch dg.Spec.Type {
+		case "string":
+		case "integer":
+			if _, err := strconv.Atoi(key); err != nil {
+				log.Errorf("Invalid record %v in the integer DataGroup %s", key, dgKey)
+				return false
+			}
+		case "ip":
+			if _, _, err := net.ParseCIDR(key); err != nil && net.ParseIP(key) == nil {
+				log.Errorf("Invalid record %v in the ip DataGroup %s", key, dgKey)
+				return false
+			}
+		default:
+			log.Errorf("Invalid type %v for the DataGroup %s, supported types are string, ip and integer",
+				dg.Spec.Type, dgKey)
+			return false
+		}
+	}
+	return true
+}
+
+// checkValidHealthMonitor checks the type, send string and client certificate of the HealthMonitor
+func (ctlr *Controller) checkValidHealthMonitor(hm *cisapiv1.HealthMonitor) bool {
+	hmKey := fmt.Sprintf("%s/%s", hm.Namespace, hm.Name)
+	switch hm.Spec.Type {
+	case HTTP, HTTPS, GRPCMonitor:
+		if hm.Spec.Send == "" {
+			log.Errorf("missing send string for the HealthMonitor %s", hmKey)
+			return false
+		}
+	case "tcp", "udp":
+	default:
+		log.Errorf("Invalid type %v for the HealthMonitor %s, supported types are http, https, tcp, udp and grpc",
+			hm.Spec.Type, hmKey)
+		return false
+	}
+	if hm.Spec.Ciphers != "" || hm.Spec.ClientCertificate != "" {
+		if hm.Spec.Type != HTTPS && hm.Spec.Type != GRPCMonitor {
+			log.Errorf("SSL options are supported only for the https and grpc HealthMonitor %s", hmKey)
+			return false
+		}
+	}
+	if hm.Spec.ClientCertificate != "" {
+		cert := ctlr.getTLSCertificate(hm.Namespace, hm.Spec.ClientCertificate)
+		if cert == nil || getTLSCertificateType(cert) != CertificateType {
+			log.Errorf("TLSCertificate %v of type %v not found for the HealthMonitor %s",
+				hm.Spec.ClientCertificate, CertificateType, hmKey)
+			return false
+		}
+		if ctlr.getCRPartition(cert.Spec.Partition) != ctlr.getCRPartition(hm.Spec.Partition) {
+			log.Errorf("TLSCertificate %v is not in the partition of the HealthMonitor %s",
+				hm.Spec.ClientCertificate, hmKey)
+			return false
+		}
+	}
 	return true
 }
 
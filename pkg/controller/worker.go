@@ -37,13 +37,23 @@ import (
 	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
 	routeapi "github.com/openshift/api/route/v1"
 	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 )
 
 const nginxMonitorPort int32 = 8081
 
+// autoGeneratedWideIPUIDPrefix identifies the WideIPs generated from VirtualServer annotations
+const autoGeneratedWideIPUIDPrefix = "virtualserver/"
+
+// VSConditionPathConflict is the VirtualServer status condition set when a path of its host
+// is already claimed by another VirtualServer
+const VSConditionPathConflict = "PathConflict"
+
 const (
 	NotEnabled = iota
 	InvalidInput
@@ -68,6 +78,11 @@ func (ctlr *Controller) nextGenResourceWorker() {
 		go ctlr.probePrimaryClusterHealthStatus()
 	}
 
+	// when CIS is running in the multi-cluster mode then enable health probe on the clusters for the ratio mode
+	if ctlr.multiClusterMode != "" {
+		go ctlr.probeClusterHealthStatus()
+	}
+
 	// process static routes after extended configMap is processed, so as to support external cluster static routes during cis init
 	if ctlr.StaticRoutingMode {
 		clusterNodes := ctlr.getNodesFromAllClusters()
@@ -119,7 +134,11 @@ func (ctlr *Controller) setInitialResourceCount() {
 			if err != nil {
 				continue
 			}
-			rscCount += len(routes)
+			for _, obj := range routes {
+				if ctlr.isManagedResourceClass(obj.(*routeapi.Route).Annotations) {
+					rscCount++
+				}
+			}
 		default:
 			crInf, found := ctlr.getNamespacedCRInformer(ns)
 			if !found {
@@ -129,17 +148,35 @@ func (ctlr *Controller) setInitialResourceCount() {
 			if err != nil {
 				continue
 			}
-			rscCount += len(vs)
+			for _, obj := range vs {
+				if ctlr.isManagedResourceClass(obj.(*cisapiv1.VirtualServer).Annotations) {
+					rscCount++
+				}
+			}
 			ts, err := crInf.tsInformer.GetIndexer().ByIndex("namespace", ns)
 			if err != nil {
 				continue
 			}
-			rscCount += len(ts)
+			for _, obj := range ts {
+				if ctlr.isManagedResourceClass(obj.(*cisapiv1.TransportServer).Annotations) {
+					rscCount++
+				}
+			}
 			il, err := crInf.ilInformer.GetIndexer().ByIndex("namespace", ns)
 			if err != nil {
 				continue
 			}
 			rscCount += len(il)
+			if crInf.ingInformer != nil {
+				ing, err := crInf.ingInformer.GetIndexer().ByIndex("namespace", ns)
+				if err == nil {
+					for _, obj := range ing {
+						if ctlr.isManagedIngress(obj.(*netv1.Ingress)) {
+							rscCount++
+						}
+					}
+				}
+			}
 			if comInf, ok := ctlr.comInformers[ns]; ok {
 				edns, err := comInf.ednsInformer.GetIndexer().ByIndex("namespace", ns)
 				if err != nil {
@@ -198,7 +235,7 @@ func (ctlr *Controller) processResources() bool {
 	// During Init time, just process all the resources
 	if ctlr.initState && rKey.kind != Namespace {
 		if rKey.kind == VirtualServer || rKey.kind == TransportServer || rKey.kind == Service ||
-			rKey.kind == IngressLink || rKey.kind == Route || rKey.kind == ExternalDNS {
+			rKey.kind == IngressLink || rKey.kind == Route || rKey.kind == ExternalDNS || rKey.kind == Ingress {
 			if rKey.kind == Service {
 				if svc, ok := rKey.rsc.(*v1.Service); ok {
 					if svc.Spec.Type == v1.ServiceTypeLoadBalancer {
@@ -246,6 +283,7 @@ func (ctlr *Controller) processResources() bool {
 
 		if rscDelete {
 			delete(ctlr.resources.processedNativeResources, resourceKey)
+			delete(ctlr.resources.pausedResources, resourceKey)
 			// Delete the route entry from hostPath Map
 			ctlr.deleteHostPathMapEntry(route)
 		}
@@ -295,6 +333,10 @@ func (ctlr *Controller) processResources() bool {
 			}
 		}
 
+		if rscDelete {
+			delete(ctlr.resources.pausedResources, rscRefKey)
+		}
+
 		if rKey.event != Create && ctlr.multiClusterMode != "" {
 			// update the poolMem cache, clusterSvcResource & resource-svc maps
 			ctlr.deleteResourceExternalClusterSvcRouteReference(rscRefKey)
@@ -306,9 +348,40 @@ func (ctlr *Controller) processResources() bool {
 			utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
 			isRetryableError = true
 		}
+		if rscDelete {
+			ctlr.recordResourceFinalizer(rscRefKey, virtual.ObjectMeta, ctlr.getCRPartition(virtual.Spec.Partition))
+		} else {
+			ctlr.addResourceFinalizer(rscRefKey, virtual.ObjectMeta)
+		}
 		if rKey.event != Create && ctlr.multiClusterMode != "" {
 			ctlr.deleteUnrefereedMultiClusterInformers()
 		}
+	case Ingress:
+		if ctlr.mode == OpenShiftMode || ctlr.mode == KubernetesMode {
+			break
+		}
+		ing := rKey.rsc.(*netv1.Ingress)
+		rscRefKey := resourceRef{
+			kind:      Ingress,
+			name:      ing.Name,
+			namespace: ing.Namespace,
+		}
+		if _, ok := ctlr.resources.processedNativeResources[rscRefKey]; ok {
+			if rKey.event == Create {
+				break
+			}
+			if rKey.event == Delete {
+				delete(ctlr.resources.processedNativeResources, rscRefKey)
+			}
+		}
+		for _, virtual := range ctlr.getVirtualServersForIngress(ing) {
+			err := ctlr.processVirtualServers(virtual, rscDelete)
+			if err != nil {
+				// TODO
+				utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
+				isRetryableError = true
+			}
+		}
 	case TLSProfile:
 		if ctlr.mode == OpenShiftMode || ctlr.mode == KubernetesMode {
 			break
@@ -361,6 +434,41 @@ func (ctlr *Controller) processResources() bool {
 					}
 				}
 			}
+			for _, virtual := range ctlr.getIngressVirtualServersForSecret(secret) {
+				err := ctlr.processVirtualServers(virtual, false)
+				if err != nil {
+					// TODO
+					utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
+					isRetryableError = true
+				}
+			}
+			for _, virtual := range ctlr.getVirtualServersForMaintenanceSecret(secret) {
+				err := ctlr.processVirtualServers(virtual, false)
+				if err != nil {
+					// TODO
+					utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
+					isRetryableError = true
+				}
+			}
+			for _, cert := range ctlr.getTLSCertificatesForSecret(secret) {
+				ctlr.processTLSCertificate(cert, false)
+			}
+			for _, virtual := range ctlr.getVirtualServersForMonitorSecret(secret) {
+				err := ctlr.processVirtualServers(virtual, false)
+				if err != nil {
+					// TODO
+					utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
+					isRetryableError = true
+				}
+			}
+			for _, virtual := range ctlr.getTransportServersForMonitorSecret(secret) {
+				err := ctlr.processTransportServers(virtual, false)
+				if err != nil {
+					// TODO
+					utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
+					isRetryableError = true
+				}
+			}
 		}
 
 	case TransportServer:
@@ -383,6 +491,9 @@ func (ctlr *Controller) processResources() bool {
 				delete(ctlr.resources.processedNativeResources, rscRefKey)
 			}
 		}
+		if rscDelete {
+			delete(ctlr.resources.pausedResources, rscRefKey)
+		}
 		if rKey.event != Create && ctlr.multiClusterMode != "" {
 			// update the poolMem cache, clusterSvcResource & resource-svc maps
 			ctlr.deleteResourceExternalClusterSvcRouteReference(rscRefKey)
@@ -393,6 +504,11 @@ func (ctlr *Controller) processResources() bool {
 			utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
 			isRetryableError = true
 		}
+		if rscDelete {
+			ctlr.recordResourceFinalizer(rscRefKey, virtual.ObjectMeta, ctlr.getCRPartition(virtual.Spec.Partition))
+		} else {
+			ctlr.addResourceFinalizer(rscRefKey, virtual.ObjectMeta)
+		}
 		if rKey.event != Create && ctlr.multiClusterMode != "" {
 			ctlr.deleteUnrefereedMultiClusterInformers()
 		}
@@ -415,6 +531,88 @@ func (ctlr *Controller) processResources() bool {
 		}
 		edns := rKey.rsc.(*cisapiv1.ExternalDNS)
 		ctlr.processExternalDNS(edns, rscDelete)
+		rscRefKey := resourceRef{
+			kind:      ExternalDNS,
+			name:      edns.Name,
+			namespace: edns.Namespace,
+		}
+		if rscDelete {
+			ctlr.recordResourceFinalizer(rscRefKey, edns.ObjectMeta, DEFAULT_GTM_PARTITION)
+		} else {
+			ctlr.addResourceFinalizer(rscRefKey, edns.ObjectMeta)
+		}
+	case DataGroup:
+		if ctlr.mode == OpenShiftMode || ctlr.mode == KubernetesMode {
+			break
+		}
+		dg := rKey.rsc.(*cisapiv1.DataGroup)
+		ctlr.processDataGroup(dg, rscDelete)
+	case TLSCertificate:
+		if ctlr.mode == OpenShiftMode || ctlr.mode == KubernetesMode {
+			break
+		}
+		cert := rKey.rsc.(*cisapiv1.TLSCertificate)
+		ctlr.processTLSCertificate(cert, rscDelete)
+		// HealthMonitors using the TLSCertificate as client certificate are validated again
+		for _, hm := range ctlr.getHealthMonitorsForTLSCertificate(cert) {
+			ctlr.processHealthMonitor(hm, false)
+		}
+		// Virtuals referring the TLSCertificate through the TLSProfiles are processed again
+		for _, tlsProfile := range ctlr.getTLSProfilesForTLSCertificate(cert) {
+			for _, virtual := range ctlr.getVirtualsForTLSProfile(tlsProfile) {
+				err := ctlr.processVirtualServers(virtual, false)
+				if err != nil {
+					// TODO
+					utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
+					isRetryableError = true
+				}
+			}
+		}
+	case ServiceReferenceGrant:
+		if ctlr.mode == OpenShiftMode || ctlr.mode == KubernetesMode {
+			break
+		}
+		grant := rKey.rsc.(*cisapiv1.ServiceReferenceGrant)
+		// VirtualServers referring the services of the namespace are processed again with the grants
+		for _, virtual := range ctlr.getVirtualServersForServiceReferenceGrant(grant) {
+			err := ctlr.processVirtualServers(virtual, false)
+			if err != nil {
+				// TODO
+				utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
+				isRetryableError = true
+			}
+		}
+		for _, virtual := range ctlr.getTransportServersForServiceReferenceGrant(grant) {
+			err := ctlr.processTransportServers(virtual, false)
+			if err != nil {
+				// TODO
+				utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
+				isRetryableError = true
+			}
+		}
+	case HealthMonitor:
+		if ctlr.mode == OpenShiftMode || ctlr.mode == KubernetesMode {
+			break
+		}
+		hm := rKey.rsc.(*cisapiv1.HealthMonitor)
+		ctlr.processHealthMonitor(hm, rscDelete)
+		// Pools of the virtuals refer the monitor only while the HealthMonitor is valid
+		for _, virtual := range ctlr.getVirtualServersForHealthMonitor(hm) {
+			err := ctlr.processVirtualServers(virtual, false)
+			if err != nil {
+				// TODO
+				utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
+				isRetryableError = true
+			}
+		}
+		for _, virtual := range ctlr.getTransportServersForHealthMonitor(hm) {
+			err := ctlr.processTransportServers(virtual, false)
+			if err != nil {
+				// TODO
+				utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
+				isRetryableError = true
+			}
+		}
 	case IPAM:
 		ipam := rKey.rsc.(*ficV1.IPAM)
 		_ = ctlr.processIPAM(ipam)
@@ -476,16 +674,31 @@ func (ctlr *Controller) processResources() bool {
 			}
 		}
 
+		// Process the VirtualServers of the domain served by the ACME HTTP-01 solver
+		if ctlr.enableACMESolver && isACMESolverService(svc) && rKey.clusterName == "" {
+			for _, virtual := range ctlr.getVirtualServersForACMESolver(svc) {
+				if err := ctlr.processVirtualServers(virtual, false); err != nil {
+					// TODO
+					utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
+					isRetryableError = true
+				}
+			}
+		}
+
 		// Don't process the service as it's not used by any resource
 		if _, ok := ctlr.resources.poolMemCache[svcKey]; !ok {
 			log.Debugf("Skipping service '%v' as it's not used by any CIS monitored resource", svcKey)
 			break
 		}
 
+		// Named servicePorts of the resources are resolved using the service ports,
+		// so the resources are reprocessed when the service ports are renamed or updated
+		svcPortUpdated := !reflect.DeepEqual(ctlr.resources.poolMemCache[svcKey].portSpec, svc.Spec.Ports)
+
 		_ = ctlr.processService(svc, rKey.clusterName)
 
 		// Update the poolMembers for affected resources
-		ctlr.updatePoolMembersForService(svcKey)
+		ctlr.updatePoolMembersForService(svcKey, svcPortUpdated)
 
 	case Endpoints:
 		ep := rKey.rsc.(*v1.Endpoints)
@@ -515,7 +728,7 @@ func (ctlr *Controller) processResources() bool {
 			}
 		}
 		// Just update the endpoints instead of processing them entirely
-		ctlr.updatePoolMembersForService(svcKey)
+		ctlr.updatePoolMembersForService(svcKey, false)
 
 	case Pod:
 		pod := rKey.rsc.(*v1.Pod)
@@ -545,7 +758,7 @@ func (ctlr *Controller) processResources() bool {
 			break
 		}
 		// Update the poolMembers for affected resources
-		ctlr.updatePoolMembersForService(svcKey)
+		ctlr.updatePoolMembersForService(svcKey, false)
 
 	case Namespace:
 		ns := rKey.rsc.(*v1.Namespace)
@@ -620,11 +833,28 @@ func (ctlr *Controller) processResources() bool {
 		}
 	case HACIS:
 		log.Debugf("posting declaration on primary cluster down event")
+	case ClusterHealth:
+		ctlr.processClusterHealthStatus()
 	case NodeUpdate:
 		log.Debugf("posting declaration on node update")
+	case BigIPObjectUpdate:
+		if ctlr.mode == OpenShiftMode || ctlr.mode == KubernetesMode {
+			break
+		}
+		// VirtualServers referring the LTM objects created or deleted on BIG-IP are processed again
+		for _, virtual := range ctlr.getVirtualServersForBigIPObjects(rKey.rsc.([]bigipObjectRef)) {
+			err := ctlr.processVirtualServers(virtual, false)
+			if err != nil {
+				// TODO
+				utilruntime.HandleError(fmt.Errorf("Sync %v failed with %v", key, err))
+				isRetryableError = true
+			}
+		}
 	default:
 		log.Errorf("Unknown resource Kind: %v", rKey.kind)
 	}
+	// resources discarded for the address conflicts are processed once the address is released
+	ctlr.enqueueReleasedAddressConflicts()
 
 	if isRetryableError {
 		ctlr.resourceQueue.AddRateLimited(key)
@@ -640,10 +870,11 @@ func (ctlr *Controller) processResources() bool {
 	if (ctlr.resourceQueue.Len() == 0 && ctlr.resources.isConfigUpdated()) ||
 		(ctlr.multiClusterMode == SecondaryCIS && rKey.kind == HACIS) {
 		config := ResourceConfigRequest{
-			ltmConfig:          ctlr.resources.getLTMConfigDeepCopy(),
+			ltmConfig:          ctlr.resources.getLTMConfigSnapshot(),
 			shareNodes:         ctlr.shareNodes,
 			gtmConfig:          ctlr.resources.getGTMConfigCopy(),
 			defaultRouteDomain: ctlr.defaultRouteDomain,
+			sharedBundle:       ctlr.resources.sharedBundle,
 		}
 
 		if ctlr.multiClusterMode != "" {
@@ -662,6 +893,9 @@ func (ctlr *Controller) processResources() bool {
 		ctlr.Agent.PostConfig(config)
 		ctlr.initState = false
 		ctlr.resources.updateCaches()
+	} else if ctlr.resourceQueue.Len() == 0 {
+		// deleted resources did not change the configuration, so there is nothing to remove from BIG-IP
+		ctlr.removeResourceFinalizers(0, nil)
 	}
 	return true
 }
@@ -732,6 +966,14 @@ func (ctlr *Controller) getVirtualsForTLSProfile(tls *cisapiv1.TLSProfile) []*ci
 
 	// find VirtualServers that reference the TLSProfile
 	virtualsForTLSProfile := getVirtualServersForTLSProfile(allVirtuals, tls)
+	// VirtualServers without TLSProfile use the default TLSProfile of the namespace
+	if isDefaultTLSProfile(tls) {
+		for _, vs := range allVirtuals {
+			if usesDefaultTLSProfile(vs) {
+				virtualsForTLSProfile = append(virtualsForTLSProfile, vs)
+			}
+		}
+	}
 	if nil == virtualsForTLSProfile {
 		log.Infof("Change in TLSProfile %s does not effect any VirtualServer",
 			tls.ObjectMeta.Name)
@@ -741,12 +983,20 @@ func (ctlr *Controller) getVirtualsForTLSProfile(tls *cisapiv1.TLSProfile) []*ci
 }
 
 func (ctlr *Controller) getVirtualsForCustomPolicy(plc *cisapiv1.Policy) []*cisapiv1.VirtualServer {
+	// default policy is merged into the policy of all the VirtualServers
+	if ctlr.isDefaultPolicy(plc) {
+		return ctlr.getAllVSFromMonitoredNamespaces()
+	}
 	nsVirtuals := ctlr.getAllVirtualServers(plc.Namespace)
 	if nil == nsVirtuals {
 		log.Infof("No VirtualServers found in namespace %s",
 			plc.Namespace)
 		return nil
 	}
+	// namespace policy is merged into the policy of all the VirtualServers of the namespace
+	if isNamespacePolicy(plc) {
+		return nsVirtuals
+	}
 
 	var plcVSs []*cisapiv1.VirtualServer
 	var plcVSNames []string
@@ -764,12 +1014,18 @@ func (ctlr *Controller) getVirtualsForCustomPolicy(plc *cisapiv1.Policy) []*cisa
 }
 
 func (ctlr *Controller) getTransportServersForCustomPolicy(plc *cisapiv1.Policy) []*cisapiv1.TransportServer {
+	if ctlr.isDefaultPolicy(plc) {
+		return ctlr.getAllTSFromMonitoredNamespaces()
+	}
 	nsVirtuals := ctlr.getAllTransportServers(plc.Namespace)
 	if nil == nsVirtuals {
 		log.Infof("No VirtualServers found in namespace %s",
 			plc.Namespace)
 		return nil
 	}
+	if isNamespacePolicy(plc) {
+		return nsVirtuals
+	}
 
 	var plcVSs []*cisapiv1.TransportServer
 	var plcVSNames []string
@@ -788,12 +1044,25 @@ func (ctlr *Controller) getTransportServersForCustomPolicy(plc *cisapiv1.Policy)
 
 // getLBServicesForCustomPolicy gets all services of type LB affected by the policy
 func (ctlr *Controller) getLBServicesForCustomPolicy(plc *cisapiv1.Policy) []*v1.Service {
+	if ctlr.isDefaultPolicy(plc) {
+		if ctlr.watchingAllNamespaces() {
+			return ctlr.getAllLBServices("")
+		}
+		var allLBServices []*v1.Service
+		for ns := range ctlr.namespaces {
+			allLBServices = append(allLBServices, ctlr.getAllLBServices(ns)...)
+		}
+		return allLBServices
+	}
 	LBServices := ctlr.getAllLBServices(plc.Namespace)
 	if nil == LBServices {
 		log.Infof("No LB service found in namespace %s",
 			plc.Namespace)
 		return nil
 	}
+	if isNamespacePolicy(plc) {
+		return LBServices
+	}
 
 	var plcSvcs []*v1.Service
 	var plcSvcNames []string
@@ -836,9 +1105,15 @@ func (ctlr *Controller) getAllVirtualServers(namespace string) []*cisapiv1.Virtu
 
 	for _, obj := range orderedVSs {
 		vs := obj.(*cisapiv1.VirtualServer)
+		// Skip the VirtualServers of other resource classes and the VirtualServers being deleted
+		if !ctlr.isManagedResourceClass(vs.Annotations) || vs.DeletionTimestamp != nil {
+			continue
+		}
 		// TODO: Validate the VirtualServers List to check if all the vs are valid.
 		allVirtuals = append(allVirtuals, vs)
 	}
+	// VirtualServers derived from Ingresses are processed along with VirtualServers
+	allVirtuals = append(allVirtuals, ctlr.getIngressVirtualServers(namespace)...)
 
 	return allVirtuals
 }
@@ -905,15 +1180,9 @@ func getVirtualServersForTLSProfile(allVirtuals []*cisapiv1.VirtualServer,
 
 	for _, vs := range allVirtuals {
 		if vs.ObjectMeta.Namespace == tlsNamespace && vs.Spec.TLSProfileName == tlsName {
-			found := false
-			for _, host := range tls.Spec.Hosts {
-				if vs.Spec.Host == host {
-					result = append(result, vs)
-					found = true
-					break
-				}
-			}
-			if !found {
+			if tlsProfileMatchesHost(tls, vs.Spec.Host) {
+				result = append(result, vs)
+			} else {
 				log.Errorf("TLSProfile hostname is not same as virtual host %s for profile %s", vs.Spec.Host, vs.Spec.TLSProfileName)
 			}
 		}
@@ -928,6 +1197,11 @@ func (ctlr *Controller) getTLSProfileForVirtualServer(
 	tlsName := vs.Spec.TLSProfileName
 	tlsKey := fmt.Sprintf("%s/%s", namespace, tlsName)
 
+	// VirtualServers derived from Ingresses refer the TLS secret of the host
+	if _, ok := getIngressNameForVirtualServer(vs); ok {
+		return getTLSProfileForIngressVirtualServer(vs)
+	}
+
 	// Initialize CustomResource Informer for required namespace
 	crInf, ok := ctlr.getNamespacedCRInformer(namespace)
 	if !ok {
@@ -966,7 +1240,7 @@ func (ctlr *Controller) getTLSProfileForVirtualServer(
 				}
 				clientSecret := clientSecretobj.(*v1.Secret)
 				//validate at least one clientSSL certificates matches the VS hostname
-				if checkCertificateHost(vs.Spec.Host, clientSecret.Data["tls.crt"], clientSecret.Data["tls.key"]) {
+				if checkSecretCertificateHost(vs.Spec.Host, clientSecret, tlsProfile.Spec.TLS.PrivateKeys) {
 					match = true
 					break
 				}
@@ -980,7 +1254,7 @@ func (ctlr *Controller) getTLSProfileForVirtualServer(
 			}
 			clientSecret := clientSecretobj.(*v1.Secret)
 			//validate clientSSL certificates and hostname
-			match = checkCertificateHost(vs.Spec.Host, clientSecret.Data["tls.crt"], clientSecret.Data["tls.key"])
+			match = checkSecretCertificateHost(vs.Spec.Host, clientSecret, tlsProfile.Spec.TLS.PrivateKeys)
 		}
 		if match == false {
 			return nil
@@ -994,23 +1268,93 @@ func (ctlr *Controller) getTLSProfileForVirtualServer(
 		return tlsProfile
 	}
 
-	for _, host := range tlsProfile.Spec.Hosts {
-		if host == vs.Spec.Host {
-			// TLSProfile Object
-			return tlsProfile
+	if tlsProfileMatchesHost(tlsProfile, vs.Spec.Host) {
+		// TLSProfile Object
+		return tlsProfile
+	}
+	log.Errorf("TLSProfile %s with host %s does not match with virtual server %s host.", tlsName, vs.Spec.Host, vs.ObjectMeta.Name)
+	return nil
+
+}
+
+// tlsProfileMatchesHost checks whether the hosts of the TLSProfile match the host including the wildcard hosts
+func tlsProfileMatchesHost(tls *cisapiv1.TLSProfile, vsHost string) bool {
+	for _, host := range tls.Spec.Hosts {
+		if host == vsHost {
+			return true
 		}
 		// check for wildcard match
 		if strings.HasPrefix(host, "*") {
 			host = strings.TrimPrefix(host, "*")
-			if strings.HasSuffix(vs.Spec.Host, host) {
-				// TLSProfile Object
-				return tlsProfile
+			if strings.HasSuffix(vsHost, host) {
+				return true
 			}
 		}
 	}
-	log.Errorf("TLSProfile %s with host %s does not match with virtual server %s host.", tlsName, vs.Spec.Host, vs.ObjectMeta.Name)
-	return nil
+	return false
+}
+
+// isDefaultTLSProfile checks whether the TLSProfile is the default TLSProfile of its namespace
+func isDefaultTLSProfile(tls *cisapiv1.TLSProfile) bool {
+	return tls.Annotations[DefaultTLSProfileAnnotation] == "true"
+}
 
+// usesDefaultTLSProfile checks whether the VirtualServer uses the default TLSProfile of its namespace,
+// VirtualServers derived from Ingresses refer the TLS secrets of the Ingresses
+func usesDefaultTLSProfile(vs *cisapiv1.VirtualServer) bool {
+	if _, ok := getIngressNameForVirtualServer(vs); ok {
+		return false
+	}
+	return vs.Spec.Host != "" && vs.Spec.TLSProfileName == ""
+}
+
+// getDefaultTLSProfile fetches the TLSProfile with the default TLSProfile annotation in the namespace,
+// oldest one is used when there are multiple default TLSProfiles
+func (ctlr *Controller) getDefaultTLSProfile(namespace string) *cisapiv1.TLSProfile {
+	crInf, ok := ctlr.getNamespacedCRInformer(namespace)
+	if !ok {
+		return nil
+	}
+	objs, err := crInf.tlsInformer.GetIndexer().ByIndex("namespace", namespace)
+	if err != nil {
+		log.Errorf("Unable to get list of TLSProfiles for namespace '%v': %v", namespace, err)
+		return nil
+	}
+	var defaultTLS *cisapiv1.TLSProfile
+	for _, obj := range objs {
+		tls := obj.(*cisapiv1.TLSProfile)
+		if !isDefaultTLSProfile(tls) {
+			continue
+		}
+		if defaultTLS == nil || tls.CreationTimestamp.Before(&defaultTLS.CreationTimestamp) ||
+			(tls.CreationTimestamp.Equal(&defaultTLS.CreationTimestamp) && tls.Name < defaultTLS.Name) {
+			defaultTLS = tls
+		}
+	}
+	return defaultTLS
+}
+
+// getDefaultTLSVirtualServers replaces the VirtualServers with host and without TLSProfile with the VirtualServers
+// referring the default TLSProfile of their namespace, if the hosts of the default TLSProfile match the host
+func (ctlr *Controller) getDefaultTLSVirtualServers(virtuals []*cisapiv1.VirtualServer) []*cisapiv1.VirtualServer {
+	defaultTLSProfiles := make(map[string]*cisapiv1.TLSProfile)
+	for i, vrt := range virtuals {
+		if !usesDefaultTLSProfile(vrt) {
+			continue
+		}
+		tls, found := defaultTLSProfiles[vrt.Namespace]
+		if !found {
+			tls = ctlr.getDefaultTLSProfile(vrt.Namespace)
+			defaultTLSProfiles[vrt.Namespace] = tls
+		}
+		if tls == nil || !tlsProfileMatchesHost(tls, vrt.Spec.Host) {
+			continue
+		}
+		vrt = vrt.DeepCopy()
+		vrt.Spec.TLSProfileName = tls.Name
+		virtuals[i] = vrt
+	}
+	return virtuals
 }
 
 func isTLSVirtualServer(vrt *cisapiv1.VirtualServer) bool {
@@ -1038,6 +1382,45 @@ func doVSHandleHTTP(virtuals []*cisapiv1.VirtualServer, virtual *cisapiv1.Virtua
 	return false
 }
 
+// getPausedResource returns the resource to be processed, a resource with the pause annotation is processed
+// as observed when the annotation is added until the annotation is removed or the resource is deleted
+func (ctlr *Controller) getPausedResource(rscRef resourceRef, annotations map[string]string, rsc interface{}) interface{} {
+	if annotations[PauseAnnotation] != "true" {
+		if _, ok := ctlr.resources.pausedResources[rscRef]; ok {
+			log.Infof("Resuming the updates of %v %v/%v", rscRef.kind, rscRef.namespace, rscRef.name)
+			delete(ctlr.resources.pausedResources, rscRef)
+		}
+		return rsc
+	}
+	if pausedRsc, ok := ctlr.resources.pausedResources[rscRef]; ok {
+		log.Debugf("Updates of %v %v/%v are paused with %v annotation, processing the paused resource",
+			rscRef.kind, rscRef.namespace, rscRef.name, PauseAnnotation)
+		return pausedRsc
+	}
+	log.Infof("Pausing the updates of %v %v/%v with %v annotation", rscRef.kind, rscRef.namespace,
+		rscRef.name, PauseAnnotation)
+	ctlr.resources.pausedResources[rscRef] = rsc
+	return rsc
+}
+
+// getPausedVirtualServers replaces the VirtualServers with the pause annotation with the paused VirtualServers
+func (ctlr *Controller) getPausedVirtualServers(virtuals []*cisapiv1.VirtualServer) []*cisapiv1.VirtualServer {
+	for i, vrt := range virtuals {
+		rscRef := resourceRef{kind: VirtualServer, namespace: vrt.Namespace, name: vrt.Name}
+		virtuals[i] = ctlr.getPausedResource(rscRef, vrt.Annotations, vrt).(*cisapiv1.VirtualServer)
+	}
+	return virtuals
+}
+
+// getPausedTransportServers replaces the TransportServers with the pause annotation with the paused TransportServers
+func (ctlr *Controller) getPausedTransportServers(virtuals []*cisapiv1.TransportServer) []*cisapiv1.TransportServer {
+	for i, vrt := range virtuals {
+		rscRef := resourceRef{kind: TransportServer, namespace: vrt.Namespace, name: vrt.Name}
+		virtuals[i] = ctlr.getPausedResource(rscRef, vrt.Annotations, vrt).(*cisapiv1.TransportServer)
+	}
+	return virtuals
+}
+
 // processVirtualServers takes the Virtual Server as input and processes all
 // associated VirtualServers to create a resource config(Internal DataStructure)
 // or to update if exists already.
@@ -1053,8 +1436,15 @@ func (ctlr *Controller) processVirtualServers(
 			virtual, endTime.Sub(startTime))
 	}()
 
+	// WideIP generated from the deleted Virtual Server is removed even if the virtuals are not processed
+	if isVSDeleted {
+		defer ctlr.processWideIPForVirtualServer(virtual, isVSDeleted)
+	}
+
 	// Skip validation for a deleted Virtual Server
 	if !isVSDeleted {
+		virtual = ctlr.getPausedVirtualServers([]*cisapiv1.VirtualServer{virtual})[0]
+		virtual = ctlr.getDefaultTLSVirtualServers([]*cisapiv1.VirtualServer{virtual})[0]
 		// check if the virutal server matches all the requirements.
 		vkey := virtual.ObjectMeta.Namespace + "/" + virtual.ObjectMeta.Name
 		valid := ctlr.checkValidVirtualServer(virtual)
@@ -1072,6 +1462,7 @@ func (ctlr *Controller) processVirtualServers(
 	} else {
 		allVirtuals = ctlr.getAllVirtualServers(virtual.ObjectMeta.Namespace)
 	}
+	allVirtuals = ctlr.getDefaultTLSVirtualServers(ctlr.getPausedVirtualServers(allVirtuals))
 	ctlr.TeemData.Lock()
 	ctlr.TeemData.ResourceType.VirtualServer[virtual.ObjectMeta.Namespace] = len(allVirtuals)
 	ctlr.TeemData.Unlock()
@@ -1083,6 +1474,17 @@ func (ctlr *Controller) processVirtualServers(
 	VSSpecProps := &VSSpecProperties{}
 	virtuals := ctlr.getAssociatedVirtualServers(virtual, allVirtuals, isVSDeleted, VSSpecProps)
 	//ctlr.getAssociatedSpecVirtuals(virtuals,VSSpecProps)
+	ctlr.updateVirtualServerConflictStatus(virtuals, VSSpecProps.PathConflicts)
+	// Services of other namespaces are referred only when granted
+	virtuals = ctlr.filterGrantedServiceReferences(virtuals)
+	// VirtualServers quarantined for the AS3 errors are excluded from the declaration
+	virtuals = ctlr.filterQuarantinedVirtualServers(virtuals)
+	// VirtualServers referring the BIG-IP objects not allowed for their namespaces are excluded from the declaration
+	virtuals = ctlr.filterAllowedBigIPReferences(virtuals)
+	// VirtualServers referring the LTM policies and iRules not found on BIG-IP are excluded from the declaration
+	virtuals = ctlr.filterMissingBigIPObjects(virtuals)
+	// NodeMemberLabel of the VirtualServer is the default of its pools
+	virtuals = setVirtualServerNodeMemberLabel(virtuals)
 
 	var ip string
 	var status int
@@ -1149,6 +1551,8 @@ func (ctlr *Controller) processVirtualServers(
 	}
 	// Updating the virtual server IP Address status
 	virtual.Status.VSAddress = ip
+	// pin the virtual to the route domain
+	ip = appendRouteDomain(ip, virtual.Spec.RouteDomain)
 	// Depending on the ports defined, TLS type or Unsecured we will populate the resource config.
 	portStructs := ctlr.virtualPorts(virtual)
 
@@ -1156,7 +1560,6 @@ func (ctlr *Controller) processVirtualServers(
 	vsMap := make(ResourceMap)
 	processingError := false
 	for _, portS := range portStructs {
-		// TODO: Add Route Domain
 		var rsName string
 		if virtual.Spec.VirtualServerName != "" {
 			if virtual.Spec.HostGroup != "" {
@@ -1188,6 +1591,11 @@ func (ctlr *Controller) processVirtualServers(
 			rsMap := ctlr.resources.getPartitionResourceMap(partition)
 
 			if _, ok := rsMap[rsName]; ok {
+				// retain the virtual of the resource claiming the address
+				if isClaimedByOtherResources(rsMap[rsName],
+					getVirtualServerBaseResources(append([]*cisapiv1.VirtualServer{virtual}, virtuals...)...)) {
+					continue
+				}
 				hostnames = rsMap[rsName].MetaData.hosts
 			}
 			ctlr.deleteVirtualServer(partition, rsName)
@@ -1213,8 +1621,9 @@ func (ctlr *Controller) processVirtualServers(
 			portS.port,
 		)
 		//set additionalVirtualAddresses if present
-		if len(virtual.Spec.AdditionalVirtualServerAddresses) > 0 {
-			rsCfg.Virtual.AdditionalVirtualAddresses = virtual.Spec.AdditionalVirtualServerAddresses
+		for _, address := range virtual.Spec.AdditionalVirtualServerAddresses {
+			rsCfg.Virtual.AdditionalVirtualAddresses = append(rsCfg.Virtual.AdditionalVirtualAddresses,
+				appendRouteDomain(address, virtual.Spec.RouteDomain))
 		}
 		rsCfg.IntDgMap = make(InternalDataGroupMap)
 		rsCfg.IRulesMap = make(IRulesMap)
@@ -1225,6 +1634,7 @@ func (ctlr *Controller) processVirtualServers(
 			err := ctlr.handleVSResourceConfigForPolicy(rsCfg, plc)
 			if err != nil {
 				processingError = true
+				log.Errorf("%v", err)
 				break
 			}
 		}
@@ -1253,7 +1663,11 @@ func (ctlr *Controller) processVirtualServers(
 
 			log.Debugf("Processing Virtual Server %s for port %v",
 				vrt.ObjectMeta.Name, portS.port)
-			rsCfg.MetaData.baseResources[vrt.Namespace+"/"+vrt.Name] = VirtualServer
+			rscKind, rscName := VirtualServer, vrt.Name
+			if ingName, ok := getIngressNameForVirtualServer(vrt); ok {
+				rscKind, rscName = Ingress, ingName
+			}
+			rsCfg.MetaData.baseResources[vrt.Namespace+"/"+rscName] = rscKind
 			err := ctlr.prepareRSConfigFromVirtualServer(
 				rsCfg,
 				vrt,
@@ -1276,11 +1690,14 @@ func (ctlr *Controller) processVirtualServers(
 				log.Debugf("Updated Virtual %s with TLSProfile %s",
 					vrt.ObjectMeta.Name, vrt.Spec.TLSProfileName)
 			}
+			if rscKind == VirtualServer {
+				ctlr.checkSNATReturnPath(vrt, rsCfg.Virtual.SNAT)
+			}
 
 			ctlr.resources.processedNativeResources[resourceRef{
-				kind:      VirtualServer,
+				kind:      rscKind,
 				namespace: vrt.Namespace,
-				name:      vrt.Name,
+				name:      rscName,
 			}] = struct{}{}
 
 		}
@@ -1303,6 +1720,9 @@ func (ctlr *Controller) processVirtualServers(
 
 		// Update ltmConfig with ResourceConfigs created for the current virtuals
 		for rsName, rsCfg := range vsMap {
+			if !ctlr.claimVirtualAddress(partition, rsName, rsCfg) {
+				continue
+			}
 			if _, ok := rsMap[rsName]; !ok {
 				hostnames = rsCfg.MetaData.hosts
 			}
@@ -1312,6 +1732,9 @@ func (ctlr *Controller) processVirtualServers(
 		if len(hostnames) > 0 {
 			ctlr.ProcessAssociatedExternalDNS(hostnames)
 		}
+		if !isVSDeleted {
+			ctlr.processWideIPForVirtualServer(virtual, isVSDeleted)
+		}
 	}
 
 	return nil
@@ -1359,13 +1782,15 @@ func (ctlr *Controller) getAssociatedVirtualServers(
 	// that particular VirtualServer will be skipped.
 
 	var virtuals []*cisapiv1.VirtualServer
-	// {hostname: {path: <empty_struct>}}
-	uniqueHostPathMap := make(map[string]map[string]struct{})
+	// {hostname: {path: <VirtualServer claiming the path>}}
+	uniqueHostPathMap := make(map[string]map[string]*cisapiv1.VirtualServer)
 	currentVSPartition := ctlr.getCRPartition(currentVS.Spec.Partition)
 
+	// The oldest VirtualServer claiming a path keeps it, irrespective of the namespace
+	allVirtuals = sortVirtualServersByCreation(allVirtuals)
 	for _, vrt := range allVirtuals {
 		// skip the deleted virtual in the event of deletion
-		if isVSDeleted && vrt.Name == currentVS.Name {
+		if isVSDeleted && vrt.Name == currentVS.Name && vrt.Namespace == currentVS.Namespace {
 			continue
 		}
 
@@ -1454,10 +1879,22 @@ func (ctlr *Controller) getAssociatedVirtualServers(
 			continue
 		}
 
+		// skip the virtuals with different redirect status code
+		if currentVS.Spec.HTTPRedirect.StatusCode != vrt.Spec.HTTPRedirect.StatusCode {
+			log.Errorf("%v/%v and %v/%v VS should have same httpRedirect statusCode.", vrt.Namespace, vrt.Name, currentVS.Namespace, currentVS.Name)
+			continue
+		}
+
+		// skip the virtuals with different error page
+		if !reflect.DeepEqual(currentVS.Spec.ErrorPage, vrt.Spec.ErrorPage) {
+			log.Errorf("%v/%v and %v/%v VS should have same error page.", vrt.Namespace, vrt.Name, currentVS.Namespace, currentVS.Name)
+			continue
+		}
+
 		// Check for duplicate path entries among virtuals
 		uniquePaths, ok := uniqueHostPathMap[vrt.Spec.Host]
 		if !ok {
-			uniqueHostPathMap[vrt.Spec.Host] = make(map[string]struct{})
+			uniqueHostPathMap[vrt.Spec.Host] = make(map[string]*cisapiv1.VirtualServer)
 			uniquePaths = uniqueHostPathMap[vrt.Spec.Host]
 		}
 		isUnique := true
@@ -1466,22 +1903,94 @@ func (ctlr *Controller) getAssociatedVirtualServers(
 			if pool.WAF != "" {
 				VSSpecProperties.PoolWAF = true
 			}
-			if _, ok := uniquePaths[pool.Path]; ok {
+			if owner, ok := uniquePaths[pool.Path]; ok {
 				// path already exists for the same host
-				log.Debugf("Discarding the VirtualServer %v/%v due to duplicate path",
-					vrt.ObjectMeta.Namespace, vrt.ObjectMeta.Name)
+				log.Errorf("Discarding the VirtualServer %v/%v as path %v of host %v is already claimed by VirtualServer %v/%v",
+					vrt.ObjectMeta.Namespace, vrt.ObjectMeta.Name, pool.Path, vrt.Spec.Host, owner.Namespace, owner.Name)
+				VSSpecProperties.PathConflicts = append(VSSpecProperties.PathConflicts, vsPathConflict{
+					virtual: vrt,
+					owner:   owner,
+					host:    vrt.Spec.Host,
+					path:    pool.Path,
+				})
 				isUnique = false
 				break
 			}
-			uniquePaths[pool.Path] = struct{}{}
 		}
 		if isUnique {
+			// paths are claimed only by the associated virtuals
+			for _, pool := range vrt.Spec.Pools {
+				uniquePaths[pool.Path] = vrt
+			}
 			virtuals = append(virtuals, vrt)
 		}
 	}
 	return virtuals
 }
 
+// setVirtualServerNodeMemberLabel sets the NodeMemberLabel of the VirtualServers on their pools without
+// the NodeMemberLabel, the VirtualServers are copied only when their pools are updated
+func setVirtualServerNodeMemberLabel(virtuals []*cisapiv1.VirtualServer) []*cisapiv1.VirtualServer {
+	var result []*cisapiv1.VirtualServer
+	for _, vs := range virtuals {
+		if vs.Spec.NodeMemberLabel != "" {
+			vs = vs.DeepCopy()
+			for i := range vs.Spec.Pools {
+				if vs.Spec.Pools[i].NodeMemberLabel == "" {
+					vs.Spec.Pools[i].NodeMemberLabel = vs.Spec.NodeMemberLabel
+				}
+			}
+			if vs.Spec.DefaultPool.Reference == ServiceRef && vs.Spec.DefaultPool.NodeMemberLabel == "" {
+				vs.Spec.DefaultPool.NodeMemberLabel = vs.Spec.NodeMemberLabel
+			}
+		}
+		result = append(result, vs)
+	}
+	return result
+}
+
+// setTransportServerNodeMemberLabel sets the NodeMemberLabel of the TransportServer on its pool without
+// the NodeMemberLabel
+func setTransportServerNodeMemberLabel(ts *cisapiv1.TransportServer) *cisapiv1.TransportServer {
+	if ts.Spec.NodeMemberLabel == "" {
+		return ts
+	}
+	update := ts.Spec.Pool.NodeMemberLabel == ""
+	for _, route := range ts.Spec.SNIRoutes {
+		update = update || route.Pool.NodeMemberLabel == ""
+	}
+	if !update {
+		return ts
+	}
+	ts = ts.DeepCopy()
+	if ts.Spec.Pool.NodeMemberLabel == "" {
+		ts.Spec.Pool.NodeMemberLabel = ts.Spec.NodeMemberLabel
+	}
+	for i := range ts.Spec.SNIRoutes {
+		if ts.Spec.SNIRoutes[i].Pool.NodeMemberLabel == "" {
+			ts.Spec.SNIRoutes[i].Pool.NodeMemberLabel = ts.Spec.NodeMemberLabel
+		}
+	}
+	return ts
+}
+
+// sortVirtualServersByCreation returns the VirtualServers sorted by the creation time,
+// the VirtualServers created at the same time are sorted by the namespace and name
+func sortVirtualServersByCreation(virtuals []*cisapiv1.VirtualServer) []*cisapiv1.VirtualServer {
+	sorted := make([]*cisapiv1.VirtualServer, len(virtuals))
+	copy(sorted, virtuals)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].CreationTimestamp.Equal(&sorted[j].CreationTimestamp) {
+			return sorted[i].CreationTimestamp.Before(&sorted[j].CreationTimestamp)
+		}
+		if sorted[i].Namespace != sorted[j].Namespace {
+			return sorted[i].Namespace < sorted[j].Namespace
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
 func (ctlr *Controller) validateTSWithSameVSAddress(
 	currentTS *cisapiv1.TransportServer,
 	allVirtuals []*cisapiv1.TransportServer,
@@ -1552,7 +2061,7 @@ func (ctlr *Controller) getPolicyFromVirtuals(virtuals []*cisapiv1.VirtualServer
 		}
 	}
 	if plcName == "" {
-		return nil, nil
+		return ctlr.getEffectivePolicy(ns, nil), nil
 	}
 	crInf, ok := ctlr.getNamespacedCommonInformer(ns)
 	if !ok {
@@ -1570,7 +2079,7 @@ func (ctlr *Controller) getPolicyFromVirtuals(virtuals []*cisapiv1.VirtualServer
 		return nil, fmt.Errorf("Policy Not Found: %v", key)
 	}
 
-	return obj.(*cisapiv1.Policy), nil
+	return ctlr.getEffectivePolicy(ns, obj.(*cisapiv1.Policy)), nil
 }
 
 func (ctlr *Controller) getPolicyFromTransportServer(virtual *cisapiv1.TransportServer) (*cisapiv1.Policy, error) {
@@ -1580,35 +2089,142 @@ func (ctlr *Controller) getPolicyFromTransportServer(virtual *cisapiv1.Transport
 		return nil, nil
 	}
 
-	plcName := virtual.Spec.PolicyName
-	if plcName == "" {
-		return nil, nil
-	}
-	ns := virtual.Namespace
-	return ctlr.getPolicy(ns, plcName)
+	plcName := virtual.Spec.PolicyName
+	if plcName == "" {
+		return ctlr.getEffectivePolicy(virtual.Namespace, nil), nil
+	}
+	ns := virtual.Namespace
+	plc, err := ctlr.getPolicy(ns, plcName)
+	if err != nil {
+		return nil, err
+	}
+	return ctlr.getEffectivePolicy(ns, plc), nil
+}
+
+// getPolicy fetches the policy CR
+func (ctlr *Controller) getPolicy(ns string, plcName string) (*cisapiv1.Policy, error) {
+	crInf, ok := ctlr.getNamespacedCommonInformer(ns)
+	if !ok {
+		log.Errorf("Informer not found for namespace: %v", ns)
+		return nil, fmt.Errorf("Informer not found for namespace: %v", ns)
+	}
+	key := ns + "/" + plcName
+
+	obj, exist, err := crInf.plcInformer.GetIndexer().GetByKey(key)
+	if err != nil {
+		log.Errorf("Error while fetching Policy: %v: %v",
+			key, err)
+		return nil, fmt.Errorf("Error while fetching Policy: %v: %v", key, err)
+	}
+
+	if !exist {
+		log.Errorf("Policy Not Found: %v", key)
+		return nil, fmt.Errorf("Policy Not Found: %v", key)
+	}
+	return obj.(*cisapiv1.Policy), nil
+}
+
+// getDefaultPolicy fetches the default policy CR specified with --default-policy
+func (ctlr *Controller) getDefaultPolicy() *cisapiv1.Policy {
+	if ctlr.defaultPolicy == "" {
+		return nil
+	}
+	splits := strings.Split(ctlr.defaultPolicy, "/")
+	if len(splits) != 2 {
+		return nil
+	}
+	plc, err := ctlr.getPolicy(splits[0], splits[1])
+	if err != nil {
+		return nil
+	}
+	return plc
+}
+
+// isDefaultPolicy checks whether the policy CR is the default policy
+func (ctlr *Controller) isDefaultPolicy(plc *cisapiv1.Policy) bool {
+	return ctlr.defaultPolicy != "" && ctlr.defaultPolicy == plc.Namespace+"/"+plc.Name
+}
+
+// isNamespacePolicy checks whether the policy CR is the namespace policy of its namespace
+func isNamespacePolicy(plc *cisapiv1.Policy) bool {
+	return plc.Annotations[NamespacePolicyAnnotation] == "true"
+}
+
+// getNamespacePolicy fetches the policy CR with the namespace policy annotation in the namespace,
+// oldest one is used when there are multiple namespace policies
+func (ctlr *Controller) getNamespacePolicy(namespace string) *cisapiv1.Policy {
+	comInf, ok := ctlr.getNamespacedCommonInformer(namespace)
+	if !ok {
+		return nil
+	}
+	objs, err := comInf.plcInformer.GetIndexer().ByIndex("namespace", namespace)
+	if err != nil {
+		log.Errorf("Unable to get list of Policies for namespace '%v': %v", namespace, err)
+		return nil
+	}
+	var nsPlc *cisapiv1.Policy
+	for _, obj := range objs {
+		plc := obj.(*cisapiv1.Policy)
+		if !isNamespacePolicy(plc) {
+			continue
+		}
+		if nsPlc == nil || plc.CreationTimestamp.Before(&nsPlc.CreationTimestamp) ||
+			(plc.CreationTimestamp.Equal(&nsPlc.CreationTimestamp) && plc.Name < nsPlc.Name) {
+			nsPlc = plc
+		}
+	}
+	return nsPlc
+}
+
+// mergeDefaultPolicy returns the policy merged with the default policy, route groups are not
+// namespace scoped and use only the default policy
+func (ctlr *Controller) mergeDefaultPolicy(plc *cisapiv1.Policy) *cisapiv1.Policy {
+	return ctlr.getEffectivePolicy("", plc)
 }
 
-// getPolicy fetches the policy CR
-func (ctlr *Controller) getPolicy(ns string, plcName string) (*cisapiv1.Policy, error) {
-	crInf, ok := ctlr.getNamespacedCommonInformer(ns)
-	if !ok {
-		log.Errorf("Informer not found for namespace: %v", ns)
-		return nil, fmt.Errorf("Informer not found for namespace: %v", ns)
+// getEffectivePolicy returns the policy of the resource merged with the namespace policy of the namespace and
+// the default policy. Fields set in the policy of the resource take precedence over the namespace policy,
+// which takes precedence over the default policy
+func (ctlr *Controller) getEffectivePolicy(namespace string, plc *cisapiv1.Policy) *cisapiv1.Policy {
+	var layers []*cisapiv1.Policy
+	if plc != nil {
+		layers = append(layers, plc)
 	}
-	key := ns + "/" + plcName
-
-	obj, exist, err := crInf.plcInformer.GetIndexer().GetByKey(key)
-	if err != nil {
-		log.Errorf("Error while fetching Policy: %v: %v",
-			key, err)
-		return nil, fmt.Errorf("Error while fetching Policy: %v: %v", key, err)
+	if namespace != "" {
+		// namespace policy referenced by the resource is merged only once
+		if nsPlc := ctlr.getNamespacePolicy(namespace); nsPlc != nil && (plc == nil || nsPlc.Name != plc.Name) {
+			layers = append(layers, nsPlc)
+		}
+	}
+	if defaultPlc := ctlr.getDefaultPolicy(); defaultPlc != nil {
+		layers = append(layers, defaultPlc)
 	}
+	if len(layers) == 0 {
+		return nil
+	}
+	if len(layers) == 1 {
+		return layers[0]
+	}
+	mergedPlc := layers[0].DeepCopy()
+	for _, layer := range layers[1:] {
+		mergePolicySpec(reflect.ValueOf(&mergedPlc.Spec).Elem(), reflect.ValueOf(layer.Spec.DeepCopy()).Elem())
+	}
+	return mergedPlc
+}
 
-	if !exist {
-		log.Errorf("Policy Not Found: %v", key)
-		return nil, fmt.Errorf("Policy Not Found: %v", key)
+// mergePolicySpec sets the fields of the spec which are not set with the fields of the default spec,
+// nested fields are merged individually
+func mergePolicySpec(spec, defaultSpec reflect.Value) {
+	for i := 0; i < spec.NumField(); i++ {
+		field := spec.Field(i)
+		if field.Kind() == reflect.Struct {
+			mergePolicySpec(field, defaultSpec.Field(i))
+			continue
+		}
+		if field.IsZero() {
+			field.Set(defaultSpec.Field(i))
+		}
 	}
-	return obj.(*cisapiv1.Policy), nil
 }
 
 func getIPAMLabel(virtuals []*cisapiv1.VirtualServer) string {
@@ -1936,7 +2552,9 @@ func (ctlr *Controller) updatePoolIdentifierForService(key MultiClusterServiceKe
 	ctlr.multiClusterResources.clusterSvcMap[key.clusterName][key][multiClusterSvcConfig][poolId] = struct{}{}
 }
 
-func (ctlr *Controller) updatePoolMembersForService(svcKey MultiClusterServiceKey) {
+// updatePoolMembersForService updates the pool members of the resources using the service, the resources
+// with a named targetPort or with the service ports updated are reprocessed to resolve the targetPort again
+func (ctlr *Controller) updatePoolMembersForService(svcKey MultiClusterServiceKey, svcPortUpdated bool) {
 	if serviceKey, ok := ctlr.multiClusterResources.clusterSvcMap[svcKey.clusterName]; ok {
 		if svcPorts, ok2 := serviceKey[svcKey]; ok2 {
 			for _, poolIds := range svcPorts {
@@ -1949,7 +2567,7 @@ func (ctlr *Controller) updatePoolMembersForService(svcKey MultiClusterServiceKe
 					freshRsCfg.copyConfig(rsCfg)
 					for index, pool := range freshRsCfg.Pools {
 						if pool.Name == poolId.poolName && pool.Partition == poolId.partition {
-							if pool.ServicePort.IntVal == 0 {
+							if pool.ServicePort.IntVal == 0 || svcPortUpdated {
 								switch poolId.rsKey.kind {
 								case Route:
 									// this case happens when a route does not contain a target port and service is created after route creation
@@ -2059,9 +2677,24 @@ func (ctlr *Controller) updatePoolMembersForResources(pool *Pool) {
 	var poolMembers []PoolMember
 	// for local cluster
 	if pool.Cluster == "" {
-		poolMembers = append(poolMembers,
-			ctlr.fetchPoolMembersForService(pool.ServiceName, pool.ServiceNamespace, pool.ServicePort,
-				pool.NodeMemberLabel, "")...)
+		members := ctlr.fetchPoolMembersForService(pool.ServiceName, pool.ServiceNamespace, pool.ServicePort,
+			pool.NodeMemberLabel, "")
+		// ratio is set with the pod addresses of the members before they are replaced with the other addresses
+		if pool.MemberRatio != "" {
+			members = ctlr.setPoolMemberRatio(pool.ServiceNamespace, pool.ServiceName, pool.MemberRatio, members)
+		}
+		if pool.WarmUpPeriod > 0 {
+			members = ctlr.setWarmUpMemberRatio(pool.ServiceNamespace, pool.ServiceName, pool.WarmUpPeriod,
+				members)
+		}
+		if pool.NetworkAttachment != "" {
+			members = ctlr.getNetworkAttachmentMembers(pool.ServiceNamespace, pool.ServiceName,
+				pool.NetworkAttachment, members)
+		} else if pool.AddressFamily != "" {
+			members = ctlr.getAddressFamilyMembers(pool.ServiceNamespace, pool.ServiceName, pool.AddressFamily,
+				members)
+		}
+		poolMembers = append(poolMembers, members...)
 		if len(ctlr.clusterRatio) > 0 {
 			pool.Members = poolMembers
 			return
@@ -2091,12 +2724,32 @@ func (ctlr *Controller) updatePoolMembersForResources(pool *Pool) {
 		// Ensure cluster services of the HA pair cluster (if specified as multi cluster service in route annotations)
 		// isn't considered for updating the pool members as it may lead to duplicate pool members as it may have been
 		// already populated while updating the HA cluster pair service pool members above
-		if _, ok := ctlr.multiClusterPoolInformers[mcs.ClusterName]; ok && ctlr.multiClusterConfigs.HAPairClusterName != mcs.ClusterName {
+		// Services of the backup clusters are considered only as the backup pool members
+		if _, ok := ctlr.multiClusterPoolInformers[mcs.ClusterName]; ok && ctlr.multiClusterConfigs.HAPairClusterName != mcs.ClusterName &&
+			!ctlr.isBackupCluster(mcs.ClusterName) {
 			poolMembers = append(poolMembers,
 				ctlr.fetchPoolMembersForService(mcs.SvcName, mcs.Namespace, mcs.ServicePort,
 					pool.NodeMemberLabel, mcs.ClusterName)...)
 		}
 	}
+
+	// For backup cluster services, the pool members of the other clusters are moved to the higher priority group
+	// so that BIG-IP sends the traffic to the backup pool members only when none of them are available
+	var backupPoolMembers []PoolMember
+	for _, clusterName := range ctlr.getBackupClusterNames() {
+		backupPoolMembers = append(backupPoolMembers,
+			ctlr.fetchPoolMembersForService(pool.ServiceName, pool.ServiceNamespace, pool.ServicePort,
+				pool.NodeMemberLabel, clusterName)...)
+	}
+	if len(backupPoolMembers) > 0 {
+		for i := range poolMembers {
+			poolMembers[i].PriorityGroup = PrimaryPriorityGroup
+		}
+		for i := range backupPoolMembers {
+			backupPoolMembers[i].PriorityGroup = BackupPriorityGroup
+		}
+		poolMembers = append(poolMembers, backupPoolMembers...)
+	}
 	pool.Members = poolMembers
 }
 
@@ -2218,11 +2871,23 @@ func (ctlr *Controller) getEndpointsForNodePort(
 	}
 	var members []PoolMember
 	for _, v := range nodes {
+		if !ctlr.isPoolMemberNodeOS(v.OS) {
+			continue
+		}
 		member := PoolMember{
 			Address: v.Addr,
 			Port:    nodePort,
 			Session: "user-enabled",
 		}
+		if v.Unschedulable {
+			switch ctlr.unschedulableNodes {
+			case UnschedulableNodeRemove:
+				continue
+			case UnschedulableNodeDrain:
+				// members of the unschedulable node are drained before the node is removed
+				member.Session = "user-disabled"
+			}
+		}
 		members = append(members, member)
 	}
 	return members
@@ -2296,6 +2961,7 @@ func (ctlr *Controller) processTransportServers(
 
 	// Skip validation for a deleted Virtual Server
 	if !isTSDeleted {
+		virtual = ctlr.getPausedTransportServers([]*cisapiv1.TransportServer{virtual})[0]
 		// check if the virutal server matches all the requirements.
 		vkey := virtual.ObjectMeta.Namespace + "/" + virtual.ObjectMeta.Name
 		valid := ctlr.checkValidTransportServer(virtual)
@@ -2322,6 +2988,7 @@ func (ctlr *Controller) processTransportServers(
 	} else {
 		allVirtuals = ctlr.getAllTransportServers(virtual.ObjectMeta.Namespace)
 	}
+	allVirtuals = ctlr.getPausedTransportServers(allVirtuals)
 	isValidTS := ctlr.validateTSWithSameVSAddress(virtual, allVirtuals, isTSDeleted)
 	if !isValidTS {
 		return nil
@@ -2366,6 +3033,8 @@ func (ctlr *Controller) processTransportServers(
 	}
 	// Updating the virtual server IP Address status
 	virtual.Status.VSAddress = ip
+	// pin the virtual to the route domain
+	ip = appendRouteDomain(ip, virtual.Spec.RouteDomain)
 	var rsName string
 	if virtual.Spec.VirtualServerName != "" {
 		rsName = formatCustomVirtualServerName(
@@ -2379,10 +3048,25 @@ func (ctlr *Controller) processTransportServers(
 		)
 	}
 
-	if isTSDeleted {
+	// TransportServer quarantined for the AS3 errors, referring the BIG-IP objects not allowed for its
+	// namespace or not in the allow-list, or referring the service of other namespace not allowed by a
+	// ServiceReferenceGrant is excluded from the declaration
+	var referenceDenied bool
+	if !isTSDeleted {
+		virtual, referenceDenied = ctlr.getAllowedTransportServer(virtual)
+		if !referenceDenied {
+			virtual, referenceDenied = ctlr.getGrantedTransportServer(virtual)
+		}
+	}
+	if isTSDeleted || ctlr.isResourceQuarantined(TransportServer, virtual.Namespace, virtual.Name) || referenceDenied {
 		rsMap := ctlr.resources.getPartitionResourceMap(partition)
 		var hostnames []string
 		if _, ok := rsMap[rsName]; ok {
+			// retain the virtual of the resource claiming the address
+			if isClaimedByOtherResources(rsMap[rsName],
+				map[string]string{virtual.Namespace + "/" + virtual.Name: TransportServer}) {
+				return nil
+			}
 			hostnames = rsMap[rsName].MetaData.hosts
 		}
 
@@ -2406,6 +3090,11 @@ func (ctlr *Controller) processTransportServers(
 		ip,
 		virtual.Spec.VirtualServerPort,
 	)
+	//set additionalVirtualAddresses if present
+	for _, address := range virtual.Spec.AdditionalVirtualServerAddresses {
+		rsCfg.Virtual.AdditionalVirtualAddresses = append(rsCfg.Virtual.AdditionalVirtualAddresses,
+			appendRouteDomain(address, virtual.Spec.RouteDomain))
+	}
 	plc, err := ctlr.getPolicyFromTransportServer(virtual)
 	if plc != nil {
 		err := ctlr.handleTSResourceConfigForPolicy(rsCfg, plc)
@@ -2422,14 +3111,16 @@ func (ctlr *Controller) processTransportServers(
 	log.Debugf("Processing Transport Server %s for port %v",
 		virtual.ObjectMeta.Name, virtual.Spec.VirtualServerPort)
 	rsCfg.MetaData.baseResources[virtual.ObjectMeta.Namespace+"/"+virtual.ObjectMeta.Name] = TransportServer
+	// NodeMemberLabel of the TransportServer is the default of its pool
 	err = ctlr.prepareRSConfigFromTransportServer(
 		rsCfg,
-		virtual,
+		setTransportServerNodeMemberLabel(virtual),
 	)
 	if err != nil {
 		log.Errorf("Cannot Publish TransportServer %s", virtual.ObjectMeta.Name)
 		return nil
 	}
+	ctlr.checkSNATReturnPath(virtual, rsCfg.Virtual.SNAT)
 
 	// Add TS resource key to processedNativeResources to mark it as processed
 	ctlr.resources.processedNativeResources[resourceRef{
@@ -2438,6 +3129,9 @@ func (ctlr *Controller) processTransportServers(
 		name:      virtual.Name,
 	}] = struct{}{}
 
+	if !ctlr.claimVirtualAddress(partition, rsName, rsCfg) {
+		return nil
+	}
 	rsMap := ctlr.resources.getPartitionResourceMap(partition)
 	rsMap[rsName] = rsCfg
 
@@ -2484,6 +3178,10 @@ func (ctlr *Controller) getAllTransportServers(namespace string) []*cisapiv1.Tra
 	}
 	for _, obj := range orderedTSs {
 		vs := obj.(*cisapiv1.TransportServer)
+		// Skip the TransportServers of other resource classes and the TransportServers being deleted
+		if !ctlr.isManagedResourceClass(vs.Annotations) || vs.DeletionTimestamp != nil {
+			continue
+		}
 		// TODO Validate the TransportServers List to check if all the vs are valid.
 		allVirtuals = append(allVirtuals, vs)
 	}
@@ -2562,6 +3260,11 @@ func filterTransportServersForService(allVirtuals []*cisapiv1.TransportServer,
 		if vs.Spec.Pool.Service == svcName {
 			isValidVirtual = true
 		}
+		for _, route := range vs.Spec.SNIRoutes {
+			if route.Pool.Service == svcName {
+				isValidVirtual = true
+			}
+		}
 		if !isValidVirtual {
 			continue
 		}
@@ -2780,7 +3483,8 @@ func (ctlr *Controller) processExternalDNS(edns *cisapiv1.ExternalDNS, isDelete
 
 	if gtmPartitionConfig, ok := ctlr.resources.gtmConfig[DEFAULT_GTM_PARTITION]; ok {
 		if processedWIP, ok := gtmPartitionConfig.WideIPs[edns.Spec.DomainName]; ok {
-			if processedWIP.UID != string(edns.UID) {
+			// ExternalDNS resources take precedence over WideIPs generated from VirtualServers
+			if processedWIP.UID != string(edns.UID) && !isAutoGeneratedWideIP(processedWIP) {
 				log.Errorf("EDNS with same domain name %s present", edns.Spec.DomainName)
 				return
 			}
@@ -2793,9 +3497,14 @@ func (ctlr *Controller) processExternalDNS(edns *cisapiv1.ExternalDNS, isDelete
 		}
 
 		delete(ctlr.resources.gtmConfig[DEFAULT_GTM_PARTITION].WideIPs, edns.Spec.DomainName)
+		delete(ctlr.resources.gtmConfig[DEFAULT_GTM_PARTITION].WideIPs, getDualStackWideIPKey(edns.Spec.DomainName))
 		ctlr.TeemData.Lock()
 		ctlr.TeemData.ResourceType.ExternalDNS[edns.Namespace]--
 		ctlr.TeemData.Unlock()
+		// Fall back to the WideIP generated from VirtualServers serving the same domain
+		if ctlr.autoGenerateWideIP {
+			ctlr.processAutoGeneratedWideIP(edns.Spec.DomainName, nil)
+		}
 		return
 	}
 
@@ -2837,11 +3546,82 @@ func (ctlr *Controller) processExternalDNS(edns *cisapiv1.ExternalDNS, isDelete
 
 	log.Debugf("Processing WideIP: %v", edns.Spec.DomainName)
 
+	if _, ok := ctlr.resources.gtmConfig[DEFAULT_GTM_PARTITION]; !ok {
+		ctlr.resources.gtmConfig[DEFAULT_GTM_PARTITION] = GTMPartitionConfig{
+			WideIPs: make(map[string]WideIP),
+		}
+	}
+	wideIPs := ctlr.resources.gtmConfig[DEFAULT_GTM_PARTITION].WideIPs
+	if edns.Spec.DNSRecordType != DNSRecordTypeDualStack {
+		wip.Pools = ctlr.getWideIPPools(edns, wip.RecordType, false)
+		wideIPs[wip.DomainName] = wip
+		// AAAA WideIP of the domain is removed once the ExternalDNS is no longer dual-stack
+		delete(wideIPs, getDualStackWideIPKey(wip.DomainName))
+		return
+	}
+	// Dual-stack ExternalDNS serves the A and AAAA WideIPs of the domain, AAAA WideIP is keyed apart
+	// as the WideIPs of GTM partition are keyed by the domain
+	wip.RecordType = "A"
+	wip.Pools = ctlr.getWideIPPools(edns, "A", true)
+	wideIPs[wip.DomainName] = wip
+	aaaaWIP := wip
+	aaaaWIP.RecordType = "AAAA"
+	aaaaWIP.Pools = ctlr.getWideIPPools(edns, "AAAA", true)
+	wideIPs[getDualStackWideIPKey(wip.DomainName)] = aaaaWIP
+}
+
+// isVirtualOfRecordType checks whether the address family of the virtual address serves the DNS record type,
+// IPv4 virtuals serve the A and IPv6 virtuals the AAAA records. Virtuals without the address serve either
+func isVirtualOfRecordType(rsCfg *ResourceConfig, recordType string) bool {
+	if rsCfg.Virtual.VirtualAddress == nil {
+		return true
+	}
+	// route domain and mask of the address are not part of the address family
+	ip := getVirtualAddressIP(rsCfg.Virtual.VirtualAddress.BindAddr)
+	if ip == nil {
+		return true
+	}
+	switch recordType {
+	case "A":
+		return ip.To4() != nil
+	case "AAAA":
+		return ip.To4() == nil
+	}
+	return true
+}
+
+// getDualStackWideIPKey returns the key of the AAAA WideIP of the dual-stack domain in the GTM partition config
+func getDualStackWideIPKey(domainName string) string {
+	return domainName + "_AAAA"
+}
+
+// getWideIPPools returns the GSLB pools of the ExternalDNS for the WideIP of the record type, virtual servers
+// of the address family of the record type are the pool members. Pools of dual-stack ExternalDNS are created
+// for both record types and are named after the record type, unless the pool is of either record type
+func (ctlr *Controller) getWideIPPools(edns *cisapiv1.ExternalDNS, recordType string, dualStack bool) []GSLBPool {
+	var pools []GSLBPool
 	partitions := ctlr.resources.getLTMPartitions()
 
-	for _, pl := range edns.Spec.Pools {
+	poolNames := make(map[string]struct{})
+	for i, pl := range edns.Spec.Pools {
+		if dualStack && pl.DNSRecordType != "" && pl.DNSRecordType != recordType {
+			continue
+		}
 		UniquePoolName := strings.Replace(edns.Spec.DomainName, "*", "wildcard", -1) + "_" +
 			AS3NameFormatter(strings.TrimPrefix(ctlr.Agent.BIGIPURL, "https://")) + "_" + DEFAULT_GTM_PARTITION
+		// Pools of the other data servers, e.g. BIG-IPs of other clusters, are named after their data server
+		if i > 0 {
+			UniquePoolName += "_" + AS3NameFormatter(strings.TrimPrefix(pl.DataServerName, "/"))
+		}
+		if dualStack && pl.DNSRecordType == "" {
+			UniquePoolName += "_" + recordType
+		}
+		if _, ok := poolNames[UniquePoolName]; ok {
+			log.Warningf("Skipping WideIP Pool with duplicate dataServerName %v in ExternalDNS %v/%v",
+				pl.DataServerName, edns.Namespace, edns.Name)
+			continue
+		}
+		poolNames[UniquePoolName] = struct{}{}
 		log.Debugf("Processing WideIP Pool: %v", UniquePoolName)
 		pool := GSLBPool{
 			Name:          UniquePoolName,
@@ -2850,6 +3630,7 @@ func (ctlr *Controller) processExternalDNS(edns *cisapiv1.ExternalDNS, isDelete
 			PriorityOrder: pl.PriorityOrder,
 			DataServer:    pl.DataServerName,
 			Ratio:         pl.Ratio,
+			TTL:           edns.Spec.TTL,
 		}
 		if pl.LBModeFallback != "" {
 			pool.LBModeFallBack = pl.LBModeFallback
@@ -2858,12 +3639,26 @@ func (ctlr *Controller) processExternalDNS(edns *cisapiv1.ExternalDNS, isDelete
 		}
 
 		if pl.DNSRecordType == "" {
-			pool.RecordType = "A"
+			pool.RecordType = recordType
 		}
 		if pl.LoadBalanceMethod == "" {
 			pool.LBMethod = "round-robin"
 		}
-		for _, partition := range partitions {
+		preGTMServerName := ""
+		if ctlr.Agent.ccclGTMAgent {
+			preGTMServerName = fmt.Sprintf("%v:", pl.DataServerName)
+		}
+		// Virtual servers of the members are registered on the data server by CIS of other clusters,
+		// without members the virtual servers of this CIS are expected with the same names on the data server
+		poolPartitions := partitions
+		if len(pl.Members) > 0 {
+			poolPartitions = nil
+		}
+		for _, member := range pl.Members {
+			log.Debugf("Adding WideIP Pool Member: %v", member)
+			pool.Members = append(pool.Members, preGTMServerName+member)
+		}
+		for _, partition := range poolPartitions {
 			rsMap := ctlr.resources.getPartitionResourceMap(partition)
 
 			for vsName, vs := range rsMap {
@@ -2879,9 +3674,8 @@ func (ctlr *Controller) processExternalDNS(edns *cisapiv1.ExternalDNS, isDelete
 					if vs.MetaData.Protocol == "http" && (vs.MetaData.httpTraffic == TLSRedirectInsecure || vs.MetaData.httpTraffic == TLSAllowInsecure) {
 						continue
 					}
-					preGTMServerName := ""
-					if ctlr.Agent.ccclGTMAgent {
-						preGTMServerName = fmt.Sprintf("%v:", pl.DataServerName)
+					if !isVirtualOfRecordType(vs, pool.RecordType) {
+						continue
 					}
 					// add only one VS member to pool.
 					if len(pool.Members) > 0 && strings.HasPrefix(vsName, "ingress_link_") {
@@ -2902,55 +3696,44 @@ func (ctlr *Controller) processExternalDNS(edns *cisapiv1.ExternalDNS, isDelete
 		if len(pl.Monitors) > 0 {
 			var monitors []Monitor
 			for i, monitor := range pl.Monitors {
-				monitors = append(monitors,
-					Monitor{
-						Name:      fmt.Sprintf("%s_monitor%d", UniquePoolName, i),
-						Partition: "Common",
-						Type:      monitor.Type,
-						Interval:  monitor.Interval,
-						Send:      monitor.Send,
-						Recv:      monitor.Recv,
-						Timeout:   monitor.Timeout})
+				monitors = append(monitors, newGSLBMonitor(fmt.Sprintf("%s_monitor%d", UniquePoolName, i), monitor))
 			}
 			pool.Monitors = monitors
 
 		} else if pl.Monitor.Type != "" {
 			// TODO: Need to change to DEFAULT_PARTITION from Common, once Agent starts to support DEFAULT_PARTITION
-			var monitors []Monitor
-
-			if pl.Monitor.Type == "http" || pl.Monitor.Type == "https" {
-				monitors = append(monitors,
-					Monitor{
-						Name:      UniquePoolName + "_monitor",
-						Partition: "Common",
-						Type:      pl.Monitor.Type,
-						Interval:  pl.Monitor.Interval,
-						Send:      pl.Monitor.Send,
-						Recv:      pl.Monitor.Recv,
-						Timeout:   pl.Monitor.Timeout,
-					})
-			} else {
-				monitors = append(monitors,
-					Monitor{
-						Name:      UniquePoolName + "_monitor",
-						Partition: "Common",
-						Type:      pl.Monitor.Type,
-						Interval:  pl.Monitor.Interval,
-						Timeout:   pl.Monitor.Timeout,
-					})
+			monitor := newGSLBMonitor(UniquePoolName+"_monitor", pl.Monitor)
+			if pl.Monitor.Type != "http" && pl.Monitor.Type != "https" {
+				monitor.Send = ""
+				monitor.Recv = ""
 			}
-			pool.Monitors = monitors
+			pool.Monitors = []Monitor{monitor}
 		}
-		wip.Pools = append(wip.Pools, pool)
-	}
-	if _, ok := ctlr.resources.gtmConfig[DEFAULT_GTM_PARTITION]; !ok {
-		ctlr.resources.gtmConfig[DEFAULT_GTM_PARTITION] = GTMPartitionConfig{
-			WideIPs: make(map[string]WideIP),
+		if isValidMinimumMonitors(pl.MinimumMonitors, len(pool.Monitors), pool.Name, edns.Namespace, edns.Name) {
+			minimum := pl.MinimumMonitors
+			pool.MinimumMonitors = &minimum
 		}
+		pools = append(pools, pool)
 	}
+	return pools
+}
 
-	ctlr.resources.gtmConfig[DEFAULT_GTM_PARTITION].WideIPs[wip.DomainName] = wip
-	return
+// newGSLBMonitor returns the GTM monitor of the ExternalDNS pool, SNI is sent only by the https monitor
+func newGSLBMonitor(name string, monitor cisapiv1.Monitor) Monitor {
+	gslbMonitor := Monitor{
+		Name:         name,
+		Partition:    "Common",
+		Type:         monitor.Type,
+		Interval:     monitor.Interval,
+		Send:         monitor.Send,
+		Recv:         monitor.Recv,
+		Timeout:      monitor.Timeout,
+		ProbeTimeout: monitor.ProbeTimeout,
+	}
+	if monitor.Type == "https" {
+		gslbMonitor.SNIServerName = monitor.SNIServerName
+	}
+	return gslbMonitor
 }
 
 func (ctlr *Controller) getAllExternalDNS(namespace string) []*cisapiv1.ExternalDNS {
@@ -2976,6 +3759,10 @@ func (ctlr *Controller) getAllExternalDNS(namespace string) []*cisapiv1.External
 
 	for _, obj := range orderedEDNSs {
 		edns := obj.(*cisapiv1.ExternalDNS)
+		// Skip the ExternalDNSs being deleted
+		if edns.DeletionTimestamp != nil {
+			continue
+		}
 		allEDNS = append(allEDNS, edns)
 	}
 
@@ -3014,6 +3801,142 @@ func (ctlr *Controller) ProcessAssociatedExternalDNS(hostnames []string) {
 	}
 }
 
+// getAllEDNSFromMonitoredNamespaces returns list of all ExternalDNS in the monitored namespaces.
+func (ctlr *Controller) getAllEDNSFromMonitoredNamespaces() []*cisapiv1.ExternalDNS {
+	var allEDNS []*cisapiv1.ExternalDNS
+	if ctlr.watchingAllNamespaces() {
+		return ctlr.getAllExternalDNS("")
+	}
+	for ns := range ctlr.namespaces {
+		allEDNS = append(allEDNS, ctlr.getAllExternalDNS(ns)...)
+	}
+	return allEDNS
+}
+
+// processWideIPForVirtualServer creates, updates or removes the WideIP for the host of a VirtualServer
+// annotated with a GSLB zone, when WideIP auto generation is enabled. The WideIP generated from the
+// VirtualServer is removed when the VirtualServer is deleted or its annotations are removed.
+func (ctlr *Controller) processWideIPForVirtualServer(virtual *cisapiv1.VirtualServer, isVSDeleted bool) {
+	if !ctlr.autoGenerateWideIP || virtual.Spec.Host == "" {
+		return
+	}
+	if _, ok := virtual.Annotations[GSLBZoneAnnotation]; !ok && !ctlr.hasAutoGeneratedWideIP(virtual.Spec.Host) {
+		return
+	}
+	var deletedVS *cisapiv1.VirtualServer
+	if isVSDeleted {
+		deletedVS = virtual
+	}
+	ctlr.processAutoGeneratedWideIP(virtual.Spec.Host, deletedVS)
+}
+
+// processAutoGeneratedWideIP generates the WideIP of the host from the annotated VirtualServers serving the
+// host, the deleted VirtualServer is skipped. The oldest VirtualServer with valid annotations is used so that
+// the WideIP doesn't depend on the order in which the VirtualServers are processed, as the pool members are
+// discovered from all the virtuals of the host. The generated WideIP is removed when no such VirtualServer exists.
+func (ctlr *Controller) processAutoGeneratedWideIP(host string, deletedVS *cisapiv1.VirtualServer) {
+	for _, edns := range ctlr.getAllEDNSFromMonitoredNamespaces() {
+		if edns.Spec.DomainName == host {
+			log.Debugf("ExternalDNS %v/%v found for host %v, skipping WideIP generation from VirtualServers",
+				edns.Namespace, edns.Name, host)
+			return
+		}
+	}
+
+	for _, vs := range ctlr.getGSLBVirtualServersForHost(host, deletedVS) {
+		edns, err := newExternalDNSForVirtualServer(vs)
+		if err != nil {
+			log.Errorf("Unable to generate WideIP for VirtualServer %v/%v: %v", vs.Namespace, vs.Name, err)
+			continue
+		}
+		ctlr.processExternalDNS(edns, false)
+		return
+	}
+
+	if gtmPartitionConfig, ok := ctlr.resources.gtmConfig[DEFAULT_GTM_PARTITION]; ok {
+		if wip, found := gtmPartitionConfig.WideIPs[host]; found && isAutoGeneratedWideIP(wip) {
+			log.Debugf("Removing WideIP %v generated from VirtualServers", host)
+			delete(gtmPartitionConfig.WideIPs, host)
+		}
+	}
+}
+
+// getGSLBVirtualServersForHost returns the VirtualServers of the host annotated with a GSLB zone sorted by the
+// creation timestamp, the deleted VirtualServer and the VirtualServers being deleted are skipped.
+func (ctlr *Controller) getGSLBVirtualServersForHost(host string,
+	deletedVS *cisapiv1.VirtualServer) []*cisapiv1.VirtualServer {
+	var virtuals []*cisapiv1.VirtualServer
+	for _, vs := range ctlr.getAllVSFromMonitoredNamespaces() {
+		if deletedVS != nil && vs.Namespace == deletedVS.Namespace && vs.Name == deletedVS.Name {
+			continue
+		}
+		if _, ok := vs.Annotations[GSLBZoneAnnotation]; !ok || vs.Spec.Host != host || vs.DeletionTimestamp != nil {
+			continue
+		}
+		if !ctlr.isManagedResourceClass(vs.Annotations) {
+			continue
+		}
+		virtuals = append(virtuals, vs)
+	}
+	sort.Slice(virtuals, func(i, j int) bool {
+		if !virtuals[i].CreationTimestamp.Equal(&virtuals[j].CreationTimestamp) {
+			return virtuals[i].CreationTimestamp.Before(&virtuals[j].CreationTimestamp)
+		}
+		if virtuals[i].Namespace != virtuals[j].Namespace {
+			return virtuals[i].Namespace < virtuals[j].Namespace
+		}
+		return virtuals[i].Name < virtuals[j].Name
+	})
+	return virtuals
+}
+
+// hasAutoGeneratedWideIP returns true if the WideIP of the host is generated from VirtualServer annotations
+func (ctlr *Controller) hasAutoGeneratedWideIP(host string) bool {
+	if gtmPartitionConfig, ok := ctlr.resources.gtmConfig[DEFAULT_GTM_PARTITION]; ok {
+		if wip, found := gtmPartitionConfig.WideIPs[host]; found {
+			return isAutoGeneratedWideIP(wip)
+		}
+	}
+	return false
+}
+
+// newExternalDNSForVirtualServer frames the ExternalDNS equivalent of the GSLB annotations of a VirtualServer
+func newExternalDNSForVirtualServer(virtual *cisapiv1.VirtualServer) (*cisapiv1.ExternalDNS, error) {
+	host := virtual.Spec.Host
+	zone := strings.TrimSuffix(strings.TrimPrefix(virtual.Annotations[GSLBZoneAnnotation], "."), ".")
+	if zone == "" {
+		return nil, fmt.Errorf("empty value for annotation %v", GSLBZoneAnnotation)
+	}
+	if host != zone && !strings.HasSuffix(host, "."+zone) {
+		return nil, fmt.Errorf("host %v does not belong to GSLB zone %v", host, zone)
+	}
+	dataServer := virtual.Annotations[GSLBDataServerAnnotation]
+	if dataServer == "" {
+		return nil, fmt.Errorf("missing annotation %v", GSLBDataServerAnnotation)
+	}
+
+	return &cisapiv1.ExternalDNS{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      virtual.Name,
+			Namespace: virtual.Namespace,
+			UID:       types.UID(autoGeneratedWideIPUIDPrefix + host),
+		},
+		Spec: cisapiv1.ExternalDNSSpec{
+			DomainName: host,
+			Pools: []cisapiv1.DNSPool{
+				{
+					DataServerName: dataServer,
+				},
+			},
+		},
+	}, nil
+}
+
+// isAutoGeneratedWideIP returns true if the WideIP is generated from VirtualServer annotations
+func isAutoGeneratedWideIP(wip WideIP) bool {
+	return strings.HasPrefix(wip.UID, autoGeneratedWideIPUIDPrefix)
+}
+
 // Validate certificate hostname
 func checkCertificateHost(host string, certificate []byte, key []byte) bool {
 	cert, certErr := tls.X509KeyPair(certificate, key)
@@ -3026,6 +3949,11 @@ func checkCertificateHost(host string, certificate []byte, key []byte) bool {
 		log.Errorf("failed to parse certificate; %s", err)
 		return false
 	}
+	return checkX509CertificateHost(host, x509cert)
+}
+
+// checkX509CertificateHost validates the hostname of the certificate with the host
+func checkX509CertificateHost(host string, x509cert *x509.Certificate) bool {
 	if len(x509cert.DNSNames) > 0 {
 		ok := x509cert.VerifyHostname(host)
 		if ok != nil {
@@ -3200,6 +4128,8 @@ func (ctlr *Controller) processIngressLink(
 				vkey)
 			return nil
 		}
+		// iRules not in the allow-list are stripped with the strip action
+		ingLink = ctlr.getAllowedIngressLink(ingLink)
 	}
 	var ingLinks []*cisapiv1.IngressLink
 	if ingLink.Spec.Host != "" {
@@ -3262,6 +4192,8 @@ func (ctlr *Controller) processIngressLink(
 		}
 		ip = ingLink.Spec.VirtualServerAddress
 	}
+	// pin the virtual to the route domain
+	ip = appendRouteDomain(ip, ingLink.Spec.RouteDomain)
 	if isILDeleted {
 		var delRes []string
 		rsMap := ctlr.resources.getPartitionResourceMap(partition)
@@ -3345,6 +4277,11 @@ func (ctlr *Controller) processIngressLink(
 			ip,
 			port.Port,
 		)
+		//set additionalVirtualAddresses if present
+		for _, address := range ingLink.Spec.AdditionalVirtualServerAddresses {
+			rsCfg.Virtual.AdditionalVirtualAddresses = append(rsCfg.Virtual.AdditionalVirtualAddresses,
+				appendRouteDomain(address, ingLink.Spec.RouteDomain))
+		}
 		svcPort := intstr.IntOrString{IntVal: port.Port}
 		pool := Pool{
 			Name: formatPoolName(
@@ -3664,10 +4601,21 @@ func getNodeport(svc *v1.Service, servicePort int32) int32 {
 	return 0
 }
 
-// Update virtual server status with virtual server address
-func (ctlr *Controller) updateVirtualServerStatus(vs *cisapiv1.VirtualServer, ip string, statusOk string) {
+// Update virtual server status with virtual server address and the virtual servers on BIG-IP
+func (ctlr *Controller) updateVirtualServerStatus(vs *cisapiv1.VirtualServer, ip string, statusOk string,
+	bigipVirtuals []cisapiv1.BigIPVirtualServer) {
 	// Set the vs status to include the virtual IP address
-	vsStatus := cisapiv1.VirtualServerStatus{VSAddress: ip, StatusOk: statusOk}
+	vsStatus := cisapiv1.VirtualServerStatus{VSAddress: ip, StatusOk: statusOk, Conditions: vs.Status.Conditions,
+		BigIPVirtualServers: bigipVirtuals, ObservedGeneration: vs.Status.ObservedGeneration}
+	if statusOk == "Ok" {
+		// declaration of the virtual server is posted successfully
+		meta.RemoveStatusCondition(&vsStatus.Conditions, ConditionDeclarationError)
+		vsStatus.ObservedGeneration = vs.Generation
+	}
+	// effective policy merged from the default, namespace and VirtualServer policies for debugging
+	if plc, err := ctlr.getPolicyFromVirtuals([]*cisapiv1.VirtualServer{vs}); err == nil && plc != nil {
+		vsStatus.EffectivePolicy = plc.Spec.DeepCopy()
+	}
 	log.Debugf("Updating VirtualServer Status with %v for resource name:%v , namespace: %v", vsStatus, vs.Name, vs.Namespace)
 	vs.Status = vsStatus
 	vs.Status.VSAddress = ip
@@ -3679,10 +4627,59 @@ func (ctlr *Controller) updateVirtualServerStatus(vs *cisapiv1.VirtualServer, ip
 	}
 }
 
+// updateVirtualServerConflictStatus sets the PathConflict condition of the VirtualServers discarded for
+// the paths claimed by other VirtualServers and clears it once the VirtualServers are associated again
+func (ctlr *Controller) updateVirtualServerConflictStatus(virtuals []*cisapiv1.VirtualServer, conflicts []vsPathConflict) {
+	for _, conflict := range conflicts {
+		if _, ok := getIngressNameForVirtualServer(conflict.virtual); ok {
+			continue
+		}
+		msg := fmt.Sprintf("Path %v of host %v is already claimed by VirtualServer %v/%v",
+			conflict.path, conflict.host, conflict.owner.Namespace, conflict.owner.Name)
+		cond := meta.FindStatusCondition(conflict.virtual.Status.Conditions, VSConditionPathConflict)
+		if cond != nil && cond.Status == metav1.ConditionTrue && cond.Message == msg {
+			continue
+		}
+		vs := conflict.virtual.DeepCopy()
+		meta.SetStatusCondition(&vs.Status.Conditions, metav1.Condition{
+			Type:               VSConditionPathConflict,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: vs.Generation,
+			Reason:             "DuplicatePath",
+			Message:            msg,
+		})
+		ctlr.updateVirtualServerConditions(vs)
+	}
+	for _, vs := range virtuals {
+		if meta.FindStatusCondition(vs.Status.Conditions, VSConditionPathConflict) == nil {
+			continue
+		}
+		vs = vs.DeepCopy()
+		meta.RemoveStatusCondition(&vs.Status.Conditions, VSConditionPathConflict)
+		ctlr.updateVirtualServerConditions(vs)
+	}
+}
+
+// updateVirtualServerConditions updates the conditions in the virtual server status
+func (ctlr *Controller) updateVirtualServerConditions(vs *cisapiv1.VirtualServer) {
+	log.Debugf("Updating VirtualServer Status with conditions %v for resource name:%v , namespace: %v",
+		vs.Status.Conditions, vs.Name, vs.Namespace)
+	_, updateErr := ctlr.kubeCRClient.CisV1().VirtualServers(vs.ObjectMeta.Namespace).UpdateStatus(context.TODO(), vs, metav1.UpdateOptions{})
+	if nil != updateErr {
+		log.Debugf("Error while updating virtual server status:%v", updateErr)
+	}
+}
+
 // Update Transport server status with virtual server address
 func (ctlr *Controller) updateTransportServerStatus(ts *cisapiv1.TransportServer, ip string, statusOk string) {
 	// Set the vs status to include the virtual IP address
-	tsStatus := cisapiv1.TransportServerStatus{VSAddress: ip, StatusOk: statusOk}
+	tsStatus := cisapiv1.TransportServerStatus{VSAddress: ip, StatusOk: statusOk, Conditions: ts.Status.Conditions,
+		ObservedGeneration: ts.Status.ObservedGeneration}
+	if statusOk == "Ok" {
+		// declaration of the transport server is posted successfully
+		meta.RemoveStatusCondition(&tsStatus.Conditions, ConditionDeclarationError)
+		tsStatus.ObservedGeneration = ts.Generation
+	}
 	log.Debugf("Updating VirtualServer Status with %v for resource name:%v , namespace: %v", tsStatus, ts.Name, ts.Namespace)
 	ts.Status = tsStatus
 	ts.Status.VSAddress = ip
@@ -3694,6 +4691,61 @@ func (ctlr *Controller) updateTransportServerStatus(ts *cisapiv1.TransportServer
 	}
 }
 
+// updateTransportServerConditions updates the conditions in the transport server status
+func (ctlr *Controller) updateTransportServerConditions(ts *cisapiv1.TransportServer) {
+	log.Debugf("Updating TransportServer Status with conditions %v for resource name:%v , namespace: %v",
+		ts.Status.Conditions, ts.Name, ts.Namespace)
+	_, updateErr := ctlr.kubeCRClient.CisV1().TransportServers(ts.ObjectMeta.Namespace).UpdateStatus(context.TODO(), ts, metav1.UpdateOptions{})
+	if nil != updateErr {
+		log.Debugf("Error while updating transport server status:%v", updateErr)
+	}
+}
+
+// setResourceCondition sets the condition in the status of the VirtualServer or TransportServer,
+// status is updated only when the condition is changed
+func (ctlr *Controller) setResourceCondition(rsc metav1.Object, cond metav1.Condition) {
+	isSet := func(conditions []metav1.Condition) bool {
+		found := meta.FindStatusCondition(conditions, cond.Type)
+		return found != nil && found.Status == cond.Status && found.Reason == cond.Reason && found.Message == cond.Message
+	}
+	switch obj := rsc.(type) {
+	case *cisapiv1.VirtualServer:
+		if isSet(obj.Status.Conditions) {
+			return
+		}
+		vs := obj.DeepCopy()
+		meta.SetStatusCondition(&vs.Status.Conditions, cond)
+		ctlr.updateVirtualServerConditions(vs)
+	case *cisapiv1.TransportServer:
+		if isSet(obj.Status.Conditions) {
+			return
+		}
+		ts := obj.DeepCopy()
+		meta.SetStatusCondition(&ts.Status.Conditions, cond)
+		ctlr.updateTransportServerConditions(ts)
+	}
+}
+
+// removeResourceCondition removes the condition from the status of the VirtualServer or TransportServer
+func (ctlr *Controller) removeResourceCondition(rsc metav1.Object, condType string) {
+	switch obj := rsc.(type) {
+	case *cisapiv1.VirtualServer:
+		if meta.FindStatusCondition(obj.Status.Conditions, condType) == nil {
+			return
+		}
+		vs := obj.DeepCopy()
+		meta.RemoveStatusCondition(&vs.Status.Conditions, condType)
+		ctlr.updateVirtualServerConditions(vs)
+	case *cisapiv1.TransportServer:
+		if meta.FindStatusCondition(obj.Status.Conditions, condType) == nil {
+			return
+		}
+		ts := obj.DeepCopy()
+		meta.RemoveStatusCondition(&ts.Status.Conditions, condType)
+		ctlr.updateTransportServerConditions(ts)
+	}
+}
+
 // Update ingresslink status with virtual server address
 func (ctlr *Controller) updateIngressLinkStatus(il *cisapiv1.IngressLink, ip string) {
 	// Set the vs status to include the virtual IP address
@@ -3910,6 +4962,8 @@ func (ctlr *Controller) processConfigMap(cm *v1.ConfigMap, isDelete bool) (error
 				oldClusterRatio[cluster] = *ratio
 			}
 		}
+		// Store old backup clusters before processing multiClusterConfig
+		oldBackupClusters := ctlr.getBackupClusterNames()
 		// Read multi-cluster config from extended CM
 		err := ctlr.readMultiClusterConfigFromGlobalCM(es.HAClusterConfig, es.ExternalClustersConfig)
 		ctlr.checkSecondaryCISConfig()
@@ -3917,6 +4971,10 @@ func (ctlr *Controller) processConfigMap(cm *v1.ConfigMap, isDelete bool) (error
 		if err != nil {
 			return err, false
 		}
+		// Routes are re-processed for the updated backup clusters same as the updated cluster ratio
+		if !reflect.DeepEqual(oldBackupClusters, ctlr.getBackupClusterNames()) {
+			clusterRatioUpdated = true
+		}
 		// Log cluster ratios used
 		if len(ctlr.clusterRatio) > 0 {
 			ratioKeyValues := ""
@@ -3937,6 +4995,16 @@ func (ctlr *Controller) processConfigMap(cm *v1.ConfigMap, isDelete bool) (error
 			log.Debugf("[MultiCluster] Cluster ratios:%s", ratioKeyValues)
 		}
 	}
+	// allow-list of the BIG-IP objects referred by the resources and the shared bundle of the iRules and profiles
+	// are set with the global extended configMap
+	if ctlr.isGlobalExtendedCM(cm) {
+		if err := ctlr.setBigIPAllowList(es.BigIPAllowList, isDelete); err != nil {
+			return fmt.Errorf("invalid extended spec in configmap: %v/%v error: %v", cm.Namespace, cm.Name, err), false
+		}
+		if err := ctlr.setSharedBundle(es.SharedBundle, isDelete); err != nil {
+			return fmt.Errorf("invalid extended spec in configmap: %v/%v error: %v", cm.Namespace, cm.Name, err), false
+		}
+	}
 	// Process the routeSpec defined in extended configMap
 	if ctlr.mode == OpenShiftMode {
 		if ctlr.isGlobalExtendedCM(cm) {
@@ -3946,60 +5014,69 @@ func (ctlr *Controller) processConfigMap(cm *v1.ConfigMap, isDelete bool) (error
 		}
 	} else {
 		// Re-process all the VS and TS resources
-		for resRef, _ := range ctlr.resources.processedNativeResources {
-			var rs interface{}
-			var exists bool
-			var err error
-			var crInf *CRInformer
-			crInf, _ = ctlr.crInformers[""]
-			switch resRef.kind {
-			case VirtualServer:
-				// Fetch the latest VS
-				if crInf != nil {
-					rs, exists, err = crInf.vsInformer.GetIndexer().GetByKey(
-						fmt.Sprintf("%s/%s", resRef.namespace, resRef.name))
-				} else if _, ok := ctlr.crInformers[resRef.namespace]; ok {
-					rs, exists, err = ctlr.crInformers[resRef.namespace].vsInformer.GetIndexer().GetByKey(
-						fmt.Sprintf("%s/%s", resRef.namespace, resRef.name))
-				}
-			case TransportServer:
-				// Fetch the latest TS
-				if crInf != nil {
-					rs, exists, err = crInf.tsInformer.GetIndexer().GetByKey(
-						fmt.Sprintf("%s/%s", resRef.namespace, resRef.name))
-				} else if _, ok := ctlr.crInformers[resRef.namespace]; ok {
-					rs, exists, err = ctlr.crInformers[resRef.namespace].tsInformer.GetIndexer().GetByKey(
-						fmt.Sprintf("%s/%s", resRef.namespace, resRef.name))
-				}
-			default:
-				// Don't process other resources except VS and TS
-				continue
-			}
-			// Skip processing if resource could not be fetched
-			if !exists || err != nil {
-				continue
-			}
-			key := &rqKey{
-				namespace: resRef.namespace,
-				kind:      resRef.kind,
-				rscName:   resRef.name,
-				rsc:       rs,
-				event:     Update,
+		ctlr.enqueueProcessedVirtualAndTransportServers()
+	}
+	return nil, true
+}
+
+// enqueueProcessedVirtualAndTransportServers enqueues all the processed VS and TS resources for re-processing
+func (ctlr *Controller) enqueueProcessedVirtualAndTransportServers() {
+	for resRef, _ := range ctlr.resources.processedNativeResources {
+		var rs interface{}
+		var exists bool
+		var err error
+		var crInf *CRInformer
+		crInf, _ = ctlr.crInformers[""]
+		switch resRef.kind {
+		case VirtualServer:
+			// Fetch the latest VS
+			if crInf != nil {
+				rs, exists, err = crInf.vsInformer.GetIndexer().GetByKey(
+					fmt.Sprintf("%s/%s", resRef.namespace, resRef.name))
+			} else if _, ok := ctlr.crInformers[resRef.namespace]; ok {
+				rs, exists, err = ctlr.crInformers[resRef.namespace].vsInformer.GetIndexer().GetByKey(
+					fmt.Sprintf("%s/%s", resRef.namespace, resRef.name))
+			}
+		case TransportServer:
+			// Fetch the latest TS
+			if crInf != nil {
+				rs, exists, err = crInf.tsInformer.GetIndexer().GetByKey(
+					fmt.Sprintf("%s/%s", resRef.namespace, resRef.name))
+			} else if _, ok := ctlr.crInformers[resRef.namespace]; ok {
+				rs, exists, err = ctlr.crInformers[resRef.namespace].tsInformer.GetIndexer().GetByKey(
+					fmt.Sprintf("%s/%s", resRef.namespace, resRef.name))
 			}
-			ctlr.resourceQueue.Add(key)
+		default:
+			// Don't process other resources except VS and TS
+			continue
+		}
+		// Skip processing if resource could not be fetched
+		if !exists || err != nil {
+			continue
+		}
+		key := &rqKey{
+			namespace: resRef.namespace,
+			kind:      resRef.kind,
+			rscName:   resRef.name,
+			rsc:       rs,
+			event:     Update,
 		}
+		ctlr.resourceQueue.Add(key)
 	}
-	return nil, true
 }
 
 // getPolicyFromLBService gets the policy attached to the service and returns it
 func (ctlr *Controller) getPolicyFromLBService(svc *v1.Service) (*cisapiv1.Policy, error) {
 	plcName, found := svc.Annotations[LBServicePolicyNameAnnotation]
 	if !found || plcName == "" {
-		return nil, nil
+		return ctlr.getEffectivePolicy(svc.Namespace, nil), nil
 	}
 	ns := svc.Namespace
-	return ctlr.getPolicy(ns, plcName)
+	plc, err := ctlr.getPolicy(ns, plcName)
+	if err != nil {
+		return nil, err
+	}
+	return ctlr.getEffectivePolicy(ns, plc), nil
 }
 
 // skipVirtual return true if virtuals don't have any common HTTP/HTTPS ports, else returns false
@@ -4078,6 +5155,56 @@ func fetchPortString(port intstr.IntOrString) string {
 	return ""
 }
 
+// getVirtualServersForMaintenanceSecret returns the VirtualServers in maintenance
+// responding with the maintenance response of the secret
+func (ctlr *Controller) getVirtualServersForMaintenanceSecret(secret *v1.Secret) []*cisapiv1.VirtualServer {
+	var virtuals []*cisapiv1.VirtualServer
+	for _, vs := range ctlr.getAllVirtualServers(secret.Namespace) {
+		if isMaintenanceModeEnabled(vs) && vs.Spec.MaintenanceMode.Secret == secret.Name {
+			virtuals = append(virtuals, vs)
+		}
+	}
+	return virtuals
+}
+
+// refersMonitorSecret checks whether any of the monitors presents the client certificate of the secret
+func refersMonitorSecret(secret *v1.Secret, monitors ...cisapiv1.Monitor) bool {
+	for _, monitor := range monitors {
+		if monitor.ClientTLS == secret.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// getVirtualServersForMonitorSecret returns the VirtualServers with the pool monitors using the secret as clientTLS
+func (ctlr *Controller) getVirtualServersForMonitorSecret(secret *v1.Secret) []*cisapiv1.VirtualServer {
+	var virtuals []*cisapiv1.VirtualServer
+	for _, vs := range ctlr.getAllVirtualServers(secret.Namespace) {
+		refers := refersMonitorSecret(secret, vs.Spec.DefaultPool.Monitors...)
+		for _, pool := range vs.Spec.Pools {
+			if refersMonitorSecret(secret, append([]cisapiv1.Monitor{pool.Monitor}, pool.Monitors...)...) {
+				refers = true
+			}
+		}
+		if refers {
+			virtuals = append(virtuals, vs)
+		}
+	}
+	return virtuals
+}
+
+// getTransportServersForMonitorSecret returns the TransportServers with the pool monitors using the secret as clientTLS
+func (ctlr *Controller) getTransportServersForMonitorSecret(secret *v1.Secret) []*cisapiv1.TransportServer {
+	var virtuals []*cisapiv1.TransportServer
+	for _, ts := range ctlr.getAllTransportServers(secret.Namespace) {
+		if refersMonitorSecret(secret, append([]cisapiv1.Monitor{ts.Spec.Pool.Monitor}, ts.Spec.Pool.Monitors...)...) {
+			virtuals = append(virtuals, ts)
+		}
+	}
+	return virtuals
+}
+
 // fetch list of tls profiles for given secret.
 func (ctlr *Controller) getTLSProfilesForSecret(secret *v1.Secret) []*cisapiv1.TLSProfile {
 	var allTLSProfiles []*cisapiv1.TLSProfile
@@ -4100,7 +5227,9 @@ func (ctlr *Controller) getTLSProfilesForSecret(secret *v1.Secret) []*cisapiv1.T
 	for _, obj := range orderedTLS {
 		tlsProfile := obj.(*cisapiv1.TLSProfile)
 		if tlsProfile.Spec.TLS.Reference == Secret {
-			if len(tlsProfile.Spec.TLS.ClientSSLs) > 0 {
+			if refersPassphraseSecret(tlsProfile, secret.Name) {
+				allTLSProfiles = append(allTLSProfiles, tlsProfile)
+			} else if len(tlsProfile.Spec.TLS.ClientSSLs) > 0 {
 				for _, name := range tlsProfile.Spec.TLS.ClientSSLs {
 					if name == secret.Name {
 						allTLSProfiles = append(allTLSProfiles, tlsProfile)
@@ -1,14 +1,18 @@
 package controller
 
 import (
+	"bytes"
 	"container/list"
 	"context"
 	"encoding/json"
+	"errors"
 	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/clustermanager"
 	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/resource"
 	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/teem"
+	mockhc "github.com/f5devcentral/mockhttpclient"
 	routeapi "github.com/openshift/api/route/v1"
 	fakeRouteClient "github.com/openshift/client-go/route/clientset/versioned/fake"
+	"io/ioutil"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/util/workqueue"
 	"net/http"
@@ -23,14 +27,20 @@ import (
 	crdfake "github.com/F5Networks/k8s-bigip-ctlr/v2/config/client/clientset/versioned/fake"
 	cisinfv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/client/informers/externalversions/cis/v1"
 	apm "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/appmanager"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 
 	cisapiv1 "github.com/F5Networks/k8s-bigip-ctlr/v2/config/apis/cis/v1"
 	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/test"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -733,6 +743,59 @@ var _ = Describe("Worker Tests", func() {
 				Expect(virts[1].Spec.Host).To(Equal("test3.com"), "Wrong Virtual Server Host")
 			})
 
+			It("HostGroup across namespaces with duplicate path", func() {
+				vrt2.Spec.HostGroup = "test"
+				vrt2.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Minute))
+				vrt3.Spec.HostGroup = "test"
+				vrt3.Spec.Pools = append(vrt3.Spec.Pools, cisapiv1.Pool{Path: "/path", Service: "svc"})
+				vrt3.Namespace = "alpha"
+				vrt3.CreationTimestamp = metav1.NewTime(time.Now())
+				vrt4.Spec.HostGroup = "test"
+				vrt4.Namespace = "beta"
+				vrt4.CreationTimestamp = metav1.NewTime(time.Now())
+
+				VSSpecProps := &VSSpecProperties{}
+				virts := mockCtlr.getAssociatedVirtualServers(vrt2,
+					[]*cisapiv1.VirtualServer{vrt3, vrt4, vrt2},
+					false, VSSpecProps)
+				Expect(len(virts)).To(Equal(2), "Wrong number of Virtual Servers")
+				Expect(virts[0].Name).To(Equal("SampleVS2"), "Wrong Virtual Server")
+				Expect(virts[1].Name).To(Equal("SampleVS4"), "Wrong Virtual Server")
+				Expect(len(VSSpecProps.PathConflicts)).To(Equal(1), "Path conflict not detected")
+				Expect(VSSpecProps.PathConflicts[0].virtual.Name).To(Equal("SampleVS3"))
+				Expect(VSSpecProps.PathConflicts[0].owner.Name).To(Equal("SampleVS2"))
+				Expect(VSSpecProps.PathConflicts[0].path).To(Equal("/path"))
+
+				// VirtualServer with the same name in other namespace is not skipped on deletion
+				vrt4.Name = vrt2.Name
+				virts = mockCtlr.getAssociatedVirtualServers(vrt2,
+					[]*cisapiv1.VirtualServer{vrt3, vrt4, vrt2},
+					true, &VSSpecProperties{})
+				Expect(len(virts)).To(Equal(2), "Wrong number of Virtual Servers")
+				Expect(virts[0].Namespace).To(Equal("alpha"), "Wrong Virtual Server")
+				Expect(virts[1].Namespace).To(Equal("beta"), "Wrong Virtual Server")
+			})
+
+			It("Path conflict status condition", func() {
+				mockCtlr.updateVirtualServerConflictStatus(nil, []vsPathConflict{
+					{virtual: vrt1, owner: vrt2, host: "test.com", path: "/path"},
+				})
+				vs, err := mockCtlr.kubeCRClient.CisV1().VirtualServers(namespace).Get(
+					context.TODO(), vrt1.Name, metav1.GetOptions{})
+				Expect(err).To(BeNil())
+				Expect(len(vs.Status.Conditions)).To(Equal(1), "Path conflict condition not set")
+				Expect(vs.Status.Conditions[0].Type).To(Equal(VSConditionPathConflict))
+				Expect(vs.Status.Conditions[0].Status).To(Equal(metav1.ConditionTrue))
+				Expect(vs.Status.Conditions[0].Message).To(ContainSubstring(namespace + "/SampleVS2"))
+
+				// condition is cleared once the VirtualServer is associated
+				mockCtlr.updateVirtualServerConflictStatus([]*cisapiv1.VirtualServer{vs}, nil)
+				vs, err = mockCtlr.kubeCRClient.CisV1().VirtualServers(namespace).Get(
+					context.TODO(), vrt1.Name, metav1.GetOptions{})
+				Expect(err).To(BeNil())
+				Expect(len(vs.Status.Conditions)).To(Equal(0), "Path conflict condition not cleared")
+			})
+
 			It("Host Group with IP Address Only specified once", func() {
 				vrt2.Spec.HostGroup = "test"
 				vrt3.Spec.HostGroup = "test"
@@ -915,6 +978,110 @@ var _ = Describe("Worker Tests", func() {
 			Expect(len(mems)).To(Equal(0), "Wrong set of Endpoints for NodePort")
 		})
 
+		It("Backup cluster pool members", func() {
+			mockCtlr.PoolMemberType = Cluster
+			mockCtlr.multiClusterMode = StandAloneCIS
+			mockCtlr.haModeType = StandBy
+			mockCtlr.multiClusterPoolInformers = make(map[string]map[string]*MultiClusterPoolInformer)
+			svcPorts := []v1.ServicePort{{Port: 80, Name: "port0"}}
+			svc := test.NewService("svc1", "1", namespace, v1.ServiceTypeClusterIP, svcPorts)
+			svc.Spec.ClusterIP = "None"
+			mockCtlr.addService(svc)
+			mockCtlr.addEndpoints(test.NewEndpoints("svc1", "1", "node0", namespace, []string{"10.1.1.1"},
+				[]string{}, convertSvcPortsToEndpointPorts(svcPorts)))
+
+			// Same service in the backup cluster
+			mockCtlr.multiClusterConfigs.ClusterConfigs["cluster2"] = clustermanager.ClusterConfig{
+				KubeClient: k8sfake.NewSimpleClientset()}
+			mockCtlr.resources.externalClustersConfig["cluster2"] = ExternalClusterConfig{
+				ClusterName: "cluster2", Secret: "default/kubeconfig2", Backup: true}
+			_ = mockCtlr.addMultiClusterNamespacedInformers("cluster2", namespace, nil, false)
+			poolInf, found := mockCtlr.getNamespaceMultiClusterPoolInformer(namespace, "cluster2")
+			Expect(found).To(BeTrue(), "Informer not found for backup cluster")
+			_ = poolInf.svcInformer.GetIndexer().Add(svc)
+			_ = poolInf.epsInformer.GetIndexer().Add(test.NewEndpoints("svc1", "1", "node0", namespace,
+				[]string{"10.2.1.1"}, []string{}, convertSvcPortsToEndpointPorts(svcPorts)))
+
+			pool := Pool{
+				ServiceName:      "svc1",
+				ServiceNamespace: namespace,
+				ServicePort:      intstr.FromInt(80),
+			}
+			mockCtlr.updatePoolMembersForResources(&pool)
+			Expect(pool.Members).To(Equal([]PoolMember{
+				{Address: "10.1.1.1", Port: 80, Session: "user-enabled", PriorityGroup: PrimaryPriorityGroup},
+				{Address: "10.2.1.1", Port: 80, Session: "user-enabled", PriorityGroup: BackupPriorityGroup},
+			}), "Wrong set of pool members with backup cluster")
+
+			// Backup cluster members are not used in ratio mode
+			mockCtlr.haModeType = Ratio
+			Expect(mockCtlr.isBackupCluster("cluster2")).To(BeFalse())
+
+			// Priority groups are not set without backup cluster members
+			mockCtlr.haModeType = StandBy
+			mockCtlr.resources.externalClustersConfig["cluster2"] = ExternalClusterConfig{
+				ClusterName: "cluster2", Secret: "default/kubeconfig2"}
+			mockCtlr.updatePoolMembersForResources(&pool)
+			Expect(pool.Members).To(Equal([]PoolMember{
+				{Address: "10.1.1.1", Port: 80, Session: "user-enabled"},
+			}), "Wrong set of pool members without backup cluster")
+		})
+
+		It("Cluster ratio of the down clusters", func() {
+			mockCtlr.haModeType = Ratio
+			mockCtlr.resources = NewResourceStore()
+			localRatio, ratio2, ratio3 := 1, 2, 3
+			mockCtlr.clusterRatio = map[string]*int{"": &localRatio, "cluster2": &ratio2, "cluster3": &ratio3}
+			downClient := k8sfake.NewSimpleClientset()
+			downClient.PrependReactor("list", "nodes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, errors.New("connection refused")
+			})
+			mockCtlr.multiClusterConfigs.ClusterConfigs["cluster2"] = clustermanager.ClusterConfig{
+				KubeClient: k8sfake.NewSimpleClientset()}
+			mockCtlr.multiClusterConfigs.ClusterConfigs["cluster3"] = clustermanager.ClusterConfig{KubeClient: downClient}
+
+			mockCtlr.processClusterHealthStatus()
+			Expect(mockCtlr.downClusters).To(Equal(map[string]struct{}{"cluster3": {}}), "Down cluster not detected")
+			Expect(mockCtlr.getClusterRatio("")).To(Equal(1))
+			Expect(mockCtlr.getClusterRatio("cluster2")).To(Equal(2))
+			Expect(mockCtlr.getClusterRatio("cluster3")).To(BeZero(), "Down cluster not excluded")
+
+			// Cluster is up again
+			mockCtlr.multiClusterConfigs.ClusterConfigs["cluster3"] = clustermanager.ClusterConfig{
+				KubeClient: k8sfake.NewSimpleClientset()}
+			mockCtlr.processClusterHealthStatus()
+			Expect(mockCtlr.downClusters).To(BeEmpty(), "Cluster not detected as up")
+			Expect(mockCtlr.getClusterRatio("cluster3")).To(Equal(3))
+		})
+
+		It("Primary cluster lease", func() {
+			_, _, err := parseLeaseEndPoint("lease://kube-system")
+			Expect(err).NotTo(BeNil(), "Invalid lease endpoint accepted")
+			namespace, name, err := parseLeaseEndPoint("lease://kube-system/cis-primary")
+			Expect(err).To(BeNil())
+			Expect(namespace).To(Equal("kube-system"))
+			Expect(name).To(Equal("cis-primary"))
+
+			// Secondary CIS without the lease of the primary CIS
+			probeParams := &mockCtlr.Agent.PostManager.PrimaryClusterHealthProbeParams
+			probeParams.leaseClient = mockCtlr.kubeClient
+			probeParams.leaseNamespace = namespace
+			probeParams.leaseName = name
+			Expect(mockCtlr.Agent.getPrimaryClusterHealthStatusFromLease()).To(BeFalse(), "Missing lease not detected")
+
+			// Lease renewed by the primary CIS
+			Expect(mockCtlr.renewLease(namespace, name, "cluster1")).To(BeNil(), "Lease not created")
+			Expect(mockCtlr.renewLease(namespace, name, "cluster1")).To(BeNil(), "Lease not renewed")
+			lease, err := mockCtlr.kubeClient.CoordinationV1().Leases(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+			Expect(err).To(BeNil())
+			Expect(*lease.Spec.HolderIdentity).To(Equal("cluster1"))
+			Expect(mockCtlr.Agent.getPrimaryClusterHealthStatusFromLease()).To(BeTrue(), "Renewed lease not detected")
+
+			// Lease not renewed for the lease duration
+			probeParams.leaseObservedTime = time.Now().Add(-DefaultLeaseDuration * time.Second)
+			Expect(mockCtlr.Agent.getPrimaryClusterHealthStatusFromLease()).To(BeFalse(), "Expired lease not detected")
+		})
+
 	})
 
 	Describe("Processing Resources", func() {
@@ -994,7 +1161,10 @@ var _ = Describe("Worker Tests", func() {
 				"SampleEDNS",
 				namespace,
 				cisapiv1.ExternalDNSSpec{
-					DomainName: "test.com",
+					DomainName:         "test.com",
+					PersistenceEnabled: true,
+					PersistCidrIPv4:    24,
+					TTL:                60,
 					Pools: []cisapiv1.DNSPool{
 						{
 							DataServerName: "DataServer",
@@ -1014,6 +1184,11 @@ var _ = Describe("Worker Tests", func() {
 			Expect(len(gtmConfig["test.com"].Pools)).To(Equal(1))
 			Expect(len(gtmConfig["test.com"].Pools[0].Members)).To(Equal(0))
 			Expect(gtmConfig["test.com"].Pools[0].Ratio).To(Equal(4))
+			Expect(gtmConfig["test.com"].Pools[0].TTL).To(BeEquivalentTo(60))
+			Expect(gtmConfig["test.com"].PersistenceEnabled).To(BeTrue())
+			Expect(gtmConfig["test.com"].PersistCidrIPv4).To(BeEquivalentTo(24))
+			Expect(gtmConfig["test.com"].PersistCidrIPv6).To(BeEquivalentTo(128))
+			Expect(gtmConfig["test.com"].TTLPersistence).To(BeEquivalentTo(3600))
 
 			zero := 0
 			mockCtlr.resources.ltmConfig["default"] = &PartitionConfig{ResourceMap: make(ResourceMap), Priority: &zero}
@@ -1033,6 +1208,170 @@ var _ = Describe("Worker Tests", func() {
 			Expect(len(gtmConfig)).To(Equal(0))
 		})
 
+		It("Processing dual-stack External DNS", func() {
+			mockCtlr.resources.Init()
+			DEFAULT_PARTITION = "default"
+			DEFAULT_GTM_PARTITION = "default_gtm"
+			mockCtlr.TeemData = &teem.TeemsData{
+				ResourceType: teem.ResourceTypes{
+					ExternalDNS: make(map[string]int),
+				},
+			}
+			mockCtlr.Partition = "default"
+			zero := 0
+			mockCtlr.resources.ltmConfig["default"] = &PartitionConfig{ResourceMap: make(ResourceMap), Priority: &zero}
+			for name, addr := range map[string]string{"crd_10_1_1_1_443": "10.1.1.1", "crd_2001_db8__1_443": "2001:db8::1"} {
+				rsCfg := &ResourceConfig{MetaData: metaData{hosts: []string{"test.com"}}}
+				rsCfg.Virtual.SetVirtualAddress(addr, 443)
+				mockCtlr.resources.ltmConfig["default"].ResourceMap[name] = rsCfg
+			}
+
+			newEDNS := test.NewExternalDNS(
+				"SampleEDNS",
+				namespace,
+				cisapiv1.ExternalDNSSpec{
+					DomainName:    "test.com",
+					DNSRecordType: DNSRecordTypeDualStack,
+					Pools: []cisapiv1.DNSPool{
+						{DataServerName: "DataServer"},
+						{DataServerName: "/Common/DataServer2", DNSRecordType: "AAAA", Members: []string{"/test/Shared/vs1"}},
+					},
+				})
+			mockCtlr.processExternalDNS(newEDNS, false)
+			wideIPs := mockCtlr.resources.gtmConfig[DEFAULT_GTM_PARTITION].WideIPs
+			Expect(len(wideIPs)).To(Equal(2))
+			aWIP := wideIPs["test.com"]
+			Expect(aWIP.RecordType).To(Equal("A"))
+			Expect(len(aWIP.Pools)).To(Equal(1))
+			Expect(aWIP.Pools[0].RecordType).To(Equal("A"))
+			Expect(aWIP.Pools[0].Members).To(Equal([]string{"/default/Shared/crd_10_1_1_1_443"}))
+			aaaaWIP := wideIPs[getDualStackWideIPKey("test.com")]
+			Expect(aaaaWIP.RecordType).To(Equal("AAAA"))
+			Expect(aaaaWIP.DomainName).To(Equal("test.com"))
+			Expect(len(aaaaWIP.Pools)).To(Equal(2))
+			Expect(aaaaWIP.Pools[0].RecordType).To(Equal("AAAA"))
+			Expect(aaaaWIP.Pools[0].Name).NotTo(Equal(aWIP.Pools[0].Name))
+			Expect(aaaaWIP.Pools[0].Members).To(Equal([]string{"/default/Shared/crd_2001_db8__1_443"}))
+			Expect(aaaaWIP.Pools[1].Members).To(Equal([]string{"/test/Shared/vs1"}))
+
+			// AAAA WideIP is removed once the ExternalDNS is no longer dual-stack
+			newEDNS.Spec.DNSRecordType = "A"
+			mockCtlr.processExternalDNS(newEDNS, false)
+			wideIPs = mockCtlr.resources.gtmConfig[DEFAULT_GTM_PARTITION].WideIPs
+			Expect(len(wideIPs)).To(Equal(1))
+			Expect(wideIPs["test.com"].Pools[0].Members).To(Equal([]string{"/default/Shared/crd_10_1_1_1_443"}))
+
+			newEDNS.Spec.DNSRecordType = DNSRecordTypeDualStack
+			mockCtlr.processExternalDNS(newEDNS, false)
+			mockCtlr.processExternalDNS(newEDNS, true)
+			Expect(len(mockCtlr.resources.gtmConfig[DEFAULT_GTM_PARTITION].WideIPs)).To(Equal(0))
+		})
+
+		It("Processing External DNS with GTM monitors", func() {
+			mockCtlr.resources.Init()
+			DEFAULT_PARTITION = "default"
+			DEFAULT_GTM_PARTITION = "default_gtm"
+			mockCtlr.TeemData = &teem.TeemsData{
+				ResourceType: teem.ResourceTypes{
+					ExternalDNS: make(map[string]int),
+				},
+			}
+			mockCtlr.Partition = "default"
+
+			newEDNS := test.NewExternalDNS(
+				"SampleEDNS",
+				namespace,
+				cisapiv1.ExternalDNSSpec{
+					DomainName: "test.com",
+					Pools: []cisapiv1.DNSPool{
+						{
+							DataServerName: "DataServer",
+							Monitors: []cisapiv1.Monitor{
+								{
+									Type:          "https",
+									Send:          "GET /health HTTP/1.1\r\nHost: test.com\r\n\r\n",
+									Recv:          "200 OK",
+									Interval:      10,
+									Timeout:       31,
+									ProbeTimeout:  5,
+									SNIServerName: "test.com",
+								},
+								{
+									Type:          "tcp",
+									Interval:      10,
+									Timeout:       31,
+									SNIServerName: "test.com",
+								},
+							},
+							MinimumMonitors: intstr.FromInt(1),
+						},
+					},
+				})
+			mockCtlr.processExternalDNS(newEDNS, false)
+			pool := mockCtlr.resources.gtmConfig[DEFAULT_GTM_PARTITION].WideIPs["test.com"].Pools[0]
+			Expect(len(pool.Monitors)).To(Equal(2))
+			Expect(pool.Monitors[0].ProbeTimeout).To(Equal(5))
+			Expect(pool.Monitors[0].SNIServerName).To(Equal("test.com"))
+			Expect(pool.Monitors[1].SNIServerName).To(BeEmpty(), "SNI set for the tcp monitor")
+			Expect(*pool.MinimumMonitors).To(Equal(intstr.FromInt(1)))
+
+			// minimumMonitors more than the monitors of the pool is not set
+			newEDNS.Spec.Pools[0].MinimumMonitors = intstr.FromInt(3)
+			mockCtlr.processExternalDNS(newEDNS, false)
+			pool = mockCtlr.resources.gtmConfig[DEFAULT_GTM_PARTITION].WideIPs["test.com"].Pools[0]
+			Expect(pool.MinimumMonitors).To(BeNil())
+
+			newEDNS.Spec.Pools[0].MinimumMonitors = intstr.FromString("all")
+			mockCtlr.processExternalDNS(newEDNS, false)
+			pool = mockCtlr.resources.gtmConfig[DEFAULT_GTM_PARTITION].WideIPs["test.com"].Pools[0]
+			Expect(pool.MinimumMonitors.StrVal).To(Equal("all"))
+		})
+
+		It("Processing External DNS with pools of other clusters", func() {
+			mockCtlr.resources.Init()
+			DEFAULT_PARTITION = "default"
+			DEFAULT_GTM_PARTITION = "default_gtm"
+			mockCtlr.TeemData = &teem.TeemsData{
+				ResourceType: teem.ResourceTypes{
+					ExternalDNS: make(map[string]int),
+				},
+			}
+			mockCtlr.Partition = "default"
+			zero := 0
+			mockCtlr.resources.ltmConfig["default"] = &PartitionConfig{ResourceMap: make(ResourceMap), Priority: &zero}
+			mockCtlr.resources.ltmConfig["default"].ResourceMap["SampleVS"] = &ResourceConfig{
+				MetaData: metaData{
+					hosts: []string{"test.com"},
+				},
+			}
+
+			newEDNS := test.NewExternalDNS(
+				"SampleEDNS",
+				namespace,
+				cisapiv1.ExternalDNSSpec{
+					DomainName: "test.com",
+					Pools: []cisapiv1.DNSPool{
+						{DataServerName: "/Common/DataServer"},
+						// virtual servers with the same names on the BIG-IP of the other cluster
+						{DataServerName: "/Common/DataServer2"},
+						{
+							DataServerName: "/Common/DataServer3",
+							Members:        []string{"/test/Shared/crd_10_1_1_3_80", "/test/Shared/crd_10_1_1_4_80"},
+						},
+						{DataServerName: "/Common/DataServer3"},
+					},
+				})
+			mockCtlr.processExternalDNS(newEDNS, false)
+			pools := mockCtlr.resources.gtmConfig[DEFAULT_GTM_PARTITION].WideIPs["test.com"].Pools
+			Expect(len(pools)).To(Equal(3), "Pool with duplicate dataServerName not skipped")
+			Expect(pools[0].Members).To(Equal([]string{"/default/Shared/SampleVS"}))
+			Expect(pools[1].Members).To(Equal([]string{"/default/Shared/SampleVS"}))
+			Expect(pools[1].DataServer).To(Equal("/Common/DataServer2"))
+			Expect(pools[1].Name).To(Equal(pools[0].Name+"_Common_DataServer2"), "Pool name not unique")
+			Expect(pools[2].Members).To(Equal([]string{"/test/Shared/crd_10_1_1_3_80", "/test/Shared/crd_10_1_1_4_80"}))
+			Expect(pools[2].Name).To(Equal(pools[0].Name+"_Common_DataServer3"), "Pool name not unique")
+		})
+
 		It("Processing IngressLink", func() {
 			// Creation of IngressLink
 			fooPorts := []v1.ServicePort{
@@ -1389,6 +1728,794 @@ var _ = Describe("Worker Tests", func() {
 		})
 	})
 
+	Describe("Default and namespace Policy", func() {
+		var defaultPlc, plc *cisapiv1.Policy
+		BeforeEach(func() {
+			defaultPlc = test.NewPolicy("default-policy", namespace, cisapiv1.PolicySpec{
+				L7Policies: cisapiv1.L7PolicySpec{WAF: "/Common/WAF_Default"},
+				Profiles: cisapiv1.ProfileSpec{
+					TCP:         cisapiv1.ProfileTCP{Client: "/Common/f5-tcp-lan", Server: "/Common/f5-tcp-wan"},
+					LogProfiles: []string{"/Common/Log all requests"},
+				},
+			})
+			plc = test.NewPolicy("policy", namespace, cisapiv1.PolicySpec{
+				SNAT: "auto",
+				Profiles: cisapiv1.ProfileSpec{
+					TCP: cisapiv1.ProfileTCP{Client: "/Common/f5-tcp-mobile"},
+				},
+			})
+			mockCtlr.defaultPolicy = namespace + "/default-policy"
+		})
+
+		It("Merges the default policy into the policy of the virtuals", func() {
+			// Default policy not found
+			Expect(mockCtlr.mergeDefaultPolicy(nil)).To(BeNil())
+			Expect(mockCtlr.mergeDefaultPolicy(plc)).To(Equal(plc))
+
+			mockCtlr.addPolicy(defaultPlc)
+			mockCtlr.addPolicy(plc)
+			Expect(mockCtlr.mergeDefaultPolicy(nil)).To(Equal(defaultPlc), "Default policy should be used")
+			mergedPlc := mockCtlr.mergeDefaultPolicy(plc)
+			Expect(mergedPlc.Name).To(Equal("policy"))
+			Expect(mergedPlc.Spec.SNAT).To(Equal("auto"))
+			Expect(mergedPlc.Spec.L7Policies.WAF).To(Equal("/Common/WAF_Default"))
+			Expect(mergedPlc.Spec.Profiles.TCP).To(Equal(cisapiv1.ProfileTCP{
+				Client: "/Common/f5-tcp-mobile", Server: "/Common/f5-tcp-wan"}))
+			Expect(mergedPlc.Spec.Profiles.LogProfiles).To(Equal([]string{"/Common/Log all requests"}))
+			Expect(plc.Spec.L7Policies.WAF).To(BeEmpty(), "Policy in cache should not be modified")
+
+			vrt1.Spec.PolicyName = "policy"
+			mergedPlc, err := mockCtlr.getPolicyFromVirtuals([]*cisapiv1.VirtualServer{vrt1})
+			Expect(err).To(BeNil())
+			Expect(mergedPlc.Spec.L7Policies.WAF).To(Equal("/Common/WAF_Default"))
+			vrt1.Spec.PolicyName = ""
+			mergedPlc, err = mockCtlr.getPolicyFromVirtuals([]*cisapiv1.VirtualServer{vrt1})
+			Expect(err).To(BeNil())
+			Expect(mergedPlc).To(Equal(defaultPlc))
+
+			Expect(mockCtlr.isDefaultPolicy(defaultPlc)).To(BeTrue())
+			Expect(mockCtlr.isDefaultPolicy(plc)).To(BeFalse())
+		})
+
+		It("Merges the layered policies with the override precedence", func() {
+			nsPlc := test.NewPolicy("ns-policy", namespace, cisapiv1.PolicySpec{
+				SNAT:       "none",
+				L7Policies: cisapiv1.L7PolicySpec{WAF: "/Common/WAF_Namespace"},
+				Profiles: cisapiv1.ProfileSpec{
+					TCP: cisapiv1.ProfileTCP{Server: "/Common/f5-tcp-progressive"},
+				},
+			})
+			nsPlc.Annotations = map[string]string{NamespacePolicyAnnotation: "true"}
+			mockCtlr.addPolicy(defaultPlc)
+			mockCtlr.addPolicy(plc)
+			mockCtlr.addPolicy(nsPlc)
+			Expect(mockCtlr.getNamespacePolicy(namespace)).To(Equal(nsPlc))
+
+			// VirtualServer policy takes precedence over the namespace policy and the default policy
+			effectivePlc := mockCtlr.getEffectivePolicy(namespace, plc)
+			Expect(effectivePlc.Spec.SNAT).To(Equal("auto"))
+			Expect(effectivePlc.Spec.L7Policies.WAF).To(Equal("/Common/WAF_Namespace"))
+			Expect(effectivePlc.Spec.Profiles.TCP).To(Equal(cisapiv1.ProfileTCP{
+				Client: "/Common/f5-tcp-mobile", Server: "/Common/f5-tcp-progressive"}))
+			Expect(effectivePlc.Spec.Profiles.LogProfiles).To(Equal([]string{"/Common/Log all requests"}))
+
+			effectivePlc = mockCtlr.getEffectivePolicy(namespace, nil)
+			Expect(effectivePlc.Name).To(Equal("ns-policy"))
+			Expect(effectivePlc.Spec.SNAT).To(Equal("none"))
+			Expect(effectivePlc.Spec.Profiles.TCP.Client).To(Equal("/Common/f5-tcp-lan"))
+			// route groups are not namespace scoped
+			Expect(mockCtlr.mergeDefaultPolicy(nil)).To(Equal(defaultPlc))
+
+			// VirtualServers of the namespace are affected by the namespace policy
+			vrt1.Spec.PolicyName = "policy"
+			vrt2 := test.NewVirtualServer("vrt2", namespace, cisapiv1.VirtualServerSpec{})
+			mockCtlr.addVirtualServer(vrt1)
+			mockCtlr.addVirtualServer(vrt2)
+			Expect(len(mockCtlr.getVirtualsForCustomPolicy(nsPlc))).To(Equal(2))
+			Expect(mockCtlr.getVirtualsForCustomPolicy(plc)).To(Equal([]*cisapiv1.VirtualServer{vrt1}))
+
+			// Effective policy is exposed in the VirtualServer status
+			mockCtlr.updateVirtualServerStatus(vrt1, "10.1.1.1", "Ok", nil)
+			vs, err := mockCtlr.kubeCRClient.CisV1().VirtualServers(namespace).Get(context.TODO(), vrt1.Name, metav1.GetOptions{})
+			Expect(err).To(BeNil())
+			Expect(vs.Status.EffectivePolicy).NotTo(BeNil())
+			Expect(*vs.Status.EffectivePolicy).To(Equal(mockCtlr.getEffectivePolicy(namespace, plc).Spec))
+		})
+	})
+
+	Describe("Default TLSProfile", func() {
+		It("Uses the default TLSProfile of the namespace for the VirtualServers without TLSProfile", func() {
+			tlsProf := test.NewTLSProfile("default-tls", namespace, cisapiv1.TLSProfileSpec{
+				Hosts: []string{"*.test.com"},
+				TLS: cisapiv1.TLS{
+					Termination: TLSEdge,
+					ClientSSL:   "/Common/clientssl",
+					Reference:   BIGIP,
+				},
+			})
+			vrt1.Spec.Host = "foo.test.com"
+			vrt2 := test.NewVirtualServer("vrt2", namespace, cisapiv1.VirtualServerSpec{Host: "foo.example.com"})
+			vrt3 := test.NewVirtualServer("vrt3", namespace, cisapiv1.VirtualServerSpec{})
+			mockCtlr.addVirtualServer(vrt1)
+			mockCtlr.addVirtualServer(vrt2)
+			mockCtlr.addVirtualServer(vrt3)
+
+			// TLSProfile without annotation is not the default TLSProfile
+			mockCtlr.addTLSProfile(tlsProf)
+			Expect(mockCtlr.getDefaultTLSProfile(namespace)).To(BeNil())
+			Expect(mockCtlr.getVirtualsForTLSProfile(tlsProf)).To(BeNil())
+
+			tlsProf = tlsProf.DeepCopy()
+			tlsProf.Annotations = map[string]string{DefaultTLSProfileAnnotation: "true"}
+			mockCtlr.addTLSProfile(tlsProf)
+			Expect(mockCtlr.getDefaultTLSProfile(namespace)).To(Equal(tlsProf))
+
+			virtuals := mockCtlr.getDefaultTLSVirtualServers([]*cisapiv1.VirtualServer{vrt1, vrt2, vrt3})
+			Expect(virtuals[0].Spec.TLSProfileName).To(Equal("default-tls"), "Default TLSProfile should be used")
+			Expect(virtuals[1].Spec.TLSProfileName).To(BeEmpty(), "Default TLSProfile doesn't match the host")
+			Expect(virtuals[2].Spec.TLSProfileName).To(BeEmpty(), "VirtualServer without host")
+			Expect(vrt1.Spec.TLSProfileName).To(BeEmpty(), "VirtualServer in cache should not be modified")
+			Expect(mockCtlr.getTLSProfileForVirtualServer(virtuals[0], namespace)).To(Equal(tlsProf))
+
+			// VirtualServers with host and without TLSProfile are affected by the default TLSProfile
+			Expect(len(mockCtlr.getVirtualsForTLSProfile(tlsProf))).To(Equal(2))
+		})
+	})
+
+	Describe("Address conflicts", func() {
+		It("Rejects the newer resources claiming the same address and port", func() {
+			newResourceConfig := func(name, kind string) *ResourceConfig {
+				rsCfg := &ResourceConfig{}
+				rsCfg.Virtual.SetVirtualAddress("1.2.3.4", 80)
+				rsCfg.MetaData.baseResources = map[string]string{namespace + "/" + name: kind}
+				return rsCfg
+			}
+			vrt1.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+			mockCtlr.addVirtualServer(vrt1)
+			ts := test.NewTransportServer("ts1", namespace, cisapiv1.TransportServerSpec{
+				VirtualServerAddress: "1.2.3.4",
+				VirtualServerPort:    80,
+			})
+			ts.CreationTimestamp = metav1.Now()
+			_, _ = mockCtlr.kubeCRClient.CisV1().TransportServers(namespace).Create(context.TODO(), ts, metav1.CreateOptions{})
+			mockCtlr.addTransportServer(ts)
+			rsMap := mockCtlr.resources.getPartitionResourceMap("test")
+
+			// Newer TransportServer processed first claims the address
+			tsCfg := newResourceConfig(ts.Name, TransportServer)
+			Expect(mockCtlr.claimVirtualAddress("test", "ts_virtual_80", tsCfg)).To(BeTrue())
+			rsMap["ts_virtual_80"] = tsCfg
+
+			// Older VirtualServer takes over the address and the TransportServer is marked
+			vsCfg := newResourceConfig(vrt1.Name, VirtualServer)
+			Expect(mockCtlr.claimVirtualAddress("test", "crd_1_2_3_4_80", vsCfg)).To(BeTrue())
+			rsMap["crd_1_2_3_4_80"] = vsCfg
+			Expect(rsMap).NotTo(HaveKey("ts_virtual_80"), "Virtual of the newer claim not removed")
+			Expect(mockCtlr.resources.addressConflicts).To(HaveKeyWithValue(
+				resourceRef{kind: TransportServer, namespace: namespace, name: ts.Name}, "1.2.3.4:80"))
+			tsObj, err := mockCtlr.kubeCRClient.CisV1().TransportServers(namespace).Get(
+				context.TODO(), ts.Name, metav1.GetOptions{})
+			Expect(err).To(BeNil())
+			Expect(len(tsObj.Status.Conditions)).To(Equal(1))
+			Expect(tsObj.Status.Conditions[0].Type).To(Equal(ConditionAddressConflict))
+			Expect(tsObj.Status.Conditions[0].Message).To(Equal(
+				"Address 1.2.3.4:80 is already claimed by VirtualServer default/SampleVS"))
+
+			// TransportServer is rejected while the VirtualServer claims the address
+			Expect(mockCtlr.claimVirtualAddress("test", "ts_virtual_80", newResourceConfig(ts.Name,
+				TransportServer))).To(BeFalse())
+			Expect(isClaimedByOtherResources(vsCfg, map[string]string{namespace + "/" + ts.Name: TransportServer})).To(
+				BeTrue(), "Virtual of the VirtualServer should not be deleted for the TransportServer")
+
+			// TransportServer is processed again once the address is released
+			queued := mockCtlr.resourceQueue.Len()
+			mockCtlr.enqueueReleasedAddressConflicts()
+			Expect(mockCtlr.resourceQueue.Len()).To(Equal(queued))
+			delete(rsMap, "crd_1_2_3_4_80")
+			mockCtlr.enqueueReleasedAddressConflicts()
+			Expect(mockCtlr.resourceQueue.Len()).To(Equal(queued + 1))
+			Expect(mockCtlr.resources.addressConflicts).To(BeEmpty())
+		})
+	})
+
+	Describe("AS3 errors", func() {
+		It("Records the AS3 errors on the resources of the objects in the error", func() {
+			recorder := record.NewFakeRecorder(10)
+			mockCtlr.eventRecorder = recorder
+			mockCtlr.addVirtualServer(vrt1)
+			rsCfg := &ResourceConfig{}
+			rsCfg.Virtual.Name = "crd_1_2_3_4_80"
+			rsCfg.Pools = Pools{{Name: "svc1_80_default"}}
+			rsCfg.MetaData.baseResources = map[string]string{namespace + "/" + vrt1.Name: VirtualServer}
+			mockCtlr.resources.getPartitionResourceMap("test")["crd_1_2_3_4_80"] = rsCfg
+			mockCtlr.enqueueReq(ResourceConfigRequest{ltmConfig: mockCtlr.resources.getLTMConfigSnapshot()})
+			rm := mockCtlr.requestQueue.Back().Value.(requestMeta)
+
+			// Objects of the other tenants and unknown objects are not mapped
+			Expect(getAS3ErrorResources(rm.as3Objects["test"], "test", "/other/Shared/crd_1_2_3_4_80: invalid")).To(BeEmpty())
+			Expect(getAS3ErrorResources(rm.as3Objects["test"], "test", "/test/Shared/unknown: invalid")).To(BeEmpty())
+			// Objects derived from the virtual are mapped to the resources of the virtual
+			Expect(getAS3ErrorResources(rm.as3Objects["test"], "test", "/test/Shared/crd_1_2_3_4_80_tls_client: invalid")).To(
+				Equal(map[string]string{namespace + "/" + vrt1.Name: VirtualServer}))
+
+			msg := "declaration failed: 01020066:3: The requested Pool (/test/Shared/svc1_80_default) already exists"
+			mockCtlr.updateAS3ErrorStatus(rm, "test", msg)
+			vs, err := mockCtlr.kubeCRClient.CisV1().VirtualServers(namespace).Get(context.TODO(), vrt1.Name, metav1.GetOptions{})
+			Expect(err).To(BeNil())
+			Expect(len(vs.Status.Conditions)).To(Equal(1))
+			Expect(vs.Status.Conditions[0].Type).To(Equal(ConditionDeclarationError))
+			Expect(vs.Status.Conditions[0].Message).To(Equal(msg))
+			Expect(recorder.Events).To(Receive(Equal("Warning AS3Error " + msg)))
+
+			// Error is cleared once the declaration is posted
+			mockCtlr.updateVirtualServerStatus(vs, "1.2.3.4", "Ok", nil)
+			vs, _ = mockCtlr.kubeCRClient.CisV1().VirtualServers(namespace).Get(context.TODO(), vrt1.Name, metav1.GetOptions{})
+			Expect(vs.Status.Conditions).To(BeEmpty())
+		})
+	})
+
+	Describe("BIG-IP virtual servers status", func() {
+		It("Exposes the BIG-IP objects of the VirtualServer in the status", func() {
+			vrt1.Generation = 2
+			mockCtlr.addVirtualServer(vrt1)
+			rsCfg := &ResourceConfig{}
+			rsCfg.Virtual.Name = "crd_1_2_3_4_443"
+			rsCfg.Virtual.SetVirtualAddress("1.2.3.4", 443)
+			rsCfg.Virtual.Policies = []nameRef{{Name: "crd_1_2_3_4_443_test_policy", Partition: "test"}}
+			rsCfg.Virtual.WAF = "/Common/WAF_Policy"
+			rsCfg.Virtual.Profiles = ProfileRefs{
+				{Name: "clientssl", Partition: "Common", Context: CustomProfileClient, BigIPProfile: true},
+				{Name: "secret1", Partition: "test", Context: CustomProfileServer},
+			}
+			rsCfg.Virtual.ProfileMultiplex = "/Common/oneconnect"
+			rsCfg.MetaData.baseResources = map[string]string{namespace + "/" + vrt1.Name: VirtualServer}
+			httpCfg := &ResourceConfig{}
+			httpCfg.Virtual.Name = "crd_1_2_3_4_80"
+			httpCfg.Virtual.SetVirtualAddress("1.2.3.4", 80)
+			httpCfg.MetaData.baseResources = map[string]string{namespace + "/" + vrt1.Name: VirtualServer}
+			mockCtlr.resources.getPartitionResourceMap("test")["crd_1_2_3_4_443"] = rsCfg
+			mockCtlr.resources.getPartitionResourceMap("test")["crd_1_2_3_4_80"] = httpCfg
+			mockCtlr.enqueueReq(ResourceConfigRequest{ltmConfig: mockCtlr.resources.getLTMConfigSnapshot()})
+			rm := mockCtlr.requestQueue.Back().Value.(requestMeta)
+
+			bigipVirtuals := []cisapiv1.BigIPVirtualServer{
+				{
+					Partition:   "test",
+					Name:        "crd_1_2_3_4_443",
+					Destination: "1.2.3.4:443",
+					Policies:    []string{"/test/Shared/crd_1_2_3_4_443_test_policy", "/Common/WAF_Policy"},
+					Profiles:    []string{"/Common/clientssl", "/test/Shared/crd_1_2_3_4_443_tls_client", "/Common/oneconnect"},
+				},
+				{Partition: "test", Name: "crd_1_2_3_4_80", Destination: "1.2.3.4:80"},
+			}
+			Expect(rm.virtualServers[namespace+"/"+vrt1.Name]).To(Equal(bigipVirtuals))
+
+			mockCtlr.updateVirtualServerStatus(vrt1, "1.2.3.4", "Ok", rm.virtualServers[namespace+"/"+vrt1.Name])
+			vs, err := mockCtlr.kubeCRClient.CisV1().VirtualServers(namespace).Get(context.TODO(), vrt1.Name, metav1.GetOptions{})
+			Expect(err).To(BeNil())
+			Expect(vs.Status.BigIPVirtualServers).To(Equal(bigipVirtuals))
+			Expect(vs.Status.ObservedGeneration).To(BeEquivalentTo(2))
+
+			// Observed generation is retained when the declaration is not posted
+			vs.Generation = 3
+			mockCtlr.updateVirtualServerStatus(vs, "1.2.3.4", "Error", nil)
+			vs, _ = mockCtlr.kubeCRClient.CisV1().VirtualServers(namespace).Get(context.TODO(), vrt1.Name, metav1.GetOptions{})
+			Expect(vs.Status.ObservedGeneration).To(BeEquivalentTo(2))
+		})
+	})
+
+	Describe("Resource quarantine", func() {
+		It("Excludes the resources of the AS3 errors until they are updated", func() {
+			recorder := record.NewFakeRecorder(10)
+			mockCtlr.eventRecorder = recorder
+			vrt1.Generation = 1
+			mockCtlr.addVirtualServer(vrt1)
+			rscs := map[string]string{namespace + "/" + vrt1.Name: VirtualServer}
+			msg := "declaration failed: 01020066:3: The requested Pool (/test/Shared/svc1_80_default) already exists"
+
+			// Resources are not quarantined unless enabled
+			mockCtlr.quarantineResources(rscs, msg)
+			Expect(mockCtlr.filterQuarantinedVirtualServers([]*cisapiv1.VirtualServer{vrt1})).To(HaveLen(1))
+
+			mockCtlr.enableQuarantine = true
+			queued := mockCtlr.resourceQueue.Len()
+			mockCtlr.quarantineResources(rscs, msg)
+			Expect(mockCtlr.resourceQueue.Len()).To(Equal(queued + 1))
+			Expect(mockCtlr.filterQuarantinedVirtualServers([]*cisapiv1.VirtualServer{vrt1})).To(BeEmpty())
+			vs, err := mockCtlr.kubeCRClient.CisV1().VirtualServers(namespace).Get(context.TODO(), vrt1.Name, metav1.GetOptions{})
+			Expect(err).To(BeNil())
+			Expect(len(vs.Status.Conditions)).To(Equal(1))
+			Expect(vs.Status.Conditions[0].Type).To(Equal(ConditionQuarantined))
+			Expect(vs.Status.Conditions[0].Message).To(Equal(msg))
+			Expect(recorder.Events).To(Receive(Equal("Warning Quarantined " + msg)))
+
+			// Quarantined resource is not quarantined again for the same generation
+			mockCtlr.quarantineResources(rscs, msg)
+			Expect(recorder.Events).NotTo(Receive())
+
+			// Resource is released once its spec is updated
+			updatedVS := vs.DeepCopy()
+			updatedVS.Generation = 2
+			cusInf, _ := mockCtlr.getNamespacedCRInformer(namespace)
+			_ = cusInf.vsInformer.GetStore().Update(updatedVS)
+			Expect(mockCtlr.filterQuarantinedVirtualServers([]*cisapiv1.VirtualServer{updatedVS})).To(HaveLen(1))
+			vs, _ = mockCtlr.kubeCRClient.CisV1().VirtualServers(namespace).Get(context.TODO(), vrt1.Name, metav1.GetOptions{})
+			Expect(vs.Status.Conditions).To(BeEmpty())
+		})
+	})
+
+	Describe("BIG-IP references", func() {
+		It("Parses the BIG-IP references of the namespaces", func() {
+			refs, err := ParseBigIPReferences([]string{namespace + ":/Common/team-a-", "*:/Common/f5-"})
+			Expect(err).To(BeNil())
+			Expect(refs.isAllowed(namespace, "/Common/team-a-irule")).To(BeTrue())
+			Expect(refs.isAllowed("other", "/Common/team-a-irule")).To(BeFalse())
+			Expect(refs.isAllowed("other", "/Common/f5-tcp")).To(BeTrue())
+			Expect(BigIPReferences(nil).isAllowed("other", "/Common/irule")).To(BeTrue())
+			for _, ref := range []string{namespace, ":/Common", namespace + ":Common"} {
+				_, err = ParseBigIPReferences([]string{ref})
+				Expect(err).NotTo(BeNil(), ref)
+			}
+		})
+		It("Excludes the resources referring the BIG-IP objects not allowed for their namespaces", func() {
+			mockCtlr.addVirtualServer(vrt1)
+			vs := vrt1.DeepCopy()
+			vs.Spec.IRules = []string{"/Common/team-a-irule", "/Common/admin-irule"}
+			vs.Spec.SNAT = "auto"
+			vs.Spec.TLSProfileName = "tls"
+			vs.Spec.PolicyName = "plc"
+			mockCtlr.addTLSProfile(test.NewTLSProfile("tls", namespace, cisapiv1.TLSProfileSpec{
+				TLS: cisapiv1.TLS{Termination: TLSEdge, ClientSSL: "/Common/clientssl", Reference: BIGIP},
+			}))
+			mockCtlr.addPolicy(test.NewPolicy("plc", namespace, cisapiv1.PolicySpec{
+				L7Policies: cisapiv1.L7PolicySpec{WAF: "/Common/WAF_Policy"},
+				Profiles:   cisapiv1.ProfileSpec{TCP: cisapiv1.ProfileTCP{Client: "/Common/f5-tcp-lan"}},
+			}))
+
+			// Resources refer any BIG-IP object unless restricted
+			Expect(mockCtlr.filterAllowedBigIPReferences([]*cisapiv1.VirtualServer{vs})).To(HaveLen(1))
+
+			mockCtlr.bigipReferences, _ = ParseBigIPReferences([]string{namespace + ":/Common/team-a-",
+				"*:/Common/f5-"})
+			Expect(mockCtlr.getDeniedVirtualServerReferences(vs)).To(Equal([]string{"/Common/WAF_Policy",
+				"/Common/admin-irule", "/Common/clientssl"}))
+			Expect(mockCtlr.filterAllowedBigIPReferences([]*cisapiv1.VirtualServer{vs})).To(BeEmpty())
+			updatedVS, err := mockCtlr.kubeCRClient.CisV1().VirtualServers(namespace).Get(context.TODO(), vrt1.Name,
+				metav1.GetOptions{})
+			Expect(err).To(BeNil())
+			Expect(updatedVS.Status.Conditions).To(HaveLen(1))
+			Expect(updatedVS.Status.Conditions[0].Type).To(Equal(ConditionBigIPReferenceDenied))
+			Expect(updatedVS.Status.Conditions[0].Message).To(ContainSubstring("/Common/admin-irule"))
+
+			// Condition is removed once the resource refers only the allowed objects
+			mockCtlr.bigipReferences[namespace] = []string{"/Common/"}
+			Expect(mockCtlr.filterAllowedBigIPReferences([]*cisapiv1.VirtualServer{updatedVS})).To(HaveLen(1))
+			updatedVS, _ = mockCtlr.kubeCRClient.CisV1().VirtualServers(namespace).Get(context.TODO(), vrt1.Name,
+				metav1.GetOptions{})
+			Expect(updatedVS.Status.Conditions).To(BeEmpty())
+
+			// TransportServer pools and IngressLink iRules are validated
+			ts := test.NewTransportServer("ts", "other", cisapiv1.TransportServerSpec{
+				Pool: cisapiv1.Pool{Monitors: []cisapiv1.Monitor{{Name: "/Common/tcp", Reference: BIGIP}}},
+			})
+			Expect(mockCtlr.bigipReferences.getDeniedReferences(ts.Namespace,
+				getTransportServerBigIPReferences(ts))).To(Equal([]string{"/Common/tcp"}))
+			il := test.NewIngressLink("il", "other", "1", cisapiv1.IngressLinkSpec{IRules: []string{"/Common/f5-irule"}})
+			Expect(mockCtlr.checkIngressLinkBigIPReferences(il)).To(BeTrue())
+		})
+		It("Rejects or strips the BIG-IP references not in the allow-list", func() {
+			mockCtlr.addVirtualServer(vrt1)
+			cm := test.NewConfigMap("global-cm", "1", "kube-system", map[string]string{"extendedSpec": `
+bigipAllowList:
+  action: drop
+  iRules: [/Common/allowed-irule]
+`})
+			err, _ := mockCtlr.processConfigMap(cm, false)
+			Expect(err).NotTo(BeNil())
+			Expect(mockCtlr.bigipAllowList).To(BeNil())
+
+			cm.Data["extendedSpec"] = `
+bigipAllowList:
+  profiles: [/Common/f5-tcp-lan]
+  iRules: [/Common/allowed-irule]
+  wafPolicies: []
+`
+			err, _ = mockCtlr.processConfigMap(cm, false)
+			Expect(err).To(BeNil())
+			Expect(mockCtlr.bigipAllowList).NotTo(BeNil())
+
+			vs := vrt1.DeepCopy()
+			vs.Spec.IRules = []string{"/Common/allowed-irule", "/Common/other-irule"}
+			vs.Spec.Pools[0].WAF = "/Common/WAF_Policy"
+			vs.Spec.Profiles.TCP.Client = "/Common/f5-tcp-lan"
+			vs.Spec.PersistenceProfile = "cookie"
+
+			// Resources with the references not in the allow-list are rejected by default
+			Expect(mockCtlr.filterAllowedBigIPReferences([]*cisapiv1.VirtualServer{vs})).To(BeEmpty())
+			updatedVS, err := mockCtlr.kubeCRClient.CisV1().VirtualServers(namespace).Get(context.TODO(), vrt1.Name,
+				metav1.GetOptions{})
+			Expect(err).To(BeNil())
+			Expect(updatedVS.Status.Conditions).To(HaveLen(1))
+			Expect(updatedVS.Status.Conditions[0].Reason).To(Equal("NotInAllowList"))
+			Expect(updatedVS.Status.Conditions[0].Message).To(Equal(
+				"BIG-IP references /Common/WAF_Policy, /Common/other-irule are not in the allow-list"))
+
+			// References of the resource are removed with the strip action
+			mockCtlr.bigipAllowList.Action = AllowListStrip
+			virtuals := mockCtlr.filterAllowedBigIPReferences([]*cisapiv1.VirtualServer{vs})
+			Expect(virtuals).To(HaveLen(1))
+			Expect(virtuals[0].Spec.IRules).To(Equal([]string{"/Common/allowed-irule"}))
+			Expect(virtuals[0].Spec.Pools[0].WAF).To(BeEmpty())
+			Expect(virtuals[0].Spec.Profiles.TCP.Client).To(Equal("/Common/f5-tcp-lan"))
+			Expect(virtuals[0].Spec.PersistenceProfile).To(Equal("cookie"))
+			Expect(vs.Spec.IRules).To(HaveLen(2))
+			updatedVS, _ = mockCtlr.kubeCRClient.CisV1().VirtualServers(namespace).Get(context.TODO(), vrt1.Name,
+				metav1.GetOptions{})
+			Expect(updatedVS.Status.Conditions[0].Reason).To(Equal("Stripped"))
+
+			// References of the policy shared by the resources are not stripped
+			mockCtlr.addPolicy(test.NewPolicy("plc", namespace, cisapiv1.PolicySpec{
+				IRuleList: []string{"/Common/policy-irule"},
+			}))
+			vs.Spec.PolicyName = "plc"
+			Expect(mockCtlr.filterAllowedBigIPReferences([]*cisapiv1.VirtualServer{vs})).To(BeEmpty())
+
+			// TransportServer and IngressLink iRules are stripped
+			ts := test.NewTransportServer("ts", namespace, cisapiv1.TransportServerSpec{
+				IRules: []string{"/Common/other-irule"}, ProfileL4: "/Common/f5-tcp-lan",
+			})
+			allowedTS, rejected := mockCtlr.getAllowedTransportServer(ts)
+			Expect(rejected).To(BeFalse())
+			Expect(allowedTS.Spec.IRules).To(BeEmpty())
+			Expect(allowedTS.Spec.ProfileL4).To(Equal("/Common/f5-tcp-lan"))
+			il := test.NewIngressLink("il", namespace, "1", cisapiv1.IngressLinkSpec{IRules: []string{"/Common/other-irule"}})
+			Expect(mockCtlr.getAllowedIngressLink(il).Spec.IRules).To(BeEmpty())
+			mockCtlr.bigipAllowList.Action = AllowListReject
+			Expect(mockCtlr.checkIngressLinkBigIPReferences(il)).To(BeFalse())
+
+			// Resources refer any BIG-IP object once the allow-list is removed
+			err, _ = mockCtlr.processConfigMap(cm, true)
+			Expect(err).To(BeNil())
+			Expect(mockCtlr.bigipAllowList).To(BeNil())
+			Expect(mockCtlr.filterAllowedBigIPReferences([]*cisapiv1.VirtualServer{vs})).To(HaveLen(1))
+		})
+	})
+
+	Describe("Shared bundle", func() {
+		It("Installs the shared bundle of the global extended ConfigMap in /Common/Shared", func() {
+			cm := test.NewConfigMap("global-cm", "1", "kube-system", map[string]string{"extendedSpec": `
+sharedBundle:
+  iRules:
+  - name: redirect_irule
+    code: ""
+`})
+			err, _ := mockCtlr.processConfigMap(cm, false)
+			Expect(err).NotTo(BeNil(), "iRule without code not rejected")
+			Expect(mockCtlr.resources.sharedBundle).To(BeNil())
+
+			cm.Data["extendedSpec"] = `
+sharedBundle:
+  iRules:
+  - name: redirect_irule
+    code: "when HTTP_REQUEST { HTTP::redirect https://[HTTP::host][HTTP::uri] }"
+  profiles:
+  - name: xff_http
+    class: HTTP_Profile
+    properties:
+      xForwardedFor: true
+      insertHeader:
+      - name: X-Team
+        value: team-a
+`
+			err, _ = mockCtlr.processConfigMap(cm, false)
+			Expect(err).To(BeNil())
+			Expect(mockCtlr.resources.sharedBundle).NotTo(BeNil())
+			Expect(mockCtlr.resources.isConfigUpdated()).To(BeTrue())
+			bundle := mockCtlr.resources.sharedBundle
+
+			// unchanged bundle is not updated
+			err, _ = mockCtlr.processConfigMap(cm, false)
+			Expect(err).To(BeNil())
+			Expect(mockCtlr.resources.sharedBundle).To(BeIdenticalTo(bundle))
+
+			config := ResourceConfigRequest{ltmConfig: make(LTMConfig), sharedBundle: mockCtlr.resources.sharedBundle}
+			adc := mockCtlr.Agent.createAS3LTMConfigADC(config)
+			Expect(adc).To(HaveKey("Common"))
+			sharedApp := adc["Common"].(as3Tenant)[as3SharedApplication].(as3Application)
+			Expect(sharedApp["redirect_irule"].(*as3IRules).Class).To(Equal("iRule"))
+			prof := sharedApp["xff_http"].(map[string]interface{})
+			Expect(prof["class"]).To(Equal("HTTP_Profile"))
+			Expect(prof["xForwardedFor"]).To(BeTrue())
+			Expect(prof["insertHeader"]).To(Equal([]interface{}{
+				map[string]interface{}{"name": "X-Team", "value": "team-a"},
+			}))
+			Expect(mockCtlr.Agent.isSharedBundleTenant(config, "Common")).To(BeTrue())
+
+			// Common tenant is flushed once the installed bundle is removed
+			mockCtlr.Agent.cachedTenantDeclMap["Common"] = adc["Common"].(as3Tenant)
+			err, _ = mockCtlr.processConfigMap(cm, true)
+			Expect(err).To(BeNil())
+			Expect(mockCtlr.resources.sharedBundle).To(BeNil())
+			config.sharedBundle = nil
+			adc = mockCtlr.Agent.createAS3LTMConfigADC(config)
+			sharedApp = adc["Common"].(as3Tenant)[as3SharedApplication].(as3Application)
+			Expect(sharedApp).NotTo(HaveKey("redirect_irule"))
+			Expect(sharedApp).NotTo(HaveKey("xff_http"))
+			Expect(mockCtlr.Agent.isSharedBundleTenant(config, "Common")).To(BeTrue())
+
+			// Common tenant is not posted without the shared bundle
+			delete(mockCtlr.Agent.cachedTenantDeclMap, "Common")
+			Expect(mockCtlr.Agent.createAS3LTMConfigADC(config)).NotTo(HaveKey("Common"))
+			Expect(mockCtlr.Agent.isSharedBundleTenant(config, "Common")).To(BeFalse())
+		})
+	})
+
+	Describe("BIG-IP objects", func() {
+		It("Excludes the VirtualServers referring the LTM policies and iRules not found on BIG-IP", func() {
+			newResponse := func(status int, body string) *http.Response {
+				return &http.Response{
+					StatusCode: status,
+					Header:     http.Header{},
+					Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+				}
+			}
+			setResponses := func(responses ...*http.Response) {
+				client, _ := mockhc.NewMockHTTPClient(mockhc.ResponseConfigMap{
+					http.MethodGet: &mockhc.ResponseConfig{Responses: responses},
+				})
+				mockCtlr.Agent.PostManager.httpClient = client
+			}
+			policy := bigipObjectRef{kind: bigipObjectPolicy, path: "/Common/policy1"}
+			iRule := bigipObjectRef{kind: bigipObjectIRule, path: "/Common/irule1"}
+			Expect(mockCtlr.Agent.PostManager.getBigIPObjectURL(iRule)).To(Equal(
+				"10.10.10.1/mgmt/tm/ltm/rule/~Common~irule1?$select=name"))
+
+			vrt1.Spec.BigIPPolicies = []string{policy.path}
+			vrt1.Spec.IRules = []string{iRule.path}
+			mockCtlr.namespaces = map[string]bool{namespace: true}
+			mockCtlr.addVirtualServer(vrt1)
+			virtuals := []*cisapiv1.VirtualServer{vrt1}
+
+			// objects are not verified when disabled
+			Expect(mockCtlr.filterMissingBigIPObjects(virtuals)).To(Equal(virtuals))
+
+			mockCtlr.bigipObjectCheckInterval = 30
+			setResponses(newResponse(http.StatusOK, `{"name": "policy1"}`),
+				newResponse(http.StatusNotFound, `{"code": 404}`))
+			Expect(mockCtlr.filterMissingBigIPObjects(virtuals)).To(BeEmpty())
+			Expect(mockCtlr.bigipObjects.objects).To(Equal(map[bigipObjectRef]bool{policy: true, iRule: false}))
+			vs, err := mockCtlr.kubeCRClient.CisV1().VirtualServers(namespace).Get(
+				context.TODO(), vrt1.Name, metav1.GetOptions{})
+			Expect(err).To(BeNil())
+			Expect(len(vs.Status.Conditions)).To(Equal(1), "BIG-IP object not found condition not set")
+			Expect(vs.Status.Conditions[0].Type).To(Equal(ConditionBigIPObjectNotFound))
+			Expect(vs.Status.Conditions[0].Message).To(Equal("BIG-IP objects /Common/irule1 are not found"))
+
+			// iRule created on BIG-IP
+			setResponses(newResponse(http.StatusOK, `{"name": "policy1"}`),
+				newResponse(http.StatusOK, `{"name": "irule1"}`))
+			changed := mockCtlr.refreshBigIPObjects()
+			Expect(changed).To(Equal([]bigipObjectRef{iRule}))
+			affected := mockCtlr.getVirtualServersForBigIPObjects(changed)
+			Expect(affected).To(HaveLen(1))
+			Expect(affected[0].Name).To(Equal(vrt1.Name))
+			Expect(mockCtlr.filterMissingBigIPObjects([]*cisapiv1.VirtualServer{vs})).To(HaveLen(1))
+			vs, err = mockCtlr.kubeCRClient.CisV1().VirtualServers(namespace).Get(
+				context.TODO(), vrt1.Name, metav1.GetOptions{})
+			Expect(err).To(BeNil())
+			Expect(len(vs.Status.Conditions)).To(Equal(0), "BIG-IP object not found condition not cleared")
+		})
+	})
+
+	Describe("SNAT return path", func() {
+		It("Warns for the snat none of the unverified return path", func() {
+			recorder := record.NewFakeRecorder(10)
+			mockCtlr.eventRecorder = recorder
+			mockCtlr.addVirtualServer(vrt1)
+
+			mockCtlr.PoolMemberType = Cluster
+			mockCtlr.StaticRoutingMode = true
+			mockCtlr.checkSNATReturnPath(vrt1, SNATNone)
+			Expect(recorder.Events).NotTo(Receive())
+
+			mockCtlr.PoolMemberType = NodePort
+			mockCtlr.checkSNATReturnPath(vrt1, SNATNone)
+			Expect(recorder.Events).To(Receive(ContainSubstring("Warning SNATReturnPathUnverified")))
+			vs, err := mockCtlr.kubeCRClient.CisV1().VirtualServers(namespace).Get(context.TODO(), vrt1.Name, metav1.GetOptions{})
+			Expect(err).To(BeNil())
+			Expect(len(vs.Status.Conditions)).To(Equal(1))
+			Expect(vs.Status.Conditions[0].Type).To(Equal(ConditionSNATReturnPathUnverified))
+
+			// Warning is raised once for the resource
+			mockCtlr.checkSNATReturnPath(vs, SNATNone)
+			Expect(recorder.Events).NotTo(Receive())
+
+			// Condition is removed once the client address is translated
+			mockCtlr.checkSNATReturnPath(vs, DEFAULT_SNAT)
+			vs, _ = mockCtlr.kubeCRClient.CisV1().VirtualServers(namespace).Get(context.TODO(), vrt1.Name, metav1.GetOptions{})
+			Expect(vs.Status.Conditions).To(BeEmpty())
+		})
+	})
+
+	Describe("Node member label", func() {
+		It("Sets the node member label of the resource on the pools", func() {
+			vs := test.NewVirtualServer("vs", namespace, cisapiv1.VirtualServerSpec{
+				NodeMemberLabel: "pool=ingress",
+				Pools: []cisapiv1.Pool{
+					{Service: "svc1", ServicePort: intstr.FromInt(80)},
+					{Service: "svc2", ServicePort: intstr.FromInt(80), NodeMemberLabel: "pool=edge"},
+				},
+				DefaultPool: cisapiv1.DefaultPool{Reference: ServiceRef, Service: "svc3"},
+			})
+			virtuals := setVirtualServerNodeMemberLabel([]*cisapiv1.VirtualServer{vs})
+			Expect(virtuals[0].Spec.Pools[0].NodeMemberLabel).To(Equal("pool=ingress"))
+			Expect(virtuals[0].Spec.Pools[1].NodeMemberLabel).To(Equal("pool=edge"))
+			Expect(virtuals[0].Spec.DefaultPool.NodeMemberLabel).To(Equal("pool=ingress"))
+			Expect(vs.Spec.Pools[0].NodeMemberLabel).To(BeEmpty(), "VirtualServer of the informer is updated")
+
+			ts := test.NewTransportServer("ts", namespace, cisapiv1.TransportServerSpec{
+				NodeMemberLabel: "pool=ingress",
+				Pool:            cisapiv1.Pool{Service: "svc1", ServicePort: intstr.FromInt(80)},
+			})
+			Expect(setTransportServerNodeMemberLabel(ts).Spec.Pool.NodeMemberLabel).To(Equal("pool=ingress"))
+			Expect(ts.Spec.Pool.NodeMemberLabel).To(BeEmpty(), "TransportServer of the informer is updated")
+			ts.Spec.Pool.NodeMemberLabel = "pool=edge"
+			Expect(setTransportServerNodeMemberLabel(ts)).To(Equal(ts))
+		})
+	})
+
+	Describe("Secondary networks", func() {
+		It("Uses the addresses of the pods on the network attachment as the pool members", func() {
+			members := []PoolMember{{Address: "10.244.0.5", Port: 8080}, {Address: "10.244.0.6", Port: 8080}}
+			Expect(mockCtlr.getNetworkAttachmentMembers(namespace, "svc", "macvlan",
+				members)).To(Equal(members), "Members updated without the secondary networks")
+
+			mockCtlr.secondaryNetworks = true
+			mockCtlr.PoolMemberType = Cluster
+			delete(mockCtlr.comInformers, namespace)
+			Expect(mockCtlr.addNamespacedInformers(namespace, false)).To(BeNil(), "Informers Creation Failed")
+			comInf, _ := mockCtlr.getNamespacedCommonInformer(namespace)
+			Expect(comInf.podInformer).NotTo(BeNil(), "Pod informer not created")
+
+			svc := test.NewServicewithselectors("svc", "1", namespace, map[string]string{"app": "web"},
+				v1.ServiceTypeClusterIP, nil)
+			_ = comInf.svcInformer.GetIndexer().Add(svc)
+			pod1 := test.NewPod("pod1", namespace, 8080, map[string]string{"app": "web"})
+			pod1.Status.PodIP = "10.244.0.5"
+			pod1.Annotations = map[string]string{MultusNetworkStatusAnnotation: `[{"name":"ovn-kubernetes",` +
+				`"ips":["10.244.0.5"],"default":true},{"name":"default/macvlan","interface":"net1",` +
+				`"ips":["192.168.10.5"]}]`}
+			pod2 := test.NewPod("pod2", namespace, 8080, map[string]string{"app": "web"})
+			pod2.Status.PodIP = "10.244.0.6"
+			pod2.Annotations = map[string]string{MultusNetworksStatusAnnotation: `[{"name":"default/sriov",` +
+				`"ips":["192.168.20.6"]}]`}
+			_ = comInf.podInformer.GetIndexer().Add(pod1)
+			_ = comInf.podInformer.GetIndexer().Add(pod2)
+
+			Expect(mockCtlr.getNetworkAttachmentMembers(namespace, "svc", "macvlan", members)).To(Equal(
+				[]PoolMember{{Address: "192.168.10.5", Port: 8080}}), "Invalid pool members")
+			Expect(mockCtlr.getNetworkAttachmentMembers(namespace, "svc", namespace+"/sriov", members)).To(Equal(
+				[]PoolMember{{Address: "192.168.20.6", Port: 8080}}), "Invalid pool members")
+			Expect(mockCtlr.getNetworkAttachmentMembers(namespace, "svc", "ipvlan", members)).To(BeEmpty(),
+				"Members without the network attachment")
+		})
+	})
+
+	Describe("Dual-stack pool members", func() {
+		It("Uses the addresses of the address family of the pods as the pool members", func() {
+			members := []PoolMember{{Address: "10.244.0.5", Port: 8080}, {Address: "10.244.0.6", Port: 8080}}
+			Expect(mockCtlr.getAddressFamilyMembers(namespace, "svc", AddressFamilyIPv6,
+				members)).To(BeEmpty(), "IPv4 endpoints selected for ipv6")
+			Expect(mockCtlr.getAddressFamilyMembers(namespace, "svc", AddressFamilyPreferIPv6,
+				members)).To(Equal(members), "Endpoints not selected for prefer-ipv6")
+
+			mockCtlr.dualStackMembers = true
+			mockCtlr.PoolMemberType = Cluster
+			delete(mockCtlr.comInformers, namespace)
+			Expect(mockCtlr.addNamespacedInformers(namespace, false)).To(BeNil(), "Informers Creation Failed")
+			comInf, _ := mockCtlr.getNamespacedCommonInformer(namespace)
+			Expect(comInf.podInformer).NotTo(BeNil(), "Pod informer not created")
+
+			svc := test.NewServicewithselectors("svc", "1", namespace, map[string]string{"app": "web"},
+				v1.ServiceTypeClusterIP, nil)
+			_ = comInf.svcInformer.GetIndexer().Add(svc)
+			pod1 := test.NewPod("pod1", namespace, 8080, map[string]string{"app": "web"})
+			pod1.Status.PodIP = "10.244.0.5"
+			pod1.Status.PodIPs = []v1.PodIP{{IP: "10.244.0.5"}, {IP: "fd00:10:244::5"}}
+			pod2 := test.NewPod("pod2", namespace, 8080, map[string]string{"app": "web"})
+			pod2.Status.PodIP = "10.244.0.6"
+			pod2.Status.PodIPs = []v1.PodIP{{IP: "10.244.0.6"}}
+			_ = comInf.podInformer.GetIndexer().Add(pod1)
+			_ = comInf.podInformer.GetIndexer().Add(pod2)
+
+			Expect(mockCtlr.getAddressFamilyMembers(namespace, "svc", AddressFamilyIPv4, members)).To(Equal(
+				members), "Invalid pool members")
+			Expect(mockCtlr.getAddressFamilyMembers(namespace, "svc", AddressFamilyIPv6, members)).To(Equal(
+				[]PoolMember{{Address: "fd00:10:244::5", Port: 8080}}), "Invalid pool members")
+			Expect(mockCtlr.getAddressFamilyMembers(namespace, "svc", AddressFamilyPreferIPv6, members)).To(Equal(
+				[]PoolMember{{Address: "fd00:10:244::5", Port: 8080}, {Address: "10.244.0.6", Port: 8080}}),
+				"Invalid pool members")
+		})
+	})
+
+	Describe("Ratio of pool members", func() {
+		It("Sets the ratio of the pool members from the pods", func() {
+			members := []PoolMember{{Address: "10.244.0.5", Port: 8080}, {Address: "10.244.0.6", Port: 8080},
+				{Address: "10.244.0.7", Port: 8080}}
+			Expect(mockCtlr.setPoolMemberRatio(namespace, "svc", MemberRatioAnnotation,
+				members)).To(Equal(members), "Ratio set without the pool member ratio")
+
+			mockCtlr.poolMemberRatio = true
+			mockCtlr.PoolMemberType = Cluster
+			delete(mockCtlr.comInformers, namespace)
+			Expect(mockCtlr.addNamespacedInformers(namespace, false)).To(BeNil(), "Informers Creation Failed")
+			comInf, _ := mockCtlr.getNamespacedCommonInformer(namespace)
+			Expect(comInf.podInformer).NotTo(BeNil(), "Pod informer not created")
+
+			svc := test.NewServicewithselectors("svc", "1", namespace, map[string]string{"app": "web"},
+				v1.ServiceTypeClusterIP, nil)
+			_ = comInf.svcInformer.GetIndexer().Add(svc)
+			for i, ratio := range []string{"20", "5", "invalid"} {
+				pod := test.NewPod("pod"+strconv.Itoa(i), namespace, 8080, map[string]string{"app": "web"})
+				pod.Status.PodIP = "10.244.0." + strconv.Itoa(i+5)
+				pod.Annotations = map[string]string{PoolMemberRatioAnnotation: ratio}
+				pod.Spec.Containers = []v1.Container{{Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: *k8sresource.NewMilliQuantity(int64(1000-i*250),
+						k8sresource.DecimalSI)},
+				}}}
+				_ = comInf.podInformer.GetIndexer().Add(pod)
+			}
+
+			Expect(mockCtlr.setPoolMemberRatio(namespace, "svc", MemberRatioAnnotation, members)).To(Equal(
+				[]PoolMember{{Address: "10.244.0.5", Port: 8080, Ratio: 20}, {Address: "10.244.0.6", Port: 8080,
+					Ratio: 5}, {Address: "10.244.0.7", Port: 8080}}), "Invalid ratio of the pool members")
+			Expect(mockCtlr.setPoolMemberRatio(namespace, "svc", MemberRatioCPURequests, members)).To(Equal(
+				[]PoolMember{{Address: "10.244.0.5", Port: 8080, Ratio: 100}, {Address: "10.244.0.6", Port: 8080,
+					Ratio: 75}, {Address: "10.244.0.7", Port: 8080, Ratio: 50}}), "Invalid ratio of the pool members")
+		})
+
+		It("Raises the ratio of the pool members of the pods warming up", func() {
+			members := []PoolMember{{Address: "10.244.0.5", Port: 8080}, {Address: "10.244.0.6", Port: 8080},
+				{Address: "10.244.0.7", Port: 8080}}
+			Expect(mockCtlr.setWarmUpMemberRatio(namespace, "svc", 60,
+				members)).To(Equal(members), "Ratio set without the pool member ratio")
+
+			mockCtlr.poolMemberRatio = true
+			mockCtlr.PoolMemberType = Cluster
+			delete(mockCtlr.comInformers, namespace)
+			Expect(mockCtlr.addNamespacedInformers(namespace, false)).To(BeNil(), "Informers Creation Failed")
+			comInf, _ := mockCtlr.getNamespacedCommonInformer(namespace)
+
+			svc := test.NewServicewithselectors("svc", "1", namespace, map[string]string{"app": "web"},
+				v1.ServiceTypeClusterIP, nil)
+			_ = comInf.svcInformer.GetIndexer().Add(svc)
+			gate := v1.PodConditionType("example.com/load-balancer-ready")
+			for i, ready := range []time.Duration{time.Hour, 30 * time.Second, time.Second} {
+				pod := test.NewPod("pod"+strconv.Itoa(i), namespace, 8080, map[string]string{"app": "web"})
+				pod.Status.PodIP = "10.244.0." + strconv.Itoa(i+5)
+				pod.Spec.ReadinessGates = []v1.PodReadinessGate{{ConditionType: gate}}
+				pod.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-ready))}}
+				if i < 2 {
+					pod.Status.Conditions = append(pod.Status.Conditions, v1.PodCondition{Type: gate,
+						Status: v1.ConditionTrue})
+				}
+				_ = comInf.podInformer.GetIndexer().Add(pod)
+			}
+
+			Expect(mockCtlr.setWarmUpMemberRatio(namespace, "svc", 60, members)).To(Equal(
+				[]PoolMember{{Address: "10.244.0.5", Port: 8080, Ratio: 100}, {Address: "10.244.0.6", Port: 8080,
+					Ratio: 50}}), "Invalid ratio of the pool members")
+			members[1].Ratio = 20
+			Expect(mockCtlr.setWarmUpMemberRatio(namespace, "svc", 60, members)[1].Ratio).To(Equal(10),
+				"Invalid ratio of the pool member")
+			mockCtlr.warmUpPods.Lock()
+			Expect(mockCtlr.warmUpPods.pending).To(HaveKey(namespace+"/pod1"), "Warm-up not scheduled")
+			Expect(mockCtlr.warmUpPods.pending).To(HaveLen(1), "Warm-up scheduled for the pods warmed up")
+			mockCtlr.warmUpPods.Unlock()
+		})
+	})
+
 	Describe("Deletion of virtuals", func() {
 		var vrts []*cisapiv1.VirtualServer
 		var vrt2 *cisapiv1.VirtualServer
@@ -2077,6 +3204,7 @@ var _ = Describe("Worker Tests", func() {
 				rscUpdateMeta := resourceStatusMeta{
 					0,
 					make(map[string]struct{}),
+					nil,
 				}
 
 				time.Sleep(10 * time.Millisecond)
@@ -2084,7 +3212,7 @@ var _ = Describe("Worker Tests", func() {
 				time.Sleep(10 * time.Millisecond)
 
 				config := ResourceConfigRequest{
-					ltmConfig:  mockCtlr.resources.getLTMConfigDeepCopy(),
+					ltmConfig:  mockCtlr.resources.getLTMConfigSnapshot(),
 					shareNodes: mockCtlr.shareNodes,
 					gtmConfig:  mockCtlr.resources.getGTMConfigCopy(),
 				}
@@ -2183,6 +3311,471 @@ var _ = Describe("Worker Tests", func() {
 				Expect(len(mockCtlr.resources.ltmConfig[mockCtlr.Partition].ResourceMap)).To(Equal(2), "Invalid VS count")
 
 			})
+
+			It("Virtual Server with route domain and rejectVlans", func() {
+				crInf := mockCtlr.newNamespacedCustomResourceInformer(namespace)
+				nrInf := mockCtlr.newNamespacedNativeResourceInformer(namespace)
+				crInf.start()
+				nrInf.start()
+				vs.Spec.TLSProfileName = ""
+				vs.Spec.PolicyName = ""
+				vs.Spec.VirtualServerAddress = "10.8.0.1"
+				vs.Spec.RouteDomain = 2
+				vs.Spec.AllowVLANs = nil
+				vs.Spec.RejectVLANs = []string{"/Common/external"}
+				mockCtlr.Partition = "test"
+				mockCtlr.addVirtualServer(vs)
+				mockCtlr.processResources()
+
+				rsCfg := mockCtlr.resources.getPartitionResourceMap(mockCtlr.Partition)[formatVirtualServerName("10.8.0.1%2", 80)]
+				Expect(rsCfg).NotTo(BeNil(), "VirtualServer not processed with route domain")
+				Expect(rsCfg.Virtual.Destination).To(Equal("/test/10.8.0.1%2:80"), "Route domain not set in destination")
+				Expect(rsCfg.Virtual.RejectVLANs).To(Equal([]string{"/Common/external"}), "rejectVlans not set")
+				Expect(appendRouteDomain("10.8.0.1%3", 2)).To(Equal("10.8.0.1%3"), "Route domain of the address overridden")
+
+				// allowVlans and rejectVlans are mutually exclusive
+				vs.Spec.AllowVLANs = []string{"/Common/internal"}
+				Expect(mockCtlr.checkValidVirtualServer(vs)).To(BeFalse(), "allowVlans accepted with rejectVlans")
+			})
+
+			It("Virtual Server with IPv6 and IPv4 addresses", func() {
+				crInf := mockCtlr.newNamespacedCustomResourceInformer(namespace)
+				nrInf := mockCtlr.newNamespacedNativeResourceInformer(namespace)
+				crInf.start()
+				nrInf.start()
+				vs.Spec.TLSProfileName = ""
+				vs.Spec.PolicyName = ""
+				vs.Spec.VirtualServerAddress = "2001:db8::10"
+				vs.Spec.AdditionalVirtualServerAddresses = []string{"10.8.0.0/24"}
+				vs.Spec.RouteDomain = 2
+				mockCtlr.Partition = "test"
+				mockCtlr.addVirtualServer(vs)
+				mockCtlr.processResources()
+
+				rsCfg := mockCtlr.resources.getPartitionResourceMap(mockCtlr.Partition)[formatVirtualServerName("2001:db8::10%2", 80)]
+				Expect(rsCfg).NotTo(BeNil(), "IPv6 VirtualServer not processed")
+				Expect(rsCfg.Virtual.Destination).To(Equal("/test/2001:db8::10%2.80"), "Route domain not set in destination")
+				Expect(rsCfg.Virtual.AdditionalVirtualAddresses).To(Equal([]string{"10.8.0.0%2/24"}),
+					"Route domain not set before the mask")
+				Expect(isVirtualOfRecordType(rsCfg, "AAAA")).To(BeTrue())
+
+				// invalid addresses are rejected
+				for _, address := range []string{"2001:db8::10%x", "2001:db8::10/129", "10.8.0.1/24", "10.8.0.1%65535",
+					"10.8.0.300"} {
+					vs.Spec.AdditionalVirtualServerAddresses = []string{address}
+					Expect(mockCtlr.checkValidVirtualServer(vs)).To(BeFalse(), "Invalid address %v accepted", address)
+				}
+				vs.Spec.AdditionalVirtualServerAddresses = []string{"2001:db8::10"}
+				Expect(mockCtlr.checkValidVirtualServer(vs)).To(BeFalse(), "Duplicate address accepted")
+				vs.Spec.AdditionalVirtualServerAddresses = []string{"2001:db8::/64", "10.8.0.1/32", "10.8.0.1%3"}
+				Expect(mockCtlr.checkValidVirtualServer(vs)).To(BeTrue())
+			})
+
+			It("Virtual Server with pause annotation", func() {
+				crInf := mockCtlr.newNamespacedCustomResourceInformer(namespace)
+				nrInf := mockCtlr.newNamespacedNativeResourceInformer(namespace)
+				crInf.start()
+				nrInf.start()
+				vs.Spec.TLSProfileName = ""
+				vs.Spec.PolicyName = ""
+				vs.Spec.VirtualServerAddress = "10.8.0.1"
+				vs.Annotations = map[string]string{PauseAnnotation: "true"}
+				mockCtlr.Partition = "test"
+				mockCtlr.addVirtualServer(vs)
+				mockCtlr.processResources()
+
+				rsCfg := mockCtlr.resources.getPartitionResourceMap(mockCtlr.Partition)["crd_10_8_0_1_80"]
+				Expect(rsCfg).NotTo(BeNil(), "VirtualServer not processed")
+				Expect(rsCfg.Virtual.IRules).To(ContainElement("/Common/SampleIRule"), "iRule not set")
+
+				// updates are not processed while the annotation is present
+				pausedVS := vs.DeepCopy()
+				pausedVS.Spec.IRules = []string{"/Common/UpdatedIRule"}
+				mockCtlr.updateVirtualServer(vs, pausedVS)
+				mockCtlr.processResources()
+				rsCfg = mockCtlr.resources.getPartitionResourceMap(mockCtlr.Partition)["crd_10_8_0_1_80"]
+				Expect(rsCfg).NotTo(BeNil(), "VirtualServer removed while paused")
+				Expect(rsCfg.Virtual.IRules).To(ContainElement("/Common/SampleIRule"), "Update processed while paused")
+				Expect(rsCfg.Virtual.IRules).NotTo(ContainElement("/Common/UpdatedIRule"), "Update processed while paused")
+
+				// updates are processed once the annotation is removed
+				resumedVS := pausedVS.DeepCopy()
+				resumedVS.Annotations = nil
+				mockCtlr.updateVirtualServer(pausedVS, resumedVS)
+				mockCtlr.processResources()
+				rsCfg = mockCtlr.resources.getPartitionResourceMap(mockCtlr.Partition)["crd_10_8_0_1_80"]
+				Expect(rsCfg).NotTo(BeNil(), "VirtualServer not processed")
+				Expect(rsCfg.Virtual.IRules).To(ContainElement("/Common/UpdatedIRule"), "Update not processed on resume")
+				Expect(mockCtlr.resources.pausedResources).To(BeEmpty(), "Paused VirtualServer not removed")
+			})
+
+			It("Virtual Server with finalizer", func() {
+				mockCtlr.enableFinalizers = true
+				defer func() { mockCtlr.enableFinalizers = false }()
+				crInf := mockCtlr.newNamespacedCustomResourceInformer(namespace)
+				nrInf := mockCtlr.newNamespacedNativeResourceInformer(namespace)
+				crInf.start()
+				nrInf.start()
+				vs.Spec.TLSProfileName = ""
+				vs.Spec.PolicyName = ""
+				vs.Spec.VirtualServerAddress = "10.8.0.1"
+				mockCtlr.Partition = "test"
+				_, err := mockCtlr.kubeCRClient.CisV1().VirtualServers(namespace).Create(context.TODO(), vs, metav1.CreateOptions{})
+				Expect(err).To(BeNil(), "VirtualServer not created")
+				// only the finalizers of the VirtualServer are patched
+				var patches []string
+				crClient := mockCtlr.kubeCRClient.(*crdfake.Clientset)
+				crClient.PrependReactor("patch", "virtualservers", func(action k8stesting.Action) (bool, runtime.Object, error) {
+					patchAction := action.(k8stesting.PatchAction)
+					Expect(patchAction.GetPatchType()).To(Equal(types.MergePatchType))
+					patches = append(patches, string(patchAction.GetPatch()))
+					return false, nil, nil
+				})
+				crClient.PrependReactor("update", "virtualservers", func(action k8stesting.Action) (bool, runtime.Object, error) {
+					Fail("VirtualServer updated to change the finalizer")
+					return false, nil, nil
+				})
+				mockCtlr.addVirtualServer(vs)
+				mockCtlr.processResources()
+				Expect(mockCtlr.resources.getPartitionResourceMap(mockCtlr.Partition)).To(HaveKey("crd_10_8_0_1_80"))
+				Expect(patches).To(Equal([]string{`{"metadata":{"finalizers":["` + ResourceFinalizer + `"]}}`}))
+
+				processedVS, err := mockCtlr.kubeCRClient.CisV1().VirtualServers(namespace).Get(context.TODO(), vs.Name, metav1.GetOptions{})
+				Expect(err).To(BeNil(), "VirtualServer not found")
+				Expect(processedVS.Finalizers).To(Equal([]string{ResourceFinalizer}), "Finalizer not added")
+
+				// VirtualServer with the finalizer is deleted
+				deletedVS := processedVS.DeepCopy()
+				deletionTime := metav1.Now()
+				deletedVS.DeletionTimestamp = &deletionTime
+				mockCtlr.updateVirtualServer(processedVS, deletedVS)
+				mockCtlr.processResources()
+				Expect(mockCtlr.resources.getPartitionResourceMap(mockCtlr.Partition)).NotTo(HaveKey("crd_10_8_0_1_80"))
+				Expect(mockCtlr.getAllVirtualServers(namespace)).To(BeEmpty(), "Deleted VirtualServer listed")
+				rscRef := resourceRef{kind: VirtualServer, namespace: namespace, name: vs.Name}
+				Expect(mockCtlr.resourceFinalizers.resources).To(HaveKey(rscRef), "Deleted VirtualServer not recorded")
+				reqId := mockCtlr.resourceFinalizers.resources[rscRef].reqId
+				Expect(reqId).NotTo(BeZero(), "Request not assigned")
+
+				// finalizer is retained when the tenant fails
+				mockCtlr.removeResourceFinalizers(reqId, map[string]struct{}{"test": {}})
+				processedVS, _ = mockCtlr.kubeCRClient.CisV1().VirtualServers(namespace).Get(context.TODO(), vs.Name, metav1.GetOptions{})
+				Expect(processedVS.Finalizers).To(Equal([]string{ResourceFinalizer}), "Finalizer removed on failure")
+
+				mockCtlr.removeResourceFinalizers(reqId, map[string]struct{}{})
+				processedVS, _ = mockCtlr.kubeCRClient.CisV1().VirtualServers(namespace).Get(context.TODO(), vs.Name, metav1.GetOptions{})
+				Expect(processedVS.Finalizers).To(BeEmpty(), "Finalizer not removed")
+				Expect(patches[len(patches)-1]).To(Equal(`{"metadata":{"finalizers":null}}`))
+				Expect(mockCtlr.resourceFinalizers.resources).To(BeEmpty(), "Deleted VirtualServer not removed")
+			})
+
+			It("Virtual Server with ACME HTTP-01 solver", func() {
+				mockCtlr.enableACMESolver = true
+				defer func() { mockCtlr.enableACMESolver = false }()
+				crInf := mockCtlr.newNamespacedCustomResourceInformer(namespace)
+				nrInf := mockCtlr.newNamespacedNativeResourceInformer(namespace)
+				crInf.start()
+				nrInf.start()
+				vs.Spec.TLSProfileName = ""
+				vs.Spec.PolicyName = ""
+				vs.Spec.VirtualServerAddress = "10.8.0.1"
+				mockCtlr.Partition = "test"
+				mockCtlr.addVirtualServer(vs)
+				mockCtlr.processResources()
+
+				solver := test.NewService("cm-acme-http-solver-abcde", "1", namespace, v1.ServiceTypeNodePort,
+					[]v1.ServicePort{{Port: 8089, NodePort: 30089}})
+				solver.Labels = map[string]string{
+					ACMESolverLabel: "true",
+					ACMEDomainLabel: getACMEDomainHash("test.com"),
+				}
+				mockCtlr.addService(solver)
+				mockCtlr.processResources()
+
+				rsCfg := mockCtlr.resources.getPartitionResourceMap(mockCtlr.Partition)["crd_10_8_0_1_80"]
+				Expect(rsCfg).NotTo(BeNil(), "VirtualServer not processed")
+				poolName := formatPoolName(namespace, solver.Name, intstr.FromInt(8089), "", "test.com", "")
+				var solverPool bool
+				for _, pool := range rsCfg.Pools {
+					if pool.Name == poolName {
+						solverPool = true
+					}
+				}
+				Expect(solverPool).To(BeTrue(), "Solver pool not created")
+				iRuleName := getRSCfgResName(rsCfg.Virtual.Name, ACMEChallengeIRuleName)
+				Expect(rsCfg.Virtual.IRules).To(ContainElement(JoinBigipPath(mockCtlr.Partition, iRuleName)), "ACME challenge iRule not attached")
+				dgName := NameRef{Name: getRSCfgResName(rsCfg.Virtual.Name, ACMEChallengeDgName), Partition: mockCtlr.Partition}
+				Expect(rsCfg.IntDgMap[dgName][namespace].Records).To(Equal(InternalDataGroupRecords{{Name: "test.com", Data: poolName}}))
+
+				// Solver of other domain is not used
+				mockCtlr.deleteService(solver)
+				mockCtlr.processResources()
+				solver.Labels[ACMEDomainLabel] = getACMEDomainHash("foo.com")
+				mockCtlr.addService(solver)
+				mockCtlr.processResources()
+				rsCfg = mockCtlr.resources.getPartitionResourceMap(mockCtlr.Partition)["crd_10_8_0_1_80"]
+				Expect(rsCfg.IRulesMap).NotTo(HaveKey(NameRef{Name: iRuleName, Partition: mockCtlr.Partition}), "ACME challenge iRule created for other domain")
+			})
+
+			It("Virtual Server with TLSCertificate", func() {
+				crInf := mockCtlr.newNamespacedCustomResourceInformer(namespace)
+				nrInf := mockCtlr.newNamespacedNativeResourceInformer(namespace)
+				crInf.start()
+				nrInf.start()
+				vs.Spec.PolicyName = ""
+				vs.Spec.VirtualServerAddress = "10.8.0.1"
+				mockCtlr.Partition = "test"
+
+				mockCtlr.addSecret(secret)
+				mockCtlr.processResources()
+				cert := test.NewTLSCertificate("foo-cert", namespace, cisapiv1.TLSCertificateSpec{
+					Secret: secret.Name,
+				})
+				caBundle := test.NewTLSCertificate("backend-ca", namespace, cisapiv1.TLSCertificateSpec{
+					Type:        CABundleType,
+					Certificate: string(secret.Data["tls.crt"]),
+				})
+				mockCtlr.addTLSCertificate(cert)
+				mockCtlr.processResources()
+				mockCtlr.addTLSCertificate(caBundle)
+				mockCtlr.processResources()
+				rsMap := mockCtlr.resources.getPartitionResourceMap(mockCtlr.Partition)
+				Expect(rsMap).To(BeEmpty(), "TLSCertificate stored as virtual")
+				certs := mockCtlr.resources.getPartitionConfig(mockCtlr.Partition).Certificates
+				Expect(certs).To(HaveKey(namespace+"/"+cert.Name), "TLSCertificate not processed")
+				Expect(certs).To(HaveKey(namespace+"/"+caBundle.Name), "TLSCertificate not processed")
+
+				mockCtlr.addTLSProfile(test.NewTLSProfile("sampleTLS", namespace, cisapiv1.TLSProfileSpec{
+					Hosts: []string{"test.com"},
+					TLS: cisapiv1.TLS{
+						Termination: TLSReencrypt,
+						ClientSSL:   cert.Name,
+						ServerSSL:   caBundle.Name,
+						Reference:   TLSCertificateRef,
+					},
+				}))
+				mockCtlr.processResources()
+				mockCtlr.addVirtualServer(vs)
+				mockCtlr.processResources()
+
+				rsCfg := rsMap["crd_10_8_0_1_443"]
+				Expect(rsCfg).NotTo(BeNil(), "VirtualServer not processed")
+				clientProf := rsCfg.customProfiles[SecretKey{Name: cert.Name, ResourceName: rsCfg.GetName()}]
+				Expect(clientProf.SharedCertificates).To(Equal([]string{"default_foo_cert"}))
+				Expect(clientProf.Certificates).To(BeEmpty(), "Certificate copied to the virtual")
+				serverProf := rsCfg.customProfiles[SecretKey{Name: caBundle.Name, ResourceName: rsCfg.GetName()}]
+				Expect(serverProf.SharedCABundle).To(Equal("default_backend_ca"))
+
+				sharedApp := as3Application{}
+				processResourcesForAS3(rsMap, sharedApp, false, mockCtlr.Partition)
+				processCustomProfilesForAS3(rsMap, sharedApp, 3.44)
+				processSharedCertificatesForAS3(certs, sharedApp)
+				svcName := rsCfg.Virtual.Name
+				tlsServer, ok := sharedApp[svcName+"_tls_server"].(*as3TLSServer)
+				Expect(ok).To(BeTrue(), "TLSServer not created")
+				Expect(tlsServer.Certificates).To(Equal([]as3TLSServerCertificates{{Certificate: "default_foo_cert"}}))
+				tlsClient, ok := sharedApp[svcName+"_tls_client"].(*as3TLSClient)
+				Expect(ok).To(BeTrue(), "TLSClient not created")
+				Expect(tlsClient.TrustCA.Use).To(Equal("default_backend_ca"))
+				as3Cert, ok := sharedApp["default_foo_cert"].(*as3Certificate)
+				Expect(ok).To(BeTrue(), "Certificate not created")
+				Expect(as3Cert.PrivateKey).To(Equal(string(secret.Data["tls.key"])))
+				Expect(sharedApp["default_backend_ca"]).To(BeAssignableToTypeOf(&as3CABundle{}), "CA Bundle not created")
+
+				// TLSCertificate of other partition is not referred
+				otherCert := cert.DeepCopy()
+				otherCert.Spec.Partition = "dev"
+				mockCtlr.addTLSCertificate(otherCert)
+				mockCtlr.processResources()
+				Expect(certs).NotTo(HaveKey(namespace+"/"+cert.Name), "TLSCertificate not removed from old partition")
+				Expect(mockCtlr.resources.getPartitionConfig("dev").Certificates).To(HaveKey(namespace+"/"+cert.Name),
+					"TLSCertificate not moved to new partition")
+				_, err := mockCtlr.getReferredSharedCertificate(rsCfg, namespace, cert.Name, CertificateType)
+				Expect(err).To(HaveOccurred(), "TLSCertificate of other partition referred")
+				_, err = mockCtlr.getReferredSharedCertificate(rsCfg, namespace, caBundle.Name, CertificateType)
+				Expect(err).To(HaveOccurred(), "CA bundle referred as certificate")
+
+				mockCtlr.deleteTLSCertificate(otherCert)
+				mockCtlr.processResources()
+				Expect(mockCtlr.resources.getPartitionConfig("dev").Certificates).NotTo(HaveKey(namespace+"/"+cert.Name),
+					"TLSCertificate not deleted")
+			})
+
+			It("Virtual Server with ServiceReferenceGrant", func() {
+				mockCtlr.enforceSvcRefGrants = true
+				defer func() { mockCtlr.enforceSvcRefGrants = false }()
+				mockCtlr.namespaces["foo"] = true
+				Expect(mockCtlr.addNamespacedInformers("foo", false)).To(BeNil(), "Informers Creation Failed")
+
+				vs.Spec.Pools[0].ServiceNamespace = "foo"
+				vs.Spec.Pools[1].AlternateBackends = []cisapiv1.AlternateBackend{
+					{Service: "svc3", ServiceNamespace: "foo"},
+					{Service: "svc4"},
+				}
+				vs.Spec.DefaultPool = cisapiv1.DefaultPool{Reference: ServiceRef, Service: "svc1", ServiceNamespace: "foo"}
+
+				// References to the services of other namespaces are removed without grants
+				virtuals := mockCtlr.filterGrantedServiceReferences([]*cisapiv1.VirtualServer{vs})
+				Expect(len(virtuals)).To(Equal(1))
+				Expect(len(virtuals[0].Spec.Pools)).To(Equal(1), "Pool of other namespace not removed")
+				Expect(virtuals[0].Spec.Pools[0].Service).To(Equal("svc2"))
+				Expect(virtuals[0].Spec.Pools[0].AlternateBackends).To(Equal([]cisapiv1.AlternateBackend{{Service: "svc4"}}))
+				Expect(virtuals[0].Spec.DefaultPool).To(Equal(cisapiv1.DefaultPool{}), "Default pool of other namespace not removed")
+				Expect(len(vs.Spec.Pools)).To(Equal(2), "VirtualServer modified")
+
+				grant := test.NewServiceReferenceGrant("allow-default", "foo", cisapiv1.ServiceReferenceGrantSpec{
+					From:     []cisapiv1.ServiceReferenceGrantFrom{{Namespace: namespace}},
+					Services: []string{"svc1"},
+				})
+				mockCtlr.addServiceReferenceGrant(grant)
+				mockCtlr.processResources()
+				Expect(mockCtlr.isServiceReferenceGranted(namespace, "foo", "svc1")).To(BeTrue())
+				Expect(mockCtlr.isServiceReferenceGranted(namespace, "foo", "svc3")).To(BeFalse())
+				Expect(mockCtlr.isServiceReferenceGranted("bar", "foo", "svc1")).To(BeFalse())
+				virtuals = mockCtlr.filterGrantedServiceReferences([]*cisapiv1.VirtualServer{vs})
+				Expect(len(virtuals[0].Spec.Pools)).To(Equal(2), "Granted pool removed")
+				Expect(len(virtuals[0].Spec.Pools[1].AlternateBackends)).To(Equal(1), "Alternate backend not granted")
+				Expect(virtuals[0].Spec.DefaultPool.Service).To(Equal("svc1"), "Granted default pool removed")
+
+				// Grant without services allows all the services of the namespace
+				mockCtlr.deleteServiceReferenceGrant(grant)
+				mockCtlr.processResources()
+				grant.Spec.Services = nil
+				mockCtlr.addServiceReferenceGrant(grant)
+				mockCtlr.processResources()
+				virtuals = mockCtlr.filterGrantedServiceReferences([]*cisapiv1.VirtualServer{vs})
+				Expect(virtuals[0]).To(BeIdenticalTo(vs), "Granted VirtualServer copied")
+
+				// Grants are not required when they are not enforced
+				mockCtlr.deleteServiceReferenceGrant(grant)
+				mockCtlr.processResources()
+				mockCtlr.enforceSvcRefGrants = false
+				Expect(mockCtlr.isServiceReferenceGranted(namespace, "foo", "svc1")).To(BeTrue())
+			})
+
+			It("Virtual Server with named servicePort", func() {
+				crInf := mockCtlr.newNamespacedCustomResourceInformer(namespace)
+				nrInf := mockCtlr.newNamespacedNativeResourceInformer(namespace)
+				crInf.start()
+				nrInf.start()
+				vs.Spec.PolicyName = ""
+				vs.Spec.TLSProfileName = ""
+				vs.Spec.VirtualServerAddress = "10.8.0.1"
+				vs.Spec.Pools = vs.Spec.Pools[:1]
+				vs.Spec.Pools[0].ServicePort = intstr.FromString("http")
+				vs.Spec.RewriteAppRoot = ""
+				mockCtlr.Partition = "test"
+
+				svcPorts := []v1.ServicePort{{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080)}}
+				svc := test.NewService("svc1", "1", namespace, v1.ServiceTypeClusterIP, svcPorts)
+				mockCtlr.addService(svc)
+				mockCtlr.processResources()
+				mockCtlr.addVirtualServer(vs)
+				mockCtlr.processResources()
+
+				rsMap := mockCtlr.resources.getPartitionResourceMap(mockCtlr.Partition)
+				rsCfg := rsMap["crd_10_8_0_1_80"]
+				Expect(rsCfg).NotTo(BeNil(), "VirtualServer not processed")
+				Expect(len(rsCfg.Pools)).To(Equal(1), "Pool not created")
+				Expect(rsCfg.Pools[0].ServicePort).To(Equal(intstr.FromInt(8080)), "Named servicePort not resolved")
+
+				// servicePort is resolved again once the service port is renamed
+				svc = svc.DeepCopy()
+				svc.Spec.Ports[0].Name = "web"
+				mockCtlr.updateService(svc)
+				mockCtlr.enqueueService(svc, "")
+				mockCtlr.processResources()
+				rsCfg = rsMap["crd_10_8_0_1_80"]
+				Expect(rsCfg.Pools[0].ServicePort).To(Equal(intstr.IntOrString{}), "Renamed service port in use")
+
+				svc = svc.DeepCopy()
+				svc.Spec.Ports[0].Name = "http"
+				svc.Spec.Ports[0].TargetPort = intstr.FromInt(9090)
+				mockCtlr.updateService(svc)
+				mockCtlr.enqueueService(svc, "")
+				mockCtlr.processResources()
+				rsCfg = rsMap["crd_10_8_0_1_80"]
+				Expect(rsCfg.Pools[0].ServicePort).To(Equal(intstr.FromInt(9090)), "Updated targetPort not resolved")
+			})
+
+			It("Virtual Server with HealthMonitor", func() {
+				crInf := mockCtlr.newNamespacedCustomResourceInformer(namespace)
+				nrInf := mockCtlr.newNamespacedNativeResourceInformer(namespace)
+				crInf.start()
+				nrInf.start()
+				vs.Spec.PolicyName = ""
+				vs.Spec.TLSProfileName = ""
+				vs.Spec.VirtualServerAddress = "10.8.0.1"
+				vs.Spec.RewriteAppRoot = ""
+				vs.Spec.Pools[0].Monitor = cisapiv1.Monitor{Name: "http-check", Reference: HealthMonitorRef}
+				vs.Spec.Pools[1].Monitor = cisapiv1.Monitor{}
+				vs.Spec.Pools[1].Monitors = []cisapiv1.Monitor{{Name: "http-check", Reference: HealthMonitorRef}}
+				mockCtlr.Partition = "test"
+
+				hm := test.NewHealthMonitor("http-check", namespace, cisapiv1.HealthMonitorSpec{
+					Type:     HTTP,
+					Send:     "GET /health",
+					Interval: 10,
+					Timeout:  31,
+				})
+				mockCtlr.addHealthMonitor(hm)
+				mockCtlr.processResources()
+				rsMap := mockCtlr.resources.getPartitionResourceMap(mockCtlr.Partition)
+				Expect(rsMap).To(BeEmpty(), "HealthMonitor stored as virtual")
+				monitors := mockCtlr.resources.getPartitionConfig(mockCtlr.Partition).Monitors
+				Expect(monitors).To(HaveKey(namespace+"/"+hm.Name), "HealthMonitor not processed")
+
+				mockCtlr.addVirtualServer(vs)
+				mockCtlr.processResources()
+				rsCfg := rsMap["crd_10_8_0_1_80"]
+				Expect(rsCfg).NotTo(BeNil(), "VirtualServer not processed")
+				Expect(rsCfg.Monitors).To(BeEmpty(), "Monitor created for the virtual")
+				monitorName := MonitorName{Name: JoinBigipPath(mockCtlr.Partition, "default_http_check")}
+				Expect(len(rsCfg.Pools)).To(Equal(2))
+				for _, pool := range rsCfg.Pools {
+					Expect(pool.MonitorNames).To(Equal([]MonitorName{monitorName}), "HealthMonitor not referred")
+				}
+
+				sharedApp := as3Application{}
+				processResourcesForAS3(rsMap, sharedApp, false, mockCtlr.Partition)
+				processSharedMonitorsForAS3(monitors, sharedApp)
+				monitor, ok := sharedApp["default_http_check"].(*as3Monitor)
+				Expect(ok).To(BeTrue(), "Monitor not created")
+				Expect(monitor.Send).To(Equal("GET /health"))
+				Expect(monitor.Interval).To(Equal(10))
+				pool, ok := sharedApp[rsCfg.Pools[0].Name].(*as3Pool)
+				Expect(ok).To(BeTrue(), "Pool not created")
+				Expect(pool.Monitors).To(Equal([]as3ResourcePointer{{Use: "/test/Shared/default_http_check"}}))
+
+				// SSL options are supported only for the https and grpc monitors
+				mockCtlr.addTLSCertificate(test.NewTLSCertificate("client-cert", namespace, cisapiv1.TLSCertificateSpec{
+					Certificate: "cert",
+					PrivateKey:  "key",
+				}))
+				mockCtlr.processResources()
+				grpcMonitor := test.NewHealthMonitor("grpc-check", namespace, cisapiv1.HealthMonitorSpec{
+					Type:              GRPCMonitor,
+					Send:              "GET /health",
+					ClientCertificate: "client-cert",
+				})
+				Expect(mockCtlr.checkValidHealthMonitor(grpcMonitor)).To(BeTrue())
+				Expect(getMonitorForHealthMonitor(grpcMonitor, "test").Type).To(Equal(HTTP2Monitor))
+				Expect(getMonitorForHealthMonitor(grpcMonitor, "test").ClientCertificate).To(Equal("default_client_cert"))
+				grpcMonitor.Spec.ClientCertificate = "unknown"
+				Expect(mockCtlr.checkValidHealthMonitor(grpcMonitor)).To(BeFalse(), "Missing client certificate accepted")
+				grpcMonitor.Spec.Type = "tcp"
+				grpcMonitor.Spec.ClientCertificate = "client-cert"
+				Expect(mockCtlr.checkValidHealthMonitor(grpcMonitor)).To(BeFalse(), "SSL options accepted for tcp")
+
+				// Pools stop referring the deleted HealthMonitor
+				mockCtlr.deleteHealthMonitor(hm)
+				mockCtlr.processResources()
+				Expect(monitors).NotTo(HaveKey(namespace+"/"+hm.Name), "HealthMonitor not deleted")
+				rsCfg = rsMap["crd_10_8_0_1_80"]
+				for _, pool := range rsCfg.Pools {
+					Expect(pool.MonitorNames).To(BeEmpty(), "Deleted HealthMonitor referred")
+				}
+			})
 		})
 
 		Describe("Processing Transport Server", func() {
@@ -2271,15 +3864,44 @@ var _ = Describe("Worker Tests", func() {
 				mockCtlr.processResources()
 				Expect(len(mockCtlr.resources.ltmConfig)).To(Equal(1), "Transport Server not processed")
 
+				// with SNI routes
+				ts.Spec.SNIRoutes = []cisapiv1.SNIRoute{
+					{Host: "foo.com", Pool: cisapiv1.Pool{Service: "svc1", ServicePort: intstr.FromInt(8080)}},
+				}
+				ts.Spec.Mode = "performance"
+				Expect(mockCtlr.checkValidTransportServer(ts)).To(BeFalse(), "SNI routes in performance mode")
+				ts.Spec.Mode = "standard"
+				Expect(mockCtlr.checkValidTransportServer(ts)).To(BeTrue(), "Invalid SNI routes")
+				ts.Spec.SNIRoutes = append(ts.Spec.SNIRoutes,
+					cisapiv1.SNIRoute{Host: "FOO.com", Pool: cisapiv1.Pool{Service: "svc1", ServicePort: intstr.FromInt(9090)}})
+				Expect(mockCtlr.checkValidTransportServer(ts)).To(BeFalse(), "Duplicate host in SNI routes")
+				ts.Spec.SNIRoutes = nil
+				ts.Spec.ProxyProtocol = ProxyProtocolV1
+				Expect(mockCtlr.checkValidTransportServer(ts)).To(BeTrue(), "Invalid PROXY protocol")
+				ts.Spec.Mode = "performance"
+				Expect(mockCtlr.checkValidTransportServer(ts)).To(BeFalse(), "PROXY protocol in performance mode")
+				ts.Spec.ProxyProtocol = ""
+
+				// with fastL4 idle timeout
+				ts.Spec.FastL4 = cisapiv1.FastL4{IdleTimeout: -1}
+				Expect(mockCtlr.checkValidTransportServer(ts)).To(BeTrue(), "Invalid fastL4 infinite idle timeout")
+				ts.Spec.FastL4.IdleTimeout = 86401
+				Expect(mockCtlr.checkValidTransportServer(ts)).To(BeFalse(), "Invalid fastL4 idle timeout")
+				ts.Spec.FastL4.IdleTimeout = -2
+				Expect(mockCtlr.checkValidTransportServer(ts)).To(BeFalse(), "Invalid fastL4 idle timeout")
+				ts.Spec.FastL4 = cisapiv1.FastL4{}
+				ts.Spec.Mode = ""
+
 				rscUpdateMeta := resourceStatusMeta{
 					0,
 					make(map[string]struct{}),
+					nil,
 				}
 
 				mockCtlr.Agent.respChan <- rscUpdateMeta
 
 				config := ResourceConfigRequest{
-					ltmConfig:  mockCtlr.resources.getLTMConfigDeepCopy(),
+					ltmConfig:  mockCtlr.resources.getLTMConfigSnapshot(),
 					shareNodes: mockCtlr.shareNodes,
 					gtmConfig:  mockCtlr.resources.getGTMConfigCopy(),
 				}
@@ -2300,6 +3922,50 @@ var _ = Describe("Worker Tests", func() {
 
 			})
 
+			It("Transport Server with ServiceReferenceGrant", func() {
+				mockCtlr.enforceSvcRefGrants = true
+				defer func() { mockCtlr.enforceSvcRefGrants = false }()
+				mockCtlr.namespaces["foo"] = true
+				Expect(mockCtlr.addNamespacedInformers("foo", false)).To(BeNil(), "Informers Creation Failed")
+				mockCtlr.addPolicy(policy)
+				mockCtlr.processResources()
+
+				// TransportServer referring the service of other namespace is not processed without grants
+				ts.Spec.Pool.ServiceNamespace = "foo"
+				mockCtlr.addTransportServer(ts)
+				mockCtlr.processResources()
+				rsMap := mockCtlr.resources.getPartitionResourceMap(mockCtlr.Partition)
+				Expect(len(rsMap)).To(Equal(0), "TransportServer of not granted service processed")
+
+				grant := test.NewServiceReferenceGrant("allow-default", "foo", cisapiv1.ServiceReferenceGrantSpec{
+					From:     []cisapiv1.ServiceReferenceGrantFrom{{Namespace: namespace}},
+					Services: []string{"svc1"},
+				})
+				mockCtlr.addServiceReferenceGrant(grant)
+				mockCtlr.processResources()
+				rsMap = mockCtlr.resources.getPartitionResourceMap(mockCtlr.Partition)
+				Expect(len(rsMap)).To(Equal(1), "TransportServer of granted service not processed")
+
+				// SNI routes to the services not granted are removed
+				ts.Spec.SNIRoutes = []cisapiv1.SNIRoute{
+					{Host: "foo.com", Pool: cisapiv1.Pool{Service: "svc1", ServicePort: intstr.FromInt(8080)}},
+					{Host: "bar.com", Pool: cisapiv1.Pool{Service: "svc3", ServiceNamespace: "foo",
+						ServicePort: intstr.FromInt(8080)}},
+				}
+				granted, denied := mockCtlr.getGrantedTransportServer(ts)
+				Expect(denied).To(BeFalse())
+				Expect(len(granted.Spec.SNIRoutes)).To(Equal(1), "SNI route of not granted service not removed")
+				Expect(granted.Spec.SNIRoutes[0].Host).To(Equal("foo.com"))
+				Expect(len(ts.Spec.SNIRoutes)).To(Equal(2), "TransportServer modified")
+				ts.Spec.SNIRoutes = nil
+
+				// TransportServer is removed when the grant is deleted
+				mockCtlr.deleteServiceReferenceGrant(grant)
+				mockCtlr.processResources()
+				rsMap = mockCtlr.resources.getPartitionResourceMap(mockCtlr.Partition)
+				Expect(len(rsMap)).To(Equal(0), "TransportServer not removed with the grant")
+			})
+
 			It("Transport Server with IPAM", func() {
 				go mockCtlr.Agent.agentWorker()
 				go mockCtlr.Agent.retryWorker()
@@ -2648,6 +4314,266 @@ var _ = Describe("Worker Tests", func() {
 
 			})
 
+			It("Auto generated WideIP for Virtual Server", func() {
+				mockCtlr.autoGenerateWideIP = true
+				defer func() { mockCtlr.autoGenerateWideIP = false }()
+				mockCtlr.addEndpoints(fooEndpts)
+				mockCtlr.processResources()
+
+				svc := test.NewService("svc1", "1", namespace, "NodePort", fooPorts)
+				mockCtlr.addService(svc)
+				mockCtlr.processResources()
+
+				vs := test.NewVirtualServer(
+					"SampleVS",
+					namespace,
+					cisapiv1.VirtualServerSpec{
+						Host:                 "test.com",
+						VirtualServerAddress: "10.1.1.1",
+						Pools: []cisapiv1.Pool{
+							{
+								Path:        "/",
+								Service:     "svc1",
+								ServicePort: intstr.IntOrString{IntVal: 80},
+							},
+						},
+					},
+				)
+				vs.Annotations = map[string]string{
+					GSLBZoneAnnotation:       "test.com",
+					GSLBDataServerAnnotation: "/Common/DataServer",
+				}
+				mockCtlr.addVirtualServer(vs)
+				mockCtlr.processResources()
+				wip, ok := mockCtlr.resources.gtmConfig[DEFAULT_GTM_PARTITION].WideIPs["test.com"]
+				Expect(ok).To(BeTrue(), "WideIP not generated for Virtual Server")
+				Expect(isAutoGeneratedWideIP(wip)).To(BeTrue())
+				Expect(wip.Pools[0].DataServer).To(Equal("/Common/DataServer"), "Invalid data server")
+				Expect(len(wip.Pools[0].Members)).To(Equal(1), "Invalid pool member count")
+
+				// ExternalDNS takes precedence over the generated WideIP
+				mockCtlr.addEDNS(newEDNS)
+				mockCtlr.processResources()
+				wip = mockCtlr.resources.gtmConfig[DEFAULT_GTM_PARTITION].WideIPs["test.com"]
+				Expect(isAutoGeneratedWideIP(wip)).To(BeFalse(), "ExternalDNS not processed")
+
+				mockCtlr.deleteEDNS(newEDNS)
+				mockCtlr.processResources()
+				wip, ok = mockCtlr.resources.gtmConfig[DEFAULT_GTM_PARTITION].WideIPs["test.com"]
+				Expect(ok).To(BeTrue(), "WideIP not generated after ExternalDNS deletion")
+				Expect(isAutoGeneratedWideIP(wip)).To(BeTrue())
+
+				// Oldest Virtual Server of the host generates the WideIP
+				vs2 := vs.DeepCopy()
+				vs2.Name = "SampleVS2"
+				vs2.CreationTimestamp = metav1.NewTime(vs.CreationTimestamp.Add(time.Minute))
+				vs2.Spec.Pools[0].Path = "/foo"
+				vs2.Annotations[GSLBDataServerAnnotation] = "/Common/DataServer2"
+				mockCtlr.addVirtualServer(vs2)
+				mockCtlr.processResources()
+				wip = mockCtlr.resources.gtmConfig[DEFAULT_GTM_PARTITION].WideIPs["test.com"]
+				Expect(wip.Pools[0].DataServer).To(Equal("/Common/DataServer"), "Invalid data server")
+
+				mockCtlr.deleteVirtualServer(vs)
+				mockCtlr.processResources()
+				wip, ok = mockCtlr.resources.gtmConfig[DEFAULT_GTM_PARTITION].WideIPs["test.com"]
+				Expect(ok).To(BeTrue(), "WideIP not generated from the other Virtual Server of the host")
+				Expect(wip.Pools[0].DataServer).To(Equal("/Common/DataServer2"), "Invalid data server")
+
+				// WideIP of the old host is removed when the host is updated
+				newVS2 := vs2.DeepCopy()
+				newVS2.Spec.Host = "foo.test.com"
+				mockCtlr.updateVirtualServer(vs2, newVS2)
+				mockCtlr.processResources()
+				mockCtlr.processResources()
+				_, ok = mockCtlr.resources.gtmConfig[DEFAULT_GTM_PARTITION].WideIPs["test.com"]
+				Expect(ok).To(BeFalse(), "WideIP of the old host not deleted")
+				_, ok = mockCtlr.resources.gtmConfig[DEFAULT_GTM_PARTITION].WideIPs["foo.test.com"]
+				Expect(ok).To(BeTrue(), "WideIP not generated for the new host")
+
+				// WideIP is removed when the GSLB annotations are removed
+				vs2 = newVS2
+				newVS2 = vs2.DeepCopy()
+				newVS2.Annotations = nil
+				mockCtlr.updateVirtualServer(vs2, newVS2)
+				mockCtlr.processResources()
+				_, ok = mockCtlr.resources.gtmConfig[DEFAULT_GTM_PARTITION].WideIPs["foo.test.com"]
+				Expect(ok).To(BeFalse(), "Generated WideIP not deleted")
+
+				// Host outside the GSLB zone is not published
+				vs.Annotations[GSLBZoneAnnotation] = "example.com"
+				_, err := newExternalDNSForVirtualServer(vs)
+				Expect(err).NotTo(BeNil())
+			})
+
+		})
+
+		Describe("Processing Ingress", func() {
+			var ing *netv1.Ingress
+			BeforeEach(func() {
+				mockCtlr.enableCRDIngress = true
+				mockCtlr.crInformers["default"] = mockCtlr.newNamespacedCustomResourceInformer("default")
+				exact := netv1.PathTypeExact
+				prefix := netv1.PathTypePrefix
+				ing = test.NewIngressNetV1("ing1", "1", namespace,
+					netv1.IngressSpec{
+						DefaultBackend: &netv1.IngressBackend{
+							Service: &netv1.IngressServiceBackend{
+								Name: "svc3",
+								Port: netv1.ServiceBackendPort{Number: 80},
+							},
+						},
+						Rules: []netv1.IngressRule{{
+							Host: "foo.com",
+							IngressRuleValue: netv1.IngressRuleValue{
+								HTTP: &netv1.HTTPIngressRuleValue{
+									Paths: []netv1.HTTPIngressPath{
+										{
+											Path:     "/foo",
+											PathType: &exact,
+											Backend: netv1.IngressBackend{
+												Service: &netv1.IngressServiceBackend{
+													Name: "svc1",
+													Port: netv1.ServiceBackendPort{Number: 80},
+												},
+											},
+										},
+										{
+											Path:     "/bar",
+											PathType: &prefix,
+											Backend: netv1.IngressBackend{
+												Service: &netv1.IngressServiceBackend{
+													Name: "svc2",
+													Port: netv1.ServiceBackendPort{Name: "http"},
+												},
+											},
+										},
+									},
+								},
+							},
+						}},
+					},
+					map[string]string{IngressVSAddressAnnotation: "10.8.0.2"})
+			})
+			AfterEach(func() {
+				mockCtlr.enableCRDIngress = false
+			})
+
+			It("Ingress", func() {
+				mockCtlr.addIngress(ing)
+				mockCtlr.processResources()
+
+				rsMap := mockCtlr.resources.getPartitionResourceMap(mockCtlr.Partition)
+				rsCfg, ok := rsMap["crd_10_8_0_2_80"]
+				Expect(ok).To(BeTrue(), "Ingress not processed")
+				Expect(rsCfg.MetaData.baseResources["default/ing1"]).To(Equal(Ingress))
+				Expect(rsCfg.MetaData.hosts).To(Equal([]string{"foo.com"}))
+				Expect(len(rsCfg.Pools)).To(Equal(3), "Default backend and path pools not created")
+				Expect(rsCfg.Virtual.PoolName).NotTo(BeEmpty(), "Default backend not set as default pool")
+
+				var exactRule, prefixRule bool
+				for _, rule := range rsCfg.Policies[0].Rules {
+					for _, cnd := range rule.Conditions {
+						if cnd.Path && cnd.Equals && reflect.DeepEqual(cnd.Values, []string{"/foo"}) {
+							exactRule = true
+						}
+						if cnd.PathSegment && reflect.DeepEqual(cnd.Values, []string{"bar"}) {
+							prefixRule = true
+						}
+					}
+				}
+				Expect(exactRule).To(BeTrue(), "Exact pathType not translated to path condition")
+				Expect(prefixRule).To(BeTrue(), "Prefix pathType not translated to path segment condition")
+
+				// Ingresses of other classes are not processed
+				nginxIng := ing.DeepCopy()
+				nginxIng.Annotations = map[string]string{
+					IngressClassAnnotation:     "nginx",
+					IngressVSAddressAnnotation: "10.8.0.3",
+				}
+				nginxIng.Name = "ing2"
+				mockCtlr.addIngress(nginxIng)
+				Expect(mockCtlr.resourceQueue.Len()).To(Equal(0), "Ingress of other class queued")
+				Expect(mockCtlr.getVirtualServersForIngress(nginxIng)).To(BeEmpty(), "Ingress of other class processed")
+
+				// TLS hosts are served on HTTPS with HTTP traffic redirected
+				newIng := ing.DeepCopy()
+				newIng.Spec.TLS = []netv1.IngressTLS{{Hosts: []string{"foo.com"}, SecretName: "foo-secret"}}
+				mockCtlr.updateIngress(ing, newIng)
+				mockCtlr.processResources()
+				vs := mockCtlr.getVirtualServersForIngress(newIng)
+				Expect(len(vs)).To(Equal(1))
+				Expect(vs[0].Spec.TLSProfileName).To(Equal("foo-secret"))
+				Expect(vs[0].Spec.HTTPTraffic).To(Equal(TLSRedirectInsecure))
+				tlsProf := mockCtlr.getTLSProfileForVirtualServer(vs[0], namespace)
+				Expect(tlsProf.Spec.TLS.Termination).To(Equal(TLSEdge))
+				Expect(tlsProf.Spec.TLS.ClientSSL).To(Equal("foo-secret"))
+
+				mockCtlr.deleteIngress(newIng)
+				mockCtlr.processResources()
+				Expect(len(mockCtlr.resources.getPartitionResourceMap(mockCtlr.Partition))).To(Equal(0), "Ingress not deleted")
+			})
+		})
+
+		Describe("Processing DataGroup", func() {
+			It("DataGroup", func() {
+				dg := test.NewDataGroup("allowed-hosts", namespace, cisapiv1.DataGroupSpec{
+					Type: "string",
+					Records: map[string]string{
+						"www.example.com": "web_pool",
+						"api.example.com": "api_pool",
+					},
+				})
+				mockCtlr.addDataGroup(dg)
+				mockCtlr.processResources()
+
+				dgKey := namespace + "/" + dg.Name
+				Expect(mockCtlr.resources.getPartitionResourceMap(mockCtlr.Partition)).To(BeEmpty(),
+					"DataGroup stored as virtual")
+				dataGroups := mockCtlr.resources.getPartitionConfig(mockCtlr.Partition).DataGroups
+				Expect(dataGroups).To(HaveKey(dgKey), "DataGroup not processed")
+
+				sharedApp := as3Application{}
+				processSharedDataGroupsForAS3(dataGroups, sharedApp)
+				as3DG, ok := sharedApp[getDataGroupName(namespace, "allowed-hosts")].(*as3DataGroup)
+				Expect(ok).To(BeTrue(), "Data group not created")
+				Expect(as3DG.KeyDataType).To(Equal("string"))
+				Expect(as3DG.Records).To(Equal([]as3Record{
+					{Key: "api.example.com", Value: "api_pool"},
+					{Key: "www.example.com", Value: "web_pool"},
+				}))
+
+				// DataGroup moved to another partition
+				newDG := dg.DeepCopy()
+				newDG.Spec.Partition = "dev"
+				mockCtlr.updateDataGroup(newDG)
+				mockCtlr.processResources()
+				_, ok = dataGroups[dgKey]
+				Expect(ok).To(BeFalse(), "DataGroup not removed from old partition")
+				_, ok = mockCtlr.resources.getPartitionConfig("dev").DataGroups[dgKey]
+				Expect(ok).To(BeTrue(), "DataGroup not moved to new partition")
+
+				// Records not matching the type are rejected
+				invalidDG := newDG.DeepCopy()
+				invalidDG.Spec.Type = "integer"
+				mockCtlr.updateDataGroup(invalidDG)
+				mockCtlr.processResources()
+				_, ok = mockCtlr.resources.getPartitionConfig("dev").DataGroups[dgKey]
+				Expect(ok).To(BeFalse(), "Invalid DataGroup processed")
+
+				ipDG := newDG.DeepCopy()
+				ipDG.Spec.Type = "ip"
+				ipDG.Spec.Records = map[string]string{"10.10.0.0/16": "internal", "192.168.1.10": "admin"}
+				mockCtlr.updateDataGroup(ipDG)
+				mockCtlr.processResources()
+				_, ok = mockCtlr.resources.getPartitionConfig("dev").DataGroups[dgKey]
+				Expect(ok).To(BeTrue(), "Valid ip DataGroup not processed")
+
+				mockCtlr.deleteDataGroup(ipDG)
+				mockCtlr.processResources()
+				_, ok = mockCtlr.resources.getPartitionConfig("dev").DataGroups[dgKey]
+				Expect(ok).To(BeFalse(), "DataGroup not deleted")
+			})
 		})
 
 		Describe("Processing Ingress Link", func() {
@@ -3704,6 +5630,7 @@ extendedRouteSpec:
 				rscUpdateMeta := resourceStatusMeta{
 					0,
 					make(map[string]struct{}),
+					nil,
 				}
 
 				mockCtlr.routeClientV1.Routes("default").Create(context.TODO(), route1, metav1.CreateOptions{})
@@ -3713,7 +5640,7 @@ extendedRouteSpec:
 				mockCtlr.Agent.respChan <- rscUpdateMeta
 
 				config := ResourceConfigRequest{
-					ltmConfig:  mockCtlr.resources.getLTMConfigDeepCopy(),
+					ltmConfig:  mockCtlr.resources.getLTMConfigSnapshot(),
 					shareNodes: mockCtlr.shareNodes,
 					gtmConfig:  mockCtlr.resources.getGTMConfigCopy(),
 				}
@@ -0,0 +1,451 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+)
+
+const (
+	xcOriginPools       = "origin_pools"
+	xcHTTPLoadBalancers = "http_loadbalancers"
+	xcTCPLoadBalancers  = "tcp_loadbalancers"
+
+	// labels of the F5 Distributed Cloud objects created by CIS
+	xcOwnerLabel     = "cis.f5.com/owner"
+	xcPartitionLabel = "cis.f5.com/partition"
+
+	// sites are present in the system namespace of the tenant
+	xcSystemNamespace = "system"
+	xcMaxNameLength   = 64
+)
+
+// xcObjectKinds lists the kinds in the order of creation, objects are deleted in the reverse order
+var xcObjectKinds = []string{xcOriginPools, xcHTTPLoadBalancers, xcTCPLoadBalancers}
+
+var xcInvalidNameChars = regexp.MustCompile("[^a-z0-9-]+")
+
+func NewXCManager(params AgentParams) *XCManager {
+	return &XCManager{
+		httpClient:    &http.Client{Timeout: timeoutLarge},
+		XCParams:      params.XCParams,
+		owner:         params.Partition,
+		xcObjectCache: make(map[string]string),
+	}
+}
+
+// postXCConfig translates the configuration to F5 Distributed Cloud objects and posts them
+func (xcMgr *XCManager) postXCConfig(rsConfig ResourceConfigRequest) (map[string]struct{}, bool) {
+	return xcMgr.syncXCObjects(xcMgr.createXCObjects(rsConfig.ltmConfig))
+}
+
+// getXCObjectName formats the name as per the F5 Distributed Cloud object names
+func getXCObjectName(partition, name string) string {
+	xcName := strings.Trim(xcInvalidNameChars.ReplaceAllString(strings.ToLower(partition+"-"+name), "-"), "-")
+	if len(xcName) <= xcMaxNameLength {
+		return xcName
+	}
+	// long names are truncated with the hash of the name to keep them unique
+	hash := sha256.Sum256([]byte(xcName))
+	return strings.TrimRight(xcName[:xcMaxNameLength-9], "-") + "-" + hex.EncodeToString(hash[:])[:8]
+}
+
+func (xcMgr *XCManager) getXCObjectRef(partition, name string) xcObjectRef {
+	ps := strings.Split(name, "/")
+	return xcObjectRef{
+		Name:      getXCObjectName(partition, ps[len(ps)-1]),
+		Namespace: xcMgr.XCNamespace,
+	}
+}
+
+func (xcMgr *XCManager) newXCObject(partition, name string, spec interface{}) xcObject {
+	return xcObject{
+		Metadata: xcMetadata{
+			Name:      getXCObjectName(partition, name),
+			Namespace: xcMgr.XCNamespace,
+			Labels: map[string]string{
+				xcOwnerLabel:     xcMgr.owner,
+				xcPartitionLabel: partition,
+			},
+			Description: "Auto-generated by CIS",
+		},
+		Spec: spec,
+	}
+}
+
+// createXCObjects translates the VirtualServers and TransportServers of the configuration to
+// origin pools and HTTP/TCP load balancers, key of the objects is kind/name
+func (xcMgr *XCManager) createXCObjects(ltmConfig LTMConfig) map[string]xcObject {
+	objs := make(map[string]xcObject)
+	for partition, partitionConfig := range ltmConfig {
+		for _, cfg := range partitionConfig.ResourceMap {
+			if cfg.MetaData.ResourceType != VirtualServer && cfg.MetaData.ResourceType != TransportServer {
+				continue
+			}
+			// pools without members are skipped, as origin pools need at least one origin server
+			pools := make(map[string]struct{})
+			for _, pool := range cfg.Pools {
+				if originPool, ok := xcMgr.createXCOriginPool(partition, pool); ok {
+					objs[xcOriginPools+"/"+originPool.Metadata.Name] = originPool
+					pools[originPool.Metadata.Name] = struct{}{}
+				}
+			}
+			var lb xcObject
+			var kind string
+			var ok bool
+			if cfg.MetaData.ResourceType == VirtualServer {
+				lb, ok = xcMgr.createXCHTTPLoadBalancer(partition, cfg, pools)
+				kind = xcHTTPLoadBalancers
+			} else {
+				lb, ok = xcMgr.createXCTCPLoadBalancer(partition, cfg, pools)
+				kind = xcTCPLoadBalancers
+			}
+			if ok {
+				objs[kind+"/"+lb.Metadata.Name] = lb
+			}
+		}
+	}
+	return objs
+}
+
+// getXCLoadBalancerAlgorithm returns the F5 Distributed Cloud algorithm for the pool load balancing method
+func getXCLoadBalancerAlgorithm(balance string) string {
+	switch {
+	case strings.HasPrefix(balance, "least-connections"):
+		return "LEAST_REQUEST"
+	case balance == "" || balance == "round-robin":
+	default:
+		log.Warningf("[XC] Load balancing method %v is not supported, using ROUND_ROBIN", balance)
+	}
+	return "ROUND_ROBIN"
+}
+
+func (xcMgr *XCManager) createXCOriginPool(partition string, pool Pool) (xcObject, bool) {
+	if len(pool.Members) == 0 {
+		log.Debugf("[XC] Skipping pool %v without members", pool.Name)
+		return xcObject{}, false
+	}
+	originPool := xcOriginPool{
+		// origin pool has a single port for all the origin servers
+		Port:                  pool.Members[0].Port,
+		LoadBalancerAlgorithm: getXCLoadBalancerAlgorithm(pool.Balance),
+		EndpointSelection:     "LOCAL_PREFERRED",
+		NoTLS:                 &struct{}{},
+	}
+	addresses := make(map[string]struct{})
+	for _, member := range pool.Members {
+		if member.Port != originPool.Port {
+			log.Warningf("[XC] Skipping member %v:%v of pool %v, origin servers should use port %v",
+				member.Address, member.Port, pool.Name, originPool.Port)
+			continue
+		}
+		if _, ok := addresses[member.Address]; ok {
+			continue
+		}
+		addresses[member.Address] = struct{}{}
+		server := xcOriginServer{}
+		if xcMgr.XCSite != "" {
+			server.PrivateIP = &xcOriginServerIP{
+				IP: member.Address,
+				SiteLocator: &xcSiteLocator{
+					Site: xcObjectRef{Name: xcMgr.XCSite, Namespace: xcSystemNamespace},
+				},
+				InsideNetwork: &struct{}{},
+			}
+		} else {
+			server.PublicIP = &xcOriginServerIP{IP: member.Address}
+		}
+		originPool.OriginServers = append(originPool.OriginServers, server)
+	}
+	return xcMgr.newXCObject(partition, pool.Name, originPool), true
+}
+
+// getXCPoolWeights returns the references of the origin pools, pools not created are skipped
+func (xcMgr *XCManager) getXCPoolWeights(partition, poolName string, pools map[string]struct{}) []xcPoolWeight {
+	ref := xcMgr.getXCObjectRef(partition, poolName)
+	if _, ok := pools[ref.Name]; !ok {
+		return nil
+	}
+	return []xcPoolWeight{{Pool: ref, Weight: 1, Priority: 1}}
+}
+
+func (xcMgr *XCManager) createXCHTTPLoadBalancer(partition string, cfg *ResourceConfig, pools map[string]struct{}) (xcObject, bool) {
+	// HTTP virtual of the VirtualServer with TLS is served by the HTTPS load balancer
+	if cfg.MetaData.Protocol == HTTP &&
+		(cfg.MetaData.httpTraffic == TLSRedirectInsecure || cfg.MetaData.httpTraffic == TLSNoInsecure) {
+		return xcObject{}, false
+	}
+	var domains []string
+	hosts := make(map[string]struct{})
+	for _, host := range cfg.MetaData.hosts {
+		if _, ok := hosts[host]; ok || host == "" {
+			continue
+		}
+		hosts[host] = struct{}{}
+		domains = append(domains, host)
+	}
+	if len(domains) == 0 {
+		log.Warningf("[XC] Skipping virtual %v, VirtualServer without host is not supported", cfg.Virtual.Name)
+		return xcObject{}, false
+	}
+	_, port := extractVirtualAddressAndPort(cfg.Virtual.Destination)
+
+	lb := xcHTTPLoadBalancer{
+		Domains:                     domains,
+		AdvertiseOnPublicDefaultVIP: &struct{}{},
+	}
+	if cfg.MetaData.Protocol == HTTPS {
+		// certificates are managed by F5 Distributed Cloud, TLSProfiles are not translated
+		lb.HTTPSAutoCert = &xcHTTPSAutoCert{
+			Port:         port,
+			HTTPRedirect: cfg.MetaData.httpTraffic == TLSRedirectInsecure,
+		}
+	} else {
+		lb.HTTP = &xcHTTP{Port: port}
+	}
+	if cfg.Virtual.PoolName != "" && cfg.MetaData.defaultPoolType != BIGIP {
+		lb.DefaultRoutePools = xcMgr.getXCPoolWeights(partition, cfg.Virtual.PoolName, pools)
+	}
+	for _, policy := range cfg.Policies {
+		for _, rl := range policy.Rules {
+			if route, ok := xcMgr.createXCRoute(partition, rl, pools); ok {
+				lb.Routes = append(lb.Routes, route)
+			}
+		}
+	}
+	return xcMgr.newXCObject(partition, cfg.Virtual.Name, lb), true
+}
+
+// createXCRoute translates the forward rule of the LTM policy to the route of the HTTP load balancer
+func (xcMgr *XCManager) createXCRoute(partition string, rl *Rule, pools map[string]struct{}) (xcRoute, bool) {
+	var originPools []xcPoolWeight
+	for _, act := range rl.Actions {
+		if act.Forward && act.Pool != "" {
+			originPools = xcMgr.getXCPoolWeights(partition, act.Pool, pools)
+		}
+	}
+	if len(originPools) == 0 {
+		log.Debugf("[XC] Skipping rule %v without origin pool", rl.Name)
+		return xcRoute{}, false
+	}
+	route := xcSimpleRoute{
+		HTTPMethod:  "ANY",
+		OriginPools: originPools,
+		Path:        xcPathMatch{Prefix: "/"},
+	}
+	if i := strings.Index(rl.FullURI, "/"); i >= 0 {
+		route.Path.Prefix = rl.FullURI[i:]
+	}
+	for _, cnd := range rl.Conditions {
+		switch {
+		case cnd.HTTPHost && cnd.Equals:
+			route.Headers = append(route.Headers, xcHeaderMatch{Name: "Host", Exact: cnd.Values[0]})
+		case cnd.HTTPHost && cnd.EndsWith:
+			route.Headers = append(route.Headers, xcHeaderMatch{
+				Name:  "Host",
+				Regex: "^[^.]+" + regexp.QuoteMeta(cnd.Values[0]) + "$",
+			})
+		case cnd.HTTPURI && cnd.Path && cnd.Equals:
+			route.Path = xcPathMatch{Path: cnd.Values[0]}
+		}
+	}
+	return xcRoute{SimpleRoute: route}, true
+}
+
+func (xcMgr *XCManager) createXCTCPLoadBalancer(partition string, cfg *ResourceConfig, pools map[string]struct{}) (xcObject, bool) {
+	if cfg.Virtual.IpProtocol == "udp" {
+		log.Warningf("[XC] Skipping virtual %v, UDP TransportServer is not supported", cfg.Virtual.Name)
+		return xcObject{}, false
+	}
+	originPools := xcMgr.getXCPoolWeights(partition, cfg.Virtual.PoolName, pools)
+	if len(originPools) == 0 {
+		log.Warningf("[XC] Skipping virtual %v without origin pool", cfg.Virtual.Name)
+		return xcObject{}, false
+	}
+	_, port := extractVirtualAddressAndPort(cfg.Virtual.Destination)
+	lb := xcTCPLoadBalancer{
+		Domains:                     cfg.MetaData.hosts,
+		ListenPort:                  port,
+		AdvertiseOnPublicDefaultVIP: &struct{}{},
+		OriginPoolsWeights:          originPools,
+	}
+	return xcMgr.newXCObject(partition, cfg.Virtual.Name, lb), true
+}
+
+func (xcMgr *XCManager) getXCAPIURL(kind string) string {
+	return fmt.Sprintf("%s/api/config/namespaces/%s/%s",
+		strings.TrimSuffix(xcMgr.XCAPIURL, "/"), xcMgr.XCNamespace, kind)
+}
+
+// syncXCObjects creates, replaces and deletes the objects in F5 Distributed Cloud as per the objects of
+// the configuration, it returns the partitions of the failed objects and false when any request failed
+func (xcMgr *XCManager) syncXCObjects(objs map[string]xcObject) (map[string]struct{}, bool) {
+	failedPartitions := make(map[string]struct{})
+	ok := true
+	if !xcMgr.cacheSynced {
+		xcMgr.cacheSynced = xcMgr.fetchXCObjects()
+		if !xcMgr.cacheSynced {
+			ok = false
+		}
+	}
+
+	keys := make([]string, 0, len(objs))
+	for key := range objs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, kind := range xcObjectKinds {
+		for _, key := range keys {
+			if !strings.HasPrefix(key, kind+"/") {
+				continue
+			}
+			obj := objs[key]
+			body, _ := json.Marshal(obj)
+			if cached, found := xcMgr.xcObjectCache[key]; found && cached == string(body) {
+				continue
+			}
+			if !xcMgr.postXCObject(kind, obj.Metadata.Name, body) {
+				failedPartitions[obj.Metadata.Labels[xcPartitionLabel]] = struct{}{}
+				ok = false
+				continue
+			}
+			xcMgr.xcObjectCache[key] = string(body)
+		}
+	}
+
+	// load balancers are deleted ahead of the origin pools they refer
+	var deleted []string
+	for key := range xcMgr.xcObjectCache {
+		if _, found := objs[key]; !found {
+			deleted = append(deleted, key)
+		}
+	}
+	sort.Strings(deleted)
+	for i := len(xcObjectKinds) - 1; i >= 0; i-- {
+		kind := xcObjectKinds[i]
+		for _, key := range deleted {
+			if !strings.HasPrefix(key, kind+"/") {
+				continue
+			}
+			if !xcMgr.deleteXCObject(kind, strings.TrimPrefix(key, kind+"/")) {
+				ok = false
+				continue
+			}
+			delete(xcMgr.xcObjectCache, key)
+		}
+	}
+	return failedPartitions, ok
+}
+
+// postXCObject replaces the object when it exists, creates it otherwise
+func (xcMgr *XCManager) postXCObject(kind, name string, body []byte) bool {
+	if _, found := xcMgr.xcObjectCache[kind+"/"+name]; !found {
+		status, _ := xcMgr.xcRequest(http.MethodPost, xcMgr.getXCAPIURL(kind), body)
+		switch status {
+		case http.StatusOK, http.StatusCreated:
+			log.Debugf("[XC] Created %v %v", kind, name)
+			return true
+		case http.StatusConflict:
+			// object created by an earlier CIS run
+		default:
+			return false
+		}
+	}
+	status, _ := xcMgr.xcRequest(http.MethodPut, xcMgr.getXCAPIURL(kind)+"/"+name, body)
+	if status != http.StatusOK {
+		return false
+	}
+	log.Debugf("[XC] Updated %v %v", kind, name)
+	return true
+}
+
+func (xcMgr *XCManager) deleteXCObject(kind, name string) bool {
+	status, _ := xcMgr.xcRequest(http.MethodDelete, xcMgr.getXCAPIURL(kind)+"/"+name, nil)
+	if status != http.StatusOK && status != http.StatusNotFound {
+		return false
+	}
+	log.Debugf("[XC] Deleted %v %v", kind, name)
+	return true
+}
+
+// fetchXCObjects adds the objects created by CIS in earlier runs to the cache, so that
+// the objects not present in the configuration are deleted
+func (xcMgr *XCManager) fetchXCObjects() bool {
+	for _, kind := range xcObjectKinds {
+		labelFilter := url.QueryEscape(fmt.Sprintf("%s=%s", xcOwnerLabel, xcMgr.owner))
+		status, response := xcMgr.xcRequest(http.MethodGet, xcMgr.getXCAPIURL(kind)+"?label_filter="+labelFilter, nil)
+		if status != http.StatusOK {
+			return false
+		}
+		items, _ := response["items"].([]interface{})
+		for _, item := range items {
+			if obj, ok := item.(map[string]interface{}); ok {
+				if name, ok := obj["name"].(string); ok {
+					if _, found := xcMgr.xcObjectCache[kind+"/"+name]; !found {
+						xcMgr.xcObjectCache[kind+"/"+name] = ""
+					}
+				}
+			}
+		}
+	}
+	return true
+}
+
+// xcRequest sends the request to F5 Distributed Cloud API and returns the status code and response
+func (xcMgr *XCManager) xcRequest(method, apiURL string, body []byte) (int, map[string]interface{}) {
+	req, err := http.NewRequest(method, apiURL, bytes.NewBuffer(body))
+	if err != nil {
+		log.Errorf("[XC] Creating new HTTP request error: %v ", err)
+		return 0, nil
+	}
+	req.Header.Set("Authorization", "APIToken "+xcMgr.XCAPIToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	log.Debugf("[XC] %v request on %v", method, apiURL)
+	httpResp, err := xcMgr.httpClient.Do(req)
+	if err != nil {
+		log.Errorf("[XC] REST call error: %v ", err)
+		return 0, nil
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		log.Errorf("[XC] REST call response error: %v ", err)
+		return 0, nil
+	}
+	var response map[string]interface{}
+	_ = json.Unmarshal(respBody, &response)
+	if httpResp.StatusCode >= http.StatusBadRequest && httpResp.StatusCode != http.StatusConflict &&
+		!(method == http.MethodDelete && httpResp.StatusCode == http.StatusNotFound) {
+		log.Errorf("[XC] %v request on %v failed with status code %v: %v",
+			method, apiURL, httpResp.StatusCode, string(respBody))
+	}
+	return httpResp.StatusCode, response
+}
@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	mockhc "github.com/f5devcentral/mockhttpclient"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("F5 Distributed Cloud Agent", func() {
+	var xcMgr *XCManager
+	var ltmConfig LTMConfig
+
+	newResponse := func(status int, body string) *http.Response {
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+		}
+	}
+
+	BeforeEach(func() {
+		xcMgr = NewXCManager(AgentParams{
+			Partition: "test",
+			XCParams: XCParams{
+				XCAPIURL:    "https://acme.console.ves.volterra.io",
+				XCAPIToken:  "token",
+				XCNamespace: "cis",
+			},
+		})
+
+		rsCfg := &ResourceConfig{}
+		rsCfg.MetaData.ResourceType = VirtualServer
+		rsCfg.MetaData.Protocol = HTTP
+		rsCfg.MetaData.hosts = []string{"foo.com"}
+		rsCfg.Virtual.Name = "crd_10_1_1_1_80"
+		rsCfg.Virtual.Destination = "/test/10.1.1.1:80"
+		rsCfg.Pools = Pools{
+			{
+				Name:    "svc1_80_default_foo_com",
+				Balance: "least-connections-member",
+				Members: []PoolMember{
+					{Address: "10.244.1.1", Port: 8080},
+					{Address: "10.244.1.2", Port: 8080},
+					{Address: "10.244.1.2", Port: 8080},
+				},
+			},
+			{
+				Name: "svc2_80_default_foo_com",
+			},
+		}
+		rl, err := createRule("foo.com/path", "svc1_80_default_foo_com", "vs_foo_com_path", nil, "", false)
+		Expect(err).To(BeNil())
+		rl2, err := createRule("foo.com/empty", "svc2_80_default_foo_com", "vs_foo_com_empty", nil, "", false)
+		Expect(err).To(BeNil())
+		rsCfg.Policies = Policies{{Name: "crd_10_1_1_1_80_test_policy", Rules: Rules{rl, rl2}}}
+
+		tsCfg := &ResourceConfig{}
+		tsCfg.MetaData.ResourceType = TransportServer
+		tsCfg.Virtual.Name = "crd_10_1_1_1_8443"
+		tsCfg.Virtual.Destination = "/test/10.1.1.1:8443"
+		tsCfg.Virtual.PoolName = "svc3_443_default"
+		tsCfg.Pools = Pools{
+			{
+				Name:    "svc3_443_default",
+				Members: []PoolMember{{Address: "10.244.1.3", Port: 443}},
+			},
+		}
+
+		ltmConfig = LTMConfig{
+			"test": &PartitionConfig{ResourceMap: ResourceMap{
+				rsCfg.Virtual.Name: rsCfg,
+				tsCfg.Virtual.Name: tsCfg,
+			}},
+		}
+	})
+
+	It("Formats object names", func() {
+		Expect(getXCObjectName("test", "svc1_80_default_foo.com")).To(Equal("test-svc1-80-default-foo-com"))
+		name := getXCObjectName("test", "svc1_80_default_a_very_long_host_name_of_the_virtual_server_foo_com")
+		Expect(len(name)).To(BeNumerically("<=", xcMaxNameLength))
+		Expect(name).NotTo(Equal(getXCObjectName("test", "svc1_80_default_a_very_long_host_name_of_the_virtual_server_bar_com")))
+	})
+
+	It("Translates VirtualServer and TransportServer to load balancers", func() {
+		objs := xcMgr.createXCObjects(ltmConfig)
+		Expect(len(objs)).To(Equal(4))
+
+		pool, ok := objs["origin_pools/test-svc1-80-default-foo-com"]
+		Expect(ok).To(BeTrue())
+		Expect(pool.Metadata.Namespace).To(Equal("cis"))
+		Expect(pool.Metadata.Labels).To(Equal(map[string]string{xcOwnerLabel: "test", xcPartitionLabel: "test"}))
+		originPool := pool.Spec.(xcOriginPool)
+		Expect(originPool.Port).To(Equal(int32(8080)))
+		Expect(originPool.LoadBalancerAlgorithm).To(Equal("LEAST_REQUEST"))
+		Expect(originPool.OriginServers).To(HaveLen(2))
+		Expect(originPool.OriginServers[0].PublicIP.IP).To(Equal("10.244.1.1"))
+
+		lb, ok := objs["http_loadbalancers/test-crd-10-1-1-1-80"]
+		Expect(ok).To(BeTrue())
+		httpLB := lb.Spec.(xcHTTPLoadBalancer)
+		Expect(httpLB.Domains).To(Equal([]string{"foo.com"}))
+		Expect(httpLB.HTTP.Port).To(Equal(80))
+		// route of the pool without members is skipped
+		Expect(httpLB.Routes).To(HaveLen(1))
+		route := httpLB.Routes[0].SimpleRoute
+		Expect(route.Path.Prefix).To(Equal("/path"))
+		Expect(route.Headers).To(Equal([]xcHeaderMatch{{Name: "Host", Exact: "foo.com"}}))
+		Expect(route.OriginPools[0].Pool).To(Equal(xcObjectRef{Name: "test-svc1-80-default-foo-com", Namespace: "cis"}))
+
+		lb, ok = objs["tcp_loadbalancers/test-crd-10-1-1-1-8443"]
+		Expect(ok).To(BeTrue())
+		tcpLB := lb.Spec.(xcTCPLoadBalancer)
+		Expect(tcpLB.ListenPort).To(Equal(8443))
+		Expect(tcpLB.OriginPoolsWeights[0].Pool.Name).To(Equal("test-svc3-443-default"))
+
+		// HTTPS virtual with XC managed certificate and origin servers reached through the site
+		xcMgr.XCSite = "site1"
+		rsCfg := ltmConfig["test"].ResourceMap["crd_10_1_1_1_80"]
+		rsCfg.MetaData.Protocol = HTTPS
+		rsCfg.MetaData.httpTraffic = TLSRedirectInsecure
+		rsCfg.Virtual.Destination = "/test/10.1.1.1:443"
+		objs = xcMgr.createXCObjects(ltmConfig)
+		httpLB = objs["http_loadbalancers/test-crd-10-1-1-1-80"].Spec.(xcHTTPLoadBalancer)
+		Expect(httpLB.HTTP).To(BeNil())
+		Expect(*httpLB.HTTPSAutoCert).To(Equal(xcHTTPSAutoCert{Port: 443, HTTPRedirect: true}))
+		originPool = objs["origin_pools/test-svc1-80-default-foo-com"].Spec.(xcOriginPool)
+		Expect(originPool.OriginServers[0].PublicIP).To(BeNil())
+		Expect(originPool.OriginServers[0].PrivateIP.SiteLocator.Site).To(Equal(xcObjectRef{Name: "site1", Namespace: "system"}))
+
+		// HTTP virtual redirecting to HTTPS is served by the HTTPS load balancer
+		rsCfg.MetaData.Protocol = HTTP
+		objs = xcMgr.createXCObjects(ltmConfig)
+		_, ok = objs["http_loadbalancers/test-crd-10-1-1-1-80"]
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Syncs the objects with F5 Distributed Cloud", func() {
+		responseMap := mockhc.ResponseConfigMap{
+			http.MethodGet: &mockhc.ResponseConfig{
+				Responses: []*http.Response{
+					newResponse(http.StatusOK, `{"items": [{"name": "test-svc1-80-default-foo-com"}, {"name": "test-stale-pool"}]}`),
+					newResponse(http.StatusOK, `{"items": []}`),
+					newResponse(http.StatusOK, `{"items": []}`),
+				},
+			},
+			http.MethodPut: &mockhc.ResponseConfig{
+				Responses: []*http.Response{newResponse(http.StatusOK, `{}`), newResponse(http.StatusOK, `{}`)},
+			},
+			http.MethodPost: &mockhc.ResponseConfig{
+				Responses: []*http.Response{
+					newResponse(http.StatusOK, `{}`),
+					newResponse(http.StatusConflict, `{"code": 409}`),
+					newResponse(http.StatusBadRequest, `{"code": 400}`),
+				},
+			},
+			http.MethodDelete: &mockhc.ResponseConfig{
+				Responses: []*http.Response{newResponse(http.StatusNotFound, `{}`)},
+			},
+		}
+		client, _ := mockhc.NewMockHTTPClient(responseMap)
+		xcMgr.httpClient = client
+
+		objs := xcMgr.createXCObjects(ltmConfig)
+		failedPartitions, ok := xcMgr.syncXCObjects(objs)
+		// existing pool is replaced, new pool is created, HTTP load balancer is replaced on conflict
+		// and TCP load balancer fails
+		Expect(ok).To(BeFalse())
+		Expect(failedPartitions).To(HaveKey("test"))
+		Expect(xcMgr.cacheSynced).To(BeTrue())
+		Expect(xcMgr.xcObjectCache).To(HaveLen(3))
+		Expect(xcMgr.xcObjectCache).NotTo(HaveKey("origin_pools/test-stale-pool"))
+		Expect(xcMgr.xcObjectCache).NotTo(HaveKey("tcp_loadbalancers/test-crd-10-1-1-1-8443"))
+		body, _ := json.Marshal(objs["http_loadbalancers/test-crd-10-1-1-1-80"])
+		Expect(xcMgr.xcObjectCache["http_loadbalancers/test-crd-10-1-1-1-80"]).To(Equal(string(body)))
+
+		// Unchanged objects are not posted again
+		responseMap = mockhc.ResponseConfigMap{
+			http.MethodPost: &mockhc.ResponseConfig{
+				Responses: []*http.Response{newResponse(http.StatusOK, `{}`)},
+			},
+			http.MethodDelete: &mockhc.ResponseConfig{
+				Responses: []*http.Response{newResponse(http.StatusOK, `{}`)},
+			},
+		}
+		client, _ = mockhc.NewMockHTTPClient(responseMap)
+		xcMgr.httpClient = client
+		failedPartitions, ok = xcMgr.syncXCObjects(objs)
+		Expect(ok).To(BeTrue())
+		Expect(failedPartitions).To(BeEmpty())
+		Expect(xcMgr.xcObjectCache).To(HaveLen(4))
+
+		// Objects of the deleted resources are deleted
+		delete(objs, "tcp_loadbalancers/test-crd-10-1-1-1-8443")
+		failedPartitions, ok = xcMgr.syncXCObjects(objs)
+		Expect(ok).To(BeTrue())
+		Expect(xcMgr.xcObjectCache).To(HaveLen(3))
+		Expect(xcMgr.xcObjectCache).NotTo(HaveKey("tcp_loadbalancers/test-crd-10-1-1-1-8443"))
+	})
+})
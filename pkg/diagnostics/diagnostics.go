@@ -0,0 +1,157 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package diagnostics serves the pprof profiles, the runtime statistics and the log level of the controller on
+// localhost.
+// net/http/pprof and expvar are not imported as they register their handlers on the default mux served on
+// --http-listen-address, and expvar exposes the command line with the BIG-IP credentials
+package diagnostics
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+)
+
+const (
+	// DefaultPort is the port of the diagnostics endpoint
+	DefaultPort = 6060
+
+	// DefaultCPUProfileSeconds is the duration of the CPU profile when the seconds are not provided
+	DefaultCPUProfileSeconds = 30
+
+	// maxCPUProfileSeconds limits the duration of the CPU profile
+	maxCPUProfileSeconds = 300
+
+	pprofPath    = "/debug/pprof/"
+	varsPath     = "/debug/vars"
+	logLevelPath = "/loglevel"
+)
+
+var startTime = time.Now()
+
+// Handler returns the handler of the CPU profile, the runtime profiles of pprof, the runtime statistics and the
+// log level
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(pprofPath, profileHandler)
+	mux.HandleFunc(pprofPath+"profile", cpuProfileHandler)
+	mux.HandleFunc(varsPath, varsHandler)
+	mux.Handle(logLevelPath, log.LogLevelHandler())
+	return mux
+}
+
+// Start serves the diagnostics endpoint on the port of localhost, profiles are reachable only from the pod or
+// with kubectl port-forward
+func Start(port int) {
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+	log.Infof("[INIT] Serving the diagnostics endpoint on %v", addr)
+	go func() {
+		if err := http.ListenAndServe(addr, Handler()); err != nil {
+			log.Errorf("[INIT] Unable to serve the diagnostics endpoint on %v: %v", addr, err)
+		}
+	}()
+}
+
+// profileHandler writes the runtime profile of the path, the index of the profiles is written for the base path
+func profileHandler(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, pprofPath)
+	if name == "" {
+		writeIndex(w)
+		return
+	}
+	profile := pprof.Lookup(name)
+	if profile == nil {
+		http.Error(w, fmt.Sprintf("Unknown profile %v", name), http.StatusNotFound)
+		return
+	}
+	debug, _ := strconv.Atoi(r.FormValue("debug"))
+	if name == "heap" && r.FormValue("gc") != "" {
+		runtime.GC()
+	}
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if debug != 0 {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%v"`, name))
+	}
+	if err := profile.WriteTo(w, debug); err != nil {
+		log.Errorf("Unable to write the %v profile: %v", name, err)
+	}
+}
+
+// cpuProfileHandler writes the CPU profile of the seconds of the request
+func cpuProfileHandler(w http.ResponseWriter, r *http.Request) {
+	seconds, err := strconv.Atoi(r.FormValue("seconds"))
+	if err != nil || seconds <= 0 {
+		seconds = DefaultCPUProfileSeconds
+	}
+	if seconds > maxCPUProfileSeconds {
+		seconds = maxCPUProfileSeconds
+	}
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", `attachment; filename="profile"`)
+	// only one CPU profile can be taken at a time
+	if err := pprof.StartCPUProfile(w); err != nil {
+		w.Header().Del("Content-Disposition")
+		http.Error(w, fmt.Sprintf("Unable to start the CPU profile: %v", err), http.StatusInternalServerError)
+		return
+	}
+	select {
+	case <-time.After(time.Duration(seconds) * time.Second):
+	case <-r.Context().Done():
+	}
+	pprof.StopCPUProfile()
+}
+
+// varsHandler writes the runtime statistics of the controller in JSON
+func varsHandler(w http.ResponseWriter, r *http.Request) {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	vars := map[string]interface{}{
+		"goroutines": runtime.NumGoroutine(),
+		"gomaxprocs": runtime.GOMAXPROCS(0),
+		"numCPU":     runtime.NumCPU(),
+		"goVersion":  runtime.Version(),
+		"uptime":     time.Since(startTime).Round(time.Second).String(),
+		"memstats":   memStats,
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(vars); err != nil {
+		log.Errorf("Unable to write the runtime statistics: %v", err)
+	}
+}
+
+// writeIndex writes the links of the runtime profiles and the CPU profile
+func writeIndex(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintf(w, "%vprofile?seconds=%v\n", pprofPath, DefaultCPUProfileSeconds)
+	for _, profile := range pprof.Profiles() {
+		fmt.Fprintf(w, "%v%v?debug=1 (%v)\n", pprofPath, profile.Name(), profile.Count())
+	}
+	fmt.Fprintf(w, "%v\n", varsPath)
+	fmt.Fprintf(w, "%v\n", logLevelPath)
+}
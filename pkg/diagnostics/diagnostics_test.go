@@ -0,0 +1,85 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Diagnostics", func() {
+	serve := func(url string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		Handler().ServeHTTP(rec, httptest.NewRequest("GET", url, nil))
+		return rec
+	}
+
+	It("Serves the index and the runtime profiles", func() {
+		rec := serve("/debug/pprof/")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring("/debug/pprof/profile?seconds=30"))
+		Expect(rec.Body.String()).To(ContainSubstring("/debug/pprof/goroutine?debug=1"))
+		Expect(rec.Body.String()).To(ContainSubstring("/debug/pprof/heap?debug=1"))
+
+		rec = serve("/debug/pprof/goroutine?debug=1")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring("goroutine profile:"))
+
+		rec = serve("/debug/pprof/heap?gc=1")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Content-Type")).To(Equal("application/octet-stream"))
+		Expect(rec.Body.Len()).NotTo(BeZero())
+
+		rec = serve("/debug/pprof/unknown")
+		Expect(rec.Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("Serves the CPU profile", func() {
+		rec := serve("/debug/pprof/profile?seconds=1")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Content-Disposition")).To(Equal(`attachment; filename="profile"`))
+		Expect(rec.Body.Len()).NotTo(BeZero())
+	})
+
+	It("Serves the runtime statistics without the command line", func() {
+		rec := serve("/debug/vars")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var vars map[string]interface{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &vars)).To(Succeed())
+		Expect(vars).To(HaveKey("goroutines"))
+		Expect(vars).To(HaveKey("memstats"))
+		Expect(vars).NotTo(HaveKey("cmdline"))
+	})
+
+	It("Serves the log level", func() {
+		defer log.SetLogLevel(log.GetLogLevel())
+		log.SetLogLevel(log.LL_INFO)
+		rec := serve("/loglevel")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal("info"))
+
+		// log level is switched while the messages are logged
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				log.Debugf("Log level %v", log.GetLogLevel())
+			}
+		}()
+		rec = httptest.NewRecorder()
+		Handler().ServeHTTP(rec, httptest.NewRequest("PUT", "/loglevel?level=debug", nil))
+		wg.Wait()
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(log.GetLogLevel()).To(Equal(log.LogLevel(log.LL_DEBUG)))
+
+		rec = httptest.NewRecorder()
+		Handler().ServeHTTP(rec, httptest.NewRequest("PUT", "/loglevel?level=verbose", nil))
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		Expect(log.GetLogLevel()).To(Equal(log.LogLevel(log.LL_DEBUG)))
+	})
+})
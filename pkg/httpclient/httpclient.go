@@ -0,0 +1,133 @@
+/*-
+ * Copyright (c) 2016-2021, F5 Networks, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package httpclient provides the http client of the REST calls of the agents to BIG-IP
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/prometheus"
+	log "github.com/F5Networks/k8s-bigip-ctlr/v2/pkg/vlogger"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	// DefaultRequestTimeout of the REST calls, including the synchronous AS3 declarations
+	DefaultRequestTimeout = 180 * time.Second
+	// DefaultConnectTimeout of establishing the connection and the TLS handshake with BIG-IP
+	DefaultConnectTimeout = 30 * time.Second
+	// DefaultMaxIdleConnsPerHost is the idle connections to BIG-IP kept alive for the REST calls
+	DefaultMaxIdleConnsPerHost = 10
+	// idle connections are closed after the timeout, before BIG-IP closes them
+	idleConnTimeout = 90 * time.Second
+)
+
+// Params of the http client of the REST calls to BIG-IP
+type Params struct {
+	TrustedCerts string
+	SSLInsecure  bool
+	// Instrument the client with the http client metrics
+	Metrics bool
+	// Timeouts of the REST calls and of establishing the connections, defaults are used when 0
+	RequestTimeout time.Duration
+	ConnectTimeout time.Duration
+	// Idle connections per host kept alive for the REST calls, keep-alive is disabled when 0
+	MaxIdleConnsPerHost int
+	// Proxy the REST calls with the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+	ProxyFromEnvironment bool
+}
+
+// GetRequestTimeout returns the timeout of the REST calls
+func (params Params) GetRequestTimeout() time.Duration {
+	if params.RequestTimeout > 0 {
+		return params.RequestTimeout
+	}
+	return DefaultRequestTimeout
+}
+
+// getConnectTimeout returns the timeout of establishing the connections
+func (params Params) getConnectTimeout() time.Duration {
+	if params.ConnectTimeout > 0 {
+		return params.ConnectTimeout
+	}
+	return DefaultConnectTimeout
+}
+
+// NewClient returns the http client of the REST calls with the connections to BIG-IP pooled and kept alive
+func NewClient(params Params) *http.Client {
+	// Get the SystemCertPool, continue with an empty pool on error
+	rootCAs, _ := x509.SystemCertPool()
+	if rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+	// Append our certs to the system pool
+	if ok := rootCAs.AppendCertsFromPEM([]byte(params.TrustedCerts)); !ok {
+		log.Debug("[AS3] No certs appended, using only system certs")
+	}
+
+	connectTimeout := params.getConnectTimeout()
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{
+			InsecureSkipVerify: params.SSLInsecure,
+			RootCAs:            rootCAs,
+		},
+		DialContext: (&net.Dialer{
+			Timeout:   connectTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout: connectTimeout,
+		MaxIdleConns:        params.MaxIdleConnsPerHost,
+		MaxIdleConnsPerHost: params.MaxIdleConnsPerHost,
+		IdleConnTimeout:     idleConnTimeout,
+		DisableKeepAlives:   params.MaxIdleConnsPerHost == 0,
+	}
+	if params.ProxyFromEnvironment {
+		tr.Proxy = http.ProxyFromEnvironment
+	}
+
+	var roundTripper http.RoundTripper = tr
+	if params.Metrics {
+		log.Debug("[BIGIP] Http client instrumented with metrics!")
+		roundTripper = promhttp.InstrumentRoundTripperInFlight(prometheus.ClientInFlightGauge,
+			promhttp.InstrumentRoundTripperCounter(prometheus.ClientAPIRequestsCounter,
+				promhttp.InstrumentRoundTripperTrace(prometheus.ClientTrace,
+					promhttp.InstrumentRoundTripperDuration(prometheus.ClientHistVec, tr),
+				),
+			),
+		)
+	}
+	return &http.Client{
+		Transport: roundTripper,
+		Timeout:   params.GetRequestTimeout(),
+	}
+}
+
+// WithRequestContext returns the request with the context of the REST call, which is cancelled after the
+// request timeout or with the parent context. cancel is called once the response body is read
+func WithRequestContext(parent context.Context, req *http.Request,
+	timeout time.Duration) (*http.Request, context.CancelFunc) {
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	return req.WithContext(ctx), cancel
+}
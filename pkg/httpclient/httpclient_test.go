@@ -0,0 +1,69 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HTTP Client", func() {
+	It("Creates the client with the timeouts, keep-alive and proxy", func() {
+		client := NewClient(Params{})
+		Expect(client.Timeout).To(Equal(DefaultRequestTimeout))
+		tr := client.Transport.(*http.Transport)
+		Expect(tr.TLSHandshakeTimeout).To(Equal(DefaultConnectTimeout))
+		Expect(tr.DisableKeepAlives).To(BeTrue())
+		Expect(tr.Proxy).To(BeNil())
+
+		client = NewClient(Params{RequestTimeout: 10 * time.Second, ConnectTimeout: 5 * time.Second,
+			MaxIdleConnsPerHost: DefaultMaxIdleConnsPerHost, ProxyFromEnvironment: true, SSLInsecure: true})
+		Expect(client.Timeout).To(Equal(10 * time.Second))
+		tr = client.Transport.(*http.Transport)
+		Expect(tr.TLSHandshakeTimeout).To(Equal(5 * time.Second))
+		Expect(tr.DisableKeepAlives).To(BeFalse())
+		Expect(tr.MaxIdleConnsPerHost).To(Equal(DefaultMaxIdleConnsPerHost))
+		Expect(tr.Proxy).NotTo(BeNil())
+		Expect(tr.TLSClientConfig.InsecureSkipVerify).To(BeTrue())
+
+		client = NewClient(Params{Metrics: true})
+		_, ok := client.Transport.(*http.Transport)
+		Expect(ok).To(BeFalse(), "Client not instrumented with metrics")
+	})
+
+	It("Cancels the REST calls with the request context", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		}))
+		defer server.Close()
+		client := NewClient(Params{MaxIdleConnsPerHost: 1})
+
+		// REST call exceeding the request timeout
+		req, _ := http.NewRequest("POST", server.URL, nil)
+		req, cancel := WithRequestContext(context.Background(), req, 50*time.Millisecond)
+		start := time.Now()
+		_, err := client.Do(req)
+		cancel()
+		Expect(err).NotTo(BeNil())
+		Expect(time.Since(start) < 5*time.Second).To(BeTrue())
+
+		// REST call cancelled with the parent context
+		parent, stop := context.WithCancel(context.Background())
+		req, _ = http.NewRequest("POST", server.URL, nil)
+		req, cancel = WithRequestContext(parent, req, DefaultRequestTimeout)
+		defer cancel()
+		go func() {
+			<-time.After(50 * time.Millisecond)
+			stop()
+		}()
+		_, err = client.Do(req)
+		Expect(err).NotTo(BeNil())
+		Expect(parent.Err()).To(Equal(context.Canceled))
+	})
+})
@@ -32,6 +32,101 @@ var CurrentErrors = prometheus.NewGaugeVec(
 	[]string{},
 )
 
+var AS3OptimisticLockConflicts = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "bigip_as3_optimistic_lock_conflicts_total",
+		Help: "Total count of AS3 tenants modified on BIG-IP by other clients.",
+	},
+	[]string{"tenant"},
+)
+
+var AS3DeclarationSize = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "bigip_as3_declaration_size_bytes",
+	Help: "Size of the last AS3 declaration generated by the BigIP k8s CTLR.",
+})
+
+var CircuitBreakerOpen = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "bigip_circuit_breaker_open",
+	Help: "Set to 1 when the controller stopped posting to the unreachable BigIP.",
+})
+
+var BigIPRESTFrameworkUnhealthy = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "bigip_rest_framework_unhealthy",
+	Help: "Set to 1 when restjavad or restnoded of the BigIP are not serving the REST calls.",
+})
+
+var BigIPRESTFrameworkErrors = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "bigip_rest_framework_errors_total",
+		Help: "Total count of REST calls failed by restjavad or restnoded of the BigIP.",
+	},
+	[]string{"code"},
+)
+
+var BigIPProvisionedModules = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "bigip_provisioned_modules",
+		Help: "Modules provisioned on the BigIP with their provisioning level.",
+	},
+	[]string{"module", "level"},
+)
+
+var BigIPVirtualServers = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "bigip_virtual_servers",
+		Help: "Total count of virtual servers on the BigIP by partition.",
+	},
+	[]string{"partition"},
+)
+
+var ManagedVirtualServers = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "bigip_managed_virtual_servers",
+	Help: "Total count of virtual servers managed by the BigIP k8s CTLR.",
+})
+
+var BigIPThroughputLimit = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "bigip_license_throughput_limit_mbps",
+	Help: "Throughput limit of the BigIP license in Mbps, 0 when the license does not limit the throughput.",
+})
+
+var PoolMemberCurrentConnections = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "bigip_pool_member_current_connections",
+		Help: "Current server side connections of the pool members managed by the BigIP k8s CTLR.",
+	},
+	[]string{"partition", "pool", "member", "namespace", "service", "pod"},
+)
+
+var PoolMemberTotalConnections = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "bigip_pool_member_connections_total",
+		Help: "Counter of the total server side connections of the pool members managed by the BigIP k8s CTLR, " +
+			"read from the pool member statistics of the BigIP.",
+	},
+	[]string{"partition", "pool", "member", "namespace", "service", "pod"},
+)
+
+var PoolMemberAvailable = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "bigip_pool_member_available",
+		Help: "Set to 1 when the pool member managed by the BigIP k8s CTLR is available on the BigIP.",
+	},
+	[]string{"partition", "pool", "member", "namespace", "service", "pod"},
+)
+
+var SuppressedNodeEvents = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "bigip_suppressed_node_events_total",
+	Help: "Total count of node events processed with the batch of the earlier node event.",
+})
+
+var NodeStatus = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "bigip_node_status",
+		Help: "Nodes monitored by the BigIP k8s CTLR with their operating system and status as the pool members.",
+	},
+	[]string{"cluster", "node", "os", "status"},
+)
+
 var ClientInFlightGauge = prometheus.NewGauge(prometheus.GaugeOpts{
 	Name: "bigip_http_client_in_flight_requests",
 	Help: "Total count of in-flight requests for the wrapped http client.",
@@ -96,6 +191,20 @@ func RegisterMetrics(httpClientMetrics bool) {
 			MonitoredNodes,
 			MonitoredServices,
 			CurrentErrors,
+			AS3OptimisticLockConflicts,
+			AS3DeclarationSize,
+			CircuitBreakerOpen,
+			BigIPRESTFrameworkUnhealthy,
+			BigIPRESTFrameworkErrors,
+			BigIPProvisionedModules,
+			BigIPVirtualServers,
+			ManagedVirtualServers,
+			BigIPThroughputLimit,
+			PoolMemberCurrentConnections,
+			PoolMemberTotalConnections,
+			PoolMemberAvailable,
+			SuppressedNodeEvents,
+			NodeStatus,
 			ClientInFlightGauge,
 			ClientAPIRequestsCounter,
 			ClientDNSLatencyVec,
@@ -107,6 +216,20 @@ func RegisterMetrics(httpClientMetrics bool) {
 			MonitoredNodes,
 			MonitoredServices,
 			CurrentErrors,
+			AS3OptimisticLockConflicts,
+			AS3DeclarationSize,
+			CircuitBreakerOpen,
+			BigIPRESTFrameworkUnhealthy,
+			BigIPRESTFrameworkErrors,
+			BigIPProvisionedModules,
+			BigIPVirtualServers,
+			ManagedVirtualServers,
+			BigIPThroughputLimit,
+			PoolMemberCurrentConnections,
+			PoolMemberTotalConnections,
+			PoolMemberAvailable,
+			SuppressedNodeEvents,
+			NodeStatus,
 		)
 	}
 }
@@ -2754,10 +2754,11 @@ func CreatePolicy(rls Rules, policyName, partition string) *Policy {
 	return &plcy
 }
 
-func (cm *AgentCfgMap) Init(n string, ns string, d string, l map[string]string, getEP func(string, string) ([]Member, error)) {
+func (cm *AgentCfgMap) Init(n string, ns string, d string, l map[string]string, a map[string]string, getEP func(string, string) ([]Member, error)) {
 	cm.Name = n
 	cm.Namespace = ns
 	cm.Data = d
 	cm.Label = l
+	cm.Annotation = a
 	cm.GetEndpoints = getEP
 }
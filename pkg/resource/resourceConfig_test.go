@@ -2215,10 +2215,12 @@ var _ = Describe("Resource Config Tests", func() {
 		It("Test AgentCfgMap Init", func() {
 			cm := &AgentCfgMap{}
 			label := make(map[string]string)
+			annotation := map[string]string{F5OverrideAS3PriorityAnnotation: "10"}
 			getEP := func(string, string) ([]Member, error) { return nil, nil }
-			cm.Init("test1", "default", "", label, getEP)
+			cm.Init("test1", "default", "", label, annotation, getEP)
 			Expect(cm.Name).To(Equal("test1"))
 			Expect(cm.Namespace).To(Equal("default"))
+			Expect(cm.Annotation).To(Equal(annotation))
 		})
 		It("Test UpdatePolicy", func() {
 			rs := &Resources{
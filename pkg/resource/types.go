@@ -420,6 +420,7 @@ type (
 		Name         string
 		Namespace    string
 		Label        map[string]string
+		Annotation   map[string]string
 	}
 
 	AgentResources struct {
@@ -521,6 +522,8 @@ const F5ServerSslSecureAnnotation = "virtual-server.f5.com/secure-serverssl"
 const DefaultSslServerCAName = "openshift_route_cluster_default-ca"
 const F5VSTranslateServerAddress = "virtual-server.f5.com/translate-server-address"
 const F5VsWAFPolicy = "virtual-server.f5.com/waf"
+const F5OverrideAS3PriorityAnnotation = "virtual-server.f5.com/override-priority"
+const F5OverrideAS3TenantsAnnotation = "virtual-server.f5.com/override-tenants"
 const OprTypeCreate = "create"
 const OprTypeUpdate = "update"
 const OprTypeDelete = "delete"
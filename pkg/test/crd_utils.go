@@ -19,6 +19,16 @@ const (
 	ExternalDNS = "ExternalDNS"
 	// IPAM is a F5 Customr Resource Kind
 	IPAM = "IPAM"
+	// DataGroup is a F5 Custom Resource Kind
+	DataGroup = "DataGroup"
+	// TLSCertificate is a F5 Custom Resource Kind
+	TLSCertificate = "TLSCertificate"
+	// ServiceReferenceGrant is a F5 Custom Resource Kind
+	ServiceReferenceGrant = "ServiceReferenceGrant"
+	// HealthMonitor is a F5 Custom Resource Kind
+	HealthMonitor = "HealthMonitor"
+	// DeployConfig is a F5 Custom Resource Kind
+	DeployConfig = "DeployConfig"
 )
 
 func NewVirtualServer(name, namespace string, spec cisapiv1.VirtualServerSpec) *cisapiv1.VirtualServer {
@@ -92,6 +102,75 @@ func NewIngressLink(name, namespace, rv string, spec cisapiv1.IngressLinkSpec) *
 	}
 }
 
+func NewDataGroup(name, namespace string, spec cisapiv1.DataGroupSpec) *cisapiv1.DataGroup {
+	return &cisapiv1.DataGroup{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       DataGroup,
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: spec,
+	}
+}
+
+func NewTLSCertificate(name, namespace string, spec cisapiv1.TLSCertificateSpec) *cisapiv1.TLSCertificate {
+	return &cisapiv1.TLSCertificate{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       TLSCertificate,
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: spec,
+	}
+}
+
+func NewServiceReferenceGrant(name, namespace string, spec cisapiv1.ServiceReferenceGrantSpec) *cisapiv1.ServiceReferenceGrant {
+	return &cisapiv1.ServiceReferenceGrant{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       ServiceReferenceGrant,
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: spec,
+	}
+}
+
+func NewHealthMonitor(name, namespace string, spec cisapiv1.HealthMonitorSpec) *cisapiv1.HealthMonitor {
+	return &cisapiv1.HealthMonitor{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       HealthMonitor,
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: spec,
+	}
+}
+
+func NewDeployConfig(name string, spec cisapiv1.DeployConfigSpec) *cisapiv1.DeployConfig {
+	return &cisapiv1.DeployConfig{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       DeployConfig,
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: spec,
+	}
+}
+
 func NewExternalDNS(name, namespace string, spec cisapiv1.ExternalDNSSpec) *cisapiv1.ExternalDNS {
 	return &cisapiv1.ExternalDNS{
 		TypeMeta: metav1.TypeMeta{
@@ -101,6 +101,13 @@ Two related defines can also be used (mainly as a convenience for logger registr
 	LL_MIN_LEVEL
 	LL_MAX_LEVEL
 
+The log level can be switched at runtime with an http handler, which returns the log
+level for GET and sets it for PUT and POST with the level query parameter, or with a
+signal toggling between the debug and the previous log level:
+
+	LogLevelHandler() http.Handler
+	ToggleDebugOnSignal(sig os.Signal)
+
 Note that certain concrete packages will have their own fine-grained filtering for
 logging.  However, the package-level controls will supercede these finer controls.
 
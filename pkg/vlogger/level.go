@@ -0,0 +1,81 @@
+// Copyright (c) 2019-2021, F5 Networks, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// level.go:
+//
+//	This module provides the runtime switching of the package-level log level.
+package vlogger
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+// LogLevelHandler returns the current log level for GET requests and sets the log level
+// for PUT and POST requests with the level query parameter or the level as request body
+func LogLevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			level := r.URL.Query().Get("level")
+			if level == "" {
+				body, err := ioutil.ReadAll(r.Body)
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(err.Error()))
+					return
+				}
+				level = strings.TrimSpace(string(body))
+			}
+			ll := NewLogLevel(level)
+			if nil == ll {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(fmt.Sprintf("Unknown log level requested: %s, valid log levels are: "+
+					"DEBUG, INFO, WARNING, ERROR, CRITICAL", level)))
+				return
+			}
+			SetLogLevel(*ll)
+			Infof("[LOG] Log level set to %v", ll)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(GetLogLevel().String()))
+	})
+}
+
+// ToggleDebugOnSignal switches the log level to debug when the signal is received,
+// the previous log level is restored when the signal is received again
+func ToggleDebugOnSignal(sig os.Signal) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, sig)
+	go func() {
+		previous := GetLogLevel()
+		for range sigs {
+			if GetLogLevel() != LL_DEBUG {
+				previous = GetLogLevel()
+				SetLogLevel(LL_DEBUG)
+			} else {
+				SetLogLevel(previous)
+			}
+			Infof("[LOG] Log level set to %v on signal %v", GetLogLevel(), sig)
+		}
+	}()
+}
@@ -22,6 +22,8 @@ import (
 	"log/syslog" // For LOG level definitions
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // LogLevel is used for global (package-level) filtering of log messages based on their priority
@@ -127,8 +129,13 @@ var (
 	vlog [LL_LOGLEVEL_SIZE]Logger
 
 	// logLevel indicates the current package-level filtering being applied
-	// (may be further restricted by specific concrete loggers).
-	logLevel LogLevel = LL_DEBUG
+	// (may be further restricted by specific concrete loggers). It is accessed
+	// atomically as the log level is switched at runtime.
+	logLevel = int32(LL_DEBUG)
+
+	// logLevelMutex serializes SetLogLevel so that the loggers are left with the
+	// package-level filtering of the last call.
+	logLevelMutex sync.Mutex
 
 	// logLevelToSyslogLevel maps vlogger log levels to the internal representation used
 	// by the implementations (which use syslog's definitions).
@@ -253,10 +260,12 @@ func Panicf(format string, params ...interface{}) {
 
 // SetLogLevel sets the current package-level filtering
 func SetLogLevel(level LogLevel) {
-	logLevel = level
+	logLevelMutex.Lock()
+	defer logLevelMutex.Unlock()
+	atomic.StoreInt32(&logLevel, int32(level))
 
 	// Update all loggers to the new level
-	slLogLevel := logLevelToSyslogLevel[logLevel]
+	slLogLevel := logLevelToSyslogLevel[level]
 	for i, _ := range vlog {
 		if vlog[i] != nil {
 			vlog[i].SetLogLevel(slLogLevel)
@@ -266,7 +275,7 @@ func SetLogLevel(level LogLevel) {
 
 // GetLogLevel returns the current package-level filtering
 func GetLogLevel() LogLevel {
-	return logLevel
+	return LogLevel(atomic.LoadInt32(&logLevel))
 }
 
 // Close informs the configured loggers that they are being closed and
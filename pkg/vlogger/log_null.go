@@ -17,18 +17,21 @@
 //	Turns off all logging.
 package vlogger
 
-import "log/syslog"
+import (
+	"log/syslog"
+	"sync/atomic"
+)
 
 type (
 	nullLogger struct {
-		slLogLevel syslog.Priority
+		slLogLevel int32
 	}
 )
 
 // newNullLogger creates a logger object that drops all log messages.
 func newNullLogger() *nullLogger {
 	return &nullLogger{
-		slLogLevel: syslog.LOG_DEBUG,
+		slLogLevel: int32(syslog.LOG_DEBUG),
 	}
 }
 
@@ -50,8 +53,8 @@ func (cl *nullLogger) Critical(msg string)                            {}
 func (cl *nullLogger) Criticalf(format string, params ...interface{}) {}
 func (cl *nullLogger) Close()                                         {}
 func (cl *nullLogger) SetLogLevel(slLogLevel syslog.Priority) {
-	cl.slLogLevel = slLogLevel
+	atomic.StoreInt32(&cl.slLogLevel, int32(slLogLevel))
 }
 func (cl *nullLogger) GetLogLevel() syslog.Priority {
-	return cl.slLogLevel
+	return syslog.Priority(atomic.LoadInt32(&cl.slLogLevel))
 }